@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// debugPayloadRedacted replaces the value of redacted fields
+const debugPayloadRedacted = "[redacted]"
+
+// debugPayloadContentFields are the WAHA payload fields that carry message content. They are
+// redacted wherever they appear when server.debugPayloadRedact is set; "message" is the engine's
+// raw message under _data, which holds the same content again.
+var debugPayloadContentFields = map[string]bool{
+	"body":     true,
+	"caption":  true,
+	"text":     true,
+	"url":      true,
+	"filename": true,
+	"message":  true,
+}
+
+// debugPayloadNameSanitizer keeps event names and request IDs safe to use in file names
+var debugPayloadNameSanitizer = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// debugPayloadRecord is what a kept payload file holds
+type debugPayloadRecord struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	Event      string          `json:"event"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Error      string          `json:"error"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// debugPayloadStore keeps the raw payloads of WhatsApp webhooks that failed to process in
+// server.debugPayloadDir, one file each, deleting the oldest beyond server.debugPayloadMaxFiles.
+// It is for inspecting why a message did not bridge; unlike the replay cache it keeps the body.
+type debugPayloadStore struct {
+	mu       sync.Mutex
+	dir      string
+	redact   bool
+	maxFiles int
+	logger   *logrus.Logger
+}
+
+// newDebugPayloadStore returns the store configured in cfg, or nil when server.debugPayloadDir
+// is not set
+func newDebugPayloadStore(cfg models.ServerConfig, logger *logrus.Logger) *debugPayloadStore {
+	if cfg.DebugPayloadDir == "" {
+		return nil
+	}
+	maxFiles := cfg.DebugPayloadMaxFiles
+	if maxFiles <= 0 {
+		maxFiles = constants.DefaultDebugPayloadMaxFiles
+	}
+	return &debugPayloadStore{
+		dir:      cfg.DebugPayloadDir,
+		redact:   cfg.DebugPayloadRedact,
+		maxFiles: maxFiles,
+		logger:   logger,
+	}
+}
+
+// save writes body, the payload of an event that failed with cause, and trims the directory.
+// Failures are logged rather than returned, so they never change the webhook's response.
+func (d *debugPayloadStore) save(event, requestID string, body []byte, cause error) {
+	if d == nil {
+		return
+	}
+	if err := d.write(time.Now(), event, requestID, body, cause); err != nil {
+		d.logger.WithError(err).WithField("dir", d.dir).Warn("Failed to keep debug payload")
+	}
+}
+
+func (d *debugPayloadStore) write(now time.Time, event, requestID string, body []byte, cause error) error {
+	payload := json.RawMessage(body)
+	if d.redact {
+		redacted, err := redactDebugPayload(body)
+		if err != nil {
+			return fmt.Errorf("failed to redact payload: %w", err)
+		}
+		payload = redacted
+	}
+	data, err := json.MarshalIndent(debugPayloadRecord{
+		ReceivedAt: now.UTC(),
+		Event:      event,
+		RequestID:  requestID,
+		Error:      cause.Error(),
+		Payload:    payload,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := os.MkdirAll(d.dir, constants.DefaultDirectoryPermissions); err != nil {
+		return fmt.Errorf("failed to create debug payload directory: %w", err)
+	}
+	// The timestamp first makes file names sort oldest first
+	name := now.UTC().Format("20060102T150405.000000000") + "-" + debugPayloadNameSanitizer.ReplaceAllString(event, "_")
+	if requestID != "" {
+		name += "-" + debugPayloadNameSanitizer.ReplaceAllString(requestID, "_")
+	}
+	if err := os.WriteFile(filepath.Join(d.dir, name+".json"), data, constants.DefaultFilePermissions); err != nil {
+		return fmt.Errorf("failed to write debug payload: %w", err)
+	}
+	return d.trim()
+}
+
+// trim deletes the oldest payload files beyond maxFiles. Callers hold mu.
+func (d *debugPayloadStore) trim() error {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list debug payloads: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) <= d.maxFiles {
+		return nil
+	}
+	sort.Strings(names)
+	for _, name := range names[:len(names)-d.maxFiles] {
+		if err := os.Remove(filepath.Join(d.dir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete old debug payload: %w", err)
+		}
+	}
+	return nil
+}
+
+// redactDebugPayload replaces the content fields of a JSON payload, keeping its structure and
+// the IDs needed to trace the message
+func redactDebugPayload(body []byte) (json.RawMessage, error) {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactDebugValue(payload))
+}
+
+func redactDebugValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if debugPayloadContentFields[key] && field != nil && field != "" {
+				v[key] = debugPayloadRedacted
+				continue
+			}
+			v[key] = redactDebugValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactDebugValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func readDebugPayloads(t *testing.T, dir string) []debugPayloadRecord {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	records := make([]debugPayloadRecord, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		var record debugPayloadRecord
+		require.NoError(t, json.Unmarshal(data, &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestWhatsAppWebhook_FailedEventKeepsPayload(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	dir := filepath.Join(t.TempDir(), "payloads")
+	msgService := &mockMessageService{}
+	cfg := &models.Config{
+		WhatsApp: models.WhatsAppConfig{WebhookSecret: "test-secret"},
+		Server:   models.ServerConfig{DebugPayloadDir: dir},
+	}
+	server := NewServer(cfg, msgService, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+	msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+1234567890", "msg-ok", "+1234567890", "", "fine", "").Return(nil).Once()
+	msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+1234567890", "msg-bad", "+1234567890", "", "broken", "").Return(errors.New("signal-cli unavailable")).Once()
+
+	send := func(id, body string) int {
+		payload, err := json.Marshal(map[string]interface{}{
+			"event":   models.EventMessage,
+			"session": "default",
+			"payload": map[string]interface{}{"id": id, "from": "+1234567890", "body": body},
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/webhook/whatsapp", bytes.NewReader(payload))
+		req.Header.Set(XWahaSignatureHeader, signWahaTestPayload(cfg.WhatsApp.WebhookSecret, payload))
+		req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.handleWhatsAppWebhook()(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("msg-ok", "fine"))
+	assert.NoDirExists(t, dir, "processed events are not kept")
+
+	assert.NotEqual(t, http.StatusOK, send("msg-bad", "broken"))
+	records := readDebugPayloads(t, dir)
+	require.Len(t, records, 1)
+	assert.Equal(t, models.EventMessage, records[0].Event)
+	assert.Contains(t, records[0].Error, "signal-cli unavailable")
+	assert.NotEmpty(t, records[0].RequestID)
+	var kept models.WhatsAppWebhookPayload
+	require.NoError(t, json.Unmarshal(records[0].Payload, &kept))
+	assert.Equal(t, "msg-bad", kept.Payload.ID)
+	assert.Equal(t, "broken", kept.Payload.Body)
+	msgService.AssertExpectations(t)
+}
+
+func TestDebugPayloadStore_CapsFiles(t *testing.T) {
+	dir := t.TempDir()
+	store := newDebugPayloadStore(models.ServerConfig{DebugPayloadDir: dir, DebugPayloadMaxFiles: 3}, logrus.New())
+
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		body := fmt.Sprintf(`{"event":"message","payload":{"id":"msg-%d"}}`, i)
+		require.NoError(t, store.write(start.Add(time.Duration(i)*time.Second), "message", "", []byte(body), errors.New("failed")))
+	}
+
+	records := readDebugPayloads(t, dir)
+	require.Len(t, records, 3)
+	for i, record := range records {
+		assert.Contains(t, string(record.Payload), fmt.Sprintf(`"msg-%d"`, i+2), "the newest payloads are kept")
+	}
+}
+
+func TestDebugPayloadStore_Redacts(t *testing.T) {
+	dir := t.TempDir()
+	store := newDebugPayloadStore(models.ServerConfig{DebugPayloadDir: dir, DebugPayloadRedact: true}, logrus.New())
+
+	body := `{"event":"message","payload":{"id":"msg-1","from":"123@c.us","body":"secret","media":{"url":"http://waha/file","mimetype":"image/jpeg"},"replyTo":{"id":"msg-0","body":"older secret"},"_data":{"pushName":"Alice","message":{"conversation":"secret"}}}}`
+	require.NoError(t, store.write(time.Now(), "message", "req-1", []byte(body), errors.New("failed")))
+
+	records := readDebugPayloads(t, dir)
+	require.Len(t, records, 1)
+	assert.NotContains(t, string(records[0].Payload), "secret")
+	assert.NotContains(t, string(records[0].Payload), "http://waha/file")
+
+	var kept models.WhatsAppWebhookPayload
+	require.NoError(t, json.Unmarshal(records[0].Payload, &kept))
+	assert.Equal(t, "msg-1", kept.Payload.ID, "IDs are kept to trace the message")
+	assert.Equal(t, debugPayloadRedacted, kept.Payload.Body)
+	require.NotNil(t, kept.Payload.Media)
+	assert.Equal(t, "image/jpeg", kept.Payload.Media.MimeType)
+	assert.Equal(t, debugPayloadRedacted, kept.Payload.Media.URL)
+	assert.Equal(t, "Alice", kept.Payload.Data.PushName)
+}
+
+func TestNewDebugPayloadStore_DisabledWithoutDir(t *testing.T) {
+	store := newDebugPayloadStore(models.ServerConfig{}, logrus.New())
+	assert.Nil(t, store)
+	store.save("message", "", []byte(`{}`), errors.New("failed"))
+}
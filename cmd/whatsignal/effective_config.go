@@ -0,0 +1,26 @@
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"whatsignal/internal/config"
+)
+
+// handleConfig returns the configuration the process is running with, after defaults and
+// environment overrides, with its secrets redacted. It shows the whole deployment, so like
+// /loglevel it is refused outright when no admin token is configured.
+func (s *Server) handleConfig() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if os.Getenv("WHATSIGNAL_ADMIN_TOKEN") == "" {
+			http.Error(w, "Reading the configuration requires WHATSIGNAL_ADMIN_TOKEN to be set", http.StatusForbidden)
+			return
+		}
+		if !requireProductionAdminToken(w, r) {
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		s.writeJSON(w, http.StatusOK, config.Redacted(s.cfg))
+	}
+}
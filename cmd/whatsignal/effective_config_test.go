@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"whatsignal/internal/config"
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getConfig(server *Server, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/config", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestConfigEndpoint_RedactsSecrets(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", testRegistrationToken)
+
+	cfg := &models.Config{
+		WhatsApp: models.WhatsAppConfig{
+			APIBaseURL:    "http://waha:3000",
+			WebhookSecret: "global-webhook-secret",
+		},
+		Signal:        models.SignalConfig{RPCURL: "http://signal:8080"},
+		Media:         models.MediaConfig{DownloadHeaders: map[string]string{"X-Proxy-Token": "header-token"}},
+		Server:        models.ServerConfig{CleanupIntervalHours: 12},
+		RetentionDays: 30,
+		Channels: []models.Channel{
+			{WhatsAppSessionName: "default", SignalDestinationPhoneNumber: "+1111111111", WebhookSecret: "channel-webhook-secret"},
+		},
+	}
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	server := NewServer(cfg, &mockMessageService{}, logger, &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+	w := getConfig(server, testRegistrationToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	for _, secret := range []string{"global-webhook-secret", "channel-webhook-secret", "header-token"} {
+		assert.NotContains(t, w.Body.String(), secret)
+	}
+
+	var got models.Config
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, config.RedactedValue, got.WhatsApp.WebhookSecret)
+	assert.Equal(t, config.RedactedValue, got.Channels[0].WebhookSecret)
+	assert.Equal(t, "default", got.Channels[0].WhatsAppSessionName)
+	assert.Equal(t, "+1111111111", got.Channels[0].SignalDestinationPhoneNumber)
+	assert.Equal(t, 30, got.RetentionDays)
+	assert.Equal(t, 12, got.Server.CleanupIntervalHours)
+	assert.Equal(t, "http://waha:3000", got.WhatsApp.APIBaseURL)
+}
+
+func TestConfigEndpoint_RequiresAdminToken(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	tests := []struct {
+		name           string
+		configured     string
+		presented      string
+		expectedStatus int
+	}{
+		{name: "no token configured", expectedStatus: http.StatusForbidden},
+		{name: "missing token", configured: testRegistrationToken, expectedStatus: http.StatusUnauthorized},
+		{name: "wrong token", configured: testRegistrationToken, presented: "wrong", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WHATSIGNAL_ADMIN_TOKEN", tt.configured)
+			server := NewServer(&models.Config{}, &mockMessageService{}, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+			w := getConfig(server, tt.presented)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"whatsignal/internal/config"
+	"whatsignal/internal/database"
+	"whatsignal/internal/service"
+)
+
+// runImportContacts loads the contacts CSV at path into the database of the configured
+// instance, prints the counts to out and returns the process exit code
+func runImportContacts(ctx context.Context, cfgPath, path string, out io.Writer) int {
+	if err := importContactsFromFile(ctx, cfgPath, path, out); err != nil {
+		fmt.Fprintf(os.Stderr, "Contact import failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func importContactsFromFile(ctx context.Context, cfgPath, path string, out io.Writer) error {
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	file, err := os.Open(filepath.Clean(path)) // #nosec G304 - Path given by the operator on the command line
+	if err != nil {
+		return fmt.Errorf("failed to open contacts file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	db, err := database.New(cfg.Database.Path, &cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	result, err := service.ImportContactsCSV(ctx, file, db)
+	fmt.Fprintf(out, "Imported %d contacts, skipped %d malformed rows\n", result.Imported, result.Malformed)
+	return err
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+type logLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// runtimeLogLevels are the levels POST /loglevel switches between
+var runtimeLogLevels = map[string]logrus.Level{
+	"info":  logrus.InfoLevel,
+	"debug": logrus.DebugLevel,
+}
+
+// handleLogLevel switches the log level between info and debug without a restart, which would
+// drop the WhatsApp session. Debug logs include sensitive information, so the endpoint is
+// refused outright when no admin token is configured.
+func (s *Server) handleLogLevel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if os.Getenv("WHATSIGNAL_ADMIN_TOKEN") == "" {
+			http.Error(w, "Changing the log level requires WHATSIGNAL_ADMIN_TOKEN to be set", http.StatusForbidden)
+			return
+		}
+		if !requireProductionAdminToken(w, r) {
+			return
+		}
+
+		var req logLevelRequest
+		if !decodeOptionalJSONBody(w, r, &req) {
+			return
+		}
+		level, ok := runtimeLogLevels[strings.ToLower(strings.TrimSpace(req.Level))]
+		if !ok {
+			s.writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": `level must be "info" or "debug"`})
+			return
+		}
+
+		previous := s.logger.GetLevel()
+		s.logger.SetLevel(level)
+		if level == logrus.DebugLevel && previous != logrus.DebugLevel {
+			s.logger.Warn("Debug logging enabled at runtime - sensitive information will be logged")
+		} else if level != previous {
+			s.logger.WithField("level", level.String()).Info("Log level changed at runtime")
+		}
+
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"level":    level.String(),
+			"previous": previous.String(),
+		})
+	}
+}
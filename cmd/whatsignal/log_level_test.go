@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevel_ChangesAndReverts(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", testRegistrationToken)
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	logger.SetLevel(logrus.InfoLevel)
+	server := NewServer(&models.Config{}, &mockMessageService{}, logger, &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+	logger.Debug("before switching")
+	assert.NotContains(t, logs.String(), "before switching")
+
+	w := postRegistration(server, "/loglevel", testRegistrationToken, `{"level":"debug"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"debug","previous":"info"}`, w.Body.String())
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+	assert.Contains(t, logs.String(), "sensitive information will be logged")
+
+	logger.Debug("while debugging")
+	assert.Contains(t, logs.String(), "while debugging", "later log calls use the new level")
+
+	w = postRegistration(server, "/loglevel", testRegistrationToken, `{"level":"INFO"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"info","previous":"debug"}`, w.Body.String())
+	assert.Equal(t, logrus.InfoLevel, logger.GetLevel())
+
+	logger.Debug("after reverting")
+	assert.NotContains(t, logs.String(), "after reverting")
+}
+
+func TestLogLevel_Rejected(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	tests := []struct {
+		name           string
+		configured     string
+		presented      string
+		body           string
+		expectedStatus int
+	}{
+		{name: "no token configured", body: `{"level":"debug"}`, expectedStatus: http.StatusForbidden},
+		{name: "wrong token", configured: testRegistrationToken, presented: "wrong", body: `{"level":"debug"}`, expectedStatus: http.StatusUnauthorized},
+		{name: "unsupported level", configured: testRegistrationToken, presented: testRegistrationToken, body: `{"level":"trace"}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid body", configured: testRegistrationToken, presented: testRegistrationToken, body: `{`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WHATSIGNAL_ADMIN_TOKEN", tt.configured)
+			logger := logrus.New()
+			logger.SetLevel(logrus.InfoLevel)
+			server := NewServer(&models.Config{}, &mockMessageService{}, logger, &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+			w := postRegistration(server, "/loglevel", tt.presented, tt.body)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, logrus.InfoLevel, logger.GetLevel())
+		})
+	}
+}
@@ -35,10 +35,11 @@ var (
 	GitCommit = "unknown"
 
 	// CLI flags
-	verbose     = flag.Bool("verbose", false, "Enable verbose logging (includes sensitive information)")
-	configPath  = flag.String("config", "config.json", "Path to configuration file")
-	version     = flag.Bool("version", false, "Show version information")
-	healthcheck = flag.Bool("healthcheck", false, "Run a health check against the local server and exit")
+	verbose            = flag.Bool("verbose", false, "Enable verbose logging (includes sensitive information)")
+	configPath         = flag.String("config", "config.json", "Path to configuration file")
+	version            = flag.Bool("version", false, "Show version information")
+	healthcheck        = flag.Bool("healthcheck", false, "Run a health check against the local server and exit")
+	importContactsPath = flag.String("import-contacts", "", "Import contacts from a CSV file of number,name rows and exit")
 )
 
 func main() {
@@ -53,6 +54,10 @@ func main() {
 		os.Exit(runHealthCheck())
 	}
 
+	if *importContactsPath != "" {
+		os.Exit(runImportContacts(context.Background(), *configPath, *importContactsPath, os.Stdout))
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -79,6 +84,7 @@ func runHealthCheck() int {
 func run(ctx context.Context) error {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(tracing.NewRequestIDHook())
 
 	logger.WithFields(logrus.Fields{
 		"version": Version,
@@ -126,7 +132,7 @@ func run(ctx context.Context) error {
 	}()
 
 	// Initialize database with exponential backoff retry
-	var db *database.Database
+	var db bridgeDatabase
 	backoffConfig := retry.BackoffConfig{
 		InitialDelay: time.Duration(cfg.Retry.InitialBackoffMs) * time.Millisecond,
 		MaxDelay:     time.Duration(cfg.Retry.MaxBackoffMs) * time.Millisecond,
@@ -138,7 +144,7 @@ func run(ctx context.Context) error {
 
 	err = backoff.Retry(ctx, func() error {
 		var initErr error
-		db, initErr = database.New(cfg.Database.Path, &cfg.Database)
+		db, initErr = openDatabase(cfg)
 		if initErr != nil {
 			logger.Warnf("Failed to initialize database: %v", initErr)
 		}
@@ -165,7 +171,7 @@ func run(ctx context.Context) error {
 	}
 
 	// Create channel manager
-	channelManager, err := service.NewChannelManager(cfg.Channels)
+	channelManager, err := service.NewChannelManagerWithMedia(cfg.Channels, cfg.Media)
 	if err != nil {
 		return fmt.Errorf("failed to create channel manager: %w", err)
 	}
@@ -174,17 +180,30 @@ func run(ctx context.Context) error {
 	// (already validated in validateConfig)
 	defaultSessionName := cfg.Channels[0].WhatsAppSessionName
 
+	// Forward each message's request ID to WAHA and signal-cli unless disabled
+	var requestIDTransport http.RoundTripper
+	if !cfg.Tracing.DisableRequestIDHeader {
+		requestIDTransport = tracing.NewRequestIDTransport(nil)
+	}
+
 	waClient := whatsapp.NewClientWithLogger(types.ClientConfig{
 		BaseURL:     cfg.WhatsApp.APIBaseURL,
 		APIKey:      apiKey,
 		SessionName: defaultSessionName,
 		Timeout:     cfg.WhatsApp.Timeout,
 		RetryCount:  cfg.WhatsApp.RetryCount,
+		Transport:   requestIDTransport,
 	}, logger)
+	sessionCfg := cfg.WhatsApp
+	if secret := channelManager.GetWebhookSecret(defaultSessionName); secret != "" {
+		sessionCfg.WebhookSecret = secret
+	}
+	ensureSessionCreated(ctx, waClient, sessionCfg, logger)
 
 	// Use configured Signal HTTP timeout or default
 	signalHTTPClient := &http.Client{
-		Timeout: getTimeoutDuration(cfg.Signal.HTTPTimeoutSec, constants.DefaultSignalHTTPTimeoutSec),
+		Timeout:   getTimeoutDuration(cfg.Signal.HTTPTimeoutSec, constants.DefaultSignalHTTPTimeoutSec),
+		Transport: requestIDTransport,
 	}
 
 	sigClient := signalapi.NewClientWithLogger(
@@ -202,6 +221,10 @@ func run(ctx context.Context) error {
 		}
 		logger.Warnf("Failed to initialize Signal device: %v. whatsignal may not function correctly with Signal.", err)
 	}
+	if client, ok := sigClient.(*signalapi.SignalClient); ok {
+		client.SetStrictAccountMatch(cfg.Signal.StrictAccountMatch)
+		warnIfSignalUnregistered(ctx, client, cfg.Signal.IntermediaryPhoneNumber, logger)
+	}
 
 	// Use configured contact cache hours or default
 	cacheHours := cfg.WhatsApp.ContactCacheHours
@@ -209,6 +232,7 @@ func run(ctx context.Context) error {
 		cacheHours = constants.DefaultContactCacheHours
 	}
 	contactService := service.NewContactServiceWithConfigAndLogger(db, waClient, cacheHours, logger)
+	contactService.SetSignalClient(sigClient)
 
 	syncOnStartup := cfg.WhatsApp.ContactSyncOnStartup
 	if syncOnStartup {
@@ -234,17 +258,20 @@ func run(ctx context.Context) error {
 		logger.Info("Group sync on startup is disabled")
 	}
 
-	bridge := service.NewBridge(waClient, sigClient, db, mediaHandler, models.RetryConfig{
-		InitialBackoffMs: cfg.Retry.InitialBackoffMs,
-		MaxBackoffMs:     cfg.Retry.MaxBackoffMs,
-		MaxAttempts:      cfg.Retry.MaxAttempts,
-	}, cfg.Media, channelManager, contactService, groupService, cfg.Signal.AttachmentsDir, logger)
+	bridge := service.NewBridgeWithConfig(waClient, sigClient, db, mediaHandler, *cfg, channelManager, contactService, groupService, logger)
 
 	logger.WithField("channels", len(cfg.Channels)).Info("Multi-channel bridge initialized")
 
 	messageService := service.NewMessageServiceWithLogger(bridge, db, mediaHandler, sigClient, cfg.Signal, channelManager, logger)
 
 	scheduler := service.NewScheduler(bridge, cfg.RetentionDays, cfg.Server.CleanupIntervalHours, logger)
+	scheduler.SetRetention(bridge.Retention())
+	scheduler.SetContactCleanup(contactService, cfg.Server.ContactRetentionDays)
+	scheduler.SetGroupCleanup(groupService, cfg.Server.GroupRetentionDays)
+	scheduler.SetOrphanMediaPurge(bridge, time.Duration(cfg.Media.OrphanPurgeGraceHours)*time.Hour)
+	if cfg.Media.MaxCacheSizeMB > 0 {
+		scheduler.SetCacheBudget(mediaHandler)
+	}
 	go scheduler.Start(ctx)
 	defer scheduler.Stop()
 
@@ -252,6 +279,17 @@ func run(ctx context.Context) error {
 	go deliveryMonitor.Start(ctx)
 	defer deliveryMonitor.Stop()
 
+	// Look up the number of each session in the background; sessions that are not logged in yet
+	// are picked up by the session monitor once they are working
+	sessionNumbers := service.NewSessionNumbers(waClient, logger)
+	go func() {
+		lookupCtx, cancel := context.WithTimeout(ctx, time.Duration(constants.DefaultHTTPTimeoutSec)*time.Second)
+		defer cancel()
+		if err := sessionNumbers.Refresh(lookupCtx, channelManager.GetAllWhatsAppSessions()); err != nil {
+			logger.WithError(err).Warn("Failed to look up WhatsApp session phone numbers")
+		}
+	}()
+
 	// Start session monitor if auto-restart is enabled
 	if cfg.WhatsApp.SessionAutoRestart {
 		checkInterval := getTimeoutDuration(cfg.WhatsApp.SessionHealthCheckSec, constants.DefaultSessionHealthCheckSec)
@@ -270,6 +308,17 @@ func run(ctx context.Context) error {
 			checkInterval,
 			startupTimeout,
 		)
+		sessionMonitor.SetSessionNumbers(sessionNumbers)
+		sessionMonitor.SetRestartBackoff(
+			getTimeoutDuration(cfg.WhatsApp.SessionRestartBackoffSec, constants.DefaultSessionRestartBackoffSec),
+			getTimeoutDuration(cfg.WhatsApp.SessionRestartBackoffMaxSec, constants.DefaultSessionRestartBackoffMaxSec),
+			getTimeoutDuration(cfg.WhatsApp.SessionRestartResetSec, constants.DefaultSessionRestartResetSec),
+		)
+		if cfg.WhatsApp.SessionDownNotify {
+			sessionMonitor.SetDownNotifier(messageService,
+				time.Duration(cfg.WhatsApp.SessionDownNotifyAfterSec)*time.Second,
+				time.Duration(cfg.WhatsApp.SessionDownRepeatSec)*time.Second)
+		}
 		sessionMonitor.Start(ctx)
 		defer sessionMonitor.Stop()
 
@@ -279,6 +328,11 @@ func run(ctx context.Context) error {
 		}).Info("Session health monitor started")
 	}
 
+	// Hold back Signal polling and webhooks until the WhatsApp sessions can send
+	if cfg.WhatsApp.SessionWarmupTimeoutSec > 0 {
+		warmUpSessions(ctx, waClient, channelManager.GetAllWhatsAppSessions(), time.Duration(cfg.WhatsApp.SessionWarmupTimeoutSec)*time.Second, logger)
+	}
+
 	ctxWithVerbose := context.WithValue(ctx, service.VerboseContextKey, *verbose)
 
 	signalPoller := service.NewSignalPoller(sigClient, messageService, cfg.Signal, models.RetryConfig{
@@ -286,6 +340,8 @@ func run(ctx context.Context) error {
 		MaxBackoffMs:     cfg.Retry.MaxBackoffMs,
 		MaxAttempts:      cfg.Retry.MaxAttempts,
 	}, logger)
+	workQueue := service.NewWorkQueue(cfg.Server.MaxQueueDepth)
+	signalPoller.SetWorkQueue(workQueue)
 
 	if err := signalPoller.Start(ctxWithVerbose); err != nil {
 		logger.Warnf("Failed to start Signal poller: %v", err)
@@ -301,6 +357,12 @@ func run(ctx context.Context) error {
 	}
 
 	server := NewServer(cfg, messageService, logger, waClient, channelManager, db, signalClient)
+	server.SetSessionNumbers(sessionNumbers)
+	server.SetMediaCache(mediaHandler)
+	server.SetGroupService(groupService)
+	server.SetWorkQueue(workQueue)
+	// database.New applies all migrations before returning
+	server.MarkMigrationsApplied()
 	serverErrCh := make(chan error, constants.ServerErrorChannelSize)
 	go func() {
 		if err := server.Start(); err != nil {
@@ -327,6 +389,40 @@ func run(ctx context.Context) error {
 	return nil
 }
 
+// bridgeDatabase is the database the services and HTTP handlers share: a single file, or a
+// file per session when database.shardBySession is set
+type bridgeDatabase interface {
+	service.DatabaseService
+	service.Database
+	service.ContactDatabaseService
+	service.GroupDatabaseService
+	service.StaleMessageCounter
+	DatabaseInterface
+	Close() error
+}
+
+// openDatabase opens the database at database.path, with a shard per configured session when
+// database.shardBySession is set
+func openDatabase(cfg *models.Config) (bridgeDatabase, error) {
+	if !cfg.Database.ShardBySession {
+		db, err := database.New(cfg.Database.Path, &cfg.Database)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+
+	sessions := make([]string, 0, len(cfg.Channels))
+	for _, channel := range cfg.Channels {
+		sessions = append(sessions, channel.WhatsAppSessionName)
+	}
+	db, err := database.NewSharded(cfg.Database.Path, &cfg.Database, sessions)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
 func validateWhatsAppAPIKey(apiKey, environment string) error {
 	if apiKey == "" {
 		return fmt.Errorf("WHATSAPP_API_KEY environment variable is required")
@@ -397,7 +493,7 @@ func setLogLevel(logger *logrus.Logger, configLevel string) {
 }
 
 // syncParallelContacts performs contact sync for all sessions in parallel with bounded concurrency
-func syncParallelContacts(ctx context.Context, cfg *models.Config, db *database.Database, apiKey string, cacheHours int, logger *logrus.Logger) {
+func syncParallelContacts(ctx context.Context, cfg *models.Config, db service.ContactDatabaseService, apiKey string, cacheHours int, logger *logrus.Logger) {
 	channels := cfg.Channels
 	if len(channels) == 0 {
 		return
@@ -436,7 +532,7 @@ func syncParallelContacts(ctx context.Context, cfg *models.Config, db *database.
 }
 
 // syncSessionContacts handles contact sync for a single session
-func syncSessionContacts(ctx context.Context, cfg *models.Config, db *database.Database, apiKey, sessionName string, cacheHours int, logger *logrus.Logger) {
+func syncSessionContacts(ctx context.Context, cfg *models.Config, db service.ContactDatabaseService, apiKey, sessionName string, cacheHours int, logger *logrus.Logger) {
 	sessionLogger := logger.WithField("session", sessionName)
 	sessionLogger.Info("Waiting for WhatsApp session to be ready...")
 
@@ -463,7 +559,7 @@ func syncSessionContacts(ctx context.Context, cfg *models.Config, db *database.D
 }
 
 // syncParallelGroups performs group sync for all sessions in parallel with bounded concurrency
-func syncParallelGroups(ctx context.Context, cfg *models.Config, db *database.Database, apiKey string, cacheHours int, logger *logrus.Logger) {
+func syncParallelGroups(ctx context.Context, cfg *models.Config, db service.GroupDatabaseService, apiKey string, cacheHours int, logger *logrus.Logger) {
 	channels := cfg.Channels
 	if len(channels) == 0 {
 		return
@@ -502,7 +598,7 @@ func syncParallelGroups(ctx context.Context, cfg *models.Config, db *database.Da
 }
 
 // syncSessionGroups handles group sync for a single session
-func syncSessionGroups(ctx context.Context, cfg *models.Config, db *database.Database, apiKey, sessionName string, cacheHours int, logger *logrus.Logger) {
+func syncSessionGroups(ctx context.Context, cfg *models.Config, db service.GroupDatabaseService, apiKey, sessionName string, cacheHours int, logger *logrus.Logger) {
 	sessionLogger := logger.WithField("session", sessionName)
 	sessionLogger.Info("Waiting for WhatsApp session to be ready for group sync...")
 
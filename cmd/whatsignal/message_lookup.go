@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/hmac"
+	"net/http"
+	"time"
+
+	"whatsignal/internal/models"
+
+	"github.com/gorilla/mux"
+)
+
+// messageMappingView is the JSON form of a message mapping returned by GET /api/messages/{id}.
+// It lists the decrypted fields explicitly, so the local media path and whatever else the
+// mapping gains later stay out of the response.
+type messageMappingView struct {
+	WhatsAppChatID  string                `json:"whatsappChatId"`
+	WhatsAppMsgID   string                `json:"whatsappMsgId"`
+	SignalMsgID     string                `json:"signalMsgId"`
+	SignalTimestamp time.Time             `json:"signalTimestamp"`
+	Session         string                `json:"session"`
+	DeliveryStatus  models.DeliveryStatus `json:"deliveryStatus"`
+	ForwardedAt     time.Time             `json:"forwardedAt"`
+	MediaType       string                `json:"mediaType"`
+	CreatedAt       time.Time             `json:"createdAt"`
+	UpdatedAt       time.Time             `json:"updatedAt"`
+}
+
+// handleMessageLookup returns the message mapping for a WhatsApp or Signal message ID, for
+// debugging why a message was or was not delivered without opening the database
+func (s *Server) handleMessageLookup() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.hasWebhookSecret(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		messageID := mux.Vars(r)["id"]
+		mapping, err := s.db.GetMessageMapping(r.Context(), messageID)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to load message mapping")
+			s.writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load message mapping"})
+			return
+		}
+		if mapping == nil {
+			s.writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": "message not found"})
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		s.writeJSON(w, http.StatusOK, messageMappingView{
+			WhatsAppChatID:  mapping.WhatsAppChatID,
+			WhatsAppMsgID:   mapping.WhatsAppMsgID,
+			SignalMsgID:     mapping.SignalMsgID,
+			SignalTimestamp: mapping.SignalTimestamp,
+			Session:         mapping.SessionName,
+			DeliveryStatus:  mapping.DeliveryStatus,
+			ForwardedAt:     mapping.ForwardedAt,
+			MediaType:       mapping.MediaType,
+			CreatedAt:       mapping.CreatedAt,
+			UpdatedAt:       mapping.UpdatedAt,
+		})
+	}
+}
+
+// hasWebhookSecret reports whether the request's X-Api-Key header is the WhatsApp webhook secret
+// or the webhook secret of one of the channels. Unlike the webhooks themselves, it requires the
+// secret in every mode, so a server without one configured rejects every request.
+func (s *Server) hasWebhookSecret(r *http.Request) bool {
+	presented := r.Header.Get(XApiKeyHeader)
+	if presented == "" {
+		return false
+	}
+
+	secrets := []string{s.cfg.WhatsApp.WebhookSecret}
+	if s.channelManager != nil {
+		for _, session := range s.channelManager.GetAllWhatsAppSessions() {
+			secrets = append(secrets, s.channelManager.GetWebhookSecret(session))
+		}
+	}
+	for _, secret := range secrets {
+		if secret != "" && hmac.Equal([]byte(presented), []byte(secret)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	"whatsignal/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testLookupSecret = "lookup-webhook-secret"
+
+func getMessage(server *Server, id, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/messages/"+id, nil)
+	if apiKey != "" {
+		req.Header.Set(XApiKeyHeader, apiKey)
+	}
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMessageLookup(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &models.Config{WhatsApp: models.WhatsAppConfig{WebhookSecret: testLookupSecret}}
+
+	t.Run("found", func(t *testing.T) {
+		mockDB := &mockDatabase{}
+		server := NewServer(cfg, &mockMessageService{}, logger, &mockWAClient{}, createTestChannelManager(), mockDB, nil)
+
+		forwardedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		mediaPath := "/var/lib/whatsignal/cache/abc.jpg"
+		mockDB.On("GetMessageMapping", mock.Anything, "wamid.found").Return(&models.MessageMapping{
+			ID:             7,
+			WhatsAppChatID: "15550001111@c.us",
+			WhatsAppMsgID:  "wamid.found",
+			SignalMsgID:    "1727784000000",
+			ForwardedAt:    forwardedAt,
+			DeliveryStatus: models.DeliveryStatusDelivered,
+			MediaPath:      &mediaPath,
+			MediaType:      "image",
+			SessionName:    "default",
+		}, nil).Once()
+
+		w := getMessage(server, "wamid.found", testLookupSecret)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "15550001111@c.us", body["whatsappChatId"])
+		assert.Equal(t, "1727784000000", body["signalMsgId"])
+		assert.Equal(t, "default", body["session"])
+		assert.Equal(t, string(models.DeliveryStatusDelivered), body["deliveryStatus"])
+		assert.Equal(t, "image", body["mediaType"])
+		assert.Equal(t, forwardedAt.Format(time.RFC3339), body["forwardedAt"])
+		assert.NotContains(t, body, "id")
+		assert.NotContains(t, w.Body.String(), mediaPath)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockDB := &mockDatabase{}
+		server := NewServer(cfg, &mockMessageService{}, logger, &mockWAClient{}, createTestChannelManager(), mockDB, nil)
+		mockDB.On("GetMessageMapping", mock.Anything, "wamid.unknown").Return(nil, nil).Once()
+
+		w := getMessage(server, "wamid.unknown", testLookupSecret)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mockDB := &mockDatabase{}
+		server := NewServer(cfg, &mockMessageService{}, logger, &mockWAClient{}, createTestChannelManager(), mockDB, nil)
+
+		for _, apiKey := range []string{"", "wrong-secret"} {
+			w := getMessage(server, "wamid.found", apiKey)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		}
+		mockDB.AssertNotCalled(t, "GetMessageMapping", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unauthorized outside secure mode", func(t *testing.T) {
+		t.Setenv("WHATSIGNAL_ENV", "development")
+		mockDB := &mockDatabase{}
+		server := NewServer(&models.Config{}, &mockMessageService{}, logger, &mockWAClient{}, createTestChannelManager(), mockDB, nil)
+
+		w := getMessage(server, "wamid.found", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "a request without the secret is rejected even when none is configured")
+		mockDB.AssertNotCalled(t, "GetMessageMapping", mock.Anything, mock.Anything)
+	})
+
+	t.Run("channel webhook secret", func(t *testing.T) {
+		mockDB := &mockDatabase{}
+		channelManager, err := service.NewChannelManager([]models.Channel{
+			{WhatsAppSessionName: "default", SignalDestinationPhoneNumber: "+1234567890", WebhookSecret: "channel-secret"},
+		})
+		require.NoError(t, err)
+		server := NewServer(&models.Config{}, &mockMessageService{}, logger, &mockWAClient{}, channelManager, mockDB, nil)
+		mockDB.On("GetMessageMapping", mock.Anything, "wamid.unknown").Return(nil, nil).Once()
+
+		w := getMessage(server, "wamid.unknown", "channel-secret")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockDB.AssertExpectations(t)
+	})
+}
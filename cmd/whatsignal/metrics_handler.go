@@ -6,6 +6,7 @@ import (
 
 	"whatsignal/internal/metrics"
 	"whatsignal/internal/tracing"
+	"whatsignal/pkg/media"
 
 	"github.com/sirupsen/logrus"
 )
@@ -25,15 +26,29 @@ func (s *Server) handleMetrics() http.HandlerFunc {
 			"endpoint":   "/metrics",
 		}).Debug("Serving metrics endpoint")
 
+		s.updateMediaCacheGauges()
+
 		// Get all metrics from the global registry
 		allMetrics := metrics.GetAllMetrics()
 
-		// Set appropriate headers
-		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 		w.Header().Set("Pragma", "no-cache")
 		w.Header().Set("Expires", "0")
 
+		if s.servesPrometheusMetrics(r) {
+			w.Header().Set("Content-Type", metrics.PrometheusContentType)
+			if err := allMetrics.WritePrometheus(w); err != nil {
+				s.logger.WithFields(logrus.Fields{
+					"request_id": requestInfo.RequestID,
+					"trace_id":   requestInfo.TraceID,
+					"error":      err,
+				}).Error("Failed to write Prometheus metrics response")
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
 		// Encode and send metrics
 		encoder := json.NewEncoder(w)
 		encoder.SetIndent("", "  ")
@@ -56,3 +71,35 @@ func (s *Server) handleMetrics() http.HandlerFunc {
 		}).Debug("Metrics endpoint served successfully")
 	}
 }
+
+// servesPrometheusMetrics reports whether /metrics answers r in the Prometheus text format:
+// with server.metricsEnabled, unless the JSON report is asked for with ?format=json
+func (s *Server) servesPrometheusMetrics(r *http.Request) bool {
+	return s.cfg != nil && s.cfg.Server.MetricsEnabled && r.URL.Query().Get("format") != "json"
+}
+
+// mediaCacheStats returns the media cache statistics, or false when there is no media cache or
+// they cannot be read
+func (s *Server) mediaCacheStats() (media.CacheStats, bool) {
+	if s.mediaCache == nil {
+		return media.CacheStats{}, false
+	}
+	stats, err := s.mediaCache.CacheStats()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to read media cache statistics")
+		return media.CacheStats{}, false
+	}
+	return stats, true
+}
+
+// updateMediaCacheGauges refreshes the media cache gauges so /metrics reports the cache as it is now
+func (s *Server) updateMediaCacheGauges() {
+	stats, ok := s.mediaCacheStats()
+	if !ok {
+		return
+	}
+	metrics.SetGauge("media_cache_files", float64(stats.Files), nil, "Files in the media cache")
+	metrics.SetGauge("media_cache_size_bytes", float64(stats.SizeBytes), nil, "Total size of the media cache")
+	metrics.SetGauge("media_cache_oldest_file_age_seconds", stats.OldestFileAge.Seconds(), nil, "Age of the oldest file in the media cache")
+	metrics.SetGauge("media_cache_hit_rate", stats.HitRate, nil, "Share of processed media found already in the cache since startup")
+}
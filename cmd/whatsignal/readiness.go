@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"whatsignal/internal/constants"
+)
+
+// MarkMigrationsApplied records that database migrations completed. Until it is called the
+// readiness probe reports the bridge as not ready.
+func (s *Server) MarkMigrationsApplied() {
+	s.migrationsApplied.Store(true)
+}
+
+// workingSession returns the first configured WhatsApp session whose status is WORKING, or ""
+// if none is. Once a session has been seen working the result is remembered, so a session
+// restarting later does not take the bridge out of rotation.
+func (s *Server) workingSession(ctx context.Context) string {
+	if name, ok := s.workingSessionName.Load().(string); ok && name != "" {
+		return name
+	}
+	if s.waClient == nil || s.channelManager == nil {
+		return ""
+	}
+
+	for _, sessionName := range s.channelManager.GetAllWhatsAppSessions() {
+		session, err := s.waClient.GetSessionStatusByName(ctx, sessionName)
+		if err != nil {
+			s.logger.WithError(err).WithField("session", sessionName).Debug("Readiness session status check failed")
+			continue
+		}
+		if session != nil && session.Status == "WORKING" {
+			s.workingSessionName.Store(sessionName)
+			return sessionName
+		}
+	}
+	return ""
+}
+
+// handleReadiness reports whether the bridge can serve traffic: migrations are applied, the
+// Signal device is initialized and at least one WhatsApp session has reached WORKING
+func (s *Server) handleReadiness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(constants.DefaultSessionStatusTimeoutSec)*time.Second)
+		defer cancel()
+
+		ready := true
+
+		migrations := map[string]interface{}{"ready": s.migrationsApplied.Load()}
+		if !s.migrationsApplied.Load() {
+			ready = false
+		}
+
+		signalCheck := map[string]interface{}{"ready": false}
+		if s.sigClient != nil && s.sigClient.IsInitialized() {
+			signalCheck["ready"] = true
+		} else {
+			ready = false
+			if s.sigClient != nil {
+				if initErr := s.sigClient.InitializationError(); initErr != "" {
+					signalCheck["error"] = initErr
+				}
+			}
+		}
+
+		sessionCheck := map[string]interface{}{"ready": false}
+		if sessionName := s.workingSession(ctx); sessionName != "" {
+			sessionCheck["ready"] = true
+			sessionCheck["session"] = sessionName
+		} else {
+			ready = false
+		}
+
+		status := "ready"
+		httpStatus := http.StatusOK
+		if !ready {
+			status = "not_ready"
+			httpStatus = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatus)
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  status,
+			"version": Version,
+			"checks": map[string]interface{}{
+				"migrations":       migrations,
+				"signal_device":    signalCheck,
+				"whatsapp_session": sessionCheck,
+			},
+		}); err != nil {
+			s.logger.WithError(err).Error("Failed to write readiness response")
+		}
+	}
+}
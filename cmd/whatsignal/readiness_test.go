@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"whatsignal/internal/models"
+	"whatsignal/pkg/signal"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newInitializedSignalClient returns a Signal client that completed device initialization
+// against a stub signal-cli-rest-api
+func newInitializedSignalClient(t *testing.T) *signal.SignalClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"versions":["v1","v2"],"mode":"normal"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client := signal.NewClient(srv.URL, "+1234567890", "test-device", t.TempDir(), srv.Client()).(*signal.SignalClient)
+	require.NoError(t, client.InitializeDevice(context.Background()))
+	return client
+}
+
+func getReadiness(t *testing.T, server *Server, path string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	return w.Code, body
+}
+
+func TestServer_ReadinessNotReadyUntilStartupCompletes(t *testing.T) {
+	mockWAClient := &mockWAClient{}
+	mockWAClient.On("GetSessionStatusByName", mock.Anything, "default").Return(&types.Session{Name: "default", Status: "STARTING"}, nil)
+
+	server := NewServer(&models.Config{}, &mockMessageService{}, logrus.New(), mockWAClient, createTestChannelManager(), &mockDatabase{}, nil)
+
+	for _, path := range []string{"/ready", "/readyz"} {
+		t.Run(path, func(t *testing.T) {
+			status, body := getReadiness(t, server, path)
+			assert.Equal(t, http.StatusServiceUnavailable, status)
+			assert.Equal(t, "not_ready", body["status"])
+
+			checks := body["checks"].(map[string]interface{})
+			for _, check := range []string{"migrations", "signal_device", "whatsapp_session"} {
+				assert.Equal(t, false, checks[check].(map[string]interface{})["ready"], check)
+			}
+		})
+	}
+}
+
+func TestServer_ReadinessRequiresWorkingSession(t *testing.T) {
+	mockWAClient := &mockWAClient{}
+	mockWAClient.On("GetSessionStatusByName", mock.Anything, "default").Return(nil, errors.New("waha unreachable"))
+
+	server := NewServer(&models.Config{}, &mockMessageService{}, logrus.New(), mockWAClient, createTestChannelManager(), &mockDatabase{}, newInitializedSignalClient(t))
+	server.MarkMigrationsApplied()
+
+	status, body := getReadiness(t, server, "/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	checks := body["checks"].(map[string]interface{})
+	assert.Equal(t, true, checks["migrations"].(map[string]interface{})["ready"])
+	assert.Equal(t, true, checks["signal_device"].(map[string]interface{})["ready"])
+	assert.Equal(t, false, checks["whatsapp_session"].(map[string]interface{})["ready"])
+}
+
+func TestServer_ReadinessReady(t *testing.T) {
+	mockWAClient := &mockWAClient{}
+	mockWAClient.On("GetSessionStatusByName", mock.Anything, "default").Return(&types.Session{Name: "default", Status: "WORKING"}, nil).Once()
+
+	server := NewServer(&models.Config{}, &mockMessageService{}, logrus.New(), mockWAClient, createTestChannelManager(), &mockDatabase{}, newInitializedSignalClient(t))
+	server.MarkMigrationsApplied()
+
+	status, body := getReadiness(t, server, "/ready")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ready", body["status"])
+	assert.Equal(t, "default", body["checks"].(map[string]interface{})["whatsapp_session"].(map[string]interface{})["session"])
+
+	// A session that reached WORKING is remembered without asking WAHA again
+	status, _ = getReadiness(t, server, "/ready")
+	assert.Equal(t, http.StatusOK, status)
+	mockWAClient.AssertNumberOfCalls(t, "GetSessionStatusByName", 1)
+}
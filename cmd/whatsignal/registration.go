@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/privacy"
+	signalapi "whatsignal/pkg/signal"
+
+	"github.com/sirupsen/logrus"
+)
+
+type signalRegisterRequest struct {
+	UseVoice bool   `json:"useVoice"`
+	Captcha  string `json:"captcha"`
+}
+
+type signalVerifyRequest struct {
+	Code string `json:"code"`
+	Pin  string `json:"pin"`
+}
+
+// warnIfSignalUnregistered logs how to complete registration when the intermediary number is
+// not registered with signal-cli, which otherwise only shows up as failing sends and receives
+func warnIfSignalUnregistered(ctx context.Context, client *signalapi.SignalClient, phoneNumber string, logger *logrus.Logger) {
+	registered, err := client.IsRegistered(ctx)
+	if err != nil {
+		logger.WithError(err).Debug("Could not check Signal registration state")
+		return
+	}
+	if registered {
+		return
+	}
+
+	logger.WithField("number", privacy.MaskPhoneNumber(phoneNumber)).Warn(
+		"Signal number is not registered with signal-cli. Register it with POST /signal/register " +
+			`(body {"captcha": "<token from https://signalcaptchas.org/registration/generate.html>"}), ` +
+			`then confirm the code Signal sends with POST /signal/verify (body {"code": "123-456"}). ` +
+			"Both endpoints require the WHATSIGNAL_ADMIN_TOKEN bearer token.")
+}
+
+// requireRegistrationAuth checks the admin token. Unlike diagnostics endpoints, registration
+// can take over the Signal number, so it is refused outright when no admin token is configured.
+func requireRegistrationAuth(w http.ResponseWriter, r *http.Request) bool {
+	if os.Getenv("WHATSIGNAL_ADMIN_TOKEN") == "" {
+		http.Error(w, "Signal registration requires WHATSIGNAL_ADMIN_TOKEN to be set", http.StatusForbidden)
+		return false
+	}
+	return requireProductionAdminToken(w, r)
+}
+
+// decodeOptionalJSONBody decodes an optional JSON request body into v
+func decodeOptionalJSONBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, constants.MaxSignalRegistrationBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleSignalRegister() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !requireRegistrationAuth(w, r) {
+			return
+		}
+		if s.sigClient == nil {
+			s.writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"error": "Signal client not available"})
+			return
+		}
+
+		var req signalRegisterRequest
+		if !decodeOptionalJSONBody(w, r, &req) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(constants.DefaultSignalRegistrationTimeoutSec)*time.Second)
+		defer cancel()
+
+		if err := s.sigClient.Register(ctx, req.UseVoice, strings.TrimSpace(req.Captcha)); err != nil {
+			s.logger.WithError(err).Warn("Signal registration request failed")
+			// signal-cli's message says what is missing, e.g. a captcha, so pass it on
+			s.writeJSON(w, http.StatusBadGateway, map[string]interface{}{"error": err.Error()})
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status":  "verification_requested",
+			"message": "Signal sent a verification code. Submit it to POST /signal/verify.",
+		})
+	}
+}
+
+func (s *Server) handleSignalVerify() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !requireRegistrationAuth(w, r) {
+			return
+		}
+		if s.sigClient == nil {
+			s.writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"error": "Signal client not available"})
+			return
+		}
+
+		var req signalVerifyRequest
+		if !decodeOptionalJSONBody(w, r, &req) {
+			return
+		}
+		code := strings.TrimSpace(req.Code)
+		if !isVerificationCode(code) {
+			s.writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "code must be the numeric verification code, e.g. 123-456"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(constants.DefaultSignalRegistrationTimeoutSec)*time.Second)
+		defer cancel()
+
+		if err := s.sigClient.VerifyRegistration(ctx, code, strings.TrimSpace(req.Pin)); err != nil {
+			s.logger.WithError(err).Warn("Signal verification failed")
+			s.writeJSON(w, http.StatusBadGateway, map[string]interface{}{"error": err.Error()})
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{"status": "verified"})
+	}
+}
+
+// isVerificationCode reports whether code looks like a Signal verification code: digits,
+// optionally split by a hyphen
+func isVerificationCode(code string) bool {
+	digits := 0
+	for _, c := range code {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '-':
+		default:
+			return false
+		}
+	}
+	return digits >= 4 && digits <= 8
+}
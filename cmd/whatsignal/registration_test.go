@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"whatsignal/internal/models"
+	"whatsignal/pkg/signal"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRegistrationToken = "registration-test-token-0123456789abcdef"
+
+// newRegistrationTestServer returns a Server whose Signal client talks to a stub
+// signal-cli-rest-api, recording the paths it was called with
+func newRegistrationTestServer(t *testing.T, status int, body string) (*Server, *[]string) {
+	t.Helper()
+	var paths []string
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(stub.Close)
+
+	sigClient := signal.NewClient(stub.URL, "+15550001111", "test-device", t.TempDir(), stub.Client()).(*signal.SignalClient)
+	server := NewServer(&models.Config{}, &mockMessageService{}, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, sigClient)
+	return server, &paths
+}
+
+func postRegistration(server *Server, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSignalRegistration_RequiresAdminToken(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	t.Run("no token configured", func(t *testing.T) {
+		t.Setenv("WHATSIGNAL_ADMIN_TOKEN", "")
+		server, paths := newRegistrationTestServer(t, http.StatusCreated, "")
+
+		w := postRegistration(server, "/signal/register", "", `{}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, *paths)
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		t.Setenv("WHATSIGNAL_ADMIN_TOKEN", testRegistrationToken)
+		server, paths := newRegistrationTestServer(t, http.StatusCreated, "")
+
+		w := postRegistration(server, "/signal/verify", "wrong", `{"code":"123-456"}`)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, *paths)
+	})
+}
+
+func TestSignalRegistration_Register(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", testRegistrationToken)
+	server, paths := newRegistrationTestServer(t, http.StatusCreated, "")
+
+	w := postRegistration(server, "/signal/register", testRegistrationToken, `{"captcha":"signalcaptcha://token"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "verification_requested")
+	assert.Equal(t, []string{"/v1/register/+15550001111"}, *paths)
+}
+
+func TestSignalRegistration_RegisterPassesOnSignalError(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", testRegistrationToken)
+	server, _ := newRegistrationTestServer(t, http.StatusBadRequest, `{"error":"Captcha required for verification"}`)
+
+	w := postRegistration(server, "/signal/register", testRegistrationToken, "")
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Contains(t, w.Body.String(), "Captcha required")
+}
+
+func TestSignalRegistration_Verify(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", testRegistrationToken)
+
+	t.Run("valid code", func(t *testing.T) {
+		server, paths := newRegistrationTestServer(t, http.StatusCreated, "")
+
+		w := postRegistration(server, "/signal/verify", testRegistrationToken, `{"code":"123-456"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "verified")
+		assert.Equal(t, []string{"/v1/register/+15550001111/verify/123-456"}, *paths)
+	})
+
+	for _, code := range []string{"", "12", "12a-456", "../accounts"} {
+		t.Run("rejects "+strings.ReplaceAll(code, "/", "_"), func(t *testing.T) {
+			server, paths := newRegistrationTestServer(t, http.StatusCreated, "")
+
+			w := postRegistration(server, "/signal/verify", testRegistrationToken, `{"code":"`+code+`"}`)
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Empty(t, *paths)
+		})
+	}
+}
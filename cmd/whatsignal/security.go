@@ -119,6 +119,16 @@ func (c *WebhookReplayCache) CheckAndStore(body []byte, timestamp string, ttl ti
 	return false
 }
 
+// Forget removes a stored delivery so the same body and timestamp are accepted again
+func (c *WebhookReplayCache) Forget(body []byte, timestamp string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, webhookReplayKey(body, timestamp))
+}
+
 func webhookReplayKey(body []byte, timestamp string) string {
 	bodyHash := sha256.Sum256(body)
 	return hex.EncodeToString(bodyHash[:]) + ":" + timestamp
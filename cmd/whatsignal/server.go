@@ -6,18 +6,23 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"whatsignal/internal/constants"
+	apperrors "whatsignal/internal/errors"
 	"whatsignal/internal/metrics"
 	"whatsignal/internal/middleware"
 	"whatsignal/internal/models"
 	"whatsignal/internal/service"
+	"whatsignal/internal/tracing"
+	"whatsignal/pkg/media"
 	"whatsignal/pkg/signal"
 	"whatsignal/pkg/whatsapp/types"
 
@@ -27,16 +32,25 @@ import (
 
 const (
 	XWahaSignatureHeader = "X-Webhook-Hmac"
+	XApiKeyHeader        = "X-Api-Key"
 )
 
-// DatabaseInterface defines the minimal interface needed for health checks
+// DatabaseInterface defines the minimal database interface needed by the HTTP handlers
 type DatabaseInterface interface {
 	HealthCheck(ctx context.Context) error
+	SaveMessageEdit(ctx context.Context, edit *models.MessageEdit) error
+	GetMessageEdits(ctx context.Context, whatsappMsgID string) ([]models.MessageEdit, error)
+	GetMessageMapping(ctx context.Context, id string) (*models.MessageMapping, error)
 }
 
 // SignalClientInterface defines the minimal interface needed for health checks
 type SignalClientInterface = *signal.SignalClient
 
+// MediaCacheInterface reports media cache statistics for /metrics and /session/status
+type MediaCacheInterface interface {
+	CacheStats() (media.CacheStats, error)
+}
+
 // ValidationError represents a validation error that should return HTTP 400
 type ValidationError struct {
 	Message string `json:"message"`
@@ -80,6 +94,14 @@ type Server struct {
 	replayCache    *WebhookReplayCache
 	db             DatabaseInterface
 	sigClient      SignalClientInterface
+	sessionNumbers *service.SessionNumbers
+	mediaCache     MediaCacheInterface
+	groupService   service.GroupServiceInterface
+	debugPayloads  *debugPayloadStore
+	workQueue      *service.WorkQueue
+
+	migrationsApplied  atomic.Bool
+	workingSessionName atomic.Value
 }
 
 func NewServer(cfg *models.Config, msgService service.MessageService, logger *logrus.Logger, waClient types.WAClient, channelManager *service.ChannelManager, db DatabaseInterface, sigClient SignalClientInterface) *Server {
@@ -106,6 +128,7 @@ func NewServer(cfg *models.Config, msgService service.MessageService, logger *lo
 		replayCache:    NewWebhookReplayCache(),
 		db:             db,
 		sigClient:      sigClient,
+		debugPayloads:  newDebugPayloadStore(cfg.Server, logger),
 	}
 
 	s.setupRoutes()
@@ -113,6 +136,30 @@ func NewServer(cfg *models.Config, msgService service.MessageService, logger *lo
 	return s
 }
 
+// SetSessionNumbers makes the session status report include the session's redacted phone number.
+// It must be called before Start.
+func (s *Server) SetSessionNumbers(numbers *service.SessionNumbers) {
+	s.sessionNumbers = numbers
+}
+
+// SetMediaCache makes /metrics and /session/status include media cache statistics. It must be
+// called before Start.
+func (s *Server) SetMediaCache(cache MediaCacheInterface) {
+	s.mediaCache = cache
+}
+
+// SetGroupService enables POST /sessions/{name}/groups/{groupId}/refresh, which refreshes
+// groups through groups. It must be called before Start.
+func (s *Server) SetGroupService(groups service.GroupServiceInterface) {
+	s.groupService = groups
+}
+
+// SetWorkQueue bounds the WhatsApp webhooks processed at once; webhooks arriving while queue is
+// full are answered 503 so WAHA retries them later. It must be called before Start.
+func (s *Server) SetWorkQueue(queue *service.WorkQueue) {
+	s.workQueue = queue
+}
+
 func (s *Server) setupRoutes() {
 	// Recovery middleware as outermost layer to catch panics
 	s.router.Use(middleware.RecoveryMiddleware(s.logger))
@@ -122,32 +169,60 @@ func (s *Server) setupRoutes() {
 	public.Use(middleware.ObservabilityMiddleware(s.logger))
 	public.HandleFunc("/health", s.handleHealth()).Methods(http.MethodGet)
 	public.HandleFunc("/healthz", s.handleLiveness()).Methods(http.MethodGet)
-	public.HandleFunc("/readyz", s.handleHealth()).Methods(http.MethodGet)
+	public.HandleFunc("/ready", s.handleReadiness()).Methods(http.MethodGet)
+	public.HandleFunc("/readyz", s.handleReadiness()).Methods(http.MethodGet)
 	public.HandleFunc("/session/status", s.handleSessionStatus()).Methods(http.MethodGet)
 	public.HandleFunc("/metrics", s.handleMetrics()).Methods(http.MethodGet)
+	public.HandleFunc("/sessions/{name}/groups/{groupId}/refresh", s.handleGroupRefresh()).Methods(http.MethodPost)
+	public.HandleFunc("/edits/{id}", s.handleMessageEdits()).Methods(http.MethodGet)
+	public.HandleFunc("/api/messages/{id}", s.handleMessageLookup()).Methods(http.MethodGet)
+	public.HandleFunc("/signal/register", s.handleSignalRegister()).Methods(http.MethodPost)
+	public.HandleFunc("/signal/verify", s.handleSignalVerify()).Methods(http.MethodPost)
+	public.HandleFunc("/loglevel", s.handleLogLevel()).Methods(http.MethodPost)
+	public.HandleFunc("/config", s.handleConfig()).Methods(http.MethodGet)
 
 	// Webhook endpoints with security middleware and webhook-specific observability
 	// Note: We use WebhookObservabilityMiddleware instead of the general ObservabilityMiddleware
 	// to avoid duplicate logging and provide webhook-specific context
-	whatsapp := s.router.PathPrefix("/webhook/whatsapp").Subrouter()
+	whatsapp := s.router.PathPrefix(s.webhookPath()).Subrouter()
 	whatsapp.Use(s.securityMiddleware)
 	whatsapp.Use(middleware.WebhookObservabilityMiddleware(s.logger, "whatsapp"))
 	whatsapp.HandleFunc("", s.handleWhatsAppWebhook()).Methods(http.MethodPost)
 
 }
 
-func (s *Server) Start() error {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = strconv.Itoa(constants.DefaultServerPort)
+// webhookPath returns the path WAHA webhooks are received at: server.webhookPath, or
+// /webhook/whatsapp when it is not set
+func (s *Server) webhookPath() string {
+	if s.cfg.Server.WebhookPath != "" {
+		return s.cfg.Server.WebhookPath
 	}
+	return constants.DefaultWebhookPath
+}
 
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+// listenPort returns the port to listen on: the PORT environment variable, else server.port,
+// else the default
+func (s *Server) listenPort() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return port
+	}
+	if s.cfg.Server.Port > 0 {
+		return strconv.Itoa(s.cfg.Server.Port)
+	}
+	return strconv.Itoa(constants.DefaultServerPort)
+}
+
+func (s *Server) Start() error {
+	addr := ":" + s.listenPort()
+	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return err
 	}
 
-	s.logger.Infof("Starting server on port %s", port)
+	s.logger.WithFields(logrus.Fields{
+		"address":      listener.Addr().String(),
+		"webhook_path": s.webhookPath(),
+	}).Infof("Starting server on %s, receiving WhatsApp webhooks at %s", addr, s.webhookPath())
 	return s.Serve(listener)
 }
 
@@ -237,7 +312,7 @@ func (s *Server) securityMiddleware(next http.Handler) http.Handler {
 			r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
 
 			// Additional validation for webhook endpoints (POST to webhook paths)
-			if r.Method == http.MethodPost && (strings.Contains(r.URL.Path, "webhook") || strings.Contains(r.URL.Path, "whatsapp") || strings.Contains(r.URL.Path, "signal")) {
+			if r.Method == http.MethodPost && (r.URL.Path == s.webhookPath() || strings.Contains(r.URL.Path, "webhook") || strings.Contains(r.URL.Path, "whatsapp") || strings.Contains(r.URL.Path, "signal")) {
 				contentType := r.Header.Get("Content-Type")
 				if !strings.Contains(contentType, "application/json") {
 					s.logger.WithFields(logrus.Fields{
@@ -262,21 +337,16 @@ func (s *Server) securityMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// handleHealth confirms the process and database respond. Dependencies that can be down
+// without the process being broken (WAHA, signal-cli) are reported by handleReadiness.
 func (s *Server) handleHealth() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		// Check all dependencies
 		dependencies := map[string]interface{}{
 			"database": map[string]interface{}{
 				"status": "healthy",
 			},
-			"whatsapp_api": map[string]interface{}{
-				"status": "healthy",
-			},
-			"signal_api": map[string]interface{}{
-				"status": "healthy",
-			},
 		}
 
 		overallStatus := "healthy"
@@ -292,38 +362,6 @@ func (s *Server) handleHealth() http.HandlerFunc {
 			}
 		}
 
-		// Check WhatsApp API health
-		if s.waClient != nil {
-			if err := s.waClient.HealthCheck(ctx); err != nil {
-				s.logger.WithError(err).Warn("WhatsApp API health check failed")
-				dependencies["whatsapp_api"] = map[string]interface{}{
-					"status": "unhealthy",
-				}
-				if overallStatus == "healthy" {
-					overallStatus = "degraded"
-				}
-			}
-		}
-
-		// Check Signal API health
-		if s.sigClient != nil {
-			signalStatus := map[string]interface{}{
-				"status":      "healthy",
-				"initialized": s.sigClient.IsInitialized(),
-			}
-			if initErr := s.sigClient.InitializationError(); initErr != "" {
-				s.logger.WithField("init_error", initErr).Warn("Signal API initialization error")
-			}
-			if err := s.sigClient.HealthCheck(ctx); err != nil {
-				s.logger.WithError(err).Warn("Signal API health check failed")
-				signalStatus["status"] = "unhealthy"
-				if overallStatus == "healthy" {
-					overallStatus = "degraded"
-				}
-			}
-			dependencies["signal_api"] = signalStatus
-		}
-
 		health := map[string]interface{}{
 			"status":       overallStatus,
 			"version":      Version,
@@ -336,14 +374,10 @@ func (s *Server) handleHealth() http.HandlerFunc {
 
 		w.Header().Set("Content-Type", "application/json")
 
-		// Return appropriate HTTP status based on health
-		switch overallStatus {
-		case "healthy":
+		if overallStatus == "healthy" {
 			w.WriteHeader(http.StatusOK)
-		case "degraded":
+		} else {
 			w.WriteHeader(http.StatusServiceUnavailable)
-		case "unhealthy":
-			w.WriteHeader(http.StatusServiceUnavailable) // 503
 		}
 
 		if err := json.NewEncoder(w).Encode(health); err != nil {
@@ -393,6 +427,20 @@ func (s *Server) handleSessionStatus() http.HandlerFunc {
 			"healthy":    string(session.Status) == "WORKING",
 			"updated_at": session.UpdatedAt,
 		}
+		if phone := s.sessionNumbers.Redacted(session.Name); phone != "" {
+			sessionStatus["phone_number"] = phone
+		}
+
+		if stats, ok := s.mediaCacheStats(); ok {
+			sessionStatus["media_cache"] = map[string]interface{}{
+				"files":               stats.Files,
+				"size_bytes":          stats.SizeBytes,
+				"oldest_file_age_sec": int64(stats.OldestFileAge.Seconds()),
+				"hits":                stats.Hits,
+				"misses":              stats.Misses,
+				"hit_rate":            stats.HitRate,
+			}
+		}
 
 		// Add config info
 		sessionStatus["config"] = map[string]interface{}{
@@ -408,31 +456,119 @@ func (s *Server) handleSessionStatus() http.HandlerFunc {
 	}
 }
 
+// handleGroupRefresh re-fetches a group's metadata from WAHA through the session in the path and
+// updates the groups cache, so group prefixes pick up renames without waiting for the cache to
+// expire.
+func (s *Server) handleGroupRefresh() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !requireProductionAdminToken(w, r) {
+			return
+		}
+
+		vars := mux.Vars(r)
+		sessionName := vars["name"]
+		groupID := vars["groupId"]
+
+		if !s.channelManager.IsValidSession(sessionName) {
+			s.writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": "unknown session"})
+			return
+		}
+		if !strings.HasSuffix(groupID, "@g.us") {
+			s.writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "invalid group ID"})
+			return
+		}
+
+		if s.groupService == nil {
+			s.writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"error": "group refresh unavailable"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(constants.DefaultGroupRefreshTimeoutSec)*time.Second)
+		defer cancel()
+
+		group, err := s.groupService.RefreshGroup(ctx, groupID, sessionName)
+		if err != nil {
+			s.logger.WithError(err).WithField("session", sessionName).Error("Failed to refresh group")
+			s.writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"error": "Failed to refresh group"})
+			return
+		}
+		if group == nil {
+			s.writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": "group not found"})
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"group_id":          group.GroupID,
+			"session":           sessionName,
+			"subject":           group.Subject,
+			"participant_count": group.ParticipantCount,
+		})
+	}
+}
+
+// handleMessageEdits returns the recorded edit history of a bridged WhatsApp message
+func (s *Server) handleMessageEdits() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !requireProductionAdminToken(w, r) {
+			return
+		}
+		if !s.cfg.WhatsApp.EditHistory {
+			s.writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": "edit history is disabled"})
+			return
+		}
+
+		messageID := mux.Vars(r)["id"]
+		edits, err := s.db.GetMessageEdits(r.Context(), messageID)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to load message edit history")
+			s.writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load edit history"})
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"message_id": messageID,
+			"edits":      edits,
+		})
+	}
+}
+
+// writeJSON writes body as a JSON response with the given status code
+func (s *Server) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.WithError(err).Error("Failed to write JSON response")
+	}
+}
+
 func (s *Server) handleWhatsAppWebhook() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		receivedAt := time.Now()
 		s.logger.Debug("Processing WhatsApp webhook request")
+		requestID := tracing.GetRequestID(r.Context())
 
 		maxSkewSec := s.cfg.Server.WebhookMaxSkewSec
 		if maxSkewSec <= 0 {
 			maxSkewSec = constants.DefaultWebhookMaxSkewSec
 		}
 		maxSkew := time.Duration(maxSkewSec) * time.Second
-		bodyBytes, err := verifySignatureWithSkew(r, s.cfg.WhatsApp.WebhookSecret, XWahaSignatureHeader, maxSkew)
+		bodyBytes, err := verifySignatureWithSkew(r, s.webhookSecretFor(r), XWahaSignatureHeader, maxSkew)
 		if err != nil {
 			if isRequestBodyTooLarge(err) {
 				s.logger.WithError(err).Warn("Webhook request body too large")
-				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				s.writeWebhookError(w, webhookError{status: http.StatusRequestEntityTooLarge, code: apperrors.ErrCodeInvalidInput, message: http.StatusText(http.StatusRequestEntityTooLarge)}, requestID)
 				return
 			}
 			s.logger.WithError(err).Error("WhatsApp webhook signature verification failed")
-			http.Error(w, "Signature verification failed", http.StatusUnauthorized)
+			s.writeWebhookError(w, webhookError{status: http.StatusUnauthorized, code: apperrors.ErrCodeAuthentication, message: "Signature verification failed"}, requestID)
 			return
 		}
-		if timestamp := r.Header.Get("X-Webhook-Timestamp"); timestamp != "" {
+		timestamp := r.Header.Get("X-Webhook-Timestamp")
+		if timestamp != "" {
 			replayTTL := maxSkew + time.Duration(constants.DefaultWebhookReplayBufferSec)*time.Second
 			if s.replayCache.CheckAndStore(bodyBytes, timestamp, replayTTL) {
 				s.logger.Warn("WhatsApp webhook replay rejected")
-				http.Error(w, "Webhook replay detected", http.StatusConflict)
+				s.writeWebhookError(w, webhookError{status: http.StatusConflict, code: apperrors.ErrCodeValidationFailed, message: "Webhook replay detected"}, requestID)
 				return
 			}
 		}
@@ -442,20 +578,47 @@ func (s *Server) handleWhatsAppWebhook() http.HandlerFunc {
 			s.logger.WithError(err).Error("Failed to decode webhook payload after signature verification")
 			// Do not log raw body to avoid leaking PII; log size instead
 			s.logger.WithField("body_len", len(bodyBytes)).Debug("Invalid webhook JSON payload")
-			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			s.writeWebhookError(w, webhookError{status: http.StatusBadRequest, code: apperrors.ErrCodeInvalidInput, message: "Invalid request body"}, requestID)
 			return
 		}
 
 		s.logger.WithField("event", payload.Event).Debug("Received WhatsApp webhook payload")
 
+		if !s.webhookEventEnabled(payload.Event) {
+			s.logger.WithField("event", payload.Event).Debug("Dropping WhatsApp event not listed in enabledEvents")
+			metrics.IncrementCounter("webhook_events_filtered_total", map[string]string{
+				"event": payload.Event,
+			}, "WhatsApp webhook events dropped by the enabledEvents filter")
+			s.writeWebhookIgnored(w, "event_filtered", requestID)
+			return
+		}
+
 		// Skip messages from ourselves to avoid loops, but only for content events.
 		// ACK and waiting events for our own messages are expected and must be processed.
 		if payload.Payload.FromMe && payload.Event != models.EventMessageACK && payload.Event != models.EventMessageWaiting {
 			s.logger.Debug("Skipping message from ourselves")
-			w.WriteHeader(http.StatusOK)
+			s.writeWebhookIgnored(w, "from_me", requestID)
 			return
 		}
 
+		if !s.workQueue.TryAcquire() {
+			s.logger.WithFields(logrus.Fields{
+				"event":       payload.Event,
+				"queue_depth": s.workQueue.Depth(),
+			}).Warn("Work queue full, shedding WhatsApp webhook")
+			metrics.IncrementCounter("webhook_requests_shed_total", map[string]string{
+				"event": payload.Event,
+			}, "WhatsApp webhooks answered 503 because the work queue was full")
+			if timestamp != "" {
+				// Let WAHA's retry of this delivery through the replay check
+				s.replayCache.Forget(bodyBytes, timestamp)
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(constants.QueueFullRetryAfterSec))
+			s.writeWebhookError(w, webhookError{status: http.StatusServiceUnavailable, code: apperrors.ErrCodeRateLimit, message: "Too much work queued", retry: true}, requestID)
+			return
+		}
+		defer s.workQueue.Release()
+
 		// Use a detached context for processing to survive WAHA connection timeouts.
 		// WAHA may close the webhook HTTP connection before processing completes (especially
 		// for WA→Signal forwards that involve retrying the Signal send). Using r.Context()
@@ -465,6 +628,12 @@ func (s *Server) handleWhatsAppWebhook() http.HandlerFunc {
 		// deadline before completing a single successful send.
 		processCtx, processCancel := context.WithTimeout(context.Background(), 120*time.Second)
 		defer processCancel()
+		// Keep the request ID so the message's log lines and outbound calls can be correlated
+		if requestID := tracing.GetRequestID(r.Context()); requestID != "" {
+			processCtx = tracing.WithRequestID(processCtx, requestID)
+		}
+		processCtx = tracing.EnsureRequestID(processCtx)
+		processCtx = service.WithIngressTime(processCtx, receivedAt)
 
 		// Handle different event types
 		switch payload.Event {
@@ -478,24 +647,69 @@ func (s *Server) handleWhatsAppWebhook() http.HandlerFunc {
 			err = s.handleWhatsAppACK(processCtx, &payload)
 		case models.EventMessageWaiting:
 			err = s.handleWhatsAppWaitingMessage(processCtx, &payload)
+		case models.EventGroupParticipants, models.EventGroupV2Participants:
+			err = s.handleWhatsAppGroupParticipants(processCtx, &payload, bodyBytes)
 		default:
 			s.logger.WithField("event", payload.Event).Debug("Skipping unsupported WhatsApp event")
-			w.WriteHeader(http.StatusOK)
+			s.writeWebhookIgnored(w, "unsupported_event", requestID)
 			return
 		}
 
 		if err != nil {
-			s.logger.WithError(err).WithField("event", payload.Event).Error("Failed to handle WhatsApp event")
-			if _, isValidationError := err.(ValidationError); isValidationError {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-			} else {
-				http.Error(w, "Failed to process event", http.StatusInternalServerError)
+			webhookErr := classifyWebhookError(err)
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"event":  payload.Event,
+				"status": webhookErr.status,
+				"retry":  webhookErr.retry,
+			}).Error("Failed to handle WhatsApp event")
+			s.debugPayloads.save(payload.Event, tracing.GetRequestID(processCtx), bodyBytes, err)
+			if webhookErr.retry && timestamp != "" {
+				// Let WAHA's retry of this delivery through the replay check
+				s.replayCache.Forget(bodyBytes, timestamp)
 			}
+			s.writeWebhookError(w, webhookErr, tracing.GetRequestID(processCtx))
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
+		s.writeWebhookAccepted(w, tracing.GetRequestID(processCtx))
+	}
+}
+
+// webhookSecretFor returns the secret a WhatsApp webhook must be signed with: the secret of the
+// channel for the session named in its payload, or whatsapp.webhook_secret when that channel has
+// none or the session cannot be read. The body is read ahead of verification and put back.
+func (s *Server) webhookSecretFor(r *http.Request) string {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		// Verification reads the body again and reports the error
+		return s.cfg.WhatsApp.WebhookSecret
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var envelope struct {
+		Session string `json:"session"`
+	}
+	if json.Unmarshal(body, &envelope) == nil && envelope.Session != "" && s.channelManager != nil {
+		if secret := s.channelManager.GetWebhookSecret(envelope.Session); secret != "" {
+			return secret
+		}
 	}
+	return s.cfg.WhatsApp.WebhookSecret
+}
+
+// webhookEventEnabled reports whether a WhatsApp webhook event should be processed. With no
+// whatsapp.enabledEvents configured every event is; "message" always is.
+func (s *Server) webhookEventEnabled(event string) bool {
+	enabled := s.cfg.WhatsApp.EnabledEvents
+	if len(enabled) == 0 || event == models.EventMessage {
+		return true
+	}
+	for _, e := range enabled {
+		if strings.EqualFold(strings.TrimSpace(e), event) {
+			return true
+		}
+	}
+	return false
 }
 
 func isRequestBodyTooLarge(err error) bool {
@@ -510,15 +724,25 @@ func (s *Server) handleWhatsAppMessage(ctx context.Context, payload *models.What
 	if payload.Payload.From == "" {
 		return ValidationError{Message: "missing required field: Payload.From"}
 	}
-	if payload.Payload.Body == "" && !payload.Payload.HasMedia {
+	if pin, ok := payload.PinnedMessage(); ok {
+		return s.handleWhatsAppPin(ctx, payload, pin)
+	}
+	if loc, ok := payload.LiveLocation(); ok {
+		return s.handleWhatsAppLiveLocation(ctx, payload, loc)
+	}
+	product, isProduct := payload.ProductMessage()
+	payment, isPayment := payload.PaymentMessage()
+	location, isLocation := payload.Location()
+	viewOnce := payload.IsViewOnce()
+	if payload.Payload.Body == "" && !payload.Payload.HasMedia && !isProduct && !isPayment && !isLocation && !viewOnce {
 		// Skip empty system messages (status updates, typing indicators, etc.)
-		s.logger.WithField("messageID", service.SanitizeMessageID(payload.Payload.ID)).Debug("Ignoring empty system message")
+		s.logger.WithContext(ctx).WithField("messageID", service.SanitizeMessageID(payload.Payload.ID)).Debug("Ignoring empty system message")
 		return nil
 	}
 
 	// Skip WhatsApp status/broadcast messages
 	if strings.Contains(payload.Payload.From, "status@broadcast") {
-		s.logger.WithFields(logrus.Fields{
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"messageID": service.SanitizeMessageID(payload.Payload.ID),
 			"from":      payload.Payload.From,
 		}).Debug("Ignoring WhatsApp status/broadcast message")
@@ -534,7 +758,7 @@ func (s *Server) handleWhatsAppMessage(ctx context.Context, payload *models.What
 	isGroupMessage := strings.HasSuffix(chatID, "@g.us")
 	if isGroupMessage && payload.Payload.Participant != "" {
 		sender = payload.Payload.Participant
-		s.logger.WithFields(logrus.Fields{
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"chatID":      service.SanitizePhoneNumber(chatID),
 			"participant": service.SanitizePhoneNumber(sender),
 		}).Debug("Group message: using participant as sender")
@@ -555,7 +779,7 @@ func (s *Server) handleWhatsAppMessage(ctx context.Context, payload *models.What
 	// Validate sender phone number and skip invalid system messages
 	// (newsletters, channels, and other special WhatsApp message types may have invalid sender IDs)
 	if err := service.ValidatePhoneNumber(sender); err != nil {
-		s.logger.WithFields(logrus.Fields{
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"messageID": service.SanitizeMessageID(payload.Payload.ID),
 			"from":      payload.Payload.From,
 			"sender":    sender,
@@ -583,6 +807,30 @@ func (s *Server) handleWhatsAppMessage(ctx context.Context, payload *models.What
 		return nil
 	}
 
+	if quote, ok := payload.QuotedMessage(); ok {
+		ctx = service.WithWhatsAppQuote(ctx, service.WhatsAppQuote{
+			MessageID: quote.ID,
+			Body:      quote.Body,
+			FromMe:    quote.Participant != "" && quote.Participant == payload.Me.ID,
+		})
+	}
+	if isProduct {
+		ctx = service.WithWhatsAppProduct(ctx, product)
+	}
+	if isPayment {
+		ctx = service.WithWhatsAppPayment(ctx, payment)
+	}
+	if isLocation {
+		ctx = service.WithWhatsAppLocation(ctx, location)
+	}
+	if viewOnce {
+		ctx = service.WithWhatsAppViewOnce(ctx)
+	}
+	ctx = service.WithWhatsAppExpiry(ctx, payload.EphemeralExpiry())
+	if ts := payload.Payload.Timestamp.Int64(); ts > 0 {
+		ctx = service.WithWhatsAppSentAt(ctx, time.Unix(ts, 0))
+	}
+
 	return s.msgService.HandleWhatsAppMessageWithSession(
 		ctx,
 		sessionName,
@@ -612,7 +860,7 @@ func (s *Server) handleWhatsAppReaction(ctx context.Context, payload *models.Wha
 		return nil
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"from":      service.SanitizePhoneNumber(payload.Payload.From),
 		"messageId": service.SanitizeWhatsAppMessageID(payload.Payload.Reaction.MessageID),
 		"emoji":     payload.Payload.Reaction.Text,
@@ -621,34 +869,29 @@ func (s *Server) handleWhatsAppReaction(ctx context.Context, payload *models.Wha
 	// Find the original message mapping to get the Signal message ID
 	mapping, err := s.msgService.GetMessageMappingByWhatsAppID(ctx, payload.Payload.Reaction.MessageID)
 	if err != nil {
-		s.logger.WithError(err).Warn("Could not find original message for reaction")
+		s.logger.WithContext(ctx).WithError(err).Warn("Could not find original message for reaction")
 		return nil // Don't error out, just log and continue
 	}
 
 	if mapping == nil {
-		s.logger.WithField("messageId", service.SanitizeWhatsAppMessageID(payload.Payload.Reaction.MessageID)).Warn("No mapping found for reacted message")
+		s.logger.WithContext(ctx).WithField("messageId", service.SanitizeWhatsAppMessageID(payload.Payload.Reaction.MessageID)).Warn("No mapping found for reacted message")
 		return nil // Don't error out, just log and continue
 	}
 
-	// Forward reaction to Signal as a text message (since Signal CLI doesn't support reactions yet)
-	senderName := payload.Payload.NotifyName
-	if senderName == "" && payload.Payload.Data != nil {
+	// In groups the chat is the group and the participant is the person who reacted
+	reactorID := payload.Payload.From
+	if strings.HasSuffix(reactorID, "@g.us") && payload.Payload.Participant != "" {
+		reactorID = payload.Payload.Participant
+	}
+
+	reactorName := payload.Payload.NotifyName
+	if reactorName == "" && payload.Payload.Data != nil {
 		if payload.Payload.Data.NotifyName != "" {
-			senderName = payload.Payload.Data.NotifyName
+			reactorName = payload.Payload.Data.NotifyName
 		} else if payload.Payload.Data.PushName != "" {
-			senderName = payload.Payload.Data.PushName
+			reactorName = payload.Payload.Data.PushName
 		}
 	}
-	if senderName == "" {
-		senderName = payload.Payload.From
-	}
-
-	var reactionText string
-	if payload.Payload.Reaction.Text == "" {
-		reactionText = fmt.Sprintf("%s removed reaction from message", senderName)
-	} else {
-		reactionText = fmt.Sprintf("%s reacted with %s", senderName, payload.Payload.Reaction.Text)
-	}
 
 	// Use the session from the mapping, falling back to the webhook session
 	reactionSessionName := mapping.SessionName
@@ -656,14 +899,19 @@ func (s *Server) handleWhatsAppReaction(ctx context.Context, payload *models.Wha
 		reactionSessionName = webhookSessionName
 	}
 
-	// Use the message service to send via the bridge with session context
-	err = s.msgService.SendSignalNotification(ctx, reactionSessionName, reactionText)
+	// WAHA reports a removed reaction as one with empty text
+	err = s.msgService.HandleWhatsAppReaction(ctx, reactionSessionName, service.WhatsAppReaction{
+		ReactorID:   reactorID,
+		ReactorName: reactorName,
+		Emoji:       strings.TrimSpace(payload.Payload.Reaction.Text),
+		FromMe:      strings.HasPrefix(payload.Payload.Reaction.MessageID, "true_"),
+	}, mapping)
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to forward reaction to Signal")
+		s.logger.WithContext(ctx).WithError(err).Error("Failed to forward reaction to Signal")
 		return err
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"whatsappMessageId": service.SanitizeWhatsAppMessageID(payload.Payload.Reaction.MessageID),
 		"signalMessageId":   mapping.ID,
 		"emoji":             payload.Payload.Reaction.Text,
@@ -689,7 +937,7 @@ func (s *Server) handleWhatsAppEditedMessage(ctx context.Context, payload *model
 		return nil
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"from":            service.SanitizePhoneNumber(payload.Payload.From),
 		"editedMessageId": service.SanitizeWhatsAppMessageID(*payload.Payload.EditedMessageID),
 		"newBody":         service.SanitizeContent(payload.Payload.Body),
@@ -698,15 +946,28 @@ func (s *Server) handleWhatsAppEditedMessage(ctx context.Context, payload *model
 	// Find the original message mapping
 	mapping, err := s.msgService.GetMessageMappingByWhatsAppID(ctx, *payload.Payload.EditedMessageID)
 	if err != nil {
-		s.logger.WithError(err).Warn("Could not find original message for edit")
+		s.logger.WithContext(ctx).WithError(err).Warn("Could not find original message for edit")
 		return nil
 	}
 
 	if mapping == nil {
-		s.logger.WithField("messageId", service.SanitizeWhatsAppMessageID(*payload.Payload.EditedMessageID)).Warn("No mapping found for edited message")
+		s.logger.WithContext(ctx).WithField("messageId", service.SanitizeWhatsAppMessageID(*payload.Payload.EditedMessageID)).Warn("No mapping found for edited message")
 		return nil
 	}
 
+	// Record the edit before relaying so the history is kept even if Signal is unreachable
+	if s.cfg.WhatsApp.EditHistory && s.db != nil {
+		edit := &models.MessageEdit{
+			WhatsAppMsgID: *payload.Payload.EditedMessageID,
+			SessionName:   webhookSessionName,
+			Body:          payload.Payload.Body,
+			EditedAt:      time.Now(),
+		}
+		if err := s.db.SaveMessageEdit(ctx, edit); err != nil {
+			s.logger.WithContext(ctx).WithError(err).Warn("Failed to record message edit history")
+		}
+	}
+
 	// For now, send an edit notification to Signal as a new message
 	editNotification := fmt.Sprintf("✏️ Message edited: %s", payload.Payload.Body)
 
@@ -719,18 +980,113 @@ func (s *Server) handleWhatsAppEditedMessage(ctx context.Context, payload *model
 	// Use the message service to send via the bridge with session context
 	err = s.msgService.SendSignalNotification(ctx, editSessionName, editNotification)
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to send edit notification to Signal")
+		s.logger.WithContext(ctx).WithError(err).Error("Failed to send edit notification to Signal")
+		return err
+	}
+
+	s.logger.WithContext(ctx).Info("Successfully forwarded message edit notification to Signal")
+	return nil
+}
+
+// handleWhatsAppPin relays a pinned message notice to Signal when whatsapp.relayPins is set.
+// Unpins are not relayed.
+func (s *Server) handleWhatsAppPin(ctx context.Context, payload *models.WhatsAppWebhookPayload, pin models.WhatsAppPin) error {
+	if !s.cfg.WhatsApp.RelayPins || pin.Unpin {
+		s.logger.WithContext(ctx).WithField("unpin", pin.Unpin).Debug("Ignoring WhatsApp pin event")
+		return nil
+	}
+
+	sessionName, sessionErr, skip := s.validateWebhookSession(payload, "pin")
+	if sessionErr != nil {
+		return sessionErr
+	}
+	if skip {
+		return nil
+	}
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"chatId":    service.SanitizePhoneNumber(pin.ChatID),
+		"messageId": service.SanitizeWhatsAppMessageID(pin.MessageID),
+	}).Info("Processing WhatsApp pin for forwarding to Signal")
+
+	if err := s.msgService.HandleWhatsAppPin(ctx, sessionName, pin); err != nil {
+		s.logger.WithContext(ctx).WithError(err).Error("Failed to forward pin to Signal")
+		return err
+	}
+	return nil
+}
+
+// handleWhatsAppLiveLocation relays a live location update to Signal when
+// whatsapp.liveLocationIntervalSec is set; the bridge coalesces updates within the interval.
+func (s *Server) handleWhatsAppLiveLocation(ctx context.Context, payload *models.WhatsAppWebhookPayload, loc models.WhatsAppLiveLocation) error {
+	if s.cfg.WhatsApp.LiveLocationIntervalSec == 0 || strings.Contains(payload.Payload.From, "status@broadcast") {
+		s.logger.WithContext(ctx).Debug("Ignoring WhatsApp live location update")
+		return nil
+	}
+
+	sessionName, sessionErr, skip := s.validateWebhookSession(payload, "live location")
+	if sessionErr != nil {
+		return sessionErr
+	}
+	if skip {
+		return nil
+	}
+
+	// For group chats the participant shared the location
+	loc.ChatID = payload.Payload.From
+	loc.Sender = payload.Payload.From
+	if strings.HasSuffix(loc.ChatID, "@g.us") && payload.Payload.Participant != "" {
+		loc.Sender = payload.Payload.Participant
+	}
+	loc.SenderName = payload.Payload.NotifyName
+	if loc.SenderName == "" && payload.Payload.Data != nil {
+		loc.SenderName = payload.Payload.Data.NotifyName
+		if loc.SenderName == "" {
+			loc.SenderName = payload.Payload.Data.PushName
+		}
+	}
+
+	if err := s.msgService.HandleWhatsAppLiveLocation(ctx, sessionName, loc); err != nil {
+		s.logger.WithContext(ctx).WithError(err).Error("Failed to forward live location to Signal")
 		return err
 	}
+	return nil
+}
+
+// handleWhatsAppGroupParticipants relays members joining or leaving a group. The bridge decides
+// whether the session's channel relays them; promotions and demotions are ignored.
+func (s *Server) handleWhatsAppGroupParticipants(ctx context.Context, payload *models.WhatsAppWebhookPayload, body []byte) error {
+	change, ok := models.ParseGroupParticipantChange(body)
+	if !ok {
+		s.logger.WithContext(ctx).Debug("Ignoring WhatsApp group participant event without a membership change")
+		return nil
+	}
+
+	sessionName, sessionErr, skip := s.validateWebhookSession(payload, "group participants")
+	if sessionErr != nil {
+		return sessionErr
+	}
+	if skip {
+		return nil
+	}
 
-	s.logger.Info("Successfully forwarded message edit notification to Signal")
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"chatId":       service.SanitizePhoneNumber(change.GroupID),
+		"action":       change.Action,
+		"participants": len(change.Participants),
+	}).Info("Processing WhatsApp group participant change")
+
+	if err := s.msgService.HandleWhatsAppGroupParticipants(ctx, sessionName, change); err != nil {
+		s.logger.WithContext(ctx).WithError(err).Error("Failed to forward group participant change to Signal")
+		return err
+	}
 	return nil
 }
 
 func (s *Server) handleWhatsAppACK(ctx context.Context, payload *models.WhatsAppWebhookPayload) error {
 	ackStatus, hasACKStatus := ackStatusFromPayload(payload)
 	if !hasACKStatus {
-		s.logger.WithFields(logrus.Fields{
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"messageId": service.SanitizeWhatsAppMessageID(payload.Payload.ID),
 			"from":      service.SanitizePhoneNumber(payload.Payload.From),
 			"to":        service.SanitizePhoneNumber(payload.Payload.To),
@@ -770,52 +1126,40 @@ func (s *Server) handleWhatsAppACK(ctx context.Context, payload *models.WhatsApp
 
 	switch ackStatus {
 	case models.ACKError:
-		s.logger.WithFields(ackLogFields).Error("WhatsApp message delivery failed (ACK error)")
+		s.logger.WithContext(ctx).WithFields(ackLogFields).Error("WhatsApp message delivery failed (ACK error)")
 	case models.ACKPending:
-		s.logger.WithFields(ackLogFields).Info("WhatsApp ACK status update")
+		s.logger.WithContext(ctx).WithFields(ackLogFields).Info("WhatsApp ACK status update")
 	default:
-		s.logger.WithFields(ackLogFields).Info("WhatsApp ACK status update")
+		s.logger.WithContext(ctx).WithFields(ackLogFields).Info("WhatsApp ACK status update")
 	}
 
 	// Update delivery status in database if we have a mapping
 	mapping, err := s.msgService.GetMessageMappingByWhatsAppID(ctx, payload.Payload.ID)
 	if err != nil {
-		s.logger.WithError(err).WithFields(ackLogFields).Warn("Failed to look up message mapping for ACK")
+		s.logger.WithContext(ctx).WithError(err).WithFields(ackLogFields).Warn("Failed to look up message mapping for ACK")
 		metrics.IncrementCounter("whatsapp_ack_total", map[string]string{
 			"result": "lookup_error",
 		}, "WhatsApp ACK processing outcomes")
 		return nil
 	}
 	if mapping == nil {
-		s.logger.WithFields(ackLogFields).Debug("No mapping found for ACK message ID (untracked message)")
+		s.logger.WithContext(ctx).WithFields(ackLogFields).Debug("No mapping found for ACK message ID (untracked message)")
 		metrics.IncrementCounter("whatsapp_ack_total", map[string]string{
 			"result": "no_mapping",
 		}, "WhatsApp ACK processing outcomes")
 		return nil
 	}
 
-	// Map WhatsApp ACK to our delivery status
-	var deliveryStatus string
-	switch ackStatus {
-	case models.ACKError:
-		deliveryStatus = "failed"
-	case models.ACKPending, models.ACKServer:
-		deliveryStatus = "sent"
-	case models.ACKDevice:
-		deliveryStatus = "delivered"
-	case models.ACKRead, models.ACKPlayed:
-		deliveryStatus = "read"
-	}
-
-	if deliveryStatus != "" {
+	if status, ok := deliveryStatusForACK(ackStatus); ok {
+		deliveryStatus := string(status)
 		err = s.msgService.UpdateDeliveryStatus(ctx, payload.Payload.ID, deliveryStatus)
 		if err != nil {
-			s.logger.WithError(err).WithFields(ackLogFields).Warn("Failed to update delivery status")
+			s.logger.WithContext(ctx).WithError(err).WithFields(ackLogFields).Warn("Failed to update delivery status")
 			metrics.IncrementCounter("whatsapp_ack_total", map[string]string{
 				"result": "update_error",
 			}, "WhatsApp ACK processing outcomes")
 		} else {
-			s.logger.WithFields(logrus.Fields{
+			s.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"messageId":      service.SanitizeWhatsAppMessageID(payload.Payload.ID),
 				"deliveryStatus": deliveryStatus,
 				"ack":            ackStatus,
@@ -829,6 +1173,24 @@ func (s *Server) handleWhatsAppACK(ctx context.Context, payload *models.WhatsApp
 	return nil
 }
 
+// deliveryStatusForACK maps a WAHA ACK level to the delivery status it records. Read and played
+// are both recorded as read, distinct from delivered; the message service never moves a
+// mapping back, so a late device ACK does not undo a read.
+func deliveryStatusForACK(ack int) (models.DeliveryStatus, bool) {
+	switch ack {
+	case models.ACKError:
+		return models.DeliveryStatusFailed, true
+	case models.ACKPending, models.ACKServer:
+		return models.DeliveryStatusSent, true
+	case models.ACKDevice:
+		return models.DeliveryStatusDelivered, true
+	case models.ACKRead, models.ACKPlayed:
+		return models.DeliveryStatusRead, true
+	default:
+		return "", false
+	}
+}
+
 func ackStatusFromPayload(payload *models.WhatsAppWebhookPayload) (int, bool) {
 	if payload.Payload.ACK != nil {
 		return *payload.Payload.ACK, true
@@ -853,7 +1215,7 @@ func ackStatusFromPayload(payload *models.WhatsAppWebhookPayload) (int, bool) {
 }
 
 func (s *Server) handleWhatsAppWaitingMessage(ctx context.Context, payload *models.WhatsAppWebhookPayload) error {
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"from":      service.SanitizePhoneNumber(payload.Payload.From),
 		"messageId": service.SanitizeWhatsAppMessageID(payload.Payload.ID),
 	}).Info("Processing WhatsApp waiting message event")
@@ -868,9 +1230,9 @@ func (s *Server) handleWhatsAppWaitingMessage(ctx context.Context, payload *mode
 	}
 
 	waitingNotification := "⏳ WhatsApp is waiting for a message"
-	err := s.msgService.SendSignalNotification(ctx, sessionName, waitingNotification)
+	err := s.msgService.SendSystemNotification(ctx, sessionName, waitingNotification)
 	if err != nil {
-		s.logger.WithError(err).Warn("Failed to send waiting notification to Signal")
+		s.logger.WithContext(ctx).WithError(err).Warn("Failed to send waiting notification to Signal")
 		return err
 	}
 
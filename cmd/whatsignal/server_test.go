@@ -13,13 +13,18 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"whatsignal/internal/constants"
+	"whatsignal/internal/metrics"
+	"whatsignal/internal/middleware"
 	"whatsignal/internal/models"
 	"whatsignal/internal/service"
+	"whatsignal/internal/tracing"
+	"whatsignal/pkg/media"
 	signaltypes "whatsignal/pkg/signal/types"
 	"whatsignal/pkg/whatsapp/types"
 
@@ -39,6 +44,45 @@ func (m *mockDatabase) HealthCheck(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *mockDatabase) SaveGroup(ctx context.Context, group *models.Group) error {
+	args := m.Called(ctx, group)
+	return args.Error(0)
+}
+
+func (m *mockDatabase) GetGroup(ctx context.Context, groupID, sessionName string) (*models.Group, error) {
+	args := m.Called(ctx, groupID, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *mockDatabase) CleanupOldGroups(ctx context.Context, retentionDays int) error {
+	args := m.Called(ctx, retentionDays)
+	return args.Error(0)
+}
+
+func (m *mockDatabase) SaveMessageEdit(ctx context.Context, edit *models.MessageEdit) error {
+	args := m.Called(ctx, edit)
+	return args.Error(0)
+}
+
+func (m *mockDatabase) GetMessageEdits(ctx context.Context, whatsappMsgID string) ([]models.MessageEdit, error) {
+	args := m.Called(ctx, whatsappMsgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MessageEdit), args.Error(1)
+}
+
+func (m *mockDatabase) GetMessageMapping(ctx context.Context, id string) (*models.MessageMapping, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MessageMapping), args.Error(1)
+}
+
 // For tests, we'll use nil for signal client since the code has nil checks
 
 // Helper function to create a test channel manager
@@ -102,6 +146,14 @@ func (m *mockWAClient) SendTextWithSession(ctx context.Context, chatID, message,
 	return args.Get(0).(*types.SendMessageResponse), args.Error(1)
 }
 
+func (m *mockWAClient) SendMentionsWithSession(ctx context.Context, chatID, message string, mentions []string, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	args := m.Called(ctx, chatID, message, mentions, replyTo, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.SendMessageResponse), args.Error(1)
+}
+
 func (m *mockWAClient) SendImageWithSession(ctx context.Context, chatID, imagePath, caption, replyTo, sessionName string) (*types.SendMessageResponse, error) {
 	args := m.Called(ctx, chatID, imagePath, caption, replyTo, sessionName)
 	if args.Get(0) == nil {
@@ -134,8 +186,8 @@ func (m *mockWAClient) SendVoiceWithSession(ctx context.Context, chatID, voicePa
 	return args.Get(0).(*types.SendMessageResponse), args.Error(1)
 }
 
-func (m *mockWAClient) CreateSession(ctx context.Context) error {
-	args := m.Called(ctx)
+func (m *mockWAClient) CreateSession(ctx context.Context, config *types.SessionConfig) error {
+	args := m.Called(ctx, config)
 	return args.Error(0)
 }
 
@@ -209,6 +261,14 @@ func (m *mockWAClient) GetGroup(ctx context.Context, groupID string) (*types.Gro
 	return args.Get(0).(*types.Group), args.Error(1)
 }
 
+func (m *mockWAClient) GetGroupWithSession(ctx context.Context, groupID, sessionName string) (*types.Group, error) {
+	args := m.Called(ctx, groupID, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Group), args.Error(1)
+}
+
 func (m *mockWAClient) GetAllGroups(ctx context.Context, limit, offset int) ([]types.Group, error) {
 	args := m.Called(ctx, limit, offset)
 	if args.Get(0) == nil {
@@ -217,6 +277,30 @@ func (m *mockWAClient) GetAllGroups(ctx context.Context, limit, offset int) ([]t
 	return args.Get(0).([]types.Group), args.Error(1)
 }
 
+func (m *mockWAClient) GetChatLabels(ctx context.Context, chatID string) ([]types.Label, error) {
+	args := m.Called(ctx, chatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.Label), args.Error(1)
+}
+
+func (m *mockWAClient) GetChatMessages(ctx context.Context, chatID string, limit int, sessionName string) ([]types.ChatMessage, error) {
+	args := m.Called(ctx, chatID, limit, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.ChatMessage), args.Error(1)
+}
+
+func (m *mockWAClient) GetMe(ctx context.Context, sessionName string) (*types.Me, error) {
+	args := m.Called(ctx, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Me), args.Error(1)
+}
+
 func (m *mockWAClient) SendReactionWithSession(ctx context.Context, chatID, messageID, reaction, sessionName string) (*types.SendMessageResponse, error) {
 	args := m.Called(ctx, chatID, messageID, reaction, sessionName)
 	if args.Get(0) == nil {
@@ -230,6 +314,16 @@ func (m *mockWAClient) DeleteMessage(ctx context.Context, chatID, messageID stri
 	return args.Error(0)
 }
 
+func (m *mockWAClient) EditMessageWithSession(ctx context.Context, chatID, messageID, newText, sessionName string) error {
+	args := m.Called(ctx, chatID, messageID, newText, sessionName)
+	return args.Error(0)
+}
+
+func (m *mockWAClient) StarMessageWithSession(ctx context.Context, chatID, messageID string, star bool, sessionName string) error {
+	args := m.Called(ctx, chatID, messageID, star, sessionName)
+	return args.Error(0)
+}
+
 func (m *mockWAClient) GetSessionName() string {
 	return "test-session"
 }
@@ -239,11 +333,25 @@ func (m *mockWAClient) HealthCheck(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *mockWAClient) GetCapabilities(ctx context.Context) types.Capabilities {
+	return types.Capabilities{}
+}
+
 func (m *mockWAClient) AckMessage(ctx context.Context, chatID, sessionName string) error {
 	args := m.Called(ctx, chatID, sessionName)
 	return args.Error(0)
 }
 
+func (m *mockWAClient) StartTyping(ctx context.Context, chatID, sessionName string) error {
+	args := m.Called(ctx, chatID, sessionName)
+	return args.Error(0)
+}
+
+func (m *mockWAClient) StopTyping(ctx context.Context, chatID, sessionName string) error {
+	args := m.Called(ctx, chatID, sessionName)
+	return args.Error(0)
+}
+
 func (m *mockMessageService) GetMessageThread(ctx context.Context, threadID string) ([]*models.Message, error) {
 	args := m.Called(ctx, threadID)
 	if args.Get(0) == nil {
@@ -302,6 +410,31 @@ func (m *mockMessageService) SendSignalNotification(ctx context.Context, session
 	return args.Error(0)
 }
 
+func (m *mockMessageService) SendSystemNotification(ctx context.Context, sessionName, message string) error {
+	args := m.Called(ctx, sessionName, message)
+	return args.Error(0)
+}
+
+func (m *mockMessageService) HandleWhatsAppReaction(ctx context.Context, sessionName string, reaction service.WhatsAppReaction, mapping *models.MessageMapping) error {
+	args := m.Called(ctx, sessionName, reaction, mapping)
+	return args.Error(0)
+}
+
+func (m *mockMessageService) HandleWhatsAppPin(ctx context.Context, sessionName string, pin models.WhatsAppPin) error {
+	args := m.Called(ctx, sessionName, pin)
+	return args.Error(0)
+}
+
+func (m *mockMessageService) HandleWhatsAppLiveLocation(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation) error {
+	args := m.Called(ctx, sessionName, loc)
+	return args.Error(0)
+}
+
+func (m *mockMessageService) HandleWhatsAppGroupParticipants(ctx context.Context, sessionName string, change models.WhatsAppGroupParticipants) error {
+	args := m.Called(ctx, sessionName, change)
+	return args.Error(0)
+}
+
 func (m *mockMessageService) GetMessageMappingByWhatsAppID(ctx context.Context, whatsappID string) (*models.MessageMapping, error) {
 	args := m.Called(ctx, whatsappID)
 	if args.Get(0) == nil {
@@ -550,7 +683,6 @@ func TestServer_Health(t *testing.T) {
 
 	// Setup mock expectations for health checks
 	mockDB.On("HealthCheck", mock.Anything).Return(nil)
-	mockWAClient.On("HealthCheck", mock.Anything).Return(nil)
 
 	server := NewServer(cfg, msgService, logger, mockWAClient, channelManager, mockDB, nil)
 
@@ -563,10 +695,11 @@ func TestServer_Health(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	mockDB.AssertExpectations(t)
-	mockWAClient.AssertExpectations(t)
+	// Liveness must not depend on WAHA being reachable
+	mockWAClient.AssertNotCalled(t, "HealthCheck", mock.Anything)
 }
 
-func TestServer_ReadinessReturnsUnavailableWhenDegraded(t *testing.T) {
+func TestServer_HealthReturnsUnavailableWhenDatabaseFails(t *testing.T) {
 	msgService := &mockMessageService{}
 	logger := logrus.New()
 	cfg := &models.Config{}
@@ -574,25 +707,18 @@ func TestServer_ReadinessReturnsUnavailableWhenDegraded(t *testing.T) {
 	channelManager := createTestChannelManager()
 	mockDB := &mockDatabase{}
 
-	mockDB.On("HealthCheck", mock.Anything).Return(nil).Twice()
-	mockWAClient.On("HealthCheck", mock.Anything).Return(errors.New("whatsapp unavailable")).Twice()
+	mockDB.On("HealthCheck", mock.Anything).Return(errors.New("database locked"))
 
 	server := NewServer(cfg, msgService, logger, mockWAClient, channelManager, mockDB, nil)
 
-	for _, path := range []string{"/health", "/readyz"} {
-		t.Run(path, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodGet, path, nil)
-			w := httptest.NewRecorder()
-
-			server.router.ServeHTTP(w, req)
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
 
-			resp := w.Result()
-			require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
-		})
-	}
+	server.router.ServeHTTP(w, req)
 
+	resp := w.Result()
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
 	mockDB.AssertExpectations(t)
-	mockWAClient.AssertExpectations(t)
 }
 
 func TestServer_LivenessDoesNotCheckDependencies(t *testing.T) {
@@ -713,6 +839,285 @@ func TestServer_SessionStatus(t *testing.T) {
 	}
 }
 
+func TestServer_SessionStatusPhoneNumber(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", "")
+
+	mockWAClient := &mockWAClient{}
+	mockWAClient.On("GetMe", mock.Anything, "test-session").Return(&types.Me{ID: "15551234567@c.us"}, nil).Once()
+	mockWAClient.On("GetSessionStatus", mock.Anything).Return(&types.Session{
+		Name:   "test-session",
+		Status: "WORKING",
+	}, nil).Once()
+
+	numbers := service.NewSessionNumbers(mockWAClient, logrus.New())
+	require.NoError(t, numbers.Refresh(context.Background(), []string{"test-session"}))
+
+	server := NewServer(&models.Config{}, &mockMessageService{}, logrus.New(), mockWAClient, createTestChannelManager(), &mockDatabase{}, nil)
+	server.SetSessionNumbers(numbers)
+
+	w := httptest.NewRecorder()
+	server.handleSessionStatus()(w, httptest.NewRequest(http.MethodGet, "/session/status", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "15551234567")
+	var responseBody map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&responseBody))
+	assert.Equal(t, "+*******4567", responseBody["phone_number"])
+	mockWAClient.AssertExpectations(t)
+}
+
+type stubMediaCache media.CacheStats
+
+func (c stubMediaCache) CacheStats() (media.CacheStats, error) { return media.CacheStats(c), nil }
+
+func TestServer_MediaCacheStats(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", "")
+
+	mockWAClient := &mockWAClient{}
+	mockWAClient.On("GetSessionStatus", mock.Anything).Return(&types.Session{Name: "test-session", Status: "WORKING"}, nil).Once()
+
+	server := NewServer(&models.Config{}, &mockMessageService{}, logrus.New(), mockWAClient, createTestChannelManager(), &mockDatabase{}, nil)
+	server.SetMediaCache(stubMediaCache{Files: 3, SizeBytes: 4096, OldestFileAge: 90 * time.Minute, Hits: 6, Misses: 2, HitRate: 0.75})
+
+	w := httptest.NewRecorder()
+	server.handleSessionStatus()(w, httptest.NewRequest(http.MethodGet, "/session/status", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var status map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+	assert.Equal(t, map[string]interface{}{
+		"files":               float64(3),
+		"size_bytes":          float64(4096),
+		"oldest_file_age_sec": float64(5400),
+		"hits":                float64(6),
+		"misses":              float64(2),
+		"hit_rate":            0.75,
+	}, status["media_cache"])
+
+	w = httptest.NewRecorder()
+	server.handleMetrics()(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var snapshot metrics.MetricsSnapshot
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&snapshot))
+	require.Contains(t, snapshot.Gauges, "media_cache_size_bytes")
+	assert.Equal(t, float64(4096), snapshot.Gauges["media_cache_size_bytes"].Value)
+	assert.Equal(t, float64(3), snapshot.Gauges["media_cache_files"].Value)
+	assert.Equal(t, 0.75, snapshot.Gauges["media_cache_hit_rate"].Value)
+}
+
+func TestServer_PrometheusMetrics(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", "")
+
+	cfg := &models.Config{Server: models.ServerConfig{MetricsEnabled: true}}
+	server := NewServer(cfg, &mockMessageService{}, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, metrics.PrometheusContentType, w.Header().Get("Content-Type"))
+		return w.Body.String()
+	}
+
+	labels := map[string]string{"direction": "whatsapp_to_signal", "session": "prometheus-test"}
+	metrics.IncrementCounter("message_processing_success", labels, "Successful message processing operations")
+	assert.Contains(t, scrape(), `whatsignal_message_processing_success{direction="whatsapp_to_signal",session="prometheus-test"} 1`+"\n")
+
+	metrics.IncrementCounter("message_processing_success", labels, "Successful message processing operations")
+	assert.Contains(t, scrape(), `whatsignal_message_processing_success{direction="whatsapp_to_signal",session="prometheus-test"} 2`+"\n")
+
+	// The JSON report stays available
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics?format=json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var snapshot metrics.MetricsSnapshot
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&snapshot))
+	assert.NotEmpty(t, snapshot.Counters)
+
+	// Without server.metricsEnabled /metrics answers JSON as before
+	cfg.Server.MetricsEnabled = false
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
+
+func TestServer_GroupRefresh(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", "")
+
+	const groupID = "120363028123456789@g.us"
+
+	newGroupRefreshServer := func(waClient *mockWAClient, db *mockDatabase, channelManager *service.ChannelManager) *Server {
+		server := NewServer(&models.Config{}, &mockMessageService{}, logrus.New(), waClient, channelManager, db, nil)
+		server.SetGroupService(service.NewGroupService(db, waClient))
+		return server
+	}
+
+	t.Run("refresh updates cached subject", func(t *testing.T) {
+		mockWAClient := &mockWAClient{}
+		mockDB := &mockDatabase{}
+		server := newGroupRefreshServer(mockWAClient, mockDB, createTestChannelManager())
+
+		mockWAClient.On("GetGroupWithSession", mock.Anything, groupID, "default").Return(&types.Group{
+			ID:      types.WAHAGroupID(groupID),
+			Subject: "Renamed Group",
+			Participants: []types.GroupParticipant{
+				{ID: "111@c.us"}, {ID: "222@c.us"}, {ID: "333@c.us"},
+			},
+		}, nil).Once()
+		mockDB.On("SaveGroup", mock.Anything, mock.MatchedBy(func(g *models.Group) bool {
+			return g.GroupID == groupID && g.Subject == "Renamed Group" && g.SessionName == "default" && g.ParticipantCount == 3
+		})).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/sessions/default/groups/"+groupID+"/refresh", nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "Renamed Group", body["subject"])
+		assert.Equal(t, float64(3), body["participant_count"])
+		assert.Equal(t, groupID, body["group_id"])
+
+		mockWAClient.AssertExpectations(t)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("refresh uses the session in the path", func(t *testing.T) {
+		mockWAClient := &mockWAClient{}
+		mockDB := &mockDatabase{}
+		channelManager, err := service.NewChannelManager([]models.Channel{
+			{WhatsAppSessionName: "default", SignalDestinationPhoneNumber: "+1234567890"},
+			{WhatsAppSessionName: "business", SignalDestinationPhoneNumber: "+1234567891"},
+		})
+		require.NoError(t, err)
+		server := newGroupRefreshServer(mockWAClient, mockDB, channelManager)
+
+		mockWAClient.On("GetGroupWithSession", mock.Anything, groupID, "business").Return(&types.Group{
+			ID:      types.WAHAGroupID(groupID),
+			Subject: "Suppliers",
+		}, nil).Once()
+		mockDB.On("SaveGroup", mock.Anything, mock.MatchedBy(func(g *models.Group) bool {
+			return g.GroupID == groupID && g.SessionName == "business"
+		})).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/sessions/business/groups/"+groupID+"/refresh", nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "business", body["session"])
+		mockWAClient.AssertExpectations(t)
+		mockWAClient.AssertNotCalled(t, "GetGroup", mock.Anything, mock.Anything)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("unknown group returns 404", func(t *testing.T) {
+		mockWAClient := &mockWAClient{}
+		mockDB := &mockDatabase{}
+		server := newGroupRefreshServer(mockWAClient, mockDB, createTestChannelManager())
+
+		mockWAClient.On("GetGroupWithSession", mock.Anything, groupID, "default").Return(nil, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/sessions/default/groups/"+groupID+"/refresh", nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockDB.AssertNotCalled(t, "SaveGroup", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown session returns 404", func(t *testing.T) {
+		mockWAClient := &mockWAClient{}
+		server := newGroupRefreshServer(mockWAClient, &mockDatabase{}, createTestChannelManager())
+
+		req := httptest.NewRequest(http.MethodPost, "/sessions/other/groups/"+groupID+"/refresh", nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockWAClient.AssertNotCalled(t, "GetGroupWithSession", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServer_MessageEdits(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", "")
+
+	cfg := &models.Config{WhatsApp: models.WhatsAppConfig{EditHistory: true}}
+
+	t.Run("returns edits in order", func(t *testing.T) {
+		mockDB := &mockDatabase{}
+		server := NewServer(cfg, &mockMessageService{}, logrus.New(), &mockWAClient{}, createTestChannelManager(), mockDB, nil)
+
+		editedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		mockDB.On("GetMessageEdits", mock.Anything, "wamid.edited").Return([]models.MessageEdit{
+			{ID: 1, WhatsAppMsgID: "wamid.edited", SessionName: "default", Body: "first fix", EditedAt: editedAt},
+			{ID: 2, WhatsAppMsgID: "wamid.edited", SessionName: "default", Body: "final text", EditedAt: editedAt.Add(time.Minute)},
+		}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/edits/wamid.edited", nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			MessageID string               `json:"message_id"`
+			Edits     []models.MessageEdit `json:"edits"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "wamid.edited", body.MessageID)
+		require.Len(t, body.Edits, 2)
+		assert.Equal(t, "first fix", body.Edits[0].Body)
+		assert.Equal(t, "final text", body.Edits[1].Body)
+		assert.True(t, editedAt.Add(time.Minute).Equal(body.Edits[1].EditedAt))
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("disabled returns 404", func(t *testing.T) {
+		mockDB := &mockDatabase{}
+		server := NewServer(&models.Config{}, &mockMessageService{}, logrus.New(), &mockWAClient{}, createTestChannelManager(), mockDB, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/edits/wamid.edited", nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockDB.AssertNotCalled(t, "GetMessageEdits", mock.Anything, mock.Anything)
+	})
+
+	t.Run("edit webhook records history", func(t *testing.T) {
+		mockDB := &mockDatabase{}
+		msgService := &mockMessageService{}
+		server := NewServer(cfg, msgService, logrus.New(), &mockWAClient{}, createTestChannelManager(), mockDB, nil)
+
+		msgService.On("GetMessageMappingByWhatsAppID", mock.Anything, "wamid.edited").Return(&models.MessageMapping{
+			WhatsAppMsgID:  "wamid.edited",
+			WhatsAppChatID: "+0987654321@c.us",
+			SessionName:    "default",
+		}, nil).Once()
+		msgService.On("SendSignalNotification", mock.Anything, "default", "✏️ Message edited: final text").Return(nil).Once()
+		mockDB.On("SaveMessageEdit", mock.Anything, mock.MatchedBy(func(edit *models.MessageEdit) bool {
+			return edit.WhatsAppMsgID == "wamid.edited" && edit.Body == "final text" && edit.SessionName == "default" && !edit.EditedAt.IsZero()
+		})).Return(nil).Once()
+
+		editedID := "wamid.edited"
+		payload := &models.WhatsAppWebhookPayload{Event: models.EventMessageEdited, Session: "default"}
+		payload.Payload.From = "+0987654321"
+		payload.Payload.Body = "final text"
+		payload.Payload.EditedMessageID = &editedID
+
+		require.NoError(t, server.handleWhatsAppEditedMessage(context.Background(), payload))
+		mockDB.AssertExpectations(t)
+		msgService.AssertExpectations(t)
+	})
+}
+
 func TestServer_ProductionDiagnosticsRequireAdminToken(t *testing.T) {
 	t.Setenv("WHATSIGNAL_ENV", "production")
 	t.Setenv("WHATSIGNAL_ADMIN_TOKEN", "admin-token-with-enough-entropy")
@@ -1178,6 +1583,88 @@ func TestWebhookProcessingDetachedContext(t *testing.T) {
 	})
 }
 
+func TestWebhookRequestIDPropagation(t *testing.T) {
+	// A webhook's request ID must be the same in the ingress log, the bridge's logs and the
+	// X-Request-ID header of the calls it makes to WAHA or signal-cli
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetLevel(logrus.DebugLevel)
+	logger.AddHook(tracing.NewRequestIDHook())
+
+	var outboundRequestID string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outboundRequestID = r.Header.Get(tracing.RequestIDHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+	upstreamClient := &http.Client{Transport: tracing.NewRequestIDTransport(nil)}
+
+	msgService := &mockMessageService{}
+	cfg := &models.Config{
+		WhatsApp: models.WhatsAppConfig{
+			WebhookSecret: "test-secret",
+		},
+	}
+	server := NewServer(cfg, msgService, logger, &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+	var bridgeRequestID string
+	msgService.On("HandleWhatsAppMessageWithSession",
+		mock.Anything, "default", "+1234567890", "msg123", "+1234567890", "", "Test message", "",
+	).Run(func(args mock.Arguments) {
+		// Simulate the bridge logging and then calling an upstream API
+		ctx := args.Get(0).(context.Context)
+		bridgeRequestID = tracing.GetRequestID(ctx)
+		logger.WithContext(ctx).Info("bridge stage")
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, upstream.URL, nil)
+		require.NoError(t, err)
+		resp, err := upstreamClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}).Return(nil).Once()
+
+	payload := map[string]interface{}{
+		"event":   "message",
+		"session": "default",
+		"payload": map[string]interface{}{
+			"id":       "msg123",
+			"from":     "+1234567890",
+			"fromMe":   false,
+			"body":     "Test message",
+			"hasMedia": false,
+		},
+	}
+	bodyBytes, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", "/webhook/whatsapp", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("X-Webhook-Hmac", signWahaTestPayload(cfg.WhatsApp.WebhookSecret, bodyBytes))
+	req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	middleware.WebhookObservabilityMiddleware(logger, "whatsapp")(server.handleWhatsAppWebhook()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	msgService.AssertExpectations(t)
+
+	require.NotEmpty(t, bridgeRequestID)
+	assert.Equal(t, bridgeRequestID, outboundRequestID)
+
+	var stagesLogged []string
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if id, ok := entry[service.LogFieldRequestID]; ok {
+			assert.Equal(t, bridgeRequestID, id, "log line %q", entry["msg"])
+			stagesLogged = append(stagesLogged, entry["msg"].(string))
+		}
+	}
+	assert.Contains(t, stagesLogged, "bridge stage")
+	assert.GreaterOrEqual(t, len(stagesLogged), 2, "ingress and bridge should both log the request ID")
+}
+
 func TestWhatsAppWebhookRejectsReplay(t *testing.T) {
 	t.Setenv("WHATSIGNAL_ENV", "production")
 
@@ -1238,6 +1725,177 @@ func TestWhatsAppWebhookRejectsReplay(t *testing.T) {
 	msgService.AssertExpectations(t)
 }
 
+func TestWhatsAppWebhook_EnabledEventsFilter(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	reaction := map[string]interface{}{
+		"event":   models.EventMessageReaction,
+		"session": "default",
+		"payload": map[string]interface{}{
+			"id":       "reaction-1",
+			"from":     "+1234567890",
+			"reaction": map[string]interface{}{"text": "👍", "messageId": "wa-1"},
+		},
+	}
+	message := map[string]interface{}{
+		"event":   models.EventMessage,
+		"session": "default",
+		"payload": map[string]interface{}{
+			"id":   "msg-1",
+			"from": "+1234567890",
+			"body": "hello",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		enabledEvents []string
+		payload       map[string]interface{}
+		expectHandled bool
+	}{
+		{"all events processed by default", nil, reaction, true},
+		{"listed event processed", []string{models.EventMessageReaction}, reaction, true},
+		{"unlisted event dropped", []string{models.EventMessageACK}, reaction, false},
+		{"message always processed", []string{models.EventMessageACK}, message, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgService := &mockMessageService{}
+			cfg := &models.Config{
+				WhatsApp: models.WhatsAppConfig{
+					WebhookSecret: "test-secret",
+					EnabledEvents: tt.enabledEvents,
+				},
+			}
+			server := NewServer(cfg, msgService, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+			if tt.expectHandled {
+				if tt.payload["event"] == models.EventMessage {
+					msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+1234567890", "msg-1", "+1234567890", "", "hello", "").Return(nil).Once()
+				} else {
+					msgService.On("GetMessageMappingByWhatsAppID", mock.Anything, "wa-1").Return(nil, nil).Once()
+				}
+			}
+
+			body, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/webhook/whatsapp", bytes.NewReader(body))
+			req.Header.Set(XWahaSignatureHeader, signWahaTestPayload(cfg.WhatsApp.WebhookSecret, body))
+			req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			server.handleWhatsAppWebhook()(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			msgService.AssertExpectations(t)
+			if !tt.expectHandled {
+				msgService.AssertNotCalled(t, "GetMessageMappingByWhatsAppID", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestWhatsAppWebhook_CustomPath(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	msgService := &mockMessageService{}
+	cfg := &models.Config{
+		Server:   models.ServerConfig{WebhookPath: "/hooks/waha"},
+		WhatsApp: models.WhatsAppConfig{WebhookSecret: "test-secret"},
+	}
+	server := NewServer(cfg, msgService, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+	msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+1234567890", "msg-1", "+1234567890", "", "hello", "").Return(nil).Once()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event":   models.EventMessage,
+		"session": "default",
+		"payload": map[string]interface{}{"id": "msg-1", "from": "+1234567890", "body": "hello"},
+	})
+	require.NoError(t, err)
+	post := func(path string) int {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set(XWahaSignatureHeader, signWahaTestPayload(cfg.WhatsApp.WebhookSecret, body))
+		req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, post("/hooks/waha"))
+	assert.Equal(t, http.StatusNotFound, post("/webhook/whatsapp"), "the default path is not served when another is configured")
+	msgService.AssertExpectations(t)
+}
+
+func TestServer_ListenPort(t *testing.T) {
+	server := &Server{cfg: &models.Config{}}
+	t.Setenv("PORT", "")
+	assert.Equal(t, "8082", server.listenPort())
+
+	server.cfg.Server.Port = 9090
+	assert.Equal(t, "9090", server.listenPort())
+
+	t.Setenv("PORT", "7070")
+	assert.Equal(t, "7070", server.listenPort(), "PORT overrides server.port")
+}
+
+func TestWhatsAppWebhook_GroupParticipants(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	tests := []struct {
+		name          string
+		event         string
+		changeType    string
+		expectHandled bool
+	}{
+		{"v2 join", models.EventGroupV2Participants, "join", true},
+		{"leave", models.EventGroupParticipants, "leave", true},
+		{"promotion ignored", models.EventGroupV2Participants, "promote", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgService := &mockMessageService{}
+			cfg := &models.Config{WhatsApp: models.WhatsAppConfig{WebhookSecret: "test-secret"}}
+			server := NewServer(cfg, msgService, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+			if tt.expectHandled {
+				msgService.On("HandleWhatsAppGroupParticipants", mock.Anything, "default", models.WhatsAppGroupParticipants{
+					GroupID:      "456@g.us",
+					Action:       tt.changeType,
+					Participants: []string{"123@c.us"},
+				}).Return(nil).Once()
+			}
+
+			body, err := json.Marshal(map[string]interface{}{
+				"event":   tt.event,
+				"session": "default",
+				"payload": map[string]interface{}{
+					"group":        map[string]interface{}{"id": "456@g.us"},
+					"type":         tt.changeType,
+					"participants": []map[string]interface{}{{"id": "123@c.us", "role": "participant"}},
+				},
+			})
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/webhook/whatsapp", bytes.NewReader(body))
+			req.Header.Set(XWahaSignatureHeader, signWahaTestPayload(cfg.WhatsApp.WebhookSecret, body))
+			req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			server.handleWhatsAppWebhook()(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			msgService.AssertExpectations(t)
+			if !tt.expectHandled {
+				msgService.AssertNotCalled(t, "HandleWhatsAppGroupParticipants", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestServer_SignalWebhook(t *testing.T) {
 	t.Skip("Signal webhook functionality removed - Signal uses polling instead")
 	/*
@@ -1569,7 +2227,7 @@ func TestServer_WhatsAppEventHandlers(t *testing.T) {
 					}, nil).Once()
 
 				// Mock sending reaction notification to Signal
-				msgService.On("SendSignalNotification", mock.Anything, "default", "+0987654321 reacted with 👍").Return(nil).Once()
+				msgService.On("HandleWhatsAppReaction", mock.Anything, "default", service.WhatsAppReaction{ReactorID: "+0987654321", Emoji: "👍"}, mock.AnythingOfType("*models.MessageMapping")).Return(nil).Once()
 			},
 		},
 		{
@@ -1691,7 +2349,7 @@ func TestServer_WhatsAppEventHandlers(t *testing.T) {
 			},
 			setup: func() {
 				// Mock sending waiting notification to Signal
-				msgService.On("SendSignalNotification", mock.Anything, "default", "⏳ WhatsApp is waiting for a message").Return(nil).Once()
+				msgService.On("SendSystemNotification", mock.Anything, "default", "⏳ WhatsApp is waiting for a message").Return(nil).Once()
 			},
 		},
 		{
@@ -1708,7 +2366,7 @@ func TestServer_WhatsAppEventHandlers(t *testing.T) {
 				},
 			},
 			setup: func() {
-				msgService.On("SendSignalNotification", mock.Anything, "default", "⏳ WhatsApp is waiting for a message").Return(nil).Once()
+				msgService.On("SendSystemNotification", mock.Anything, "default", "⏳ WhatsApp is waiting for a message").Return(nil).Once()
 			},
 		},
 		{
@@ -1737,7 +2395,7 @@ func TestServer_WhatsAppEventHandlers(t *testing.T) {
 						SessionName:    "",
 						DeliveryStatus: models.DeliveryStatusSent,
 					}, nil).Once()
-				msgService.On("SendSignalNotification", mock.Anything, "default", "+0987654321 reacted with ❤️").Return(nil).Once()
+				msgService.On("HandleWhatsAppReaction", mock.Anything, "default", service.WhatsAppReaction{ReactorID: "+0987654321", Emoji: "❤️"}, mock.AnythingOfType("*models.MessageMapping")).Return(nil).Once()
 			},
 		},
 		{
@@ -1793,7 +2451,7 @@ func TestServer_WhatsAppEventHandlers(t *testing.T) {
 						SessionName:    "default",
 						DeliveryStatus: models.DeliveryStatusSent,
 					}, nil).Once()
-				msgService.On("SendSignalNotification", mock.Anything, "default", "+0987654321 reacted with 🔥").Return(nil).Once()
+				msgService.On("HandleWhatsAppReaction", mock.Anything, "default", service.WhatsAppReaction{ReactorID: "+0987654321", Emoji: "🔥"}, mock.AnythingOfType("*models.MessageMapping")).Return(nil).Once()
 			},
 		},
 	}
@@ -1842,7 +2500,41 @@ func TestHandleWhatsAppReaction_Direct(t *testing.T) {
 						WhatsAppChatID: "+15551234567@c.us",
 						SessionName:    "default",
 					}, nil).Once()
-				ms.On("SendSignalNotification", mock.Anything, "default", "+15551234567 reacted with 👍").Return(nil).Once()
+				ms.On("HandleWhatsAppReaction", mock.Anything, "default", service.WhatsAppReaction{ReactorID: "+15551234567", Emoji: "👍"}, mock.AnythingOfType("*models.MessageMapping")).Return(nil).Once()
+			},
+		},
+		{
+			name: "group reaction is attributed to the participant",
+			mutate: func(payload *models.WhatsAppWebhookPayload) {
+				payload.Payload.From = "family@g.us"
+				payload.Payload.Participant = "+15559876543@c.us"
+				payload.Payload.NotifyName = "Alice"
+			},
+			setupMocks: func(ms *mockMessageService) {
+				ms.On("GetMessageMappingByWhatsAppID", mock.Anything, "wa-original").
+					Return(&models.MessageMapping{
+						WhatsAppMsgID:  "wa-original",
+						SignalMsgID:    "sig-original",
+						WhatsAppChatID: "family@g.us",
+						SessionName:    "default",
+					}, nil).Once()
+				ms.On("HandleWhatsAppReaction", mock.Anything, "default", service.WhatsAppReaction{ReactorID: "+15559876543@c.us", ReactorName: "Alice", Emoji: "👍"}, mock.AnythingOfType("*models.MessageMapping")).Return(nil).Once()
+			},
+		},
+		{
+			name: "removed reaction is relayed with an empty emoji",
+			mutate: func(payload *models.WhatsAppWebhookPayload) {
+				payload.Payload.Reaction.Text = " "
+			},
+			setupMocks: func(ms *mockMessageService) {
+				ms.On("GetMessageMappingByWhatsAppID", mock.Anything, "wa-original").
+					Return(&models.MessageMapping{
+						WhatsAppMsgID:  "wa-original",
+						SignalMsgID:    "sig-original",
+						WhatsAppChatID: "+15551234567@c.us",
+						SessionName:    "default",
+					}, nil).Once()
+				ms.On("HandleWhatsAppReaction", mock.Anything, "default", service.WhatsAppReaction{ReactorID: "+15551234567", Emoji: ""}, mock.AnythingOfType("*models.MessageMapping")).Return(nil).Once()
 			},
 		},
 		{
@@ -1899,6 +2591,143 @@ func TestHandleWhatsAppReaction_Direct(t *testing.T) {
 	}
 }
 
+func TestHandleWhatsAppMessage_Pin(t *testing.T) {
+	const pinJSON = `{"event": "message", "session": "default", "payload": {"id": "pin-1", "from": "+15551234567@c.us", "_data": {"message": {"pinInChatMessage": {"key": {"id": "ABC"}, "type": %d}}}}}`
+	tests := []struct {
+		name      string
+		relayPins bool
+		pinType   int
+		expectPin bool
+	}{
+		{name: "pin relayed when enabled", relayPins: true, pinType: 1, expectPin: true},
+		{name: "pin ignored by default", pinType: 1},
+		{name: "unpin ignored", relayPins: true, pinType: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgService := &mockMessageService{}
+			logger := logrus.New()
+			logger.SetLevel(logrus.ErrorLevel)
+			cfg := &models.Config{WhatsApp: models.WhatsAppConfig{RelayPins: tt.relayPins}}
+			server := NewServer(cfg, msgService, logger, nil, createTestChannelManager(), nil, nil)
+			if tt.expectPin {
+				msgService.On("HandleWhatsAppPin", mock.Anything, "default", models.WhatsAppPin{ChatID: "+15551234567@c.us", MessageID: "ABC"}).Return(nil).Once()
+			}
+
+			var payload models.WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(pinJSON, tt.pinType)), &payload))
+			require.NoError(t, server.handleWhatsAppMessage(context.Background(), &payload))
+
+			msgService.AssertExpectations(t)
+			msgService.AssertNotCalled(t, "HandleWhatsAppMessageWithSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandleWhatsAppMessage_LiveLocation(t *testing.T) {
+	const liveJSON = `{"event": "message", "session": "default", "payload": {"id": "live-1", "from": "family@g.us", "participant": "+15551234567@c.us", "notifyName": "Alice", "_data": {"message": {"liveLocationMessage": {"degreesLatitude": 52.52, "degreesLongitude": 13.405}}}}}`
+	tests := []struct {
+		name        string
+		intervalSec int
+		expectRelay bool
+	}{
+		{name: "relayed when an interval is set", intervalSec: 60, expectRelay: true},
+		{name: "ignored by default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgService := &mockMessageService{}
+			logger := logrus.New()
+			logger.SetLevel(logrus.ErrorLevel)
+			cfg := &models.Config{WhatsApp: models.WhatsAppConfig{LiveLocationIntervalSec: tt.intervalSec}}
+			server := NewServer(cfg, msgService, logger, nil, createTestChannelManager(), nil, nil)
+			if tt.expectRelay {
+				msgService.On("HandleWhatsAppLiveLocation", mock.Anything, "default", models.WhatsAppLiveLocation{
+					ID:         "live-1",
+					ChatID:     "family@g.us",
+					Sender:     "+15551234567@c.us",
+					SenderName: "Alice",
+					Latitude:   52.52,
+					Longitude:  13.405,
+				}).Return(nil).Once()
+			}
+
+			var payload models.WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(liveJSON), &payload))
+			require.NoError(t, server.handleWhatsAppMessage(context.Background(), &payload))
+
+			msgService.AssertExpectations(t)
+			msgService.AssertNotCalled(t, "HandleWhatsAppMessageWithSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandleWhatsAppMessage_Product(t *testing.T) {
+	const productJSON = `{"event": "message", "session": "default", "payload": {"id": "product-1", "from": "+15551234567@c.us", "body": "", "_data": {"message": {"productMessage": {"product": {"title": "Teapot", "currencyCode": "USD", "priceAmount1000": "45990"}}}}}}`
+
+	msgService := &mockMessageService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	server := NewServer(&models.Config{}, msgService, logger, nil, createTestChannelManager(), nil, nil)
+	msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+15551234567@c.us", "product-1", "+15551234567@c.us", "", "", "").Return(nil).Once()
+
+	var payload models.WhatsAppWebhookPayload
+	require.NoError(t, json.Unmarshal([]byte(productJSON), &payload))
+	require.NoError(t, server.handleWhatsAppMessage(context.Background(), &payload), "a product without a body is not skipped as empty")
+
+	msgService.AssertExpectations(t)
+}
+
+func TestHandleWhatsAppMessage_Payment(t *testing.T) {
+	const paymentJSON = `{"event": "message", "session": "default", "payload": {"id": "payment-1", "from": "+15551234567@c.us", "body": "", "_data": {"type": "payment", "paymentAmount1000": 45990, "paymentCurrency": "USD", "paymentStatus": 4}}}`
+
+	msgService := &mockMessageService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	server := NewServer(&models.Config{}, msgService, logger, nil, createTestChannelManager(), nil, nil)
+	msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+15551234567@c.us", "payment-1", "+15551234567@c.us", "", "", "").Return(nil).Once()
+
+	var payload models.WhatsAppWebhookPayload
+	require.NoError(t, json.Unmarshal([]byte(paymentJSON), &payload))
+	require.NoError(t, server.handleWhatsAppMessage(context.Background(), &payload), "a payment without a body is not skipped as empty")
+
+	msgService.AssertExpectations(t)
+}
+
+func TestHandleWhatsAppMessage_Location(t *testing.T) {
+	const locationJSON = `{"event": "message", "session": "default", "payload": {"id": "location-1", "from": "+15551234567@c.us", "body": "", "_data": {"message": {"locationMessage": {"degreesLatitude": 40.6892, "degreesLongitude": -74.0445, "name": "Statue of Liberty"}}}}}`
+
+	msgService := &mockMessageService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	server := NewServer(&models.Config{}, msgService, logger, nil, createTestChannelManager(), nil, nil)
+	msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+15551234567@c.us", "location-1", "+15551234567@c.us", "", "", "").Return(nil).Once()
+
+	var payload models.WhatsAppWebhookPayload
+	require.NoError(t, json.Unmarshal([]byte(locationJSON), &payload))
+	require.NoError(t, server.handleWhatsAppMessage(context.Background(), &payload), "a location without a body is not skipped as empty")
+
+	msgService.AssertExpectations(t)
+}
+
+func TestHandleWhatsAppMessage_ViewOnce(t *testing.T) {
+	const viewOnceJSON = `{"event": "message", "session": "default", "payload": {"id": "once-1", "from": "+15551234567@c.us", "body": "", "_data": {"message": {"viewOnceMessageV2": {"message": {"imageMessage": {"viewOnce": true}}}}}}}`
+
+	msgService := &mockMessageService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	server := NewServer(&models.Config{}, msgService, logger, nil, createTestChannelManager(), nil, nil)
+	msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+15551234567@c.us", "once-1", "+15551234567@c.us", "", "", "").Return(nil).Once()
+
+	var payload models.WhatsAppWebhookPayload
+	require.NoError(t, json.Unmarshal([]byte(viewOnceJSON), &payload))
+	require.NoError(t, server.handleWhatsAppMessage(context.Background(), &payload), "view-once media WAHA could not download is not skipped as empty")
+
+	msgService.AssertExpectations(t)
+}
+
 func TestHandleWhatsAppWaitingMessage_Direct(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1910,7 +2739,7 @@ func TestHandleWhatsAppWaitingMessage_Direct(t *testing.T) {
 			name:    "valid waiting event sends signal notification",
 			session: "default",
 			setupMocks: func(ms *mockMessageService) {
-				ms.On("SendSignalNotification", mock.Anything, "default", "⏳ WhatsApp is waiting for a message").Return(nil).Once()
+				ms.On("SendSystemNotification", mock.Anything, "default", "⏳ WhatsApp is waiting for a message").Return(nil).Once()
 			},
 		},
 		{
@@ -2768,3 +3597,125 @@ func TestHandleWhatsAppACK(t *testing.T) {
 		})
 	}
 }
+
+func TestDeliveryStatusForACK(t *testing.T) {
+	tests := []struct {
+		ack      int
+		expected models.DeliveryStatus
+	}{
+		{ack: models.ACKError, expected: models.DeliveryStatusFailed},
+		{ack: models.ACKPending, expected: models.DeliveryStatusSent},
+		{ack: models.ACKServer, expected: models.DeliveryStatusSent},
+		{ack: models.ACKDevice, expected: models.DeliveryStatusDelivered},
+		{ack: models.ACKRead, expected: models.DeliveryStatusRead},
+		{ack: models.ACKPlayed, expected: models.DeliveryStatusRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("ack %d", tt.ack), func(t *testing.T) {
+			status, ok := deliveryStatusForACK(tt.ack)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, status)
+		})
+	}
+
+	_, ok := deliveryStatusForACK(7)
+	assert.False(t, ok, "unknown ACK levels leave the status alone")
+}
+
+func TestWhatsAppWebhook_FullWorkQueueSheds(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	msgService := &mockMessageService{}
+	cfg := &models.Config{WhatsApp: models.WhatsAppConfig{WebhookSecret: "test-secret"}}
+	server := NewServer(cfg, msgService, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+	queue := service.NewWorkQueue(1)
+	server.SetWorkQueue(queue)
+
+	msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+1234567890", "msg-1", "+1234567890", "", "hello", "").Return(nil).Once()
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"event":   models.EventMessage,
+		"session": "default",
+		"payload": map[string]interface{}{"id": "msg-1", "from": "+1234567890", "body": "hello"},
+	})
+	require.NoError(t, err)
+	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/whatsapp", bytes.NewReader(payload))
+		req.Header.Set(XWahaSignatureHeader, signWahaTestPayload(cfg.WhatsApp.WebhookSecret, payload))
+		req.Header.Set("X-Webhook-Timestamp", timestamp)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.handleWhatsAppWebhook()(w, req)
+		return w
+	}
+
+	// Another event holds the only slot
+	require.True(t, queue.TryAcquire())
+	w := send()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, strconv.Itoa(constants.QueueFullRetryAfterSec), w.Header().Get("Retry-After"))
+	var resp webhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Retry)
+	msgService.AssertNotCalled(t, "HandleWhatsAppMessageWithSession")
+
+	// WAHA's retry of the same delivery is processed once the queue drains
+	queue.Release()
+	w = send()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, queue.Depth(), "the webhook releases its slot")
+	msgService.AssertExpectations(t)
+}
+
+func TestWhatsAppWebhook_PerChannelSecrets(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	const (
+		globalSecret   = "global-secret"
+		businessSecret = "business-secret"
+		personalSecret = "personal-secret"
+	)
+	channelManager, err := service.NewChannelManager([]models.Channel{
+		{WhatsAppSessionName: "business", SignalDestinationPhoneNumber: "+1111111111", WebhookSecret: businessSecret},
+		{WhatsAppSessionName: "personal", SignalDestinationPhoneNumber: "+2222222222", WebhookSecret: personalSecret},
+		{WhatsAppSessionName: "shared", SignalDestinationPhoneNumber: "+3333333333"},
+	})
+	require.NoError(t, err)
+	cfg := &models.Config{WhatsApp: models.WhatsAppConfig{WebhookSecret: globalSecret}}
+	server := NewServer(cfg, &mockMessageService{}, logrus.New(), &mockWAClient{}, channelManager, &mockDatabase{}, nil)
+
+	tests := []struct {
+		name     string
+		session  string
+		secret   string
+		expected int
+	}{
+		{name: "session signed with its own secret", session: "business", secret: businessSecret, expected: http.StatusOK},
+		{name: "session signed with another session's secret", session: "business", secret: personalSecret, expected: http.StatusUnauthorized},
+		{name: "session with its own secret signed with the global one", session: "business", secret: globalSecret, expected: http.StatusUnauthorized},
+		{name: "session without a secret uses the global one", session: "shared", secret: globalSecret, expected: http.StatusOK},
+		{name: "session without a secret rejects a session's secret", session: "shared", secret: businessSecret, expected: http.StatusUnauthorized},
+		{name: "unknown session uses the global secret", session: "unknown", secret: globalSecret, expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// An event WhatSignal does not handle is answered once the signature is verified
+			payload, err := json.Marshal(map[string]interface{}{
+				"event":   "session.status",
+				"session": tt.session,
+				"payload": map[string]interface{}{"id": tt.name},
+			})
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/webhook/whatsapp", bytes.NewReader(payload))
+			req.Header.Set(XWahaSignatureHeader, signWahaTestPayload(tt.secret, payload))
+			req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			server.handleWhatsAppWebhook()(w, req)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+
+	"whatsignal/internal/models"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// webhookEvents are the WAHA events WhatSignal handles
+var webhookEvents = []string{
+	models.EventMessage,
+	models.EventMessageReaction,
+	models.EventMessageEdited,
+	models.EventMessageACK,
+	models.EventMessageWaiting,
+	models.EventGroupV2Participants,
+}
+
+// wahaSessionConfig builds the WAHA config of a session WhatSignal creates: a webhook to
+// whatsapp.webhookURL for the events WhatSignal processes, signed with the webhook secret, and
+// the proxy in whatsapp.sessionProxy. It returns nil when neither is configured.
+func wahaSessionConfig(cfg models.WhatsAppConfig) *types.SessionConfig {
+	var config types.SessionConfig
+
+	if cfg.WebhookURL != "" {
+		events := webhookEvents
+		if len(cfg.EnabledEvents) > 0 {
+			events = []string{models.EventMessage}
+			for _, event := range cfg.EnabledEvents {
+				event = strings.ToLower(strings.TrimSpace(event))
+				if !slices.Contains(events, event) {
+					events = append(events, event)
+				}
+			}
+		}
+		webhook := types.SessionWebhook{URL: cfg.WebhookURL, Events: events}
+		if cfg.WebhookSecret != "" {
+			webhook.HMAC = &types.WebhookHMAC{Key: cfg.WebhookSecret}
+		}
+		config.Webhooks = []types.SessionWebhook{webhook}
+	}
+
+	if cfg.SessionProxy != "" {
+		if u, err := url.Parse(cfg.SessionProxy); err == nil && u.Host != "" {
+			config.Proxy = &types.SessionProxy{Server: u.Host}
+			if u.User != nil {
+				config.Proxy.Username = u.User.Username()
+				config.Proxy.Password, _ = u.User.Password()
+			}
+		}
+	}
+
+	if config.Proxy == nil && len(config.Webhooks) == 0 {
+		return nil
+	}
+	return &config
+}
+
+// ensureSessionCreated creates the client's session in WAHA, configured by wahaSessionConfig,
+// when whatsapp.sessionAutoCreate is set and WAHA does not have it. WAHA starts a new session
+// straight away.
+func ensureSessionCreated(ctx context.Context, client types.WAClient, cfg models.WhatsAppConfig, logger *logrus.Logger) {
+	if !cfg.SessionAutoCreate {
+		return
+	}
+
+	sessionLogger := logger.WithField("session", client.GetSessionName())
+	_, err := client.GetSessionStatus(ctx)
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, types.ErrSessionNotFound) {
+		sessionLogger.WithError(err).Warn("Could not check whether the WhatsApp session exists; not creating it")
+		return
+	}
+
+	if err := client.CreateSession(ctx, wahaSessionConfig(cfg)); err != nil {
+		sessionLogger.WithError(err).Error("Failed to create WhatsApp session")
+		return
+	}
+	sessionLogger.Info("Created WhatsApp session in WAHA")
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/retry"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// warmUpSessions waits, for at most timeout, until every session is WORKING, so the first
+// messages after startup are not relayed to a session that cannot send them yet. Sessions are
+// waited for in parallel; a failed status check is retried with backoff until the timeout. It
+// returns the sessions that were still not ready, sorted; startup goes on without them and the
+// session monitor, when enabled, takes over.
+func warmUpSessions(ctx context.Context, waClient types.WAClient, sessions []string, timeout time.Duration, logger *logrus.Logger) []string {
+	deadline := time.Now().Add(timeout)
+	warmupCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	logger.WithFields(logrus.Fields{
+		"sessions": len(sessions),
+		"timeout":  timeout,
+	}).Info("Waiting for WhatsApp sessions to be ready before relaying")
+
+	var (
+		mu       sync.Mutex
+		notReady []string
+		wg       sync.WaitGroup
+	)
+	for _, sessionName := range sessions {
+		wg.Add(1)
+		go func(sessionName string) {
+			defer wg.Done()
+			if err := waitForSessionWarmup(warmupCtx, waClient, sessionName, deadline); err != nil {
+				logger.WithError(err).WithField("session", sessionName).Warn("WhatsApp session not ready after warm-up; starting without it")
+				mu.Lock()
+				notReady = append(notReady, sessionName)
+				mu.Unlock()
+				return
+			}
+			logger.WithField("session", sessionName).Info("WhatsApp session is ready")
+		}(sessionName)
+	}
+	wg.Wait()
+
+	sort.Strings(notReady)
+	return notReady
+}
+
+// waitForSessionWarmup waits until the session is WORKING, deadline passes or ctx ends. WAHA
+// being unreachable or the session not existing yet fails a check early, so checks are repeated
+// with backoff.
+func waitForSessionWarmup(ctx context.Context, waClient types.WAClient, sessionName string, deadline time.Time) error {
+	backoff := retry.NewBackoff(retry.BackoffConfig{
+		InitialDelay: time.Duration(constants.DefaultBackoffInitialMs) * time.Millisecond,
+		MaxDelay:     time.Duration(constants.DefaultBackoffMaxSec) * time.Second,
+		Multiplier:   2.0,
+	})
+
+	for attempt := 1; ; attempt++ {
+		err := waClient.WaitForSessionReadyByName(ctx, sessionName, time.Until(deadline))
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff.GetNextDelay(attempt)):
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWarmUpSessions_BlocksUntilSessionsAreReady(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	release := make(chan struct{})
+
+	waClient := &mockWAClient{}
+	waClient.On("WaitForSessionReadyByName", mock.Anything, "personal", mock.Anything).Return(nil).Once()
+	// The first check fails as if WAHA were still starting; the retry waits for the session
+	waClient.On("WaitForSessionReadyByName", mock.Anything, "work", mock.Anything).Return(errors.New("connection refused")).Once()
+	waClient.On("WaitForSessionReadyByName", mock.Anything, "work", mock.Anything).Run(func(mock.Arguments) { <-release }).Return(nil).Once()
+
+	done := make(chan []string)
+	go func() {
+		done <- warmUpSessions(context.Background(), waClient, []string{"personal", "work"}, 10*time.Second, logger)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("warm-up finished before every session was ready")
+	case <-time.After(time.Second):
+	}
+
+	close(release)
+	select {
+	case notReady := <-done:
+		assert.Empty(t, notReady)
+	case <-time.After(5 * time.Second):
+		t.Fatal("warm-up did not finish once the sessions were ready")
+	}
+	waClient.AssertExpectations(t)
+}
+
+func TestWarmUpSessions_TimesOut(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	waClient := &mockWAClient{}
+	waClient.On("WaitForSessionReadyByName", mock.Anything, "personal", mock.Anything).Return(nil)
+	waClient.On("WaitForSessionReadyByName", mock.Anything, "work", mock.Anything).Return(errors.New("session status is SCAN_QR_CODE"))
+
+	start := time.Now()
+	notReady := warmUpSessions(context.Background(), waClient, []string{"work", "personal"}, 200*time.Millisecond, logger)
+
+	assert.Equal(t, []string{"work"}, notReady)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond, "warm-up waits for the full timeout")
+	assert.Less(t, time.Since(start), 2*time.Second, "warm-up does not wait past the timeout")
+}
+
+func TestWarmUpSessions_StopsOnShutdown(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	waClient := &mockWAClient{}
+	waClient.On("WaitForSessionReadyByName", mock.Anything, "work", mock.Anything).Return(errors.New("connection refused"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, []string{"work"}, warmUpSessions(ctx, waClient, []string{"work"}, time.Minute, logger))
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	apperrors "whatsignal/internal/errors"
+	"whatsignal/internal/retry"
+	"whatsignal/pkg/media"
+)
+
+// Values for webhookResponse.Status
+const (
+	webhookStatusAccepted = "accepted" // The event was processed
+	webhookStatusIgnored  = "ignored"  // The event was valid but deliberately not processed
+	webhookStatusError    = "error"    // The event failed; see Retry
+)
+
+// webhookResponse is the JSON body of every WhatsApp webhook response. Retry tells WAHA, or a
+// proxy in front of it, whether sending the same event again can succeed; it is only true for
+// 5xx responses.
+type webhookResponse struct {
+	Status    string               `json:"status"`
+	Reason    string               `json:"reason,omitempty"`
+	Error     *webhookErrorDetails `json:"error,omitempty"`
+	Retry     bool                 `json:"retry"`
+	RequestID string               `json:"request_id,omitempty"`
+}
+
+type webhookErrorDetails struct {
+	Code    apperrors.ErrorCode `json:"code"`
+	Message string              `json:"message"`
+}
+
+// webhookError is a failed webhook request with the status code and retry hint to answer it with
+type webhookError struct {
+	status  int
+	code    apperrors.ErrorCode
+	message string
+	retry   bool
+}
+
+// classifyWebhookError maps an error from processing a WhatsApp event to its response. Invalid
+// events and failures that cannot succeed later, such as oversized media or a Signal recipient
+// that is not registered, get a 4xx and no retry. Anything else may be transient and gets a 5xx.
+func classifyWebhookError(err error) webhookError {
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return webhookError{status: http.StatusBadRequest, code: apperrors.ErrCodeValidationFailed, message: validationErr.Message}
+	}
+
+	var oversizeErr *media.OversizeError
+	if errors.As(err, &oversizeErr) {
+		return webhookError{status: http.StatusUnprocessableEntity, code: apperrors.ErrCodeMediaDownload, message: "Media exceeds the size limit"}
+	}
+
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		if appErr.Retryable {
+			return webhookError{status: http.StatusServiceUnavailable, code: appErr.Code, message: "Failed to process event", retry: true}
+		}
+		return webhookError{status: http.StatusUnprocessableEntity, code: appErr.Code, message: apperrors.GetUserMessage(appErr)}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return webhookError{status: http.StatusServiceUnavailable, code: apperrors.ErrCodeTimeout, message: "Timed out processing event", retry: true}
+	}
+
+	if !retry.IsRetryableSignalError(err) {
+		return webhookError{status: http.StatusUnprocessableEntity, code: apperrors.ErrCodeSignalAPI, message: "Signal rejected the message"}
+	}
+
+	return webhookError{status: http.StatusInternalServerError, code: apperrors.ErrCodeInternalError, message: "Failed to process event", retry: true}
+}
+
+// writeWebhookAccepted answers a webhook event that was processed
+func (s *Server) writeWebhookAccepted(w http.ResponseWriter, requestID string) {
+	s.writeJSON(w, http.StatusOK, webhookResponse{Status: webhookStatusAccepted, RequestID: requestID})
+}
+
+// writeWebhookIgnored answers a webhook event that is deliberately not processed with 200, so
+// WAHA does not retry it
+func (s *Server) writeWebhookIgnored(w http.ResponseWriter, reason, requestID string) {
+	s.writeJSON(w, http.StatusOK, webhookResponse{Status: webhookStatusIgnored, Reason: reason, RequestID: requestID})
+}
+
+// writeWebhookError answers a failed webhook request
+func (s *Server) writeWebhookError(w http.ResponseWriter, webhookErr webhookError, requestID string) {
+	s.writeJSON(w, webhookErr.status, webhookResponse{
+		Status:    webhookStatusError,
+		Error:     &webhookErrorDetails{Code: webhookErr.code, Message: webhookErr.message},
+		Retry:     webhookErr.retry,
+		RequestID: requestID,
+	})
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apperrors "whatsignal/internal/errors"
+	"whatsignal/internal/models"
+	"whatsignal/pkg/media"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyWebhookError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    apperrors.ErrorCode
+		wantRetry   bool
+		wantMessage string
+	}{
+		{
+			name:        "validation error",
+			err:         ValidationError{Message: "missing required field: Payload.ID"},
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    apperrors.ErrCodeValidationFailed,
+			wantMessage: "missing required field: Payload.ID",
+		},
+		{
+			name:        "wrapped validation error",
+			err:         fmt.Errorf("handling event: %w", ValidationError{Message: "invalid session name"}),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    apperrors.ErrCodeValidationFailed,
+			wantMessage: "invalid session name",
+		},
+		{
+			name:       "oversized media",
+			err:        fmt.Errorf("failed to process media: %w", &media.OversizeError{MediaType: "video", Size: 200, Limit: 100}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   apperrors.ErrCodeMediaDownload,
+		},
+		{
+			name:       "retryable application error",
+			err:        fmt.Errorf("send failed: %w", apperrors.WrapRetryable(errors.New("connection reset"), apperrors.ErrCodeSignalAPI, "signal send failed")),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   apperrors.ErrCodeSignalAPI,
+			wantRetry:  true,
+		},
+		{
+			name:       "permanent application error",
+			err:        apperrors.New(apperrors.ErrCodeNotFound, "chat not found"),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   apperrors.ErrCodeNotFound,
+		},
+		{
+			name:       "timeout",
+			err:        fmt.Errorf("failed to send to Signal: %w", context.DeadlineExceeded),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   apperrors.ErrCodeTimeout,
+			wantRetry:  true,
+		},
+		{
+			name:       "Signal recipient not registered",
+			err:        errors.New("failed to send message: Unregistered user +15551234567"),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   apperrors.ErrCodeSignalAPI,
+		},
+		{
+			name:       "unknown failure",
+			err:        errors.New("database is locked"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   apperrors.ErrCodeInternalError,
+			wantRetry:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyWebhookError(tt.err)
+			assert.Equal(t, tt.wantStatus, got.status)
+			assert.Equal(t, tt.wantCode, got.code)
+			assert.Equal(t, tt.wantRetry, got.retry)
+			assert.Equal(t, tt.wantRetry, got.status >= 500, "only retryable failures get a 5xx")
+			if tt.wantMessage != "" {
+				assert.Equal(t, tt.wantMessage, got.message)
+			}
+		})
+	}
+}
+
+// webhookResponseTestServer returns a server whose message service fails HandleWhatsAppMessageWithSession
+// with serviceErr, and a function posting a signed message event to it
+func webhookResponseTestServer(t *testing.T, cfg *models.Config, serviceErr error) (*mockMessageService, func(timestamp string) (*httptest.ResponseRecorder, webhookResponse)) {
+	t.Helper()
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	msgService := &mockMessageService{}
+	msgService.On("HandleWhatsAppMessageWithSession", mock.Anything, "default", "+1234567890", "msg-1", "+1234567890", "", "hello", "").Return(serviceErr)
+	server := NewServer(cfg, msgService, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event":   models.EventMessage,
+		"session": "default",
+		"payload": map[string]interface{}{"id": "msg-1", "from": "+1234567890", "body": "hello"},
+	})
+	require.NoError(t, err)
+
+	post := func(timestamp string) (*httptest.ResponseRecorder, webhookResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/whatsapp", bytes.NewReader(body))
+		req.Header.Set(XWahaSignatureHeader, signWahaTestPayload(cfg.WhatsApp.WebhookSecret, body))
+		req.Header.Set("X-Webhook-Timestamp", timestamp)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.handleWhatsAppWebhook()(w, req)
+
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var resp webhookResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return w, resp
+	}
+	return msgService, post
+}
+
+func TestWhatsAppWebhook_StructuredErrorResponses(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+		wantCode   apperrors.ErrorCode
+		wantRetry  bool
+	}{
+		{"processing succeeded", nil, http.StatusOK, "", false},
+		{"validation error", ValidationError{Message: "invalid message ID"}, http.StatusBadRequest, apperrors.ErrCodeValidationFailed, false},
+		{"oversized media", &media.OversizeError{MediaType: "image", Size: 10, Limit: 5}, http.StatusUnprocessableEntity, apperrors.ErrCodeMediaDownload, false},
+		{"retryable failure", apperrors.WrapRetryable(errors.New("503"), apperrors.ErrCodeSignalAPI, "signal unavailable"), http.StatusServiceUnavailable, apperrors.ErrCodeSignalAPI, true},
+		{"unknown failure", errors.New("boom"), http.StatusInternalServerError, apperrors.ErrCodeInternalError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &models.Config{WhatsApp: models.WhatsAppConfig{WebhookSecret: "test-secret"}}
+			_, post := webhookResponseTestServer(t, cfg, tt.serviceErr)
+
+			w, resp := post(fmt.Sprintf("%d", time.Now().UnixMilli()))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantRetry, resp.Retry)
+			assert.NotEmpty(t, resp.RequestID)
+			if tt.serviceErr == nil {
+				assert.Equal(t, webhookStatusAccepted, resp.Status)
+				assert.Nil(t, resp.Error)
+				return
+			}
+			assert.Equal(t, webhookStatusError, resp.Status)
+			require.NotNil(t, resp.Error)
+			assert.Equal(t, tt.wantCode, resp.Error.Code)
+			assert.NotEmpty(t, resp.Error.Message)
+		})
+	}
+}
+
+func TestWhatsAppWebhook_FilteredEventIsIgnoredWith200(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	cfg := &models.Config{WhatsApp: models.WhatsAppConfig{
+		WebhookSecret: "test-secret",
+		EnabledEvents: []string{models.EventMessageACK},
+	}}
+	server := NewServer(cfg, &mockMessageService{}, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event":   models.EventMessageReaction,
+		"session": "default",
+		"payload": map[string]interface{}{"id": "reaction-1", "from": "+1234567890"},
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/webhook/whatsapp", bytes.NewReader(body))
+	req.Header.Set(XWahaSignatureHeader, signWahaTestPayload(cfg.WhatsApp.WebhookSecret, body))
+	req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	server.handleWhatsAppWebhook()(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp webhookResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, webhookStatusIgnored, resp.Status)
+	assert.Equal(t, "event_filtered", resp.Reason)
+	assert.False(t, resp.Retry)
+}
+
+func TestWhatsAppWebhook_InvalidSignatureIsNotRetryable(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENV", "development")
+
+	cfg := &models.Config{WhatsApp: models.WhatsAppConfig{WebhookSecret: "test-secret"}}
+	server := NewServer(cfg, &mockMessageService{}, logrus.New(), &mockWAClient{}, createTestChannelManager(), &mockDatabase{}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook/whatsapp", bytes.NewReader([]byte(`{"event":"message"}`)))
+	req.Header.Set(XWahaSignatureHeader, "sha512=invalid")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	server.handleWhatsAppWebhook()(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var resp webhookResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, apperrors.ErrCodeAuthentication, resp.Error.Code)
+	assert.False(t, resp.Retry)
+}
+
+func TestWhatsAppWebhook_RetryableFailureCanBeRedelivered(t *testing.T) {
+	cfg := &models.Config{WhatsApp: models.WhatsAppConfig{WebhookSecret: "test-secret"}}
+	msgService, post := webhookResponseTestServer(t, cfg, errors.New("boom"))
+	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
+
+	first, _ := post(timestamp)
+	require.Equal(t, http.StatusInternalServerError, first.Code)
+
+	second, _ := post(timestamp)
+	assert.Equal(t, http.StatusInternalServerError, second.Code, "a retry of a retryable failure is processed again, not rejected as a replay")
+	msgService.AssertNumberOfCalls(t, "HandleWhatsAppMessageWithSession", 2)
+}
+
+func TestWhatsAppWebhook_PermanentFailureIsNotRedelivered(t *testing.T) {
+	cfg := &models.Config{WhatsApp: models.WhatsAppConfig{WebhookSecret: "test-secret"}}
+	msgService, post := webhookResponseTestServer(t, cfg, ValidationError{Message: "invalid message ID"})
+	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
+
+	first, _ := post(timestamp)
+	require.Equal(t, http.StatusBadRequest, first.Code)
+
+	second, resp := post(timestamp)
+	assert.Equal(t, http.StatusConflict, second.Code)
+	assert.False(t, resp.Retry)
+	msgService.AssertNumberOfCalls(t, "HandleWhatsAppMessageWithSession", 1)
+}
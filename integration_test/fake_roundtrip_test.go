@@ -0,0 +1,136 @@
+package integration_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"whatsignal/internal/models"
+	"whatsignal/internal/service"
+	"whatsignal/internal/testutil"
+	"whatsignal/pkg/media"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRoundTrip wires the real bridge and message service to in-memory WAHA and Signal fakes
+type fakeRoundTrip struct {
+	waha           *testutil.FakeWAHA
+	signal         *testutil.FakeSignal
+	messageService service.MessageService
+}
+
+func newFakeRoundTrip(t *testing.T, signalConfig models.SignalConfig) *fakeRoundTrip {
+	t.Helper()
+
+	db, cleanup := NewTestDatabase(t, &TestDatabaseOptions{
+		UseInMemory:      true,
+		EncryptionSecret: "test-secret-key-for-integration-tests-32bytes!!",
+	})
+	t.Cleanup(cleanup)
+
+	mediaConfig := models.MediaConfig{
+		CacheDir:  t.TempDir(),
+		MaxSizeMB: models.MediaSizeLimits{Image: 5, Video: 100, Document: 100, Voice: 16},
+	}
+	mediaHandler, err := media.NewHandler(mediaConfig.CacheDir, mediaConfig)
+	require.NoError(t, err)
+
+	channelManager, err := service.NewChannelManager([]models.Channel{
+		{WhatsAppSessionName: "personal", SignalDestinationPhoneNumber: "+1111111111"},
+	})
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	waha := testutil.NewFakeWAHA("personal")
+	signal := testutil.NewFakeSignal("+1234567890")
+	bridge := service.NewBridge(
+		waha,
+		signal,
+		db,
+		mediaHandler,
+		models.RetryConfig{InitialBackoffMs: 1, MaxBackoffMs: 10, MaxAttempts: 1},
+		mediaConfig,
+		channelManager,
+		service.NewContactService(db, waha),
+		nil,
+		t.TempDir(),
+		logger,
+	)
+	messageService := service.NewMessageServiceWithLogger(bridge, db, mediaHandler, signal, signalConfig, channelManager, logger)
+
+	return &fakeRoundTrip{waha: waha, signal: signal, messageService: messageService}
+}
+
+func TestFakeRoundTrip_ReplyRoutesToOriginalChat(t *testing.T) {
+	rt := newFakeRoundTrip(t, models.SignalConfig{})
+	ctx := context.Background()
+
+	// Two WhatsApp chats are relayed to the same Signal destination
+	require.NoError(t, rt.messageService.HandleWhatsAppMessageWithSession(ctx, "personal",
+		"15550001111@c.us", "wamid.alice1", "15550001111@c.us", "Alice", "Dinner tonight?", ""))
+	aliceSend, ok := rt.signal.LastSend()
+	require.True(t, ok)
+	assert.Equal(t, "+1111111111", aliceSend.Recipient)
+	assert.Contains(t, aliceSend.Message, "Dinner tonight?")
+
+	require.NoError(t, rt.messageService.HandleWhatsAppMessageWithSession(ctx, "personal",
+		"15550002222@c.us", "wamid.bob1", "15550002222@c.us", "Bob", "Call me back", ""))
+	require.Len(t, rt.signal.Sends(), 2)
+
+	// Replying to Alice's message on Signal goes back to Alice, not to the latest chat
+	rt.signal.Deliver(rt.signal.Reply(aliceSend, "Sounds good"))
+	require.NoError(t, rt.messageService.PollSignalMessages(ctx))
+
+	sends := rt.waha.Sends()
+	require.Len(t, sends, 1)
+	assert.Equal(t, "personal", sends[0].Session)
+	assert.Equal(t, "15550001111@c.us", sends[0].ChatID)
+	assert.Equal(t, "Sounds good", sends[0].Text)
+	assert.Equal(t, "wamid.alice1", sends[0].ReplyTo)
+}
+
+func TestFakeRoundTrip_SignalSendFailure(t *testing.T) {
+	rt := newFakeRoundTrip(t, models.SignalConfig{})
+	ctx := context.Background()
+
+	rt.signal.FailNext("SendMessage", assert.AnError)
+
+	err := rt.messageService.HandleWhatsAppMessageWithSession(ctx, "personal",
+		"15550001111@c.us", "wamid.alice1", "15550001111@c.us", "Alice", "Are you there?", "")
+	require.Error(t, err)
+	assert.Equal(t, 1, rt.signal.Calls("SendMessage"))
+	assert.Empty(t, rt.signal.Sends())
+}
+
+func TestFakeRoundTrip_SystemNotificationsGoToOpsRecipient(t *testing.T) {
+	rt := newFakeRoundTrip(t, models.SignalConfig{OpsRecipient: "+1999999999"})
+	ctx := context.Background()
+
+	require.NoError(t, rt.messageService.HandleWhatsAppMessageWithSession(ctx, "personal",
+		"15550001111@c.us", "wamid.alice1", "15550001111@c.us", "Alice", "Dinner tonight?", ""))
+	require.NoError(t, rt.messageService.SendSignalNotification(ctx, "personal", "✏️ Message edited: Lunch tonight?"))
+	require.NoError(t, rt.messageService.SendSystemNotification(ctx, "personal", "WhatsApp session is down"))
+
+	sends := rt.signal.Sends()
+	require.Len(t, sends, 3)
+	assert.Equal(t, "+1111111111", sends[0].Recipient)
+	assert.Equal(t, "+1111111111", sends[1].Recipient)
+	assert.Equal(t, "+1999999999", sends[2].Recipient)
+	assert.Equal(t, "[personal] WhatsApp session is down", sends[2].Message)
+}
+
+func TestFakeRoundTrip_SystemNotificationsWithoutOpsRecipient(t *testing.T) {
+	rt := newFakeRoundTrip(t, models.SignalConfig{})
+
+	require.NoError(t, rt.messageService.SendSystemNotification(context.Background(), "personal", "WhatsApp session is down"))
+
+	sent, ok := rt.signal.LastSend()
+	require.True(t, ok)
+	assert.Equal(t, "+1111111111", sent.Recipient)
+	assert.Equal(t, "WhatsApp session is down", sent.Message)
+}
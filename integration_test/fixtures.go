@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -271,9 +272,32 @@ func (f *TestFixtures) WhatsAppWebhooks() map[string]models.WhatsAppWebhookPaylo
 					Text      string `json:"text"`
 					MessageID string `json:"messageId"`
 				} `json:"reaction"`
+				ReplyTo *struct {
+					ID          string `json:"id"`
+					Participant string `json:"participant,omitempty"`
+					Body        string `json:"body,omitempty"`
+				} `json:"replyTo,omitempty"`
 				Data *struct {
-					NotifyName string `json:"notifyName,omitempty"`
-					PushName   string `json:"pushName,omitempty"`
+					NotifyName        string `json:"notifyName,omitempty"`
+					PushName          string `json:"pushName,omitempty"`
+					EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+					IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+					QuotedMsg         *struct {
+						Body    string `json:"body,omitempty"`
+						Caption string `json:"caption,omitempty"`
+					} `json:"quotedMsg,omitempty"`
+					Message           json.RawMessage `json:"message,omitempty"`
+					PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+					Type              string          `json:"type,omitempty"`
+					PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+					PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+					PaymentStatus     int             `json:"paymentStatus,omitempty"`
+					PaymentNoteMsg    *struct {
+						Body string `json:"body,omitempty"`
+					} `json:"paymentNoteMsg,omitempty"`
+					Lat float64 `json:"lat,omitempty"`
+					Lng float64 `json:"lng,omitempty"`
+					Loc string  `json:"loc,omitempty"`
 				} `json:"_data,omitempty"`
 				EditedMessageID *string `json:"editedMessageId,omitempty"`
 				ACK             *int    `json:"ack,omitempty"`
@@ -310,9 +334,32 @@ func (f *TestFixtures) WhatsAppWebhooks() map[string]models.WhatsAppWebhookPaylo
 					Text      string `json:"text"`
 					MessageID string `json:"messageId"`
 				} `json:"reaction"`
+				ReplyTo *struct {
+					ID          string `json:"id"`
+					Participant string `json:"participant,omitempty"`
+					Body        string `json:"body,omitempty"`
+				} `json:"replyTo,omitempty"`
 				Data *struct {
-					NotifyName string `json:"notifyName,omitempty"`
-					PushName   string `json:"pushName,omitempty"`
+					NotifyName        string `json:"notifyName,omitempty"`
+					PushName          string `json:"pushName,omitempty"`
+					EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+					IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+					QuotedMsg         *struct {
+						Body    string `json:"body,omitempty"`
+						Caption string `json:"caption,omitempty"`
+					} `json:"quotedMsg,omitempty"`
+					Message           json.RawMessage `json:"message,omitempty"`
+					PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+					Type              string          `json:"type,omitempty"`
+					PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+					PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+					PaymentStatus     int             `json:"paymentStatus,omitempty"`
+					PaymentNoteMsg    *struct {
+						Body string `json:"body,omitempty"`
+					} `json:"paymentNoteMsg,omitempty"`
+					Lat float64 `json:"lat,omitempty"`
+					Lng float64 `json:"lng,omitempty"`
+					Loc string  `json:"loc,omitempty"`
 				} `json:"_data,omitempty"`
 				EditedMessageID *string `json:"editedMessageId,omitempty"`
 				ACK             *int    `json:"ack,omitempty"`
@@ -358,9 +405,32 @@ func (f *TestFixtures) WhatsAppWebhooks() map[string]models.WhatsAppWebhookPaylo
 					Text      string `json:"text"`
 					MessageID string `json:"messageId"`
 				} `json:"reaction"`
+				ReplyTo *struct {
+					ID          string `json:"id"`
+					Participant string `json:"participant,omitempty"`
+					Body        string `json:"body,omitempty"`
+				} `json:"replyTo,omitempty"`
 				Data *struct {
-					NotifyName string `json:"notifyName,omitempty"`
-					PushName   string `json:"pushName,omitempty"`
+					NotifyName        string `json:"notifyName,omitempty"`
+					PushName          string `json:"pushName,omitempty"`
+					EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+					IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+					QuotedMsg         *struct {
+						Body    string `json:"body,omitempty"`
+						Caption string `json:"caption,omitempty"`
+					} `json:"quotedMsg,omitempty"`
+					Message           json.RawMessage `json:"message,omitempty"`
+					PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+					Type              string          `json:"type,omitempty"`
+					PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+					PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+					PaymentStatus     int             `json:"paymentStatus,omitempty"`
+					PaymentNoteMsg    *struct {
+						Body string `json:"body,omitempty"`
+					} `json:"paymentNoteMsg,omitempty"`
+					Lat float64 `json:"lat,omitempty"`
+					Lng float64 `json:"lng,omitempty"`
+					Loc string  `json:"loc,omitempty"`
 				} `json:"_data,omitempty"`
 				EditedMessageID *string `json:"editedMessageId,omitempty"`
 				ACK             *int    `json:"ack,omitempty"`
@@ -398,9 +468,32 @@ func (f *TestFixtures) WhatsAppWebhooks() map[string]models.WhatsAppWebhookPaylo
 					Text      string `json:"text"`
 					MessageID string `json:"messageId"`
 				} `json:"reaction"`
+				ReplyTo *struct {
+					ID          string `json:"id"`
+					Participant string `json:"participant,omitempty"`
+					Body        string `json:"body,omitempty"`
+				} `json:"replyTo,omitempty"`
 				Data *struct {
-					NotifyName string `json:"notifyName,omitempty"`
-					PushName   string `json:"pushName,omitempty"`
+					NotifyName        string `json:"notifyName,omitempty"`
+					PushName          string `json:"pushName,omitempty"`
+					EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+					IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+					QuotedMsg         *struct {
+						Body    string `json:"body,omitempty"`
+						Caption string `json:"caption,omitempty"`
+					} `json:"quotedMsg,omitempty"`
+					Message           json.RawMessage `json:"message,omitempty"`
+					PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+					Type              string          `json:"type,omitempty"`
+					PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+					PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+					PaymentStatus     int             `json:"paymentStatus,omitempty"`
+					PaymentNoteMsg    *struct {
+						Body string `json:"body,omitempty"`
+					} `json:"paymentNoteMsg,omitempty"`
+					Lat float64 `json:"lat,omitempty"`
+					Lng float64 `json:"lng,omitempty"`
+					Loc string  `json:"loc,omitempty"`
 				} `json:"_data,omitempty"`
 				EditedMessageID *string `json:"editedMessageId,omitempty"`
 				ACK             *int    `json:"ack,omitempty"`
@@ -444,9 +537,32 @@ func (f *TestFixtures) WhatsAppWebhooks() map[string]models.WhatsAppWebhookPaylo
 					Text      string `json:"text"`
 					MessageID string `json:"messageId"`
 				} `json:"reaction"`
+				ReplyTo *struct {
+					ID          string `json:"id"`
+					Participant string `json:"participant,omitempty"`
+					Body        string `json:"body,omitempty"`
+				} `json:"replyTo,omitempty"`
 				Data *struct {
-					NotifyName string `json:"notifyName,omitempty"`
-					PushName   string `json:"pushName,omitempty"`
+					NotifyName        string `json:"notifyName,omitempty"`
+					PushName          string `json:"pushName,omitempty"`
+					EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+					IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+					QuotedMsg         *struct {
+						Body    string `json:"body,omitempty"`
+						Caption string `json:"caption,omitempty"`
+					} `json:"quotedMsg,omitempty"`
+					Message           json.RawMessage `json:"message,omitempty"`
+					PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+					Type              string          `json:"type,omitempty"`
+					PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+					PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+					PaymentStatus     int             `json:"paymentStatus,omitempty"`
+					PaymentNoteMsg    *struct {
+						Body string `json:"body,omitempty"`
+					} `json:"paymentNoteMsg,omitempty"`
+					Lat float64 `json:"lat,omitempty"`
+					Lng float64 `json:"lng,omitempty"`
+					Loc string  `json:"loc,omitempty"`
 				} `json:"_data,omitempty"`
 				EditedMessageID *string `json:"editedMessageId,omitempty"`
 				ACK             *int    `json:"ack,omitempty"`
@@ -483,9 +599,32 @@ func (f *TestFixtures) WhatsAppWebhooks() map[string]models.WhatsAppWebhookPaylo
 					Text      string `json:"text"`
 					MessageID string `json:"messageId"`
 				} `json:"reaction"`
+				ReplyTo *struct {
+					ID          string `json:"id"`
+					Participant string `json:"participant,omitempty"`
+					Body        string `json:"body,omitempty"`
+				} `json:"replyTo,omitempty"`
 				Data *struct {
-					NotifyName string `json:"notifyName,omitempty"`
-					PushName   string `json:"pushName,omitempty"`
+					NotifyName        string `json:"notifyName,omitempty"`
+					PushName          string `json:"pushName,omitempty"`
+					EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+					IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+					QuotedMsg         *struct {
+						Body    string `json:"body,omitempty"`
+						Caption string `json:"caption,omitempty"`
+					} `json:"quotedMsg,omitempty"`
+					Message           json.RawMessage `json:"message,omitempty"`
+					PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+					Type              string          `json:"type,omitempty"`
+					PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+					PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+					PaymentStatus     int             `json:"paymentStatus,omitempty"`
+					PaymentNoteMsg    *struct {
+						Body string `json:"body,omitempty"`
+					} `json:"paymentNoteMsg,omitempty"`
+					Lat float64 `json:"lat,omitempty"`
+					Lng float64 `json:"lng,omitempty"`
+					Loc string  `json:"loc,omitempty"`
 				} `json:"_data,omitempty"`
 				EditedMessageID *string `json:"editedMessageId,omitempty"`
 				ACK             *int    `json:"ack,omitempty"`
@@ -523,9 +662,32 @@ func (f *TestFixtures) WhatsAppWebhooks() map[string]models.WhatsAppWebhookPaylo
 					Text      string `json:"text"`
 					MessageID string `json:"messageId"`
 				} `json:"reaction"`
+				ReplyTo *struct {
+					ID          string `json:"id"`
+					Participant string `json:"participant,omitempty"`
+					Body        string `json:"body,omitempty"`
+				} `json:"replyTo,omitempty"`
 				Data *struct {
-					NotifyName string `json:"notifyName,omitempty"`
-					PushName   string `json:"pushName,omitempty"`
+					NotifyName        string `json:"notifyName,omitempty"`
+					PushName          string `json:"pushName,omitempty"`
+					EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+					IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+					QuotedMsg         *struct {
+						Body    string `json:"body,omitempty"`
+						Caption string `json:"caption,omitempty"`
+					} `json:"quotedMsg,omitempty"`
+					Message           json.RawMessage `json:"message,omitempty"`
+					PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+					Type              string          `json:"type,omitempty"`
+					PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+					PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+					PaymentStatus     int             `json:"paymentStatus,omitempty"`
+					PaymentNoteMsg    *struct {
+						Body string `json:"body,omitempty"`
+					} `json:"paymentNoteMsg,omitempty"`
+					Lat float64 `json:"lat,omitempty"`
+					Lng float64 `json:"lng,omitempty"`
+					Loc string  `json:"loc,omitempty"`
 				} `json:"_data,omitempty"`
 				EditedMessageID *string `json:"editedMessageId,omitempty"`
 				ACK             *int    `json:"ack,omitempty"`
@@ -563,9 +725,32 @@ func (f *TestFixtures) WhatsAppWebhooks() map[string]models.WhatsAppWebhookPaylo
 					Text      string `json:"text"`
 					MessageID string `json:"messageId"`
 				} `json:"reaction"`
+				ReplyTo *struct {
+					ID          string `json:"id"`
+					Participant string `json:"participant,omitempty"`
+					Body        string `json:"body,omitempty"`
+				} `json:"replyTo,omitempty"`
 				Data *struct {
-					NotifyName string `json:"notifyName,omitempty"`
-					PushName   string `json:"pushName,omitempty"`
+					NotifyName        string `json:"notifyName,omitempty"`
+					PushName          string `json:"pushName,omitempty"`
+					EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+					IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+					QuotedMsg         *struct {
+						Body    string `json:"body,omitempty"`
+						Caption string `json:"caption,omitempty"`
+					} `json:"quotedMsg,omitempty"`
+					Message           json.RawMessage `json:"message,omitempty"`
+					PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+					Type              string          `json:"type,omitempty"`
+					PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+					PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+					PaymentStatus     int             `json:"paymentStatus,omitempty"`
+					PaymentNoteMsg    *struct {
+						Body string `json:"body,omitempty"`
+					} `json:"paymentNoteMsg,omitempty"`
+					Lat float64 `json:"lat,omitempty"`
+					Lng float64 `json:"lng,omitempty"`
+					Loc string  `json:"loc,omitempty"`
 				} `json:"_data,omitempty"`
 				EditedMessageID *string `json:"editedMessageId,omitempty"`
 				ACK             *int    `json:"ack,omitempty"`
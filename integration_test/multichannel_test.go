@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -283,9 +284,32 @@ func createWhatsAppPayload(session, id, from, body string) models.WhatsAppWebhoo
 				Text      string `json:"text"`
 				MessageID string `json:"messageId"`
 			} `json:"reaction"`
+			ReplyTo *struct {
+				ID          string `json:"id"`
+				Participant string `json:"participant,omitempty"`
+				Body        string `json:"body,omitempty"`
+			} `json:"replyTo,omitempty"`
 			Data *struct {
-				NotifyName string `json:"notifyName,omitempty"`
-				PushName   string `json:"pushName,omitempty"`
+				NotifyName        string `json:"notifyName,omitempty"`
+				PushName          string `json:"pushName,omitempty"`
+				EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+				IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+				QuotedMsg         *struct {
+					Body    string `json:"body,omitempty"`
+					Caption string `json:"caption,omitempty"`
+				} `json:"quotedMsg,omitempty"`
+				Message           json.RawMessage `json:"message,omitempty"`
+				PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+				Type              string          `json:"type,omitempty"`
+				PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+				PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+				PaymentStatus     int             `json:"paymentStatus,omitempty"`
+				PaymentNoteMsg    *struct {
+					Body string `json:"body,omitempty"`
+				} `json:"paymentNoteMsg,omitempty"`
+				Lat float64 `json:"lat,omitempty"`
+				Lng float64 `json:"lng,omitempty"`
+				Loc string  `json:"loc,omitempty"`
 			} `json:"_data,omitempty"`
 			EditedMessageID *string `json:"editedMessageId,omitempty"`
 			ACK             *int    `json:"ack,omitempty"`
@@ -323,6 +347,14 @@ func (m *mockMultiSessionWAClient) GetGroup(ctx context.Context, groupID string)
 	return args.Get(0).(*types.Group), args.Error(1)
 }
 
+func (m *mockMultiSessionWAClient) GetGroupWithSession(ctx context.Context, groupID, sessionName string) (*types.Group, error) {
+	args := m.Called(ctx, groupID, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Group), args.Error(1)
+}
+
 func (m *mockMultiSessionWAClient) GetAllGroups(ctx context.Context, limit, offset int) ([]types.Group, error) {
 	args := m.Called(ctx, limit, offset)
 	if args.Get(0) == nil {
@@ -331,6 +363,30 @@ func (m *mockMultiSessionWAClient) GetAllGroups(ctx context.Context, limit, offs
 	return args.Get(0).([]types.Group), args.Error(1)
 }
 
+func (m *mockMultiSessionWAClient) GetChatLabels(ctx context.Context, chatID string) ([]types.Label, error) {
+	args := m.Called(ctx, chatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.Label), args.Error(1)
+}
+
+func (m *mockMultiSessionWAClient) GetChatMessages(ctx context.Context, chatID string, limit int, sessionName string) ([]types.ChatMessage, error) {
+	args := m.Called(ctx, chatID, limit, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.ChatMessage), args.Error(1)
+}
+
+func (m *mockMultiSessionWAClient) GetMe(ctx context.Context, sessionName string) (*types.Me, error) {
+	args := m.Called(ctx, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Me), args.Error(1)
+}
+
 func (m *mockMultiSessionWAClient) WaitForSessionReady(ctx context.Context, maxWaitTime time.Duration) error {
 	args := m.Called(ctx, maxWaitTime)
 	return args.Error(0)
@@ -345,6 +401,9 @@ func (m *mockMultiSessionWAClient) WaitForSessionReadyByName(ctx context.Context
 func (m *mockMultiSessionWAClient) SendTextWithSession(ctx context.Context, chatID, message, replyTo, sessionName string) (*types.SendMessageResponse, error) {
 	return nil, nil
 }
+func (m *mockMultiSessionWAClient) SendMentionsWithSession(ctx context.Context, chatID, message string, mentions []string, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	return nil, nil
+}
 func (m *mockMultiSessionWAClient) SendImageWithSession(ctx context.Context, chatID, imagePath, caption, replyTo, sessionName string) (*types.SendMessageResponse, error) {
 	return nil, nil
 }
@@ -363,7 +422,15 @@ func (m *mockMultiSessionWAClient) SendReactionWithSession(ctx context.Context,
 func (m *mockMultiSessionWAClient) DeleteMessage(ctx context.Context, chatID, messageID string) error {
 	return nil
 }
-func (m *mockMultiSessionWAClient) CreateSession(ctx context.Context) error {
+
+func (m *mockMultiSessionWAClient) EditMessageWithSession(ctx context.Context, chatID, messageID, newText, sessionName string) error {
+	return nil
+}
+
+func (m *mockMultiSessionWAClient) StarMessageWithSession(ctx context.Context, chatID, messageID string, star bool, sessionName string) error {
+	return nil
+}
+func (m *mockMultiSessionWAClient) CreateSession(ctx context.Context, config *types.SessionConfig) error {
 	return nil
 }
 func (m *mockMultiSessionWAClient) StartSession(ctx context.Context) error {
@@ -392,10 +459,24 @@ func (m *mockMultiSessionWAClient) AckMessage(ctx context.Context, chatID, sessi
 	return nil
 }
 
+func (m *mockMultiSessionWAClient) StartTyping(ctx context.Context, chatID, sessionName string) error {
+	args := m.Called(ctx, chatID, sessionName)
+	return args.Error(0)
+}
+
+func (m *mockMultiSessionWAClient) StopTyping(ctx context.Context, chatID, sessionName string) error {
+	args := m.Called(ctx, chatID, sessionName)
+	return args.Error(0)
+}
+
 func (m *mockMultiSessionWAClient) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+func (m *mockMultiSessionWAClient) GetCapabilities(ctx context.Context) types.Capabilities {
+	return types.Capabilities{}
+}
+
 // TestMultiSessionContactSync tests that contact sync works correctly across multiple sessions
 func TestMultiSessionContactSync(t *testing.T) {
 	ctx := context.Background()
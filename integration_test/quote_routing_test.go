@@ -41,9 +41,32 @@ func makeWhatsAppWebhook(session, chatID, msgID, body, notifyName string) models
 				Text      string `json:"text"`
 				MessageID string `json:"messageId"`
 			} `json:"reaction"`
+			ReplyTo *struct {
+				ID          string `json:"id"`
+				Participant string `json:"participant,omitempty"`
+				Body        string `json:"body,omitempty"`
+			} `json:"replyTo,omitempty"`
 			Data *struct {
-				NotifyName string `json:"notifyName,omitempty"`
-				PushName   string `json:"pushName,omitempty"`
+				NotifyName        string `json:"notifyName,omitempty"`
+				PushName          string `json:"pushName,omitempty"`
+				EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+				IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+				QuotedMsg         *struct {
+					Body    string `json:"body,omitempty"`
+					Caption string `json:"caption,omitempty"`
+				} `json:"quotedMsg,omitempty"`
+				Message           json.RawMessage `json:"message,omitempty"`
+				PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+				Type              string          `json:"type,omitempty"`
+				PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+				PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+				PaymentStatus     int             `json:"paymentStatus,omitempty"`
+				PaymentNoteMsg    *struct {
+					Body string `json:"body,omitempty"`
+				} `json:"paymentNoteMsg,omitempty"`
+				Lat float64 `json:"lat,omitempty"`
+				Lng float64 `json:"lng,omitempty"`
+				Loc string  `json:"loc,omitempty"`
 			} `json:"_data,omitempty"`
 			EditedMessageID *string `json:"editedMessageId,omitempty"`
 			ACK             *int    `json:"ack,omitempty"`
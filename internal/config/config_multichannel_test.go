@@ -192,6 +192,930 @@ func TestLoadConfig_MultiChannel(t *testing.T) {
 			expectedErr:   true,
 			errorContains: "empty Signal destination in channel 0",
 		},
+		{
+			name: "Broadcast targets",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111",
+						"broadcastTargets": ["15551234567@c.us", "120363028123456789@g.us"]
+					}
+				]
+			}`,
+			expectedErr: false,
+			validateConfig: func(t *testing.T, cfg *models.Config) {
+				assert.Equal(t, []string{"15551234567@c.us", "120363028123456789@g.us"}, cfg.Channels[0].BroadcastTargets)
+			},
+		},
+		{
+			name: "Invalid broadcast target",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111",
+						"broadcastTargets": ["not a chat"]
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "channel 0 broadcast target 0",
+		},
+		{
+			name: "Invalid ops recipient",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890",
+					"opsRecipient": "ops-team"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "Signal ops recipient",
+		},
+		{
+			name: "Simulated typing cap too long",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"simulateTyping": true,
+					"simulateTypingMaxSec": 120
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "simulate typing max seconds",
+		},
+		{
+			name: "Native reactions with reaction aggregation",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"nativeReactions": true,
+					"reactionAggregateWindowSec": 30
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "nativeReactions cannot be combined",
+		},
+		{
+			name: "Caption length too large",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"maxCaptionLength": 100000
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "max caption length",
+		},
+		{
+			name: "Work queue too deep",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"server": {
+					"maxQueueDepth": 20000
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "max queue depth too large",
+		},
+		{
+			name: "Server port out of range",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"server": {
+					"port": 70000
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "server port too large",
+		},
+		{
+			name: "Relative webhook path",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"server": {
+					"webhookPath": "hooks/waha"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "must start with /",
+		},
+		{
+			name: "Contact refresh rate too high",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"contactRefreshOnRelay": true,
+					"contactRefreshPerMinute": 1000
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "contact refresh per minute too large",
+		},
+		{
+			name: "Too many debug payload files",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"server": {
+					"debugPayloadDir": "./debug-payloads",
+					"debugPayloadMaxFiles": 20000
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "debug payload max files too large",
+		},
+		{
+			name: "Reaction aggregate window too long",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"reactionAggregateWindowSec": 3600
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "reaction aggregate window seconds too large",
+		},
+		{
+			name: "Live location interval too long",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"liveLocationIntervalSec": 7200
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "live location interval seconds too large",
+		},
+		{
+			name: "Unknown media extension detection source",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media",
+					"extensionDetectionOrder": ["contentType", "magic"]
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "invalid media extension detection source \"magic\"",
+		},
+		{
+			name: "Duplicate media extension detection source",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media",
+					"extensionDetectionOrder": ["signature", "url", "signature"]
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "media extension detection source \"signature\" is listed twice",
+		},
+		{
+			name: "Maximum relay age too long",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"server": {
+					"maxRelayAgeMin": 100000
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "max relay age minutes too large",
+		},
+		{
+			name: "Invalid WhatsApp webhook URL",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"sessionAutoCreate": true,
+					"webhookURL": "whatsignal:8082/webhook"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "invalid WhatsApp webhook URL",
+		},
+		{
+			name: "Invalid ambiguous reply routing",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890",
+					"ambiguousReplyRouting": "first"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "ambiguous reply routing",
+		},
+		{
+			name: "Invalid transform regex",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				],
+				"transforms": [
+					{"type": "replace", "pattern": "(unclosed", "replacement": ""}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "transform rule 1: invalid pattern",
+		},
+		{
+			name: "Media download resume attempts too high",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media",
+					"downloadResumeAttempts": 50
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "media download resume attempts",
+		},
+		{
+			name: "Negative media orphan purge grace hours",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media",
+					"orphanPurgeGraceHours": -1
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "media orphan purge grace hours",
+		},
+		{
+			name: "Too many media attachments per relay",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media",
+					"maxAttachmentsPerRelay": 1000
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "media max attachments per relay",
+		},
+		{
+			name: "Media max cache size too large",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media",
+					"maxCacheSizeMB": 2000000
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "media max cache size MB",
+		},
+		{
+			name: "Channel media override",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "business",
+						"signalDestinationPhoneNumber": "+1111111111",
+						"media": {
+							"maxSizeMB": {"document": 200}
+						}
+					},
+					{
+						"whatsappSessionName": "personal",
+						"signalDestinationPhoneNumber": "+2222222222"
+					}
+				]
+			}`,
+			expectedErr: false,
+			validateConfig: func(t *testing.T, cfg *models.Config) {
+				require.NotNil(t, cfg.Channels[0].Media)
+				assert.Equal(t, 200, cfg.Channels[0].Media.MaxSizeMB.Document)
+				assert.Zero(t, cfg.Channels[0].Media.MaxSizeMB.Image)
+				assert.Nil(t, cfg.Channels[1].Media)
+			},
+		},
+		{
+			name: "Channel media override document size too large",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "business",
+						"signalDestinationPhoneNumber": "+1111111111",
+						"media": {
+							"maxSizeMB": {"document": 5000}
+						}
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "document max size too large",
+		},
+		{
+			name: "Session restart backoff too long",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"sessionRestartBackoffSec": 100000
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "session restart backoff seconds",
+		},
+		{
+			name: "Session warm-up timeout too long",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"sessionWarmupTimeoutSec": 3600
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "session warm-up timeout seconds",
+		},
+		{
+			name: "Session restart backoff max below base",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"sessionRestartBackoffSec": 120,
+					"sessionRestartBackoffMaxSec": 60
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "session restart backoff max seconds cannot be less",
+		},
+		{
+			name: "Media download header with line break",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media",
+					"downloadHeaders": {"X-Proxy-Token": "abc\r\nHost: evil"}
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "invalid media download header",
+		},
+		{
+			name: "Auto-reply cooldown too long",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000",
+					"autoReplyCooldownMin": 20000
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890"
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "auto-reply cooldown minutes",
+		},
+		{
+			name: "Reaction dedup window too long",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890",
+					"reactionDedupWindowSec": 3600
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "signal reaction dedup window",
+		},
+		{
+			name: "Persisted reaction dedup without a window",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890",
+					"persistReactionDedup": true
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "persistReactionDedup requires",
+		},
+		{
+			name: "New thread grace period too long",
+			configContent: `{
+				"whatsapp": {
+					"api_base_url": "http://localhost:3000"
+				},
+				"signal": {
+					"rpc_url": "http://localhost:8080",
+					"intermediaryPhoneNumber": "+1234567890",
+					"newThreadGraceMs": 60000
+				},
+				"database": {
+					"path": "./test.db"
+				},
+				"media": {
+					"cache_dir": "./media"
+				},
+				"channels": [
+					{
+						"whatsappSessionName": "default",
+						"signalDestinationPhoneNumber": "+1111111111"
+					}
+				]
+			}`,
+			expectedErr:   true,
+			errorContains: "signal new thread grace period",
+		},
 		{
 			name: "No channels and no legacy config",
 			configContent: `{
@@ -609,6 +609,20 @@ func TestValidateSecurity(t *testing.T) {
 			expectError: true,
 			errorMsg:    "WhatsApp webhook secret must be at least 32 characters long",
 		},
+		{
+			name: "production environment - short channel webhook secret",
+			config: &models.Config{
+				WhatsApp: models.WhatsAppConfig{
+					WebhookSecret: "this-is-a-very-long-webhook-secret-that-meets-requirements",
+				},
+				Channels: []models.Channel{
+					{WhatsAppSessionName: "business", WebhookSecret: "short"},
+				},
+			},
+			environment: "production",
+			expectError: true,
+			errorMsg:    "webhook secret of channel business must be at least 32 characters long",
+		},
 		{
 			name: "production environment - valid webhook secret",
 			config: &models.Config{
@@ -1171,6 +1185,24 @@ func TestApplyEnvironmentOverrides(t *testing.T) {
 	assert.Equal(t, "/env/path/to/cache", config.Media.CacheDir)
 }
 
+func TestApplyEnvironmentOverrides_ChannelWebhookSecrets(t *testing.T) {
+	config := &models.Config{
+		Channels: []models.Channel{
+			{WhatsAppSessionName: "business-main", WebhookSecret: "config-secret"},
+			{WhatsAppSessionName: "personal", WebhookSecret: "config-secret"},
+		},
+	}
+
+	assert.Equal(t, "WHATSIGNAL_WHATSAPP_WEBHOOK_SECRET_BUSINESS_MAIN", channelWebhookSecretEnv("business-main"))
+	t.Setenv("WHATSIGNAL_WHATSAPP_WEBHOOK_SECRET_BUSINESS_MAIN", "env-business-secret")
+	t.Setenv("WHATSIGNAL_WHATSAPP_WEBHOOK_SECRET_PERSONAL", "")
+
+	applyEnvironmentOverrides(config)
+
+	assert.Equal(t, "env-business-secret", config.Channels[0].WebhookSecret)
+	assert.Equal(t, "config-secret", config.Channels[1].WebhookSecret, "an unset variable keeps the configured secret")
+}
+
 func TestApplyEnvironmentOverrides_EmptyEnv(t *testing.T) {
 	config := &models.Config{
 		WhatsApp: models.WhatsAppConfig{
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"net/url"
+
+	"whatsignal/internal/models"
+)
+
+// RedactedValue replaces secrets in a redacted config
+const RedactedValue = "[redacted]"
+
+// Redacted returns a copy of c that is safe to show: webhook secrets and download header values
+// are replaced with RedactedValue and passwords in URLs are masked. The WAHA API key and the
+// encryption secrets are read from the environment and never part of the config. c is not
+// modified.
+func Redacted(c *models.Config) models.Config {
+	r := *c
+
+	r.WhatsApp.WebhookSecret = redactSecret(r.WhatsApp.WebhookSecret)
+	r.WhatsApp.APIBaseURL = redactURL(r.WhatsApp.APIBaseURL)
+	r.WhatsApp.WebhookURL = redactURL(r.WhatsApp.WebhookURL)
+	r.WhatsApp.SessionProxy = redactURL(r.WhatsApp.SessionProxy)
+	r.Signal.RPCURL = redactURL(r.Signal.RPCURL)
+	r.Tracing.OTLPEndpoint = redactURL(r.Tracing.OTLPEndpoint)
+
+	// Header values are often tokens for a proxy in front of WAHA; the names are kept
+	if c.Media.DownloadHeaders != nil {
+		r.Media.DownloadHeaders = make(map[string]string, len(c.Media.DownloadHeaders))
+		for name := range c.Media.DownloadHeaders {
+			r.Media.DownloadHeaders[name] = RedactedValue
+		}
+	}
+
+	if c.Channels != nil {
+		r.Channels = make([]models.Channel, len(c.Channels))
+		for i, channel := range c.Channels {
+			channel.WebhookSecret = redactSecret(channel.WebhookSecret)
+			r.Channels[i] = channel
+		}
+	}
+
+	return r
+}
+
+// redactSecret replaces a set secret, leaving an unset one empty so it shows as not configured
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return RedactedValue
+}
+
+// redactURL masks the password of a URL with credentials. A value that does not parse as a URL
+// is replaced whole, as it cannot be told whether it holds one.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redactSecret(rawURL)
+	}
+	if u.User == nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
@@ -145,6 +145,15 @@ func (cw *ConfigWatcher) logConfigChanges(old, new *models.Config) {
 		}).Info("Cleanup interval changed")
 	}
 
+	if old.Server.ContactRetentionDays != new.Server.ContactRetentionDays || old.Server.GroupRetentionDays != new.Server.GroupRetentionDays {
+		cw.logger.WithFields(logrus.Fields{
+			"old_contact_days": old.Server.ContactRetentionDays,
+			"new_contact_days": new.Server.ContactRetentionDays,
+			"old_group_days":   old.Server.GroupRetentionDays,
+			"new_group_days":   new.Server.GroupRetentionDays,
+		}).Info("Cache retention changed")
+	}
+
 	if len(old.Channels) != len(new.Channels) {
 		cw.logger.WithFields(logrus.Fields{
 			"old_count": len(old.Channels),
@@ -12,12 +12,35 @@ const (
 	DefaultServerPort            = 8082
 )
 
+// HTTP server values
+const (
+	DefaultWebhookPath = "/webhook/whatsapp" // Path WAHA posts webhooks to unless server.webhookPath is set
+	MaxServerPort      = 65535
+)
+
+// Signal cursor configuration values
+const (
+	MaxReactionDedupWindowSec = 600  // Upper bound for signal.reactionDedupWindowSec
+	MaxRecentReactionEntries  = 1000 // Max reactions tracked for reaction dedup
+)
+
 // Default media configuration values
 const (
-	DefaultMaxImageSizeMB    = 5
-	DefaultMaxVideoSizeMB    = 100
-	DefaultMaxDocumentSizeMB = 100
-	DefaultMaxVoiceSizeMB    = 16
+	DefaultMaxImageSizeMB         = 5
+	DefaultMaxVideoSizeMB         = 100
+	DefaultMaxDocumentSizeMB      = 100
+	DefaultMaxVoiceSizeMB         = 16
+	MaxMediaDedupWindowSec        = 3600    // Upper bound for media.dedupWindowSec
+	MaxRecentMediaEntries         = 1000    // Max (chat, content hash) pairs tracked for media dedup
+	MaxCaptionJoinWindowMs        = 10000   // Upper bound for media.captionJoinWindowMs
+	DefaultMaxConcurrentDownloads = 4       // Default cap on simultaneous media URL downloads
+	MaxConcurrentDownloadsLimit   = 64      // Upper bound for media.maxConcurrentDownloads
+	MaxDownloadResumeAttempts     = 10      // Upper bound for media.downloadResumeAttempts
+	MaxOrphanPurgeGraceHours      = 720     // Upper bound for media.orphanPurgeGraceHours
+	MaxAttachmentsPerRelayLimit   = 100     // Upper bound for media.maxAttachmentsPerRelay
+	MaxCacheSizeMBLimit           = 1048576 // Upper bound for media.maxCacheSizeMB (1 TB)
+	MaxDocumentSizeMBLimit        = 2048    // Upper bound for media.maxSizeMB.document (WhatsApp's 2 GB document limit)
+	AutoOrientJPEGQuality         = 92      // JPEG quality of images re-encoded upright by media.autoOrientImages
 )
 
 // Default timeout values
@@ -31,6 +54,13 @@ const (
 	DefaultSessionRestartTimeoutSec      = 30
 	DefaultSessionWaitTimeoutSec         = 60
 	DefaultSessionStartupTimeoutSec      = 30
+	DefaultSessionDownRepeatSec          = 900   // First reminder interval while a session stays down
+	MaxSessionDownRepeatSec              = 21600 // Reminder intervals double up to this cap
+	DefaultSessionRestartBackoffSec      = 30    // Wait after a session restart attempt before the next
+	DefaultSessionRestartBackoffMaxSec   = 1800  // Restart waits double up to this cap
+	DefaultSessionRestartResetSec        = 600   // Time WORKING before the restart wait starts over
+	MaxSessionRestartBackoffSec          = 86400 // Upper bound for the restart backoff settings
+	MaxSessionWarmupTimeoutSec           = 600   // Upper bound for whatsapp.sessionWarmupTimeoutSec
 	DefaultBackoffInitialMs              = 500
 	DefaultBackoffMaxSec                 = 5
 	DefaultContactSyncBatchSize          = 100
@@ -42,6 +72,9 @@ const (
 	DefaultServerWriteTimeoutSec         = 15
 	DefaultServerIdleTimeoutSec          = 60
 	DefaultSessionStatusTimeoutSec       = 5
+	DefaultGroupRefreshTimeoutSec        = 10
+	DefaultSignalRegistrationTimeoutSec  = 30
+	MaxSignalRegistrationBodyBytes       = 16 * 1024
 	DefaultWebhookMaxSkewSec             = 120
 	DefaultWebhookReplayBufferSec        = 30
 	DefaultWebhookMaxBytes               = 5 * 1024 * 1024
@@ -52,6 +85,7 @@ const (
 	DefaultDBConnMaxLifetimeSec          = 300 // 5 minutes
 	DefaultDBConnMaxIdleTimeSec          = 60  // 1 minute
 	DefaultMediaDownloadTimeoutSec       = 30  // 30 seconds
+	DefaultMediaCompressTimeoutSec       = 120 // Limit for one run of media.compressCommand
 	DefaultSignalHTTPTimeoutSec          = 60  // 60 seconds
 )
 
@@ -75,6 +109,28 @@ const (
 	DefaultContactCacheHours       = 24
 )
 
+// Cache retention configuration values
+const (
+	MaxCacheRetentionDays = 3650 // Upper bound for server.contactRetentionDays and server.groupRetentionDays
+)
+
+// Debug payload configuration values
+const (
+	DefaultDebugPayloadMaxFiles = 100   // Default for server.debugPayloadMaxFiles
+	MaxDebugPayloadFiles        = 10000 // Upper bound for server.debugPayloadMaxFiles
+)
+
+// Relay age configuration values
+const (
+	MaxRelayAgeMinLimit = 43200 // Upper bound for server.maxRelayAgeMin (30 days)
+)
+
+// Work queue configuration values
+const (
+	MaxQueueDepthLimit     = 10000 // Upper bound for server.maxQueueDepth
+	QueueFullRetryAfterSec = 5     // Retry-After sent with webhooks shed because the queue is full
+)
+
 // Numeric conversions
 const (
 	MillisecondsPerSecond = 1000
@@ -120,6 +176,16 @@ const (
 	WhatsAppCBResetTimeoutSec = 15 // Seconds before WhatsApp API circuit breaker attempts reset
 )
 
+// Broadcast configuration
+const (
+	BroadcastSendIntervalMs = 1000 // Minimum delay between WhatsApp sends in a /broadcast, to stay under WhatsApp rate limits
+)
+
+// Orphan reply configuration
+const (
+	MaxOrphanReplyQuoteLength = 200 // Quoted text longer than this many characters is truncated when prefixed to an orphan reply
+)
+
 // Contact/Group API circuit breaker configuration
 const (
 	ContactCBMaxFailures     = 5  // Max consecutive failures before contact/group API circuit breaker trips
@@ -145,6 +211,7 @@ const (
 // Logging configuration
 const (
 	LogBase64TruncateLength = 100 // Max characters of base64 data to include in logs
+	ResponseSnippetLength   = 200 // Max characters of an unexpected response body to include in errors
 )
 
 // Media file size configuration
@@ -174,6 +241,74 @@ const (
 	DefaultGroupCacheHours = 24 // Default group cache validity in hours
 )
 
+// Default chat label configuration
+const (
+	DefaultChatLabelsCacheSec = 300   // Default lifetime of a chat's cached WhatsApp Business labels
+	MaxChatLabelsCacheSec     = 86400 // Upper bound for whatsapp.chatLabelsCacheSec
+)
+
+// WhatsApp reaction aggregation
+const (
+	MaxReactionAggregateWindowSec = 300  // Upper bound for whatsapp.reactionAggregateWindowSec
+	MaxReactionAggregateTargets   = 1000 // Max reacted messages whose tallies are kept
+)
+
+// WhatsApp live location relay
+const (
+	MaxLiveLocationIntervalSec = 3600 // Upper bound for whatsapp.liveLocationIntervalSec
+	MinLiveLocationIdleSec     = 600  // A live location without updates for this long, or two intervals if longer, has ended
+	MaxLiveLocationShares      = 100  // Max live locations followed at once
+)
+
+// Native WhatsApp reaction relay
+const (
+	MaxNativeReactionTargets = 1000 // Max reacted messages whose Signal reactions are tracked
+)
+
+// Signal delivery receipts
+const (
+	EarlyReceiptTTLSec = 60   // How long a receipt for a message whose send is not recorded yet is held
+	MaxEarlyReceipts   = 1000 // Receipts held at once for sends not recorded yet
+)
+
+// Signal new-thread grace period
+const (
+	MaxNewThreadGraceMs        = 10000 // Upper bound for signal.newThreadGraceMs
+	NewThreadRecheckIntervalMs = 50    // How often the latest mapping is looked up again during the grace period
+)
+
+// WhatsApp media captions
+const (
+	DefaultMaxCaptionLength = 1024  // Default for whatsapp.maxCaptionLength, WhatsApp's own limit
+	MaxCaptionLengthLimit   = 65536 // Upper bound for whatsapp.maxCaptionLength
+)
+
+// Background contact refresh
+const (
+	DefaultContactRefreshPerMinute = 30  // Default for whatsapp.contactRefreshPerMinute
+	MaxContactRefreshPerMinute     = 600 // Upper bound for whatsapp.contactRefreshPerMinute
+	ContactRefreshTimeoutSec       = 15  // Time allowed for one background contact refresh
+)
+
+// Outbound typing simulation
+const (
+	MaxSimulateTypingSec = 30 // Upper bound for whatsapp.simulateTypingMaxSec
+	SignalTypingHoldSec  = 15 // A relayed Signal typing indicator is cleared after this long unless Signal repeats it
+)
+
+// Per-chat auto-replies
+const (
+	DefaultAutoReplyCooldownMin = 60    // Minimum time between auto-replies to the same chat
+	MaxAutoReplyCooldownMin     = 10080 // Upper bound for whatsapp.autoReplyCooldownMin (one week)
+)
+
+// Signal /find command
+const (
+	FindCommandHistoryLimit = 200 // Recent messages of a chat searched by /find
+	MaxFindCommandResults   = 10  // Matches listed in a /find reply
+	FindSnippetLength       = 80  // Characters of a matching message shown in a /find reply
+)
+
 // Security validation constants
 const (
 	MinWebhookSecretLength = 32 // Minimum webhook secret length for production
@@ -464,6 +464,36 @@ func (d *Database) GetStaleMessageCount(ctx context.Context, threshold time.Dura
 	return count, nil
 }
 
+// GetMappingStats returns aggregate counts of the message mappings stored for a session
+func (d *Database) GetMappingStats(ctx context.Context, sessionName string) (models.MappingStats, error) {
+	stats := models.MappingStats{ByStatus: make(map[models.DeliveryStatus]int)}
+
+	err := d.db.QueryRowContext(ctx, MappingStatsBySessionQuery, sessionName).Scan(&stats.Total, &stats.Last24h, &stats.Media)
+	if err != nil {
+		return models.MappingStats{}, fmt.Errorf("failed to count message mappings: %w", err)
+	}
+
+	rows, err := d.db.QueryContext(ctx, MappingStatusCountsBySessionQuery, sessionName)
+	if err != nil {
+		return models.MappingStats{}, fmt.Errorf("failed to count message mappings by status: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return models.MappingStats{}, fmt.Errorf("failed to scan status count: %w", err)
+		}
+		stats.ByStatus[models.DeliveryStatus(status)] = count
+	}
+	if err := rows.Err(); err != nil {
+		return models.MappingStats{}, fmt.Errorf("failed to iterate status counts: %w", err)
+	}
+
+	return stats, nil
+}
+
 func (d *Database) GetLatestMessageMappingByWhatsAppChatID(ctx context.Context, whatsappChatID string) (*models.MessageMapping, error) {
 	// Encrypt the chat ID for database query (deterministic for lookup)
 	chatHash, err := d.encryptor.LookupHash(whatsappChatID)
@@ -746,6 +776,26 @@ func (d *Database) CleanupOldRecords(ctx context.Context, retentionDays int) err
 		}
 	}
 
+	hasSeenKeysTable, err := d.tableExists(ctx, "seen_keys")
+	if err != nil {
+		return fmt.Errorf("failed to check seen keys table: %w", err)
+	}
+	if hasSeenKeysTable {
+		if _, err := d.db.ExecContext(ctx, DeleteExpiredSeenKeysQuery, time.Now().UnixMilli()); err != nil {
+			return fmt.Errorf("failed to cleanup expired seen keys: %w", err)
+		}
+	}
+
+	hasEditsTable, err := d.tableExists(ctx, "message_edits")
+	if err != nil {
+		return fmt.Errorf("failed to check message edits table: %w", err)
+	}
+	if hasEditsTable {
+		if _, err := d.db.ExecContext(ctx, DeleteOldMessageEditsQuery, retentionDays); err != nil {
+			return fmt.Errorf("failed to cleanup old message edits: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -1038,6 +1088,279 @@ func (d *Database) CleanupOldGroups(ctx context.Context, retentionDays int) erro
 	return nil
 }
 
+// Chat settings operations
+
+// SetChatPaused pauses or resumes relaying for a chat within a session
+func (d *Database) SetChatPaused(ctx context.Context, sessionName, chatID string, paused bool) error {
+	encryptedChatID, err := d.encryptor.EncryptForLookupIfEnabled(chatID)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt chat ID: %w", err)
+	}
+
+	if _, err := d.db.ExecContext(ctx, UpsertChatPausedQuery, sessionName, encryptedChatID, paused); err != nil {
+		return fmt.Errorf("failed to save chat settings: %w", err)
+	}
+
+	return nil
+}
+
+// IsChatPaused reports whether relaying is paused for a chat within a session
+func (d *Database) IsChatPaused(ctx context.Context, sessionName, chatID string) (bool, error) {
+	encryptedChatID, err := d.encryptor.EncryptForLookupIfEnabled(chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to encrypt chat ID: %w", err)
+	}
+
+	var paused bool
+	err = d.db.QueryRowContext(ctx, SelectChatPausedQuery, sessionName, encryptedChatID).Scan(&paused)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to query chat settings: %w", err)
+	}
+
+	return paused, nil
+}
+
+// SetChatAutoReply sets the message automatically sent back to a chat within a session. An empty
+// message turns auto-replies off. Changing the message resets the cooldown.
+func (d *Database) SetChatAutoReply(ctx context.Context, sessionName, chatID, message string) error {
+	encryptedChatID, err := d.encryptor.EncryptForLookupIfEnabled(chatID)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt chat ID: %w", err)
+	}
+
+	encryptedMessage := ""
+	if message != "" {
+		encryptedMessage, err = d.encryptor.EncryptIfEnabled(message)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt auto-reply: %w", err)
+		}
+	}
+
+	if _, err := d.db.ExecContext(ctx, UpsertChatAutoReplyQuery, sessionName, encryptedChatID, encryptedMessage); err != nil {
+		return fmt.Errorf("failed to save chat settings: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimChatAutoReply returns the auto-reply of a chat within a session if one is set and it was
+// last sent at least cooldown before now, recording now as its send time. It returns "" when no
+// reply is due, so concurrent messages from the same chat trigger at most one reply.
+func (d *Database) ClaimChatAutoReply(ctx context.Context, sessionName, chatID string, now time.Time, cooldown time.Duration) (string, error) {
+	encryptedChatID, err := d.encryptor.EncryptForLookupIfEnabled(chatID)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt chat ID: %w", err)
+	}
+
+	var encryptedMessage string
+	err = d.db.QueryRowContext(ctx, ClaimChatAutoReplyQuery, now.Unix(), sessionName, encryptedChatID, now.Add(-cooldown).Unix()).Scan(&encryptedMessage)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to claim chat auto-reply: %w", err)
+	}
+
+	message, err := d.encryptor.DecryptIfEnabled(encryptedMessage)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt auto-reply: %w", err)
+	}
+	return message, nil
+}
+
+// Signal cursor operations
+
+// SetSignalCursor records the newest processed Signal envelope timestamp for an account.
+// The stored cursor never moves backwards.
+func (d *Database) SetSignalCursor(ctx context.Context, account string, timestamp int64) error {
+	encryptedAccount, err := d.encryptor.EncryptForLookupIfEnabled(account)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt account: %w", err)
+	}
+
+	if _, err := d.db.ExecContext(ctx, UpsertSignalCursorQuery, encryptedAccount, timestamp); err != nil {
+		return fmt.Errorf("failed to save signal cursor: %w", err)
+	}
+
+	return nil
+}
+
+// GetSignalCursor returns the newest processed Signal envelope timestamp for an account,
+// or 0 if none has been recorded
+func (d *Database) GetSignalCursor(ctx context.Context, account string) (int64, error) {
+	encryptedAccount, err := d.encryptor.EncryptForLookupIfEnabled(account)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt account: %w", err)
+	}
+
+	var timestamp int64
+	err = d.db.QueryRowContext(ctx, SelectSignalCursorQuery, encryptedAccount).Scan(&timestamp)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to query signal cursor: %w", err)
+	}
+
+	return timestamp, nil
+}
+
+// Seen key operations
+
+// RecordSeen remembers key as seen for ttl, replacing any earlier expiry. Only a lookup hash of
+// the key is stored.
+func (d *Database) RecordSeen(ctx context.Context, key string, ttl time.Duration) error {
+	keyHash, err := d.encryptor.LookupHash(key)
+	if err != nil {
+		return fmt.Errorf("failed to compute seen key hash: %w", err)
+	}
+
+	expiresAt := time.Now().Add(ttl).UnixMilli()
+	if _, err := d.db.ExecContext(ctx, UpsertSeenKeyQuery, keyHash, expiresAt); err != nil {
+		return fmt.Errorf("failed to record seen key: %w", err)
+	}
+	return nil
+}
+
+// WasSeen reports whether key was recorded by RecordSeen and has not expired
+func (d *Database) WasSeen(ctx context.Context, key string) (bool, error) {
+	keyHash, err := d.encryptor.LookupHash(key)
+	if err != nil {
+		return false, fmt.Errorf("failed to compute seen key hash: %w", err)
+	}
+
+	var found int
+	err = d.db.QueryRowContext(ctx, SelectSeenKeyQuery, keyHash, time.Now().UnixMilli()).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to query seen key: %w", err)
+	}
+	return true, nil
+}
+
+// Message edit operations
+
+// SaveMessageEdit records an edit of a bridged WhatsApp message. The body is encrypted; the
+// message ID is stored as a lookup hash.
+func (d *Database) SaveMessageEdit(ctx context.Context, edit *models.MessageEdit) error {
+	msgIDHash, err := d.encryptor.LookupHash(edit.WhatsAppMsgID)
+	if err != nil {
+		return fmt.Errorf("failed to compute message ID hash: %w", err)
+	}
+	encryptedBody, err := d.encryptor.EncryptIfEnabled(edit.Body)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt edit body: %w", err)
+	}
+
+	editedAt := edit.EditedAt
+	if editedAt.IsZero() {
+		editedAt = time.Now()
+	}
+
+	result, err := d.db.ExecContext(ctx, InsertMessageEditQuery, msgIDHash, edit.SessionName, encryptedBody, editedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save message edit: %w", err)
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		edit.ID = id
+	}
+	return nil
+}
+
+// GetMessageEdits returns the recorded edits of a WhatsApp message, oldest first
+func (d *Database) GetMessageEdits(ctx context.Context, whatsappMsgID string) ([]models.MessageEdit, error) {
+	msgIDHash, err := d.encryptor.LookupHash(whatsappMsgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute message ID hash: %w", err)
+	}
+
+	rows, err := d.db.QueryContext(ctx, SelectMessageEditsQuery, msgIDHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query message edits: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	edits := []models.MessageEdit{}
+	for rows.Next() {
+		edit := models.MessageEdit{WhatsAppMsgID: whatsappMsgID}
+		var encryptedBody string
+		if err := rows.Scan(&edit.ID, &edit.SessionName, &encryptedBody, &edit.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message edit: %w", err)
+		}
+		if edit.Body, err = d.encryptor.DecryptIfEnabled(encryptedBody); err != nil {
+			return nil, fmt.Errorf("failed to decrypt edit body: %w", err)
+		}
+		edits = append(edits, edit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read message edits: %w", err)
+	}
+
+	return edits, nil
+}
+
+// Group mapping operations
+
+// SaveGroupMapping routes a Signal group to a WhatsApp group within a session, replacing the
+// group's previous target. Both group IDs are encrypted; the Signal group ID is looked up by hash.
+func (d *Database) SaveGroupMapping(ctx context.Context, mapping *models.GroupMapping) error {
+	if mapping.SessionName == "" {
+		return fmt.Errorf("session name is required in group mapping")
+	}
+
+	encryptedSignalGroupID, err := d.encryptor.EncryptIfEnabled(mapping.SignalGroupID)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt Signal group ID: %w", err)
+	}
+	signalGroupHash, err := d.encryptor.LookupHash(mapping.SignalGroupID)
+	if err != nil {
+		return fmt.Errorf("failed to compute Signal group ID hash: %w", err)
+	}
+	encryptedWhatsAppGroupID, err := d.encryptor.EncryptIfEnabled(mapping.WhatsAppGroupID)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt WhatsApp group ID: %w", err)
+	}
+
+	if _, err := d.db.ExecContext(ctx, UpsertGroupMappingQuery, mapping.SessionName, encryptedSignalGroupID, signalGroupHash, encryptedWhatsAppGroupID); err != nil {
+		return fmt.Errorf("failed to save group mapping: %w", err)
+	}
+	return nil
+}
+
+// GetGroupMapping returns the WhatsApp group a Signal group is routed to within a session, or
+// nil if the group is not mapped
+func (d *Database) GetGroupMapping(ctx context.Context, sessionName, signalGroupID string) (*models.GroupMapping, error) {
+	signalGroupHash, err := d.encryptor.LookupHash(signalGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute Signal group ID hash: %w", err)
+	}
+
+	mapping := &models.GroupMapping{SessionName: sessionName, SignalGroupID: signalGroupID}
+	var encryptedWhatsAppGroupID string
+	err = d.db.QueryRowContext(ctx, SelectGroupMappingQuery, sessionName, signalGroupHash).Scan(
+		&mapping.ID,
+		&encryptedWhatsAppGroupID,
+		&mapping.CreatedAt,
+		&mapping.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group mapping: %w", err)
+	}
+
+	if mapping.WhatsAppGroupID, err = d.encryptor.DecryptIfEnabled(encryptedWhatsAppGroupID); err != nil {
+		return nil, fmt.Errorf("failed to decrypt WhatsApp group ID: %w", err)
+	}
+	return mapping, nil
+}
+
 // HasMessageHistoryBetween checks if there's any message history between a session and Signal sender
 func (d *Database) HasMessageHistoryBetween(ctx context.Context, sessionName, signalSender string) (bool, error) {
 	if sessionName == "" {
@@ -1069,6 +1392,62 @@ func (d *Database) HasMessageHistoryBetween(ctx context.Context, sessionName, si
 	return exists, nil
 }
 
+// GetMappingSessionsForChat returns the sessions that have bridged messages in a WhatsApp chat,
+// the session with the most recent activity first
+func (d *Database) GetMappingSessionsForChat(ctx context.Context, chatID string) ([]string, error) {
+	chatHash, err := d.encryptor.LookupHash(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute chat ID hash: %w", err)
+	}
+
+	rows, err := d.db.QueryContext(ctx, SelectMappingSessionsForChatQuery, chatHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions for chat: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []string
+	for rows.Next() {
+		var session string
+		if err := rows.Scan(&session); err != nil {
+			return nil, fmt.Errorf("failed to scan session for chat: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read sessions for chat: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// GetMediaPaths returns the media paths of all message mappings that have one
+func (d *Database) GetMediaPaths(ctx context.Context) ([]string, error) {
+	rows, err := d.db.QueryContext(ctx, SelectMediaPathsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query media paths: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var paths []string
+	for rows.Next() {
+		var encryptedPath string
+		if err := rows.Scan(&encryptedPath); err != nil {
+			return nil, fmt.Errorf("failed to scan media path: %w", err)
+		}
+		path, err := d.encryptor.DecryptIfEnabled(encryptedPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt media path: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read media paths: %w", err)
+	}
+
+	return paths, nil
+}
+
 // HealthCheck performs a database health check by pinging the database connection
 func (d *Database) HealthCheck(ctx context.Context) error {
 	if d.db == nil {
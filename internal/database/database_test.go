@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -156,6 +157,80 @@ CREATE INDEX IF NOT EXISTS idx_contact_short_name_hash ON contacts(short_name_ha
 	err = os.WriteFile(filepath.Join(migrationsPath, "004_add_contact_name_hashes.sql"), []byte(nameHashContent), 0644)
 	require.NoError(t, err)
 
+	// Create migration 007 for per-chat settings
+	chatSettingsContent := `-- Add chat_settings table for per-chat bridge settings
+CREATE TABLE IF NOT EXISTS chat_settings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_name TEXT NOT NULL,
+    chat_id TEXT NOT NULL,
+    paused BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(session_name, chat_id)
+);`
+
+	err = os.WriteFile(filepath.Join(migrationsPath, "007_add_chat_settings.sql"), []byte(chatSettingsContent), 0644)
+	require.NoError(t, err)
+
+	// Create migration 008 for the Signal cursor
+	signalCursorContent := `-- Add signal_cursor table
+CREATE TABLE IF NOT EXISTS signal_cursor (
+    account TEXT PRIMARY KEY,
+    last_timestamp INTEGER NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`
+
+	err = os.WriteFile(filepath.Join(migrationsPath, "008_add_signal_cursor.sql"), []byte(signalCursorContent), 0644)
+	require.NoError(t, err)
+
+	// Create migration 009 for message edit history
+	messageEditsContent := `-- Add message_edits table
+CREATE TABLE IF NOT EXISTS message_edits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    whatsapp_msg_id_hash TEXT NOT NULL,
+    session_name TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    edited_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`
+
+	err = os.WriteFile(filepath.Join(migrationsPath, "009_add_message_edits.sql"), []byte(messageEditsContent), 0644)
+	require.NoError(t, err)
+
+	// Create migration 010 for per-chat auto-replies
+	chatAutoReplyContent := `-- Add auto-reply columns to chat_settings
+ALTER TABLE chat_settings ADD COLUMN auto_reply TEXT NOT NULL DEFAULT '';
+ALTER TABLE chat_settings ADD COLUMN auto_reply_sent_at INTEGER NOT NULL DEFAULT 0;`
+
+	err = os.WriteFile(filepath.Join(migrationsPath, "010_add_chat_auto_reply.sql"), []byte(chatAutoReplyContent), 0644)
+	require.NoError(t, err)
+
+	// Create migration 011 for persisted dedup keys
+	seenKeysContent := `-- Add seen_keys table
+CREATE TABLE IF NOT EXISTS seen_keys (
+    key_hash TEXT PRIMARY KEY,
+    expires_at INTEGER NOT NULL
+);`
+
+	err = os.WriteFile(filepath.Join(migrationsPath, "011_add_seen_keys.sql"), []byte(seenKeysContent), 0644)
+	require.NoError(t, err)
+
+	// Create migration 012 for Signal group routing
+	groupMappingsContent := `-- Add group_mappings table
+CREATE TABLE IF NOT EXISTS group_mappings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_name TEXT NOT NULL,
+    signal_group_id TEXT NOT NULL,
+    signal_group_id_hash TEXT NOT NULL,
+    whatsapp_group_id TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(session_name, signal_group_id_hash)
+);`
+
+	err = os.WriteFile(filepath.Join(migrationsPath, "012_add_group_mappings.sql"), []byte(groupMappingsContent), 0644)
+	require.NoError(t, err)
+
 	return migrationsPath
 }
 
@@ -1469,6 +1544,73 @@ func TestDatabase_HasMessageHistoryBetween(t *testing.T) {
 	assert.False(t, hasHistory) // No messages for "default" session
 }
 
+func TestDatabase_GetMappingSessionsForChat(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	sessions, err := db.GetMappingSessionsForChat(ctx, "+1234567890@c.us")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	now := time.Now()
+	for i, m := range []struct {
+		session string
+		age     time.Duration
+	}{
+		{"personal", 3 * time.Hour},
+		{"business", time.Hour},
+		{"personal", 2 * time.Hour},
+	} {
+		err := db.SaveMessageMapping(ctx, &models.MessageMapping{
+			WhatsAppChatID:  "+1234567890@c.us",
+			WhatsAppMsgID:   fmt.Sprintf("wa_msg_%d", i),
+			SignalMsgID:     fmt.Sprintf("sig_msg_%d", i),
+			SessionName:     m.session,
+			SignalTimestamp: now.Add(-m.age),
+			ForwardedAt:     now.Add(-m.age),
+			DeliveryStatus:  models.DeliveryStatusSent,
+		})
+		require.NoError(t, err)
+	}
+
+	sessions, err = db.GetMappingSessionsForChat(ctx, "+1234567890@c.us")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"business", "personal"}, sessions, "most recent activity first")
+
+	sessions, err = db.GetMappingSessionsForChat(ctx, "+9876543210@c.us")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestDatabase_GetMediaPaths(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	for i, mediaPath := range []string{"/cache/photo.jpg", "", "/cache/clip.mp4"} {
+		mapping := &models.MessageMapping{
+			WhatsAppChatID:  "+1234567890@c.us",
+			WhatsAppMsgID:   fmt.Sprintf("wa_msg_%d", i),
+			SignalMsgID:     fmt.Sprintf("sig_msg_%d", i),
+			SessionName:     "default",
+			SignalTimestamp: now,
+			ForwardedAt:     now,
+			DeliveryStatus:  models.DeliveryStatusSent,
+		}
+		if mediaPath != "" {
+			mapping.MediaPath = &mediaPath
+		}
+		require.NoError(t, db.SaveMessageMapping(ctx, mapping))
+	}
+
+	paths, err := db.GetMediaPaths(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"/cache/photo.jpg", "/cache/clip.mp4"}, paths, "paths are decrypted and mappings without media skipped")
+}
+
 func TestDatabase_New_ErrorCases(t *testing.T) {
 	// Set up encryption secret for tests
 	t.Setenv("WHATSIGNAL_ENCRYPTION_SECRET", "this-is-a-very-long-test-secret-key-for-database-testing")
@@ -1777,6 +1919,201 @@ func TestDatabase_SaveGroup(t *testing.T) {
 	assert.Equal(t, originalID, updatedID)
 }
 
+func TestDatabase_ChatPaused(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	paused, err := db.IsChatPaused(ctx, "default", "123@c.us")
+	require.NoError(t, err)
+	assert.False(t, paused, "chats without settings are not paused")
+
+	require.NoError(t, db.SetChatPaused(ctx, "default", "123@c.us", true))
+
+	paused, err = db.IsChatPaused(ctx, "default", "123@c.us")
+	require.NoError(t, err)
+	assert.True(t, paused)
+
+	paused, err = db.IsChatPaused(ctx, "other", "123@c.us")
+	require.NoError(t, err)
+	assert.False(t, paused, "paused state is scoped to the session")
+
+	require.NoError(t, db.SetChatPaused(ctx, "default", "123@c.us", false))
+
+	paused, err = db.IsChatPaused(ctx, "default", "123@c.us")
+	require.NoError(t, err)
+	assert.False(t, paused)
+
+	var rows int
+	require.NoError(t, db.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM chat_settings").Scan(&rows))
+	assert.Equal(t, 1, rows)
+}
+
+func TestDatabase_ChatAutoReply(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	cooldown := time.Hour
+
+	message, err := db.ClaimChatAutoReply(ctx, "default", "123@c.us", now, cooldown)
+	require.NoError(t, err)
+	assert.Empty(t, message, "chats without settings have no auto-reply")
+
+	require.NoError(t, db.SetChatPaused(ctx, "default", "123@c.us", true))
+	require.NoError(t, db.SetChatAutoReply(ctx, "default", "123@c.us", "Away until Monday"))
+
+	message, err = db.ClaimChatAutoReply(ctx, "default", "123@c.us", now, cooldown)
+	require.NoError(t, err)
+	assert.Equal(t, "Away until Monday", message)
+
+	paused, err := db.IsChatPaused(ctx, "default", "123@c.us")
+	require.NoError(t, err)
+	assert.True(t, paused, "setting an auto-reply keeps the paused state")
+
+	message, err = db.ClaimChatAutoReply(ctx, "default", "123@c.us", now.Add(time.Minute), cooldown)
+	require.NoError(t, err)
+	assert.Empty(t, message, "auto-reply is not sent again within the cooldown")
+
+	message, err = db.ClaimChatAutoReply(ctx, "other", "123@c.us", now, cooldown)
+	require.NoError(t, err)
+	assert.Empty(t, message, "auto-reply is scoped to the session")
+
+	message, err = db.ClaimChatAutoReply(ctx, "default", "123@c.us", now.Add(cooldown), cooldown)
+	require.NoError(t, err)
+	assert.Equal(t, "Away until Monday", message, "auto-reply is sent again once the cooldown elapsed")
+
+	require.NoError(t, db.SetChatAutoReply(ctx, "default", "123@c.us", "Back at 9"))
+	message, err = db.ClaimChatAutoReply(ctx, "default", "123@c.us", now.Add(cooldown), cooldown)
+	require.NoError(t, err)
+	assert.Equal(t, "Back at 9", message, "changing the auto-reply resets the cooldown")
+
+	var stored string
+	require.NoError(t, db.db.QueryRowContext(ctx, "SELECT auto_reply FROM chat_settings").Scan(&stored))
+	assert.NotEqual(t, "Back at 9", stored, "auto-reply is stored encrypted")
+
+	require.NoError(t, db.SetChatAutoReply(ctx, "default", "123@c.us", ""))
+	message, err = db.ClaimChatAutoReply(ctx, "default", "123@c.us", now.Add(48*time.Hour), cooldown)
+	require.NoError(t, err)
+	assert.Empty(t, message, "an empty auto-reply turns it off")
+}
+
+func TestDatabase_GetMappingStats(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mediaPath := "/cache/photo.jpg"
+	mappings := []*models.MessageMapping{
+		{WhatsAppMsgID: "wa1", DeliveryStatus: models.DeliveryStatusSent, SessionName: "default"},
+		{WhatsAppMsgID: "wa2", DeliveryStatus: models.DeliveryStatusRead, SessionName: "default", MediaPath: &mediaPath, MediaType: "image"},
+		{WhatsAppMsgID: "wa3", DeliveryStatus: models.DeliveryStatusRead, SessionName: "default"},
+		{WhatsAppMsgID: "wa4", DeliveryStatus: models.DeliveryStatusSent, SessionName: "other", MediaPath: &mediaPath},
+	}
+	for i, m := range mappings {
+		m.WhatsAppChatID = "123@c.us"
+		m.SignalMsgID = fmt.Sprintf("sig%d", i)
+		m.SignalTimestamp = time.Now()
+		m.ForwardedAt = time.Now()
+		require.NoError(t, db.SaveMessageMapping(ctx, m))
+	}
+	_, err := db.db.ExecContext(ctx, "UPDATE message_mappings SET created_at = datetime('now', '-2 days') WHERE signal_msg_id_hash = ?", mustLookupHash(t, db, "sig0"))
+	require.NoError(t, err)
+
+	stats, err := db.GetMappingStats(ctx, "default")
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Total)
+	assert.Equal(t, 2, stats.Last24h)
+	assert.Equal(t, 1, stats.Media)
+	assert.Equal(t, map[models.DeliveryStatus]int{
+		models.DeliveryStatusSent: 1,
+		models.DeliveryStatusRead: 2,
+	}, stats.ByStatus)
+
+	stats, err = db.GetMappingStats(ctx, "empty")
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Total)
+	assert.Equal(t, 0, stats.Last24h)
+	assert.Empty(t, stats.ByStatus)
+}
+
+func mustLookupHash(t *testing.T, db *Database, value string) string {
+	t.Helper()
+	hash, err := db.encryptor.LookupHash(value)
+	require.NoError(t, err)
+	return hash
+}
+
+func TestDatabase_SignalCursor(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	cursor, err := db.GetSignalCursor(ctx, "+1111111111")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), cursor)
+
+	require.NoError(t, db.SetSignalCursor(ctx, "+1111111111", 1700000000000))
+	cursor, err = db.GetSignalCursor(ctx, "+1111111111")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000000), cursor)
+
+	// Cursor advances
+	require.NoError(t, db.SetSignalCursor(ctx, "+1111111111", 1700000005000))
+	cursor, err = db.GetSignalCursor(ctx, "+1111111111")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000005000), cursor)
+
+	// Cursor never moves backwards
+	require.NoError(t, db.SetSignalCursor(ctx, "+1111111111", 1600000000000))
+	cursor, err = db.GetSignalCursor(ctx, "+1111111111")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000005000), cursor)
+
+	// Cursors are per account
+	cursor, err = db.GetSignalCursor(ctx, "+2222222222")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), cursor)
+}
+
+func TestDatabase_SeenKeys(t *testing.T) {
+	db, tmpDir, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	seen, err := db.WasSeen(ctx, "default|1700000000000|👍|+1111111111|false")
+	require.NoError(t, err)
+	assert.False(t, seen)
+
+	require.NoError(t, db.RecordSeen(ctx, "default|1700000000000|👍|+1111111111|false", time.Hour))
+	require.NoError(t, db.RecordSeen(ctx, "short-lived", 50*time.Millisecond))
+
+	// Keys survive a restart
+	require.NoError(t, db.Close())
+	db, err = New(filepath.Join(tmpDir, "test.db"), nil)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	seen, err = db.WasSeen(ctx, "default|1700000000000|👍|+1111111111|false")
+	require.NoError(t, err)
+	assert.True(t, seen)
+
+	// Keys expire after their TTL and are removed by cleanup
+	time.Sleep(100 * time.Millisecond)
+	seen, err = db.WasSeen(ctx, "short-lived")
+	require.NoError(t, err)
+	assert.False(t, seen)
+
+	require.NoError(t, db.CleanupOldRecords(ctx, 30))
+	var count int
+	require.NoError(t, db.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM seen_keys").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
 func TestHasMessageHistoryBetweenUsesExistsQuery(t *testing.T) {
 	assert.Contains(t, HasMessageHistoryBetweenQuery, "SELECT EXISTS")
 	assert.NotContains(t, HasMessageHistoryBetweenQuery, "COUNT(*)")
@@ -2180,3 +2517,94 @@ func TestHealthCheck(t *testing.T) {
 	err := db.HealthCheck(ctx)
 	assert.NoError(t, err)
 }
+
+func TestDatabase_MessageEdits(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	edits, err := db.GetMessageEdits(ctx, "wamid.edited")
+	require.NoError(t, err)
+	assert.Empty(t, edits)
+
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, body := range []string{"first fix", "second fix", "final text"} {
+		edit := &models.MessageEdit{
+			WhatsAppMsgID: "wamid.edited",
+			SessionName:   "default",
+			Body:          body,
+			EditedAt:      base.Add(time.Duration(i) * time.Minute),
+		}
+		require.NoError(t, db.SaveMessageEdit(ctx, edit))
+		assert.NotZero(t, edit.ID)
+	}
+	require.NoError(t, db.SaveMessageEdit(ctx, &models.MessageEdit{WhatsAppMsgID: "wamid.other", Body: "unrelated"}))
+
+	edits, err = db.GetMessageEdits(ctx, "wamid.edited")
+	require.NoError(t, err)
+	require.Len(t, edits, 3)
+	for i, body := range []string{"first fix", "second fix", "final text"} {
+		assert.Equal(t, body, edits[i].Body)
+		assert.Equal(t, "wamid.edited", edits[i].WhatsAppMsgID)
+		assert.Equal(t, "default", edits[i].SessionName)
+		assert.True(t, base.Add(time.Duration(i)*time.Minute).Equal(edits[i].EditedAt))
+	}
+
+	// Bodies are not stored in plaintext
+	var stored string
+	require.NoError(t, db.db.QueryRowContext(ctx, "SELECT body FROM message_edits WHERE id = ?", edits[0].ID).Scan(&stored))
+	assert.NotEqual(t, "first fix", stored)
+}
+
+func TestDatabase_GroupMappings(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const signalGroupID = "c2lnbmFsLWdyb3VwLWlk"
+
+	mapping, err := db.GetGroupMapping(ctx, "default", signalGroupID)
+	require.NoError(t, err)
+	assert.Nil(t, mapping, "unmapped groups return nil")
+
+	require.NoError(t, db.SaveGroupMapping(ctx, &models.GroupMapping{
+		SessionName:     "default",
+		SignalGroupID:   signalGroupID,
+		WhatsAppGroupID: "120363028123456789@g.us",
+	}))
+
+	mapping, err = db.GetGroupMapping(ctx, "default", signalGroupID)
+	require.NoError(t, err)
+	require.NotNil(t, mapping)
+	assert.NotZero(t, mapping.ID)
+	assert.Equal(t, "default", mapping.SessionName)
+	assert.Equal(t, signalGroupID, mapping.SignalGroupID)
+	assert.Equal(t, "120363028123456789@g.us", mapping.WhatsAppGroupID)
+
+	mapping, err = db.GetGroupMapping(ctx, "other", signalGroupID)
+	require.NoError(t, err)
+	assert.Nil(t, mapping, "group mappings are scoped to the session")
+
+	// Saving again retargets the group
+	require.NoError(t, db.SaveGroupMapping(ctx, &models.GroupMapping{
+		SessionName:     "default",
+		SignalGroupID:   signalGroupID,
+		WhatsAppGroupID: "120363099999999999@g.us",
+	}))
+	mapping, err = db.GetGroupMapping(ctx, "default", signalGroupID)
+	require.NoError(t, err)
+	require.NotNil(t, mapping)
+	assert.Equal(t, "120363099999999999@g.us", mapping.WhatsAppGroupID)
+
+	// Group IDs are not stored in plaintext
+	var rows int
+	var storedSignal, storedWhatsApp string
+	require.NoError(t, db.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM group_mappings").Scan(&rows))
+	assert.Equal(t, 1, rows)
+	require.NoError(t, db.db.QueryRowContext(ctx, "SELECT signal_group_id, whatsapp_group_id FROM group_mappings").Scan(&storedSignal, &storedWhatsApp))
+	assert.NotEqual(t, signalGroupID, storedSignal)
+	assert.NotEqual(t, "120363099999999999@g.us", storedWhatsApp)
+
+	assert.Error(t, db.SaveGroupMapping(ctx, &models.GroupMapping{SignalGroupID: signalGroupID, WhatsAppGroupID: "1@g.us"}), "session name is required")
+}
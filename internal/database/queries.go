@@ -99,6 +99,21 @@ const (
 		  AND forwarded_at < datetime('now', '-' || ? || ' seconds')
 	`
 
+	MappingStatsBySessionQuery = `
+		SELECT COUNT(*),
+		       COALESCE(SUM(CASE WHEN created_at >= datetime('now', '-1 day') THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN media_path IS NOT NULL THEN 1 ELSE 0 END), 0)
+		FROM message_mappings
+		WHERE session_name = ?
+	`
+
+	MappingStatusCountsBySessionQuery = `
+		SELECT delivery_status, COUNT(*)
+		FROM message_mappings
+		WHERE session_name = ?
+		GROUP BY delivery_status
+	`
+
 	HasMessageHistoryBetweenQuery = `
 		SELECT EXISTS(
 			SELECT 1
@@ -106,6 +121,20 @@ const (
 			WHERE session_name = ? AND chat_id_hash = ?
 		)
 	`
+
+	SelectMappingSessionsForChatQuery = `
+		SELECT session_name
+		FROM message_mappings
+		WHERE chat_id_hash = ? AND session_name != ''
+		GROUP BY session_name
+		ORDER BY MAX(forwarded_at) DESC
+	`
+
+	SelectMediaPathsQuery = `
+		SELECT DISTINCT media_path
+		FROM message_mappings
+		WHERE media_path IS NOT NULL AND media_path != ''
+	`
 )
 
 // Contact queries
@@ -211,3 +240,109 @@ const (
 		WHERE message_id_hash = ? AND destination = ?
 	`
 )
+
+// Chat settings queries
+const (
+	UpsertChatPausedQuery = `
+		INSERT INTO chat_settings (session_name, chat_id, paused)
+		VALUES (?, ?, ?)
+		ON CONFLICT(session_name, chat_id) DO UPDATE SET
+			paused = excluded.paused
+	`
+
+	SelectChatPausedQuery = `
+		SELECT paused
+		FROM chat_settings
+		WHERE session_name = ? AND chat_id = ?
+	`
+
+	UpsertChatAutoReplyQuery = `
+		INSERT INTO chat_settings (session_name, chat_id, auto_reply, auto_reply_sent_at)
+		VALUES (?, ?, ?, 0)
+		ON CONFLICT(session_name, chat_id) DO UPDATE SET
+			auto_reply = excluded.auto_reply,
+			auto_reply_sent_at = 0
+	`
+
+	ClaimChatAutoReplyQuery = `
+		UPDATE chat_settings
+		SET auto_reply_sent_at = ?
+		WHERE session_name = ? AND chat_id = ? AND auto_reply != '' AND auto_reply_sent_at <= ?
+		RETURNING auto_reply
+	`
+)
+
+// Signal cursor queries
+const (
+	UpsertSignalCursorQuery = `
+		INSERT INTO signal_cursor (account, last_timestamp, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(account) DO UPDATE SET
+			last_timestamp = MAX(last_timestamp, excluded.last_timestamp),
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	SelectSignalCursorQuery = `
+		SELECT last_timestamp
+		FROM signal_cursor
+		WHERE account = ?
+	`
+)
+
+// Seen key queries
+const (
+	UpsertSeenKeyQuery = `
+		INSERT INTO seen_keys (key_hash, expires_at)
+		VALUES (?, ?)
+		ON CONFLICT(key_hash) DO UPDATE SET
+			expires_at = excluded.expires_at
+	`
+
+	SelectSeenKeyQuery = `
+		SELECT 1
+		FROM seen_keys
+		WHERE key_hash = ? AND expires_at > ?
+	`
+
+	DeleteExpiredSeenKeysQuery = `
+		DELETE FROM seen_keys
+		WHERE expires_at <= ?
+	`
+)
+
+// Message edit queries
+const (
+	InsertMessageEditQuery = `
+		INSERT INTO message_edits (whatsapp_msg_id_hash, session_name, body, edited_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	SelectMessageEditsQuery = `
+		SELECT id, session_name, body, edited_at
+		FROM message_edits
+		WHERE whatsapp_msg_id_hash = ?
+		ORDER BY id ASC
+	`
+
+	DeleteOldMessageEditsQuery = `
+		DELETE FROM message_edits
+		WHERE created_at < datetime('now', '-' || ? || ' days')
+	`
+)
+
+// Group mapping queries
+const (
+	UpsertGroupMappingQuery = `
+		INSERT INTO group_mappings (session_name, signal_group_id, signal_group_id_hash, whatsapp_group_id)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(session_name, signal_group_id_hash) DO UPDATE SET
+			signal_group_id = excluded.signal_group_id,
+			whatsapp_group_id = excluded.whatsapp_group_id
+	`
+
+	SelectGroupMappingQuery = `
+		SELECT id, whatsapp_group_id, created_at, updated_at
+		FROM group_mappings
+		WHERE session_name = ? AND signal_group_id_hash = ?
+	`
+)
@@ -0,0 +1,344 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"whatsignal/internal/models"
+)
+
+// unsafeShardNameChars matches the characters of a session name not used in a shard's file name
+var unsafeShardNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// ShardedDatabase keeps the message mappings, groups, group mappings and chat settings of each
+// session in a database file of its own, so one busy session does not hold the write lock of
+// the others.
+// Contacts, the Signal cursor, pending Signal messages and edit history are shared by all
+// sessions and stay in the main file, as do the records of sessions without a shard.
+//
+// Queries for a session go to its shard. Queries by message or chat ID, which can belong to
+// any session, are asked of every file.
+type ShardedDatabase struct {
+	*Database
+	shards map[string]*Database
+	// order lists the shard sessions in the order they were opened, so fan-out is stable
+	order []string
+}
+
+// ShardPath returns the path of the shard for sessionName next to the main database at dbPath:
+// whatsignal.db becomes whatsignal-<session>.db
+func ShardPath(dbPath, sessionName string) string {
+	ext := filepath.Ext(dbPath)
+	base := strings.TrimSuffix(dbPath, ext)
+	return base + "-" + unsafeShardNameChars.ReplaceAllString(sessionName, "_") + ext
+}
+
+// NewSharded opens the main database at dbPath and a shard next to it for each of sessions
+func NewSharded(dbPath string, cfg *models.DatabaseConfig, sessions []string) (*ShardedDatabase, error) {
+	shared, err := New(dbPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ShardedDatabase{Database: shared, shards: make(map[string]*Database, len(sessions))}
+	paths := map[string]string{dbPath: ""}
+	for _, session := range sessions {
+		if _, ok := s.shards[session]; ok {
+			continue
+		}
+		path := ShardPath(dbPath, session)
+		if other, ok := paths[path]; ok {
+			_ = s.Close()
+			return nil, fmt.Errorf("sessions %q and %q map to the same database file %s", other, session, path)
+		}
+		paths[path] = session
+
+		shard, err := New(path, cfg)
+		if err != nil {
+			_ = s.Close()
+			return nil, fmt.Errorf("failed to open database for session %s: %w", session, err)
+		}
+		s.shards[session] = shard
+		s.order = append(s.order, session)
+	}
+
+	return s, nil
+}
+
+// forSession returns the database holding the records of sessionName
+func (s *ShardedDatabase) forSession(sessionName string) *Database {
+	if shard, ok := s.shards[sessionName]; ok {
+		return shard
+	}
+	return s.Database
+}
+
+// all returns the main database followed by every shard
+func (s *ShardedDatabase) all() []*Database {
+	dbs := make([]*Database, 0, len(s.order)+1)
+	dbs = append(dbs, s.Database)
+	for _, session := range s.order {
+		dbs = append(dbs, s.shards[session])
+	}
+	return dbs
+}
+
+func (s *ShardedDatabase) Close() error {
+	var errs []error
+	for _, db := range s.all() {
+		if err := db.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *ShardedDatabase) HealthCheck(ctx context.Context) error {
+	for _, db := range s.all() {
+		if err := db.HealthCheck(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ShardedDatabase) SaveMessageMapping(ctx context.Context, mapping *models.MessageMapping) error {
+	if mapping == nil {
+		return s.Database.SaveMessageMapping(ctx, mapping)
+	}
+	return s.forSession(mapping.SessionName).SaveMessageMapping(ctx, mapping)
+}
+
+// firstMapping returns the first mapping get finds in any database
+func (s *ShardedDatabase) firstMapping(get func(*Database) (*models.MessageMapping, error)) (*models.MessageMapping, error) {
+	for _, db := range s.all() {
+		mapping, err := get(db)
+		if err != nil || mapping != nil {
+			return mapping, err
+		}
+	}
+	return nil, nil
+}
+
+// latestMapping returns the most recently forwarded of the mappings get finds in each database
+func (s *ShardedDatabase) latestMapping(get func(*Database) (*models.MessageMapping, error)) (*models.MessageMapping, error) {
+	var latest *models.MessageMapping
+	for _, db := range s.all() {
+		mapping, err := get(db)
+		if err != nil {
+			return nil, err
+		}
+		if mapping != nil && (latest == nil || mapping.ForwardedAt.After(latest.ForwardedAt)) {
+			latest = mapping
+		}
+	}
+	return latest, nil
+}
+
+func (s *ShardedDatabase) GetMessageMappingByWhatsAppID(ctx context.Context, whatsappID string) (*models.MessageMapping, error) {
+	return s.firstMapping(func(db *Database) (*models.MessageMapping, error) {
+		return db.GetMessageMappingByWhatsAppID(ctx, whatsappID)
+	})
+}
+
+func (s *ShardedDatabase) GetMessageMapping(ctx context.Context, id string) (*models.MessageMapping, error) {
+	return s.firstMapping(func(db *Database) (*models.MessageMapping, error) {
+		return db.GetMessageMapping(ctx, id)
+	})
+}
+
+func (s *ShardedDatabase) GetMessageMappingBySignalID(ctx context.Context, signalID string) (*models.MessageMapping, error) {
+	return s.firstMapping(func(db *Database) (*models.MessageMapping, error) {
+		return db.GetMessageMappingBySignalID(ctx, signalID)
+	})
+}
+
+func (s *ShardedDatabase) GetLatestMessageMappingByWhatsAppChatID(ctx context.Context, whatsappChatID string) (*models.MessageMapping, error) {
+	return s.latestMapping(func(db *Database) (*models.MessageMapping, error) {
+		return db.GetLatestMessageMappingByWhatsAppChatID(ctx, whatsappChatID)
+	})
+}
+
+func (s *ShardedDatabase) GetLatestMessageMapping(ctx context.Context) (*models.MessageMapping, error) {
+	return s.latestMapping(func(db *Database) (*models.MessageMapping, error) {
+		return db.GetLatestMessageMapping(ctx)
+	})
+}
+
+func (s *ShardedDatabase) GetLatestMessageMappingBySession(ctx context.Context, sessionName string) (*models.MessageMapping, error) {
+	return s.forSession(sessionName).GetLatestMessageMappingBySession(ctx, sessionName)
+}
+
+func (s *ShardedDatabase) GetLatestGroupMessageMappingBySession(ctx context.Context, sessionName string, searchLimit int) (*models.MessageMapping, error) {
+	return s.forSession(sessionName).GetLatestGroupMessageMappingBySession(ctx, sessionName, searchLimit)
+}
+
+func (s *ShardedDatabase) GetMappingStats(ctx context.Context, sessionName string) (models.MappingStats, error) {
+	return s.forSession(sessionName).GetMappingStats(ctx, sessionName)
+}
+
+func (s *ShardedDatabase) HasMessageHistoryBetween(ctx context.Context, sessionName, signalSender string) (bool, error) {
+	return s.forSession(sessionName).HasMessageHistoryBetween(ctx, sessionName, signalSender)
+}
+
+// GetMappingSessionsForChat returns the sessions that have bridged messages in a WhatsApp chat
+// across all files, the session with the most recent activity first
+func (s *ShardedDatabase) GetMappingSessionsForChat(ctx context.Context, chatID string) ([]string, error) {
+	type found struct {
+		sessions []string
+		latest   time.Time
+	}
+	var all []found
+	for _, db := range s.all() {
+		sessions, err := db.GetMappingSessionsForChat(ctx, chatID)
+		if err != nil {
+			return nil, err
+		}
+		if len(sessions) == 0 {
+			continue
+		}
+		latest, err := db.GetLatestMessageMappingByWhatsAppChatID(ctx, chatID)
+		if err != nil {
+			return nil, err
+		}
+		f := found{sessions: sessions}
+		if latest != nil {
+			f.latest = latest.ForwardedAt
+		}
+		all = append(all, f)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].latest.After(all[j].latest) })
+
+	var sessions []string
+	for _, f := range all {
+		sessions = append(sessions, f.sessions...)
+	}
+	return sessions, nil
+}
+
+// updateAny runs update on each file until one holds the mapping it updates
+func (s *ShardedDatabase) updateAny(update func(*Database) error) error {
+	var notFound error
+	for _, db := range s.all() {
+		err := update(db)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrNoMessageFound) {
+			return err
+		}
+		notFound = err
+	}
+	return notFound
+}
+
+func (s *ShardedDatabase) UpdateDeliveryStatusByWhatsAppID(ctx context.Context, whatsappID string, status string) error {
+	return s.updateAny(func(db *Database) error {
+		return db.UpdateDeliveryStatusByWhatsAppID(ctx, whatsappID, status)
+	})
+}
+
+func (s *ShardedDatabase) UpdateDeliveryStatusBySignalID(ctx context.Context, signalID string, status string) error {
+	return s.updateAny(func(db *Database) error {
+		return db.UpdateDeliveryStatusBySignalID(ctx, signalID, status)
+	})
+}
+
+func (s *ShardedDatabase) UpdateDeliveryStatus(ctx context.Context, id string, status string) error {
+	return s.updateAny(func(db *Database) error {
+		return db.UpdateDeliveryStatus(ctx, id, status)
+	})
+}
+
+func (s *ShardedDatabase) UpdateSignalIDByWhatsAppID(ctx context.Context, whatsappMsgID, signalMsgID string, signalTimestamp time.Time, status string) error {
+	for _, db := range s.all() {
+		if err := db.UpdateSignalIDByWhatsAppID(ctx, whatsappMsgID, signalMsgID, signalTimestamp, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ShardedDatabase) GetStaleMessageCount(ctx context.Context, threshold time.Duration) (int, error) {
+	total := 0
+	for _, db := range s.all() {
+		count, err := db.GetStaleMessageCount(ctx, threshold)
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// GetMediaPaths returns the media paths of the message mappings in all files
+func (s *ShardedDatabase) GetMediaPaths(ctx context.Context) ([]string, error) {
+	var paths []string
+	for _, db := range s.all() {
+		dbPaths, err := db.GetMediaPaths(ctx)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, dbPaths...)
+	}
+	return paths, nil
+}
+
+func (s *ShardedDatabase) CleanupOldRecords(ctx context.Context, retentionDays int) error {
+	for _, db := range s.all() {
+		if err := db.CleanupOldRecords(ctx, retentionDays); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ShardedDatabase) SaveGroup(ctx context.Context, group *models.Group) error {
+	if group == nil {
+		return s.Database.SaveGroup(ctx, group)
+	}
+	return s.forSession(group.SessionName).SaveGroup(ctx, group)
+}
+
+func (s *ShardedDatabase) GetGroup(ctx context.Context, groupID, sessionName string) (*models.Group, error) {
+	return s.forSession(sessionName).GetGroup(ctx, groupID, sessionName)
+}
+
+func (s *ShardedDatabase) CleanupOldGroups(ctx context.Context, retentionDays int) error {
+	for _, db := range s.all() {
+		if err := db.CleanupOldGroups(ctx, retentionDays); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ShardedDatabase) SaveGroupMapping(ctx context.Context, mapping *models.GroupMapping) error {
+	return s.forSession(mapping.SessionName).SaveGroupMapping(ctx, mapping)
+}
+
+func (s *ShardedDatabase) GetGroupMapping(ctx context.Context, sessionName, signalGroupID string) (*models.GroupMapping, error) {
+	return s.forSession(sessionName).GetGroupMapping(ctx, sessionName, signalGroupID)
+}
+
+func (s *ShardedDatabase) SetChatPaused(ctx context.Context, sessionName, chatID string, paused bool) error {
+	return s.forSession(sessionName).SetChatPaused(ctx, sessionName, chatID, paused)
+}
+
+func (s *ShardedDatabase) IsChatPaused(ctx context.Context, sessionName, chatID string) (bool, error) {
+	return s.forSession(sessionName).IsChatPaused(ctx, sessionName, chatID)
+}
+
+func (s *ShardedDatabase) SetChatAutoReply(ctx context.Context, sessionName, chatID, message string) error {
+	return s.forSession(sessionName).SetChatAutoReply(ctx, sessionName, chatID, message)
+}
+
+func (s *ShardedDatabase) ClaimChatAutoReply(ctx context.Context, sessionName, chatID string, now time.Time, cooldown time.Duration) (string, error) {
+	return s.forSession(sessionName).ClaimChatAutoReply(ctx, sessionName, chatID, now, cooldown)
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"whatsignal/internal/migrations"
+	"whatsignal/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupShardedTestDB(t *testing.T, sessions ...string) (*ShardedDatabase, string) {
+	t.Setenv("WHATSIGNAL_ENCRYPTION_SECRET", "this-is-a-very-long-test-secret-key-for-database-testing")
+
+	tmpDir := t.TempDir()
+	originalMigrationsDir := migrations.MigrationsDir
+	migrations.MigrationsDir = setupTestMigrations(t, tmpDir)
+	t.Cleanup(func() { migrations.MigrationsDir = originalMigrationsDir })
+
+	dbPath := filepath.Join(tmpDir, "whatsignal.db")
+	db, err := NewSharded(dbPath, nil, sessions)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db, dbPath
+}
+
+func shardTestMapping(session, waID, sigID string, forwardedAt time.Time) *models.MessageMapping {
+	return &models.MessageMapping{
+		WhatsAppChatID:  "123456789@c.us",
+		WhatsAppMsgID:   waID,
+		SignalMsgID:     sigID,
+		SignalTimestamp: forwardedAt,
+		ForwardedAt:     forwardedAt,
+		DeliveryStatus:  models.DeliveryStatusSent,
+		SessionName:     session,
+	}
+}
+
+func TestShardPath(t *testing.T) {
+	assert.Equal(t, "/data/whatsignal-personal.db", ShardPath("/data/whatsignal.db", "personal"))
+	assert.Equal(t, "/data/whatsignal-my_session_.db", ShardPath("/data/whatsignal.db", "my session!"))
+	assert.Equal(t, "/data/store-work", ShardPath("/data/store", "work"))
+}
+
+func TestShardedDatabase_SessionsWriteToTheirOwnFiles(t *testing.T) {
+	db, dbPath := setupShardedTestDB(t, "personal", "work")
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, db.SaveMessageMapping(ctx, shardTestMapping("personal", "wa-personal", "sig-personal", now)))
+	require.NoError(t, db.SaveMessageMapping(ctx, shardTestMapping("work", "wa-work", "sig-work", now.Add(time.Second))))
+
+	for _, session := range []string{"personal", "work"} {
+		_, err := os.Stat(ShardPath(dbPath, session))
+		require.NoError(t, err, "session %s has a file of its own", session)
+	}
+
+	personal, err := db.shards["personal"].GetMessageMappingByWhatsAppID(ctx, "wa-personal")
+	require.NoError(t, err)
+	require.NotNil(t, personal)
+	other, err := db.shards["personal"].GetMessageMappingByWhatsAppID(ctx, "wa-work")
+	require.NoError(t, err)
+	assert.Nil(t, other, "the work mapping is not in the personal file")
+	shared, err := db.Database.GetLatestMessageMapping(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, shared, "sharded sessions leave the main file empty")
+
+	latest, err := db.GetLatestMessageMappingBySession(ctx, "personal")
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, "wa-personal", latest.WhatsAppMsgID)
+
+	stats, err := db.GetMappingStats(ctx, "work")
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Total)
+}
+
+func TestShardedDatabase_CrossSessionQueriesFanOut(t *testing.T) {
+	db, _ := setupShardedTestDB(t, "personal", "work")
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, db.SaveMessageMapping(ctx, shardTestMapping("work", "wa-work", "sig-work", now)))
+	require.NoError(t, db.SaveMessageMapping(ctx, shardTestMapping("personal", "wa-personal", "sig-personal", now.Add(time.Minute))))
+	require.NoError(t, db.SaveMessageMapping(ctx, shardTestMapping("other", "wa-other", "sig-other", now.Add(-time.Minute))))
+
+	bySignal, err := db.GetMessageMappingBySignalID(ctx, "sig-work")
+	require.NoError(t, err)
+	require.NotNil(t, bySignal)
+	assert.Equal(t, "work", bySignal.SessionName)
+
+	unsharded, err := db.GetMessageMapping(ctx, "wa-other")
+	require.NoError(t, err)
+	require.NotNil(t, unsharded, "sessions without a shard use the main file")
+
+	latest, err := db.GetLatestMessageMapping(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, "wa-personal", latest.WhatsAppMsgID)
+
+	sessions, err := db.GetMappingSessionsForChat(ctx, "123456789@c.us")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"personal", "work", "other"}, sessions)
+
+	require.NoError(t, db.UpdateDeliveryStatus(ctx, "sig-work", string(models.DeliveryStatusDelivered)))
+	updated, err := db.GetMessageMappingByWhatsAppID(ctx, "wa-work")
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryStatusDelivered, updated.DeliveryStatus)
+
+	err = db.UpdateDeliveryStatus(ctx, "missing", string(models.DeliveryStatusDelivered))
+	assert.ErrorIs(t, err, ErrNoMessageFound)
+}
+
+func TestShardedDatabase_GetMediaPaths(t *testing.T) {
+	db, _ := setupShardedTestDB(t, "personal", "work")
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, m := range []struct{ session, path string }{
+		{"personal", "/cache/personal.jpg"},
+		{"work", "/cache/work.pdf"},
+		{"other", "/cache/other.mp4"},
+	} {
+		mapping := shardTestMapping(m.session, "wa-"+m.session, "sig-"+m.session, now)
+		mapping.MediaPath = &m.path
+		require.NoError(t, db.SaveMessageMapping(ctx, mapping))
+	}
+
+	paths, err := db.GetMediaPaths(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"/cache/personal.jpg", "/cache/work.pdf", "/cache/other.mp4"}, paths)
+}
+
+func TestNewSharded_RejectsSessionsSharingAFile(t *testing.T) {
+	t.Setenv("WHATSIGNAL_ENCRYPTION_SECRET", "this-is-a-very-long-test-secret-key-for-database-testing")
+	tmpDir := t.TempDir()
+	originalMigrationsDir := migrations.MigrationsDir
+	migrations.MigrationsDir = setupTestMigrations(t, tmpDir)
+	defer func() { migrations.MigrationsDir = originalMigrationsDir }()
+
+	_, err := NewSharded(filepath.Join(tmpDir, "whatsignal.db"), nil, []string{"my.session", "my_session"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "same database file")
+}
@@ -0,0 +1,112 @@
+package media
+
+import (
+	"net/http"
+	"strings"
+
+	"whatsignal/internal/constants"
+)
+
+// ExtensionFromContent returns the extension, without its dot, for the leading bytes of a file:
+// a known file signature, else the type sniffed by http.DetectContentType, or "" when neither
+// names a media type
+func ExtensionFromContent(data []byte) string {
+	// Check for specific file signatures first (more reliable than http.DetectContentType for audio)
+	if ext := ExtensionFromSignature(data); ext != "" {
+		return ext
+	}
+
+	// Detect MIME type from content
+	contentType := http.DetectContentType(data)
+
+	// Try direct mapping first
+	if ext, ok := constants.ContentTypeToExtension[contentType]; ok {
+		return ext
+	}
+
+	// Fallback to partial matching for complex content types
+	switch {
+	case strings.HasPrefix(contentType, "audio/"):
+		// Check for partial matches in audio types
+		for contentTypeKey, ext := range constants.ContentTypeToExtension {
+			if strings.HasPrefix(contentTypeKey, "audio/") && strings.Contains(contentType, strings.TrimPrefix(contentTypeKey, "audio/")) {
+				return ext
+			}
+		}
+		return constants.DefaultAudioExtension
+	case strings.HasPrefix(contentType, "image/"):
+		// Check for partial matches in image types
+		for contentTypeKey, ext := range constants.ContentTypeToExtension {
+			if strings.HasPrefix(contentTypeKey, "image/") && strings.Contains(contentType, strings.TrimPrefix(contentTypeKey, "image/")) {
+				return ext
+			}
+		}
+		return constants.DefaultImageExtension
+	case strings.HasPrefix(contentType, "video/"):
+		// Check for partial matches in video types
+		for contentTypeKey, ext := range constants.ContentTypeToExtension {
+			if strings.HasPrefix(contentTypeKey, "video/") && strings.Contains(contentType, strings.TrimPrefix(contentTypeKey, "video/")) {
+				return ext
+			}
+
+		}
+		return constants.DefaultVideoExtension
+	default:
+		// For other types, return empty string to use document default
+		return ""
+	}
+}
+
+// ExtensionFromSignature returns the extension, without its dot, of the known file signature
+// data starts with, or "" when it has none
+func ExtensionFromSignature(data []byte) string {
+	if len(data) < 3 {
+		return ""
+	}
+
+	// Check for known file signatures from constants
+	for signature, ext := range constants.FileSignatures {
+		sigBytes := []byte(signature)
+		if len(data) >= len(sigBytes) {
+			if string(data[0:len(sigBytes)]) == signature {
+				// Special case for WebP: also check for WEBP marker
+				if signature == "RIFF" && len(data) >= 12 && string(data[8:12]) == "WEBP" {
+					return ext
+				} else if signature != "RIFF" {
+					return ext
+				}
+			}
+		}
+	}
+
+	// Special binary signatures that can't be easily stored as strings
+
+	// Check for MP3 frame sync (binary pattern)
+	if len(data) >= 2 && data[0] == 0xFF && (data[1]&0xE0) == 0xE0 {
+		return "mp3"
+	}
+
+	// Check for AAC file signature (ADTS header)
+	if len(data) >= 2 && data[0] == 0xFF && (data[1]&0xF0) == 0xF0 {
+		return "aac"
+	}
+
+	// Check for M4A/MP4 signature (ftyp box)
+	if len(data) >= 8 && string(data[4:8]) == "ftyp" {
+		// Check for M4A-specific brand codes
+		if len(data) >= 12 {
+			brand := string(data[8:12])
+			if brand == "M4A " || brand == "mp41" || brand == "mp42" {
+				return "m4a"
+			}
+		}
+		return "mp4" // Default to mp4 for other ftyp variants
+	}
+
+	// Check for JPEG signatures (binary pattern)
+	if len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF {
+		return "jpg"
+	}
+
+	return ""
+}
@@ -40,31 +40,55 @@ type TimerMetric struct {
 	samples []float64
 }
 
+// HistogramBucket counts the observations less than or equal to UpperBound
+type HistogramBucket struct {
+	UpperBound float64 `json:"le"`
+	Count      int64   `json:"count"`
+}
+
+// HistogramMetric stores observations in cumulative buckets, as a Prometheus histogram does.
+// Count includes observations above the last bucket, the implicit +Inf bucket.
+type HistogramMetric struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Buckets     []HistogramBucket `json:"buckets"`
+	Count       int64             `json:"count"`
+	Sum         float64           `json:"sum"`
+	LastUpdate  time.Time         `json:"last_update"`
+}
+
+// DefaultLatencyBuckets are histogram bucket bounds in seconds suited to message relay latency
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
 // MetricsSnapshot represents a snapshot of all metrics
 type MetricsSnapshot struct {
-	Counters  map[string]*Metric      `json:"counters"`
-	Timers    map[string]*TimerMetric `json:"timers"`
-	Gauges    map[string]*Metric      `json:"gauges"`
-	UptimeMs  int64                   `json:"uptime_ms"`
-	Timestamp int64                   `json:"timestamp"`
+	Counters   map[string]*Metric          `json:"counters"`
+	Timers     map[string]*TimerMetric     `json:"timers"`
+	Gauges     map[string]*Metric          `json:"gauges"`
+	Histograms map[string]*HistogramMetric `json:"histograms"`
+	UptimeMs   int64                       `json:"uptime_ms"`
+	Timestamp  int64                       `json:"timestamp"`
 }
 
 // Registry manages all metrics in memory
 type Registry struct {
-	mu        sync.RWMutex
-	counters  map[string]*Metric
-	timers    map[string]*TimerMetric
-	gauges    map[string]*Metric
-	startTime time.Time
+	mu         sync.RWMutex
+	counters   map[string]*Metric
+	timers     map[string]*TimerMetric
+	gauges     map[string]*Metric
+	histograms map[string]*HistogramMetric
+	startTime  time.Time
 }
 
 // NewRegistry creates a new metrics registry
 func NewRegistry() *Registry {
 	return &Registry{
-		counters:  make(map[string]*Metric),
-		timers:    make(map[string]*TimerMetric),
-		gauges:    make(map[string]*Metric),
-		startTime: time.Now(),
+		counters:   make(map[string]*Metric),
+		timers:     make(map[string]*TimerMetric),
+		gauges:     make(map[string]*Metric),
+		histograms: make(map[string]*HistogramMetric),
+		startTime:  time.Now(),
 	}
 }
 
@@ -160,17 +184,49 @@ func (r *Registry) SetGauge(name string, value float64, labels map[string]string
 	}
 }
 
+// ObserveHistogram records value in a histogram. buckets are the ascending upper bounds of its
+// buckets and are fixed by the first observation of the histogram.
+func (r *Registry) ObserveHistogram(name string, value float64, buckets []float64, labels map[string]string, description string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := r.metricKey(name, labels)
+	histogram, exists := r.histograms[key]
+	if !exists {
+		histogram = &HistogramMetric{
+			Name:        name,
+			Labels:      copyLabels(labels),
+			Description: description,
+			Buckets:     make([]HistogramBucket, len(buckets)),
+		}
+		for i, bound := range buckets {
+			histogram.Buckets[i].UpperBound = bound
+		}
+		r.histograms[key] = histogram
+	}
+
+	for i := range histogram.Buckets {
+		if value <= histogram.Buckets[i].UpperBound {
+			histogram.Buckets[i].Count++
+		}
+	}
+	histogram.Count++
+	histogram.Sum += value
+	histogram.LastUpdate = time.Now()
+}
+
 // GetAllMetrics returns all metrics in a structured format
 func (r *Registry) GetAllMetrics() *MetricsSnapshot {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	result := &MetricsSnapshot{
-		Counters:  make(map[string]*Metric),
-		Timers:    make(map[string]*TimerMetric),
-		Gauges:    make(map[string]*Metric),
-		UptimeMs:  time.Since(r.startTime).Milliseconds(),
-		Timestamp: time.Now().Unix(),
+		Counters:   make(map[string]*Metric),
+		Timers:     make(map[string]*TimerMetric),
+		Gauges:     make(map[string]*Metric),
+		Histograms: make(map[string]*HistogramMetric),
+		UptimeMs:   time.Since(r.startTime).Milliseconds(),
+		Timestamp:  time.Now().Unix(),
 	}
 
 	// Copy counters
@@ -188,6 +244,11 @@ func (r *Registry) GetAllMetrics() *MetricsSnapshot {
 		result.Gauges[key] = copyMetric(gauge)
 	}
 
+	// Copy histograms
+	for key, histogram := range r.histograms {
+		result.Histograms[key] = copyHistogramMetric(histogram)
+	}
+
 	return result
 }
 
@@ -213,6 +274,16 @@ func copyTimerMetric(timer *TimerMetric) *TimerMetric {
 	return &copied
 }
 
+func copyHistogramMetric(histogram *HistogramMetric) *HistogramMetric {
+	if histogram == nil {
+		return nil
+	}
+	copied := *histogram
+	copied.Labels = copyLabels(histogram.Labels)
+	copied.Buckets = append([]HistogramBucket(nil), histogram.Buckets...)
+	return &copied
+}
+
 // metricKey generates a unique key for a metric with labels
 func (r *Registry) metricKey(name string, labels map[string]string) string {
 	if len(labels) == 0 {
@@ -288,6 +359,11 @@ func SetGauge(name string, value float64, labels map[string]string, description
 	globalRegistry.SetGauge(name, value, labels, description)
 }
 
+// ObserveHistogram records a histogram observation in the global registry
+func ObserveHistogram(name string, value float64, buckets []float64, labels map[string]string, description string) {
+	globalRegistry.ObserveHistogram(name, value, buckets, labels, description)
+}
+
 // GetAllMetrics returns all metrics from the global registry
 func GetAllMetrics() *MetricsSnapshot {
 	return globalRegistry.GetAllMetrics()
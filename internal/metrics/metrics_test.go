@@ -168,6 +168,41 @@ func TestRegistry_SetGauge(t *testing.T) {
 	}
 }
 
+func TestRegistry_ObserveHistogram(t *testing.T) {
+	registry := NewRegistry()
+	labels := map[string]string{"direction": "in"}
+	buckets := []float64{0.1, 1, 10}
+
+	for _, value := range []float64{0.05, 0.5, 0.5, 5, 50} {
+		registry.ObserveHistogram("test_histogram", value, buckets, labels, "Test histogram")
+	}
+
+	histogram, exists := registry.GetAllMetrics().Histograms["test_histogram_direction:in"]
+	if !exists {
+		t.Fatal("Expected histogram 'test_histogram' to exist")
+	}
+	if histogram.Count != 5 {
+		t.Fatalf("Expected count 5, got %d", histogram.Count)
+	}
+	if histogram.Sum != 56.05 {
+		t.Fatalf("Expected sum 56.05, got %f", histogram.Sum)
+	}
+
+	// Buckets are cumulative; the observation above 10 only counts towards Count
+	want := []int64{1, 3, 4}
+	for i, bucket := range histogram.Buckets {
+		if bucket.UpperBound != buckets[i] || bucket.Count != want[i] {
+			t.Fatalf("Expected bucket le=%v to count %d, got le=%v count %d", buckets[i], want[i], bucket.UpperBound, bucket.Count)
+		}
+	}
+
+	snapshot := registry.GetAllMetrics()
+	snapshot.Histograms["test_histogram_direction:in"].Buckets[0].Count = 99
+	if got := registry.GetAllMetrics().Histograms["test_histogram_direction:in"].Buckets[0].Count; got != 1 {
+		t.Fatalf("histogram snapshot mutation leaked into registry: got %d", got)
+	}
+}
+
 func TestRegistry_GetAllMetricsReturnsDeepCopy(t *testing.T) {
 	registry := NewRegistry()
 	labels := map[string]string{"status": "success"}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusNamespace prefixes the names of metrics exported to Prometheus
+const PrometheusNamespace = "whatsignal_"
+
+// invalidPrometheusNameChars matches the characters not allowed in a Prometheus metric or label name
+var invalidPrometheusNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// prometheusFamily is the series of one metric name in a snapshot
+type prometheusFamily struct {
+	typ         MetricType
+	description string
+	counters    []*Metric
+	histograms  []*HistogramMetric
+}
+
+// WritePrometheus writes the counters, gauges and histograms of the snapshot to w in the
+// Prometheus text exposition format, with names prefixed by PrometheusNamespace. Timers keep
+// only percentiles of recent samples, which Prometheus cannot aggregate, so they are left out;
+// a latency meant to be scraped is recorded as a histogram instead.
+func (s *MetricsSnapshot) WritePrometheus(w io.Writer) error {
+	families := make(map[string]*prometheusFamily)
+	family := func(name string, typ MetricType, description string) *prometheusFamily {
+		name = prometheusName(name)
+		f, ok := families[name]
+		if !ok {
+			f = &prometheusFamily{typ: typ, description: description}
+			families[name] = f
+		}
+		return f
+	}
+	for _, counter := range s.Counters {
+		f := family(counter.Name, Counter, counter.Description)
+		f.counters = append(f.counters, counter)
+	}
+	for _, gauge := range s.Gauges {
+		f := family(gauge.Name, Gauge, gauge.Description)
+		f.counters = append(f.counters, gauge)
+	}
+	for _, histogram := range s.Histograms {
+		f := family(histogram.Name, Histogram, histogram.Description)
+		f.histograms = append(f.histograms, histogram)
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		f := families[name]
+		if f.description != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", name, escapePrometheusHelp(f.description))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", name, f.typ)
+
+		sort.Slice(f.counters, func(i, j int) bool {
+			return prometheusLabels(f.counters[i].Labels, "", "") < prometheusLabels(f.counters[j].Labels, "", "")
+		})
+		for _, metric := range f.counters {
+			fmt.Fprintf(bw, "%s%s %s\n", name, prometheusLabels(metric.Labels, "", ""), prometheusValue(metric.Value))
+		}
+
+		sort.Slice(f.histograms, func(i, j int) bool {
+			return prometheusLabels(f.histograms[i].Labels, "", "") < prometheusLabels(f.histograms[j].Labels, "", "")
+		})
+		for _, histogram := range f.histograms {
+			for _, bucket := range histogram.Buckets {
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", name, prometheusLabels(histogram.Labels, "le", prometheusValue(bucket.UpperBound)), bucket.Count)
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", name, prometheusLabels(histogram.Labels, "le", "+Inf"), histogram.Count)
+			fmt.Fprintf(bw, "%s_sum%s %s\n", name, prometheusLabels(histogram.Labels, "", ""), prometheusValue(histogram.Sum))
+			fmt.Fprintf(bw, "%s_count%s %d\n", name, prometheusLabels(histogram.Labels, "", ""), histogram.Count)
+		}
+	}
+	return bw.Flush()
+}
+
+// prometheusName returns name prefixed by PrometheusNamespace, with characters Prometheus does
+// not allow replaced by "_"
+func prometheusName(name string) string {
+	return PrometheusNamespace + invalidPrometheusNameChars.ReplaceAllString(name, "_")
+}
+
+// prometheusLabels renders labels, and the extra label when extraName is set, as {a="1",b="2"}
+// sorted by name. It returns "" without labels.
+func prometheusLabels(labels map[string]string, extraName, extraValue string) string {
+	if len(labels) == 0 && extraName == "" {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		pairs = append(pairs, invalidPrometheusNameChars.ReplaceAllString(k, "_")+`="`+escapePrometheusLabelValue(labels[k])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// prometheusValue formats a sample value
+func prometheusValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	prometheusHelpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	prometheusLabelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapePrometheusHelp(s string) string {
+	return prometheusHelpEscaper.Replace(s)
+}
+
+func escapePrometheusLabelValue(s string) string {
+	return prometheusLabelValueEscaper.Replace(s)
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsSnapshot_WritePrometheus(t *testing.T) {
+	registry := NewRegistry()
+	registry.IncrementCounter("messages_total", map[string]string{"direction": "signal_to_whatsapp", "session": "personal"}, "Messages relayed")
+	registry.AddToCounter("messages_total", 2, map[string]string{"direction": "whatsapp_to_signal", "session": "personal"}, "Messages relayed")
+	registry.SetGauge("queue.depth", 3, nil, "Queued work\nper server")
+	registry.ObserveHistogram("latency_seconds", 0.2, []float64{0.1, 0.5}, map[string]string{"chat": `say "hi"`}, "Latency")
+	registry.ObserveHistogram("latency_seconds", 0.7, []float64{0.1, 0.5}, map[string]string{"chat": `say "hi"`}, "Latency")
+	registry.RecordTimer("send_duration", time.Second, nil, "Timer left out")
+
+	var out strings.Builder
+	if err := registry.GetAllMetrics().WritePrometheus(&out); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+
+	expected := `# HELP whatsignal_latency_seconds Latency
+# TYPE whatsignal_latency_seconds histogram
+whatsignal_latency_seconds_bucket{chat="say \"hi\"",le="0.1"} 0
+whatsignal_latency_seconds_bucket{chat="say \"hi\"",le="0.5"} 1
+whatsignal_latency_seconds_bucket{chat="say \"hi\"",le="+Inf"} 2
+whatsignal_latency_seconds_sum{chat="say \"hi\""} 0.8999999999999999
+whatsignal_latency_seconds_count{chat="say \"hi\""} 2
+# HELP whatsignal_messages_total Messages relayed
+# TYPE whatsignal_messages_total counter
+whatsignal_messages_total{direction="signal_to_whatsapp",session="personal"} 1
+whatsignal_messages_total{direction="whatsapp_to_signal",session="personal"} 2
+# HELP whatsignal_queue_depth Queued work\nper server
+# TYPE whatsignal_queue_depth gauge
+whatsignal_queue_depth 3
+`
+	if out.String() != expected {
+		t.Fatalf("Unexpected exposition:\n%s\nwant:\n%s", out.String(), expected)
+	}
+}
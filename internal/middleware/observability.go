@@ -159,6 +159,9 @@ func WebhookObservabilityMiddleware(logger *logrus.Logger, webhookType string) f
 			// Start OpenTelemetry span for webhook
 			ctx, span := tracing.WithOtelTracing(r.Context(), "webhook_request")
 			defer span.End()
+
+			// Generate a request ID that follows the webhook's message through the bridge
+			ctx = tracing.EnsureRequestID(ctx)
 			r = r.WithContext(ctx)
 
 			// Add webhook-specific OpenTelemetry attributes
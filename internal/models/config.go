@@ -4,33 +4,83 @@ import "time"
 
 // Config holds the application configuration
 type Config struct {
-	WhatsApp      WhatsAppConfig `json:"whatsapp" mapstructure:"whatsapp"`
-	Signal        SignalConfig   `json:"signal" mapstructure:"signal"`
-	Database      DatabaseConfig `json:"database" mapstructure:"database"`
-	Media         MediaConfig    `json:"media" mapstructure:"media"`
-	Retry         RetryConfig    `json:"retry" mapstructure:"retry"`
-	Server        ServerConfig   `json:"server" mapstructure:"server"`
-	Tracing       TracingConfig  `json:"tracing" mapstructure:"tracing"`
-	LogLevel      string         `json:"log_level" mapstructure:"log_level"`
-	RetentionDays int            `json:"retentionDays"`
-	Channels      []Channel      `json:"channels" mapstructure:"channels"` // Multi-channel support
+	WhatsApp      WhatsAppConfig  `json:"whatsapp" mapstructure:"whatsapp"`
+	Signal        SignalConfig    `json:"signal" mapstructure:"signal"`
+	Database      DatabaseConfig  `json:"database" mapstructure:"database"`
+	Media         MediaConfig     `json:"media" mapstructure:"media"`
+	Retry         RetryConfig     `json:"retry" mapstructure:"retry"`
+	Server        ServerConfig    `json:"server" mapstructure:"server"`
+	Tracing       TracingConfig   `json:"tracing" mapstructure:"tracing"`
+	LogLevel      string          `json:"log_level" mapstructure:"log_level"`
+	RetentionDays int             `json:"retentionDays"`
+	Channels      []Channel       `json:"channels" mapstructure:"channels"`     // Multi-channel support
+	Transforms    []TransformRule `json:"transforms" mapstructure:"transforms"` // Rewrites applied to relayed text, in order
 }
 
 // WhatsAppConfig holds WhatsApp related configurations
 type WhatsAppConfig struct {
-	APIBaseURL               string        `json:"api_base_url" mapstructure:"api_base_url"`
-	Timeout                  time.Duration `json:"timeout_ms" mapstructure:"timeout_ms"`
-	RetryCount               int           `json:"retry_count" mapstructure:"retry_count"`
-	WebhookSecret            string        `json:"webhook_secret" mapstructure:"webhook_secret"`
-	PollIntervalSec          int           `json:"pollIntervalSec"`
-	ContactSyncOnStartup     bool          `json:"contactSyncOnStartup" mapstructure:"contactSyncOnStartup"`
-	ContactCacheHours        int           `json:"contactCacheHours" mapstructure:"contactCacheHours"`
-	SessionHealthCheckSec    int           `json:"sessionHealthCheckSec" mapstructure:"sessionHealthCheckSec"`
-	SessionAutoRestart       bool          `json:"sessionAutoRestart" mapstructure:"sessionAutoRestart"`
-	SessionStartupTimeoutSec int           `json:"sessionStartupTimeoutSec" mapstructure:"sessionStartupTimeoutSec"`
-	Groups                   GroupConfig   `json:"groups" mapstructure:"groups"`
+	APIBaseURL                  string        `json:"api_base_url" mapstructure:"api_base_url"`
+	Timeout                     time.Duration `json:"timeout_ms" mapstructure:"timeout_ms"`
+	RetryCount                  int           `json:"retry_count" mapstructure:"retry_count"`
+	WebhookSecret               string        `json:"webhook_secret" mapstructure:"webhook_secret"`
+	PollIntervalSec             int           `json:"pollIntervalSec"`
+	ContactSyncOnStartup        bool          `json:"contactSyncOnStartup" mapstructure:"contactSyncOnStartup"`
+	ContactCacheHours           int           `json:"contactCacheHours" mapstructure:"contactCacheHours"`
+	SessionHealthCheckSec       int           `json:"sessionHealthCheckSec" mapstructure:"sessionHealthCheckSec"`
+	SessionAutoRestart          bool          `json:"sessionAutoRestart" mapstructure:"sessionAutoRestart"`
+	SessionStartupTimeoutSec    int           `json:"sessionStartupTimeoutSec" mapstructure:"sessionStartupTimeoutSec"`
+	SessionRestartBackoffSec    int           `json:"sessionRestartBackoffSec" mapstructure:"sessionRestartBackoffSec"`       // Wait after a restart attempt before the next; doubles after each attempt (0 = default)
+	SessionRestartBackoffMaxSec int           `json:"sessionRestartBackoffMaxSec" mapstructure:"sessionRestartBackoffMaxSec"` // Cap on the wait between restart attempts (0 = default)
+	SessionRestartResetSec      int           `json:"sessionRestartResetSec" mapstructure:"sessionRestartResetSec"`           // How long the session must stay WORKING before the wait starts over (0 = default)
+	SessionDownNotify           bool          `json:"sessionDownNotify" mapstructure:"sessionDownNotify"`                     // Notify Signal while the session monitor sees the session down
+	SessionDownNotifyAfterSec   int           `json:"sessionDownNotifyAfterSec" mapstructure:"sessionDownNotifyAfterSec"`     // Downtime before the first notice; 0 notifies on the first failed check
+	SessionDownRepeatSec        int           `json:"sessionDownRepeatSec" mapstructure:"sessionDownRepeatSec"`               // First reminder interval; doubles after each reminder
+	SessionWarmupTimeoutSec     int           `json:"sessionWarmupTimeoutSec" mapstructure:"sessionWarmupTimeoutSec"`         // On startup, wait up to this long for every session to be WORKING before relaying (0 = no wait)
+	Groups                      GroupConfig   `json:"groups" mapstructure:"groups"`
+	EnabledEvents               []string      `json:"enabledEvents" mapstructure:"enabledEvents"`               // Webhook events to process; empty processes all. "message" is always processed.
+	ChatLabelsPrefix            bool          `json:"chatLabelsPrefix" mapstructure:"chatLabelsPrefix"`         // Prefix messages relayed to Signal with the chat's WhatsApp Business labels
+	ChatLabelsCacheSec          int           `json:"chatLabelsCacheSec" mapstructure:"chatLabelsCacheSec"`     // How long a chat's labels are reused before asking WAHA again
+	EditHistory                 bool          `json:"editHistory" mapstructure:"editHistory"`                   // Record each edit of a bridged message for GET /edits/{id}
+	DisappearingMessages        string        `json:"disappearingMessages" mapstructure:"disappearingMessages"` // How disappearing WhatsApp messages are relayed: "" (as permanent), "label" or "timer"
+	SimulateTyping              bool          `json:"simulateTyping" mapstructure:"simulateTyping"`             // Show the typing indicator for a time proportional to the text before sending it
+	SimulateTypingMaxSec        int           `json:"simulateTypingMaxSec" mapstructure:"simulateTypingMaxSec"` // Cap on the simulated typing time (0 = default)
+	RelaySignalTyping           bool          `json:"relaySignalTyping" mapstructure:"relaySignalTyping"`       // Show "typing..." in WhatsApp while the Signal user is typing a reply
+	ShowSentTime                bool          `json:"showSentTime" mapstructure:"showSentTime"`                 // Prefix messages relayed to Signal with the time they were sent on WhatsApp
+	AutoReplyCooldownMin        int           `json:"autoReplyCooldownMin" mapstructure:"autoReplyCooldownMin"` // Minimum time between auto-replies to the same chat (0 = default)
+	RelayPins                   bool          `json:"relayPins" mapstructure:"relayPins"`                       // Relay messages pinned in WhatsApp chats to Signal as a notice
+	SessionAutoCreate           bool          `json:"sessionAutoCreate" mapstructure:"sessionAutoCreate"`       // Create the session in WAHA at startup if it does not exist
+	WebhookURL                  string        `json:"webhookURL" mapstructure:"webhookURL"`                     // WhatSignal's webhook URL as WAHA reaches it, set on sessions WhatSignal creates
+	SessionProxy                string        `json:"sessionProxy" mapstructure:"sessionProxy"`                 // Proxy URL for the WhatsApp connection of sessions WhatSignal creates
+	// ReactionAggregateWindowSec relays the reactions to a message as one tally per window
+	// instead of a notice for each (0 = disabled)
+	ReactionAggregateWindowSec int `json:"reactionAggregateWindowSec" mapstructure:"reactionAggregateWindowSec"`
+	// NativeReactions relays reactions as a Signal reaction on the bridged message, taken back
+	// when the WhatsApp reaction is removed, instead of a text notice
+	NativeReactions bool `json:"nativeReactions" mapstructure:"nativeReactions"`
+	// MaxCaptionLength is the longest media caption, in characters, sent to WhatsApp; text
+	// beyond it follows the media as a separate message (0 = default)
+	MaxCaptionLength int `json:"maxCaptionLength" mapstructure:"maxCaptionLength"`
+	// ContactRefreshOnRelay names senders from the contact cache alone while relaying, and
+	// refreshes a missing or stale contact from WAHA in the background
+	ContactRefreshOnRelay   bool `json:"contactRefreshOnRelay" mapstructure:"contactRefreshOnRelay"`
+	ContactRefreshPerMinute int  `json:"contactRefreshPerMinute" mapstructure:"contactRefreshPerMinute"` // Cap on background contact refreshes (0 = default)
+	// BlockViewOnce drops view-once photos and videos instead of relaying them, as they would
+	// stay on Signal for good
+	BlockViewOnce bool `json:"blockViewOnce" mapstructure:"blockViewOnce"`
+	// LiveLocationIntervalSec relays a live location shared in a chat as at most one Signal
+	// update per interval with the latest coordinates, and a note when it ends (0 = not relayed)
+	LiveLocationIntervalSec int `json:"liveLocationIntervalSec" mapstructure:"liveLocationIntervalSec"`
+	// MarkSeenOnReply marks a WhatsApp chat seen after a Signal message is relayed to it, so
+	// replying from Signal clears the chat's unread indicator
+	MarkSeenOnReply bool `json:"markSeenOnReply" mapstructure:"markSeenOnReply"`
 }
 
+// Values for WhatsAppConfig.DisappearingMessages
+const (
+	DisappearingLabel = "label" // Append "⏳ disappears in ..." to the relayed message
+	DisappearingTimer = "timer" // Set the Signal conversation's disappearing-messages timer to match
+)
+
 // GroupConfig holds group chat related configurations
 type GroupConfig struct {
 	CacheHours    int  `json:"cacheHours" mapstructure:"cacheHours"`
@@ -47,11 +97,36 @@ type SignalConfig struct {
 	PollingEnabled          bool   `json:"pollingEnabled" mapstructure:"pollingEnabled"`
 	AttachmentsDir          string `json:"attachmentsDir" mapstructure:"attachmentsDir"`
 	HTTPTimeoutSec          int    `json:"httpTimeoutSec" mapstructure:"httpTimeoutSec"`
-	StrictInit              bool   `json:"strictInit" mapstructure:"strictInit"`                 // If true, fail startup on Signal initialization failure
-	PollWorkers             int    `json:"pollWorkers" mapstructure:"pollWorkers"`               // Number of parallel workers for processing polled messages (0 = sequential)
-	ForceNativePolling      bool   `json:"forceNativePolling" mapstructure:"forceNativePolling"` // Override auto-detection; always use HTTP polling even if signal-cli reports json-rpc mode
+	StrictInit              bool   `json:"strictInit" mapstructure:"strictInit"`                         // If true, fail startup on Signal initialization failure
+	PollWorkers             int    `json:"pollWorkers" mapstructure:"pollWorkers"`                       // Number of parallel workers for processing polled messages (0 = sequential)
+	ForceNativePolling      bool   `json:"forceNativePolling" mapstructure:"forceNativePolling"`         // Override auto-detection; always use HTTP polling even if signal-cli reports json-rpc mode
+	TranslateFormatting     bool   `json:"translateFormatting" mapstructure:"translateFormatting"`       // Convert WhatsApp *bold*/_italic_/~strike~ into Signal text styles
+	StripFormatting         bool   `json:"stripFormatting" mapstructure:"stripFormatting"`               // Remove WhatsApp formatting markers when translateFormatting is off
+	PersistCursor           bool   `json:"persistCursor" mapstructure:"persistCursor"`                   // Store the newest processed envelope timestamp to skip redelivered envelopes after a restart
+	RelayOrphanReplies      bool   `json:"relayOrphanReplies" mapstructure:"relayOrphanReplies"`         // Relay replies quoting unknown messages to the quoted author's chat, prefixed with the quoted text
+	ReactionDedupWindowSec  int    `json:"reactionDedupWindowSec" mapstructure:"reactionDedupWindowSec"` // Skip a reaction identical to one relayed within this window (0 = disabled)
+	OpsRecipient            string `json:"opsRecipient" mapstructure:"opsRecipient"`                     // Signal number that gets system notices (session down, WAHA waiting) instead of the channel destination
+	AmbiguousReplyRouting   string `json:"ambiguousReplyRouting" mapstructure:"ambiguousReplyRouting"`   // Where a reply goes when its chat was bridged by several sessions: "recent" (default) or "ask"
+	NotifyOnFailure         bool   `json:"notifyOnFailure" mapstructure:"notifyOnFailure"`               // Reply on Signal when a message could not be delivered to WhatsApp
+	// DeliveryReceipts records messages relayed to Signal as sent until Signal's delivery
+	// receipt for them arrives, instead of as delivered once signal-cli accepts them
+	DeliveryReceipts bool `json:"deliveryReceipts" mapstructure:"deliveryReceipts"`
+	// NewThreadGraceMs is how long a Signal message with no WhatsApp chat to go to waits for
+	// the mapping of a message being relayed at the same time before it is rejected (0 = disabled)
+	NewThreadGraceMs int `json:"newThreadGraceMs" mapstructure:"newThreadGraceMs"`
+	// StrictAccountMatch drops received envelopes whose account is not IntermediaryPhoneNumber
+	StrictAccountMatch bool `json:"strictAccountMatch" mapstructure:"strictAccountMatch"`
+	// PersistReactionDedup stores the reactions suppressed by ReactionDedupWindowSec in the
+	// database, so a reaction redelivered after a restart is still sent only once
+	PersistReactionDedup bool `json:"persistReactionDedup" mapstructure:"persistReactionDedup"`
 }
 
+// Values for SignalConfig.AmbiguousReplyRouting
+const (
+	AmbiguousReplyRecent = "recent" // Send to the session with the most recent activity in the chat
+	AmbiguousReplyAsk    = "ask"    // Ask on Signal to resend the reply with an @session tag
+)
+
 // DatabaseConfig holds database related configurations
 type DatabaseConfig struct {
 	Path               string `json:"path"`
@@ -59,16 +134,61 @@ type DatabaseConfig struct {
 	MaxIdleConnections int    `json:"maxIdleConnections" mapstructure:"maxIdleConnections"`
 	ConnMaxLifetimeSec int    `json:"connMaxLifetimeSec" mapstructure:"connMaxLifetimeSec"`
 	ConnMaxIdleTimeSec int    `json:"connMaxIdleTimeSec" mapstructure:"connMaxIdleTimeSec"`
+	// ShardBySession keeps each session's message mappings, groups and chat settings in a
+	// database file of its own next to Path
+	ShardBySession bool `json:"shardBySession,omitempty" mapstructure:"shardBySession"`
 }
 
 // MediaConfig holds media related configurations
 type MediaConfig struct {
-	CacheDir        string            `json:"cache_dir"`
-	MaxSizeMB       MediaSizeLimits   `json:"maxSizeMB"`
-	AllowedTypes    MediaAllowedTypes `json:"allowedTypes"`
-	DownloadTimeout int               `json:"downloadTimeoutSec" mapstructure:"downloadTimeoutSec"`
+	CacheDir               string            `json:"cache_dir"`
+	MaxSizeMB              MediaSizeLimits   `json:"maxSizeMB"`
+	AllowedTypes           MediaAllowedTypes `json:"allowedTypes"`
+	DownloadTimeout        int               `json:"downloadTimeoutSec" mapstructure:"downloadTimeoutSec"`
+	DownloadUserAgent      string            `json:"downloadUserAgent" mapstructure:"downloadUserAgent"`           // User-Agent sent with media URL downloads (empty = Go default)
+	DownloadHeaders        map[string]string `json:"downloadHeaders" mapstructure:"downloadHeaders"`               // Extra headers sent with media URL downloads, e.g. for a reverse proxy
+	DedupWindowSec         int               `json:"dedupWindowSec" mapstructure:"dedupWindowSec"`                 // Suppress identical media relayed to the same chat within this window (0 = disabled)
+	CaptionJoinWindowMs    int               `json:"captionJoinWindowMs" mapstructure:"captionJoinWindowMs"`       // Join a text sent within this window after media into the media's caption (0 = disabled)
+	MaxConcurrentDownloads int               `json:"maxConcurrentDownloads" mapstructure:"maxConcurrentDownloads"` // Cap on simultaneous media URL downloads (0 = default)
+	DownloadResumeAttempts int               `json:"downloadResumeAttempts" mapstructure:"downloadResumeAttempts"` // Times a dropped media URL download is resumed with an HTTP Range request (0 = disabled)
+	PersistIndex           bool              `json:"persistIndex" mapstructure:"persistIndex"`                     // Keep an index.json of cached media so restarts need no directory scan
+	VerifyCache            bool              `json:"verifyCache" mapstructure:"verifyCache"`                       // Re-hash a cached file before reusing it and replace it from the source if it is corrupted
+	OversizeBehavior       string            `json:"oversizeBehavior" mapstructure:"oversizeBehavior"`             // What to do with Signal attachments over the size limit: "note" (default) or "compress"
+	CompressCommand        []string          `json:"compressCommand" mapstructure:"compressCommand"`               // Command that shrinks a file for "compress"; supports {input}, {output} and {maxBytes}
+	RetentionByType        MediaRetention    `json:"retentionByType" mapstructure:"retentionByType"`               // Per-type cache retention in days, overriding retentionDays (0 = use retentionDays)
+	// SessionDirs stores each session's cached media in a subdirectory of CacheDir named after
+	// the session instead of in CacheDir itself
+	SessionDirs bool `json:"sessionDirs" mapstructure:"sessionDirs"`
+	// AutoOrientImages re-encodes JPEG images upright from their EXIF orientation, so photos
+	// whose viewer ignores the tag are not shown turned
+	AutoOrientImages bool `json:"autoOrientImages" mapstructure:"autoOrientImages"`
+	// ExtensionDetectionOrder lists the sources tried, in order, for the file type of a media URL
+	// download (empty = contentType, then url). The content signature is always tried last.
+	ExtensionDetectionOrder []string `json:"extensionDetectionOrder" mapstructure:"extensionDetectionOrder"`
+	// OrphanPurgeGraceHours makes each cleanup run delete cached media that no message mapping
+	// refers to once it has gone unused for this many hours (0 = disabled)
+	OrphanPurgeGraceHours int `json:"orphanPurgeGraceHours" mapstructure:"orphanPurgeGraceHours"`
+	// MaxAttachmentsPerRelay is the most attachments of one Signal message relayed to WhatsApp;
+	// the rest are dropped with a note in the message text (0 = unlimited)
+	MaxAttachmentsPerRelay int `json:"maxAttachmentsPerRelay" mapstructure:"maxAttachmentsPerRelay"`
+	// MaxCacheSizeMB makes each cleanup run delete the least recently used cached media until
+	// the cache is no larger than this (0 = unlimited)
+	MaxCacheSizeMB int `json:"maxCacheSizeMB" mapstructure:"maxCacheSizeMB"`
 }
 
+// Values for MediaConfig.OversizeBehavior
+const (
+	MediaOversizeNote     = "note"     // Drop the attachment and add a note to the message
+	MediaOversizeCompress = "compress" // Shrink the attachment with CompressCommand, falling back to a note
+)
+
+// Values for MediaConfig.ExtensionDetectionOrder
+const (
+	MediaExtensionFromContentType = "contentType" // The response's Content-Type header
+	MediaExtensionFromURL         = "url"         // The extension of the URL path
+	MediaExtensionFromSignature   = "signature"   // The file's leading bytes
+)
+
 // MediaSizeLimits defines size limits for different media types in MB
 type MediaSizeLimits struct {
 	Image    int `json:"image"`
@@ -77,6 +197,15 @@ type MediaSizeLimits struct {
 	Voice    int `json:"voice"`
 }
 
+// MediaRetention defines how many days cached media of each type is kept. A zero value uses
+// the global retentionDays.
+type MediaRetention struct {
+	Image    int `json:"image"`
+	Video    int `json:"video"`
+	Document int `json:"document"`
+	Voice    int `json:"voice"`
+}
+
 // MediaAllowedTypes defines allowed file extensions for different media types
 type MediaAllowedTypes struct {
 	Image    []string `json:"image"`
@@ -85,6 +214,43 @@ type MediaAllowedTypes struct {
 	Voice    []string `json:"voice"`
 }
 
+// WithOverride returns m with the size limits and allowed types set in override, such as a
+// channel's media settings. Each limit or type list left unset in override keeps m's value.
+func (m MediaConfig) WithOverride(override *MediaConfig) MediaConfig {
+	if override == nil {
+		return m
+	}
+
+	sizes := override.MaxSizeMB
+	if sizes.Image > 0 {
+		m.MaxSizeMB.Image = sizes.Image
+	}
+	if sizes.Video > 0 {
+		m.MaxSizeMB.Video = sizes.Video
+	}
+	if sizes.Document > 0 {
+		m.MaxSizeMB.Document = sizes.Document
+	}
+	if sizes.Voice > 0 {
+		m.MaxSizeMB.Voice = sizes.Voice
+	}
+
+	types := override.AllowedTypes
+	if len(types.Image) > 0 {
+		m.AllowedTypes.Image = types.Image
+	}
+	if len(types.Video) > 0 {
+		m.AllowedTypes.Video = types.Video
+	}
+	if len(types.Document) > 0 {
+		m.AllowedTypes.Document = types.Document
+	}
+	if len(types.Voice) > 0 {
+		m.AllowedTypes.Voice = types.Voice
+	}
+	return m
+}
+
 // RetryConfig holds retry related configurations
 type RetryConfig struct {
 	InitialBackoffMs int `json:"initialBackoffMs"`
@@ -102,7 +268,27 @@ type ServerConfig struct {
 	RateLimitPerMinute      int      `json:"rateLimitPerMinute" mapstructure:"rateLimitPerMinute"`
 	RateLimitCleanupMinutes int      `json:"rateLimitCleanupMinutes" mapstructure:"rateLimitCleanupMinutes"`
 	CleanupIntervalHours    int      `json:"cleanupIntervalHours" mapstructure:"cleanupIntervalHours"`
+	ContactRetentionDays    int      `json:"contactRetentionDays" mapstructure:"contactRetentionDays"` // 0 keeps cached contacts indefinitely
+	GroupRetentionDays      int      `json:"groupRetentionDays" mapstructure:"groupRetentionDays"`     // 0 keeps cached groups indefinitely
 	TrustedProxies          []string `json:"trustedProxies" mapstructure:"trustedProxies"`
+	DisplayTimezone         string   `json:"displayTimezone" mapstructure:"displayTimezone"` // IANA time zone for times shown in relayed messages; empty or invalid uses UTC
+	MaxRelayAgeMin          int      `json:"maxRelayAgeMin" mapstructure:"maxRelayAgeMin"`   // WhatsApp messages sent longer ago are stored but not relayed; 0 relays messages of any age
+	// DebugPayloadDir keeps the raw payload of each WhatsApp webhook that failed to process, for
+	// debugging; empty disables it
+	DebugPayloadDir      string `json:"debugPayloadDir" mapstructure:"debugPayloadDir"`
+	DebugPayloadRedact   bool   `json:"debugPayloadRedact" mapstructure:"debugPayloadRedact"`     // Replace message text and media fields in kept payloads
+	DebugPayloadMaxFiles int    `json:"debugPayloadMaxFiles" mapstructure:"debugPayloadMaxFiles"` // Newest payloads kept; older ones are deleted (0 = default)
+	// MaxQueueDepth bounds the WhatsApp webhooks and Signal polls processed at once; beyond it
+	// webhooks are answered 503 and the Signal poller waits. 0 leaves processing unbounded.
+	MaxQueueDepth int `json:"maxQueueDepth" mapstructure:"maxQueueDepth"`
+	// Port is the port the HTTP server listens on; the PORT environment variable overrides it.
+	// 0 uses the default 8082.
+	Port int `json:"port" mapstructure:"port"`
+	// WebhookPath is the path WAHA webhooks are received at; empty uses /webhook/whatsapp
+	WebhookPath string `json:"webhookPath" mapstructure:"webhookPath"`
+	// MetricsEnabled serves /metrics in the Prometheus text format for scraping; the JSON
+	// report stays available at /metrics?format=json
+	MetricsEnabled bool `json:"metricsEnabled" mapstructure:"metricsEnabled"`
 }
 
 // TracingConfig holds OpenTelemetry tracing configurations
@@ -116,14 +302,48 @@ type TracingConfig struct {
 	UseStdout          bool    `json:"use_stdout" mapstructure:"use_stdout"`
 	OTLPInsecure       bool    `json:"otlp_insecure" mapstructure:"otlp_insecure"`
 	ShutdownTimeoutSec int     `json:"shutdown_timeout_sec" mapstructure:"shutdown_timeout_sec"`
+	// DisableRequestIDHeader stops the per-message request ID being sent to WAHA and signal-cli
+	// as X-Request-ID; it is still added to log lines
+	DisableRequestIDHeader bool `json:"disable_request_id_header" mapstructure:"disable_request_id_header"`
 }
 
 // Channel represents a WhatsApp-Signal channel pairing
 type Channel struct {
-	WhatsAppSessionName          string `json:"whatsappSessionName" mapstructure:"whatsappSessionName"`
-	SignalDestinationPhoneNumber string `json:"signalDestinationPhoneNumber" mapstructure:"signalDestinationPhoneNumber"`
+	WhatsAppSessionName          string   `json:"whatsappSessionName" mapstructure:"whatsappSessionName"`
+	SignalDestinationPhoneNumber string   `json:"signalDestinationPhoneNumber" mapstructure:"signalDestinationPhoneNumber"`
+	BroadcastTargets             []string `json:"broadcastTargets,omitempty" mapstructure:"broadcastTargets"` // WhatsApp chat IDs that receive /broadcast messages
+	// RelayGroupParticipants relays members joining and leaving the session's groups to Signal
+	RelayGroupParticipants bool `json:"relayGroupParticipants,omitempty" mapstructure:"relayGroupParticipants"`
+	// WebhookSecret is the secret the session's WAHA signs webhooks with, instead of
+	// whatsapp.webhook_secret. Prefer setting it through WHATSIGNAL_WHATSAPP_WEBHOOK_SECRET_<SESSION>.
+	WebhookSecret string `json:"webhookSecret,omitempty" mapstructure:"webhookSecret"`
+	// Media overrides the global media.maxSizeMB and media.allowedTypes for the session's
+	// attachments. Fields left unset keep the global value.
+	Media *MediaConfig `json:"media,omitempty" mapstructure:"media"`
 }
 
+// TransformRule rewrites the text of relayed messages
+type TransformRule struct {
+	Type        string `json:"type" mapstructure:"type"`               // "replace", "prefix" or "suffix"
+	Direction   string `json:"direction" mapstructure:"direction"`     // "whatsapp_to_signal", "signal_to_whatsapp" or empty for both
+	Pattern     string `json:"pattern" mapstructure:"pattern"`         // Regular expression replaced by "replace"
+	Replacement string `json:"replacement" mapstructure:"replacement"` // Replacement for "replace"; may refer to groups as $1
+	Text        string `json:"text" mapstructure:"text"`               // Text added by "prefix" and "suffix"
+}
+
+// Values for TransformRule.Type
+const (
+	TransformReplace = "replace"
+	TransformPrefix  = "prefix"
+	TransformSuffix  = "suffix"
+)
+
+// Values for TransformRule.Direction
+const (
+	TransformWhatsAppToSignal = "whatsapp_to_signal"
+	TransformSignalToWhatsApp = "signal_to_whatsapp"
+)
+
 type ConfigError struct {
 	Message string `json:"message"`
 }
@@ -69,3 +69,28 @@ func TestConfig_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestMediaConfig_WithOverride(t *testing.T) {
+	global := MediaConfig{
+		CacheDir:  "/cache",
+		MaxSizeMB: MediaSizeLimits{Image: 5, Video: 100, Document: 100, Voice: 16},
+		AllowedTypes: MediaAllowedTypes{
+			Image:    []string{"jpg", "png"},
+			Video:    []string{"mp4"},
+			Document: []string{"pdf"},
+			Voice:    []string{"ogg"},
+		},
+	}
+
+	assert.Equal(t, global, global.WithOverride(nil))
+
+	merged := global.WithOverride(&MediaConfig{
+		MaxSizeMB:    MediaSizeLimits{Document: 200},
+		AllowedTypes: MediaAllowedTypes{Document: []string{"pdf", "zip"}},
+	})
+	assert.Equal(t, MediaSizeLimits{Image: 5, Video: 100, Document: 200, Voice: 16}, merged.MaxSizeMB)
+	assert.Equal(t, []string{"pdf", "zip"}, merged.AllowedTypes.Document)
+	assert.Equal(t, []string{"jpg", "png"}, merged.AllowedTypes.Image)
+	assert.Equal(t, "/cache", merged.CacheDir, "settings other than limits and types are not overridden")
+	assert.Equal(t, 100, global.MaxSizeMB.Document, "the global config is left unchanged")
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// GroupMapping routes the messages of a Signal group to a WhatsApp group within a session
+type GroupMapping struct {
+	ID              int64     `json:"id"`
+	SessionName     string    `json:"session"`
+	SignalGroupID   string    `json:"signalGroupId"`
+	WhatsAppGroupID string    `json:"whatsappGroupId"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
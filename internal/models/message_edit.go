@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// MessageEdit is one recorded edit of a bridged WhatsApp message
+type MessageEdit struct {
+	ID            int64     `json:"id"`
+	WhatsAppMsgID string    `json:"whatsappMessageId"`
+	SessionName   string    `json:"session"`
+	Body          string    `json:"body"`
+	EditedAt      time.Time `json:"editedAt"`
+}
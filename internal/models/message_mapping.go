@@ -28,3 +28,11 @@ type MessageMapping struct {
 	CreatedAt       time.Time      `json:"createdAt"`
 	UpdatedAt       time.Time      `json:"updatedAt"`
 }
+
+// MappingStats summarises the message mappings stored for a session
+type MappingStats struct {
+	Total    int                    `json:"total"`
+	Last24h  int                    `json:"last24h"`
+	Media    int                    `json:"media"`
+	ByStatus map[DeliveryStatus]int `json:"byStatus"`
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // FlexibleTimestamp handles JSON timestamps that may be integers or floats.
@@ -35,6 +37,16 @@ const (
 	EventMessageEdited   = "message.edited"
 	EventMessageACK      = "message.ack"
 	EventMessageWaiting  = "message.waiting"
+	// EventGroupParticipants and EventGroupV2Participants report members joining or leaving a
+	// group; WAHA releases with the v2 group events send the latter
+	EventGroupParticipants   = "group.participants"
+	EventGroupV2Participants = "group.v2.participants"
+)
+
+// Group participant change types
+const (
+	GroupParticipantsJoin  = "join"
+	GroupParticipantsLeave = "leave"
 )
 
 // WhatsApp webhook JSON field names
@@ -91,10 +103,43 @@ type WhatsAppWebhookPayload struct {
 			Text      string `json:"text"`
 			MessageID string `json:"messageId"`
 		} `json:"reaction"`
+		// ReplyTo is the message this one replies to, if any
+		ReplyTo *struct {
+			ID          string `json:"id"`
+			Participant string `json:"participant,omitempty"`
+			Body        string `json:"body,omitempty"`
+		} `json:"replyTo,omitempty"`
 		// _data contains engine-specific internal data that may include additional fields
 		Data *struct {
 			NotifyName string `json:"notifyName,omitempty"`
 			PushName   string `json:"pushName,omitempty"`
+			// EphemeralDuration is the disappearing-messages timer in seconds (WEBJS)
+			EphemeralDuration int `json:"ephemeralDuration,omitempty"`
+			// IsViewOnce marks view-once media (WEBJS)
+			IsViewOnce bool `json:"isViewOnce,omitempty"`
+			// QuotedMsg is the message a reply quotes (WEBJS)
+			QuotedMsg *struct {
+				Body    string `json:"body,omitempty"`
+				Caption string `json:"caption,omitempty"`
+			} `json:"quotedMsg,omitempty"`
+			// Message is the raw message content (NOWEB, GOWS); a disappearing message carries
+			// its timer as contextInfo.expiration on the content
+			Message json.RawMessage `json:"message,omitempty"`
+			// PaymentInfo is the amount and state of a WhatsApp Pay message (NOWEB, GOWS)
+			PaymentInfo json.RawMessage `json:"paymentInfo,omitempty"`
+			// Type is the message type (WEBJS); WhatsApp Pay messages are "payment" and carry
+			// the payment fields below, the status as a PaymentInfo.Status number
+			Type              string `json:"type,omitempty"`
+			PaymentAmount1000 int64  `json:"paymentAmount1000,omitempty"`
+			PaymentCurrency   string `json:"paymentCurrency,omitempty"`
+			PaymentStatus     int    `json:"paymentStatus,omitempty"`
+			PaymentNoteMsg    *struct {
+				Body string `json:"body,omitempty"`
+			} `json:"paymentNoteMsg,omitempty"`
+			// Lat, Lng and Loc are the coordinates and description of a "location" message (WEBJS)
+			Lat float64 `json:"lat,omitempty"`
+			Lng float64 `json:"lng,omitempty"`
+			Loc string  `json:"loc,omitempty"`
 		} `json:"_data,omitempty"`
 		// Fields for message.edited event
 		EditedMessageID *string `json:"editedMessageId,omitempty"`
@@ -110,3 +155,629 @@ type WhatsAppWebhookPayload struct {
 		Browser string `json:"browser"`
 	} `json:"environment"`
 }
+
+// EphemeralExpiry returns the disappearing-messages timer of the payload's message, or 0 when
+// the message does not disappear. WEBJS reports it as _data.ephemeralDuration; NOWEB and GOWS
+// put it in contextInfo.expiration of the message content.
+func (p *WhatsAppWebhookPayload) EphemeralExpiry() time.Duration {
+	data := p.Payload.Data
+	if data == nil {
+		return 0
+	}
+	if data.EphemeralDuration > 0 {
+		return time.Duration(data.EphemeralDuration) * time.Second
+	}
+	if len(data.Message) == 0 {
+		return 0
+	}
+
+	// The content is keyed by message type, e.g. extendedTextMessage or imageMessage
+	var content map[string]json.RawMessage
+	if err := json.Unmarshal(data.Message, &content); err != nil {
+		return 0
+	}
+	for _, raw := range content {
+		var typed struct {
+			ContextInfo *struct {
+				Expiration int `json:"expiration"`
+			} `json:"contextInfo"`
+		}
+		if err := json.Unmarshal(raw, &typed); err != nil || typed.ContextInfo == nil {
+			continue
+		}
+		if typed.ContextInfo.Expiration > 0 {
+			return time.Duration(typed.ContextInfo.Expiration) * time.Second
+		}
+	}
+	return 0
+}
+
+// IsViewOnce reports whether the payload's message is view-once media. WEBJS flags it as
+// _data.isViewOnce; NOWEB and GOWS wrap the content in a viewOnceMessage (or its V2 variants) or
+// set viewOnce on the image or video content.
+func (p *WhatsAppWebhookPayload) IsViewOnce() bool {
+	data := p.Payload.Data
+	if data == nil {
+		return false
+	}
+	if data.IsViewOnce {
+		return true
+	}
+	if len(data.Message) == 0 {
+		return false
+	}
+
+	var content map[string]json.RawMessage
+	if err := json.Unmarshal(data.Message, &content); err != nil {
+		return false
+	}
+	for key, raw := range content {
+		switch key {
+		case "viewOnceMessage", "viewOnceMessageV2", "viewOnceMessageV2Extension":
+			return true
+		}
+		var typed struct {
+			ViewOnce bool `json:"viewOnce"`
+		}
+		if err := json.Unmarshal(raw, &typed); err == nil && typed.ViewOnce {
+			return true
+		}
+	}
+	return false
+}
+
+// WhatsAppPin is a message pinned or unpinned in a WhatsApp chat
+type WhatsAppPin struct {
+	ChatID      string // Chat the message was pinned in
+	MessageID   string // Protocol ID of the pinned message, without WAHA's chat prefix
+	FromMe      bool   // The pinned message was sent by the session's account
+	Participant string // Sender of the pinned message in a group chat
+	Unpin       bool
+}
+
+// WAHAMessageID returns the pinned message's ID in the form WAHA uses for message IDs, e.g.
+// "false_123@c.us_ABC" or, in groups, "false_456@g.us_ABC_789@c.us"
+func (p WhatsAppPin) WAHAMessageID() string {
+	id := fmt.Sprintf("%t_%s_%s", p.FromMe, p.ChatID, p.MessageID)
+	if p.Participant != "" && strings.HasSuffix(p.ChatID, "@g.us") {
+		id += "_" + p.Participant
+	}
+	return id
+}
+
+// PinnedMessage reports whether the payload is a pin or unpin notice. WAHA delivers these as a
+// message event without a body; NOWEB and GOWS carry the pin as pinInChatMessage in the raw
+// message content, with the type given as a number or an enum name.
+func (p *WhatsAppWebhookPayload) PinnedMessage() (WhatsAppPin, bool) {
+	data := p.Payload.Data
+	if data == nil || len(data.Message) == 0 {
+		return WhatsAppPin{}, false
+	}
+
+	var content struct {
+		PinInChatMessage *struct {
+			Key struct {
+				RemoteJid   string `json:"remoteJid"`
+				FromMe      bool   `json:"fromMe"`
+				ID          string `json:"id"`
+				Participant string `json:"participant"`
+			} `json:"key"`
+			Type json.RawMessage `json:"type"`
+		} `json:"pinInChatMessage"`
+	}
+	if err := json.Unmarshal(data.Message, &content); err != nil || content.PinInChatMessage == nil {
+		return WhatsAppPin{}, false
+	}
+	pinMsg := content.PinInChatMessage
+	if pinMsg.Key.ID == "" {
+		return WhatsAppPin{}, false
+	}
+
+	var unpin bool
+	switch strings.Trim(string(pinMsg.Type), `"`) {
+	case "1", "PIN_FOR_ALL":
+	case "2", "UNPIN_FOR_ALL":
+		unpin = true
+	default:
+		return WhatsAppPin{}, false
+	}
+
+	chatID := p.Payload.From
+	if chatID == "" {
+		chatID = wahaChatID(pinMsg.Key.RemoteJid)
+	}
+	return WhatsAppPin{
+		ChatID:      chatID,
+		MessageID:   pinMsg.Key.ID,
+		FromMe:      pinMsg.Key.FromMe,
+		Participant: wahaChatID(pinMsg.Key.Participant),
+		Unpin:       unpin,
+	}, true
+}
+
+// WhatsAppProduct is a product from a WhatsApp Business catalog shared in a chat. Any field
+// may be empty: catalogs do not require a description or price.
+type WhatsAppProduct struct {
+	Title       string
+	Description string
+	// PriceAmount1000 is the price in thousandths of the currency unit, 0 when not listed
+	PriceAmount1000 int64
+	CurrencyCode    string
+}
+
+// protoInt64 reads a protobuf int64 rendered to JSON, which depending on the engine is a
+// number, a decimal string or a Long object of two 32-bit halves
+type protoInt64 int64
+
+func (v *protoInt64) UnmarshalJSON(data []byte) error {
+	var long struct {
+		Low  int32 `json:"low"`
+		High int32 `json:"high"`
+	}
+	if err := json.Unmarshal(data, &long); err == nil {
+		*v = protoInt64(int64(long.High)<<32 | int64(uint32(long.Low)))
+		return nil
+	}
+
+	var i int64
+	if err := json.Unmarshal([]byte(strings.Trim(string(data), `"`)), &i); err != nil {
+		return fmt.Errorf("int64 must be a number, got %s", string(data))
+	}
+	*v = protoInt64(i)
+	return nil
+}
+
+// ProductMessage reports whether the payload shares a catalog product. WAHA delivers these as a
+// message event whose body is often empty; NOWEB and GOWS carry the product as productMessage
+// in the raw message content.
+func (p *WhatsAppWebhookPayload) ProductMessage() (WhatsAppProduct, bool) {
+	data := p.Payload.Data
+	if data == nil || len(data.Message) == 0 {
+		return WhatsAppProduct{}, false
+	}
+
+	var content struct {
+		ProductMessage *struct {
+			Product *struct {
+				Title           string     `json:"title"`
+				Description     string     `json:"description"`
+				PriceAmount1000 protoInt64 `json:"priceAmount1000"`
+				CurrencyCode    string     `json:"currencyCode"`
+			} `json:"product"`
+		} `json:"productMessage"`
+	}
+	if err := json.Unmarshal(data.Message, &content); err != nil || content.ProductMessage == nil || content.ProductMessage.Product == nil {
+		return WhatsAppProduct{}, false
+	}
+	product := content.ProductMessage.Product
+	return WhatsAppProduct{
+		Title:           strings.TrimSpace(product.Title),
+		Description:     strings.TrimSpace(product.Description),
+		PriceAmount1000: int64(product.PriceAmount1000),
+		CurrencyCode:    strings.TrimSpace(product.CurrencyCode),
+	}, true
+}
+
+// WhatsAppPayment is a WhatsApp Pay message: a payment sent, or a payment request made,
+// declined or cancelled. Any field may be empty when the engine does not report it.
+type WhatsAppPayment struct {
+	// Amount1000 is the amount in thousandths of the currency unit, 0 when not reported
+	Amount1000   int64
+	CurrencyCode string
+	// Status is the state of the payment in words, such as "completed" or "requested"
+	Status string
+	Note   string
+}
+
+// paymentStatusNames are the values of the PaymentInfo.Status protobuf enum, by number
+var paymentStatusNames = []string{
+	"UNKNOWN_STATUS", "PROCESSING", "SENT", "NEED_TO_ACCEPT", "COMPLETE", "COULD_NOT_COMPLETE",
+	"REFUNDED", "EXPIRED", "REJECTED", "CANCELLED", "WAITING_FOR_PAYER", "WAITING",
+}
+
+// paymentStatusWords describes the PaymentInfo.Status values for people
+var paymentStatusWords = map[string]string{
+	"PROCESSING":         "processing",
+	"SENT":               "sent",
+	"NEED_TO_ACCEPT":     "awaiting acceptance",
+	"COMPLETE":           "completed",
+	"COULD_NOT_COMPLETE": "failed",
+	"REFUNDED":           "refunded",
+	"EXPIRED":            "expired",
+	"REJECTED":           "rejected",
+	"CANCELLED":          "cancelled",
+	"WAITING_FOR_PAYER":  "awaiting payment",
+	"WAITING":            "pending",
+}
+
+// paymentStatus reads a PaymentInfo.Status enum, which engines render as its name or its number,
+// as words. An unknown status is left empty.
+type paymentStatus string
+
+func (s *paymentStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var number int
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("payment status must be a name or a number, got %s", string(data))
+		}
+		name = paymentStatusName(number)
+	}
+	*s = paymentStatus(paymentStatusWords[strings.ToUpper(name)])
+	return nil
+}
+
+// paymentStatusName returns the name of a PaymentInfo.Status number, or "" for an unknown one
+func paymentStatusName(number int) string {
+	if number < 0 || number >= len(paymentStatusNames) {
+		return ""
+	}
+	return paymentStatusNames[number]
+}
+
+// protoMoney is a protobuf Money amount: value divided by offset (such as 100 for cents), in
+// currencyCode
+type protoMoney struct {
+	Value        protoInt64 `json:"value"`
+	Offset       int64      `json:"offset"`
+	CurrencyCode string     `json:"currencyCode"`
+}
+
+// amount1000 returns the amount in thousandths of the currency unit; an amount without an
+// offset is taken to be in whole units
+func (m *protoMoney) amount1000() int64 {
+	if m.Offset <= 0 {
+		return int64(m.Value) * 1000
+	}
+	return int64(m.Value) * 1000 / m.Offset
+}
+
+// paymentNote is the note attached to a payment or payment request
+type paymentNote struct {
+	Conversation        string `json:"conversation"`
+	ExtendedTextMessage *struct {
+		Text string `json:"text"`
+	} `json:"extendedTextMessage"`
+}
+
+func (n *paymentNote) text() string {
+	if n == nil {
+		return ""
+	}
+	if n.ExtendedTextMessage != nil && strings.TrimSpace(n.ExtendedTextMessage.Text) != "" {
+		return strings.TrimSpace(n.ExtendedTextMessage.Text)
+	}
+	return strings.TrimSpace(n.Conversation)
+}
+
+// PaymentMessage reports whether the payload is a WhatsApp Pay message. WAHA delivers these as a
+// message event with an empty body. WEBJS marks them with _data.type "payment" and the
+// _data.payment* fields; NOWEB and GOWS carry a sendPaymentMessage, requestPaymentMessage,
+// declinePaymentRequestMessage or cancelPaymentRequestMessage in the raw message content, with
+// the amount and state in _data.paymentInfo.
+func (p *WhatsAppWebhookPayload) PaymentMessage() (WhatsAppPayment, bool) {
+	data := p.Payload.Data
+	if data == nil {
+		return WhatsAppPayment{}, false
+	}
+	if data.Type == "payment" {
+		payment := WhatsAppPayment{
+			Amount1000:   data.PaymentAmount1000,
+			CurrencyCode: strings.TrimSpace(data.PaymentCurrency),
+			Status:       paymentStatusWords[paymentStatusName(data.PaymentStatus)],
+		}
+		if data.PaymentNoteMsg != nil {
+			payment.Note = strings.TrimSpace(data.PaymentNoteMsg.Body)
+		}
+		return payment, true
+	}
+	if len(data.Message) == 0 {
+		return WhatsAppPayment{}, false
+	}
+
+	var content struct {
+		SendPaymentMessage *struct {
+			NoteMessage *paymentNote `json:"noteMessage"`
+		} `json:"sendPaymentMessage"`
+		RequestPaymentMessage *struct {
+			NoteMessage         *paymentNote `json:"noteMessage"`
+			CurrencyCodeIso4217 string       `json:"currencyCodeIso4217"`
+			Amount1000          protoInt64   `json:"amount1000"`
+			Amount              *protoMoney  `json:"amount"`
+		} `json:"requestPaymentMessage"`
+		DeclinePaymentRequestMessage *struct{} `json:"declinePaymentRequestMessage"`
+		CancelPaymentRequestMessage  *struct{} `json:"cancelPaymentRequestMessage"`
+	}
+	if err := json.Unmarshal(data.Message, &content); err != nil {
+		return WhatsAppPayment{}, false
+	}
+
+	var payment WhatsAppPayment
+	switch {
+	case content.SendPaymentMessage != nil:
+		payment.Status = "sent"
+		payment.Note = content.SendPaymentMessage.NoteMessage.text()
+	case content.RequestPaymentMessage != nil:
+		request := content.RequestPaymentMessage
+		payment.Status = "requested"
+		payment.Note = request.NoteMessage.text()
+		payment.Amount1000 = int64(request.Amount1000)
+		payment.CurrencyCode = request.CurrencyCodeIso4217
+		if request.Amount != nil {
+			payment.Amount1000 = request.Amount.amount1000()
+			if request.Amount.CurrencyCode != "" {
+				payment.CurrencyCode = request.Amount.CurrencyCode
+			}
+		}
+	case content.DeclinePaymentRequestMessage != nil:
+		payment.Status = "declined"
+	case content.CancelPaymentRequestMessage != nil:
+		payment.Status = "cancelled"
+	default:
+		return WhatsAppPayment{}, false
+	}
+
+	// The payment info, when present, has the latest state of the payment
+	var info struct {
+		CurrencyDeprecated string        `json:"currencyDeprecated"`
+		Amount1000         protoInt64    `json:"amount1000"`
+		Amount             *protoMoney   `json:"amount"`
+		Status             paymentStatus `json:"status"`
+	}
+	if len(data.PaymentInfo) > 0 && json.Unmarshal(data.PaymentInfo, &info) == nil {
+		if info.Amount != nil && info.Amount.Value != 0 {
+			payment.Amount1000 = info.Amount.amount1000()
+			payment.CurrencyCode = info.Amount.CurrencyCode
+		} else if info.Amount1000 != 0 {
+			payment.Amount1000 = int64(info.Amount1000)
+			payment.CurrencyCode = info.CurrencyDeprecated
+		}
+		if info.Status != "" {
+			payment.Status = string(info.Status)
+		}
+	}
+	payment.CurrencyCode = strings.TrimSpace(payment.CurrencyCode)
+	return payment, true
+}
+
+// WhatsAppLiveLocation is one update of a location shared live in a WhatsApp chat
+type WhatsAppLiveLocation struct {
+	// ID identifies the live share; WhatsApp sends every update under the ID of the message
+	// that started it
+	ID         string
+	ChatID     string
+	Sender     string
+	SenderName string
+	Latitude   float64
+	Longitude  float64
+	Caption    string
+}
+
+// LiveLocation reports whether the payload is an update of a live location. NOWEB and GOWS
+// carry it in the raw message content as liveLocationMessage, or as a locationMessage with the
+// isLive flag; a location shared once is not live. ChatID, Sender and SenderName are left for
+// the caller.
+func (p *WhatsAppWebhookPayload) LiveLocation() (WhatsAppLiveLocation, bool) {
+	data := p.Payload.Data
+	if data == nil || len(data.Message) == 0 {
+		return WhatsAppLiveLocation{}, false
+	}
+
+	type location struct {
+		DegreesLatitude  float64 `json:"degreesLatitude"`
+		DegreesLongitude float64 `json:"degreesLongitude"`
+		Caption          string  `json:"caption"`
+		IsLive           bool    `json:"isLive"`
+	}
+	var content struct {
+		LiveLocationMessage *location `json:"liveLocationMessage"`
+		LocationMessage     *location `json:"locationMessage"`
+	}
+	if err := json.Unmarshal(data.Message, &content); err != nil {
+		return WhatsAppLiveLocation{}, false
+	}
+	loc := content.LiveLocationMessage
+	if loc == nil && content.LocationMessage != nil && content.LocationMessage.IsLive {
+		loc = content.LocationMessage
+	}
+	if loc == nil || p.Payload.ID == "" {
+		return WhatsAppLiveLocation{}, false
+	}
+	return WhatsAppLiveLocation{
+		ID:        p.Payload.ID,
+		Latitude:  loc.DegreesLatitude,
+		Longitude: loc.DegreesLongitude,
+		Caption:   strings.TrimSpace(loc.Caption),
+	}, true
+}
+
+// WhatsAppLocation is a location pin shared once in a WhatsApp chat
+type WhatsAppLocation struct {
+	Latitude  float64
+	Longitude float64
+	Name      string // Name of the place, when one was picked
+	Address   string
+}
+
+// Location reports whether the payload shares a location pin. WEBJS marks it with _data.type
+// "location" and puts the coordinates in _data.lat and _data.lng, with the place's name and
+// address as the lines of _data.loc; its body is a map thumbnail. NOWEB and GOWS carry a
+// locationMessage in the raw message content. Live locations are reported by LiveLocation.
+func (p *WhatsAppWebhookPayload) Location() (WhatsAppLocation, bool) {
+	data := p.Payload.Data
+	if data == nil {
+		return WhatsAppLocation{}, false
+	}
+	if data.Type == "location" {
+		loc := WhatsAppLocation{Latitude: data.Lat, Longitude: data.Lng}
+		name, address, _ := strings.Cut(strings.TrimSpace(data.Loc), "\n")
+		loc.Name = strings.TrimSpace(name)
+		loc.Address = strings.TrimSpace(address)
+		return loc, true
+	}
+	if len(data.Message) == 0 {
+		return WhatsAppLocation{}, false
+	}
+
+	var content struct {
+		LocationMessage *struct {
+			DegreesLatitude  float64 `json:"degreesLatitude"`
+			DegreesLongitude float64 `json:"degreesLongitude"`
+			Name             string  `json:"name"`
+			Address          string  `json:"address"`
+			IsLive           bool    `json:"isLive"`
+		} `json:"locationMessage"`
+	}
+	if err := json.Unmarshal(data.Message, &content); err != nil {
+		return WhatsAppLocation{}, false
+	}
+	loc := content.LocationMessage
+	if loc == nil || loc.IsLive {
+		return WhatsAppLocation{}, false
+	}
+	return WhatsAppLocation{
+		Latitude:  loc.DegreesLatitude,
+		Longitude: loc.DegreesLongitude,
+		Name:      strings.TrimSpace(loc.Name),
+		Address:   strings.TrimSpace(loc.Address),
+	}, true
+}
+
+// WhatsAppQuotedMessage is the message a WhatsApp message replies to
+type WhatsAppQuotedMessage struct {
+	ID          string // WAHA ID of the quoted message
+	Participant string // Sender of the quoted message, when WAHA reports it
+	Body        string // Text or caption of the quoted message, when WAHA includes it
+}
+
+// QuotedMessage reports whether the payload's message is a reply and returns what it quotes.
+// WAHA reports replies as replyTo; when that has no body, the quoted text is read from the
+// engine data: _data.quotedMsg on WEBJS, contextInfo.quotedMessage of the content on NOWEB and
+// GOWS.
+func (p *WhatsAppWebhookPayload) QuotedMessage() (WhatsAppQuotedMessage, bool) {
+	replyTo := p.Payload.ReplyTo
+	if replyTo == nil || replyTo.ID == "" {
+		return WhatsAppQuotedMessage{}, false
+	}
+	quote := WhatsAppQuotedMessage{ID: replyTo.ID, Participant: replyTo.Participant, Body: strings.TrimSpace(replyTo.Body)}
+	if quote.Body == "" {
+		quote.Body = p.engineQuotedText()
+	}
+	return quote, true
+}
+
+// engineQuotedText returns the text of the quoted message from the engine data, or ""
+func (p *WhatsAppWebhookPayload) engineQuotedText() string {
+	data := p.Payload.Data
+	if data == nil {
+		return ""
+	}
+	if quoted := data.QuotedMsg; quoted != nil {
+		if body := strings.TrimSpace(quoted.Body); body != "" {
+			return body
+		}
+		return strings.TrimSpace(quoted.Caption)
+	}
+	if len(data.Message) == 0 {
+		return ""
+	}
+
+	var content map[string]json.RawMessage
+	if err := json.Unmarshal(data.Message, &content); err != nil {
+		return ""
+	}
+	for _, raw := range content {
+		var typed struct {
+			ContextInfo *struct {
+				QuotedMessage *struct {
+					Conversation        string `json:"conversation"`
+					ExtendedTextMessage *struct {
+						Text string `json:"text"`
+					} `json:"extendedTextMessage"`
+					ImageMessage *struct {
+						Caption string `json:"caption"`
+					} `json:"imageMessage"`
+					VideoMessage *struct {
+						Caption string `json:"caption"`
+					} `json:"videoMessage"`
+				} `json:"quotedMessage"`
+			} `json:"contextInfo"`
+		}
+		if err := json.Unmarshal(raw, &typed); err != nil || typed.ContextInfo == nil || typed.ContextInfo.QuotedMessage == nil {
+			continue
+		}
+		quoted := typed.ContextInfo.QuotedMessage
+		switch {
+		case quoted.Conversation != "":
+			return strings.TrimSpace(quoted.Conversation)
+		case quoted.ExtendedTextMessage != nil:
+			return strings.TrimSpace(quoted.ExtendedTextMessage.Text)
+		case quoted.ImageMessage != nil:
+			return strings.TrimSpace(quoted.ImageMessage.Caption)
+		case quoted.VideoMessage != nil:
+			return strings.TrimSpace(quoted.VideoMessage.Caption)
+		}
+	}
+	return ""
+}
+
+// WhatsAppGroupParticipants is a change to the members of a WhatsApp group
+type WhatsAppGroupParticipants struct {
+	GroupID      string
+	Action       string   // GroupParticipantsJoin or GroupParticipantsLeave
+	Participants []string // WhatsApp IDs of the members who joined or left
+}
+
+// ParseGroupParticipantChange reads a group participant event from its webhook body and
+// reports whether it is members joining or leaving. Promotions and demotions are not changes
+// to membership and report false, as does a body without a group or participants.
+func ParseGroupParticipantChange(body []byte) (WhatsAppGroupParticipants, bool) {
+	var event struct {
+		Payload struct {
+			Group struct {
+				ID string `json:"id"`
+			} `json:"group"`
+			Type         string `json:"type"` // join, leave, promote or demote
+			Participants []struct {
+				ID string `json:"id"`
+			} `json:"participants"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil || event.Payload.Group.ID == "" {
+		return WhatsAppGroupParticipants{}, false
+	}
+
+	var action string
+	switch strings.ToLower(event.Payload.Type) {
+	case "join", "add":
+		action = GroupParticipantsJoin
+	case "leave", "remove":
+		action = GroupParticipantsLeave
+	default:
+		return WhatsAppGroupParticipants{}, false
+	}
+
+	participants := make([]string, 0, len(event.Payload.Participants))
+	for _, participant := range event.Payload.Participants {
+		if participant.ID != "" {
+			participants = append(participants, wahaChatID(participant.ID))
+		}
+	}
+	if len(participants) == 0 {
+		return WhatsAppGroupParticipants{}, false
+	}
+
+	return WhatsAppGroupParticipants{
+		GroupID:      event.Payload.Group.ID,
+		Action:       action,
+		Participants: participants,
+	}, true
+}
+
+// wahaChatID converts a WhatsApp protocol JID to the @c.us form WAHA uses for contacts
+func wahaChatID(jid string) string {
+	if user, ok := strings.CutSuffix(jid, "@s.whatsapp.net"); ok {
+		return user + "@c.us"
+	}
+	return jid
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -30,9 +31,32 @@ func TestWhatsAppWebhookPayload_JSONMarshaling(t *testing.T) {
 				Text      string `json:"text"`
 				MessageID string `json:"messageId"`
 			} `json:"reaction"`
+			ReplyTo *struct {
+				ID          string `json:"id"`
+				Participant string `json:"participant,omitempty"`
+				Body        string `json:"body,omitempty"`
+			} `json:"replyTo,omitempty"`
 			Data *struct {
-				NotifyName string `json:"notifyName,omitempty"`
-				PushName   string `json:"pushName,omitempty"`
+				NotifyName        string `json:"notifyName,omitempty"`
+				PushName          string `json:"pushName,omitempty"`
+				EphemeralDuration int    `json:"ephemeralDuration,omitempty"`
+				IsViewOnce        bool   `json:"isViewOnce,omitempty"`
+				QuotedMsg         *struct {
+					Body    string `json:"body,omitempty"`
+					Caption string `json:"caption,omitempty"`
+				} `json:"quotedMsg,omitempty"`
+				Message           json.RawMessage `json:"message,omitempty"`
+				PaymentInfo       json.RawMessage `json:"paymentInfo,omitempty"`
+				Type              string          `json:"type,omitempty"`
+				PaymentAmount1000 int64           `json:"paymentAmount1000,omitempty"`
+				PaymentCurrency   string          `json:"paymentCurrency,omitempty"`
+				PaymentStatus     int             `json:"paymentStatus,omitempty"`
+				PaymentNoteMsg    *struct {
+					Body string `json:"body,omitempty"`
+				} `json:"paymentNoteMsg,omitempty"`
+				Lat float64 `json:"lat,omitempty"`
+				Lng float64 `json:"lng,omitempty"`
+				Loc string  `json:"loc,omitempty"`
 			} `json:"_data,omitempty"`
 			EditedMessageID *string `json:"editedMessageId,omitempty"`
 			ACK             *int    `json:"ack,omitempty"`
@@ -140,3 +164,453 @@ func TestFlexibleTimestamp_FullWebhookPayload(t *testing.T) {
 	assert.Equal(t, "message.waiting", payload.Event)
 	assert.Equal(t, "msg_456", payload.Payload.ID)
 }
+
+func TestWhatsAppWebhookPayload_EphemeralExpiry(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected time.Duration
+	}{
+		{
+			name:     "WEBJS ephemeral duration",
+			json:     `{"payload": {"id": "m1", "body": "hi", "_data": {"notifyName": "Alice", "ephemeralDuration": 86400}}}`,
+			expected: 24 * time.Hour,
+		},
+		{
+			name:     "NOWEB context info expiration",
+			json:     `{"payload": {"id": "m1", "body": "hi", "_data": {"message": {"extendedTextMessage": {"text": "hi", "contextInfo": {"expiration": 604800}}}}}}`,
+			expected: 7 * 24 * time.Hour,
+		},
+		{
+			name: "NOWEB plain conversation",
+			json: `{"payload": {"id": "m1", "body": "hi", "_data": {"message": {"conversation": "hi"}}}}`,
+		},
+		{
+			name: "no engine data",
+			json: `{"payload": {"id": "m1", "body": "hi"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &payload))
+			assert.Equal(t, tt.expected, payload.EphemeralExpiry())
+		})
+	}
+}
+
+func TestWhatsAppWebhookPayload_PinnedMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		json      string
+		expected  WhatsAppPin
+		expectOK  bool
+		wahaMsgID string
+	}{
+		{
+			name:      "NOWEB pin in direct chat",
+			json:      `{"payload": {"id": "p1", "from": "123@c.us", "_data": {"message": {"pinInChatMessage": {"key": {"remoteJid": "123@s.whatsapp.net", "fromMe": false, "id": "ABC"}, "type": 1, "senderTimestampMs": "1700000000000"}}}}}`,
+			expected:  WhatsAppPin{ChatID: "123@c.us", MessageID: "ABC"},
+			expectOK:  true,
+			wahaMsgID: "false_123@c.us_ABC",
+		},
+		{
+			name:      "GOWS pin in group with enum type",
+			json:      `{"payload": {"id": "p1", "from": "456@g.us", "participant": "789@c.us", "_data": {"message": {"pinInChatMessage": {"key": {"remoteJID": "456@g.us", "fromMe": true, "ID": "DEF", "participant": "789@s.whatsapp.net"}, "type": "PIN_FOR_ALL"}}}}}`,
+			expected:  WhatsAppPin{ChatID: "456@g.us", MessageID: "DEF", FromMe: true, Participant: "789@c.us"},
+			expectOK:  true,
+			wahaMsgID: "true_456@g.us_DEF_789@c.us",
+		},
+		{
+			name:      "unpin",
+			json:      `{"payload": {"id": "p1", "from": "123@c.us", "_data": {"message": {"pinInChatMessage": {"key": {"id": "ABC"}, "type": 2}}}}}`,
+			expected:  WhatsAppPin{ChatID: "123@c.us", MessageID: "ABC", Unpin: true},
+			expectOK:  true,
+			wahaMsgID: "false_123@c.us_ABC",
+		},
+		{
+			name: "unknown pin type",
+			json: `{"payload": {"id": "p1", "from": "123@c.us", "_data": {"message": {"pinInChatMessage": {"key": {"id": "ABC"}, "type": 0}}}}}`,
+		},
+		{
+			name: "regular message",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi", "_data": {"message": {"conversation": "hi"}}}}`,
+		},
+		{
+			name: "no engine data",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &payload))
+			pin, ok := payload.PinnedMessage()
+			assert.Equal(t, tt.expectOK, ok)
+			assert.Equal(t, tt.expected, pin)
+			if tt.expectOK {
+				assert.Equal(t, tt.wahaMsgID, pin.WAHAMessageID())
+			}
+		})
+	}
+}
+
+func TestWhatsAppWebhookPayload_IsViewOnce(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected bool
+	}{
+		{
+			name:     "WEBJS flag",
+			json:     `{"payload": {"id": "m1", "hasMedia": true, "_data": {"isViewOnce": true}}}`,
+			expected: true,
+		},
+		{
+			name:     "NOWEB view-once wrapper",
+			json:     `{"payload": {"id": "m1", "hasMedia": true, "_data": {"message": {"viewOnceMessageV2": {"message": {"imageMessage": {"mimetype": "image/jpeg", "viewOnce": true}}}}}}}`,
+			expected: true,
+		},
+		{
+			name:     "GOWS flag on the content",
+			json:     `{"payload": {"id": "m1", "hasMedia": true, "_data": {"message": {"videoMessage": {"mimetype": "video/mp4", "viewOnce": true}}}}}`,
+			expected: true,
+		},
+		{
+			name: "ordinary image",
+			json: `{"payload": {"id": "m1", "hasMedia": true, "_data": {"message": {"imageMessage": {"mimetype": "image/jpeg"}}}}}`,
+		},
+		{
+			name: "no engine data",
+			json: `{"payload": {"id": "m1", "body": "hi"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &payload))
+			assert.Equal(t, tt.expected, payload.IsViewOnce())
+		})
+	}
+}
+
+func TestWhatsAppWebhookPayload_ProductMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected WhatsAppProduct
+		expectOK bool
+	}{
+		{
+			name:     "NOWEB product with Long price",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "body": "", "_data": {"message": {"productMessage": {"product": {"productImage": {"mimetype": "image/jpeg"}, "productId": "998", "title": "Espresso cup ", "description": "Porcelain, 90 ml", "currencyCode": "EUR", "priceAmount1000": {"low": 12500000, "high": 0, "unsigned": false}}, "businessOwnerJid": "123@s.whatsapp.net"}}}}}`,
+			expected: WhatsAppProduct{Title: "Espresso cup", Description: "Porcelain, 90 ml", PriceAmount1000: 12500000, CurrencyCode: "EUR"},
+			expectOK: true,
+		},
+		{
+			name:     "GOWS product with string price",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"productMessage": {"product": {"productID": "998", "title": "Teapot", "currencyCode": "USD", "priceAmount1000": "45990"}, "businessOwnerJID": "123@s.whatsapp.net"}}}}}`,
+			expected: WhatsAppProduct{Title: "Teapot", PriceAmount1000: 45990, CurrencyCode: "USD"},
+			expectOK: true,
+		},
+		{
+			name:     "product without details",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"productMessage": {"product": {"productId": "998"}}}}}}`,
+			expectOK: true,
+		},
+		{
+			name: "product message without a product",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"productMessage": {}}}}}`,
+		},
+		{
+			name: "regular message",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi", "_data": {"message": {"conversation": "hi"}}}}`,
+		},
+		{
+			name: "no engine data",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &payload))
+			product, ok := payload.ProductMessage()
+			assert.Equal(t, tt.expectOK, ok)
+			assert.Equal(t, tt.expected, product)
+		})
+	}
+}
+
+func TestWhatsAppWebhookPayload_PaymentMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected WhatsAppPayment
+		expectOK bool
+	}{
+		{
+			name:     "WEBJS payment",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "body": "", "_data": {"type": "payment", "paymentAmount1000": 45990, "paymentCurrency": "INR", "paymentStatus": 4, "paymentNoteMsg": {"body": " Dinner "}}}}`,
+			expected: WhatsAppPayment{Amount1000: 45990, CurrencyCode: "INR", Status: "completed", Note: "Dinner"},
+			expectOK: true,
+		},
+		{
+			name:     "WEBJS payment without details",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"type": "payment"}}}`,
+			expectOK: true,
+		},
+		{
+			name:     "NOWEB payment sent with payment info",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"sendPaymentMessage": {"noteMessage": {"extendedTextMessage": {"text": "Rent"}}}}, "paymentInfo": {"amount": {"value": {"low": 120000, "high": 0}, "offset": 100, "currencyCode": "BRL"}, "status": "COMPLETE"}}}}`,
+			expected: WhatsAppPayment{Amount1000: 1200000, CurrencyCode: "BRL", Status: "completed", Note: "Rent"},
+			expectOK: true,
+		},
+		{
+			name:     "GOWS payment request with Money amount",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"requestPaymentMessage": {"noteMessage": {"conversation": "Tickets"}, "currencyCodeIso4217": "INR", "amount1000": "250000", "amount": {"value": "25000", "offset": 100, "currencyCode": "INR"}}}}}}`,
+			expected: WhatsAppPayment{Amount1000: 250000, CurrencyCode: "INR", Status: "requested", Note: "Tickets"},
+			expectOK: true,
+		},
+		{
+			name:     "payment request with numeric status in payment info",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"requestPaymentMessage": {"currencyCodeIso4217": "INR", "amount1000": 99000}}, "paymentInfo": {"currencyDeprecated": "INR", "amount1000": 99000, "status": 7}}}}`,
+			expected: WhatsAppPayment{Amount1000: 99000, CurrencyCode: "INR", Status: "expired"},
+			expectOK: true,
+		},
+		{
+			name:     "declined payment request",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"declinePaymentRequestMessage": {"key": {"id": "req1"}}}}}}`,
+			expected: WhatsAppPayment{Status: "declined"},
+			expectOK: true,
+		},
+		{
+			name:     "cancelled payment request with unknown status",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"cancelPaymentRequestMessage": {}}, "paymentInfo": {"status": "UNKNOWN_STATUS"}}}}`,
+			expected: WhatsAppPayment{Status: "cancelled"},
+			expectOK: true,
+		},
+		{
+			name: "regular message",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi", "_data": {"type": "chat", "message": {"conversation": "hi"}}}}`,
+		},
+		{
+			name: "no engine data",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &payload))
+			payment, ok := payload.PaymentMessage()
+			assert.Equal(t, tt.expectOK, ok)
+			assert.Equal(t, tt.expected, payment)
+		})
+	}
+}
+
+func TestWhatsAppWebhookPayload_LiveLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected WhatsAppLiveLocation
+		expectOK bool
+	}{
+		{
+			name:     "live location update",
+			json:     `{"payload": {"id": "live1", "from": "123@c.us", "body": "", "_data": {"message": {"liveLocationMessage": {"degreesLatitude": 52.370216, "degreesLongitude": 4.895168, "caption": " On my way ", "sequenceNumber": "3"}}}}}`,
+			expected: WhatsAppLiveLocation{ID: "live1", Latitude: 52.370216, Longitude: 4.895168, Caption: "On my way"},
+			expectOK: true,
+		},
+		{
+			name:     "location flagged live",
+			json:     `{"payload": {"id": "live2", "from": "123@c.us", "_data": {"message": {"locationMessage": {"degreesLatitude": -33.86, "degreesLongitude": 151.21, "isLive": true}}}}}`,
+			expected: WhatsAppLiveLocation{ID: "live2", Latitude: -33.86, Longitude: 151.21},
+			expectOK: true,
+		},
+		{
+			name: "location shared once",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"locationMessage": {"degreesLatitude": -33.86, "degreesLongitude": 151.21}}}}}`,
+		},
+		{
+			name: "regular message",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi", "_data": {"message": {"conversation": "hi"}}}}`,
+		},
+		{
+			name: "no engine data",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &payload))
+			loc, ok := payload.LiveLocation()
+			assert.Equal(t, tt.expectOK, ok)
+			assert.Equal(t, tt.expected, loc)
+		})
+	}
+}
+
+func TestWhatsAppWebhookPayload_Location(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected WhatsAppLocation
+		expectOK bool
+	}{
+		{
+			name:     "WEBJS location with name and address",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "body": "/9j/4AAQSkZJRg==", "_data": {"type": "location", "lat": 52.370216, "lng": 4.895168, "loc": "Dam Square\n1012 JS Amsterdam"}}}`,
+			expected: WhatsAppLocation{Latitude: 52.370216, Longitude: 4.895168, Name: "Dam Square", Address: "1012 JS Amsterdam"},
+			expectOK: true,
+		},
+		{
+			name:     "WEBJS dropped pin",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"type": "location", "lat": -33.8568, "lng": 151.2153}}}`,
+			expected: WhatsAppLocation{Latitude: -33.8568, Longitude: 151.2153},
+			expectOK: true,
+		},
+		{
+			name:     "NOWEB location message",
+			json:     `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"locationMessage": {"degreesLatitude": 40.6892, "degreesLongitude": -74.0445, "name": " Statue of Liberty ", "address": "New York, NY 10004"}}}}}`,
+			expected: WhatsAppLocation{Latitude: 40.6892, Longitude: -74.0445, Name: "Statue of Liberty", Address: "New York, NY 10004"},
+			expectOK: true,
+		},
+		{
+			name: "live location",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "_data": {"message": {"locationMessage": {"degreesLatitude": 40.6892, "degreesLongitude": -74.0445, "isLive": true}}}}}`,
+		},
+		{
+			name: "regular message",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi", "_data": {"type": "chat", "message": {"conversation": "hi"}}}}`,
+		},
+		{
+			name: "no engine data",
+			json: `{"payload": {"id": "m1", "from": "123@c.us", "body": "hi"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &payload))
+			loc, ok := payload.Location()
+			assert.Equal(t, tt.expectOK, ok)
+			assert.Equal(t, tt.expected, loc)
+		})
+	}
+}
+
+func TestWhatsAppWebhookPayload_QuotedMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected WhatsAppQuotedMessage
+		expectOK bool
+	}{
+		{
+			name:     "replyTo with body",
+			json:     `{"payload": {"id": "m2", "replyTo": {"id": "m1", "participant": "123@c.us", "body": " dinner at 8? "}}}`,
+			expected: WhatsAppQuotedMessage{ID: "m1", Participant: "123@c.us", Body: "dinner at 8?"},
+			expectOK: true,
+		},
+		{
+			name:     "WEBJS quoted message",
+			json:     `{"payload": {"id": "m2", "replyTo": {"id": "m1"}, "_data": {"quotedMsg": {"body": "dinner at 8?"}}}}`,
+			expected: WhatsAppQuotedMessage{ID: "m1", Body: "dinner at 8?"},
+			expectOK: true,
+		},
+		{
+			name:     "NOWEB quoted text",
+			json:     `{"payload": {"id": "m2", "replyTo": {"id": "m1"}, "_data": {"message": {"extendedTextMessage": {"text": "sure", "contextInfo": {"quotedMessage": {"conversation": "dinner at 8?"}}}}}}}`,
+			expected: WhatsAppQuotedMessage{ID: "m1", Body: "dinner at 8?"},
+			expectOK: true,
+		},
+		{
+			name:     "GOWS quoted photo caption",
+			json:     `{"payload": {"id": "m2", "replyTo": {"id": "m1"}, "_data": {"message": {"extendedTextMessage": {"text": "nice", "contextInfo": {"quotedMessage": {"imageMessage": {"caption": "the view"}}}}}}}}`,
+			expected: WhatsAppQuotedMessage{ID: "m1", Body: "the view"},
+			expectOK: true,
+		},
+		{
+			name:     "quote without text",
+			json:     `{"payload": {"id": "m2", "replyTo": {"id": "m1"}}}`,
+			expected: WhatsAppQuotedMessage{ID: "m1"},
+			expectOK: true,
+		},
+		{
+			name: "not a reply",
+			json: `{"payload": {"id": "m2", "body": "hi", "_data": {"quotedMsg": {"body": "stray"}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload WhatsAppWebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &payload))
+			quote, ok := payload.QuotedMessage()
+			assert.Equal(t, tt.expectOK, ok)
+			assert.Equal(t, tt.expected, quote)
+		})
+	}
+}
+
+func TestParseGroupParticipantChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected WhatsAppGroupParticipants
+		expectOK bool
+	}{
+		{
+			name:     "join",
+			json:     `{"event": "group.v2.participants", "payload": {"group": {"id": "456@g.us"}, "type": "join", "participants": [{"id": "123@c.us", "role": "participant"}]}}`,
+			expected: WhatsAppGroupParticipants{GroupID: "456@g.us", Action: GroupParticipantsJoin, Participants: []string{"123@c.us"}},
+			expectOK: true,
+		},
+		{
+			name:     "several members leave, with protocol IDs",
+			json:     `{"event": "group.participants", "payload": {"group": {"id": "456@g.us"}, "type": "LEAVE", "participants": [{"id": "123@s.whatsapp.net"}, {"id": "789@c.us"}]}}`,
+			expected: WhatsAppGroupParticipants{GroupID: "456@g.us", Action: GroupParticipantsLeave, Participants: []string{"123@c.us", "789@c.us"}},
+			expectOK: true,
+		},
+		{
+			name:     "remove is a leave",
+			json:     `{"payload": {"group": {"id": "456@g.us"}, "type": "remove", "participants": [{"id": "123@c.us"}]}}`,
+			expected: WhatsAppGroupParticipants{GroupID: "456@g.us", Action: GroupParticipantsLeave, Participants: []string{"123@c.us"}},
+			expectOK: true,
+		},
+		{
+			name: "promotion",
+			json: `{"payload": {"group": {"id": "456@g.us"}, "type": "promote", "participants": [{"id": "123@c.us"}]}}`,
+		},
+		{
+			name: "no participants",
+			json: `{"payload": {"group": {"id": "456@g.us"}, "type": "join", "participants": []}}`,
+		},
+		{
+			name: "no group",
+			json: `{"payload": {"type": "join", "participants": [{"id": "123@c.us"}]}}`,
+		},
+		{
+			name: "invalid JSON",
+			json: `{"payload": `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, ok := ParseGroupParticipantChange([]byte(tt.json))
+			assert.Equal(t, tt.expectOK, ok)
+			assert.Equal(t, tt.expected, change)
+		})
+	}
+}
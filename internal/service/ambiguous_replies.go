@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// resolveReplySession picks the WhatsApp session for a reply whose mapping does not name one,
+// such as a chat read from the quoted text. A chat bridged by more than one session is
+// ambiguous: a leading "@session" tag in the reply picks the session, and otherwise
+// signal.ambiguousReplyRouting decides between the session with the most recent activity and
+// asking the user to tag the reply. It returns false when the reply was answered with that
+// question instead of being relayed.
+func (b *bridge) resolveReplySession(ctx context.Context, msg *signaltypes.SignalMessage, sessionName, chatID string) (string, bool) {
+	sessions, err := b.db.GetMappingSessionsForChat(ctx, chatID)
+	if err != nil {
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
+			LogFieldSession: sessionName,
+			LogFieldChatID:  SanitizePhoneNumber(chatID),
+		}).WithError(err).Warn("Failed to look up sessions for chat, using the destination's session")
+		return sessionName, true
+	}
+
+	candidates := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		if b.channelManager.IsValidSession(session) {
+			candidates = append(candidates, session)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return sessionName, true
+	case 1:
+		return candidates[0], true
+	}
+
+	if tagged, rest, ok := replySessionTag(msg.Message, candidates); ok {
+		msg.Message = rest
+		return tagged, true
+	}
+
+	if b.signalConfig.AmbiguousReplyRouting == models.AmbiguousReplyAsk {
+		notice := fmt.Sprintf("This chat is bridged by more than one WhatsApp session (%s). Reply again starting with the session to use, for example: @%s your message",
+			strings.Join(candidates, ", "), candidates[0])
+		if err := b.SendSignalNotificationForSession(ctx, sessionName, notice); err != nil {
+			b.logger.WithContext(ctx).WithError(err).Warn("Failed to ask which session an ambiguous reply is for")
+		}
+		return "", false
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession: candidates[0],
+		LogFieldChatID:  SanitizePhoneNumber(chatID),
+		"sessions":      len(candidates),
+	}).Info("Routing ambiguous reply to the session with the most recent activity")
+	return candidates[0], true
+}
+
+// replySessionTag reports whether text starts with "@<session>" for one of sessions, returning
+// that session and the text after the tag
+func replySessionTag(text string, sessions []string) (string, string, bool) {
+	tag, rest, _ := strings.Cut(strings.TrimSpace(text), " ")
+	name, ok := strings.CutPrefix(tag, "@")
+	if !ok || name == "" {
+		return "", "", false
+	}
+	for _, session := range sessions {
+		if strings.EqualFold(session, name) {
+			return session, strings.TrimSpace(rest), true
+		}
+	}
+	return "", "", false
+}
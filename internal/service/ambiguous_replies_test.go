@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const ambiguousChat = "15550001111@c.us"
+
+// setupAmbiguousReplyBridge returns a bridge with the sessions "default" and "business" where
+// the quoted message resolves to ambiguousChat without a session, as a chat read from the quoted
+// text does. It records the session of each relayed reply and the text sent to WhatsApp.
+func setupAmbiguousReplyBridge(t *testing.T, chatSessions []string) (*bridge, *[]string, *string, func()) {
+	bridge, _, cleanup := setupTestBridge(t)
+
+	channelManager, err := NewChannelManager([]models.Channel{
+		{WhatsAppSessionName: "default", SignalDestinationPhoneNumber: "+1234567890"},
+		{WhatsAppSessionName: "business", SignalDestinationPhoneNumber: "+1222333444"},
+	})
+	require.NoError(t, err)
+	bridge.channelManager = channelManager
+
+	mockDB := bridge.db.(*mockDatabaseService)
+	mockDB.chatSessions = map[string][]string{ambiguousChat: chatSessions}
+	mockDB.On("GetMessageMapping", mock.Anything, "quoted_msg").Return(&models.MessageMapping{WhatsAppChatID: ambiguousChat}, nil)
+
+	var relayedSessions []string
+	mockDB.On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Run(func(args mock.Arguments) {
+		relayedSessions = append(relayedSessions, args.Get(1).(*models.MessageMapping).SessionName)
+	}).Return(nil)
+
+	var sentText string
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		sentText = text
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+	bridge.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{
+		MessageID: "sig_notice",
+		Timestamp: time.Now().UnixMilli(),
+	}
+
+	return bridge, &relayedSessions, &sentText, cleanup
+}
+
+func ambiguousReplyMessage(text string) *signaltypes.SignalMessage {
+	return &signaltypes.SignalMessage{
+		MessageID: "sig_reply",
+		Sender:    "+1234567890",
+		Message:   text,
+		Timestamp: time.Now().UnixMilli(),
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{ID: "quoted_msg", Text: "Alice: hi"},
+	}
+}
+
+func TestAmbiguousReply_SingleSessionIsUsed(t *testing.T) {
+	bridge, relayedSessions, sentText, cleanup := setupAmbiguousReplyBridge(t, []string{"business"})
+	defer cleanup()
+
+	err := bridge.HandleSignalMessageWithDestination(context.Background(), ambiguousReplyMessage("hello"), "+1234567890")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"business"}, *relayedSessions, "the only session that bridged the chat gets the reply")
+	assert.Equal(t, "hello", *sentText)
+}
+
+func TestAmbiguousReply_UnknownChatUsesDestinationSession(t *testing.T) {
+	bridge, relayedSessions, _, cleanup := setupAmbiguousReplyBridge(t, nil)
+	defer cleanup()
+
+	err := bridge.HandleSignalMessageWithDestination(context.Background(), ambiguousReplyMessage("hello"), "+1234567890")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"default"}, *relayedSessions)
+}
+
+func TestAmbiguousReply_RoutesToMostRecentSession(t *testing.T) {
+	bridge, relayedSessions, sentText, cleanup := setupAmbiguousReplyBridge(t, []string{"business", "default"})
+	defer cleanup()
+
+	err := bridge.HandleSignalMessageWithDestination(context.Background(), ambiguousReplyMessage("hello"), "+1234567890")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"business"}, *relayedSessions)
+	assert.Equal(t, "hello", *sentText)
+}
+
+func TestAmbiguousReply_AskModeRequestsSessionTag(t *testing.T) {
+	bridge, relayedSessions, sentText, cleanup := setupAmbiguousReplyBridge(t, []string{"business", "default"})
+	defer cleanup()
+	bridge.signalConfig.AmbiguousReplyRouting = models.AmbiguousReplyAsk
+
+	err := bridge.HandleSignalMessageWithDestination(context.Background(), ambiguousReplyMessage("hello"), "+1234567890")
+	require.NoError(t, err)
+
+	assert.Empty(t, *relayedSessions, "the reply is not relayed")
+	assert.Empty(t, *sentText)
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	assert.Equal(t, "+1234567890", sigClient.lastRecipient)
+	assert.Contains(t, sigClient.lastMessage, "business, default")
+	assert.Contains(t, sigClient.lastMessage, "@business")
+}
+
+func TestAmbiguousReply_SessionTagPicksSession(t *testing.T) {
+	bridge, relayedSessions, sentText, cleanup := setupAmbiguousReplyBridge(t, []string{"business", "default"})
+	defer cleanup()
+	bridge.signalConfig.AmbiguousReplyRouting = models.AmbiguousReplyAsk
+
+	err := bridge.HandleSignalMessageWithDestination(context.Background(), ambiguousReplyMessage("@Default hello there"), "+1234567890")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"default"}, *relayedSessions)
+	assert.Equal(t, "hello there", *sentText, "the tag is not relayed")
+}
+
+func TestAmbiguousReply_StoredSessionIsNotRerouted(t *testing.T) {
+	bridge, relayedSessions, _, cleanup := setupAmbiguousReplyBridge(t, []string{"business", "default"})
+	defer cleanup()
+
+	bridge.db.(*mockDatabaseService).On("GetMessageMapping", mock.Anything, "stored_msg").Return(&models.MessageMapping{WhatsAppChatID: ambiguousChat, SessionName: "default"}, nil)
+	msg := ambiguousReplyMessage("hello")
+	msg.QuotedMessage.ID = "stored_msg"
+
+	err := bridge.HandleSignalMessageWithDestination(context.Background(), msg, "+1234567890")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"default"}, *relayedSessions, "a reply to a stored message goes to that message's session")
+}
+
+func TestReplySessionTag(t *testing.T) {
+	sessions := []string{"business", "default"}
+
+	session, rest, ok := replySessionTag("@business see you", sessions)
+	assert.True(t, ok)
+	assert.Equal(t, "business", session)
+	assert.Equal(t, "see you", rest)
+
+	_, _, ok = replySessionTag("@alice see you", sessions)
+	assert.False(t, ok, "a mention that is not a session is left alone")
+	_, _, ok = replySessionTag("email me @business", sessions)
+	assert.False(t, ok, "only a leading tag counts")
+	_, _, ok = replySessionTag("@", sessions)
+	assert.False(t, ok)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"whatsignal/internal/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+// capAttachments keeps the first media.maxAttachmentsPerRelay attachments of a Signal message.
+// When some are dropped it returns a note for the message text saying how many.
+func (b *bridge) capAttachments(ctx context.Context, attachments []string, sessionName string) ([]string, []string) {
+	limit := b.mediaConfig.MaxAttachmentsPerRelay
+	if limit <= 0 || len(attachments) <= limit {
+		return attachments, nil
+	}
+
+	omitted := len(attachments) - limit
+	metrics.AddToCounter("attachments_omitted_total", float64(omitted), map[string]string{
+		"session": sessionName,
+	}, "Signal attachments not relayed because a message had more than media.maxAttachmentsPerRelay")
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession: sessionName,
+		"attachments":   len(attachments),
+		"limit":         limit,
+	}).Info("Signal message has more attachments than allowed, omitting the rest")
+
+	return attachments[:limit], []string{omittedAttachmentsNote(omitted)}
+}
+
+// omittedAttachmentsNote is the text sent in place of attachments dropped by capAttachments
+func omittedAttachmentsNote(omitted int) string {
+	return fmt.Sprintf("(+%d more attachments omitted)", omitted)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapAttachments(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	ctx := context.Background()
+	attachments := []string{"/tmp/1.jpg", "/tmp/2.jpg", "/tmp/3.jpg"}
+
+	capped, notes := b.capAttachments(ctx, attachments, "default")
+	assert.Equal(t, attachments, capped, "no limit relays all")
+	assert.Empty(t, notes)
+
+	b.mediaConfig.MaxAttachmentsPerRelay = 3
+	capped, notes = b.capAttachments(ctx, attachments, "default")
+	assert.Equal(t, attachments, capped)
+	assert.Empty(t, notes)
+
+	b.mediaConfig.MaxAttachmentsPerRelay = 1
+	capped, notes = b.capAttachments(ctx, attachments, "default")
+	assert.Equal(t, []string{"/tmp/1.jpg"}, capped)
+	assert.Equal(t, []string{"(+2 more attachments omitted)"}, notes)
+}
+
+func TestHandleSignalMessage_MaxAttachmentsPerRelay(t *testing.T) {
+	b, tmpDir, cleanup := setupTestBridge(t)
+	defer cleanup()
+	b.mediaConfig.MaxAttachmentsPerRelay = 2
+
+	ctx := context.Background()
+	var attachments []string
+	for i := 1; i <= 5; i++ {
+		attachments = append(attachments, filepath.Join(tmpDir, fmt.Sprintf("photo%d.jpg", i)))
+	}
+	// Only the first two are processed; the mock fails the test on any other call
+	mediaHandler := b.media.(*mockMediaHandler)
+	mediaHandler.On("ProcessMedia", attachments[0]).Return(attachments[0], nil).Once()
+	mediaHandler.On("ProcessMedia", attachments[1]).Return(attachments[1], nil).Once()
+
+	db := b.db.(*mockDatabaseService)
+	db.On("GetMessageMapping", ctx, "7777777").Return(&models.MessageMapping{
+		WhatsAppChatID: "alice@c.us",
+		WhatsAppMsgID:  "wamid.alice1",
+		SignalMsgID:    "7777777",
+		SessionName:    "default",
+	}, nil)
+	db.On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+
+	waClient := b.waClient.(*mockWhatsAppClient)
+	waClient.On("SendImageWithSession", mock.Anything, "alice@c.us", attachments[0], "Holiday photos\n(+3 more attachments omitted)", "wamid.alice1", "default").
+		Return(&types.SendMessageResponse{MessageID: "wamid.photos", Status: "sent"}, nil).Once()
+
+	require.NoError(t, b.HandleSignalMessage(ctx, &signaltypes.SignalMessage{
+		MessageID:   "signal_photos",
+		Sender:      "+1234567890",
+		Message:     "Holiday photos",
+		Timestamp:   time.Now().UnixMilli(),
+		Attachments: attachments,
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{ID: "7777777", Author: "+1234567890", Text: "Send the photos"},
+	}))
+
+	mediaHandler.AssertExpectations(t)
+	waClient.AssertExpectations(t)
+}
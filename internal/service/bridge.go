@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"whatsignal/internal/constants"
@@ -16,6 +18,7 @@ import (
 	"whatsignal/internal/privacy"
 	"whatsignal/internal/retry"
 	"whatsignal/internal/tracing"
+	"whatsignal/internal/transform"
 	"whatsignal/pkg/media"
 	"whatsignal/pkg/signal"
 	signaltypes "whatsignal/pkg/signal/types"
@@ -48,14 +51,22 @@ type RecordCleaner interface {
 // It embeds RecordCleaner to maintain backward compatibility while supporting ISP.
 type MessageBridge interface {
 	RecordCleaner
+	OrphanMediaPurger
 	SendMessage(ctx context.Context, msg *models.Message) error
 	HandleWhatsAppMessageWithSession(ctx context.Context, sessionName, chatID, msgID, sender, senderDisplayName, content string, mediaPath string) error
 	HandleSignalMessage(ctx context.Context, msg *signaltypes.SignalMessage) error
 	HandleSignalMessageWithDestination(ctx context.Context, msg *signaltypes.SignalMessage, destination string) error
 	HandleSignalReceipt(ctx context.Context, msg *signaltypes.SignalMessage) error
+	HandleSignalTyping(ctx context.Context, msg *signaltypes.SignalMessage, destination string) error
 	HandleSignalMessageDeletion(ctx context.Context, targetMessageID string, sender string) error
 	UpdateDeliveryStatus(ctx context.Context, msgID string, status models.DeliveryStatus) error
 	SendSignalNotificationForSession(ctx context.Context, sessionName, message string) error
+	HandleWhatsAppReaction(ctx context.Context, sessionName string, reaction WhatsAppReaction, mapping *models.MessageMapping) error
+	HandleWhatsAppPin(ctx context.Context, sessionName string, pin models.WhatsAppPin) error
+	HandleWhatsAppLiveLocation(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation) error
+	HandleWhatsAppGroupParticipants(ctx context.Context, sessionName string, change models.WhatsAppGroupParticipants) error
+	BroadcastMessage(ctx context.Context, sessionName string, chats []string, text string) error
+	Retention() *RetentionSetting
 }
 
 type DatabaseService interface {
@@ -68,11 +79,23 @@ type DatabaseService interface {
 	GetLatestMessageMappingBySession(ctx context.Context, sessionName string) (*models.MessageMapping, error)
 	GetLatestGroupMessageMappingBySession(ctx context.Context, sessionName string, searchLimit int) (*models.MessageMapping, error)
 	HasMessageHistoryBetween(ctx context.Context, sessionName, signalSender string) (bool, error)
+	GetMappingSessionsForChat(ctx context.Context, chatID string) ([]string, error)
+	GetMediaPaths(ctx context.Context) ([]string, error)
 	UpdateDeliveryStatus(ctx context.Context, id string, status string) error
 	CleanupOldRecords(ctx context.Context, retentionDays int) error
 	GetStaleMessageCount(ctx context.Context, threshold time.Duration) (int, error)
+	GetMappingStats(ctx context.Context, sessionName string) (models.MappingStats, error)
 	GetContactByName(ctx context.Context, name string) (*models.Contact, error)
+	GetContactByPhone(ctx context.Context, phoneNumber string) (*models.Contact, error)
 	UpdateSignalIDByWhatsAppID(ctx context.Context, whatsappMsgID, signalMsgID string, signalTimestamp time.Time, status string) error
+	SetChatPaused(ctx context.Context, sessionName, chatID string, paused bool) error
+	IsChatPaused(ctx context.Context, sessionName, chatID string) (bool, error)
+	SaveGroupMapping(ctx context.Context, mapping *models.GroupMapping) error
+	GetGroupMapping(ctx context.Context, sessionName, signalGroupID string) (*models.GroupMapping, error)
+	SetChatAutoReply(ctx context.Context, sessionName, chatID, message string) error
+	ClaimChatAutoReply(ctx context.Context, sessionName, chatID string, now time.Time, cooldown time.Duration) (string, error)
+	RecordSeen(ctx context.Context, key string, ttl time.Duration) error
+	WasSeen(ctx context.Context, key string) (bool, error)
 }
 
 type bridge struct {
@@ -88,27 +111,147 @@ type bridge struct {
 	groupService         GroupServiceInterface
 	channelManager       *ChannelManager
 	signalAttachmentsDir string
+	signalConfig         models.SignalConfig
+	recentMedia          *recentMediaCache
+	recentReactions      *recentReactionCache
+	retention            *RetentionSetting
+	persistReactions     bool                 // Also remember relayed reactions in the database, across restarts
+	reactionTallies      *reactionAggregator  // Folds WhatsApp reactions into one update per message; nil relays each one
+	liveLocations        *liveLocationRelay   // Coalesces WhatsApp live location updates; nil ignores them
+	contactRefresh       *contactRefresher    // Refreshes senders' contacts in the background; nil looks them up inline
+	signalContacts       *signalContactSyncer // Caches Signal senders' profile names; nil without a contact service
+	nativeReactions      *nativeReactionRelay // Relays WhatsApp reactions as Signal reactions; nil sends text notices
+	deliveryReceipts     *receiptCorrelator   // Advances relayed messages from sent to delivered on Signal receipts; nil records them delivered on send
+	captionJoin          *captionJoiner
+	chatLabels           *chatLabelCache
+	disappearing         string
+	signalTimers         *signalTimers
+	typing               *typingSimulator
+	signalTyping         *signalTypingRelay // Mirrors the Signal user's typing indicator; nil unless whatsapp.relaySignalTyping is set
+	displayLocation      *time.Location     // Time zone of send time prefixes; nil unless whatsapp.showSentTime is set
+	compressor           media.Compressor
+	broadcastInterval    time.Duration
+	autoReplyCooldown    time.Duration
+	transforms           *transform.Pipeline // Rewrites relayed text; nil without transform rules
 	lastFallbackChat     map[string]string
 	lastFallbackChatMu   sync.RWMutex
+	maxRelayAge          time.Duration // WhatsApp messages sent longer ago are not relayed; 0 relays all
+	maxCaptionLength     int           // Longest media caption sent to WhatsApp, in characters; 0 sends any length
+	blockViewOnce        bool          // Drops view-once WhatsApp media instead of relaying it
+	markSeenOnReply      bool          // Marks a WhatsApp chat seen after relaying a Signal message to it
+	clock                Clock         // Time of mappings, retention cutoffs and the dedup caches
+	staleDropped         atomic.Int64
 }
 
 // NewBridge creates a new bridge with channel manager (channels are required)
 func NewBridge(waClient types.WAClient, sigClient signal.Client, db DatabaseService, mh media.Handler, rc models.RetryConfig, mc models.MediaConfig, channelManager *ChannelManager, contactService ContactServiceInterface, groupService GroupServiceInterface, signalAttachmentsDir string, logger *logrus.Logger) MessageBridge {
-	return &bridge{
+	cfg := models.Config{
+		Retry:  rc,
+		Media:  mc,
+		Signal: models.SignalConfig{AttachmentsDir: signalAttachmentsDir},
+	}
+	return NewBridgeWithConfig(waClient, sigClient, db, mh, cfg, channelManager, contactService, groupService, logger)
+}
+
+// NewBridgeWithConfig creates a new bridge, taking retry, media and Signal settings from the application config
+func NewBridgeWithConfig(waClient types.WAClient, sigClient signal.Client, db DatabaseService, mh media.Handler, cfg models.Config, channelManager *ChannelManager, contactService ContactServiceInterface, groupService GroupServiceInterface, logger *logrus.Logger) MessageBridge {
+	b := &bridge{
 		waClient:             waClient,
 		sigClient:            sigClient,
 		db:                   db,
 		media:                mh,
-		retryConfig:          rc,
-		mediaConfig:          mc,
-		mediaRouter:          intmedia.NewRouter(mc),
+		retryConfig:          cfg.Retry,
+		retention:            NewRetentionSetting(cfg.RetentionDays),
+		mediaConfig:          cfg.Media,
+		mediaRouter:          intmedia.NewRouter(cfg.Media),
 		logger:               logger,
 		contactService:       contactService,
 		groupService:         groupService,
 		channelManager:       channelManager,
-		signalAttachmentsDir: signalAttachmentsDir,
+		signalAttachmentsDir: cfg.Signal.AttachmentsDir,
+		signalConfig:         cfg.Signal,
 		lastFallbackChat:     make(map[string]string),
+		broadcastInterval:    time.Duration(constants.BroadcastSendIntervalMs) * time.Millisecond,
+		autoReplyCooldown:    time.Duration(constants.DefaultAutoReplyCooldownMin) * time.Minute,
+		clock:                RealClock,
+	}
+	b.disappearing = cfg.WhatsApp.DisappearingMessages
+	if b.disappearing == models.DisappearingTimer {
+		b.signalTimers = newSignalTimers()
+	}
+	if cfg.WhatsApp.ShowSentTime {
+		b.displayLocation = loadDisplayLocation(cfg.Server.DisplayTimezone, logger)
+	}
+	if cfg.WhatsApp.SimulateTyping {
+		b.typing = newTypingSimulator(waClient, cfg.WhatsApp.SimulateTypingMaxSec, logger)
 	}
+	if cfg.WhatsApp.RelaySignalTyping {
+		b.signalTyping = newSignalTypingRelay(waClient, time.Duration(constants.SignalTypingHoldSec)*time.Second, logger)
+	}
+	if cfg.Media.DedupWindowSec > 0 {
+		b.recentMedia = newRecentMediaCache(time.Duration(cfg.Media.DedupWindowSec)*time.Second, constants.MaxRecentMediaEntries, b.clock)
+	}
+	b.maxCaptionLength = constants.DefaultMaxCaptionLength
+	if cfg.WhatsApp.MaxCaptionLength > 0 {
+		b.maxCaptionLength = cfg.WhatsApp.MaxCaptionLength
+	}
+	b.blockViewOnce = cfg.WhatsApp.BlockViewOnce
+	b.markSeenOnReply = cfg.WhatsApp.MarkSeenOnReply
+	if cfg.Server.MaxRelayAgeMin > 0 {
+		b.maxRelayAge = time.Duration(cfg.Server.MaxRelayAgeMin) * time.Minute
+	}
+	if cfg.WhatsApp.AutoReplyCooldownMin > 0 {
+		b.autoReplyCooldown = time.Duration(cfg.WhatsApp.AutoReplyCooldownMin) * time.Minute
+	}
+	if transforms, err := transform.Compile(cfg.Transforms); err != nil {
+		// Rules are validated with the config, so this only happens for configs built in code
+		logger.WithError(err).Error("Invalid transform rules, relaying text unchanged")
+	} else {
+		b.transforms = transforms
+	}
+	if cfg.Signal.ReactionDedupWindowSec > 0 {
+		b.recentReactions = newRecentReactionCache(time.Duration(cfg.Signal.ReactionDedupWindowSec)*time.Second, constants.MaxRecentReactionEntries, b.clock)
+		b.persistReactions = cfg.Signal.PersistReactionDedup
+	}
+	if cfg.WhatsApp.ContactRefreshOnRelay && b.contactsEnabled() {
+		b.contactRefresh = newContactRefresher(contactService, cfg.WhatsApp.ContactRefreshPerMinute, logger)
+	}
+	if b.contactsEnabled() {
+		cacheHours := cfg.WhatsApp.ContactCacheHours
+		if cacheHours <= 0 {
+			cacheHours = constants.DefaultContactCacheHours
+		}
+		b.signalContacts = newSignalContactSyncer(contactService, time.Duration(cacheHours)*time.Hour, logger)
+	}
+	if cfg.Signal.DeliveryReceipts {
+		b.deliveryReceipts = newReceiptCorrelator(time.Duration(constants.EarlyReceiptTTLSec)*time.Second, constants.MaxEarlyReceipts, b.clock)
+	}
+	if cfg.WhatsApp.NativeReactions {
+		b.nativeReactions = newNativeReactionRelay(constants.MaxNativeReactionTargets, b.clock)
+	}
+	if cfg.WhatsApp.ReactionAggregateWindowSec > 0 {
+		b.reactionTallies = newReactionAggregator(time.Duration(cfg.WhatsApp.ReactionAggregateWindowSec)*time.Second, constants.MaxReactionAggregateTargets, b.sendReactionTally, logger, b.clock)
+	}
+	if cfg.WhatsApp.LiveLocationIntervalSec > 0 {
+		interval := time.Duration(cfg.WhatsApp.LiveLocationIntervalSec) * time.Second
+		b.liveLocations = newLiveLocationRelay(interval, max(2*interval, constants.MinLiveLocationIdleSec*time.Second), constants.MaxLiveLocationShares, b.sendLiveLocation, logger, b.clock)
+	}
+	if cfg.Media.OversizeBehavior == models.MediaOversizeCompress && len(cfg.Media.CompressCommand) > 0 {
+		b.compressor = media.NewCommandCompressor(cfg.Media.CompressCommand)
+	}
+	if cfg.WhatsApp.ChatLabelsPrefix {
+		ttl := cfg.WhatsApp.ChatLabelsCacheSec
+		if ttl <= 0 {
+			ttl = constants.DefaultChatLabelsCacheSec
+		}
+		b.chatLabels = newChatLabelCache(time.Duration(ttl)*time.Second, b.clock)
+	}
+	if cfg.Media.CaptionJoinWindowMs > 0 {
+		b.captionJoin = newCaptionJoiner(time.Duration(cfg.Media.CaptionJoinWindowMs)*time.Millisecond, func(ctx context.Context, msg whatsAppRelay) error {
+			return b.relayWhatsAppMessage(ctx, msg.sessionName, msg.chatID, msg.msgID, msg.sender, msg.senderDisplayName, msg.content, msg.mediaPath)
+		}, b.linkJoinedWhatsAppMessage, logger)
+	}
+	return b
 }
 
 func (b *bridge) SendMessage(ctx context.Context, msg *models.Message) error {
@@ -140,6 +283,22 @@ func (b *bridge) SendMessage(ctx context.Context, msg *models.Message) error {
 }
 
 func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionName, chatID, msgID, sender, senderDisplayName, content string, mediaPath string) error {
+	if b.captionJoin != nil {
+		return b.captionJoin.handle(ctx, whatsAppRelay{
+			sessionName:       sessionName,
+			chatID:            chatID,
+			msgID:             msgID,
+			sender:            sender,
+			senderDisplayName: senderDisplayName,
+			content:           content,
+			mediaPath:         mediaPath,
+		})
+	}
+	return b.relayWhatsAppMessage(ctx, sessionName, chatID, msgID, sender, senderDisplayName, content, mediaPath)
+}
+
+// relayWhatsAppMessage forwards a WhatsApp message to the session's Signal destination
+func (b *bridge) relayWhatsAppMessage(ctx context.Context, sessionName, chatID, msgID, sender, senderDisplayName, content string, mediaPath string) error {
 	startTime := time.Now()
 	requestInfo := tracing.GetRequestInfo(ctx)
 
@@ -168,7 +327,27 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 		logrusFields[k] = v
 	}
 
-	b.logger.WithFields(logrusFields).Info("Processing WhatsApp message")
+	b.logger.WithContext(ctx).WithFields(logrusFields).Info("Processing WhatsApp message")
+
+	if b.isChatPaused(ctx, sessionName, chatID) {
+		// Keep the mapping so replies and reactions still resolve after the chat is resumed
+		if err := b.saveUnrelayedMapping(ctx, sessionName, chatID, msgID, "paused"); err != nil {
+			return fmt.Errorf("failed to save message mapping for paused chat: %w", err)
+		}
+		b.logger.WithContext(ctx).WithFields(logrusFields).Info("Chat is paused, not relaying WhatsApp message to Signal")
+		return nil
+	}
+
+	if b.isStaleWhatsAppMessage(ctx) {
+		return b.dropStaleWhatsAppMessage(ctx, sessionName, chatID, msgID)
+	}
+
+	viewOnce := isWhatsAppViewOnce(ctx)
+	if viewOnce && b.blockViewOnce {
+		return b.dropViewOnceMessage(ctx, sessionName, chatID, msgID)
+	}
+
+	b.sendChatAutoReply(ctx, sessionName, chatID)
 
 	// Extract phone number from sender ID
 	// Formats: "12345@c.us" (user), "12345@lid" (linked ID), "12345@g.us" (group - shouldn't happen after server.go fix)
@@ -187,10 +366,38 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 	displayName := senderPhone // final fallback
 	if senderDisplayName != "" {
 		displayName = senderDisplayName
-	} else if b.contactService != nil {
-		displayName = b.contactService.GetContactDisplayName(ctx, senderPhone)
+	} else if b.contactsEnabled() {
+		displayName = b.senderDisplayName(ctx, senderPhone)
 	}
 
+	// Get the Signal destination based on session
+	dest, err := b.channelManager.GetSignalDestination(sessionName)
+	if err != nil {
+		return fmt.Errorf("failed to get Signal destination for session %s: %w", sessionName, err)
+	}
+	destinationNumber := dest
+	quoteOpt := b.signalQuoteOption(ctx, sessionName, destinationNumber)
+
+	if product, ok := whatsAppProductFromContext(ctx); ok {
+		content = formatWhatsAppProduct(product, content)
+	}
+	if payment, ok := whatsAppPaymentFromContext(ctx); ok {
+		content = formatWhatsAppPayment(payment, content)
+	}
+	var mappingMediaType string
+	if loc, ok := whatsAppLocationFromContext(ctx); ok {
+		// The body of a location message, if any, is a map thumbnail
+		content = formatWhatsAppLocation(loc)
+		mappingMediaType = mappingMediaTypeLocation
+	}
+	if viewOnce {
+		content = markViewOnce(content)
+	}
+	content = withUnbridgedQuote(ctx, quoteOpt, content)
+
+	content = b.transforms.Apply(models.TransformWhatsAppToSignal, content)
+	content, sendOpts := b.applySignalFormatting(content)
+
 	// Detect if this is a group message and format accordingly
 	var message string
 	isGroupMsg := strings.HasSuffix(chatID, "@g.us")
@@ -203,22 +410,44 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 		// Direct message formatting (existing behavior)
 		message = fmt.Sprintf("%s: %s", displayName, content)
 	}
+	message = b.sentTimePrefix(ctx) + b.chatLabelPrefix(ctx, sessionName, chatID) + message
 	var attachments []string
+	var contentHash string
 
 	if mediaPath != "" {
-		processedPath, err := b.media.ProcessMedia(mediaPath)
+		processedPath, err := b.processMediaForSession(ctx, mediaPath, sessionName, "whatsapp_to_signal")
 		if err != nil {
 			return fmt.Errorf("failed to process media: %w", err)
 		}
 		attachments = append(attachments, processedPath)
+
+		contentHash = mediaContentHash(processedPath)
+		if !b.recentMedia.reserve(chatID, contentHash) {
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
+				LogFieldSession:   sessionName,
+				LogFieldMessageID: SanitizeWhatsAppMessageID(msgID),
+			}).Info("Suppressing duplicate media recently relayed to the same chat")
+			metrics.IncrementCounter("media_duplicates_suppressed_total", map[string]string{
+				"session": sessionName,
+			}, "Duplicate media relays suppressed")
+			return nil
+		}
 	}
 
-	// Get the Signal destination based on session
-	dest, err := b.channelManager.GetSignalDestination(sessionName)
-	if err != nil {
-		return fmt.Errorf("failed to get Signal destination for session %s: %w", sessionName, err)
+	// Released unless the message reaches Signal, so a retry of the same media is not suppressed
+	relayed := false
+	defer func() {
+		if !relayed {
+			b.recentMedia.release(chatID, contentHash)
+		}
+	}()
+
+	message, unlockTimer := b.applyDisappearing(ctx, sessionName, destinationNumber, message)
+	defer unlockTimer()
+
+	if quoteOpt != nil {
+		sendOpts = append(sendOpts, quoteOpt)
 	}
-	destinationNumber := dest
 
 	// Prepare retry configuration
 	backoffConfig := retry.BackoffConfig{
@@ -229,7 +458,7 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 		Jitter:       true,
 	}
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"InitialBackoffMs": b.retryConfig.InitialBackoffMs,
 		"MaxBackoffMs":     b.retryConfig.MaxBackoffMs,
 		"MaxAttempts":      b.retryConfig.MaxAttempts,
@@ -242,9 +471,10 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 		WhatsAppChatID:  chatID,
 		WhatsAppMsgID:   msgID,
 		SignalMsgID:     "pending:" + msgID,
-		SignalTimestamp: time.Now(),
-		ForwardedAt:     time.Now(),
+		SignalTimestamp: b.clock.Now(),
+		ForwardedAt:     b.clock.Now(),
 		DeliveryStatus:  models.DeliveryStatusSent,
+		MediaType:       mappingMediaType,
 		SessionName:     sessionName,
 	}
 
@@ -253,7 +483,7 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 	}
 
 	if err := b.db.SaveMessageMapping(ctx, partialMapping); err != nil {
-		b.logger.WithError(err).Warn("Failed to save partial message mapping before Signal send")
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to save partial message mapping before Signal send")
 	}
 
 	backoff := retry.NewBackoff(backoffConfig)
@@ -261,10 +491,20 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 	var resp *signaltypes.SendMessageResponse
 	retryErr := backoff.RetryWithPredicate(ctx, func() error {
 		var sendErr error
-		resp, sendErr = b.sigClient.SendMessage(ctx, destinationNumber, message, attachments)
+		resp, sendErr = b.sigClient.SendMessage(ctx, destinationNumber, message, attachments, sendOpts...)
 		return sendErr
 	}, isRetryableSignalError)
 
+	sendStatus := "success"
+	if retryErr != nil {
+		sendStatus = "failure"
+	}
+	metrics.IncrementCounter("signal_send_total", map[string]string{
+		"session":    sessionName,
+		"status":     sendStatus,
+		"media_type": b.relayMediaType(attachments),
+	}, "Signal send outcomes by media type")
+
 	if retryErr != nil {
 		// Partial mapping remains in DB with "pending:" prefix — allows routing by WhatsApp ID
 		if !isRetryableSignalError(retryErr) {
@@ -276,19 +516,21 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 	if resp == nil {
 		return fmt.Errorf("received nil response from Signal client after successful retry")
 	}
+	relayed = true
 
 	// Update the partial mapping with the real Signal message ID and timestamp
 	signalTimestamp := time.Unix(resp.Timestamp/constants.MillisecondsPerSecond, 0)
-	if err := b.db.UpdateSignalIDByWhatsAppID(ctx, msgID, resp.MessageID, signalTimestamp, string(models.DeliveryStatusDelivered)); err != nil {
-		b.logger.WithError(err).Warn("Failed to update partial mapping with Signal ID, saving new mapping")
+	if err := b.db.UpdateSignalIDByWhatsAppID(ctx, msgID, resp.MessageID, signalTimestamp, string(b.relayedStatus())); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to update partial mapping with Signal ID, saving new mapping")
 		// Fallback: save a fresh mapping if update fails
 		mapping := &models.MessageMapping{
 			WhatsAppChatID:  chatID,
 			WhatsAppMsgID:   msgID,
 			SignalMsgID:     resp.MessageID,
 			SignalTimestamp: signalTimestamp,
-			ForwardedAt:     time.Now(),
-			DeliveryStatus:  models.DeliveryStatusDelivered,
+			ForwardedAt:     b.clock.Now(),
+			DeliveryStatus:  b.relayedStatus(),
+			MediaType:       mappingMediaType,
 			SessionName:     sessionName,
 		}
 		if len(attachments) > 0 {
@@ -298,6 +540,7 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 			return fmt.Errorf("failed to save message mapping: %w", saveErr)
 		}
 	}
+	b.applyHeldReceipt(ctx, resp.Timestamp)
 
 	// Record success metrics and timing
 	processingDuration := time.Since(startTime)
@@ -311,6 +554,7 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 		"direction": "whatsapp_to_signal",
 		"session":   sessionName,
 	}, "Message processing duration")
+	recordRelayLatency(ctx, "whatsapp_to_signal")
 
 	// Log successful completion
 	completionFields := privacy.MaskSensitiveFields(map[string]interface{}{
@@ -329,11 +573,46 @@ func (b *bridge) HandleWhatsAppMessageWithSession(ctx context.Context, sessionNa
 		completionLogrusFields[k] = v
 	}
 
-	b.logger.WithFields(completionLogrusFields).Info("WhatsApp message processing completed successfully")
+	b.logger.WithContext(ctx).WithFields(completionLogrusFields).Info("WhatsApp message processing completed successfully")
 
 	return nil
 }
 
+// applyDisappearing handles a disappearing WhatsApp message according to
+// whatsapp.disappearingMessages. In timer mode it sets the Signal conversation's timer and
+// keeps it until the returned function is called; if that fails, the message is labelled.
+func (b *bridge) applyDisappearing(ctx context.Context, sessionName, destination, message string) (string, func()) {
+	expiry := whatsAppExpiryFromContext(ctx)
+	if b.signalTimers != nil {
+		unlock, err := b.signalTimers.apply(ctx, b.sigClient, destination, expiry)
+		if err == nil {
+			return message, unlock
+		}
+		b.logger.WithContext(ctx).WithError(err).WithField(LogFieldSession, sessionName).Warn("Failed to set Signal expiration timer, labelling the message instead")
+		if expiry > 0 {
+			message += "\n" + disappearingLabel(expiry)
+		}
+		return message, unlock
+	}
+	if expiry > 0 && b.disappearing == models.DisappearingLabel {
+		message += "\n" + disappearingLabel(expiry)
+	}
+	return message, func() {}
+}
+
+// applySignalFormatting converts or strips WhatsApp formatting markers according to the
+// Signal config and returns the send options needed for Signal to render the result.
+func (b *bridge) applySignalFormatting(content string) (string, []signaltypes.SendOption) {
+	switch {
+	case b.signalConfig.TranslateFormatting:
+		return TranslateWhatsAppFormatting(content), []signaltypes.SendOption{signaltypes.WithTextMode(signaltypes.TextModeStyled)}
+	case b.signalConfig.StripFormatting:
+		return StripWhatsAppFormatting(content), nil
+	default:
+		return content, nil
+	}
+}
+
 func (b *bridge) HandleSignalMessage(ctx context.Context, msg *signaltypes.SignalMessage) error {
 	// Try to infer destination from the message context
 	// If there's only one channel configured, use it
@@ -350,8 +629,17 @@ func (b *bridge) HandleSignalMessage(ctx context.Context, msg *signaltypes.Signa
 func (b *bridge) HandleSignalMessageWithDestination(ctx context.Context, msg *signaltypes.SignalMessage, destination string) error {
 	startTime := time.Now()
 
+	if b.signalContacts != nil && !msg.IsSentByMe {
+		b.signalContacts.schedule(ctx, msg.Sender)
+	}
+
+	// Edits carry the WhatsApp chat in the mapping of the message they revise, group or not
+	if msg.Edit != nil {
+		return b.handleSignalEdit(ctx, msg, destination)
+	}
+
 	// Delegate group messages to specialized handler
-	if strings.HasPrefix(msg.Sender, "group.") {
+	if msg.GroupID != "" || strings.HasPrefix(msg.Sender, "group.") {
 		return b.handleSignalGroupMessage(ctx, msg, destination)
 	}
 
@@ -374,6 +662,27 @@ func (b *bridge) HandleSignalMessageWithDestination(ctx context.Context, msg *si
 	if msg.Deletion != nil {
 		return b.handleSignalDeletionWithSession(ctx, msg, sessionName)
 	}
+	if command := signalCommand(msg); command != "" && !b.isFromDestination(ctx, msg, destination, command) {
+		return nil
+	}
+	if b.handleBroadcastCommand(ctx, msg, sessionName) {
+		return nil
+	}
+	if b.handleStatsCommand(ctx, msg, sessionName) {
+		return nil
+	}
+	if b.handleWhoisCommand(ctx, msg, sessionName) {
+		return nil
+	}
+	if b.handleRetentionCommand(ctx, msg, sessionName) {
+		return nil
+	}
+	if b.handleFindCommand(ctx, msg, sessionName) {
+		return nil
+	}
+	if b.handleStarCommand(ctx, msg, sessionName) {
+		return nil
+	}
 
 	hasMedia := fmt.Sprintf("%t", len(msg.Attachments) > 0)
 	metrics.IncrementCounter("message_processing_total", map[string]string{
@@ -403,9 +712,23 @@ func (b *bridge) HandleSignalMessageWithDestination(ctx context.Context, msg *si
 		}, "Message processing failures by stage")
 		return b.handleNewSignalThread(ctx, msg)
 	}
+	if mapping.SessionName == "" {
+		var relay bool
+		if sessionName, relay = b.resolveReplySession(ctx, msg, sessionName, mapping.WhatsAppChatID); !relay {
+			return nil
+		}
+	}
+
+	if handled, err := b.handleChatCommand(ctx, msg, sessionName, mapping.WhatsAppChatID); handled {
+		return err
+	}
+	if b.skipPausedSignalMessage(ctx, msg, sessionName, mapping.WhatsAppChatID) {
+		return nil
+	}
 
 	// Process attachments
-	attachments, err := b.processSignalAttachments(msg.Attachments)
+	capped, omittedNotes := b.capAttachments(ctx, msg.Attachments, sessionName)
+	attachments, oversizeNotes, err := b.processSignalAttachments(ctx, capped, sessionName)
 	if err != nil {
 		metrics.IncrementCounter("message_processing_failures", map[string]string{
 			"direction":    "signal_to_whatsapp",
@@ -416,14 +739,21 @@ func (b *bridge) HandleSignalMessageWithDestination(ctx context.Context, msg *si
 		return fmt.Errorf("failed to process attachments: %w", err)
 	}
 
-	// Determine reply target when quoting
+	// Determine reply target when quoting. Without a stored WhatsApp message there is nothing
+	// to reply to natively, so orphan replies carry the quoted text instead.
 	replyTo := ""
+	text, mentions := whatsAppMentions(mapping.WhatsAppChatID, msg.Message, msg.Mentions)
 	if msg.QuotedMessage != nil && mapping.WhatsAppMsgID != "" {
 		replyTo = mapping.WhatsAppMsgID
+	} else if msg.QuotedMessage != nil && b.signalConfig.RelayOrphanReplies {
+		text = withQuoteContext(msg.QuotedMessage.Text, text)
 	}
+	text = b.transforms.Apply(models.TransformSignalToWhatsApp, text)
+	text = withAttachmentNotes(text, append(oversizeNotes, omittedNotes...))
+	text, captionOverflow := b.fitCaption(text, attachments)
 
 	// Send message to WhatsApp
-	resp, err := b.sendMessageToWhatsApp(ctx, mapping.WhatsAppChatID, msg.Message, attachments, replyTo, sessionName)
+	resp, err := b.sendMessageToWhatsApp(ctx, mapping.WhatsAppChatID, text, mentions, attachments, replyTo, sessionName)
 	if err != nil {
 		metrics.IncrementCounter("message_processing_failures", map[string]string{
 			"direction":    "signal_to_whatsapp",
@@ -431,6 +761,7 @@ func (b *bridge) HandleSignalMessageWithDestination(ctx context.Context, msg *si
 			"message_type": "direct",
 			"stage":        "send_whatsapp",
 		}, "Message processing failures by stage")
+		b.notifyDeliveryFailure(ctx, msg, sessionName, err)
 		return err
 	}
 	if resp == nil {
@@ -462,6 +793,10 @@ func (b *bridge) HandleSignalMessageWithDestination(ctx context.Context, msg *si
 		}, "Message processing failures by stage")
 		return err
 	}
+	if captionOverflow != "" {
+		b.sendCaptionOverflow(ctx, msg, mapping.WhatsAppChatID, captionOverflow, sessionName)
+	}
+	b.markChatSeenAfterReply(ctx, mapping.WhatsAppChatID, sessionName)
 
 	metrics.IncrementCounter("message_processing_success", map[string]string{
 		"direction":    "signal_to_whatsapp",
@@ -474,8 +809,9 @@ func (b *bridge) HandleSignalMessageWithDestination(ctx context.Context, msg *si
 		"session":      sessionName,
 		"message_type": "direct",
 	}, "Message processing duration")
+	recordRelayLatency(ctx, "signal_to_whatsapp")
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		LogFieldChatID:    SanitizePhoneNumber(mapping.WhatsAppChatID),
 		LogFieldMessageID: SanitizeMessageID(resp.MessageID),
 		LogFieldDirection: "outgoing",
@@ -493,6 +829,13 @@ func (b *bridge) HandleSignalReceipt(ctx context.Context, msg *signaltypes.Signa
 		return nil
 	}
 
+	status := ""
+	if msg.Receipt.IsRead || msg.Receipt.IsViewed {
+		status = string(models.DeliveryStatusRead)
+	} else if msg.Receipt.IsDelivery {
+		status = string(models.DeliveryStatusDelivered)
+	}
+
 	targetID := fmt.Sprintf("%d", msg.Receipt.TargetTimestamp)
 	mapping, err := b.db.GetMessageMappingBySignalID(ctx, targetID)
 	if err != nil {
@@ -505,14 +848,15 @@ func (b *bridge) HandleSignalReceipt(ctx context.Context, msg *signaltypes.Signa
 		}
 	}
 	if mapping == nil {
+		if b.deliveryReceipts != nil && status != "" {
+			// The send this confirms may not be recorded yet
+			b.deliveryReceipts.hold(msg, status)
+			recordReceiptResult("held")
+		}
 		return nil
 	}
-
-	status := ""
-	if msg.Receipt.IsRead || msg.Receipt.IsViewed {
-		status = string(models.DeliveryStatusRead)
-	} else if msg.Receipt.IsDelivery {
-		status = string(models.DeliveryStatusDelivered)
+	if b.deliveryReceipts != nil {
+		recordReceiptResult("matched")
 	}
 
 	if status != "" && shouldUpdateDeliveryStatus(mapping.DeliveryStatus, status) {
@@ -541,7 +885,7 @@ func (b *bridge) saveSignalToWhatsAppMapping(ctx context.Context, msg *signaltyp
 		WhatsAppMsgID:   resp.MessageID,
 		SignalMsgID:     msg.MessageID,
 		SignalTimestamp: time.Unix(msg.Timestamp/constants.MillisecondsPerSecond, 0),
-		ForwardedAt:     time.Now(),
+		ForwardedAt:     b.clock.Now(),
 		DeliveryStatus:  models.DeliveryStatusSent,
 		SessionName:     sessionName,
 	}
@@ -559,7 +903,7 @@ func (b *bridge) saveSignalToWhatsAppMapping(ctx context.Context, msg *signaltyp
 }
 
 func (b *bridge) HandleSignalMessageDeletion(ctx context.Context, targetMessageID string, sender string) error {
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"targetMessageID": SanitizeMessageID(targetMessageID),
 		"sender":          SanitizePhoneNumber(sender),
 	}).Debug("Processing Signal message deletion")
@@ -567,7 +911,7 @@ func (b *bridge) HandleSignalMessageDeletion(ctx context.Context, targetMessageI
 	// Look up the message mapping for the target message by Signal ID
 	mapping, err := b.db.GetMessageMappingBySignalID(ctx, targetMessageID)
 	if err != nil {
-		b.logger.WithFields(logrus.Fields{
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"targetMessageID": SanitizeMessageID(targetMessageID),
 			"error":           err,
 		}).Error("Failed to get message mapping for deletion")
@@ -577,14 +921,14 @@ func (b *bridge) HandleSignalMessageDeletion(ctx context.Context, targetMessageI
 	if mapping == nil {
 		// Target message mapping is missing. Deletions can't be forwarded without knowing
 		// which WA message to delete. Drop gracefully.
-		b.logger.WithField("targetMessageID", SanitizeMessageID(targetMessageID)).Debug("Skipping deletion — no mapping found for target message")
+		b.logger.WithContext(ctx).WithField("targetMessageID", SanitizeMessageID(targetMessageID)).Debug("Skipping deletion — no mapping found for target message")
 		return nil
 	}
 
 	// Delete the message in WhatsApp
 	err = b.waClient.DeleteMessage(ctx, mapping.WhatsAppChatID, mapping.WhatsAppMsgID)
 	if err != nil {
-		b.logger.WithFields(logrus.Fields{
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"whatsappChatID": SanitizePhoneNumber(mapping.WhatsAppChatID),
 			"whatsappMsgID":  SanitizeWhatsAppMessageID(mapping.WhatsAppMsgID),
 			"error":          err,
@@ -592,7 +936,7 @@ func (b *bridge) HandleSignalMessageDeletion(ctx context.Context, targetMessageI
 		return fmt.Errorf("failed to delete message in WhatsApp: %w", err)
 	}
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"whatsappChatID":  SanitizePhoneNumber(mapping.WhatsAppChatID),
 		"whatsappMsgID":   SanitizeWhatsAppMessageID(mapping.WhatsAppMsgID),
 		"targetMessageID": SanitizeMessageID(targetMessageID),
@@ -604,13 +948,20 @@ func (b *bridge) HandleSignalMessageDeletion(ctx context.Context, targetMessageI
 // sendMessageToWhatsApp sends a message to WhatsApp with proper media type routing.
 // This consolidates the send logic used by both direct and group message handlers.
 // Uses exponential backoff retry for transient WAHA errors (e.g., markedUnread, 500 errors).
-func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, message string, attachments []string, replyTo string, sessionName string) (*types.SendMessageResponse, error) {
+// mentions lists WhatsApp IDs mentioned by a text message; media captions are sent without them.
+func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, message string, mentions []string, attachments []string, replyTo string, sessionName string) (*types.SendMessageResponse, error) {
 	trimmedMessage := strings.TrimSpace(message)
 	if len(attachments) == 0 && trimmedMessage == "" {
 		return nil, nil
 	}
 
+	if b.typing != nil && len(attachments) == 0 {
+		stopTyping := b.typing.start(ctx, chatID, sessionName, trimmedMessage)
+		defer stopTyping()
+	}
+
 	sendStart := time.Now()
+	mediaType := b.relayMediaType(attachments)
 
 	backoffConfig := retry.BackoffConfig{
 		InitialDelay: time.Duration(b.retryConfig.InitialBackoffMs) * time.Millisecond,
@@ -630,7 +981,7 @@ func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, messa
 
 		switch {
 		case len(attachments) > 0 && b.mediaRouter.IsImageAttachment(attachments[0]):
-			b.logger.WithFields(logrus.Fields{
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"method":      "SendImage",
 				"sessionName": sessionName,
 				"attempt":     attempt,
@@ -638,7 +989,7 @@ func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, messa
 			resp, sendErr = b.waClient.SendImageWithSession(ctx, chatID, attachments[0], message, replyTo, sessionName)
 
 		case len(attachments) > 0 && b.mediaRouter.IsVideoAttachment(attachments[0]):
-			b.logger.WithFields(logrus.Fields{
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"method":      "SendVideo",
 				"sessionName": sessionName,
 				"attempt":     attempt,
@@ -646,7 +997,7 @@ func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, messa
 			resp, sendErr = b.waClient.SendVideoWithSession(ctx, chatID, attachments[0], message, replyTo, sessionName)
 
 		case len(attachments) > 0 && b.mediaRouter.IsVoiceAttachment(attachments[0]):
-			b.logger.WithFields(logrus.Fields{
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"method":      "SendVoice",
 				"sessionName": sessionName,
 				"attempt":     attempt,
@@ -654,7 +1005,7 @@ func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, messa
 			resp, sendErr = b.waClient.SendVoiceWithSession(ctx, chatID, attachments[0], replyTo, sessionName)
 
 		case len(attachments) > 0:
-			b.logger.WithFields(logrus.Fields{
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"method":      "SendDocument",
 				"filePath":    attachments[0],
 				"sessionName": sessionName,
@@ -663,18 +1014,23 @@ func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, messa
 			resp, sendErr = b.waClient.SendDocumentWithSession(ctx, chatID, attachments[0], message, replyTo, sessionName)
 
 		default:
-			b.logger.WithFields(logrus.Fields{
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"method":        "SendText",
 				"sessionName":   sessionName,
 				"messageLength": len(trimmedMessage),
 				"attempt":       attempt,
+				"mentions":      len(mentions),
 			}).Debug("Sending text to WhatsApp")
-			resp, sendErr = b.waClient.SendTextWithSession(ctx, chatID, trimmedMessage, replyTo, sessionName)
+			if len(mentions) > 0 {
+				resp, sendErr = b.waClient.SendMentionsWithSession(ctx, chatID, trimmedMessage, mentions, replyTo, sessionName)
+			} else {
+				resp, sendErr = b.waClient.SendTextWithSession(ctx, chatID, trimmedMessage, replyTo, sessionName)
+			}
 		}
 
 		if sendErr != nil {
 			isRetryable := isRetryableWhatsAppError(sendErr)
-			b.logger.WithFields(logrus.Fields{
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"sessionName": sessionName,
 				"chatID":      SanitizePhoneNumber(chatID),
 				"attempt":     attempt,
@@ -686,7 +1042,7 @@ func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, messa
 			if isRetryable && !sessionRecoveryAttempted && shouldRestartWhatsAppSession(sendErr) {
 				sessionRecoveryAttempted = true
 				if recoveryErr := b.restartWhatsAppSession(ctx, sessionName); recoveryErr != nil {
-					b.logger.WithError(recoveryErr).WithFields(logrus.Fields{
+					b.logger.WithContext(ctx).WithError(recoveryErr).WithFields(logrus.Fields{
 						"sessionName": sessionName,
 						"chatID":      SanitizePhoneNumber(chatID),
 					}).Error("Failed to restart WhatsApp session after send failure")
@@ -700,18 +1056,20 @@ func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, messa
 	if retryErr != nil {
 		retryable := fmt.Sprintf("%t", isRetryableWhatsAppError(retryErr))
 		metrics.IncrementCounter("whatsapp_send_total", map[string]string{
-			"session": sessionName,
-			"status":  "failure",
+			"session":    sessionName,
+			"status":     "failure",
+			"media_type": mediaType,
 		}, "WhatsApp send outcomes")
 		metrics.IncrementCounter("whatsapp_send_failures", map[string]string{
-			"session":   sessionName,
-			"retryable": retryable,
+			"session":    sessionName,
+			"retryable":  retryable,
+			"media_type": mediaType,
 		}, "WhatsApp send failures by retryability")
 		metrics.RecordTimer("whatsapp_send_duration", time.Since(sendStart), map[string]string{
 			"session": sessionName,
 			"status":  "failure",
 		}, "WhatsApp send duration including retries")
-		b.logger.WithFields(logrus.Fields{
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"sessionName":   sessionName,
 			"chatID":        SanitizePhoneNumber(chatID),
 			"totalAttempts": attempt,
@@ -724,15 +1082,16 @@ func (b *bridge) sendMessageToWhatsApp(ctx context.Context, chatID string, messa
 	}
 
 	metrics.IncrementCounter("whatsapp_send_total", map[string]string{
-		"session": sessionName,
-		"status":  "success",
+		"session":    sessionName,
+		"status":     "success",
+		"media_type": mediaType,
 	}, "WhatsApp send outcomes")
 	metrics.RecordTimer("whatsapp_send_duration", time.Since(sendStart), map[string]string{
 		"session": sessionName,
 		"status":  "success",
 	}, "WhatsApp send duration including retries")
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"sessionName": sessionName,
 		"chatID":      SanitizePhoneNumber(chatID),
 		"attempts":    attempt,
@@ -764,7 +1123,7 @@ func (b *bridge) restartWhatsAppSession(ctx context.Context, sessionName string)
 		sessionName = b.waClient.GetSessionName()
 	}
 
-	b.logger.WithField("sessionName", sessionName).Warn("Restarting WhatsApp session after retryable send failure")
+	b.logger.WithContext(ctx).WithField("sessionName", sessionName).Warn("Restarting WhatsApp session after retryable send failure")
 	if err := b.waClient.RestartSessionByName(ctx, sessionName); err != nil {
 		return err
 	}
@@ -774,7 +1133,7 @@ func (b *bridge) restartWhatsAppSession(ctx context.Context, sessionName string)
 		return err
 	}
 
-	b.logger.WithField("sessionName", sessionName).Info("WhatsApp session recovered after restart")
+	b.logger.WithContext(ctx).WithField("sessionName", sessionName).Info("WhatsApp session recovered after restart")
 	return nil
 }
 
@@ -788,7 +1147,7 @@ func (b *bridge) resolveMessageMapping(ctx context.Context, msg *signaltypes.Sig
 	if msg.QuotedMessage == nil {
 		// No quoted message - find the latest message for auto-reply (fallback).
 		// Log at Warn so we can diagnose why the quote was not populated.
-		b.logger.WithFields(logrus.Fields{
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"messageID":       SanitizeMessageID(msg.MessageID),
 			"isSentByMe":      msg.IsSentByMe,
 			"hasReaction":     msg.Reaction != nil,
@@ -797,15 +1156,18 @@ func (b *bridge) resolveMessageMapping(ctx context.Context, msg *signaltypes.Sig
 			"sessionName":     sessionName,
 		}).Warn("Fallback routing triggered - SignalMessage has no quoted message")
 		mapping, err = b.db.GetLatestMessageMappingBySession(ctx, sessionName)
+		if err == nil && mapping == nil {
+			mapping, err = b.awaitLatestMapping(ctx, sessionName)
+		}
 		if err != nil {
-			b.logger.WithFields(logrus.Fields{
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"sessionName": sessionName,
 				"error":       err,
 			}).Error("Failed to get latest message mapping for auto-reply")
 			return nil, false, fmt.Errorf("failed to get latest message mapping for auto-reply: %w", err)
 		}
 		if mapping != nil {
-			b.logger.WithFields(logrus.Fields{
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"whatsappChatID": mapping.WhatsAppChatID,
 			}).Debug("Found latest message mapping for auto-reply (fallback)")
 		}
@@ -813,13 +1175,13 @@ func (b *bridge) resolveMessageMapping(ctx context.Context, msg *signaltypes.Sig
 	}
 
 	// Has quoted message - look it up
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"quotedMessageID": msg.QuotedMessage.ID,
 	}).Debug("Looking up message mapping for quoted message")
 
 	mapping, err = b.db.GetMessageMapping(ctx, msg.QuotedMessage.ID)
 	if err != nil {
-		b.logger.WithFields(logrus.Fields{
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"quotedMessageID": msg.QuotedMessage.ID,
 			"error":           err,
 		}).Error("Failed to get message mapping for quoted message")
@@ -827,18 +1189,21 @@ func (b *bridge) resolveMessageMapping(ctx context.Context, msg *signaltypes.Sig
 	}
 
 	if mapping != nil {
-		b.logger.WithFields(logrus.Fields{
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"quotedMessageID": msg.QuotedMessage.ID,
 		}).Debug("Found message mapping in database")
 		return mapping, false, nil // false = explicit quote, not fallback
 	}
 
 	// Try fallback extraction from quoted message text
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"quotedMessageID": msg.QuotedMessage.ID,
 	}).Debug("No message mapping found in database, trying fallback")
 
 	mapping = b.extractMappingFromQuotedText(ctx, msg.QuotedMessage.Text)
+	if mapping == nil && b.signalConfig.RelayOrphanReplies {
+		mapping = b.orphanReplyMapping(msg, sessionName)
+	}
 	if mapping == nil {
 		return nil, false, fmt.Errorf("no mapping found for quoted message: %s", msg.QuotedMessage.ID)
 	}
@@ -859,7 +1224,7 @@ func (b *bridge) extractMappingFromQuotedText(ctx context.Context, quotedText st
 	}
 
 	senderInfo := parts[0]
-	b.logger.Debug("Attempting fallback extraction from quoted text")
+	b.logger.WithContext(ctx).Debug("Attempting fallback extraction from quoted text")
 
 	senderInfo = strings.TrimPrefix(senderInfo, "📱 ")
 
@@ -880,7 +1245,7 @@ func (b *bridge) extractMappingFromQuotedText(ctx context.Context, quotedText st
 	}
 
 	if len(phoneNumber) >= constants.MinPhoneNumberLength {
-		b.logger.Debug("Extracted phone number from quoted text for fallback")
+		b.logger.WithContext(ctx).Debug("Extracted phone number from quoted text for fallback")
 		return &models.MessageMapping{
 			WhatsAppChatID: phoneNumber + "@c.us",
 		}
@@ -899,11 +1264,11 @@ func (b *bridge) extractMappingFromQuotedText(ctx context.Context, quotedText st
 	if hasLetter {
 		contact, err := b.db.GetContactByName(ctx, senderInfo)
 		if err != nil {
-			b.logger.WithError(err).Debug("Contact name lookup failed")
+			b.logger.WithContext(ctx).WithError(err).Debug("Contact name lookup failed")
 			return nil
 		}
 		if contact != nil && contact.PhoneNumber != "" {
-			b.logger.WithField("name", senderInfo).Debug("Resolved sender name to phone number via contacts database")
+			b.logger.WithContext(ctx).WithField("name", senderInfo).Debug("Resolved sender name to phone number via contacts database")
 			chatID := contact.PhoneNumber
 			if !strings.HasSuffix(chatID, "@c.us") {
 				chatID = chatID + "@c.us"
@@ -917,14 +1282,17 @@ func (b *bridge) extractMappingFromQuotedText(ctx context.Context, quotedText st
 	return nil
 }
 
-func (b *bridge) processSignalAttachments(attachments []string) ([]string, error) {
+// processSignalAttachments caches Signal attachments for sending to WhatsApp. Attachments over
+// the size limit are compressed when a compressor is configured; otherwise they are dropped and
+// a note for each is returned for the message text. Other failures skip the attachment.
+func (b *bridge) processSignalAttachments(ctx context.Context, attachments []string, sessionName string) ([]string, []string, error) {
 	if len(attachments) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	b.logger.WithField("attachments", attachments).Debug("Processing Signal attachments")
 
-	var processed []string
+	var processed, notes []string
 	for i, attachment := range attachments {
 		b.logger.WithFields(logrus.Fields{
 			"attachment": attachment,
@@ -932,7 +1300,17 @@ func (b *bridge) processSignalAttachments(attachments []string) ([]string, error
 			"total":      len(attachments),
 		}).Debug("Processing individual attachment")
 
-		processedPath, err := b.media.ProcessMedia(attachment)
+		processedPath, err := b.processMediaForSession(ctx, attachment, sessionName, "signal_to_whatsapp")
+		var oversize *media.OversizeError
+		if errors.As(err, &oversize) {
+			var note string
+			processedPath, note = b.handleOversizeAttachment(ctx, attachment, oversize, sessionName)
+			if note != "" {
+				notes = append(notes, note)
+				continue
+			}
+			err = nil
+		}
 		if err != nil {
 			b.logger.WithFields(logrus.Fields{
 				"attachment": attachment,
@@ -951,7 +1329,7 @@ func (b *bridge) processSignalAttachments(attachments []string) ([]string, error
 	}
 
 	// If no attachments were successfully processed, log a warning but don't fail
-	if len(processed) == 0 && len(attachments) > 0 {
+	if len(processed) == 0 && len(notes) == 0 {
 		b.logger.WithField("originalCount", len(attachments)).Error("No attachments could be processed successfully")
 	} else if len(attachments) > 0 {
 		b.logger.WithFields(logrus.Fields{
@@ -960,7 +1338,7 @@ func (b *bridge) processSignalAttachments(attachments []string) ([]string, error
 		}).Debug("Attachment processing completed successfully")
 	}
 
-	return processed, nil
+	return processed, notes, nil
 }
 
 // Removed wrapper methods - use b.mediaRouter directly
@@ -998,7 +1376,7 @@ func (b *bridge) cleanupSignalAttachments(retentionDays int) error {
 		return fmt.Errorf("failed to read signal attachments directory: %w", err)
 	}
 
-	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	cutoff := b.clock.Now().AddDate(0, 0, -retentionDays)
 
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -1039,6 +1417,24 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 		return fmt.Errorf("failed to determine WhatsApp session for Signal destination %s: %w", destination, err)
 	}
 
+	if command := signalCommand(msg); command != "" && !b.isFromDestination(ctx, msg, destination, command) {
+		return nil
+	}
+
+	// Messages posted in a Signal group carry its ID. Reactions and deletions find their target
+	// by timestamp like direct ones do, and /linkgroup maps the group before anything is relayed.
+	if msg.GroupID != "" {
+		if msg.Reaction != nil {
+			return b.handleSignalReactionWithSession(ctx, msg, sessionName)
+		}
+		if msg.Deletion != nil {
+			return b.handleSignalDeletionWithSession(ctx, msg, sessionName)
+		}
+		if b.handleLinkGroupCommand(ctx, msg, sessionName) {
+			return nil
+		}
+	}
+
 	hasMedia := fmt.Sprintf("%t", len(msg.Attachments) > 0)
 	metrics.IncrementCounter("message_processing_total", map[string]string{
 		"direction":    "signal_to_whatsapp",
@@ -1047,14 +1443,24 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 		"has_media":    hasMedia,
 	}, "Total message processing attempts")
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"messageID": msg.MessageID,
 		"sender":    msg.Sender,
 		"session":   sessionName,
 	}).Debug("Processing Signal group message")
 
-	// Resolve target WhatsApp group chat
-	mapping, usedFallback, err := b.resolveGroupMessageMapping(ctx, msg, sessionName)
+	// Resolve target WhatsApp group chat: the mapped group for a Signal group, otherwise the
+	// group of the quoted or latest bridged message
+	var mapping *models.MessageMapping
+	var usedFallback bool
+	if msg.GroupID != "" {
+		mapping, err = b.resolveSignalGroupMapping(ctx, msg, sessionName)
+		if err == nil && mapping == nil {
+			return nil
+		}
+	} else {
+		mapping, usedFallback, err = b.resolveGroupMessageMapping(ctx, msg, sessionName)
+	}
 	if err != nil {
 		metrics.IncrementCounter("message_processing_failures", map[string]string{
 			"direction":    "signal_to_whatsapp",
@@ -1067,7 +1473,7 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 			// Send notification to Signal about how to route group messages
 			guidance := "Unable to determine target group. Please quote a message from the group you want to reply to."
 			if notifyErr := b.SendSignalNotificationForSession(ctx, sessionName, guidance); notifyErr != nil {
-				b.logger.WithError(notifyErr).Warn("Failed to send group routing guidance notification")
+				b.logger.WithContext(ctx).WithError(notifyErr).Warn("Failed to send group routing guidance notification")
 			}
 		}
 		return err
@@ -1084,6 +1490,13 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 		return fmt.Errorf("resolved chat is not a group: %s", mapping.WhatsAppChatID)
 	}
 
+	if handled, err := b.handleChatCommand(ctx, msg, sessionName, mapping.WhatsAppChatID); handled {
+		return err
+	}
+	if b.skipPausedSignalMessage(ctx, msg, sessionName, mapping.WhatsAppChatID) {
+		return nil
+	}
+
 	// Send warning if fallback routing was used, but suppress if destination is unchanged
 	groupKey := "group:" + sessionName
 	if usedFallback {
@@ -1100,7 +1513,7 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 			}
 			notice := fmt.Sprintf("Group message routed to: %s\nTip: Quote a message to reply to a specific group.", groupName)
 			if notifyErr := b.SendSignalNotificationForSession(ctx, sessionName, notice); notifyErr != nil {
-				b.logger.WithError(notifyErr).Warn("Failed to send group fallback routing notification")
+				b.logger.WithContext(ctx).WithError(notifyErr).Warn("Failed to send group fallback routing notification")
 			}
 		}
 
@@ -1114,7 +1527,8 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 	}
 
 	// Process attachments
-	attachments, err := b.processSignalAttachments(msg.Attachments)
+	capped, omittedNotes := b.capAttachments(ctx, msg.Attachments, sessionName)
+	attachments, oversizeNotes, err := b.processSignalAttachments(ctx, capped, sessionName)
 	if err != nil {
 		metrics.IncrementCounter("message_processing_failures", map[string]string{
 			"direction":    "signal_to_whatsapp",
@@ -1131,8 +1545,12 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 		replyTo = mapping.WhatsAppMsgID
 	}
 
-	// Send message to WhatsApp
-	resp, err := b.sendMessageToWhatsApp(ctx, mapping.WhatsAppChatID, msg.Message, attachments, replyTo, sessionName)
+	// Send message to WhatsApp, turning Signal mentions into WhatsApp mentions
+	text, mentions := whatsAppMentions(mapping.WhatsAppChatID, msg.Message, msg.Mentions)
+	text = b.transforms.Apply(models.TransformSignalToWhatsApp, text)
+	text = withAttachmentNotes(text, append(oversizeNotes, omittedNotes...))
+	text, captionOverflow := b.fitCaption(text, attachments)
+	resp, err := b.sendMessageToWhatsApp(ctx, mapping.WhatsAppChatID, text, mentions, attachments, replyTo, sessionName)
 	if err != nil {
 		metrics.IncrementCounter("message_processing_failures", map[string]string{
 			"direction":    "signal_to_whatsapp",
@@ -1140,6 +1558,7 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 			"message_type": "group",
 			"stage":        "send_whatsapp",
 		}, "Message processing failures by stage")
+		b.notifyDeliveryFailure(ctx, msg, sessionName, err)
 		return err
 	}
 	if resp == nil {
@@ -1157,6 +1576,10 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 		}, "Message processing failures by stage")
 		return err
 	}
+	if captionOverflow != "" {
+		b.sendCaptionOverflow(ctx, msg, mapping.WhatsAppChatID, captionOverflow, sessionName)
+	}
+	b.markChatSeenAfterReply(ctx, mapping.WhatsAppChatID, sessionName)
 
 	metrics.IncrementCounter("message_processing_success", map[string]string{
 		"direction":    "signal_to_whatsapp",
@@ -1169,8 +1592,9 @@ func (b *bridge) handleSignalGroupMessage(ctx context.Context, msg *signaltypes.
 		"session":      sessionName,
 		"message_type": "group",
 	}, "Message processing duration")
+	recordRelayLatency(ctx, "signal_to_whatsapp")
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		LogFieldChatID:    SanitizePhoneNumber(mapping.WhatsAppChatID),
 		LogFieldMessageID: SanitizeMessageID(resp.MessageID),
 		LogFieldDirection: "outgoing",
@@ -1191,7 +1615,7 @@ func (b *bridge) resolveGroupMessageMapping(ctx context.Context, msg *signaltype
 	var err error
 
 	if msg.QuotedMessage != nil {
-		b.logger.WithField("quotedMessageID", msg.QuotedMessage.ID).Debug("Looking up mapping for quoted message in group")
+		b.logger.WithContext(ctx).WithField("quotedMessageID", msg.QuotedMessage.ID).Debug("Looking up mapping for quoted message in group")
 		mapping, err = b.db.GetMessageMapping(ctx, msg.QuotedMessage.ID)
 		if err != nil {
 			return nil, false, fmt.Errorf("failed to get message mapping for quoted message: %w", err)
@@ -1204,7 +1628,7 @@ func (b *bridge) resolveGroupMessageMapping(ctx context.Context, msg *signaltype
 
 	// Fallback: use latest group message mapping for the session
 	// WARNING: This can route to wrong group under concurrent load
-	b.logger.WithField("sessionName", sessionName).Debug("No quoted message - using fallback to latest group mapping")
+	b.logger.WithContext(ctx).WithField("sessionName", sessionName).Debug("No quoted message - using fallback to latest group mapping")
 	mapping, err = b.db.GetLatestGroupMessageMappingBySession(ctx, sessionName, constants.DefaultGroupMappingLookbackLimit)
 	if err != nil {
 		return nil, true, fmt.Errorf("failed to get latest group message mapping for session: %w", err)
@@ -1213,7 +1637,7 @@ func (b *bridge) resolveGroupMessageMapping(ctx context.Context, msg *signaltype
 		return nil, true, fmt.Errorf("no group context found for session %s - quote a group message to establish context", sessionName)
 	}
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"sessionName":    sessionName,
 		"whatsappChatID": SanitizePhoneNumber(mapping.WhatsAppChatID),
 		"fallback":       true,
@@ -1223,7 +1647,7 @@ func (b *bridge) resolveGroupMessageMapping(ctx context.Context, msg *signaltype
 }
 
 func (b *bridge) handleNewSignalThread(ctx context.Context, msg *signaltypes.SignalMessage) error {
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"messageID": msg.MessageID,
 		"sender":    msg.Sender,
 	}).Error("Cannot start new conversation from Signal to WhatsApp - no message mapping found")
@@ -1241,7 +1665,7 @@ func (b *bridge) handleSignalReactionWithSession(ctx context.Context, msg *signa
 		"has_media":    "false",
 	}, "Total message processing attempts")
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"messageID":       msg.MessageID,
 		"sender":          msg.Sender,
 		"reaction":        msg.Reaction.Emoji,
@@ -1259,18 +1683,36 @@ func (b *bridge) handleSignalReactionWithSession(ctx context.Context, msg *signa
 			"message_type": "reaction",
 			"stage":        "resolve_mapping",
 		}, "Message processing failures by stage")
-		b.logger.WithError(err).Error("Failed to get message mapping for reaction target")
+		b.logger.WithContext(ctx).WithError(err).Error("Failed to get message mapping for reaction target")
 		return fmt.Errorf("failed to get message mapping for reaction target: %w", err)
 	}
 
 	if mapping == nil {
-		b.logger.WithFields(logrus.Fields{
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"targetID": targetID,
 			"session":  sessionName,
 		}).Warn("Skipping reaction because target mapping is missing and session fallback is ambiguous")
 		return nil
 	}
 
+	if capabilities := b.waClient.GetCapabilities(ctx); !capabilities.SupportsReactions() {
+		metrics.IncrementCounter("message_processing_failures", map[string]string{
+			"direction":    "signal_to_whatsapp",
+			"session":      sessionName,
+			"message_type": "reaction",
+			"stage":        "unsupported",
+		}, "Message processing failures by stage")
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
+			LogFieldSession: sessionName,
+			"engine":        capabilities.Engine,
+		}).Warn("Skipping reaction because the WAHA engine does not support reactions")
+		notice := fmt.Sprintf("Reaction not sent: reactions are not supported by %s.", capabilities)
+		if notifyErr := b.SendSignalNotificationForSession(ctx, sessionName, notice); notifyErr != nil {
+			b.logger.WithContext(ctx).WithError(notifyErr).Warn("Failed to send unsupported reaction notification")
+		}
+		return nil
+	}
+
 	// Send reaction to WhatsApp
 	reaction := msg.Reaction.Emoji
 	if msg.Reaction.IsRemove {
@@ -1278,18 +1720,34 @@ func (b *bridge) handleSignalReactionWithSession(ctx context.Context, msg *signa
 		reaction = ""
 	}
 
+	dedupKey := recentReactionKey(sessionName, msg.Sender, msg.Reaction)
+	if !b.claimReaction(ctx, dedupKey) {
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
+			LogFieldSession:   sessionName,
+			"targetTimestamp": msg.Reaction.TargetTimestamp,
+			"isRemove":        msg.Reaction.IsRemove,
+		}).Info("Skipping duplicate Signal reaction recently relayed")
+		metrics.IncrementCounter("reaction_duplicates_suppressed_total", map[string]string{
+			"session": sessionName,
+		}, "Duplicate Signal reactions suppressed")
+		return nil
+	}
+
 	resp, err := b.waClient.SendReactionWithSession(ctx, mapping.WhatsAppChatID, mapping.WhatsAppMsgID, reaction, sessionName)
 	if err != nil {
+		b.recentReactions.release(dedupKey)
 		metrics.IncrementCounter("message_processing_failures", map[string]string{
 			"direction":    "signal_to_whatsapp",
 			"session":      sessionName,
 			"message_type": "reaction",
 			"stage":        "send_whatsapp",
 		}, "Message processing failures by stage")
-		b.logger.WithError(err).Error("Failed to send reaction to WhatsApp")
+		b.logger.WithContext(ctx).WithError(err).Error("Failed to send reaction to WhatsApp")
 		return fmt.Errorf("failed to send reaction to WhatsApp: %w", err)
 	}
 
+	b.rememberReaction(ctx, dedupKey)
+
 	metrics.IncrementCounter("message_processing_success", map[string]string{
 		"direction":    "signal_to_whatsapp",
 		"session":      sessionName,
@@ -1302,7 +1760,7 @@ func (b *bridge) handleSignalReactionWithSession(ctx context.Context, msg *signa
 		"message_type": "reaction",
 	}, "Message processing duration")
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"whatsappMsgID": SanitizeWhatsAppMessageID(mapping.WhatsAppMsgID),
 		"reaction":      reaction,
 		"response":      resp,
@@ -1321,7 +1779,7 @@ func (b *bridge) handleSignalDeletionWithSession(ctx context.Context, msg *signa
 		"has_media":    "false",
 	}, "Total message processing attempts")
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"messageID":       msg.MessageID,
 		"sender":          msg.Sender,
 		"targetMessageID": msg.Deletion.TargetMessageID,
@@ -1379,7 +1837,7 @@ func (b *bridge) SendSignalNotificationForSession(ctx context.Context, sessionNa
 		return fmt.Errorf("failed to send Signal notification: %w", err)
 	}
 
-	b.logger.WithFields(logrus.Fields{
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"sessionName": sessionName,
 		"destination": dest,
 		"message":     message,
@@ -240,6 +240,41 @@ func TestHandleWhatsAppMessage(t *testing.T) {
 	}
 }
 
+func TestHandleWhatsAppMessage_SignalFormatting(t *testing.T) {
+	tests := []struct {
+		name             string
+		translate        bool
+		strip            bool
+		expectedMessage  string
+		expectedTextMode string
+	}{
+		{"passthrough by default", false, false, "sender123: *bold* and _italic_", ""},
+		{"translate to styled text", true, false, "sender123: **bold** and *italic*", signaltypes.TextModeStyled},
+		{"strip markers", false, true, "sender123: bold and italic", ""},
+		{"translate wins over strip", true, true, "sender123: **bold** and *italic*", signaltypes.TextModeStyled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			bridge.signalConfig.TranslateFormatting = tt.translate
+			bridge.signalConfig.StripFormatting = tt.strip
+			sigClient := bridge.sigClient.(*mockSignalClient)
+			sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{
+				MessageID: "sig-format",
+				Timestamp: time.Now().UnixMilli(),
+			}
+
+			err := bridge.HandleWhatsAppMessageWithSession(context.Background(), "default", "chat123", "msg-format", "sender123", "", "*bold* and _italic_", "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMessage, sigClient.lastMessage)
+			assert.Equal(t, tt.expectedTextMode, sigClient.lastSendOptions.TextMode)
+		})
+	}
+}
+
 func TestHandleWhatsAppMessageDeliveryStatus(t *testing.T) {
 	bridge, tmpDir, cleanup := setupTestBridge(t)
 	defer cleanup()
@@ -1172,6 +1207,37 @@ func TestHandleSignalReaction(t *testing.T) {
 	}
 }
 
+func TestHandleSignalReactionUnsupportedEngine(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	msg := &signaltypes.SignalMessage{
+		MessageID: "msg123",
+		Sender:    "sender123",
+		Timestamp: time.Now().UnixMilli(),
+		Reaction: &signaltypes.SignalReaction{
+			Emoji:           "👍",
+			TargetTimestamp: 1234567890000,
+		},
+	}
+
+	bridge.db.(*mockDatabaseService).On("GetMessageMapping", ctx, "1234567890000").Return(&models.MessageMapping{
+		WhatsAppChatID: "chat123@c.us",
+		WhatsAppMsgID:  "wa_msg456",
+	}, nil)
+	waClient := bridge.waClient.(*mockWhatsAppClient)
+	waClient.On("GetCapabilities", ctx).Return(types.NewCapabilities(&types.ServerVersion{Engine: "VENOM", Tier: "CORE"}))
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "notice"}
+
+	err := bridge.HandleSignalMessage(ctx, msg)
+	require.NoError(t, err)
+
+	waClient.AssertNotCalled(t, "SendReactionWithSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, "Reaction not sent: reactions are not supported by WAHA VENOM (CORE).", sigClient.lastMessage)
+}
+
 func TestNewBridge(t *testing.T) {
 	waClient := &mockWhatsAppClient{}
 	sigClient := &mockSignalClient{}
@@ -1624,6 +1690,55 @@ func TestBridge_HandleWhatsAppMessage_GroupMessage(t *testing.T) {
 	})
 }
 
+func TestBridge_WithoutContactService(t *testing.T) {
+	var typedNil *ContactService
+	services := map[string]ContactServiceInterface{
+		"nil":                       nil,
+		"typed nil":                 typedNil,
+		"without a WhatsApp client": NewContactService(&mockContactDatabaseService{}, nil),
+	}
+
+	for name, contacts := range services {
+		t.Run(name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			ctx := context.Background()
+			bridge.contactService = contacts
+			mockGroupService := new(mockGroupService)
+			mockGroupService.On("GetGroupName", ctx, "group123@g.us", "default").Return("Family Group")
+			bridge.groupService = mockGroupService
+			bridge.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{
+				MessageID: "sig-msg-123",
+				Timestamp: time.Now().UnixMilli(),
+			}
+			bridge.db.(*mockDatabaseService).On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+
+			require.NotPanics(t, func() {
+				err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "group123@g.us", "wa-msg-123", "1234567890@c.us", "", "Hello everyone", "")
+				require.NoError(t, err)
+			})
+			assert.Equal(t, "1234567890 in Family Group: Hello everyone", bridge.sigClient.(*mockSignalClient).lastMessage)
+
+			assert.Equal(t, "1234567890", bridge.contactDisplayName(ctx, "1234567890@c.us", ""))
+			assert.Equal(t, "Alice", bridge.contactDisplayName(ctx, "1234567890@c.us", "Alice"))
+			assert.Equal(t, "1234567890", bridge.chatDisplayName(ctx, "default", "1234567890@c.us"))
+			assert.Equal(t, "1234567890", bridge.senderDisplayName(ctx, "1234567890"))
+		})
+	}
+}
+
+func TestNewBridgeWithConfig_ContactRefreshWithoutContactService(t *testing.T) {
+	channelManager, err := NewChannelManager([]models.Channel{{WhatsAppSessionName: "default", SignalDestinationPhoneNumber: "+1234567890"}})
+	require.NoError(t, err)
+	cfg := models.Config{WhatsApp: models.WhatsAppConfig{ContactRefreshOnRelay: true}}
+
+	var typedNil *ContactService
+	b := NewBridgeWithConfig(&mockWhatsAppClient{}, &mockSignalClient{}, &mockDatabaseService{}, &mockMediaHandler{}, cfg, channelManager, typedNil, nil, logrus.New()).(*bridge)
+	assert.Nil(t, b.contactRefresh, "no refresher without a contact service")
+	assert.Equal(t, "1234567890", b.senderDisplayName(context.Background(), "1234567890"))
+}
+
 func TestExtractMappingFromQuotedText(t *testing.T) {
 	bridge, _, cleanup := setupTestBridge(t)
 	defer cleanup()
@@ -1819,7 +1934,7 @@ func TestSendMessageToWhatsApp_RetriesOnTransientError(t *testing.T) {
 	}
 	bridge.waClient = mockWA
 
-	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@g.us", "Test message", nil, "", "default")
+	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@g.us", "Test message", nil, nil, "", "default")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
@@ -1850,7 +1965,7 @@ func TestSendMessageToWhatsApp_RestartsStuckStartingSession(t *testing.T) {
 	mockWA.On("WaitForSessionReadyByName", mock.Anything, "jo", mock.AnythingOfType("time.Duration")).Return(nil).Once()
 	bridge.waClient = mockWA
 
-	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@c.us", "Test message", nil, "", "jo")
+	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@c.us", "Test message", nil, nil, "", "jo")
 
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -1874,7 +1989,7 @@ func TestSendMessageToWhatsApp_FailsOnNonRetryableError(t *testing.T) {
 	}
 	bridge.waClient = mockWA
 
-	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@c.us", "Test message", nil, "", "default")
+	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@c.us", "Test message", nil, nil, "", "default")
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
@@ -1897,7 +2012,7 @@ func TestSendMessageToWhatsApp_FailsAfterMaxRetries(t *testing.T) {
 	}
 	bridge.waClient = mockWA
 
-	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@c.us", "Test message", nil, "", "default")
+	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@c.us", "Test message", nil, nil, "", "default")
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
@@ -1911,7 +2026,7 @@ func TestSendMessageToWhatsApp_EmptyMessage(t *testing.T) {
 
 	ctx := context.Background()
 
-	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@c.us", "   ", nil, "", "default")
+	resp, err := bridge.sendMessageToWhatsApp(ctx, "123456789@c.us", "   ", nil, nil, "", "default")
 
 	assert.NoError(t, err)
 	assert.Nil(t, resp, "Empty message should return nil response")
@@ -2240,6 +2355,73 @@ func TestResolveMessageMapping_QuoteFound_RoutesToCorrectChat(t *testing.T) {
 	assert.Equal(t, "alice@c.us", mapping.WhatsAppChatID, "Should route to Alice")
 }
 
+func TestHandleSignalMessage_QuotedReplyThreadsOnWhatsApp(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	b.db.(*mockDatabaseService).On("GetMessageMapping", ctx, "7777777").Return(&models.MessageMapping{
+		WhatsAppChatID: "alice@c.us",
+		WhatsAppMsgID:  "wamid.alice1",
+		SignalMsgID:    "7777777",
+		SessionName:    "default",
+	}, nil)
+	b.db.(*mockDatabaseService).On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	// The quoted message's WhatsApp ID becomes WAHA's reply_to, so WhatsApp shows it as a reply
+	b.waClient.(*mockWhatsAppClient).On("SendTextWithSession", ctx, "alice@c.us", "Reply to Alice", "wamid.alice1", "default").
+		Return(&types.SendMessageResponse{MessageID: "wamid.reply1", Status: "sent"}, nil)
+
+	err := b.HandleSignalMessage(ctx, &signaltypes.SignalMessage{
+		MessageID: "signal_reply_1",
+		Sender:    "+1234567890",
+		Message:   "Reply to Alice",
+		Timestamp: time.Now().UnixMilli(),
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{ID: "7777777", Author: "+1234567890", Text: "Alice's message"},
+	})
+	require.NoError(t, err)
+
+	b.waClient.(*mockWhatsAppClient).AssertExpectations(t)
+}
+
+func TestHandleSignalGroupMessage_QuotedReplyThreadsOnWhatsApp(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	b.db.(*mockDatabaseService).On("GetMessageMapping", ctx, "wa_msg_1").Return(&models.MessageMapping{
+		WhatsAppChatID: "group123@g.us",
+		WhatsAppMsgID:  "wa_msg_1",
+		SignalMsgID:    "sig_orig",
+		ForwardedAt:    time.Now(),
+	}, nil)
+	b.db.(*mockDatabaseService).On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	b.waClient.(*mockWhatsAppClient).On("SendTextWithSession", ctx, "group123@g.us", "Count me in", "wa_msg_1", "default").
+		Return(&types.SendMessageResponse{MessageID: "wa_msg_reply", Status: "sent"}, nil)
+
+	err := b.HandleSignalMessage(ctx, &signaltypes.SignalMessage{
+		MessageID: "sig_reply_1",
+		Sender:    "group.123",
+		Message:   "Count me in",
+		Timestamp: time.Now().UnixMilli(),
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{ID: "wa_msg_1"},
+	})
+	require.NoError(t, err)
+
+	b.waClient.(*mockWhatsAppClient).AssertExpectations(t)
+}
+
 func TestResolveMessageMapping_TimestampPrecisionMismatch_ReturnsError(t *testing.T) {
 	b, _, cleanup := setupTestBridge(t)
 	defer cleanup()
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"whatsignal/internal/models"
+	"whatsignal/internal/transform"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBridgeAppliesTransformsPerDirection(t *testing.T) {
+	const chat = "111@c.us"
+	bridge, _, cleanup := setupPauseTestBridge(t, chat)
+	defer cleanup()
+
+	transforms, err := transform.Compile([]models.TransformRule{
+		{Type: models.TransformReplace, Pattern: `(?i)hunter2`, Replacement: "[redacted]"},
+		{Type: models.TransformSuffix, Text: " — sent from Signal", Direction: models.TransformSignalToWhatsApp},
+	})
+	require.NoError(t, err)
+	bridge.transforms = transforms
+
+	var sentToWhatsApp []string
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		sentToWhatsApp = append(sentToWhatsApp, text)
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+
+	ctx := context.Background()
+	err = bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "sig1", Sender: "+1234567890", Message: "my password is hunter2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"my password is [redacted] — sent from Signal"}, sentToWhatsApp)
+
+	err = bridge.HandleWhatsAppMessageWithSession(ctx, "default", chat, "wa1", chat, "Alice", "is it Hunter2?", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice: is it [redacted]?", bridge.sigClient.(*mockSignalClient).lastMessage, "the signature only applies to Signal to WhatsApp")
+}
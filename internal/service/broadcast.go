@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"whatsignal/internal/metrics"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chatCommandBroadcast sends the rest of the message to the channel's broadcast targets
+const chatCommandBroadcast = "/broadcast"
+
+// parseBroadcastCommand returns the text of a "/broadcast <message>" Signal message
+func parseBroadcastCommand(msg *signaltypes.SignalMessage) (string, bool) {
+	if len(msg.Attachments) > 0 {
+		return "", false
+	}
+	text := strings.TrimSpace(msg.Message)
+	if len(text) < len(chatCommandBroadcast) || !strings.EqualFold(text[:len(chatCommandBroadcast)], chatCommandBroadcast) {
+		return "", false
+	}
+	rest := text[len(chatCommandBroadcast):]
+	if rest != "" && !strings.HasPrefix(rest, " ") && !strings.HasPrefix(rest, "\n") {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// handleBroadcastCommand sends a /broadcast message to the broadcast targets configured for
+// the session. Only the channel's own Signal number may broadcast. It reports whether msg was a
+// broadcast command.
+func (b *bridge) handleBroadcastCommand(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string) bool {
+	text, ok := parseBroadcastCommand(msg)
+	if !ok {
+		return false
+	}
+
+	var notice string
+	targets := b.channelManager.GetBroadcastTargets(sessionName)
+	switch {
+	case text == "":
+		notice = fmt.Sprintf("Usage: %s <message>", chatCommandBroadcast)
+	case len(targets) == 0:
+		notice = "No broadcast targets are configured for this channel."
+	default:
+		// Never return the error: a retried command would broadcast the message again
+		if err := b.BroadcastMessage(ctx, sessionName, targets, text); err != nil {
+			b.logger.WithContext(ctx).WithError(err).Warn("Broadcast did not complete")
+		}
+		return true
+	}
+
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, notice); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to send broadcast usage notification")
+	}
+	return true
+}
+
+// BroadcastMessage sends text to each WhatsApp chat in turn, pacing sends to respect WhatsApp
+// rate limits, and reports which chats succeeded or failed back to Signal. A failure for one
+// chat does not stop the broadcast.
+func (b *bridge) BroadcastMessage(ctx context.Context, sessionName string, chats []string, text string) error {
+	var failures []string
+	delivered := 0
+
+	for i, chatID := range chats {
+		if i > 0 && b.broadcastInterval > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(b.broadcastInterval):
+			}
+		}
+
+		status := "success"
+		resp, err := b.sendMessageToWhatsApp(ctx, chatID, text, nil, nil, "", sessionName)
+		if err == nil && resp == nil {
+			err = fmt.Errorf("nothing was sent")
+		}
+		if err != nil {
+			status = "failure"
+			failures = append(failures, fmt.Sprintf("%s: %v", b.chatDisplayName(ctx, sessionName, chatID), err))
+			b.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+				LogFieldSession: sessionName,
+				LogFieldChatID:  SanitizePhoneNumber(chatID),
+			}).Warn("Broadcast to WhatsApp chat failed")
+		} else {
+			delivered++
+		}
+
+		metrics.IncrementCounter("broadcast_messages_total", map[string]string{
+			"session": sessionName,
+			"status":  status,
+		}, "Broadcast messages sent to WhatsApp chats")
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession: sessionName,
+		"delivered":     delivered,
+		"failed":        len(failures),
+	}).Info("Broadcast completed")
+
+	report := fmt.Sprintf("Broadcast sent to %d of %d chats.", delivered, len(chats))
+	if len(failures) > 0 {
+		report += "\nFailed:\n- " + strings.Join(failures, "\n- ")
+	}
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, report); err != nil {
+		return fmt.Errorf("failed to report broadcast result: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBroadcastCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      signaltypes.SignalMessage
+		wantText string
+		wantOK   bool
+	}{
+		{"broadcast", signaltypes.SignalMessage{Message: "/broadcast hello all"}, "hello all", true},
+		{"case and whitespace", signaltypes.SignalMessage{Message: "  /BROADCAST   hi  "}, "hi", true},
+		{"multiline", signaltypes.SignalMessage{Message: "/broadcast\nline one\nline two"}, "line one\nline two", true},
+		{"empty text", signaltypes.SignalMessage{Message: "/broadcast"}, "", true},
+		{"longer word", signaltypes.SignalMessage{Message: "/broadcasting now"}, "", false},
+		{"regular message", signaltypes.SignalMessage{Message: "hello"}, "", false},
+		{"with attachment", signaltypes.SignalMessage{Message: "/broadcast hi", Attachments: []string{"a.jpg"}}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, ok := parseBroadcastCommand(&tt.msg)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantText, text)
+		})
+	}
+}
+
+// setupBroadcastTestBridge returns a bridge whose default channel broadcasts to targets and
+// which records the chats messages were sent to on WhatsApp
+func setupBroadcastTestBridge(t *testing.T, targets []string, failing map[string]bool) (*bridge, *[]string, func()) {
+	bridge, _, cleanup := setupTestBridge(t)
+	bridge.broadcastInterval = 0
+
+	channelManager, err := NewChannelManager([]models.Channel{{
+		WhatsAppSessionName:          "default",
+		SignalDestinationPhoneNumber: "+1234567890",
+		BroadcastTargets:             targets,
+	}})
+	require.NoError(t, err)
+	bridge.channelManager = channelManager
+
+	bridge.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{
+		MessageID: "sig_sent",
+		Timestamp: time.Now().UnixMilli(),
+	}
+
+	var sentTo []string
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		sentTo = append(sentTo, chatID)
+		if failing[chatID] {
+			return nil, errors.New("chat not found")
+		}
+		return &types.SendMessageResponse{MessageID: "wa_" + chatID, Status: "sent"}, nil
+	}
+
+	return bridge, &sentTo, cleanup
+}
+
+func TestBroadcastMessageReportsPartialFailure(t *testing.T) {
+	targets := []string{"111@c.us", "222@c.us", "333@g.us"}
+	bridge, sentTo, cleanup := setupBroadcastTestBridge(t, targets, map[string]bool{"222@c.us": true})
+	defer cleanup()
+
+	err := bridge.BroadcastMessage(context.Background(), "default", targets, "hello all")
+	require.NoError(t, err)
+
+	assert.Contains(t, *sentTo, "111@c.us")
+	assert.Contains(t, *sentTo, "333@g.us", "a failed chat does not stop the broadcast")
+
+	report := bridge.sigClient.(*mockSignalClient).lastMessage
+	assert.Contains(t, report, "Broadcast sent to 2 of 3 chats.")
+	assert.Contains(t, report, "Failed:\n- 222: ")
+	assert.Contains(t, report, "chat not found")
+	assert.NotContains(t, report, "111")
+}
+
+func TestBroadcastMessageStopsWhenCanceled(t *testing.T) {
+	targets := []string{"111@c.us", "222@c.us"}
+	bridge, sentTo, cleanup := setupBroadcastTestBridge(t, targets, nil)
+	defer cleanup()
+	bridge.broadcastInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		*sentTo = append(*sentTo, chatID)
+		cancel()
+		return &types.SendMessageResponse{MessageID: "wa_" + chatID, Status: "sent"}, nil
+	}
+
+	err := bridge.BroadcastMessage(ctx, "default", targets, "hello all")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"111@c.us"}, *sentTo)
+}
+
+func TestBroadcastCommandSendsToConfiguredTargets(t *testing.T) {
+	targets := []string{"111@c.us", "222@c.us"}
+	bridge, sentTo, cleanup := setupBroadcastTestBridge(t, targets, nil)
+	defer cleanup()
+
+	// No mapping lookup is expected: the command is handled before reply resolution
+	err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{
+		MessageID: "cmd1",
+		Sender:    "+1234567890",
+		Message:   "/broadcast meeting moved to 3pm",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, targets, *sentTo)
+	assert.Contains(t, bridge.sigClient.(*mockSignalClient).lastMessage, "Broadcast sent to 2 of 2 chats.")
+}
+
+func TestBroadcastCommandWithoutTargets(t *testing.T) {
+	bridge, sentTo, cleanup := setupBroadcastTestBridge(t, nil, nil)
+	defer cleanup()
+
+	err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{
+		MessageID: "cmd1",
+		Sender:    "+1234567890",
+		Message:   "/broadcast hello",
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, *sentTo)
+	assert.Equal(t, "No broadcast targets are configured for this channel.", bridge.sigClient.(*mockSignalClient).lastMessage)
+}
+
+func TestBroadcastCommandIgnoresOtherSenders(t *testing.T) {
+	targets := []string{"111@c.us", "222@c.us"}
+	bridge, sentTo, cleanup := setupBroadcastTestBridge(t, targets, nil)
+	defer cleanup()
+
+	err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{
+		MessageID: "cmd1",
+		Sender:    "+15550000001",
+		Message:   "/broadcast meeting moved to 3pm",
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, *sentTo, "nothing is sent to the broadcast targets")
+	assert.Empty(t, bridge.sigClient.(*mockSignalClient).lastMessage)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// whatsAppRelay is one WhatsApp message waiting to be relayed to Signal
+type whatsAppRelay struct {
+	sessionName       string
+	chatID            string
+	msgID             string
+	sender            string
+	senderDisplayName string
+	content           string
+	mediaPath         string
+}
+
+// pendingCaptionMedia is a media message held back so a text sent right after it can become its caption
+type pendingCaptionMedia struct {
+	ctx   context.Context
+	msg   whatsAppRelay
+	timer *time.Timer
+}
+
+// captionJoiner coalesces a WhatsApp media message and a text from the same sender that follows
+// within the window into a single Signal message. It holds at most one media message per chat;
+// anything else arriving for the chat flushes it first so ordering is preserved.
+type captionJoiner struct {
+	mu      sync.Mutex
+	window  time.Duration
+	pending map[string]*pendingCaptionMedia
+	relay   func(ctx context.Context, msg whatsAppRelay) error
+	link    func(ctx context.Context, mediaMsgID, textMsgID string) error
+	logger  *logrus.Logger
+}
+
+func newCaptionJoiner(window time.Duration, relay func(context.Context, whatsAppRelay) error, link func(context.Context, string, string) error, logger *logrus.Logger) *captionJoiner {
+	return &captionJoiner{
+		window:  window,
+		pending: make(map[string]*pendingCaptionMedia),
+		relay:   relay,
+		link:    link,
+		logger:  logger,
+	}
+}
+
+// handle relays msg, holding media back for the window and joining a following text into it
+func (j *captionJoiner) handle(ctx context.Context, msg whatsAppRelay) error {
+	key := msg.sessionName + "|" + msg.chatID
+
+	j.mu.Lock()
+	held := j.pending[key]
+	if held != nil {
+		// If the timer already fired, its callback sees the entry is gone and does nothing
+		held.timer.Stop()
+		delete(j.pending, key)
+	}
+	j.mu.Unlock()
+
+	if held != nil {
+		if msg.mediaPath == "" && msg.sender == held.msg.sender && strings.TrimSpace(msg.content) != "" {
+			return j.relayJoined(ctx, held.msg, msg)
+		}
+		j.relayHeld(held)
+	}
+
+	if msg.mediaPath != "" && j.hold(ctx, key, msg) {
+		return nil
+	}
+	return j.relay(ctx, msg)
+}
+
+// hold buffers msg until the window passes. It reports false if another message for the chat
+// was held concurrently, in which case msg should be relayed straight away.
+func (j *captionJoiner) hold(ctx context.Context, key string, msg whatsAppRelay) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.pending[key] != nil {
+		return false
+	}
+
+	held := &pendingCaptionMedia{
+		// The webhook request finishes before the timer fires
+		ctx: context.WithoutCancel(ctx),
+		msg: msg,
+	}
+	held.timer = time.AfterFunc(j.window, func() {
+		j.mu.Lock()
+		if j.pending[key] != held {
+			j.mu.Unlock()
+			return
+		}
+		delete(j.pending, key)
+		j.mu.Unlock()
+		j.relayHeld(held)
+	})
+	j.pending[key] = held
+	return true
+}
+
+// relayHeld sends a held media message on its own. Errors can only be logged: the webhook
+// that delivered the message has already been answered.
+func (j *captionJoiner) relayHeld(held *pendingCaptionMedia) {
+	if err := j.relay(held.ctx, held.msg); err != nil {
+		j.logger.WithError(err).WithFields(logrus.Fields{
+			LogFieldSession:   held.msg.sessionName,
+			LogFieldMessageID: SanitizeWhatsAppMessageID(held.msg.msgID),
+		}).Error("Failed to relay held WhatsApp media message")
+	}
+}
+
+// relayJoined sends media with text appended to its caption and maps the text message to the
+// resulting Signal message so replies and reactions to either resolve
+func (j *captionJoiner) relayJoined(ctx context.Context, media, text whatsAppRelay) error {
+	joined := media
+	if caption := strings.TrimSpace(media.content); caption != "" {
+		joined.content = caption + "\n" + text.content
+	} else {
+		joined.content = text.content
+	}
+
+	j.logger.WithFields(logrus.Fields{
+		LogFieldSession:   media.sessionName,
+		LogFieldMessageID: SanitizeWhatsAppMessageID(media.msgID),
+		"text_msg_id":     SanitizeWhatsAppMessageID(text.msgID),
+	}).Debug("Joining WhatsApp text into preceding media caption")
+
+	if err := j.relay(ctx, joined); err != nil {
+		return err
+	}
+	if err := j.link(ctx, media.msgID, text.msgID); err != nil {
+		j.logger.WithError(err).Warn("Failed to map joined WhatsApp text to its Signal message")
+	}
+	return nil
+}
+
+// linkJoinedWhatsAppMessage maps a WhatsApp text joined into a media caption to the Signal
+// message the media was relayed as
+func (b *bridge) linkJoinedWhatsAppMessage(ctx context.Context, mediaMsgID, textMsgID string) error {
+	mapping, err := b.db.GetMessageMappingByWhatsAppID(ctx, mediaMsgID)
+	if err != nil {
+		return fmt.Errorf("failed to get mapping for joined media: %w", err)
+	}
+	if mapping == nil {
+		// Paused chats and suppressed duplicates are not relayed; there is nothing to link to
+		return nil
+	}
+
+	textMapping := *mapping
+	textMapping.ID = 0
+	textMapping.WhatsAppMsgID = textMsgID
+	textMapping.MediaPath = nil
+	textMapping.MediaType = ""
+	if err := b.db.SaveMessageMapping(ctx, &textMapping); err != nil {
+		return fmt.Errorf("failed to save mapping for joined text: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRelay records the messages a captionJoiner relays and the text messages it links
+type recordingRelay struct {
+	mu      sync.Mutex
+	relayed []whatsAppRelay
+	linked  [][2]string
+}
+
+func (r *recordingRelay) relay(_ context.Context, msg whatsAppRelay) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.relayed = append(r.relayed, msg)
+	return nil
+}
+
+func (r *recordingRelay) link(_ context.Context, mediaMsgID, textMsgID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.linked = append(r.linked, [2]string{mediaMsgID, textMsgID})
+	return nil
+}
+
+func (r *recordingRelay) snapshot() []whatsAppRelay {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]whatsAppRelay(nil), r.relayed...)
+}
+
+func newTestCaptionJoiner(window time.Duration) (*captionJoiner, *recordingRelay) {
+	rec := &recordingRelay{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return newCaptionJoiner(window, rec.relay, rec.link, logger), rec
+}
+
+func mediaRelay(msgID, sender, caption string) whatsAppRelay {
+	return whatsAppRelay{sessionName: "default", chatID: "111@c.us", msgID: msgID, sender: sender, content: caption, mediaPath: "/tmp/" + msgID + ".jpg"}
+}
+
+func textRelay(msgID, sender, text string) whatsAppRelay {
+	return whatsAppRelay{sessionName: "default", chatID: "111@c.us", msgID: msgID, sender: sender, content: text}
+}
+
+func TestCaptionJoinerJoinsTextWithinWindow(t *testing.T) {
+	joiner, rec := newTestCaptionJoiner(time.Hour)
+	ctx := context.Background()
+
+	require.NoError(t, joiner.handle(ctx, mediaRelay("wa_media", "111@c.us", "")))
+	assert.Empty(t, rec.snapshot(), "media is held for a possible caption")
+
+	require.NoError(t, joiner.handle(ctx, textRelay("wa_text", "111@c.us", "look at this")))
+
+	relayed := rec.snapshot()
+	require.Len(t, relayed, 1)
+	assert.Equal(t, "wa_media", relayed[0].msgID)
+	assert.Equal(t, "look at this", relayed[0].content)
+	assert.NotEmpty(t, relayed[0].mediaPath)
+	assert.Equal(t, [][2]string{{"wa_media", "wa_text"}}, rec.linked)
+}
+
+func TestCaptionJoinerAppendsToExistingCaption(t *testing.T) {
+	joiner, rec := newTestCaptionJoiner(time.Hour)
+	ctx := context.Background()
+
+	require.NoError(t, joiner.handle(ctx, mediaRelay("wa_media", "111@c.us", "beach")))
+	require.NoError(t, joiner.handle(ctx, textRelay("wa_text", "111@c.us", "day two")))
+
+	relayed := rec.snapshot()
+	require.Len(t, relayed, 1)
+	assert.Equal(t, "beach\nday two", relayed[0].content)
+}
+
+func TestCaptionJoinerRelaysMediaAloneAfterWindow(t *testing.T) {
+	joiner, rec := newTestCaptionJoiner(10 * time.Millisecond)
+	ctx := context.Background()
+
+	require.NoError(t, joiner.handle(ctx, mediaRelay("wa_media", "111@c.us", "caption")))
+	require.Eventually(t, func() bool { return len(rec.snapshot()) == 1 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, joiner.handle(ctx, textRelay("wa_text", "111@c.us", "later text")))
+
+	relayed := rec.snapshot()
+	require.Len(t, relayed, 2)
+	assert.Equal(t, "caption", relayed[0].content)
+	assert.Equal(t, "later text", relayed[1].content)
+	assert.Empty(t, relayed[1].mediaPath)
+	assert.Empty(t, rec.linked)
+}
+
+func TestCaptionJoinerKeepsIndependentMessagesSeparate(t *testing.T) {
+	tests := []struct {
+		name string
+		next whatsAppRelay
+	}{
+		{"text from another sender", textRelay("wa_next", "222@c.us", "hello")},
+		{"another media", mediaRelay("wa_next", "111@c.us", "")},
+		{"empty text", textRelay("wa_next", "111@c.us", "  ")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joiner, rec := newTestCaptionJoiner(time.Hour)
+			ctx := context.Background()
+
+			require.NoError(t, joiner.handle(ctx, mediaRelay("wa_media", "111@c.us", "caption")))
+			require.NoError(t, joiner.handle(ctx, tt.next))
+
+			relayed := rec.snapshot()
+			require.NotEmpty(t, relayed)
+			assert.Equal(t, "wa_media", relayed[0].msgID, "held media is relayed first")
+			assert.Equal(t, "caption", relayed[0].content)
+			assert.Empty(t, rec.linked)
+		})
+	}
+}
+
+func TestCaptionJoinerTextWithoutHeldMedia(t *testing.T) {
+	joiner, rec := newTestCaptionJoiner(time.Hour)
+
+	require.NoError(t, joiner.handle(context.Background(), textRelay("wa_text", "111@c.us", "hi")))
+
+	relayed := rec.snapshot()
+	require.Len(t, relayed, 1)
+	assert.Equal(t, "wa_text", relayed[0].msgID)
+}
+
+func TestLinkJoinedWhatsAppMessage(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	mediaPath := "/cache/abc.jpg"
+	mockDB := bridge.db.(*mockDatabaseService)
+	mockDB.On("GetMessageMappingByWhatsAppID", mock.Anything, "wa_media").Return(&models.MessageMapping{
+		ID:             7,
+		WhatsAppChatID: "111@c.us",
+		WhatsAppMsgID:  "wa_media",
+		SignalMsgID:    "sig_1",
+		MediaPath:      &mediaPath,
+		MediaType:      "image",
+		SessionName:    "default",
+	}, nil)
+	mockDB.On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+
+	require.NoError(t, bridge.linkJoinedWhatsAppMessage(context.Background(), "wa_media", "wa_text"))
+
+	mockDB.AssertCalled(t, "SaveMessageMapping", mock.Anything, mock.MatchedBy(func(m *models.MessageMapping) bool {
+		return m.ID == 0 && m.WhatsAppMsgID == "wa_text" && m.SignalMsgID == "sig_1" && m.WhatsAppChatID == "111@c.us" && m.MediaPath == nil
+	}))
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"whatsignal/internal/metrics"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// zeroWidthJoiner joins emoji into one, such as a family; a caption is never cut next to it
+const zeroWidthJoiner = '\u200d'
+
+// splitCaption cuts caption to at most maxRunes characters and returns the rest. It cuts at
+// the last space in the second half of the limit when there is one, so words stay whole, and
+// never inside a character: combining marks, variation selectors and joined emoji stay with the
+// character before them. A caption that fits, or a maxRunes of 0, is returned unchanged.
+func splitCaption(caption string, maxRunes int) (string, string) {
+	runes := []rune(caption)
+	if maxRunes <= 0 || len(runes) <= maxRunes {
+		return caption, ""
+	}
+
+	cut := maxRunes
+	for i := maxRunes; i > maxRunes/2; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	for cut > 1 && (unicode.In(runes[cut], unicode.Mn, unicode.Me) || unicode.Is(unicode.Variation_Selector, runes[cut]) ||
+		runes[cut] == zeroWidthJoiner || runes[cut-1] == zeroWidthJoiner) {
+		cut--
+	}
+
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace), strings.TrimLeftFunc(string(runes[cut:]), unicode.IsSpace)
+}
+
+// fitCaption cuts the caption of a media message to whatsapp.maxCaptionLength and returns the
+// text to send after the media. Text messages, and voice notes, which carry no caption, are
+// returned unchanged.
+func (b *bridge) fitCaption(text string, attachments []string) (string, string) {
+	if len(attachments) == 0 || b.mediaRouter.IsVoiceAttachment(attachments[0]) {
+		return text, ""
+	}
+	return splitCaption(text, b.maxCaptionLength)
+}
+
+// sendCaptionOverflow sends the part of a caption cut by fitCaption as a text message after the
+// media and maps it to msg as well, so replies to either reach the Signal message. The media
+// was already delivered, so a failure is logged rather than failing the relay.
+func (b *bridge) sendCaptionOverflow(ctx context.Context, msg *signaltypes.SignalMessage, chatID, overflow, sessionName string) {
+	metrics.IncrementCounter("caption_overflow_total", map[string]string{
+		"session": sessionName,
+	}, "Media captions too long for WhatsApp sent partly as a separate message")
+
+	logger := b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession: sessionName,
+		LogFieldChatID:  SanitizePhoneNumber(chatID),
+		"signal_msg_id": SanitizeMessageID(msg.MessageID),
+	})
+
+	resp, err := b.sendMessageToWhatsApp(ctx, chatID, overflow, nil, nil, "", sessionName)
+	if err != nil {
+		logger.WithError(err).Error("Failed to send the rest of a long caption to WhatsApp")
+		return
+	}
+	if resp == nil {
+		return
+	}
+	if err := b.saveSignalToWhatsAppMapping(ctx, msg, resp, chatID, nil, sessionName); err != nil {
+		logger.WithError(err).Warn("Failed to save mapping for the rest of a long caption")
+		return
+	}
+	logger.Debug("Sent the rest of a long caption to WhatsApp")
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"whatsignal/internal/models"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCaption(t *testing.T) {
+	tests := []struct {
+		name     string
+		caption  string
+		maxRunes int
+		head     string
+		rest     string
+	}{
+		{name: "fits", caption: "short caption", maxRunes: 20, head: "short caption"},
+		{name: "no limit", caption: "short caption", maxRunes: 0, head: "short caption"},
+		{name: "breaks at a space", caption: "hello there world", maxRunes: 14, head: "hello there", rest: "world"},
+		{name: "hard cut without a late space", caption: "a bcdefghijkl", maxRunes: 6, head: "a bcde", rest: "fghijkl"},
+		{name: "counts characters, not bytes", caption: "ééééé", maxRunes: 3, head: "ééé", rest: "éé"},
+		{name: "keeps combining marks", caption: "abce\u0301fg", maxRunes: 4, head: "abc", rest: "e\u0301fg"},
+		{name: "keeps joined emoji", caption: "ab\U0001F469\u200d\U0001F4BBcd", maxRunes: 3, head: "ab", rest: "\U0001F469\u200d\U0001F4BBcd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, rest := splitCaption(tt.caption, tt.maxRunes)
+			assert.Equal(t, tt.head, head)
+			assert.Equal(t, tt.rest, rest)
+		})
+	}
+}
+
+func TestHandleSignalMessage_CaptionOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		caption  string
+		sentText string
+		mappings int
+	}{
+		{
+			name:     "long caption is split",
+			caption:  strings.Repeat("word ", 5) + "the rest of it",
+			sentText: "the rest of it",
+			mappings: 2,
+		},
+		{
+			name:     "short caption is sent inline",
+			caption:  "look",
+			mappings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+			bridge.maxCaptionLength = 25
+
+			ctx := context.Background()
+			db := bridge.db.(*mockDatabaseService)
+			db.On("GetMessageMapping", ctx, "wa_msg_1").Return(&models.MessageMapping{
+				WhatsAppChatID: "group123@g.us",
+				WhatsAppMsgID:  "wa_msg_1",
+				SignalMsgID:    "sig_orig",
+			}, nil).Once()
+			var saved []*models.MessageMapping
+			db.On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Run(func(args mock.Arguments) {
+				saved = append(saved, args.Get(1).(*models.MessageMapping))
+			}).Return(nil).Times(tt.mappings)
+
+			bridge.media.(*mockMediaHandler).On("ProcessMedia", "/tmp/photo.jpg").Return("/cache/photo.jpg", nil).Once()
+
+			head, _ := splitCaption(tt.caption, 25)
+			waClient := bridge.waClient.(*mockWhatsAppClient)
+			waClient.On("SendImageWithSession", ctx, "group123@g.us", "/cache/photo.jpg", head, "wa_msg_1", "default").
+				Return(&types.SendMessageResponse{MessageID: "wa_image", Status: "sent"}, nil).Once()
+			var sentText string
+			waClient.sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+				sentText = text
+				return &types.SendMessageResponse{MessageID: "wa_text", Status: "sent"}, nil
+			}
+
+			msg := oversizeTestMessage("/tmp/photo.jpg")
+			msg.Message = tt.caption
+			err := bridge.HandleSignalMessage(ctx, msg)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.sentText, sentText)
+			require.Len(t, saved, tt.mappings)
+			for _, mapping := range saved {
+				assert.Equal(t, msg.MessageID, mapping.SignalMsgID, "every part maps back to the Signal message")
+			}
+			if tt.mappings == 2 {
+				assert.Equal(t, "wa_image", saved[0].WhatsAppMsgID)
+				assert.Equal(t, "wa_text", saved[1].WhatsAppMsgID)
+			}
+			waClient.AssertExpectations(t)
+			db.AssertExpectations(t)
+		})
+	}
+}
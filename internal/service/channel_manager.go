@@ -9,18 +9,34 @@ import (
 
 // ChannelManager manages the mapping between WhatsApp sessions and Signal destinations
 type ChannelManager struct {
-	channels     map[string]string // whatsappSessionName -> signalDestinationPhoneNumber
-	reverse      map[string]string // signalDestinationPhoneNumber -> whatsappSessionName
-	orderedNames []string          // ordered list of session names (preserves config order)
+	channels     map[string]string   // whatsappSessionName -> signalDestinationPhoneNumber
+	reverse      map[string]string   // signalDestinationPhoneNumber -> whatsappSessionName
+	orderedNames []string            // ordered list of session names (preserves config order)
+	broadcasts   map[string][]string // whatsappSessionName -> WhatsApp chat IDs for /broadcast
+	participants map[string]bool     // whatsappSessionName -> relay group participant changes
+	secrets      map[string]string   // whatsappSessionName -> webhook secret, when set per channel
+	mediaConfig  models.MediaConfig
+	mediaByName  map[string]models.MediaConfig // whatsappSessionName -> media config, when overridden per channel
 	mu           sync.RWMutex
 }
 
 // NewChannelManager creates a new channel manager from configuration
 func NewChannelManager(channels []models.Channel) (*ChannelManager, error) {
+	return NewChannelManagerWithMedia(channels, models.MediaConfig{})
+}
+
+// NewChannelManagerWithMedia creates a new channel manager whose channels' media overrides
+// are applied on top of the global media configuration
+func NewChannelManagerWithMedia(channels []models.Channel, mediaConfig models.MediaConfig) (*ChannelManager, error) {
 	cm := &ChannelManager{
+		mediaConfig:  mediaConfig,
+		mediaByName:  make(map[string]models.MediaConfig),
 		channels:     make(map[string]string),
 		reverse:      make(map[string]string),
 		orderedNames: make([]string, 0, len(channels)),
+		broadcasts:   make(map[string][]string),
+		participants: make(map[string]bool),
+		secrets:      make(map[string]string),
 	}
 
 	// Build the mappings
@@ -45,6 +61,18 @@ func NewChannelManager(channels []models.Channel) (*ChannelManager, error) {
 		cm.channels[channel.WhatsAppSessionName] = channel.SignalDestinationPhoneNumber
 		cm.reverse[channel.SignalDestinationPhoneNumber] = channel.WhatsAppSessionName
 		cm.orderedNames = append(cm.orderedNames, channel.WhatsAppSessionName)
+		if len(channel.BroadcastTargets) > 0 {
+			cm.broadcasts[channel.WhatsAppSessionName] = append([]string(nil), channel.BroadcastTargets...)
+		}
+		if channel.RelayGroupParticipants {
+			cm.participants[channel.WhatsAppSessionName] = true
+		}
+		if channel.WebhookSecret != "" {
+			cm.secrets[channel.WhatsAppSessionName] = channel.WebhookSecret
+		}
+		if channel.Media != nil {
+			cm.mediaByName[channel.WhatsAppSessionName] = mediaConfig.WithOverride(channel.Media)
+		}
 	}
 
 	// Ensure at least one channel is configured
@@ -115,6 +143,53 @@ func (cm *ChannelManager) GetAllWhatsAppSessions() []string {
 	return sessions
 }
 
+// GetBroadcastTargets returns the WhatsApp chats that receive /broadcast messages for a session
+func (cm *ChannelManager) GetBroadcastTargets(whatsappSessionName string) []string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	return append([]string(nil), cm.broadcasts[whatsappSessionName]...)
+}
+
+// RelaysGroupParticipants reports whether the session's channel relays members joining and
+// leaving its groups
+func (cm *ChannelManager) RelaysGroupParticipants(whatsappSessionName string) bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	return cm.participants[whatsappSessionName]
+}
+
+// GetWebhookSecret returns the webhook secret set on the session's channel, or "" when the
+// channel uses the global whatsapp.webhook_secret
+func (cm *ChannelManager) GetWebhookSecret(whatsappSessionName string) string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	return cm.secrets[whatsappSessionName]
+}
+
+// GetMediaConfig returns the media configuration for the session's attachments: the global
+// configuration with the channel's media override applied, if it has one
+func (cm *ChannelManager) GetMediaConfig(whatsappSessionName string) models.MediaConfig {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if mediaConfig, ok := cm.mediaByName[whatsappSessionName]; ok {
+		return mediaConfig
+	}
+	return cm.mediaConfig
+}
+
+// HasMediaOverride reports whether the session's channel overrides the global media configuration
+func (cm *ChannelManager) HasMediaOverride(whatsappSessionName string) bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	_, ok := cm.mediaByName[whatsappSessionName]
+	return ok
+}
+
 // IsValidSession checks if a WhatsApp session is configured
 func (cm *ChannelManager) IsValidSession(sessionName string) bool {
 	cm.mu.RLock()
@@ -93,6 +93,58 @@ func TestChannelManager_IsValidSession(t *testing.T) {
 	}
 }
 
+func TestChannelManager_RelaysGroupParticipants(t *testing.T) {
+	cm, err := NewChannelManager([]models.Channel{
+		{WhatsAppSessionName: "business", SignalDestinationPhoneNumber: "+1111111111", RelayGroupParticipants: true},
+		{WhatsAppSessionName: "personal", SignalDestinationPhoneNumber: "+2222222222"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, cm.RelaysGroupParticipants("business"))
+	assert.False(t, cm.RelaysGroupParticipants("personal"))
+	assert.False(t, cm.RelaysGroupParticipants("nonexistent"))
+}
+
+func TestChannelManager_GetWebhookSecret(t *testing.T) {
+	cm, err := NewChannelManager([]models.Channel{
+		{WhatsAppSessionName: "business", SignalDestinationPhoneNumber: "+1111111111", WebhookSecret: "business-secret"},
+		{WhatsAppSessionName: "personal", SignalDestinationPhoneNumber: "+2222222222"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "business-secret", cm.GetWebhookSecret("business"))
+	assert.Empty(t, cm.GetWebhookSecret("personal"), "channels without a secret use the global one")
+	assert.Empty(t, cm.GetWebhookSecret("nonexistent"))
+}
+
+func TestChannelManager_GetMediaConfig(t *testing.T) {
+	global := models.MediaConfig{
+		MaxSizeMB:    models.MediaSizeLimits{Image: 5, Video: 100, Document: 100, Voice: 16},
+		AllowedTypes: models.MediaAllowedTypes{Document: []string{"pdf"}},
+	}
+	cm, err := NewChannelManagerWithMedia([]models.Channel{
+		{WhatsAppSessionName: "business", SignalDestinationPhoneNumber: "+1111111111", Media: &models.MediaConfig{
+			MaxSizeMB: models.MediaSizeLimits{Document: 200},
+		}},
+		{WhatsAppSessionName: "personal", SignalDestinationPhoneNumber: "+2222222222", Media: &models.MediaConfig{
+			MaxSizeMB: models.MediaSizeLimits{Image: 25, Video: 25, Document: 25, Voice: 25},
+		}},
+		{WhatsAppSessionName: "family", SignalDestinationPhoneNumber: "+3333333333"},
+	}, global)
+	require.NoError(t, err)
+
+	business := cm.GetMediaConfig("business")
+	assert.Equal(t, models.MediaSizeLimits{Image: 5, Video: 100, Document: 200, Voice: 16}, business.MaxSizeMB)
+	assert.Equal(t, []string{"pdf"}, business.AllowedTypes.Document, "unset fields fall back to the global config")
+	assert.True(t, cm.HasMediaOverride("business"))
+
+	assert.Equal(t, models.MediaSizeLimits{Image: 25, Video: 25, Document: 25, Voice: 25}, cm.GetMediaConfig("personal").MaxSizeMB)
+
+	assert.Equal(t, global, cm.GetMediaConfig("family"))
+	assert.False(t, cm.HasMediaOverride("family"))
+	assert.Equal(t, global, cm.GetMediaConfig("nonexistent"))
+}
+
 func TestChannelManager_IsValidDestination(t *testing.T) {
 	channels := []models.Channel{
 		{WhatsAppSessionName: "business", SignalDestinationPhoneNumber: "+1111111111"},
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"os"
+
+	"whatsignal/internal/metrics"
+)
+
+// processMediaForSession caches the media at path for sessionName. Media of a channel with a
+// media override is validated against the channel's size limits and types instead of the
+// global ones. The size of the cached file is counted in media_bytes_processed_total for
+// direction.
+func (b *bridge) processMediaForSession(ctx context.Context, path, sessionName, direction string) (string, error) {
+	var processed string
+	var err error
+	if b.channelManager != nil && b.channelManager.HasMediaOverride(sessionName) {
+		processed, err = b.media.ProcessMediaWithConfig(ctx, path, sessionName, b.channelManager.GetMediaConfig(sessionName))
+	} else {
+		processed, err = b.media.ProcessMediaForSession(ctx, path, sessionName)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if info, statErr := os.Stat(processed); statErr == nil {
+		metrics.AddToCounter("media_bytes_processed_total", float64(info.Size()), map[string]string{
+			"direction": direction,
+			"session":   sessionName,
+		}, "Bytes of media cached for relaying")
+	}
+	return processed, nil
+}
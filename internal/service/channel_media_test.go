@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"whatsignal/internal/models"
+	"whatsignal/pkg/media"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessSignalAttachments_ChannelMediaOverride(t *testing.T) {
+	b, tmpDir, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	global := b.mediaConfig
+	global.MaxSizeMB = models.MediaSizeLimits{Image: 5, Video: 100, Document: 100, Voice: 16}
+	channelManager, err := NewChannelManagerWithMedia([]models.Channel{
+		{WhatsAppSessionName: "business", SignalDestinationPhoneNumber: "+1111111111", Media: &models.MediaConfig{
+			MaxSizeMB: models.MediaSizeLimits{Document: 200},
+		}},
+		{WhatsAppSessionName: "personal", SignalDestinationPhoneNumber: "+2222222222", Media: &models.MediaConfig{
+			MaxSizeMB: models.MediaSizeLimits{Document: 1},
+		}},
+	}, global)
+	require.NoError(t, err)
+	b.channelManager = channelManager
+
+	handler, err := media.NewHandler(filepath.Join(tmpDir, "channel-cache"), global)
+	require.NoError(t, err)
+	b.media = handler
+
+	document := filepath.Join(tmpDir, "contract.pdf")
+	require.NoError(t, os.WriteFile(document, make([]byte, 2*1024*1024), 0644))
+	ctx := context.Background()
+
+	processed, notes, err := b.processSignalAttachments(ctx, []string{document}, "business")
+	require.NoError(t, err)
+	assert.Len(t, processed, 1)
+	assert.Empty(t, notes)
+
+	processed, notes, err = b.processSignalAttachments(ctx, []string{document}, "personal")
+	require.NoError(t, err)
+	assert.Empty(t, processed, "the personal channel's 1 MB document limit rejects the file")
+	assert.Len(t, notes, 1)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"whatsignal/internal/metrics"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chatCommandAutoReply sets the message automatically sent back to the chat a Signal message
+// resolves to; "/autoreply off" clears it
+const (
+	chatCommandAutoReply = "/autoreply"
+	autoReplyOff         = "off"
+)
+
+// parseAutoReplyCommand reports whether a Signal message is an /autoreply command and returns
+// its argument. The command must start the message and carry no attachments.
+func parseAutoReplyCommand(msg *signaltypes.SignalMessage) (string, bool) {
+	if len(msg.Attachments) > 0 {
+		return "", false
+	}
+	text := strings.TrimSpace(msg.Message)
+	command, argument, _ := strings.Cut(text, " ")
+	if !strings.EqualFold(command, chatCommandAutoReply) {
+		return "", false
+	}
+	return strings.TrimSpace(argument), true
+}
+
+// handleAutoReplyCommand stores or clears the auto-reply of chatID and confirms it on Signal
+func (b *bridge) handleAutoReplyCommand(ctx context.Context, sessionName, chatID, argument string) error {
+	if argument == "" {
+		usage := fmt.Sprintf("Usage: %s <message>, or %s %s to turn it off.", chatCommandAutoReply, chatCommandAutoReply, autoReplyOff)
+		if err := b.SendSignalNotificationForSession(ctx, sessionName, usage); err != nil {
+			b.logger.WithContext(ctx).WithError(err).Warn("Failed to send auto-reply usage on Signal")
+		}
+		return nil
+	}
+
+	message := argument
+	if strings.EqualFold(argument, autoReplyOff) {
+		message = ""
+	}
+	if err := b.db.SetChatAutoReply(ctx, sessionName, chatID, message); err != nil {
+		return fmt.Errorf("failed to update auto-reply for chat: %w", err)
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession: sessionName,
+		LogFieldChatID:  SanitizePhoneNumber(chatID),
+		"enabled":       message != "",
+	}).Info("Updated chat auto-reply from Signal command")
+
+	chatName := b.chatDisplayName(ctx, sessionName, chatID)
+	confirmation := fmt.Sprintf("Auto-reply turned off for %s.", chatName)
+	if message != "" {
+		confirmation = fmt.Sprintf("Auto-reply set for %s, sent at most once every %d minutes: %s", chatName, int(b.autoReplyCooldown.Minutes()), message)
+	}
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, confirmation); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to confirm auto-reply command on Signal")
+	}
+	return nil
+}
+
+// sendChatAutoReply answers a WhatsApp message from chatID with the chat's auto-reply, unless
+// none is set or one was sent within the cooldown. Failures are only logged: the message is
+// relayed to Signal either way.
+func (b *bridge) sendChatAutoReply(ctx context.Context, sessionName, chatID string) {
+	message, err := b.db.ClaimChatAutoReply(ctx, sessionName, chatID, b.clock.Now(), b.autoReplyCooldown)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithField(LogFieldSession, sessionName).Warn("Failed to check auto-reply for chat")
+		return
+	}
+	if message == "" {
+		return
+	}
+
+	fields := logrus.Fields{
+		LogFieldSession: sessionName,
+		LogFieldChatID:  SanitizePhoneNumber(chatID),
+	}
+	if _, err := b.waClient.SendTextWithSession(ctx, chatID, message, "", sessionName); err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithFields(fields).Warn("Failed to send auto-reply to WhatsApp")
+		return
+	}
+
+	metrics.IncrementCounter("auto_replies_sent_total", map[string]string{
+		"session": sessionName,
+	}, "Auto-replies sent to WhatsApp chats")
+	b.logger.WithContext(ctx).WithFields(fields).Info("Sent auto-reply to WhatsApp chat")
+}
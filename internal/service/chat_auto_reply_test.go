@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/testutil"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAutoReplyCommand(t *testing.T) {
+	tests := []struct {
+		name             string
+		msg              signaltypes.SignalMessage
+		expectedArgument string
+		expectedOK       bool
+	}{
+		{"message", signaltypes.SignalMessage{Message: "/autoreply Away until Monday"}, "Away until Monday", true},
+		{"case and whitespace", signaltypes.SignalMessage{Message: "  /AutoReply   Back at 9 \n"}, "Back at 9", true},
+		{"off", signaltypes.SignalMessage{Message: "/autoreply off"}, "off", true},
+		{"no argument", signaltypes.SignalMessage{Message: "/autoreply"}, "", true},
+		{"regular message", signaltypes.SignalMessage{Message: "hello"}, "", false},
+		{"command inside text", signaltypes.SignalMessage{Message: "set /autoreply later"}, "", false},
+		{"longer command", signaltypes.SignalMessage{Message: "/autoreplyoff"}, "", false},
+		{"with attachment", signaltypes.SignalMessage{Message: "/autoreply away", Attachments: []string{"a.jpg"}}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			argument, ok := parseAutoReplyCommand(&tt.msg)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedArgument, argument)
+		})
+	}
+}
+
+func TestAutoReplyFiresOnceWithinCooldown(t *testing.T) {
+	const chat = "111@c.us"
+	bridge, _, cleanup := setupPauseTestBridge(t, chat)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+
+	var autoReplies []string
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		autoReplies = append(autoReplies, chatID+": "+text)
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+
+	err := bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/autoreply Away until Monday"})
+	require.NoError(t, err)
+	assert.Empty(t, autoReplies, "the command itself is not relayed")
+	assert.Equal(t, "Auto-reply set for 111, sent at most once every 60 minutes: Away until Monday", sigClient.lastMessage)
+
+	err = bridge.HandleWhatsAppMessageWithSession(ctx, "default", chat, "wa1", chat, "Alice", "are you there?", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice: are you there?", sigClient.lastMessage, "messages are still relayed to Signal")
+
+	err = bridge.HandleWhatsAppMessageWithSession(ctx, "default", chat, "wa2", chat, "Alice", "hello?", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice: hello?", sigClient.lastMessage)
+
+	err = bridge.HandleWhatsAppMessageWithSession(ctx, "default", "222@c.us", "wa3", "222@c.us", "Bob", "hi", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"111@c.us: Away until Monday"}, autoReplies, "only one auto-reply within the cooldown, and only to the configured chat")
+}
+
+func TestAutoReplyCooldownFollowsClock(t *testing.T) {
+	const chat = "111@c.us"
+	bridge, _, cleanup := setupPauseTestBridge(t, chat)
+	defer cleanup()
+	clock := testutil.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	bridge.clock = clock
+
+	ctx := context.Background()
+	var autoReplies int
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		autoReplies++
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+
+	require.NoError(t, bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/autoreply Away until Monday"}))
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", chat, "wa1", chat, "Alice", "are you there?", ""))
+	assert.Equal(t, 1, autoReplies)
+
+	clock.Advance(59 * time.Minute)
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", chat, "wa2", chat, "Alice", "hello?", ""))
+	assert.Equal(t, 1, autoReplies, "still within the cooldown")
+
+	clock.Advance(time.Minute)
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", chat, "wa3", chat, "Alice", "anyone?", ""))
+	assert.Equal(t, 2, autoReplies, "the cooldown has passed on the bridge clock")
+}
+
+func TestAutoReplyOffStopsReplies(t *testing.T) {
+	const chat = "111@c.us"
+	bridge, whatsAppSends, cleanup := setupPauseTestBridge(t, chat)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	require.NoError(t, bridge.db.SetChatAutoReply(ctx, "default", chat, "Away"))
+
+	err := bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/autoreply off"})
+	require.NoError(t, err)
+	assert.Equal(t, "Auto-reply turned off for 111.", sigClient.lastMessage)
+
+	err = bridge.HandleWhatsAppMessageWithSession(ctx, "default", chat, "wa1", chat, "Alice", "are you there?", "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, *whatsAppSends)
+	assert.Equal(t, "Alice: are you there?", sigClient.lastMessage)
+}
+
+func TestAutoReplyCommandWithoutMessageShowsUsage(t *testing.T) {
+	const chat = "111@c.us"
+	bridge, _, cleanup := setupPauseTestBridge(t, chat)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+
+	err := bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/autoreply"})
+	require.NoError(t, err)
+	assert.Equal(t, "Usage: /autoreply <message>, or /autoreply off to turn it off.", sigClient.lastMessage)
+
+	message, err := bridge.db.ClaimChatAutoReply(ctx, "default", chat, time.Now(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, message)
+}
+
+func TestAutoReplySendFailureStillRelays(t *testing.T) {
+	const chat = "111@c.us"
+	bridge, _, cleanup := setupPauseTestBridge(t, chat)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	require.NoError(t, bridge.db.SetChatAutoReply(ctx, "default", chat, "Away"))
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		return nil, assert.AnError
+	}
+
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", chat, "wa1", chat, "Alice", "are you there?", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice: are you there?", sigClient.lastMessage)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"whatsignal/internal/metrics"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Signal commands that control relaying for the chat a message resolves to
+const (
+	chatCommandPause  = "/pause"
+	chatCommandResume = "/resume"
+)
+
+// parseChatCommand returns the chat command contained in a Signal message, or "" if the
+// message is regular content. Commands must be the whole message and carry no attachments.
+func parseChatCommand(msg *signaltypes.SignalMessage) string {
+	if len(msg.Attachments) > 0 {
+		return ""
+	}
+	switch command := strings.ToLower(strings.TrimSpace(msg.Message)); command {
+	case chatCommandPause, chatCommandResume:
+		return command
+	default:
+		return ""
+	}
+}
+
+// signalCommand returns the bridge command a Signal message carries, or "" if it is regular
+// content to relay
+func signalCommand(msg *signaltypes.SignalMessage) string {
+	if _, ok := parseBroadcastCommand(msg); ok {
+		return chatCommandBroadcast
+	}
+	if isStatsCommand(msg) {
+		return chatCommandStats
+	}
+	if _, ok := parseWhoisCommand(msg); ok {
+		return chatCommandWhois
+	}
+	if _, ok := parseRetentionCommand(msg); ok {
+		return chatCommandRetention
+	}
+	if _, ok := parseFindCommand(msg); ok {
+		return chatCommandFind
+	}
+	if star, ok := parseStarCommand(msg); ok {
+		if star {
+			return chatCommandStar
+		}
+		return chatCommandUnstar
+	}
+	if _, ok := parseLinkGroupCommand(msg); ok {
+		return chatCommandLinkGroup
+	}
+	if _, ok := parseAutoReplyCommand(msg); ok {
+		return chatCommandAutoReply
+	}
+	return parseChatCommand(msg)
+}
+
+// isFromDestination reports whether a Signal command was sent by the channel's own Signal
+// number. Commands from any other number are logged and must be neither run nor relayed.
+func (b *bridge) isFromDestination(ctx context.Context, msg *signaltypes.SignalMessage, destination, command string) bool {
+	if msg.Sender == destination {
+		return true
+	}
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"command": command,
+		"sender":  SanitizePhoneNumber(msg.Sender),
+	}).Warn("Ignoring command from a number other than the channel's Signal destination")
+	return false
+}
+
+// handleChatCommand applies a /pause, /resume or /autoreply command to chatID and confirms it on Signal.
+// Only the channel's own Signal number may send them. It reports whether msg was a command, in
+// which case it must not be relayed.
+func (b *bridge) handleChatCommand(ctx context.Context, msg *signaltypes.SignalMessage, sessionName, chatID string) (bool, error) {
+	argument, isAutoReply := parseAutoReplyCommand(msg)
+	command := parseChatCommand(msg)
+	if !isAutoReply && command == "" {
+		return false, nil
+	}
+
+	if isAutoReply {
+		return true, b.handleAutoReplyCommand(ctx, sessionName, chatID, argument)
+	}
+
+	paused := command == chatCommandPause
+	if err := b.db.SetChatPaused(ctx, sessionName, chatID, paused); err != nil {
+		return true, fmt.Errorf("failed to update paused state for chat: %w", err)
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession: sessionName,
+		LogFieldChatID:  SanitizePhoneNumber(chatID),
+		"paused":        paused,
+	}).Info("Updated chat relaying state from Signal command")
+
+	chatName := b.chatDisplayName(ctx, sessionName, chatID)
+	confirmation := fmt.Sprintf("Relaying resumed for %s.", chatName)
+	if paused {
+		confirmation = fmt.Sprintf("Relaying paused for %s. Send %s to resume.", chatName, chatCommandResume)
+	}
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, confirmation); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to confirm chat command on Signal")
+	}
+
+	return true, nil
+}
+
+// isChatPaused reports whether relaying is paused for chatID. Lookup failures are logged
+// and treated as not paused so a settings problem never silently drops messages.
+func (b *bridge) isChatPaused(ctx context.Context, sessionName, chatID string) bool {
+	paused, err := b.db.IsChatPaused(ctx, sessionName, chatID)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithField(LogFieldSession, sessionName).Warn("Failed to check paused state for chat, relaying anyway")
+		return false
+	}
+	if paused {
+		metrics.IncrementCounter("messages_paused_total", map[string]string{
+			"session": sessionName,
+		}, "Messages not relayed because their chat is paused")
+	}
+	return paused
+}
+
+// chatDisplayName returns a human readable name for a WhatsApp chat
+func (b *bridge) chatDisplayName(ctx context.Context, sessionName, chatID string) string {
+	if strings.HasSuffix(chatID, "@g.us") {
+		if b.groupService != nil {
+			if name := b.groupService.GetGroupName(ctx, chatID, sessionName); name != "" {
+				return name
+			}
+		}
+		return chatID
+	}
+
+	phone := strings.TrimSuffix(strings.TrimSuffix(chatID, "@c.us"), "@lid")
+	if b.contactsEnabled() {
+		if name := b.contactService.GetContactDisplayName(ctx, phone); name != "" {
+			return name
+		}
+	}
+	return phone
+}
+
+// skipPausedSignalMessage reports whether a Signal message for chatID must be dropped because
+// the chat is paused, reminding the user on Signal how to resume it.
+func (b *bridge) skipPausedSignalMessage(ctx context.Context, msg *signaltypes.SignalMessage, sessionName, chatID string) bool {
+	if !b.isChatPaused(ctx, sessionName, chatID) {
+		return false
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession: sessionName,
+		LogFieldChatID:  SanitizePhoneNumber(chatID),
+		"signal_msg_id": SanitizeMessageID(msg.MessageID),
+	}).Info("Chat is paused, not relaying Signal message to WhatsApp")
+
+	notice := fmt.Sprintf("Relaying is paused for %s; message not sent. Send %s to resume.", b.chatDisplayName(ctx, sessionName, chatID), chatCommandResume)
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, notice); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to send paused chat notification")
+	}
+	return true
+}
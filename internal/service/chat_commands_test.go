@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChatCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      signaltypes.SignalMessage
+		expected string
+	}{
+		{"pause", signaltypes.SignalMessage{Message: "/pause"}, chatCommandPause},
+		{"resume", signaltypes.SignalMessage{Message: "/resume"}, chatCommandResume},
+		{"case and whitespace", signaltypes.SignalMessage{Message: "  /PAUSE\n"}, chatCommandPause},
+		{"regular message", signaltypes.SignalMessage{Message: "hello"}, ""},
+		{"command inside text", signaltypes.SignalMessage{Message: "please /pause this"}, ""},
+		{"unknown command", signaltypes.SignalMessage{Message: "/stop"}, ""},
+		{"with attachment", signaltypes.SignalMessage{Message: "/pause", Attachments: []string{"a.jpg"}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseChatCommand(&tt.msg))
+		})
+	}
+}
+
+func TestSignalCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      signaltypes.SignalMessage
+		expected string
+	}{
+		{"broadcast", signaltypes.SignalMessage{Message: "/broadcast hello all"}, chatCommandBroadcast},
+		{"stats", signaltypes.SignalMessage{Message: "/stats"}, chatCommandStats},
+		{"whois", signaltypes.SignalMessage{Message: "/whois +123"}, chatCommandWhois},
+		{"retention", signaltypes.SignalMessage{Message: "/retention 30"}, chatCommandRetention},
+		{"find", signaltypes.SignalMessage{Message: "/find lunch"}, chatCommandFind},
+		{"star", signaltypes.SignalMessage{Message: "/STAR"}, chatCommandStar},
+		{"unstar", signaltypes.SignalMessage{Message: "/unstar"}, chatCommandUnstar},
+		{"link group", signaltypes.SignalMessage{Message: "/linkgroup 123@g.us"}, chatCommandLinkGroup},
+		{"auto reply", signaltypes.SignalMessage{Message: "/autoreply away"}, chatCommandAutoReply},
+		{"pause", signaltypes.SignalMessage{Message: "/pause"}, chatCommandPause},
+		{"regular message", signaltypes.SignalMessage{Message: "hello"}, ""},
+		{"unknown command", signaltypes.SignalMessage{Message: "/stop"}, ""},
+		{"with attachment", signaltypes.SignalMessage{Message: "/stats", Attachments: []string{"a.jpg"}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, signalCommand(&tt.msg))
+		})
+	}
+}
+
+// setupPauseTestBridge returns a bridge whose Signal replies route to pausedChat and which
+// counts messages sent to WhatsApp
+func setupPauseTestBridge(t *testing.T, pausedChat string) (*bridge, *int, func()) {
+	bridge, _, cleanup := setupTestBridge(t)
+
+	mockDB := bridge.db.(*mockDatabaseService)
+	mockDB.On("GetLatestMessageMappingBySession", mock.Anything, "default").Return(&models.MessageMapping{
+		WhatsAppChatID: pausedChat,
+		WhatsAppMsgID:  "wa_latest",
+		SignalMsgID:    "sig_latest",
+		SessionName:    "default",
+	}, nil)
+	mockDB.On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+
+	bridge.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{
+		MessageID: "sig_sent",
+		Timestamp: time.Now().UnixMilli(),
+	}
+
+	whatsAppSends := 0
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		whatsAppSends++
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+
+	return bridge, &whatsAppSends, cleanup
+}
+
+func TestPauseCommandStopsRelayingForChat(t *testing.T) {
+	const pausedChat = "111@c.us"
+	bridge, whatsAppSends, cleanup := setupPauseTestBridge(t, pausedChat)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+
+	err := bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/pause"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, *whatsAppSends, "the command itself is not relayed")
+	assert.Contains(t, sigClient.lastMessage, "Relaying paused")
+
+	paused, err := bridge.db.IsChatPaused(ctx, "default", pausedChat)
+	require.NoError(t, err)
+	assert.True(t, paused)
+
+	// Signal replies to the paused chat are dropped
+	err = bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "sig1", Sender: "+1234567890", Message: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, *whatsAppSends)
+	assert.Contains(t, sigClient.lastMessage, "Relaying is paused")
+
+	// WhatsApp messages from the paused chat are stored but not relayed
+	sigClient.lastMessage = ""
+	err = bridge.HandleWhatsAppMessageWithSession(ctx, "default", pausedChat, "wa1", pausedChat, "Alice", "hi from whatsapp", "")
+	require.NoError(t, err)
+	assert.Empty(t, sigClient.lastMessage)
+	bridge.db.(*mockDatabaseService).AssertCalled(t, "SaveMessageMapping", mock.Anything, mock.MatchedBy(func(m *models.MessageMapping) bool {
+		return m.WhatsAppMsgID == "wa1" && strings.HasPrefix(m.SignalMsgID, "paused:")
+	}))
+
+	// Other chats keep relaying
+	err = bridge.HandleWhatsAppMessageWithSession(ctx, "default", "222@c.us", "wa2", "222@c.us", "Bob", "hi from another chat", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Bob: hi from another chat", sigClient.lastMessage)
+}
+
+func TestResumeCommandRestoresRelaying(t *testing.T) {
+	const pausedChat = "111@c.us"
+	bridge, whatsAppSends, cleanup := setupPauseTestBridge(t, pausedChat)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	require.NoError(t, bridge.db.SetChatPaused(ctx, "default", pausedChat, true))
+
+	err := bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/resume"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, *whatsAppSends, "the command itself is not relayed")
+	assert.Contains(t, sigClient.lastMessage, "Relaying resumed")
+
+	err = bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "sig1", Sender: "+1234567890", Message: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, *whatsAppSends)
+
+	err = bridge.HandleWhatsAppMessageWithSession(ctx, "default", pausedChat, "wa1", pausedChat, "Alice", "hi again", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice: hi again", sigClient.lastMessage)
+}
+
+func TestChatCommandsIgnoreOtherSenders(t *testing.T) {
+	const pausedChat = "111@c.us"
+	bridge, whatsAppSends, cleanup := setupPauseTestBridge(t, pausedChat)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	mockDB := bridge.db.(*mockDatabaseService)
+
+	for _, command := range []string{"/pause", "/autoreply Out of office"} {
+		err := bridge.HandleSignalMessage(ctx, &signaltypes.SignalMessage{MessageID: "cmd-" + command, Sender: "+15550000001", Message: command})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 0, *whatsAppSends, "commands are not relayed")
+	assert.Empty(t, sigClient.lastMessage, "other senders get no confirmation")
+	paused, err := mockDB.IsChatPaused(ctx, "default", pausedChat)
+	require.NoError(t, err)
+	assert.False(t, paused)
+	mockDB.pausedChatsMu.Lock()
+	assert.Empty(t, mockDB.autoReplies)
+	mockDB.pausedChatsMu.Unlock()
+}
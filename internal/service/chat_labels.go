@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chatLabelCache remembers the WhatsApp Business label names of each chat for a fixed TTL, so
+// a busy chat does not cost a WAHA call per message. Failed lookups are cached as "no labels"
+// too, which keeps accounts without labels from retrying on every message.
+type chatLabelCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]chatLabelEntry
+	clock   Clock
+}
+
+type chatLabelEntry struct {
+	names     []string
+	fetchedAt time.Time
+}
+
+func newChatLabelCache(ttl time.Duration, clock Clock) *chatLabelCache {
+	return &chatLabelCache{
+		ttl:     ttl,
+		entries: make(map[string]chatLabelEntry),
+		clock:   clock,
+	}
+}
+
+// get returns the cached label names for key and whether they are still fresh
+func (c *chatLabelCache) get(key string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || c.clock.Now().Sub(entry.fetchedAt) >= c.ttl {
+		return nil, false
+	}
+	return entry.names, true
+}
+
+// put stores the label names for key, dropping expired entries to stay bounded
+func (c *chatLabelCache) put(key string, names []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	for k, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) >= c.ttl {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = chatLabelEntry{names: names, fetchedAt: now}
+}
+
+// chatLabelPrefix returns the chat's labels formatted as "[Lead][VIP] " for prefixing a message
+// relayed to Signal, or "" when the prefix is disabled or the chat has no labels. Lookup errors,
+// such as those from accounts that are not WhatsApp Business, leave the message unprefixed.
+func (b *bridge) chatLabelPrefix(ctx context.Context, sessionName, chatID string) string {
+	if b.chatLabels == nil {
+		return ""
+	}
+
+	key := sessionName + "|" + chatID
+	names, ok := b.chatLabels.get(key)
+	if !ok {
+		labels, err := b.waClient.GetChatLabels(ctx, chatID)
+		if err != nil {
+			b.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+				LogFieldSession: sessionName,
+				LogFieldChatID:  SanitizePhoneNumber(chatID),
+			}).Debug("Chat labels unavailable, relaying without them")
+		}
+		names = make([]string, 0, len(labels))
+		for _, label := range labels {
+			if name := strings.TrimSpace(label.Name); name != "" {
+				names = append(names, name)
+			}
+		}
+		b.chatLabels.put(key, names)
+	}
+
+	if len(names) == 0 {
+		return ""
+	}
+	var prefix strings.Builder
+	for _, name := range names {
+		prefix.WriteString("[" + name + "]")
+	}
+	prefix.WriteString(" ")
+	return prefix.String()
+}
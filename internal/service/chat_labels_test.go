@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/internal/testutil"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWhatsAppMessage_ChatLabelsPrefix(t *testing.T) {
+	tests := []struct {
+		name            string
+		labels          []types.Label
+		labelsErr       error
+		expectedMessage string
+	}{
+		{
+			name:            "labels present",
+			labels:          []types.Label{{ID: "1", Name: "Lead"}, {ID: "5", Name: "VIP"}},
+			expectedMessage: "[Lead][VIP] sender123: hello",
+		},
+		{
+			name:            "no labels",
+			labels:          []types.Label{},
+			expectedMessage: "sender123: hello",
+		},
+		{
+			name:            "not a business account",
+			labelsErr:       errors.New("request failed with status 400"),
+			expectedMessage: "sender123: hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			bridge.chatLabels = newChatLabelCache(time.Minute, RealClock)
+			waClient := bridge.waClient.(*mockWhatsAppClient)
+			waClient.On("GetChatLabels", mock.Anything, "chat123@c.us").Return(tt.labels, tt.labelsErr).Once()
+			sigClient := bridge.sigClient.(*mockSignalClient)
+			sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{
+				MessageID: "sig-labels",
+				Timestamp: time.Now().UnixMilli(),
+			}
+
+			// The second message is served from the cache, including a failed lookup
+			for _, msgID := range []string{"msg-labels-1", "msg-labels-2"} {
+				err := bridge.HandleWhatsAppMessageWithSession(context.Background(), "default", "chat123@c.us", msgID, "sender123", "", "hello", "")
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedMessage, sigClient.lastMessage)
+			}
+			waClient.AssertNumberOfCalls(t, "GetChatLabels", 1)
+		})
+	}
+}
+
+func TestHandleWhatsAppMessage_ChatLabelsDisabled(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	waClient := bridge.waClient.(*mockWhatsAppClient)
+	waClient.On("GetChatLabels", mock.Anything, mock.Anything).Return([]types.Label{{Name: "Lead"}}, nil)
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-nolabels", Timestamp: time.Now().UnixMilli()}
+
+	err := bridge.HandleWhatsAppMessageWithSession(context.Background(), "default", "chat123@c.us", "msg-nolabels", "sender123", "", "hello", "")
+	require.NoError(t, err)
+	assert.Equal(t, "sender123: hello", sigClient.lastMessage)
+	waClient.AssertNotCalled(t, "GetChatLabels", mock.Anything, mock.Anything)
+}
+
+func TestChatLabelCache_Expires(t *testing.T) {
+	clock := testutil.NewFakeClock(time.Now())
+	cache := newChatLabelCache(time.Minute, clock)
+
+	cache.put("default|chat123@c.us", []string{"Lead"})
+	names, ok := cache.get("default|chat123@c.us")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Lead"}, names)
+
+	clock.Advance(time.Minute)
+	_, ok = cache.get("default|chat123@c.us")
+	assert.False(t, ok)
+
+	// Expired entries are dropped on the next write
+	cache.put("default|other@c.us", nil)
+	assert.Len(t, cache.entries, 1)
+}
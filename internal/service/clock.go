@@ -0,0 +1,18 @@
+package service
+
+import "time"
+
+// Clock tells the current time. Components that expire or compare times take one instead of
+// calling time.Now, so tests can control the time they see.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the Clock of the system time, used unless a test sets another
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"whatsignal/internal/models"
+	"whatsignal/internal/validation"
+)
+
+// ContactImportResult counts the rows of a contact CSV import
+type ContactImportResult struct {
+	Imported  int // Contacts saved
+	Malformed int // Rows skipped for a bad number, a missing name or a wrong column count
+}
+
+// ImportContactsCSV reads "number,name" rows from r and saves each as a cached contact, so
+// relays show names before the first WAHA contact sync. A header row is skipped. Numbers must
+// be E.164 with or without the leading +. An existing contact keeps its WhatsApp push and short
+// names and only takes the imported name. Malformed rows are counted and skipped; a database
+// error stops the import.
+func ImportContactsCSV(ctx context.Context, r io.Reader, db ContactDatabaseService) (ContactImportResult, error) {
+	var result ContactImportResult
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Malformed++
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read contacts CSV: %w", err)
+		}
+
+		if row == 1 && isContactCSVHeader(record) {
+			continue
+		}
+		if len(record) != 2 {
+			result.Malformed++
+			continue
+		}
+
+		number := strings.TrimPrefix(strings.TrimSpace(record[0]), "+")
+		name := strings.TrimSpace(record[1])
+		if name == "" || validation.ValidateE164PhoneNumber(number) != nil {
+			result.Malformed++
+			continue
+		}
+
+		if err := importContact(ctx, db, number, name); err != nil {
+			return result, fmt.Errorf("failed to import contact on row %d: %w", row, err)
+		}
+		result.Imported++
+	}
+}
+
+// importContact saves name for the contact with the given number, keeping the other fields of
+// an already cached contact
+func importContact(ctx context.Context, db ContactDatabaseService, number, name string) error {
+	contact, err := db.GetContactByPhone(ctx, number)
+	if err != nil {
+		return err
+	}
+	if contact == nil {
+		contact = &models.Contact{
+			ContactID:   number + "@c.us",
+			PhoneNumber: number,
+		}
+	}
+	contact.Name = name
+	return db.SaveContact(ctx, contact)
+}
+
+// isContactCSVHeader reports whether record is a "number,name" header row
+func isContactCSVHeader(record []string) bool {
+	return len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "number")
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"whatsignal/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryContactDB is a ContactDatabaseService keeping contacts by phone number
+type memoryContactDB struct {
+	contacts map[string]models.Contact
+}
+
+func (m *memoryContactDB) SaveContact(ctx context.Context, contact *models.Contact) error {
+	m.contacts[contact.PhoneNumber] = *contact
+	return nil
+}
+
+func (m *memoryContactDB) GetContact(ctx context.Context, contactID string) (*models.Contact, error) {
+	return m.GetContactByPhone(ctx, strings.TrimSuffix(contactID, "@c.us"))
+}
+
+func (m *memoryContactDB) GetContactByPhone(ctx context.Context, phoneNumber string) (*models.Contact, error) {
+	contact, ok := m.contacts[phoneNumber]
+	if !ok {
+		return nil, nil
+	}
+	return &contact, nil
+}
+
+func (m *memoryContactDB) CleanupOldContacts(ctx context.Context, retentionDays int) error {
+	return nil
+}
+
+func TestImportContactsCSV(t *testing.T) {
+	db := &memoryContactDB{contacts: map[string]models.Contact{
+		"15550000003": {ContactID: "15550000003@c.us", PhoneNumber: "15550000003", Name: "Old Name", PushName: "Carol"},
+	}}
+
+	csvData := `number,name
++15550000001,Alice
+15550000002, Bob Smith
++15550000003,"Carol, from work"
+# comment lines are ignored
+not-a-number,Mallory
++1555,Too Short
++15550000004,
++15550000005
++15550000006,Eve,extra
++15550000007,"unterminated
+`
+
+	result, err := ImportContactsCSV(context.Background(), strings.NewReader(csvData), db)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, 6, result.Malformed)
+	require.Len(t, db.contacts, 3)
+
+	assert.Equal(t, models.Contact{ContactID: "15550000001@c.us", PhoneNumber: "15550000001", Name: "Alice"}, db.contacts["15550000001"])
+	assert.Equal(t, "Bob Smith", db.contacts["15550000002"].Name)
+
+	// An existing contact takes the imported name and keeps its push name
+	assert.Equal(t, "Carol, from work", db.contacts["15550000003"].Name)
+	assert.Equal(t, "Carol", db.contacts["15550000003"].PushName)
+}
+
+func TestImportContactsCSV_WithoutHeader(t *testing.T) {
+	db := &memoryContactDB{contacts: map[string]models.Contact{}}
+
+	result, err := ImportContactsCSV(context.Background(), strings.NewReader("+15550000001,Alice\n"), db)
+	require.NoError(t, err)
+	assert.Equal(t, ContactImportResult{Imported: 1}, result)
+	assert.Equal(t, "Alice", db.contacts["15550000001"].Name)
+}
+
+func TestImportContactsCSV_DatabaseErrorStops(t *testing.T) {
+	db := &mockContactDatabaseService{}
+	db.On("GetContactByPhone", mock.Anything, "15550000001").Return(nil, nil).Once()
+	db.On("SaveContact", mock.Anything, mock.Anything).Return(errors.New("disk full")).Once()
+
+	result, err := ImportContactsCSV(context.Background(), strings.NewReader("+15550000001,Alice\n+15550000002,Bob\n"), db)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 1")
+	assert.Equal(t, 0, result.Imported)
+	db.AssertExpectations(t)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+// contactRefresher refreshes contacts from WAHA in the background, so a relay naming a sender
+// from a missing or stale cache entry is not held up by the API. It runs one refresh per
+// number at a time and at most perMinute refreshes a minute; numbers over the limit are
+// skipped and scheduled again by a later relay.
+type contactRefresher struct {
+	mu       sync.Mutex
+	contacts ContactServiceInterface
+	interval time.Duration
+	last     time.Time
+	inFlight map[string]bool
+	wg       sync.WaitGroup
+	logger   *logrus.Logger
+}
+
+func newContactRefresher(contacts ContactServiceInterface, perMinute int, logger *logrus.Logger) *contactRefresher {
+	if perMinute <= 0 {
+		perMinute = constants.DefaultContactRefreshPerMinute
+	}
+	return &contactRefresher{
+		contacts: contacts,
+		interval: time.Minute / time.Duration(perMinute),
+		inFlight: make(map[string]bool),
+		logger:   logger,
+	}
+}
+
+// schedule starts a background refresh of phoneNumber and reports whether it did
+func (r *contactRefresher) schedule(ctx context.Context, phoneNumber string) bool {
+	r.mu.Lock()
+	now := time.Now()
+	if r.inFlight[phoneNumber] || now.Sub(r.last) < r.interval {
+		r.mu.Unlock()
+		return false
+	}
+	r.inFlight[phoneNumber] = true
+	r.last = now
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() {
+			r.mu.Lock()
+			delete(r.inFlight, phoneNumber)
+			r.mu.Unlock()
+		}()
+
+		// The relay that scheduled the refresh may finish first
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Duration(constants.ContactRefreshTimeoutSec)*time.Second)
+		defer cancel()
+
+		result := "success"
+		if err := r.contacts.RefreshContact(refreshCtx, phoneNumber); err != nil {
+			result = "error"
+			r.logger.WithContext(ctx).WithError(err).WithField("phone", SanitizePhoneNumber(phoneNumber)).Debug("Background contact refresh failed")
+		}
+		metrics.IncrementCounter("contact_background_refreshes_total", map[string]string{
+			"result": result,
+		}, "Contacts refreshed from WAHA in the background while relaying")
+	}()
+	return true
+}
+
+// wait blocks until running refreshes finish
+func (r *contactRefresher) wait() {
+	r.wg.Wait()
+}
+
+// contactsEnabled reports whether the bridge has a contact service to resolve names with. Without
+// one, names fall back to the bare number.
+func (b *bridge) contactsEnabled() bool {
+	return b.contactService != nil && b.contactService.Enabled()
+}
+
+// senderDisplayName names the sender of a relayed message through the contact service, or by
+// the bare number when it is disabled. With whatsapp.contactRefreshOnRelay set, only the cache
+// is read and a missing or stale contact is refreshed in the background for later relays.
+func (b *bridge) senderDisplayName(ctx context.Context, senderPhone string) string {
+	if !b.contactsEnabled() {
+		return senderPhone
+	}
+	if b.contactRefresh == nil {
+		return b.contactService.GetContactDisplayName(ctx, senderPhone)
+	}
+	name, fresh := b.contactService.CachedContactDisplayName(ctx, senderPhone)
+	if !fresh {
+		b.contactRefresh.schedule(ctx, senderPhone)
+	}
+	return name
+}
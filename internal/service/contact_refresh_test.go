@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// cachingContactDB is a memoryContactDB safe for background refreshes that stamps saved
+// contacts as freshly cached, as the database does
+type cachingContactDB struct {
+	mu sync.Mutex
+	memoryContactDB
+}
+
+func (c *cachingContactDB) SaveContact(ctx context.Context, contact *models.Contact) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	saved := *contact
+	saved.CachedAt = time.Now()
+	return c.memoryContactDB.SaveContact(ctx, &saved)
+}
+
+func (c *cachingContactDB) GetContactByPhone(ctx context.Context, phoneNumber string) (*models.Contact, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.memoryContactDB.GetContactByPhone(ctx, phoneNumber)
+}
+
+func (c *cachingContactDB) GetContact(ctx context.Context, contactID string) (*models.Contact, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.memoryContactDB.GetContact(ctx, contactID)
+}
+
+func TestHandleWhatsAppMessage_UnknownSenderRefreshesContact(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	contactDB := &cachingContactDB{memoryContactDB: memoryContactDB{contacts: map[string]models.Contact{}}}
+	waContacts := &mockWAClient{}
+	waContacts.On("GetContact", mock.Anything, "15550001111@c.us").Return(&types.Contact{
+		ID:     "15550001111@c.us",
+		Number: "15550001111",
+		Name:   "Alice",
+	}, nil).Once()
+	contacts := NewContactServiceWithConfigAndLogger(contactDB, waContacts, 24, bridge.logger)
+	bridge.contactService = contacts
+	bridge.contactRefresh = newContactRefresher(contacts, 60, bridge.logger)
+
+	bridge.db.(*mockDatabaseService).On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-1", Timestamp: time.Now().UnixMilli()}
+
+	ctx := context.Background()
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "15550001111@c.us", "msg-1", "15550001111@c.us", "", "hi", "")
+	require.NoError(t, err)
+	assert.Equal(t, "15550001111: hi", sigClient.lastMessage, "the relay does not wait for the contact")
+
+	bridge.contactRefresh.wait()
+	cached, err := contactDB.GetContactByPhone(ctx, "15550001111")
+	require.NoError(t, err)
+	require.NotNil(t, cached, "the refresh updates the cache")
+	assert.Equal(t, "Alice", cached.Name)
+
+	err = bridge.HandleWhatsAppMessageWithSession(ctx, "default", "15550001111@c.us", "msg-2", "15550001111@c.us", "", "again", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice: again", sigClient.lastMessage)
+	bridge.contactRefresh.wait()
+	waContacts.AssertExpectations(t)
+}
+
+func TestContactRefresher_RateLimitsRefreshes(t *testing.T) {
+	contacts := &mockContactService{}
+	release := make(chan struct{})
+	contacts.On("RefreshContact", mock.Anything, "15550001111").Run(func(mock.Arguments) { <-release }).Return(nil).Once()
+
+	refresher := newContactRefresher(contacts, 1, logrus.New())
+	ctx := context.Background()
+	assert.True(t, refresher.schedule(ctx, "15550001111"))
+	assert.False(t, refresher.schedule(ctx, "15550001111"), "a refresh of the same number is already running")
+	assert.False(t, refresher.schedule(ctx, "15550002222"), "one refresh a minute is allowed")
+
+	close(release)
+	refresher.wait()
+	contacts.AssertExpectations(t)
+}
+
+func TestContactRefresher_FailureIsNotFatal(t *testing.T) {
+	contacts := &mockContactService{}
+	contacts.On("RefreshContact", mock.Anything, "15550001111").Return(errors.New("WAHA unavailable")).Once()
+	contacts.On("RefreshContact", mock.Anything, "15550001111").Return(nil).Once()
+
+	refresher := newContactRefresher(contacts, 6000, logrus.New())
+	require.True(t, refresher.schedule(context.Background(), "15550001111"))
+	refresher.wait()
+
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, refresher.schedule(context.Background(), "15550001111"), "a failed refresh can be retried")
+	refresher.wait()
+	contacts.AssertExpectations(t)
+}
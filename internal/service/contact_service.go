@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -18,9 +19,12 @@ import (
 
 // ContactServiceInterface defines the interface for contact operations
 type ContactServiceInterface interface {
+	Enabled() bool
 	GetContactDisplayName(ctx context.Context, phoneNumber string) string
+	CachedContactDisplayName(ctx context.Context, phoneNumber string) (string, bool)
 	RefreshContact(ctx context.Context, phoneNumber string) error
 	SyncAllContacts(ctx context.Context) error
+	SyncSignalContact(ctx context.Context, number string) error
 	CleanupOldContacts(ctx context.Context, retentionDays int) error
 }
 
@@ -36,10 +40,13 @@ type ContactDatabaseService interface {
 type ContactService struct {
 	db              ContactDatabaseService
 	waClient        types.WAClient
+	sigProfiles     SignalProfileClient // Looks up Signal profile names; nil leaves Signal senders unnamed
 	cacheValidHours int
 	logger          *errors.Logger
 	circuitBreaker  *CircuitBreaker
 	degradedMode    atomic.Bool
+	// lookups coalesces concurrent API fetches of the same contact
+	lookups contactLookups
 }
 
 // NewContactService creates a new contact service instance
@@ -73,6 +80,12 @@ func NewContactServiceWithConfigAndLogger(db ContactDatabaseService, waClient ty
 	}
 }
 
+// Enabled reports whether the service can look up contacts. A nil service, or one built
+// without a database or WhatsApp client, is disabled.
+func (cs *ContactService) Enabled() bool {
+	return cs != nil && cs.db != nil && cs.waClient != nil
+}
+
 // GetContactDisplayName retrieves the display name for a phone number/contact ID
 // It first checks the cache, then fetches from WhatsApp API if needed
 // For group chats, it returns the phone number directly without API calls
@@ -126,12 +139,10 @@ func (cs *ContactService) GetContactDisplayName(ctx context.Context, phoneNumber
 		contactID = phoneNumber + "@c.us"
 	}
 
-	// Try to fetch from WhatsApp API with circuit breaker protection
-	var waContact *types.Contact
-	err = cs.circuitBreaker.Execute(ctx, func(ctx context.Context) error {
-		var apiErr error
-		waContact, apiErr = cs.waClient.GetContact(ctx, contactID)
-		return apiErr
+	// Fetch from WhatsApp API with circuit breaker protection, sharing the result with any
+	// concurrent lookup of the same contact
+	waContact, err := cs.lookups.do(ctx, contactID, func() (*types.Contact, error) {
+		return cs.fetchAndCacheContact(ctx, contactID, phoneNumber)
 	})
 
 	if err != nil {
@@ -166,6 +177,22 @@ func (cs *ContactService) GetContactDisplayName(ctx context.Context, phoneNumber
 		return phoneNumber
 	}
 
+	return waContact.GetDisplayName()
+}
+
+// fetchAndCacheContact fetches a contact from the WhatsApp API through the circuit breaker and
+// saves it to the cache. A contact WhatsApp does not know is returned as nil.
+func (cs *ContactService) fetchAndCacheContact(ctx context.Context, contactID, phoneNumber string) (*types.Contact, error) {
+	var waContact *types.Contact
+	err := cs.circuitBreaker.Execute(ctx, func(ctx context.Context) error {
+		var apiErr error
+		waContact, apiErr = cs.waClient.GetContact(ctx, contactID)
+		return apiErr
+	})
+	if err != nil || waContact == nil {
+		return nil, err
+	}
+
 	// Save/update in cache
 	dbContact := &models.Contact{}
 	dbContact.FromWAContact(waContact)
@@ -180,8 +207,88 @@ func (cs *ContactService) GetContactDisplayName(ctx context.Context, phoneNumber
 		// Record successful cache refresh
 		metrics.IncrementCounter("contact_cache_refreshes_total", nil, "Total contact cache refreshes")
 	}
+	return waContact, nil
+}
 
-	return waContact.GetDisplayName()
+// contactLookup is one running fetch of a contact that concurrent lookups wait on
+type contactLookup struct {
+	done    chan struct{}
+	contact *types.Contact
+	err     error
+}
+
+// contactLookups coalesces concurrent fetches of the same contact, so a burst of messages from
+// an uncached contact makes one WhatsApp API call instead of one per message. The zero value is
+// ready to use.
+type contactLookups struct {
+	mu      sync.Mutex
+	running map[string]*contactLookup
+}
+
+// do calls fetch for contactID, or waits for the call already running for it and returns that
+// call's result. Waiting stops early when ctx is done. The fetch runs with the context of the
+// lookup that started it.
+func (g *contactLookups) do(ctx context.Context, contactID string, fetch func() (*types.Contact, error)) (*types.Contact, error) {
+	g.mu.Lock()
+	if call, ok := g.running[contactID]; ok {
+		g.mu.Unlock()
+		metrics.IncrementCounter("contact_lookups_coalesced_total", nil, "Total contact lookups served by a concurrent fetch")
+		select {
+		case <-call.done:
+			return call.contact, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if g.running == nil {
+		g.running = make(map[string]*contactLookup)
+	}
+	call := &contactLookup{done: make(chan struct{})}
+	g.running[contactID] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.running, contactID)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+	call.contact, call.err = fetch()
+	return call.contact, call.err
+}
+
+// CachedContactDisplayName returns the display name for a phone number from the cache alone,
+// without calling the WhatsApp API, and whether the cached contact is fresh. A number that is
+// not cached returns the number itself and false. Groups and LIDs are never looked up and
+// count as fresh.
+func (cs *ContactService) CachedContactDisplayName(ctx context.Context, phoneNumber string) (string, bool) {
+	if strings.Contains(phoneNumber, "@g.us") {
+		return phoneNumber, true
+	}
+	if strings.HasSuffix(phoneNumber, "@lid") {
+		return strings.TrimSuffix(phoneNumber, "@lid"), true
+	}
+
+	contact, err := cs.db.GetContactByPhone(ctx, phoneNumber)
+	if err != nil {
+		cs.logger.LogWarn(
+			errors.Wrap(err, errors.ErrCodeDatabaseQuery, "failed to retrieve contact from cache"),
+			"Contact cache lookup failed",
+			logrus.Fields{"phone_number": phoneNumber},
+		)
+	}
+	if contact == nil {
+		metrics.IncrementCounter("contact_cache_misses_total", nil, "Total contact cache misses")
+		return phoneNumber, false
+	}
+
+	fresh := time.Since(contact.CachedAt) < time.Duration(cs.cacheValidHours)*time.Hour
+	if fresh {
+		metrics.IncrementCounter("contact_cache_hits_total", nil, "Total contact cache hits")
+	} else {
+		metrics.IncrementCounter("contact_cache_misses_total", nil, "Total contact cache misses")
+	}
+	return contact.GetDisplayName(), fresh
 }
 
 // RefreshContact forces a refresh of a specific contact from WhatsApp API
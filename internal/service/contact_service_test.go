@@ -70,6 +70,14 @@ func (m *mockWAClient) GetGroup(ctx context.Context, groupID string) (*types.Gro
 	return args.Get(0).(*types.Group), args.Error(1)
 }
 
+func (m *mockWAClient) GetGroupWithSession(ctx context.Context, groupID, sessionName string) (*types.Group, error) {
+	args := m.Called(ctx, groupID, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Group), args.Error(1)
+}
+
 func (m *mockWAClient) GetAllGroups(ctx context.Context, limit, offset int) ([]types.Group, error) {
 	args := m.Called(ctx, limit, offset)
 	if args.Get(0) == nil {
@@ -78,11 +86,40 @@ func (m *mockWAClient) GetAllGroups(ctx context.Context, limit, offset int) ([]t
 	return args.Get(0).([]types.Group), args.Error(1)
 }
 
+func (m *mockWAClient) GetChatLabels(ctx context.Context, chatID string) ([]types.Label, error) {
+	args := m.Called(ctx, chatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.Label), args.Error(1)
+}
+
+func (m *mockWAClient) GetChatMessages(ctx context.Context, chatID string, limit int, sessionName string) ([]types.ChatMessage, error) {
+	args := m.Called(ctx, chatID, limit, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.ChatMessage), args.Error(1)
+}
+
+func (m *mockWAClient) GetMe(ctx context.Context, sessionName string) (*types.Me, error) {
+	args := m.Called(ctx, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Me), args.Error(1)
+}
+
 func (m *mockWAClient) SendTextWithSession(ctx context.Context, chatID, message, replyTo, sessionName string) (*types.SendMessageResponse, error) {
 	args := m.Called(ctx, chatID, message, replyTo, sessionName)
 	return args.Get(0).(*types.SendMessageResponse), args.Error(1)
 }
 
+func (m *mockWAClient) SendMentionsWithSession(ctx context.Context, chatID, message string, mentions []string, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	args := m.Called(ctx, chatID, message, mentions, replyTo, sessionName)
+	return args.Get(0).(*types.SendMessageResponse), args.Error(1)
+}
+
 func (m *mockWAClient) SendImageWithSession(ctx context.Context, chatID, imagePath, caption, replyTo, sessionName string) (*types.SendMessageResponse, error) {
 	args := m.Called(ctx, chatID, imagePath, caption, replyTo, sessionName)
 	return args.Get(0).(*types.SendMessageResponse), args.Error(1)
@@ -103,8 +140,8 @@ func (m *mockWAClient) SendVoiceWithSession(ctx context.Context, chatID, voicePa
 	return args.Get(0).(*types.SendMessageResponse), args.Error(1)
 }
 
-func (m *mockWAClient) CreateSession(ctx context.Context) error {
-	args := m.Called(ctx)
+func (m *mockWAClient) CreateSession(ctx context.Context, config *types.SessionConfig) error {
+	args := m.Called(ctx, config)
 	return args.Error(0)
 }
 
@@ -164,6 +201,16 @@ func (m *mockWAClient) DeleteMessage(ctx context.Context, chatID, messageID stri
 	return args.Error(0)
 }
 
+func (m *mockWAClient) EditMessageWithSession(ctx context.Context, chatID, messageID, newText, sessionName string) error {
+	args := m.Called(ctx, chatID, messageID, newText, sessionName)
+	return args.Error(0)
+}
+
+func (m *mockWAClient) StarMessageWithSession(ctx context.Context, chatID, messageID string, star bool, sessionName string) error {
+	args := m.Called(ctx, chatID, messageID, star, sessionName)
+	return args.Error(0)
+}
+
 func (m *mockWAClient) GetSessionName() string {
 	args := m.Called()
 	return args.String(0)
@@ -174,11 +221,25 @@ func (m *mockWAClient) HealthCheck(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *mockWAClient) GetCapabilities(ctx context.Context) types.Capabilities {
+	return types.Capabilities{}
+}
+
 func (m *mockWAClient) AckMessage(ctx context.Context, chatID, sessionName string) error {
 	args := m.Called(ctx, chatID, sessionName)
 	return args.Error(0)
 }
 
+func (m *mockWAClient) StartTyping(ctx context.Context, chatID, sessionName string) error {
+	args := m.Called(ctx, chatID, sessionName)
+	return args.Error(0)
+}
+
+func (m *mockWAClient) StopTyping(ctx context.Context, chatID, sessionName string) error {
+	args := m.Called(ctx, chatID, sessionName)
+	return args.Error(0)
+}
+
 func TestNewContactService(t *testing.T) {
 	mockDB := &mockContactDatabaseService{}
 	mockWA := &mockWAClient{}
@@ -238,6 +299,14 @@ func TestNewContactServiceWithConfigAndLoggerUsesProvidedLogger(t *testing.T) {
 	assert.Same(t, logger, service.circuitBreaker.logger())
 }
 
+func TestContactService_Enabled(t *testing.T) {
+	var typedNil *ContactService
+	assert.False(t, typedNil.Enabled())
+	assert.False(t, NewContactService(&mockContactDatabaseService{}, nil).Enabled())
+	assert.False(t, NewContactService(nil, &mockWAClient{}).Enabled())
+	assert.True(t, NewContactService(&mockContactDatabaseService{}, &mockWAClient{}).Enabled())
+}
+
 func TestContactService_GetContactDisplayName(t *testing.T) {
 	ctx := context.Background()
 
@@ -390,6 +459,123 @@ func TestContactService_GetContactDisplayName(t *testing.T) {
 	})
 }
 
+func coalescedContactLookups() float64 {
+	if counter, ok := metrics.GetAllMetrics().Counters["contact_lookups_coalesced_total"]; ok {
+		return counter.Value
+	}
+	return 0
+}
+
+func TestContactService_GetContactDisplayName_CoalescesConcurrentLookups(t *testing.T) {
+	ctx := context.Background()
+	mockDB := &mockContactDatabaseService{}
+	mockWA := &mockWAClient{}
+	service := NewContactService(mockDB, mockWA)
+
+	const lookups = 20
+	mockDB.On("GetContactByPhone", ctx, "+1234567890").Return(nil, nil)
+	mockDB.On("SaveContact", ctx, mock.AnythingOfType("*models.Contact")).Return(nil).Once()
+	release := make(chan struct{})
+	mockWA.On("GetContact", mock.Anything, "+1234567890@c.us").
+		Run(func(mock.Arguments) { <-release }).
+		Return(&types.Contact{ID: "+1234567890@c.us", Number: "+1234567890", Name: "John Doe"}, nil).
+		Once()
+
+	before := coalescedContactLookups()
+	names := make(chan string, lookups)
+	for range lookups {
+		go func() { names <- service.GetContactDisplayName(ctx, "+1234567890") }()
+	}
+
+	// Hold the API call until every other lookup waits on it
+	assert.Eventually(t, func() bool {
+		return coalescedContactLookups()-before == lookups-1
+	}, 2*time.Second, 5*time.Millisecond)
+	close(release)
+
+	for range lookups {
+		assert.Equal(t, "John Doe", <-names)
+	}
+	mockWA.AssertNumberOfCalls(t, "GetContact", 1)
+	mockDB.AssertNumberOfCalls(t, "SaveContact", 1)
+}
+
+func TestContactLookups_WaiterStopsWithContext(t *testing.T) {
+	var g contactLookups
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_, _ = g.do(context.Background(), "a@c.us", func() (*types.Contact, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+	}()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	contact, err := g.do(ctx, "a@c.us", func() (*types.Contact, error) {
+		t.Error("a second fetch ran while one was in flight")
+		return nil, nil
+	})
+	assert.Nil(t, contact)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestContactService_CachedContactDisplayName(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fresh, stale and missing contacts", func(t *testing.T) {
+		mockDB := &mockContactDatabaseService{}
+		mockWA := &mockWAClient{}
+		service := NewContactService(mockDB, mockWA)
+
+		mockDB.On("GetContactByPhone", ctx, "+1111111111").Return(&models.Contact{
+			PhoneNumber: "+1111111111",
+			Name:        "John Doe",
+			CachedAt:    time.Now().Add(-1 * time.Hour),
+		}, nil)
+		mockDB.On("GetContactByPhone", ctx, "+2222222222").Return(&models.Contact{
+			PhoneNumber: "+2222222222",
+			Name:        "Old Name",
+			CachedAt:    time.Now().Add(-48 * time.Hour),
+		}, nil)
+		mockDB.On("GetContactByPhone", ctx, "+3333333333").Return(nil, nil)
+
+		name, fresh := service.CachedContactDisplayName(ctx, "+1111111111")
+		assert.Equal(t, "John Doe", name)
+		assert.True(t, fresh)
+
+		name, fresh = service.CachedContactDisplayName(ctx, "+2222222222")
+		assert.Equal(t, "Old Name", name, "a stale name is still used")
+		assert.False(t, fresh)
+
+		name, fresh = service.CachedContactDisplayName(ctx, "+3333333333")
+		assert.Equal(t, "+3333333333", name)
+		assert.False(t, fresh)
+
+		mockDB.AssertExpectations(t)
+		mockWA.AssertNotCalled(t, "GetContact")
+	})
+
+	t.Run("groups and LIDs are not looked up", func(t *testing.T) {
+		mockDB := &mockContactDatabaseService{}
+		service := NewContactService(mockDB, &mockWAClient{})
+
+		name, fresh := service.CachedContactDisplayName(ctx, "120363@g.us")
+		assert.Equal(t, "120363@g.us", name)
+		assert.True(t, fresh)
+
+		name, fresh = service.CachedContactDisplayName(ctx, "987654@lid")
+		assert.Equal(t, "987654", name)
+		assert.True(t, fresh)
+
+		mockDB.AssertNotCalled(t, "GetContactByPhone")
+	})
+}
+
 func TestContactService_RefreshContact(t *testing.T) {
 	ctx := context.Background()
 
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// deliveryFailurePrefix starts the notice sent back to Signal when a message cannot be relayed
+const deliveryFailurePrefix = "⚠️ Failed to deliver to WhatsApp: "
+
+// maxDeliveryFailureReasonRunes caps the reason quoted in a delivery failure notice
+const maxDeliveryFailureReasonRunes = 200
+
+// notifyDeliveryFailure replies to the Signal sender of msg, when signal.notifyOnFailure is set,
+// that the message was not relayed. It is called once sending has failed for good, after
+// retries, and quotes the failed message. Messages from our own number and earlier notices are
+// never answered, so a notice that comes back to the bridge cannot start a loop.
+func (b *bridge) notifyDeliveryFailure(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string, sendErr error) {
+	if !b.signalConfig.NotifyOnFailure || msg.Sender == "" || ctx.Err() != nil {
+		return
+	}
+	if msg.IsSentByMe || msg.Sender == b.signalConfig.IntermediaryPhoneNumber || strings.HasPrefix(msg.Message, deliveryFailurePrefix) {
+		return
+	}
+
+	notice := deliveryFailurePrefix + deliveryFailureReason(sendErr)
+	var opts []signaltypes.SendOption
+	if msg.Timestamp > 0 {
+		opts = append(opts, signaltypes.WithQuote(msg.Timestamp, msg.Sender, msg.Message))
+	}
+	if _, err := b.sigClient.SendMessage(ctx, msg.Sender, notice, nil, opts...); err != nil {
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
+			LogFieldSession: sessionName,
+			"signal_msg_id": SanitizeMessageID(msg.MessageID),
+		}).WithError(err).Warn("Failed to send delivery failure notice to Signal")
+	}
+}
+
+// deliveryFailureReason returns the innermost cause of err, which names the problem without the
+// layers of context added on the way up, shortened for a chat message
+func deliveryFailureReason(err error) string {
+	for inner := errors.Unwrap(err); inner != nil; inner = errors.Unwrap(inner) {
+		err = inner
+	}
+	reason := strings.TrimSpace(err.Error())
+	if runes := []rune(reason); len(runes) > maxDeliveryFailureReasonRunes {
+		reason = string(runes[:maxDeliveryFailureReasonRunes]) + "…"
+	}
+	return reason
+}
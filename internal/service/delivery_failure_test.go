@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupDeliveryFailureBridge returns a bridge whose WhatsApp text sends fail with sendErr
+func setupDeliveryFailureBridge(t *testing.T, sendErr error) (*bridge, *mockSignalClient, func()) {
+	bridge, _, cleanup := setupTestBridge(t)
+	bridge.signalConfig.NotifyOnFailure = true
+	bridge.signalConfig.IntermediaryPhoneNumber = "+1999999999"
+
+	bridge.db.(*mockDatabaseService).On("GetLatestMessageMappingBySession", mock.Anything, "default").Return(&models.MessageMapping{
+		WhatsAppChatID: "111@c.us",
+		WhatsAppMsgID:  "wa_latest",
+		SignalMsgID:    "sig_latest",
+		SessionName:    "default",
+	}, nil)
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		return nil, sendErr
+	}
+
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig_notice", Timestamp: time.Now().UnixMilli()}
+	return bridge, sigClient, cleanup
+}
+
+func TestDeliveryFailureNoticeSentToSignalSender(t *testing.T) {
+	bridge, sigClient, cleanup := setupDeliveryFailureBridge(t, errors.New("WAHA API error: status 404: chat not found"))
+	defer cleanup()
+
+	msg := &signaltypes.SignalMessage{MessageID: "1700000000000", Sender: "+1234567890", Message: "see you soon", Timestamp: 1700000000000}
+	err := bridge.HandleSignalMessage(context.Background(), msg)
+	require.Error(t, err)
+
+	assert.Equal(t, "+1234567890", sigClient.lastRecipient)
+	assert.Equal(t, "⚠️ Failed to deliver to WhatsApp: WAHA API error: status 404: chat not found", sigClient.lastMessage)
+	assert.Equal(t, int64(1700000000000), sigClient.lastSendOptions.QuoteTimestamp, "the notice replies to the failed message")
+	assert.Equal(t, "+1234567890", sigClient.lastSendOptions.QuoteAuthor)
+}
+
+func TestDeliveryFailureNoticeSkipped(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		msg     *signaltypes.SignalMessage
+	}{
+		{"disabled", false, &signaltypes.SignalMessage{MessageID: "1", Sender: "+1234567890", Message: "hi"}},
+		{"own number", true, &signaltypes.SignalMessage{MessageID: "2", Sender: "+1999999999", Message: "hi"}},
+		{"sent by me", true, &signaltypes.SignalMessage{MessageID: "3", Sender: "+1234567890", Message: "hi", IsSentByMe: true}},
+		{"earlier notice", true, &signaltypes.SignalMessage{MessageID: "4", Sender: "+1234567890", Message: deliveryFailurePrefix + "status 404"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, sigClient, cleanup := setupDeliveryFailureBridge(t, errors.New("status 404"))
+			defer cleanup()
+			bridge.signalConfig.NotifyOnFailure = tt.enabled
+
+			bridge.notifyDeliveryFailure(context.Background(), tt.msg, "default", errors.New("status 404"))
+			assert.Empty(t, sigClient.lastMessage)
+		})
+	}
+}
+
+func TestDeliveryFailureReason(t *testing.T) {
+	wrapped := fmt.Errorf("failed to send whatsapp message after retries: %w", fmt.Errorf("attempt 3: %w", errors.New("session not found")))
+	assert.Equal(t, "session not found", deliveryFailureReason(wrapped))
+
+	long := deliveryFailureReason(errors.New(strings.Repeat("x", 300)))
+	assert.Equal(t, strings.Repeat("x", maxDeliveryFailureReasonRunes)+"…", long)
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chatCommandFind searches the recent messages of a WhatsApp chat
+const chatCommandFind = "/find"
+
+// parseFindCommand reports whether a Signal message is a /find command and returns its search
+// term, empty when none was given
+func parseFindCommand(msg *signaltypes.SignalMessage) (string, bool) {
+	if len(msg.Attachments) > 0 {
+		return "", false
+	}
+	fields := strings.Fields(msg.Message)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], chatCommandFind) {
+		return "", false
+	}
+	return strings.Join(fields[1:], " "), true
+}
+
+// handleFindCommand replies to /find <term> with the recent messages of a WhatsApp chat that
+// contain term. The chat is the one of the quoted message, or the latest bridged chat when
+// nothing is quoted. Only the channel's own Signal number may use it; the command is never
+// relayed either way. It reports whether msg was a find command.
+func (b *bridge) handleFindCommand(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string) bool {
+	term, ok := parseFindCommand(msg)
+	if !ok {
+		return false
+	}
+
+	reply := fmt.Sprintf("Usage: %s <term>, quoting a message from the chat to search", chatCommandFind)
+	if term != "" {
+		reply = b.findReply(ctx, msg, sessionName, term)
+	}
+
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, reply); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to send find reply")
+	}
+	return true
+}
+
+// findReply searches the chat msg refers to for term and renders the matches
+func (b *bridge) findReply(ctx context.Context, msg *signaltypes.SignalMessage, sessionName, term string) string {
+	mapping, err := b.findChat(ctx, msg, sessionName)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithField(LogFieldSession, sessionName).Warn("Failed to look up chat for find command")
+		return "Could not look up the chat to search."
+	}
+	if mapping == nil {
+		return "No WhatsApp chat to search. Quote a message from the chat and send " + chatCommandFind + " again."
+	}
+	chatID := mapping.WhatsAppChatID
+
+	messages, err := b.waClient.GetChatMessages(ctx, chatID, constants.FindCommandHistoryLimit, sessionName)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+			LogFieldSession: sessionName,
+			LogFieldChatID:  SanitizePhoneNumber(chatID),
+		}).Warn("Failed to fetch WhatsApp chat history for find command")
+		return "Could not search WhatsApp: " + err.Error()
+	}
+
+	matches := matchChatMessages(messages, term)
+	chatName := b.chatDisplayName(ctx, sessionName, chatID)
+	if len(matches) == 0 {
+		return fmt.Sprintf("No messages matching \"%s\" in the last %d messages of %s.", term, constants.FindCommandHistoryLimit, chatName)
+	}
+
+	loc := b.displayLocation
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := b.clock.Now()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Messages matching \"%s\" in %s:", term, chatName)
+	for i, m := range matches {
+		if i == constants.MaxFindCommandResults {
+			fmt.Fprintf(&sb, "\n…and %d more", len(matches)-i)
+			break
+		}
+		sb.WriteString("\n")
+		if m.Timestamp > 0 {
+			sb.WriteString(formatSentTime(time.Unix(m.Timestamp, 0), now, loc))
+		}
+		fmt.Fprintf(&sb, "%s: %s", b.chatMessageSender(ctx, m), findSnippet(m.Body, term))
+	}
+	return sb.String()
+}
+
+// findChat returns the mapping naming the chat to search: the quoted message's, or the latest
+// bridged message's in the session
+func (b *bridge) findChat(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string) (*models.MessageMapping, error) {
+	if msg.QuotedMessage == nil {
+		return b.db.GetLatestMessageMappingBySession(ctx, sessionName)
+	}
+	mapping, err := b.db.GetMessageMapping(ctx, msg.QuotedMessage.ID)
+	if err != nil || mapping == nil || mapping.SessionName != sessionName {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// matchChatMessages returns the messages whose text contains term, ignoring case, in the order
+// WAHA returned them
+func matchChatMessages(messages []types.ChatMessage, term string) []types.ChatMessage {
+	needle := strings.ToLower(term)
+	var matches []types.ChatMessage
+	for _, m := range messages {
+		if m.Body != "" && strings.Contains(strings.ToLower(m.Body), needle) {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// chatMessageSender names the sender of a message from a chat's history
+func (b *bridge) chatMessageSender(ctx context.Context, m types.ChatMessage) string {
+	if m.FromMe {
+		return "You"
+	}
+	sender := m.From
+	if m.Participant != "" {
+		sender = m.Participant
+	}
+	return b.contactDisplayName(ctx, sender, "")
+}
+
+// findSnippet returns body on one line, cut to FindSnippetLength characters around the first
+// match of term
+func findSnippet(body, term string) string {
+	runes := []rune(strings.Join(strings.Fields(body), " "))
+	if len(runes) <= constants.FindSnippetLength {
+		return string(runes)
+	}
+
+	// Start a third of the snippet before the match, so it shows some context on both sides
+	start := 0
+	lower := strings.ToLower(string(runes))
+	if i := strings.Index(lower, strings.ToLower(term)); i >= 0 {
+		start = len([]rune(lower[:i])) - constants.FindSnippetLength/3
+	}
+	start = max(0, min(start, len(runes)-constants.FindSnippetLength))
+	end := start + constants.FindSnippetLength
+
+	snippet := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFindCommand(t *testing.T) {
+	term, ok := parseFindCommand(&signaltypes.SignalMessage{Message: " /Find  dinner   plans\n"})
+	assert.True(t, ok)
+	assert.Equal(t, "dinner plans", term)
+
+	term, ok = parseFindCommand(&signaltypes.SignalMessage{Message: "/find"})
+	assert.True(t, ok)
+	assert.Empty(t, term)
+
+	_, ok = parseFindCommand(&signaltypes.SignalMessage{Message: "/finder dinner"})
+	assert.False(t, ok)
+	_, ok = parseFindCommand(&signaltypes.SignalMessage{Message: "/find dinner", Attachments: []string{"a.jpg"}})
+	assert.False(t, ok)
+}
+
+// sendFindCommand handles msg as a Signal message and fails the test if anything is relayed
+// to WhatsApp
+func sendFindCommand(t *testing.T, b *bridge, msg *signaltypes.SignalMessage) {
+	t.Helper()
+	b.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		t.Errorf("find command relayed to WhatsApp: %q", text)
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+	require.NoError(t, b.HandleSignalMessage(context.Background(), msg))
+}
+
+func TestFindCommand_ListsMatches(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := b.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig_sent", Timestamp: time.Now().UnixMilli()}
+	b.db.(*mockDatabaseService).On("GetMessageMapping", ctx, "sig-quoted").Return(&models.MessageMapping{
+		WhatsAppChatID: "family@g.us",
+		WhatsAppMsgID:  "wa-quoted",
+		SessionName:    "default",
+	}, nil)
+	groups := new(mockGroupService)
+	groups.On("GetGroupName", ctx, "family@g.us", "default").Return("Family")
+	b.groupService = groups
+	contacts := new(mockContactService)
+	contacts.On("GetContactDisplayName", ctx, "15550001111").Return("Alice")
+	contacts.On("GetContactDisplayName", ctx, "15550002222").Return("15550002222")
+	b.contactService = contacts
+
+	sentAt := time.Date(2026, time.March, 14, 18, 30, 0, 0, time.UTC).Unix()
+	b.waClient.(*mockWhatsAppClient).On("GetChatMessages", ctx, "family@g.us", constants.FindCommandHistoryLimit, "default").Return([]types.ChatMessage{
+		{ID: "m3", Timestamp: sentAt, From: "family@g.us", Participant: "15550001111@c.us", Body: "Dinner at 8?"},
+		{ID: "m2", Timestamp: sentAt, From: "family@g.us", FromMe: true, Body: "What about dinner\non Sunday"},
+		{ID: "m1", Timestamp: sentAt, From: "family@g.us", Participant: "15550002222@c.us", Body: "Lunch instead"},
+		{ID: "m0", Timestamp: sentAt, From: "family@g.us", Participant: "15550002222@c.us", HasMedia: true},
+	}, nil)
+
+	sendFindCommand(t, b, &signaltypes.SignalMessage{
+		MessageID: "cmd1",
+		Sender:    "+1234567890",
+		Message:   "/find DINNER",
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{ID: "sig-quoted"},
+	})
+
+	assert.Equal(t, "+1234567890", sigClient.lastRecipient)
+	assert.Equal(t, "Messages matching \"DINNER\" in Family:\n"+
+		"[Mar 14 18:30] Alice: Dinner at 8?\n"+
+		"[Mar 14 18:30] You: What about dinner on Sunday", sigClient.lastMessage)
+	b.waClient.(*mockWhatsAppClient).AssertExpectations(t)
+}
+
+func TestFindCommand_NoMatchUsesLatestChat(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := b.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig_sent", Timestamp: time.Now().UnixMilli()}
+	b.db.(*mockDatabaseService).On("GetLatestMessageMappingBySession", ctx, "default").Return(&models.MessageMapping{
+		WhatsAppChatID: "15550001111@c.us",
+		SessionName:    "default",
+	}, nil)
+	b.waClient.(*mockWhatsAppClient).On("GetChatMessages", ctx, "15550001111@c.us", constants.FindCommandHistoryLimit, "default").Return([]types.ChatMessage{
+		{ID: "m1", From: "15550001111@c.us", Body: "See you tomorrow"},
+	}, nil)
+
+	sendFindCommand(t, b, &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/find dinner"})
+
+	assert.Equal(t, fmt.Sprintf("No messages matching \"dinner\" in the last %d messages of 15550001111.", constants.FindCommandHistoryLimit), sigClient.lastMessage)
+}
+
+func TestFindCommand_CapsResults(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := b.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig_sent", Timestamp: time.Now().UnixMilli()}
+	b.db.(*mockDatabaseService).On("GetLatestMessageMappingBySession", ctx, "default").Return(&models.MessageMapping{
+		WhatsAppChatID: "15550001111@c.us",
+		SessionName:    "default",
+	}, nil)
+	messages := make([]types.ChatMessage, constants.MaxFindCommandResults+3)
+	for i := range messages {
+		messages[i] = types.ChatMessage{ID: fmt.Sprintf("m%d", i), From: "15550001111@c.us", Body: fmt.Sprintf("ping %d", i)}
+	}
+	b.waClient.(*mockWhatsAppClient).On("GetChatMessages", ctx, "15550001111@c.us", constants.FindCommandHistoryLimit, "default").Return(messages, nil)
+
+	sendFindCommand(t, b, &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/find ping"})
+
+	lines := strings.Split(sigClient.lastMessage, "\n")
+	require.Len(t, lines, constants.MaxFindCommandResults+2)
+	assert.Equal(t, "15550001111: ping 0", lines[1])
+	assert.Equal(t, "…and 3 more", lines[len(lines)-1])
+}
+
+func TestFindCommand_IgnoresOtherSenders(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	sendFindCommand(t, b, &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+15559999999", Message: "/find dinner"})
+
+	assert.Empty(t, b.sigClient.(*mockSignalClient).lastMessage)
+	b.waClient.(*mockWhatsAppClient).AssertNotCalled(t, "GetChatMessages", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFindSnippet(t *testing.T) {
+	assert.Equal(t, "short message", findSnippet("short\n message", "short"))
+
+	long := strings.Repeat("a", 100) + " needle " + strings.Repeat("b", 100)
+	snippet := findSnippet(long, "NEEDLE")
+	assert.Contains(t, snippet, "needle")
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+	assert.Equal(t, constants.FindSnippetLength+2, len([]rune(snippet)))
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// whatsAppToSignalMarkers maps WhatsApp inline formatting markers to the markers
+// understood by signal-cli-rest-api when sending with text_mode "styled".
+var whatsAppToSignalMarkers = map[rune]string{
+	'*': "**", // bold
+	'_': "*",  // italic
+	'~': "~",  // strikethrough
+}
+
+// TranslateWhatsAppFormatting rewrites WhatsApp *bold*, _italic_ and ~strikethrough~
+// spans into Signal styled-text markers. Unbalanced or malformed markers are left as-is.
+func TranslateWhatsAppFormatting(text string) string {
+	return rewriteWhatsAppFormatting(text, func(marker rune, inner string) string {
+		signalMarker := whatsAppToSignalMarkers[marker]
+		return signalMarker + inner + signalMarker
+	})
+}
+
+// StripWhatsAppFormatting removes WhatsApp formatting markers while keeping the enclosed text.
+// Unbalanced or malformed markers are left as-is.
+func StripWhatsAppFormatting(text string) string {
+	return rewriteWhatsAppFormatting(text, func(_ rune, inner string) string {
+		return inner
+	})
+}
+
+// rewriteWhatsAppFormatting walks text looking for balanced WhatsApp formatting spans and
+// replaces each one with the result of render. Nested spans are rewritten first.
+func rewriteWhatsAppFormatting(text string, render func(marker rune, inner string) string) string {
+	if !strings.ContainsAny(text, "*_~") {
+		return text
+	}
+
+	runes := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if _, ok := whatsAppToSignalMarkers[r]; ok && opensFormattingSpan(runes, i) {
+			if end := findClosingMarker(runes, i); end > 0 {
+				inner := rewriteWhatsAppFormatting(string(runes[i+1:end]), render)
+				b.WriteString(render(r, inner))
+				i = end
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// opensFormattingSpan reports whether the marker at i can start a span: it must not be
+// glued to a preceding word and must be followed by non-whitespace.
+func opensFormattingSpan(runes []rune, i int) bool {
+	if i+1 >= len(runes) || unicode.IsSpace(runes[i+1]) || runes[i+1] == runes[i] {
+		return false
+	}
+	return i == 0 || !isFormattingWordRune(runes[i-1])
+}
+
+// findClosingMarker returns the index of the marker closing the span opened at start,
+// or -1 if the span is not closed on the same line.
+func findClosingMarker(runes []rune, start int) int {
+	marker := runes[start]
+	for j := start + 2; j < len(runes); j++ {
+		if runes[j] == '\n' {
+			return -1
+		}
+		if runes[j] != marker || unicode.IsSpace(runes[j-1]) {
+			continue
+		}
+		if j+1 == len(runes) || !isFormattingWordRune(runes[j+1]) {
+			return j
+		}
+	}
+	return -1
+}
+
+func isFormattingWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateWhatsAppFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"bold", "this is *bold* text", "this is **bold** text"},
+		{"italic", "this is _italic_ text", "this is *italic* text"},
+		{"strikethrough", "this is ~gone~ text", "this is ~gone~ text"},
+		{"multiple spans", "*a* and _b_", "**a** and *b*"},
+		{"nested", "*bold _and italic_*", "**bold *and italic***"},
+		{"whole message", "*hello*", "**hello**"},
+		{"no formatting", "plain text", "plain text"},
+		{"empty", "", ""},
+		{"unbalanced opener", "this *is not closed", "this *is not closed"},
+		{"unbalanced closer", "not opened* here", "not opened* here"},
+		{"space after opener", "5 * 3 * 2", "5 * 3 * 2"},
+		{"space before closer", "*not bold *", "*not bold *"},
+		{"intraword underscores", "snake_case_name", "snake_case_name"},
+		{"empty span", "** and __", "** and __"},
+		{"span does not cross lines", "*first\nsecond*", "*first\nsecond*"},
+		{"lone markers", "*", "*"},
+		{"unicode content", "*héllo* 👋", "**héllo** 👋"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, TranslateWhatsAppFormatting(tt.input))
+		})
+	}
+}
+
+func TestStripWhatsAppFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"bold", "this is *bold* text", "this is bold text"},
+		{"italic", "this is _italic_ text", "this is italic text"},
+		{"strikethrough", "this is ~gone~ text", "this is gone text"},
+		{"nested", "*bold _and italic_*", "bold and italic"},
+		{"unbalanced", "this *is not closed", "this *is not closed"},
+		{"intraword underscores", "snake_case_name", "snake_case_name"},
+		{"crossing markers", "*a _b* c_", "a _b c_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StripWhatsAppFormatting(tt.input))
+		})
+	}
+}
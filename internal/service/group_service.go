@@ -19,7 +19,7 @@ import (
 // GroupServiceInterface defines the interface for group operations
 type GroupServiceInterface interface {
 	GetGroupName(ctx context.Context, groupID, sessionName string) string
-	RefreshGroup(ctx context.Context, groupID, sessionName string) error
+	RefreshGroup(ctx context.Context, groupID, sessionName string) (*models.Group, error)
 	SyncAllGroups(ctx context.Context, sessionName string) error
 	CleanupOldGroups(ctx context.Context, retentionDays int) error
 }
@@ -163,25 +163,29 @@ func (gs *GroupService) GetGroupName(ctx context.Context, groupID, sessionName s
 	return waGroup.GetDisplayName()
 }
 
-// RefreshGroup forces a refresh of a specific group from WhatsApp API
-func (gs *GroupService) RefreshGroup(ctx context.Context, groupID, sessionName string) error {
+// RefreshGroup fetches a group from the WhatsApp API through sessionName and stores it in the
+// cache, returning the stored group. A group the session does not know returns nil.
+func (gs *GroupService) RefreshGroup(ctx context.Context, groupID, sessionName string) (*models.Group, error) {
 	if !strings.HasSuffix(groupID, "@g.us") {
-		return fmt.Errorf("invalid group ID format: %s", groupID)
+		return nil, fmt.Errorf("invalid group ID format: %s", groupID)
 	}
 
-	waGroup, err := gs.waClient.GetGroup(ctx, groupID)
+	waGroup, err := gs.waClient.GetGroupWithSession(ctx, groupID, sessionName)
 	if err != nil {
-		return fmt.Errorf("failed to fetch group from WhatsApp API: %w", err)
+		return nil, fmt.Errorf("failed to fetch group from WhatsApp API: %w", err)
 	}
 
 	if waGroup == nil {
-		return fmt.Errorf("group not found: %s", groupID)
+		return nil, nil
 	}
 
 	dbGroup := &models.Group{}
 	dbGroup.FromWAGroup(waGroup, sessionName)
 
-	return gs.db.SaveGroup(ctx, dbGroup)
+	if err := gs.db.SaveGroup(ctx, dbGroup); err != nil {
+		return nil, fmt.Errorf("failed to save group: %w", err)
+	}
+	return dbGroup, nil
 }
 
 // SyncAllGroups fetches all groups from WhatsApp and updates the cache
@@ -13,6 +13,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGroupService_GetGroupName_CacheHit(t *testing.T) {
@@ -225,16 +226,18 @@ func TestGroupService_RefreshGroup(t *testing.T) {
 		Subject: "Refreshed Group",
 	}
 
-	mockWA.On("GetGroup", ctx, groupID).Return(waGroup, nil)
+	mockWA.On("GetGroupWithSession", ctx, groupID, sessionName).Return(waGroup, nil)
 	mockDB.On("SaveGroup", ctx, mock.MatchedBy(func(g *models.Group) bool {
-		return g.GroupID == groupID && g.Subject == "Refreshed Group"
+		return g.GroupID == groupID && g.Subject == "Refreshed Group" && g.SessionName == sessionName
 	})).Return(nil)
 
 	gs := NewGroupService(mockDB, mockWA)
 
-	err := gs.RefreshGroup(ctx, groupID, sessionName)
+	group, err := gs.RefreshGroup(ctx, groupID, sessionName)
 
 	assert.NoError(t, err)
+	require.NotNil(t, group)
+	assert.Equal(t, "Refreshed Group", group.Subject)
 	mockDB.AssertExpectations(t)
 	mockWA.AssertExpectations(t)
 }
@@ -249,12 +252,12 @@ func TestGroupService_RefreshGroup_InvalidFormat(t *testing.T) {
 
 	gs := NewGroupService(mockDB, mockWA)
 
-	err := gs.RefreshGroup(ctx, invalidID, sessionName)
+	_, err := gs.RefreshGroup(ctx, invalidID, sessionName)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid group ID format")
 	mockDB.AssertNotCalled(t, "SaveGroup")
-	mockWA.AssertNotCalled(t, "GetGroup")
+	mockWA.AssertNotCalled(t, "GetGroupWithSession")
 }
 
 func TestGroupService_RefreshGroup_NotFound(t *testing.T) {
@@ -265,14 +268,14 @@ func TestGroupService_RefreshGroup_NotFound(t *testing.T) {
 	groupID := "nonexistent@g.us"
 	sessionName := "default"
 
-	mockWA.On("GetGroup", ctx, groupID).Return(nil, nil)
+	mockWA.On("GetGroupWithSession", ctx, groupID, sessionName).Return(nil, nil)
 
 	gs := NewGroupService(mockDB, mockWA)
 
-	err := gs.RefreshGroup(ctx, groupID, sessionName)
+	group, err := gs.RefreshGroup(ctx, groupID, sessionName)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "group not found")
+	assert.NoError(t, err)
+	assert.Nil(t, group, "a group the session does not know is not cached")
 	mockDB.AssertNotCalled(t, "SaveGroup")
 	mockWA.AssertExpectations(t)
 }
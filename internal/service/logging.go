@@ -86,7 +86,7 @@ func SanitizeContent(content string) string {
 
 // LogWithContext creates a logger entry with optional sensitive information
 func LogWithContext(ctx context.Context, logger *logrus.Logger) *logrus.Entry {
-	return logger.WithField("verbose", IsVerboseLogging(ctx))
+	return logger.WithContext(ctx).WithField("verbose", IsVerboseLogging(ctx))
 }
 
 // ValidatePhoneNumber performs basic phone number validation
@@ -142,7 +142,7 @@ func ValidateSessionName(sessionName string) error {
 // LogMessageProcessing logs message processing with appropriate privacy controls
 func LogMessageProcessing(ctx context.Context, logger *logrus.Logger, msgType string, chatID, msgID, sender, content string) {
 	if IsVerboseLogging(ctx) {
-		logger.WithFields(logrus.Fields{
+		logger.WithContext(ctx).WithFields(logrus.Fields{
 			"type":    msgType,
 			"chatID":  chatID,
 			"msgID":   msgID,
@@ -150,7 +150,7 @@ func LogMessageProcessing(ctx context.Context, logger *logrus.Logger, msgType st
 			"content": content,
 		}).Info("Processing message")
 	} else {
-		logger.WithFields(logrus.Fields{
+		logger.WithContext(ctx).WithFields(logrus.Fields{
 			"type":   msgType,
 			"chatID": SanitizePhoneNumber(chatID),
 			"msgID":  SanitizeMessageID(msgID),
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// markChatSeenAfterReply marks a WhatsApp chat seen after a Signal message was relayed to it,
+// when whatsapp.markSeenOnReply is set, so answering from Signal clears the chat's unread
+// indicator on the phone. The message was already delivered, so a failure is only logged.
+func (b *bridge) markChatSeenAfterReply(ctx context.Context, chatID, sessionName string) {
+	if !b.markSeenOnReply {
+		return
+	}
+	if err := b.waClient.AckMessage(ctx, chatID, sessionName); err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+			LogFieldSession: sessionName,
+			LogFieldChatID:  SanitizePhoneNumber(chatID),
+		}).Warn("Failed to mark WhatsApp chat seen after relaying a Signal message")
+	}
+}
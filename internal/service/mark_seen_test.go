@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// sendSignalReply relays a Signal reply to the WhatsApp chat alice@c.us
+func sendSignalReply(t *testing.T, b *bridge) {
+	t.Helper()
+	ctx := context.Background()
+	b.db.(*mockDatabaseService).On("GetMessageMapping", ctx, "7777777").Return(&models.MessageMapping{
+		WhatsAppChatID: "alice@c.us",
+		WhatsAppMsgID:  "wamid.alice1",
+		SignalMsgID:    "7777777",
+		SessionName:    "default",
+	}, nil)
+	b.db.(*mockDatabaseService).On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	b.waClient.(*mockWhatsAppClient).sendTextResp = &types.SendMessageResponse{MessageID: "wamid.reply1", Status: "sent"}
+
+	require.NoError(t, b.HandleSignalMessage(ctx, &signaltypes.SignalMessage{
+		MessageID: "signal_reply_1",
+		Sender:    "+1234567890",
+		Message:   "On my way",
+		Timestamp: time.Now().UnixMilli(),
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{ID: "7777777", Author: "+1234567890", Text: "Where are you?"},
+	}))
+}
+
+func TestHandleSignalMessage_MarkSeenOnReply(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	b.markSeenOnReply = true
+
+	waClient := b.waClient.(*mockWhatsAppClient)
+	waClient.On("AckMessage", context.Background(), "alice@c.us", "default").Return(nil).Once()
+
+	sendSignalReply(t, b)
+
+	waClient.AssertExpectations(t)
+}
+
+func TestHandleSignalMessage_MarkSeenOnReplyFailureKeepsRelay(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	b.markSeenOnReply = true
+
+	waClient := b.waClient.(*mockWhatsAppClient)
+	waClient.On("AckMessage", context.Background(), "alice@c.us", "default").Return(errors.New("session busy")).Once()
+
+	// sendSignalReply requires the relay to succeed
+	sendSignalReply(t, b)
+
+	waClient.AssertExpectations(t)
+}
+
+func TestHandleSignalMessage_MarkSeenOnReplyDisabled(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	sendSignalReply(t, b)
+
+	b.waClient.(*mockWhatsAppClient).AssertNotCalled(t, "AckMessage", mock.Anything, mock.Anything, mock.Anything)
+}
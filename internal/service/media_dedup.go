@@ -0,0 +1,89 @@
+package service
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recentMediaCache remembers which media (by content hash) was recently relayed to each chat,
+// so identical media sent twice in quick succession is only forwarded once.
+type recentMediaCache struct {
+	mu         sync.Mutex
+	window     time.Duration
+	maxEntries int
+	entries    map[string]time.Time
+	clock      Clock
+}
+
+func newRecentMediaCache(window time.Duration, maxEntries int, clock Clock) *recentMediaCache {
+	return &recentMediaCache{
+		window:     window,
+		maxEntries: maxEntries,
+		entries:    make(map[string]time.Time),
+		clock:      clock,
+	}
+}
+
+// reserve marks the media as relayed to chatID and reports true, or reports false when the
+// same media was relayed or reserved for chatID within the window. Checking and marking under
+// one lock makes only one of two concurrent webhooks for a double-sent file relay it. A relay
+// that then fails hands the media back with release. Expired and then oldest entries are
+// evicted to stay bounded.
+func (c *recentMediaCache) reserve(chatID, contentHash string) bool {
+	if c == nil || contentHash == "" {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := recentMediaKey(chatID, contentHash)
+	now := c.clock.Now()
+	if relayedAt, ok := c.entries[key]; ok && now.Sub(relayedAt) < c.window {
+		return false
+	}
+
+	for key, relayedAt := range c.entries {
+		if now.Sub(relayedAt) >= c.window {
+			delete(c.entries, key)
+		}
+	}
+
+	for c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for key, relayedAt := range c.entries {
+			if oldestKey == "" || relayedAt.Before(oldest) {
+				oldestKey, oldest = key, relayedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+
+	c.entries[key] = now
+	return true
+}
+
+// release forgets a reservation whose relay failed, so the media can be relayed again
+func (c *recentMediaCache) release(chatID, contentHash string) {
+	if c == nil || contentHash == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, recentMediaKey(chatID, contentHash))
+}
+
+func recentMediaKey(chatID, contentHash string) string {
+	return chatID + "|" + contentHash
+}
+
+// mediaContentHash extracts the content hash from a cached media path; the media
+// handler names cached files "<sha256>.<ext>".
+func mediaContentHash(cachedPath string) string {
+	base := filepath.Base(cachedPath)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
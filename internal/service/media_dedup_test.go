@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"whatsignal/internal/testutil"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentMediaCache(t *testing.T) {
+	clock := testutil.NewFakeClock(time.Now())
+	cache := newRecentMediaCache(time.Minute, 10, clock)
+
+	assert.True(t, cache.reserve("chat1", "abc"))
+	assert.False(t, cache.reserve("chat1", "abc"))
+	assert.True(t, cache.reserve("chat2", "abc"), "same media to another chat is not a duplicate")
+	assert.True(t, cache.reserve("chat1", "def"), "different media to the same chat is not a duplicate")
+	assert.True(t, cache.reserve("chat1", ""), "media without a hash is never a duplicate")
+	assert.True(t, cache.reserve("chat1", ""))
+
+	clock.Advance(time.Minute)
+	assert.True(t, cache.reserve("chat1", "abc"), "entries expire after the window")
+
+	cache.release("chat1", "abc")
+	assert.True(t, cache.reserve("chat1", "abc"), "released media can be relayed again")
+}
+
+func TestRecentMediaCacheIsBounded(t *testing.T) {
+	clock := testutil.NewFakeClock(time.Now())
+	cache := newRecentMediaCache(time.Hour, 2, clock)
+
+	cache.reserve("chat1", "a")
+	clock.Advance(time.Second)
+	cache.reserve("chat1", "b")
+	clock.Advance(time.Second)
+	cache.reserve("chat1", "c")
+
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, recentMediaKey("chat1", "a"), "oldest entry is evicted")
+	assert.False(t, cache.reserve("chat1", "b"))
+	assert.False(t, cache.reserve("chat1", "c"))
+}
+
+func TestRecentMediaCacheNilIsDisabled(t *testing.T) {
+	var cache *recentMediaCache
+	assert.True(t, cache.reserve("chat1", "abc"))
+	assert.True(t, cache.reserve("chat1", "abc"))
+	cache.release("chat1", "abc")
+}
+
+func TestMediaContentHash(t *testing.T) {
+	assert.Equal(t, "0123abcd", mediaContentHash("/cache/0123abcd.jpg"))
+	assert.Equal(t, "0123abcd", mediaContentHash("0123abcd"))
+}
+
+func TestHandleWhatsAppMessage_SuppressesDuplicateMedia(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	clock := testutil.NewFakeClock(time.Now())
+	bridge.recentMedia = newRecentMediaCache(30*time.Second, 10, clock)
+
+	const cachedPath = "/cache/5f2b9c.jpg"
+	bridge.media.(*mockMediaHandler).On("ProcessMedia", "https://waha/media/1.jpg").Return(cachedPath, nil)
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.On("SendMessage", mock.Anything, "+1234567890", mock.Anything, []string{cachedPath}).
+		Return(&signaltypes.SendMessageResponse{MessageID: "sig1", Timestamp: clock.Now().UnixMilli()}, nil)
+
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123", "msg1", "sender123", "", "photo", "https://waha/media/1.jpg"))
+	sigClient.AssertNumberOfCalls(t, "SendMessage", 1)
+
+	// Same image to the same chat within the window is suppressed
+	clock.Advance(10 * time.Second)
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123", "msg2", "sender123", "", "photo", "https://waha/media/1.jpg"))
+	sigClient.AssertNumberOfCalls(t, "SendMessage", 1)
+
+	// Outside the window it is relayed again
+	clock.Advance(time.Minute)
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123", "msg3", "sender123", "", "photo", "https://waha/media/1.jpg"))
+	sigClient.AssertNumberOfCalls(t, "SendMessage", 2)
+}
+
+func TestHandleWhatsAppMessage_ConcurrentDuplicateMedia(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	bridge.recentMedia = newRecentMediaCache(30*time.Second, 10, RealClock)
+
+	// Both webhooks finish processing the media before either checks for a duplicate
+	var processing sync.WaitGroup
+	processing.Add(2)
+	const cachedPath = "/cache/5f2b9c.jpg"
+	bridge.media.(*mockMediaHandler).On("ProcessMedia", "https://waha/media/1.jpg").Run(func(mock.Arguments) {
+		processing.Done()
+		processing.Wait()
+	}).Return(cachedPath, nil)
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.On("SendMessage", mock.Anything, "+1234567890", mock.Anything, []string{cachedPath}).
+		Return(&signaltypes.SendMessageResponse{MessageID: "sig1", Timestamp: time.Now().UnixMilli()}, nil)
+
+	var wg sync.WaitGroup
+	for _, msgID := range []string{"msg1", "msg2"} {
+		wg.Add(1)
+		go func(msgID string) {
+			defer wg.Done()
+			assert.NoError(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123", msgID, "sender123", "", "photo", "https://waha/media/1.jpg"))
+		}(msgID)
+	}
+	wg.Wait()
+
+	sigClient.AssertNumberOfCalls(t, "SendMessage", 1)
+}
+
+func TestHandleWhatsAppMessage_FailedMediaRelayIsNotSuppressed(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	bridge.recentMedia = newRecentMediaCache(30*time.Second, 10, RealClock)
+	bridge.retryConfig.MaxAttempts = 1
+
+	const cachedPath = "/cache/5f2b9c.jpg"
+	bridge.media.(*mockMediaHandler).On("ProcessMedia", "https://waha/media/1.jpg").Return(cachedPath, nil)
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.On("SendMessage", mock.Anything, "+1234567890", mock.Anything, []string{cachedPath}).
+		Return(nil, errors.New("signal-cli unavailable")).Once()
+	sigClient.On("SendMessage", mock.Anything, "+1234567890", mock.Anything, []string{cachedPath}).
+		Return(&signaltypes.SendMessageResponse{MessageID: "sig1", Timestamp: time.Now().UnixMilli()}, nil)
+
+	require.Error(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123", "msg1", "sender123", "", "photo", "https://waha/media/1.jpg"))
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123", "msg1", "sender123", "", "photo", "https://waha/media/1.jpg"))
+	sigClient.AssertNumberOfCalls(t, "SendMessage", 2)
+}
@@ -15,6 +15,7 @@ import (
 	"whatsignal/internal/metrics"
 	"whatsignal/internal/models"
 	"whatsignal/internal/retry"
+	"whatsignal/internal/tracing"
 	"whatsignal/pkg/signal"
 	signaltypes "whatsignal/pkg/signal/types"
 
@@ -32,10 +33,12 @@ type Database interface {
 	GetPendingMessages(ctx context.Context, limit int) ([]models.PendingSignalMessage, error)
 	DeletePendingMessage(ctx context.Context, messageID string, destination string) error
 	IncrementPendingRetryCount(ctx context.Context, messageID string, destination string) error
+	GetSignalCursor(ctx context.Context, account string) (int64, error)
+	SetSignalCursor(ctx context.Context, account string, timestamp int64) error
 }
 
 type MediaCache interface {
-	ProcessMedia(path string) (string, error)
+	ProcessMedia(ctx context.Context, path string) (string, error)
 	CleanupOldFiles(maxAge int64) error
 }
 
@@ -53,6 +56,11 @@ type MessageService interface {
 	PollSignalMessages(ctx context.Context) error
 	DispatchSingleSignalMessage(ctx context.Context, msg signaltypes.SignalMessage) error
 	SendSignalNotification(ctx context.Context, sessionName, message string) error
+	SendSystemNotification(ctx context.Context, sessionName, message string) error
+	HandleWhatsAppReaction(ctx context.Context, sessionName string, reaction WhatsAppReaction, mapping *models.MessageMapping) error
+	HandleWhatsAppPin(ctx context.Context, sessionName string, pin models.WhatsAppPin) error
+	HandleWhatsAppLiveLocation(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation) error
+	HandleWhatsAppGroupParticipants(ctx context.Context, sessionName string, change models.WhatsAppGroupParticipants) error
 	GetMessageMappingByWhatsAppID(ctx context.Context, whatsappID string) (*models.MessageMapping, error)
 	ProcessPendingMessages(ctx context.Context) error
 }
@@ -115,6 +123,7 @@ type messageService struct {
 	mu                 sync.RWMutex
 	chatLockManager    *chatLockManager
 	inProgressMessages sync.Map // tracks message IDs currently being processed
+	cursor             signalCursor
 }
 
 func NewMessageService(bridge MessageBridge, db Database, mediaCache MediaCache, signalClient signal.Client, signalConfig models.SignalConfig, channelManager *ChannelManager) MessageService {
@@ -152,7 +161,7 @@ func (s *messageService) SendMessage(ctx context.Context, msg *models.Message) e
 		if err := validateRemoteMediaURL(msg.MediaURL); err != nil {
 			return err
 		}
-		cachePath, err := s.mediaCache.ProcessMedia(msg.MediaURL)
+		cachePath, err := s.mediaCache.ProcessMedia(ctx, msg.MediaURL)
 		if err != nil {
 			return fmt.Errorf("failed to process media: %w", err)
 		}
@@ -201,7 +210,7 @@ func (s *messageService) ReceiveMessage(ctx context.Context, msg *models.Message
 		if err := validateRemoteMediaURL(msg.MediaURL); err != nil {
 			return err
 		}
-		cachePath, err := s.mediaCache.ProcessMedia(msg.MediaURL)
+		cachePath, err := s.mediaCache.ProcessMedia(ctx, msg.MediaURL)
 		if err != nil {
 			return err
 		}
@@ -312,7 +321,7 @@ func (s *messageService) MarkMessageDelivered(ctx context.Context, id string) er
 func (s *messageService) HandleWhatsAppMessageWithSession(ctx context.Context, sessionName, chatID, msgID, sender, senderDisplayName, content string, mediaPath string) error {
 	// Check if message is already being processed (in-flight deduplication)
 	if _, alreadyProcessing := s.inProgressMessages.LoadOrStore(msgID, true); alreadyProcessing {
-		s.logger.Debug("Message already being processed, skipping duplicate webhook")
+		s.logger.WithContext(ctx).Debug("Message already being processed, skipping duplicate webhook")
 		return nil
 	}
 	// Ensure we clean up the in-progress marker when done
@@ -324,7 +333,7 @@ func (s *messageService) HandleWhatsAppMessageWithSession(ctx context.Context, s
 	s.mu.RUnlock()
 
 	if err == nil && existingMapping != nil {
-		s.logger.Debug("Message already processed, skipping")
+		s.logger.WithContext(ctx).Debug("Message already processed, skipping")
 		return nil
 	}
 
@@ -346,7 +355,7 @@ func (s *messageService) HandleSignalMessage(ctx context.Context, msg *models.Me
 		if err := validateRemoteMediaURL(msg.MediaURL); err != nil {
 			return err
 		}
-		cachePath, err := s.mediaCache.ProcessMedia(msg.MediaURL)
+		cachePath, err := s.mediaCache.ProcessMedia(ctx, msg.MediaURL)
 		if err != nil {
 			return fmt.Errorf("failed to process media for HandleSignalMessage: %w", err)
 		}
@@ -367,7 +376,7 @@ func (s *messageService) ProcessIncomingSignalMessage(ctx context.Context, rawSi
 func (s *messageService) ProcessIncomingSignalMessageWithDestination(ctx context.Context, rawSignalMsg *signaltypes.SignalMessage, destination string) error {
 	// Check if message is already being processed (in-flight deduplication)
 	if _, alreadyProcessing := s.inProgressMessages.LoadOrStore(rawSignalMsg.MessageID, true); alreadyProcessing {
-		s.logger.WithField("messageID", rawSignalMsg.MessageID).Debug("Signal message already being processed, skipping duplicate")
+		s.logger.WithContext(ctx).WithField("messageID", rawSignalMsg.MessageID).Debug("Signal message already being processed, skipping duplicate")
 		return nil
 	}
 	// Ensure we clean up the in-progress marker when done
@@ -376,6 +385,9 @@ func (s *messageService) ProcessIncomingSignalMessageWithDestination(ctx context
 	if rawSignalMsg.Receipt != nil {
 		return s.bridge.HandleSignalReceipt(ctx, rawSignalMsg)
 	}
+	if rawSignalMsg.Typing != nil {
+		return s.bridge.HandleSignalTyping(ctx, rawSignalMsg, destination)
+	}
 
 	LogMessageProcessing(ctx, s.logger, "Signal", "", rawSignalMsg.MessageID, rawSignalMsg.Sender, rawSignalMsg.Message)
 
@@ -403,10 +415,19 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 	if pollTimeout <= 0 {
 		pollTimeout = s.signalConfig.PollIntervalSec
 	}
+	pollStart := time.Now()
 	messages, err := s.signalClient.ReceiveMessages(ctx, pollTimeout)
+	pollStatus := "success"
+	if err != nil {
+		pollStatus = "failure"
+	}
+	metrics.ObserveHistogram("signal_poll_latency_seconds", time.Since(pollStart).Seconds(), metrics.DefaultLatencyBuckets, map[string]string{
+		"status": pollStatus,
+	}, "Time signal-cli took to answer a poll for messages")
 	if err != nil {
 		return fmt.Errorf("failed to poll Signal messages: %w", err)
 	}
+	receivedAt := time.Now()
 
 	LogSignalPolling(ctx, s.logger, len(messages))
 
@@ -426,9 +447,17 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 	var dispatched []messageWithDest
 
 	for _, msg := range messages {
+		if s.isBeforeSignalCursor(ctx, &msg) {
+			s.logger.WithContext(ctx).WithField("messageID", SanitizeMessageID(msg.MessageID)).Debug("Skipping Signal envelope already processed before restart")
+			metrics.IncrementCounter("signal_poll_messages_skipped", map[string]string{
+				"reason": "before_cursor",
+			}, "Messages skipped at dispatch")
+			continue
+		}
+
 		destinations := s.channelManager.GetAllSignalDestinations()
 		if len(destinations) == 0 {
-			s.logger.Error("No Signal destinations configured")
+			s.logger.WithContext(ctx).Error("No Signal destinations configured")
 			metrics.IncrementCounter("signal_poll_messages_skipped", map[string]string{
 				"reason": "no_destinations",
 			}, "Messages skipped at dispatch")
@@ -441,7 +470,7 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 		} else {
 			destination = s.determineDestinationForSender(ctx, msg.Sender, destinations)
 			if destination == "" {
-				s.logger.WithFields(logrus.Fields{
+				s.logger.WithContext(ctx).WithFields(logrus.Fields{
 					"sender":    SanitizePhoneNumber(msg.Sender),
 					"messageID": SanitizeMessageID(msg.MessageID),
 				}).Warn("Could not determine destination for Signal sender, skipping message")
@@ -464,7 +493,7 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 	for _, d := range dispatched {
 		rawJSON, jsonErr := json.Marshal(d.msg)
 		if jsonErr != nil {
-			s.logger.WithError(jsonErr).WithField("messageID", d.msg.MessageID).Warn("Failed to serialize message for persistence")
+			s.logger.WithContext(ctx).WithError(jsonErr).WithField("messageID", d.msg.MessageID).Warn("Failed to serialize message for persistence")
 			continue
 		}
 		pendingMessages = append(pendingMessages, models.PendingSignalMessage{
@@ -479,7 +508,7 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 
 	if len(pendingMessages) > 0 {
 		if saveErr := s.db.SavePendingMessages(ctx, pendingMessages); saveErr != nil {
-			s.logger.WithError(saveErr).Warn("Failed to persist pending messages, continuing with in-memory processing")
+			s.logger.WithContext(ctx).WithError(saveErr).Warn("Failed to persist pending messages, continuing with in-memory processing")
 			metrics.IncrementCounter("signal_pending_save_failures", nil, "Failed attempts to persist pending messages")
 		} else {
 			persisted = true
@@ -501,6 +530,9 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 			defer wg.Done()
 			defer func() { <-sem }()
 
+			// Each polled message gets its own request ID for log and HTTP correlation
+			msgCtx := WithIngressTime(tracing.WithRequestID(ctx, tracing.GenerateRequestID()), receivedAt)
+
 			chatKey := m.Sender + ":" + dest
 			chatLock := s.chatLockManager.getLock(chatKey)
 			chatLock.Lock()
@@ -517,16 +549,16 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 			})
 
 			for attempt := 1; attempt <= maxAttempts; attempt++ {
-				if err := s.ProcessIncomingSignalMessageWithDestination(ctx, &m, dest); err != nil {
+				if err := s.ProcessIncomingSignalMessageWithDestination(msgCtx, &m, dest); err != nil {
 					lastErr = err
 					if attempt < maxAttempts {
-						s.logger.WithFields(logrus.Fields{
+						s.logger.WithContext(msgCtx).WithFields(logrus.Fields{
 							"messageID": m.MessageID,
 							"attempt":   attempt,
 						}).WithError(err).Warn("Message processing failed, retrying")
 						select {
 						case <-ctx.Done():
-							s.logger.WithField("messageID", m.MessageID).Error("Context cancelled during message retry")
+							s.logger.WithContext(msgCtx).WithField("messageID", m.MessageID).Error("Context cancelled during message retry")
 							metrics.IncrementCounter("signal_message_process_failures", map[string]string{
 								"reason": "context_cancelled",
 							}, "Signal message processing failures")
@@ -539,16 +571,17 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 						metrics.IncrementCounter("signal_message_process_retries_succeeded", nil,
 							"Signal messages that succeeded after retry")
 					}
+					s.advanceSignalCursor(ctx, m.Timestamp)
 					if isPersisted {
 						if delErr := s.db.DeletePendingMessage(ctx, m.MessageID, dest); delErr != nil {
-							s.logger.WithError(delErr).WithField("messageID", m.MessageID).Warn("Failed to delete pending message after success")
+							s.logger.WithContext(msgCtx).WithError(delErr).WithField("messageID", m.MessageID).Warn("Failed to delete pending message after success")
 						}
 					}
 					return
 				}
 			}
 
-			s.logger.WithFields(logrus.Fields{
+			s.logger.WithContext(msgCtx).WithFields(logrus.Fields{
 				"messageID": m.MessageID,
 				"attempts":  maxAttempts,
 			}).WithError(lastErr).Error("Message processing failed after all retry attempts")
@@ -558,7 +591,7 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 
 			if isPersisted {
 				if incErr := s.db.IncrementPendingRetryCount(ctx, m.MessageID, dest); incErr != nil {
-					s.logger.WithError(incErr).WithField("messageID", m.MessageID).Warn("Failed to increment pending retry count")
+					s.logger.WithContext(ctx).WithError(incErr).WithField("messageID", m.MessageID).Warn("Failed to increment pending retry count")
 				}
 			}
 		}(d.msg, d.destination, persisted)
@@ -576,6 +609,10 @@ func (s *messageService) PollSignalMessages(ctx context.Context) error {
 }
 
 func (s *messageService) ProcessPendingMessages(ctx context.Context) error {
+	if s.signalCursorEnabled() {
+		s.loadSignalCursor(ctx)
+	}
+
 	pending, err := s.db.GetPendingMessages(ctx, constants.DefaultPendingMessageBatchSize)
 	if err != nil {
 		return fmt.Errorf("failed to get pending messages: %w", err)
@@ -585,29 +622,30 @@ func (s *messageService) ProcessPendingMessages(ctx context.Context) error {
 		return nil
 	}
 
-	s.logger.WithField("count", len(pending)).Info("Reprocessing pending messages from previous session")
+	s.logger.WithContext(ctx).WithField("count", len(pending)).Info("Reprocessing pending messages from previous session")
 
 	for _, pm := range pending {
 		var msg signaltypes.SignalMessage
 		if err := json.Unmarshal([]byte(pm.RawJSON), &msg); err != nil {
-			s.logger.WithError(err).WithField("messageID", pm.MessageID).Error("Failed to deserialize pending message, deleting")
+			s.logger.WithContext(ctx).WithError(err).WithField("messageID", pm.MessageID).Error("Failed to deserialize pending message, deleting")
 			if delErr := s.db.DeletePendingMessage(ctx, pm.MessageID, pm.Destination); delErr != nil {
-				s.logger.WithError(delErr).Warn("Failed to delete corrupt pending message")
+				s.logger.WithContext(ctx).WithError(delErr).Warn("Failed to delete corrupt pending message")
 			}
 			continue
 		}
 
-		if err := s.ProcessIncomingSignalMessageWithDestination(ctx, &msg, pm.Destination); err != nil {
-			s.logger.WithError(err).WithFields(logrus.Fields{
+		msgCtx := tracing.WithRequestID(ctx, tracing.GenerateRequestID())
+		if err := s.ProcessIncomingSignalMessageWithDestination(msgCtx, &msg, pm.Destination); err != nil {
+			s.logger.WithContext(msgCtx).WithError(err).WithFields(logrus.Fields{
 				"messageID":  pm.MessageID,
 				"retryCount": pm.RetryCount,
 			}).Error("Failed to reprocess pending message")
 			if incErr := s.db.IncrementPendingRetryCount(ctx, pm.MessageID, pm.Destination); incErr != nil {
-				s.logger.WithError(incErr).Warn("Failed to increment pending retry count")
+				s.logger.WithContext(ctx).WithError(incErr).Warn("Failed to increment pending retry count")
 			}
 		} else {
 			if delErr := s.db.DeletePendingMessage(ctx, pm.MessageID, pm.Destination); delErr != nil {
-				s.logger.WithError(delErr).Warn("Failed to delete processed pending message")
+				s.logger.WithContext(ctx).WithError(delErr).Warn("Failed to delete processed pending message")
 			}
 		}
 	}
@@ -616,6 +654,11 @@ func (s *messageService) ProcessPendingMessages(ctx context.Context) error {
 }
 
 func (s *messageService) DispatchSingleSignalMessage(ctx context.Context, msg signaltypes.SignalMessage) error {
+	if s.isBeforeSignalCursor(ctx, &msg) {
+		s.logger.WithContext(ctx).WithField("messageID", SanitizeMessageID(msg.MessageID)).Debug("Skipping Signal envelope already processed before restart")
+		return nil
+	}
+
 	destinations := s.channelManager.GetAllSignalDestinations()
 	if len(destinations) == 0 {
 		return fmt.Errorf("no Signal destinations configured")
@@ -627,12 +670,16 @@ func (s *messageService) DispatchSingleSignalMessage(ctx context.Context, msg si
 	} else {
 		destination = s.determineDestinationForSender(ctx, msg.Sender, destinations)
 		if destination == "" {
-			s.logger.WithField("sender", SanitizePhoneNumber(msg.Sender)).Warn("Could not determine destination for Signal sender (WebSocket)")
+			s.logger.WithContext(ctx).WithField("sender", SanitizePhoneNumber(msg.Sender)).Warn("Could not determine destination for Signal sender (WebSocket)")
 			return nil
 		}
 	}
 
-	return s.ProcessIncomingSignalMessageWithDestination(ctx, &msg, destination)
+	if err := s.ProcessIncomingSignalMessageWithDestination(ctx, &msg, destination); err != nil {
+		return err
+	}
+	s.advanceSignalCursor(ctx, msg.Timestamp)
+	return nil
 }
 
 func deliveryStatusRank(status string) int {
@@ -663,11 +710,11 @@ func (s *messageService) determineDestinationForSender(ctx context.Context, send
 		if sender == destination {
 			session, err := s.channelManager.GetWhatsAppSession(destination)
 			if err != nil {
-				s.logger.WithError(err).WithField("destination", SanitizePhoneNumber(destination)).Warn("Failed to get WhatsApp session for destination")
+				s.logger.WithContext(ctx).WithError(err).WithField("destination", SanitizePhoneNumber(destination)).Warn("Failed to get WhatsApp session for destination")
 				continue
 			}
 
-			s.logger.WithFields(logrus.Fields{
+			s.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"sender":      SanitizePhoneNumber(sender),
 				"destination": SanitizePhoneNumber(destination),
 				"session":     session,
@@ -683,7 +730,7 @@ func (s *messageService) determineDestinationForSender(ctx context.Context, send
 		// Check if we have any message history between this session and the sender
 		hasHistory, err := s.db.HasMessageHistoryBetween(ctx, session, sender)
 		if err != nil {
-			s.logger.WithError(err).WithFields(logrus.Fields{
+			s.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
 				"session": session,
 				"sender":  SanitizePhoneNumber(sender),
 			}).Warn("Failed to check message history")
@@ -693,14 +740,14 @@ func (s *messageService) determineDestinationForSender(ctx context.Context, send
 		if hasHistory {
 			destination, err := s.channelManager.GetSignalDestination(session)
 			if err != nil {
-				s.logger.WithError(err).WithField("session", session).Warn("Failed to get Signal destination for session")
+				s.logger.WithContext(ctx).WithError(err).WithField("session", session).Warn("Failed to get Signal destination for session")
 				continue
 			}
 
 			// Verify this destination is in our available list
 			for _, availableDest := range availableDestinations {
 				if destination == availableDest {
-					s.logger.WithFields(logrus.Fields{
+					s.logger.WithContext(ctx).WithFields(logrus.Fields{
 						"sender":      SanitizePhoneNumber(sender),
 						"destination": SanitizePhoneNumber(destination),
 						"session":     session,
@@ -711,7 +758,7 @@ func (s *messageService) determineDestinationForSender(ctx context.Context, send
 		}
 	}
 
-	s.logger.WithField("sender", SanitizePhoneNumber(sender)).Debug("No message history found for sender")
+	s.logger.WithContext(ctx).WithField("sender", SanitizePhoneNumber(sender)).Debug("No message history found for sender")
 	return ""
 }
 
@@ -721,6 +768,37 @@ func (s *messageService) SendSignalNotification(ctx context.Context, sessionName
 	return s.bridge.SendSignalNotificationForSession(ctx, sessionName, message)
 }
 
+// SendSystemNotification sends a notice about the bridge itself, such as the session going down.
+// It goes to signal.opsRecipient when set, prefixed with the session name since that number
+// hears about every channel, and to the session's Signal destination otherwise.
+func (s *messageService) SendSystemNotification(ctx context.Context, sessionName, message string) error {
+	if s.signalConfig.OpsRecipient == "" {
+		return s.SendSignalNotification(ctx, sessionName, message)
+	}
+
+	notice := fmt.Sprintf("[%s] %s", sessionName, message)
+	if _, err := s.signalClient.SendMessage(ctx, s.signalConfig.OpsRecipient, notice, []string{}); err != nil {
+		return fmt.Errorf("failed to send Signal notification to ops recipient: %w", err)
+	}
+	return nil
+}
+
+func (s *messageService) HandleWhatsAppReaction(ctx context.Context, sessionName string, reaction WhatsAppReaction, mapping *models.MessageMapping) error {
+	return s.bridge.HandleWhatsAppReaction(ctx, sessionName, reaction, mapping)
+}
+
+func (s *messageService) HandleWhatsAppPin(ctx context.Context, sessionName string, pin models.WhatsAppPin) error {
+	return s.bridge.HandleWhatsAppPin(ctx, sessionName, pin)
+}
+
+func (s *messageService) HandleWhatsAppLiveLocation(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation) error {
+	return s.bridge.HandleWhatsAppLiveLocation(ctx, sessionName, loc)
+}
+
+func (s *messageService) HandleWhatsAppGroupParticipants(ctx context.Context, sessionName string, change models.WhatsAppGroupParticipants) error {
+	return s.bridge.HandleWhatsAppGroupParticipants(ctx, sessionName, change)
+}
+
 func (s *messageService) GetMessageMappingByWhatsAppID(ctx context.Context, whatsappID string) (*models.MessageMapping, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
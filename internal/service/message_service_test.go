@@ -10,6 +10,8 @@ import (
 
 	"whatsignal/internal/constants"
 	"whatsignal/internal/models"
+	"whatsignal/internal/tracing"
+	"whatsignal/pkg/media"
 	signaltypes "whatsignal/pkg/signal/types"
 
 	"github.com/sirupsen/logrus"
@@ -24,6 +26,12 @@ type mockBridge struct {
 
 var _ MessageService = (*messageService)(nil)
 
+// polledMessageCtx matches the per-message context, carrying its own request ID, that
+// polling hands to the bridge
+func polledMessageCtx() interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool { return tracing.GetRequestID(ctx) != "" })
+}
+
 func (m *mockBridge) SendMessage(ctx context.Context, msg *models.Message) error {
 	args := m.Called(ctx, msg)
 	return args.Error(0)
@@ -49,11 +57,50 @@ func (m *mockBridge) HandleSignalReceipt(ctx context.Context, msg *signaltypes.S
 	return args.Error(0)
 }
 
+func (m *mockBridge) HandleSignalTyping(ctx context.Context, msg *signaltypes.SignalMessage, destination string) error {
+	args := m.Called(ctx, msg, destination)
+	return args.Error(0)
+}
+
 func (m *mockBridge) SendSignalNotificationForSession(ctx context.Context, sessionName, message string) error {
 	args := m.Called(ctx, sessionName, message)
 	return args.Error(0)
 }
 
+func (m *mockBridge) HandleWhatsAppReaction(ctx context.Context, sessionName string, reaction WhatsAppReaction, mapping *models.MessageMapping) error {
+	args := m.Called(ctx, sessionName, reaction, mapping)
+	return args.Error(0)
+}
+
+func (m *mockBridge) HandleWhatsAppPin(ctx context.Context, sessionName string, pin models.WhatsAppPin) error {
+	args := m.Called(ctx, sessionName, pin)
+	return args.Error(0)
+}
+
+func (m *mockBridge) HandleWhatsAppLiveLocation(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation) error {
+	args := m.Called(ctx, sessionName, loc)
+	return args.Error(0)
+}
+
+func (m *mockBridge) HandleWhatsAppGroupParticipants(ctx context.Context, sessionName string, change models.WhatsAppGroupParticipants) error {
+	args := m.Called(ctx, sessionName, change)
+	return args.Error(0)
+}
+
+func (m *mockBridge) PurgeOrphanedMedia(ctx context.Context, gracePeriod time.Duration) (int, error) {
+	args := m.Called(ctx, gracePeriod)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockBridge) Retention() *RetentionSetting {
+	return NewRetentionSetting(0)
+}
+
+func (m *mockBridge) BroadcastMessage(ctx context.Context, sessionName string, chats []string, text string) error {
+	args := m.Called(ctx, sessionName, chats, text)
+	return args.Error(0)
+}
+
 func (m *mockBridge) HandleSignalMessageDeletion(ctx context.Context, targetMessageID string, sender string) error {
 	args := m.Called(ctx, targetMessageID, sender)
 	return args.Error(0)
@@ -135,11 +182,21 @@ func (m *mockDB) IncrementPendingRetryCount(ctx context.Context, messageID strin
 	return args.Error(0)
 }
 
+func (m *mockDB) GetSignalCursor(ctx context.Context, account string) (int64, error) {
+	args := m.Called(ctx, account)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockDB) SetSignalCursor(ctx context.Context, account string, timestamp int64) error {
+	args := m.Called(ctx, account, timestamp)
+	return args.Error(0)
+}
+
 type mockMediaCache struct {
 	mock.Mock
 }
 
-func (m *mockMediaCache) ProcessMedia(path string) (string, error) {
+func (m *mockMediaCache) ProcessMedia(ctx context.Context, path string) (string, error) {
 	args := m.Called(path)
 	return args.String(0), args.Error(1)
 }
@@ -149,6 +206,14 @@ func (m *mockMediaCache) CleanupOldFiles(maxAge int64) error {
 	return args.Error(0)
 }
 
+func (m *mockMediaCache) CacheStats() (media.CacheStats, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return media.CacheStats{}, args.Error(1)
+	}
+	return args.Get(0).(media.CacheStats), args.Error(1)
+}
+
 func setupTestService(t *testing.T) (MessageService, context.Context) {
 	ctx := context.Background()
 	bridge := new(mockBridge)
@@ -887,6 +952,30 @@ func TestProcessIncomingSignalMessageWithDestination_Receipt(t *testing.T) {
 	bridge.AssertNotCalled(t, "HandleSignalMessageWithDestination", mock.Anything, mock.Anything, mock.Anything)
 }
 
+func TestProcessIncomingSignalMessageWithDestination_Typing(t *testing.T) {
+	bridge := new(mockBridge)
+	channelManager, _ := NewChannelManager([]models.Channel{
+		{
+			WhatsAppSessionName:          "default",
+			SignalDestinationPhoneNumber: "+1234567890",
+		},
+	})
+	service := NewMessageService(bridge, new(mockDB), new(mockMediaCache), &mockSignalClient{}, models.SignalConfig{PollIntervalSec: 5}, channelManager)
+
+	ctx := context.Background()
+	msg := &signaltypes.SignalMessage{
+		MessageID: "1700000000200",
+		Sender:    "+1234567890",
+		Typing:    &signaltypes.TypingInfo{Action: signaltypes.TypingActionStarted},
+	}
+	bridge.On("HandleSignalTyping", ctx, msg, "+1234567890").Return(nil).Once()
+
+	err := service.ProcessIncomingSignalMessageWithDestination(ctx, msg, "+1234567890")
+	assert.NoError(t, err)
+	bridge.AssertExpectations(t)
+	bridge.AssertNotCalled(t, "HandleSignalMessageWithDestination", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestPollSignalMessages(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -926,7 +1015,7 @@ func TestPollSignalMessages(t *testing.T) {
 						Timestamp: time.Now().UnixMilli(),
 					},
 				}, nil).Once()
-				bridge.On("HandleSignalMessageWithDestination", mock.AnythingOfType("context.backgroundCtx"), mock.MatchedBy(func(msg *signaltypes.SignalMessage) bool {
+				bridge.On("HandleSignalMessageWithDestination", mock.MatchedBy(func(ctx context.Context) bool { return tracing.GetRequestID(ctx) != "" }), mock.MatchedBy(func(msg *signaltypes.SignalMessage) bool {
 					return msg.Sender == "+1234567890" || msg.Sender == "+0987654321"
 				}), mock.AnythingOfType("string")).Return(nil).Twice()
 			},
@@ -1027,14 +1116,13 @@ func TestPollSignalMessages_MultiChannel(t *testing.T) {
 				db.On("HasMessageHistoryBetween", ctx, "business", "+8888888888").Return(true, nil).Maybe()
 			},
 			expectations: func(bridge *mockBridge) {
-				ctx := context.Background()
 				// First message should route to personal destination
-				bridge.On("HandleSignalMessageWithDestination", ctx, mock.MatchedBy(func(msg *signaltypes.SignalMessage) bool {
+				bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.MatchedBy(func(msg *signaltypes.SignalMessage) bool {
 					return msg.MessageID == "sig1" && msg.Sender == "+9999999999"
 				}), "+1111111111").Return(nil)
 
 				// Second message should route to business destination
-				bridge.On("HandleSignalMessageWithDestination", ctx, mock.MatchedBy(func(msg *signaltypes.SignalMessage) bool {
+				bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.MatchedBy(func(msg *signaltypes.SignalMessage) bool {
 					return msg.MessageID == "sig2" && msg.Sender == "+8888888888"
 				}), "+2222222222").Return(nil)
 			},
@@ -1075,8 +1163,7 @@ func TestPollSignalMessages_MultiChannel(t *testing.T) {
 				// No history calls needed for single channel
 			},
 			expectations: func(bridge *mockBridge) {
-				ctx := context.Background()
-				bridge.On("HandleSignalMessageWithDestination", ctx, mock.MatchedBy(func(msg *signaltypes.SignalMessage) bool {
+				bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.MatchedBy(func(msg *signaltypes.SignalMessage) bool {
 					return msg.MessageID == "sig4" && msg.Sender == "+4444444444"
 				}), "+1234567890").Return(nil)
 			},
@@ -1531,7 +1618,7 @@ func TestProcessPendingMessages(t *testing.T) {
 					{MessageID: "msg1", Sender: "+1234567890", Message: "hello", RawJSON: rawJSON, Destination: "+1234567890"},
 				}
 				db.On("GetPendingMessages", ctx, mock.Anything).Return(pending, nil)
-				bridge.On("HandleSignalMessageWithDestination", ctx, mock.MatchedBy(func(m *signaltypes.SignalMessage) bool {
+				bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.MatchedBy(func(m *signaltypes.SignalMessage) bool {
 					return m.MessageID == "msg1"
 				}), "+1234567890").Return(nil)
 				db.On("DeletePendingMessage", ctx, "msg1", "+1234567890").Return(nil)
@@ -1545,7 +1632,7 @@ func TestProcessPendingMessages(t *testing.T) {
 					{MessageID: "msg2", Sender: "+1234567890", Message: "fail", RawJSON: rawJSON, Destination: "+1234567890"},
 				}
 				db.On("GetPendingMessages", ctx, mock.Anything).Return(pending, nil)
-				bridge.On("HandleSignalMessageWithDestination", ctx, mock.MatchedBy(func(m *signaltypes.SignalMessage) bool {
+				bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.MatchedBy(func(m *signaltypes.SignalMessage) bool {
 					return m.MessageID == "msg2"
 				}), "+1234567890").Return(assert.AnError)
 				db.On("IncrementPendingRetryCount", ctx, "msg2", "+1234567890").Return(nil)
@@ -1571,11 +1658,11 @@ func TestProcessPendingMessages(t *testing.T) {
 					{MessageID: "msg-fail", Sender: "+2222222222", Message: "fail", RawJSON: rawFail, Destination: "+2222222222"},
 				}
 				db.On("GetPendingMessages", ctx, mock.Anything).Return(pending, nil)
-				bridge.On("HandleSignalMessageWithDestination", ctx, mock.MatchedBy(func(m *signaltypes.SignalMessage) bool {
+				bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.MatchedBy(func(m *signaltypes.SignalMessage) bool {
 					return m.MessageID == "msg-ok"
 				}), "+1111111111").Return(nil)
 				db.On("DeletePendingMessage", ctx, "msg-ok", "+1111111111").Return(nil)
-				bridge.On("HandleSignalMessageWithDestination", ctx, mock.MatchedBy(func(m *signaltypes.SignalMessage) bool {
+				bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.MatchedBy(func(m *signaltypes.SignalMessage) bool {
 					return m.MessageID == "msg-fail"
 				}), "+2222222222").Return(assert.AnError)
 				db.On("IncrementPendingRetryCount", ctx, "msg-fail", "+2222222222").Return(nil)
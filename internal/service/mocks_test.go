@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"sync"
 	"time"
 	"whatsignal/internal/models"
+	"whatsignal/pkg/media"
 	signaltypes "whatsignal/pkg/signal/types"
 	"whatsignal/pkg/whatsapp/types"
 
@@ -27,12 +29,30 @@ type mockWhatsAppClient struct {
 }
 
 func (m *mockWhatsAppClient) SendTextWithSession(ctx context.Context, chatID, text, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	if m.hasExpectation("SendTextWithSession") {
+		args := m.Called(ctx, chatID, text, replyTo, sessionName)
+		if args.Get(0) == nil {
+			return nil, args.Error(1)
+		}
+		return args.Get(0).(*types.SendMessageResponse), args.Error(1)
+	}
 	if m.sendTextFunc != nil {
 		return m.sendTextFunc(ctx, chatID, text)
 	}
 	return m.sendTextResp, m.sendTextErr
 }
 
+func (m *mockWhatsAppClient) SendMentionsWithSession(ctx context.Context, chatID, text string, mentions []string, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	if m.hasExpectation("SendMentionsWithSession") {
+		args := m.Called(ctx, chatID, text, mentions, replyTo, sessionName)
+		if args.Get(0) == nil {
+			return nil, args.Error(1)
+		}
+		return args.Get(0).(*types.SendMessageResponse), args.Error(1)
+	}
+	return m.SendTextWithSession(ctx, chatID, text, replyTo, sessionName)
+}
+
 func (m *mockWhatsAppClient) SendImageWithSession(ctx context.Context, chatID, imagePath, caption, replyTo, sessionName string) (*types.SendMessageResponse, error) {
 	if m.hasExpectation("SendImageWithSession") {
 		args := m.Called(ctx, chatID, imagePath, caption, replyTo, sessionName)
@@ -99,6 +119,16 @@ func (m *mockWhatsAppClient) DeleteMessage(ctx context.Context, chatID, messageI
 	return args.Error(0)
 }
 
+func (m *mockWhatsAppClient) EditMessageWithSession(ctx context.Context, chatID, messageID, newText, sessionName string) error {
+	args := m.Called(ctx, chatID, messageID, newText, sessionName)
+	return args.Error(0)
+}
+
+func (m *mockWhatsAppClient) StarMessageWithSession(ctx context.Context, chatID, messageID string, star bool, sessionName string) error {
+	args := m.Called(ctx, chatID, messageID, star, sessionName)
+	return args.Error(0)
+}
+
 func (m *mockWhatsAppClient) SendContact(ctx context.Context, chatID, contactID string) (*types.SendMessageResponse, error) {
 	args := m.Called(ctx, chatID, contactID)
 	if args.Get(0) == nil {
@@ -123,8 +153,8 @@ func (m *mockWhatsAppClient) SendSticker(ctx context.Context, chatID, stickerPat
 	return args.Get(0).(*types.SendMessageResponse), args.Error(1)
 }
 
-func (m *mockWhatsAppClient) CreateSession(ctx context.Context) error {
-	args := m.Called(ctx)
+func (m *mockWhatsAppClient) CreateSession(ctx context.Context, config *types.SessionConfig) error {
+	args := m.Called(ctx, config)
 	return args.Error(0)
 }
 
@@ -204,6 +234,14 @@ func (m *mockWhatsAppClient) GetGroup(ctx context.Context, groupID string) (*typ
 	return args.Get(0).(*types.Group), args.Error(1)
 }
 
+func (m *mockWhatsAppClient) GetGroupWithSession(ctx context.Context, groupID, sessionName string) (*types.Group, error) {
+	args := m.Called(ctx, groupID, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Group), args.Error(1)
+}
+
 func (m *mockWhatsAppClient) GetAllGroups(ctx context.Context, limit, offset int) ([]types.Group, error) {
 	args := m.Called(ctx, limit, offset)
 	if args.Get(0) == nil {
@@ -212,6 +250,36 @@ func (m *mockWhatsAppClient) GetAllGroups(ctx context.Context, limit, offset int
 	return args.Get(0).([]types.Group), args.Error(1)
 }
 
+func (m *mockWhatsAppClient) GetChatLabels(ctx context.Context, chatID string) ([]types.Label, error) {
+	if m.hasExpectation("GetChatLabels") {
+		args := m.Called(ctx, chatID)
+		if args.Get(0) == nil {
+			return nil, args.Error(1)
+		}
+		return args.Get(0).([]types.Label), args.Error(1)
+	}
+	return nil, nil
+}
+
+func (m *mockWhatsAppClient) GetChatMessages(ctx context.Context, chatID string, limit int, sessionName string) ([]types.ChatMessage, error) {
+	args := m.Called(ctx, chatID, limit, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.ChatMessage), args.Error(1)
+}
+
+func (m *mockWhatsAppClient) GetMe(ctx context.Context, sessionName string) (*types.Me, error) {
+	if m.hasExpectation("GetMe") {
+		args := m.Called(ctx, sessionName)
+		if args.Get(0) == nil {
+			return nil, args.Error(1)
+		}
+		return args.Get(0).(*types.Me), args.Error(1)
+	}
+	return nil, nil
+}
+
 func (m *mockWhatsAppClient) GetSessionName() string {
 	return "test-session"
 }
@@ -221,11 +289,35 @@ func (m *mockWhatsAppClient) HealthCheck(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *mockWhatsAppClient) GetCapabilities(ctx context.Context) types.Capabilities {
+	if m.hasExpectation("GetCapabilities") {
+		args := m.Called(ctx)
+		return args.Get(0).(types.Capabilities)
+	}
+	return types.Capabilities{}
+}
+
 func (m *mockWhatsAppClient) AckMessage(ctx context.Context, chatID, sessionName string) error {
 	args := m.Called(ctx, chatID, sessionName)
 	return args.Error(0)
 }
 
+func (m *mockWhatsAppClient) StartTyping(ctx context.Context, chatID, sessionName string) error {
+	if m.hasExpectation("StartTyping") {
+		args := m.Called(ctx, chatID, sessionName)
+		return args.Error(0)
+	}
+	return nil
+}
+
+func (m *mockWhatsAppClient) StopTyping(ctx context.Context, chatID, sessionName string) error {
+	if m.hasExpectation("StopTyping") {
+		args := m.Called(ctx, chatID, sessionName)
+		return args.Error(0)
+	}
+	return nil
+}
+
 // Mock Signal client
 type mockSignalClient struct {
 	mock.Mock
@@ -234,11 +326,16 @@ type mockSignalClient struct {
 	initializeDeviceErr error
 	lastMessage         string
 	lastRecipient       string
+	lastSendOptions     signaltypes.SendMessageRequest
 }
 
-func (m *mockSignalClient) SendMessage(ctx context.Context, recipient, message string, attachments []string) (*signaltypes.SendMessageResponse, error) {
+func (m *mockSignalClient) SendMessage(ctx context.Context, recipient, message string, attachments []string, opts ...signaltypes.SendOption) (*signaltypes.SendMessageResponse, error) {
 	m.lastMessage = message
 	m.lastRecipient = recipient
+	m.lastSendOptions = signaltypes.SendMessageRequest{}
+	for _, opt := range opts {
+		opt(&m.lastSendOptions)
+	}
 	if m.sendMessageResponse != nil || m.sendMessageErr != nil {
 		return m.sendMessageResponse, m.sendMessageErr
 	}
@@ -281,6 +378,24 @@ func (m *mockSignalClient) ListAttachments(ctx context.Context) ([]string, error
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *mockSignalClient) SetExpiration(ctx context.Context, recipient string, expiration time.Duration) error {
+	args := m.Called(ctx, recipient, expiration)
+	return args.Error(0)
+}
+
+func (m *mockSignalClient) SendReaction(ctx context.Context, recipient, emoji, targetAuthor string, targetTimestamp int64, remove bool) error {
+	args := m.Called(ctx, recipient, emoji, targetAuthor, targetTimestamp, remove)
+	return args.Error(0)
+}
+
+func (m *mockSignalClient) GetContactProfile(ctx context.Context, number string) (*signaltypes.ContactProfile, error) {
+	args := m.Called(ctx, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*signaltypes.ContactProfile), args.Error(1)
+}
+
 func (m *mockSignalClient) DetectedMode() string {
 	return "native"
 }
@@ -290,21 +405,70 @@ type mockMediaHandler struct {
 	mock.Mock
 }
 
-func (h *mockMediaHandler) ProcessMedia(sourcePath string) (string, error) {
+func (h *mockMediaHandler) ProcessMedia(ctx context.Context, sourcePath string) (string, error) {
 	args := h.Called(sourcePath)
 	return args.String(0), args.Error(1)
 }
 
+// ProcessMediaForSession is recorded as ProcessMedia, so tests need not tell the two apart
+func (h *mockMediaHandler) ProcessMediaForSession(ctx context.Context, sourcePath, sessionName string) (string, error) {
+	return h.ProcessMedia(ctx, sourcePath)
+}
+
+// ProcessMediaWithConfig is recorded as ProcessMedia too
+func (h *mockMediaHandler) ProcessMediaWithConfig(ctx context.Context, sourcePath, sessionName string, config models.MediaConfig) (string, error) {
+	return h.ProcessMedia(ctx, sourcePath)
+}
+
 func (h *mockMediaHandler) CleanupOldFiles(maxAgeSeconds int64) error {
 	args := h.Called(maxAgeSeconds)
 	return args.Error(0)
 }
 
+func (h *mockMediaHandler) RemoveUnreferencedFiles(referenced []string, gracePeriod time.Duration) (int, error) {
+	args := h.Called(referenced, gracePeriod)
+	return args.Int(0), args.Error(1)
+}
+
+func (h *mockMediaHandler) EnforceCacheBudget() (int64, error) {
+	args := h.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (h *mockMediaHandler) CacheStats() (media.CacheStats, error) {
+	args := h.Called()
+	if args.Get(0) == nil {
+		return media.CacheStats{}, args.Error(1)
+	}
+	return args.Get(0).(media.CacheStats), args.Error(1)
+}
+
 // Mock channel manager
 
 // Mock database service
 type mockDatabaseService struct {
 	mock.Mock
+
+	// pausedChats backs SetChatPaused/IsChatPaused as a fake so tests need no expectations for them
+	pausedChatsMu sync.Mutex
+	pausedChats   map[string]bool
+
+	// autoReplies backs SetChatAutoReply/ClaimChatAutoReply as a fake, guarded by pausedChatsMu
+	autoReplies map[string]*mockChatAutoReply
+
+	// chatSessions backs GetMappingSessionsForChat, most recent session first
+	chatSessions map[string][]string
+
+	// seenKeys backs RecordSeen/WasSeen as a fake, guarded by pausedChatsMu
+	seenKeys map[string]time.Time
+
+	// groupMappings backs SaveGroupMapping/GetGroupMapping as a fake, guarded by pausedChatsMu
+	groupMappings map[string]string
+}
+
+type mockChatAutoReply struct {
+	message string
+	sentAt  time.Time
 }
 
 func (m *mockDatabaseService) SaveMessageMapping(ctx context.Context, mapping *models.MessageMapping) error {
@@ -397,6 +561,92 @@ func (m *mockDatabaseService) GetContactByName(ctx context.Context, name string)
 	return args.Get(0).(*models.Contact), args.Error(1)
 }
 
+func (m *mockDatabaseService) GetMappingSessionsForChat(ctx context.Context, chatID string) ([]string, error) {
+	return m.chatSessions[chatID], nil
+}
+
+func (m *mockDatabaseService) GetMediaPaths(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockDatabaseService) SetChatPaused(ctx context.Context, sessionName, chatID string, paused bool) error {
+	m.pausedChatsMu.Lock()
+	defer m.pausedChatsMu.Unlock()
+	if m.pausedChats == nil {
+		m.pausedChats = make(map[string]bool)
+	}
+	m.pausedChats[sessionName+"|"+chatID] = paused
+	return nil
+}
+
+func (m *mockDatabaseService) IsChatPaused(ctx context.Context, sessionName, chatID string) (bool, error) {
+	m.pausedChatsMu.Lock()
+	defer m.pausedChatsMu.Unlock()
+	return m.pausedChats[sessionName+"|"+chatID], nil
+}
+
+func (m *mockDatabaseService) SaveGroupMapping(ctx context.Context, mapping *models.GroupMapping) error {
+	m.pausedChatsMu.Lock()
+	defer m.pausedChatsMu.Unlock()
+	if m.groupMappings == nil {
+		m.groupMappings = make(map[string]string)
+	}
+	m.groupMappings[mapping.SessionName+"|"+mapping.SignalGroupID] = mapping.WhatsAppGroupID
+	return nil
+}
+
+func (m *mockDatabaseService) GetGroupMapping(ctx context.Context, sessionName, signalGroupID string) (*models.GroupMapping, error) {
+	m.pausedChatsMu.Lock()
+	defer m.pausedChatsMu.Unlock()
+	whatsAppGroupID, ok := m.groupMappings[sessionName+"|"+signalGroupID]
+	if !ok {
+		return nil, nil
+	}
+	return &models.GroupMapping{SessionName: sessionName, SignalGroupID: signalGroupID, WhatsAppGroupID: whatsAppGroupID}, nil
+}
+
+func (m *mockDatabaseService) SetChatAutoReply(ctx context.Context, sessionName, chatID, message string) error {
+	m.pausedChatsMu.Lock()
+	defer m.pausedChatsMu.Unlock()
+	if m.autoReplies == nil {
+		m.autoReplies = make(map[string]*mockChatAutoReply)
+	}
+	m.autoReplies[sessionName+"|"+chatID] = &mockChatAutoReply{message: message}
+	return nil
+}
+
+func (m *mockDatabaseService) ClaimChatAutoReply(ctx context.Context, sessionName, chatID string, now time.Time, cooldown time.Duration) (string, error) {
+	m.pausedChatsMu.Lock()
+	defer m.pausedChatsMu.Unlock()
+	reply := m.autoReplies[sessionName+"|"+chatID]
+	if reply == nil || reply.message == "" || now.Sub(reply.sentAt) < cooldown {
+		return "", nil
+	}
+	reply.sentAt = now
+	return reply.message, nil
+}
+
+func (m *mockDatabaseService) RecordSeen(ctx context.Context, key string, ttl time.Duration) error {
+	m.pausedChatsMu.Lock()
+	defer m.pausedChatsMu.Unlock()
+	if m.seenKeys == nil {
+		m.seenKeys = make(map[string]time.Time)
+	}
+	m.seenKeys[key] = time.Now().Add(ttl)
+	return nil
+}
+
+func (m *mockDatabaseService) WasSeen(ctx context.Context, key string) (bool, error) {
+	m.pausedChatsMu.Lock()
+	defer m.pausedChatsMu.Unlock()
+	expiresAt, ok := m.seenKeys[key]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
 func (m *mockDatabaseService) UpdateSignalIDByWhatsAppID(ctx context.Context, whatsappMsgID, signalMsgID string, signalTimestamp time.Time, status string) error {
 	args := m.Called(ctx, whatsappMsgID, signalMsgID, signalTimestamp, status)
 	return args.Error(0)
@@ -423,6 +673,11 @@ func (m *mockDatabaseService) HasMessageHistoryBetween(ctx context.Context, sess
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *mockDatabaseService) GetMappingStats(ctx context.Context, sessionName string) (models.MappingStats, error) {
+	args := m.Called(ctx, sessionName)
+	return args.Get(0).(models.MappingStats), args.Error(1)
+}
+
 func (m *mockDatabaseService) GetStaleMessageCount(ctx context.Context, threshold time.Duration) (int, error) {
 	args := m.Called(ctx, threshold)
 	return args.Int(0), args.Error(1)
@@ -433,11 +688,20 @@ type mockContactService struct {
 	mock.Mock
 }
 
+func (m *mockContactService) Enabled() bool {
+	return true
+}
+
 func (m *mockContactService) GetContactDisplayName(ctx context.Context, phoneNumber string) string {
 	args := m.Called(ctx, phoneNumber)
 	return args.String(0)
 }
 
+func (m *mockContactService) CachedContactDisplayName(ctx context.Context, phoneNumber string) (string, bool) {
+	args := m.Called(ctx, phoneNumber)
+	return args.String(0), args.Bool(1)
+}
+
 func (m *mockContactService) SyncContacts(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -469,9 +733,12 @@ func (m *mockGroupService) GetGroupName(ctx context.Context, groupID, sessionNam
 	return args.String(0)
 }
 
-func (m *mockGroupService) RefreshGroup(ctx context.Context, groupID, sessionName string) error {
+func (m *mockGroupService) RefreshGroup(ctx context.Context, groupID, sessionName string) (*models.Group, error) {
 	args := m.Called(ctx, groupID, sessionName)
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
 }
 
 func (m *mockGroupService) SyncAllGroups(ctx context.Context, sessionName string) error {
@@ -494,6 +761,16 @@ func (m *mockContactService) RefreshContact(ctx context.Context, contactID strin
 	return args.Error(0)
 }
 
+func (m *mockContactService) SyncSignalContact(ctx context.Context, number string) error {
+	for _, call := range m.ExpectedCalls {
+		if call.Method == "SyncSignalContact" {
+			args := m.Called(ctx, number)
+			return args.Error(0)
+		}
+	}
+	return nil
+}
+
 func (m *mockContactService) SyncAllContacts(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// nativeReactionTarget tracks the WhatsApp reactions to one bridged message and the Signal
+// reaction mirroring them
+type nativeReactionTarget struct {
+	// reactors lists the WhatsApp IDs of the people reacting, most recent last; emojis maps
+	// each to their emoji. WhatsApp allows one reaction per person.
+	reactors []string
+	emojis   map[string]string
+	// shown is the emoji of the Signal reaction on the message, empty when there is none
+	shown     string
+	updatedAt time.Time
+}
+
+// nativeReactionRelay relays WhatsApp reactions as Signal reactions on the bridged message.
+// The bridge's Signal account can hold one reaction per message, so it shows the emoji of the
+// most recent WhatsApp reactor; when that reaction is removed it falls back to the previous
+// reactor's emoji, or is removed once nobody reacts any more. At most maxTargets messages are
+// tracked.
+type nativeReactionRelay struct {
+	// mu is held across the Signal call so reactions to a message reach Signal in order
+	mu         sync.Mutex
+	maxTargets int
+	targets    map[string]*nativeReactionTarget
+	clock      Clock
+}
+
+func newNativeReactionRelay(maxTargets int, clock Clock) *nativeReactionRelay {
+	return &nativeReactionRelay{
+		maxTargets: maxTargets,
+		targets:    make(map[string]*nativeReactionTarget),
+		clock:      clock,
+	}
+}
+
+// applyLocked records reaction on the target with key and returns the target. It reports false
+// for the removal of a reaction to a message it does not track, as the Signal reaction's emoji,
+// which Signal needs to take it back, is unknown.
+func (r *nativeReactionRelay) applyLocked(key string, reaction WhatsAppReaction) (*nativeReactionTarget, bool) {
+	target := r.targets[key]
+	if target == nil {
+		if reaction.Emoji == "" {
+			return nil, false
+		}
+		r.evictLocked()
+		target = &nativeReactionTarget{emojis: make(map[string]string)}
+		r.targets[key] = target
+	}
+
+	for i, id := range target.reactors {
+		if id == reaction.ReactorID {
+			target.reactors = append(target.reactors[:i], target.reactors[i+1:]...)
+			break
+		}
+	}
+	if reaction.Emoji == "" {
+		delete(target.emojis, reaction.ReactorID)
+	} else {
+		target.reactors = append(target.reactors, reaction.ReactorID)
+		target.emojis[reaction.ReactorID] = reaction.Emoji
+	}
+	target.updatedAt = r.clock.Now()
+	return target, true
+}
+
+// evictLocked makes room for one more message by dropping the least recently updated targets
+func (r *nativeReactionRelay) evictLocked() {
+	for r.maxTargets > 0 && len(r.targets) >= r.maxTargets {
+		var oldestKey string
+		var oldest *nativeReactionTarget
+		for k, target := range r.targets {
+			if oldest == nil || target.updatedAt.Before(oldest.updatedAt) {
+				oldestKey, oldest = k, target
+			}
+		}
+		delete(r.targets, oldestKey)
+	}
+}
+
+// wanted returns the emoji the Signal reaction should show, empty for none
+func (t *nativeReactionTarget) wanted() string {
+	if len(t.reactors) == 0 {
+		return ""
+	}
+	return t.emojis[t.reactors[len(t.reactors)-1]]
+}
+
+// relayNativeReaction mirrors reaction as a Signal reaction on the message mapping was bridged
+// as. It reports false, leaving the reaction to the text notice, when the mapping has no Signal
+// message to react to or a removal arrives for a reaction it never relayed.
+func (b *bridge) relayNativeReaction(ctx context.Context, sessionName, dest string, reaction WhatsAppReaction, mapping *models.MessageMapping) (bool, error) {
+	timestamp, author, ok := b.mappingSignalTarget(sessionName, dest, mapping, reaction.FromMe)
+	if !ok {
+		return false, nil
+	}
+
+	r := b.nativeReactions
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := sessionName + "|" + mapping.WhatsAppMsgID
+	target, ok := r.applyLocked(key, reaction)
+	if !ok {
+		return false, nil
+	}
+
+	wanted := target.wanted()
+	switch {
+	case wanted == target.shown:
+		// Another reactor's change left the shown reaction as it is
+	case wanted == "":
+		if err := b.sigClient.SendReaction(ctx, dest, target.shown, author, timestamp, true); err != nil {
+			return true, fmt.Errorf("failed to remove reaction on Signal: %w", err)
+		}
+	default:
+		// A new reaction replaces the account's previous one
+		if err := b.sigClient.SendReaction(ctx, dest, wanted, author, timestamp, false); err != nil {
+			return true, fmt.Errorf("failed to send reaction to Signal: %w", err)
+		}
+	}
+	target.shown = wanted
+	if wanted == "" {
+		delete(r.targets, key)
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession:   sessionName,
+		LogFieldChatID:    SanitizePhoneNumber(mapping.WhatsAppChatID),
+		LogFieldMessageID: SanitizeWhatsAppMessageID(mapping.WhatsAppMsgID),
+		"emoji":           reaction.Emoji,
+		"shown":           wanted,
+	}).Debug("Relayed WhatsApp reaction to Signal as a reaction")
+
+	return true, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupNativeReactionBridge(t *testing.T) (*bridge, *mockSignalClient, func()) {
+	bridge, _, cleanup := setupTestBridge(t)
+	bridge.signalConfig.IntermediaryPhoneNumber = "+1999999999"
+	bridge.nativeReactions = newNativeReactionRelay(10, bridge.clock)
+	return bridge, bridge.sigClient.(*mockSignalClient), cleanup
+}
+
+func TestHandleWhatsAppReaction_NativeRemovalIsPropagated(t *testing.T) {
+	bridge, sigClient, cleanup := setupNativeReactionBridge(t)
+	defer cleanup()
+
+	mapping := &models.MessageMapping{WhatsAppChatID: "+15550001111@c.us", WhatsAppMsgID: "wa_text", SignalMsgID: "1700000000123", SessionName: "default"}
+	sigClient.On("SendReaction", mock.Anything, "+1234567890", "👍", "+1999999999", int64(1700000000123), false).Return(nil).Once()
+	sigClient.On("SendReaction", mock.Anything, "+1234567890", "👍", "+1999999999", int64(1700000000123), true).Return(nil).Once()
+
+	ctx := context.Background()
+	require.NoError(t, bridge.HandleWhatsAppReaction(ctx, "default", WhatsAppReaction{ReactorID: "+15550001111@c.us", Emoji: "👍"}, mapping))
+	require.NoError(t, bridge.HandleWhatsAppReaction(ctx, "default", WhatsAppReaction{ReactorID: "+15550001111@c.us", Emoji: ""}, mapping))
+
+	sigClient.AssertExpectations(t)
+	assert.Empty(t, sigClient.lastMessage, "no text notice is sent")
+}
+
+func TestHandleWhatsAppReaction_NativeGroupFallsBackToEarlierReaction(t *testing.T) {
+	bridge, sigClient, cleanup := setupNativeReactionBridge(t)
+	defer cleanup()
+
+	mapping := &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_sent", SignalMsgID: "1700000000456", SessionName: "default"}
+	// The reacted message was sent from Signal, so the Signal user is its author
+	sigClient.On("SendReaction", mock.Anything, "+1234567890", "👍", "+1234567890", int64(1700000000456), false).Return(nil).Twice()
+	sigClient.On("SendReaction", mock.Anything, "+1234567890", "❤️", "+1234567890", int64(1700000000456), false).Return(nil).Once()
+	sigClient.On("SendReaction", mock.Anything, "+1234567890", "👍", "+1234567890", int64(1700000000456), true).Return(nil).Once()
+
+	ctx := context.Background()
+	for _, reaction := range []WhatsAppReaction{
+		{ReactorID: "+15550001111@c.us", Emoji: "👍", FromMe: true},
+		{ReactorID: "+15550002222@c.us", Emoji: "❤️", FromMe: true},
+		{ReactorID: "+15550002222@c.us", Emoji: "", FromMe: true}, // back to 👍
+		{ReactorID: "+15550003333@c.us", Emoji: "", FromMe: true}, // never reacted; no change
+		{ReactorID: "+15550001111@c.us", Emoji: "", FromMe: true}, // nobody left
+	} {
+		require.NoError(t, bridge.HandleWhatsAppReaction(ctx, "default", reaction, mapping))
+	}
+
+	sigClient.AssertExpectations(t)
+	assert.Empty(t, sigClient.lastMessage)
+}
+
+func TestHandleWhatsAppReaction_NativeFallsBackToNotice(t *testing.T) {
+	bridge, sigClient, cleanup := setupNativeReactionBridge(t)
+	defer cleanup()
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-notice", Timestamp: time.Now().UnixMilli()}
+
+	ctx := context.Background()
+	pending := &models.MessageMapping{WhatsAppChatID: "+15550001111@c.us", WhatsAppMsgID: "wa_text", SignalMsgID: "pending:wa_text", SessionName: "default"}
+	require.NoError(t, bridge.HandleWhatsAppReaction(ctx, "default", WhatsAppReaction{ReactorID: "+15550001111@c.us", Emoji: "❤️"}, pending))
+	assert.Equal(t, "Reacted ❤️", sigClient.lastMessage, "a message not yet on Signal gets a notice")
+
+	// A removal whose reaction was relayed before a restart has no emoji to take back
+	untracked := &models.MessageMapping{WhatsAppChatID: "+15550001111@c.us", WhatsAppMsgID: "wa_old", SignalMsgID: "1700000000789", SessionName: "default"}
+	require.NoError(t, bridge.HandleWhatsAppReaction(ctx, "default", WhatsAppReaction{ReactorID: "+15550001111@c.us", Emoji: ""}, untracked))
+	assert.Equal(t, "Removed reaction", sigClient.lastMessage)
+	sigClient.AssertNotCalled(t, "SendReaction")
+}
+
+func TestHandleWhatsAppReaction_NativeRemovalRetriedAfterFailure(t *testing.T) {
+	bridge, sigClient, cleanup := setupNativeReactionBridge(t)
+	defer cleanup()
+
+	mapping := &models.MessageMapping{WhatsAppChatID: "+15550001111@c.us", WhatsAppMsgID: "wa_text", SignalMsgID: "1700000000123", SessionName: "default"}
+	sigClient.On("SendReaction", mock.Anything, "+1234567890", "👍", "+1999999999", int64(1700000000123), false).Return(nil).Once()
+	sigClient.On("SendReaction", mock.Anything, "+1234567890", "👍", "+1999999999", int64(1700000000123), true).Return(errors.New("signal-cli unavailable")).Once()
+	sigClient.On("SendReaction", mock.Anything, "+1234567890", "👍", "+1999999999", int64(1700000000123), true).Return(nil).Once()
+
+	ctx := context.Background()
+	removal := WhatsAppReaction{ReactorID: "+15550001111@c.us", Emoji: ""}
+	require.NoError(t, bridge.HandleWhatsAppReaction(ctx, "default", WhatsAppReaction{ReactorID: "+15550001111@c.us", Emoji: "👍"}, mapping))
+	require.Error(t, bridge.HandleWhatsAppReaction(ctx, "default", removal, mapping))
+	require.NoError(t, bridge.HandleWhatsAppReaction(ctx, "default", removal, mapping), "WAHA's retry removes the reaction")
+	sigClient.AssertExpectations(t)
+}
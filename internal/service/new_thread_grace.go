@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/metrics"
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// awaitLatestMapping looks up the session's latest message mapping again for up to
+// signal.newThreadGraceMs. A Signal reply sent right after a WhatsApp message arrives can be
+// handled before that message's mapping is saved; waiting briefly routes it to the chat instead
+// of rejecting it as a new conversation. It returns nil when no mapping appears in time.
+func (b *bridge) awaitLatestMapping(ctx context.Context, sessionName string) (*models.MessageMapping, error) {
+	grace := time.Duration(b.signalConfig.NewThreadGraceMs) * time.Millisecond
+	if grace <= 0 {
+		return nil, nil
+	}
+
+	interval := time.Duration(constants.NewThreadRecheckIntervalMs) * time.Millisecond
+	deadline := b.clock.Now().Add(grace)
+	for {
+		wait := deadline.Sub(b.clock.Now())
+		if wait <= 0 {
+			return nil, nil
+		}
+		if wait > interval {
+			wait = interval
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		mapping, err := b.db.GetLatestMessageMappingBySession(ctx, sessionName)
+		if err != nil {
+			return nil, err
+		}
+		if mapping != nil {
+			metrics.IncrementCounter("new_thread_grace_hits_total", map[string]string{
+				"session": sessionName,
+			}, "Signal messages routed to a chat whose mapping was saved during the new-thread grace period")
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
+				LogFieldSession: sessionName,
+				LogFieldChatID:  SanitizePhoneNumber(mapping.WhatsAppChatID),
+			}).Debug("Found latest message mapping after waiting for it")
+			return mapping, nil
+		}
+	}
+}
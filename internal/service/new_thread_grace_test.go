@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	"whatsignal/internal/testutil"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSignalMessage_NewThreadGrace(t *testing.T) {
+	tests := []struct {
+		name       string
+		graceMs    int
+		savedAfter int // Lookups before the mapping of the WhatsApp message is saved
+		relayed    bool
+	}{
+		{name: "mapping saved during the grace period", graceMs: 500, savedAfter: 2, relayed: true},
+		{name: "mapping saved after the grace period", graceMs: 120, savedAfter: 100},
+		{name: "no grace period", savedAfter: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+			bridge.signalConfig.NewThreadGraceMs = tt.graceMs
+
+			ctx := context.Background()
+			db := bridge.db.(*mockDatabaseService)
+			db.On("GetLatestMessageMappingBySession", ctx, "default").Return(nil, nil).Times(tt.savedAfter)
+			db.On("GetLatestMessageMappingBySession", ctx, "default").Return(&models.MessageMapping{
+				WhatsAppChatID: "15550001111@c.us",
+				WhatsAppMsgID:  "wa-1",
+				SessionName:    "default",
+				ForwardedAt:    time.Now(),
+			}, nil)
+			db.On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Return(nil).Maybe()
+
+			var sentTo string
+			bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+				sentTo = chatID
+				return &types.SendMessageResponse{MessageID: "wa-reply", Status: "sent"}, nil
+			}
+
+			msg := &signaltypes.SignalMessage{
+				MessageID: "sig-1",
+				Sender:    "+1234567890",
+				Message:   "quick reply",
+				Timestamp: time.Now().UnixMilli(),
+			}
+			err := bridge.HandleSignalMessage(ctx, msg)
+
+			if !tt.relayed {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "cannot start new conversations")
+				assert.Empty(t, sentTo)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "15550001111@c.us", sentTo, "the reply goes to the chat of the message being saved")
+		})
+	}
+}
+
+func TestAwaitLatestMapping_StopsWithContext(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	bridge.signalConfig.NewThreadGraceMs = 5000
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mapping, err := bridge.awaitLatestMapping(ctx, "default")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, mapping)
+}
+
+func TestAwaitLatestMapping_GraceExpiresByClock(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	clock := testutil.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	bridge.clock = clock
+	// Far longer than the test runs; only the fake clock can make it pass
+	bridge.signalConfig.NewThreadGraceMs = int((10 * time.Minute).Milliseconds())
+
+	ctx := context.Background()
+	db := bridge.db.(*mockDatabaseService)
+	db.On("GetLatestMessageMappingBySession", ctx, "default").Return(nil, nil).Run(func(mock.Arguments) {
+		clock.Advance(4 * time.Minute)
+	})
+
+	mapping, err := bridge.awaitLatestMapping(ctx, "default")
+	require.NoError(t, err)
+	assert.Nil(t, mapping)
+	db.AssertNumberOfCalls(t, "GetLatestMessageMappingBySession", 3)
+}
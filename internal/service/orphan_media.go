@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"whatsignal/internal/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OrphanMediaPurger removes cached media that no message mapping refers to
+type OrphanMediaPurger interface {
+	PurgeOrphanedMedia(ctx context.Context, gracePeriod time.Duration) (int, error)
+}
+
+// PurgeOrphanedMedia deletes the files of the media cache that are not the media path of any
+// message mapping and were last used longer than gracePeriod ago, e.g. media of messages whose
+// relay failed or whose mapping was cleaned up earlier than the file. It returns the number of
+// files deleted.
+func (b *bridge) PurgeOrphanedMedia(ctx context.Context, gracePeriod time.Duration) (int, error) {
+	referenced, err := b.db.GetMediaPaths(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get referenced media paths: %w", err)
+	}
+
+	removed, err := b.media.RemoveUnreferencedFiles(referenced, gracePeriod)
+	if removed > 0 {
+		metrics.AddToCounter("media_orphans_purged_total", float64(removed), nil, "Cached media files deleted because no message mapping referred to them")
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"removed":     removed,
+			"gracePeriod": gracePeriod.String(),
+		}).Info("Purged orphaned media files")
+	}
+	if err != nil {
+		return removed, fmt.Errorf("failed to purge orphaned media files: %w", err)
+	}
+	return removed, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"whatsignal/pkg/media"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeOrphanedMedia(t *testing.T) {
+	b, tmpDir, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	cacheDir := filepath.Join(tmpDir, "orphan-cache")
+	handler, err := media.NewHandler(cacheDir, b.mediaConfig)
+	require.NoError(t, err)
+	b.media = handler
+
+	oldTime := time.Now().Add(-48 * time.Hour)
+	seed := func(name string, modTime time.Time) string {
+		path := filepath.Join(cacheDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+		return path
+	}
+	referenced := seed("referenced.jpg", oldTime)
+	orphaned := seed("orphaned.jpg", oldTime)
+	orphanedDocument := seed("orphaned.pdf", oldTime)
+	justCached := seed("just-cached.jpg", time.Now())
+
+	ctx := context.Background()
+	b.db.(*mockDatabaseService).On("GetMediaPaths", ctx).Return([]string{referenced}, nil)
+
+	removed, err := b.PurgeOrphanedMedia(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	assert.FileExists(t, referenced)
+	assert.FileExists(t, justCached, "files within the grace period are kept")
+	assert.NoFileExists(t, orphaned)
+	assert.NoFileExists(t, orphanedDocument)
+}
+
+func TestPurgeOrphanedMedia_KeepsFilesWhenPathsUnavailable(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	b.db.(*mockDatabaseService).On("GetMediaPaths", ctx).Return(nil, assert.AnError)
+
+	removed, err := b.PurgeOrphanedMedia(ctx, time.Hour)
+	require.Error(t, err)
+	assert.Zero(t, removed)
+	b.media.(*mockMediaHandler).AssertNotCalled(t, "RemoveUnreferencedFiles", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"strings"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/models"
+	"whatsignal/internal/validation"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// orphanReplyMapping derives the WhatsApp chat for a Signal reply whose quoted message has no
+// stored mapping, using the quoted author's phone number. Quotes of our own numbers (the
+// intermediary or a channel destination) cannot identify a chat and yield nil.
+func (b *bridge) orphanReplyMapping(msg *signaltypes.SignalMessage, sessionName string) *models.MessageMapping {
+	if msg.QuotedMessage == nil {
+		return nil
+	}
+
+	author := strings.TrimSpace(msg.QuotedMessage.Author)
+	if validation.ValidateE164PhoneNumber(author) != nil {
+		return nil
+	}
+	if author == b.signalConfig.IntermediaryPhoneNumber || b.channelManager.IsValidDestination(author) {
+		return nil
+	}
+
+	chatID := strings.TrimPrefix(author, "+") + "@c.us"
+	b.logger.WithFields(logrus.Fields{
+		LogFieldSession: sessionName,
+		LogFieldChatID:  SanitizePhoneNumber(chatID),
+		"signal_msg_id": SanitizeMessageID(msg.MessageID),
+	}).Info("Relaying orphan Signal reply to the quoted author's chat")
+
+	return &models.MessageMapping{
+		WhatsAppChatID: chatID,
+		SessionName:    sessionName,
+	}
+}
+
+// withQuoteContext prefixes text with the quoted message as "> " block quote lines, so the
+// recipient still sees what is being replied to when no native reply can be made
+func withQuoteContext(quoted, text string) string {
+	quoted = strings.TrimSpace(quoted)
+	if quoted == "" {
+		return text
+	}
+
+	if runes := []rune(quoted); len(runes) > constants.MaxOrphanReplyQuoteLength {
+		quoted = strings.TrimSpace(string(runes[:constants.MaxOrphanReplyQuoteLength])) + "…"
+	}
+
+	lines := strings.Split(quoted, "\n")
+	for i, line := range lines {
+		lines[i] = "> " + line
+	}
+	if strings.TrimSpace(text) == "" {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines, "\n") + "\n\n" + text
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithQuoteContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		quoted   string
+		text     string
+		expected string
+	}{
+		{"single line", "see you at 5", "sounds good", "> see you at 5\n\nsounds good"},
+		{"multiline quote", "line one\nline two", "ok", "> line one\n> line two\n\nok"},
+		{"empty quote", "  ", "ok", "ok"},
+		{"empty text", "photo caption", "", "> photo caption"},
+		{"long quote truncated", strings.Repeat("a", 250), "ok", "> " + strings.Repeat("a", 200) + "…\n\nok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, withQuoteContext(tt.quoted, tt.text))
+		})
+	}
+}
+
+func orphanReplyMessage(author string) *signaltypes.SignalMessage {
+	return &signaltypes.SignalMessage{
+		MessageID: "sig_reply",
+		Sender:    "+1234567890",
+		Message:   "sounds good",
+		Timestamp: time.Now().UnixMilli(),
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{
+			ID:     "unknown_quote",
+			Author: author,
+			Text:   "see you at 5",
+		},
+	}
+}
+
+// setupOrphanReplyBridge returns a bridge with orphan reply relaying enabled that records the
+// chat and text of WhatsApp sends
+func setupOrphanReplyBridge(t *testing.T) (*bridge, *string, *string, func()) {
+	bridge, _, cleanup := setupTestBridge(t)
+	bridge.signalConfig.RelayOrphanReplies = true
+	bridge.signalConfig.IntermediaryPhoneNumber = "+1999999999"
+
+	mockDB := bridge.db.(*mockDatabaseService)
+	mockDB.On("GetMessageMapping", mock.Anything, "unknown_quote").Return(nil, nil)
+	mockDB.On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+
+	var sentChat, sentText string
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		sentChat, sentText = chatID, text
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+
+	return bridge, &sentChat, &sentText, cleanup
+}
+
+func TestOrphanReplyRelayedToQuotedAuthor(t *testing.T) {
+	bridge, sentChat, sentText, cleanup := setupOrphanReplyBridge(t)
+	defer cleanup()
+
+	err := bridge.HandleSignalMessage(context.Background(), orphanReplyMessage("+15550001111"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "15550001111@c.us", *sentChat)
+	assert.Equal(t, "> see you at 5\n\nsounds good", *sentText)
+	bridge.db.(*mockDatabaseService).AssertCalled(t, "SaveMessageMapping", mock.Anything, mock.MatchedBy(func(m *models.MessageMapping) bool {
+		return m.WhatsAppChatID == "15550001111@c.us" && m.SignalMsgID == "sig_reply"
+	}))
+}
+
+func TestOrphanReplyNotRelayed(t *testing.T) {
+	tests := []struct {
+		name    string
+		author  string
+		enabled bool
+	}{
+		{"disabled", "+15550001111", false},
+		{"quoted intermediary number", "+1999999999", true},
+		{"quoted own destination", "+1234567890", true},
+		{"author is not a phone number", "3f1c9a2e-uuid", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, sentChat, _, cleanup := setupOrphanReplyBridge(t)
+			defer cleanup()
+			bridge.signalConfig.RelayOrphanReplies = tt.enabled
+
+			err := bridge.HandleSignalMessage(context.Background(), orphanReplyMessage(tt.author))
+			assert.Error(t, err)
+			assert.Empty(t, *sentChat)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"whatsignal/pkg/media"
+
+	"github.com/sirupsen/logrus"
+)
+
+// handleOversizeAttachment tries to compress an attachment that is over the size limit. It
+// returns the cached path of the compressed copy, or a note for the message text when the
+// attachment has to be dropped.
+func (b *bridge) handleOversizeAttachment(ctx context.Context, attachment string, oversize *media.OversizeError, sessionName string) (string, string) {
+	if b.compressor != nil {
+		compressed, err := b.compressor.Compress(ctx, attachment, oversize.Limit)
+		if err == nil {
+			defer func() { _ = os.Remove(compressed) }()
+			var processed string
+			if processed, err = b.processMediaForSession(ctx, compressed, sessionName, "signal_to_whatsapp"); err == nil {
+				b.logger.WithContext(ctx).WithFields(logrus.Fields{
+					"mediaType": oversize.MediaType,
+					"size":      oversize.Size,
+					"limit":     oversize.Limit,
+				}).Info("Compressed oversized Signal attachment to fit WhatsApp limits")
+				return processed, ""
+			}
+		}
+		b.logger.WithContext(ctx).WithError(err).WithField("mediaType", oversize.MediaType).Warn("Failed to compress oversized Signal attachment, dropping it")
+	} else {
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"mediaType": oversize.MediaType,
+			"size":      oversize.Size,
+			"limit":     oversize.Limit,
+		}).Info("Dropping oversized Signal attachment")
+	}
+
+	return "", oversizeNote(oversize.Size)
+}
+
+// oversizeNote is the text sent in place of a dropped attachment, with its size rounded up to MB
+func oversizeNote(size int64) string {
+	const bytesPerMB = 1024 * 1024
+	return fmt.Sprintf("[attachment too large, %d MB]", (size+bytesPerMB-1)/bytesPerMB)
+}
+
+// withAttachmentNotes appends notes for dropped attachments to the message text
+func withAttachmentNotes(text string, notes []string) string {
+	if len(notes) == 0 {
+		return text
+	}
+	joined := strings.Join(notes, "\n")
+	if strings.TrimSpace(text) == "" {
+		return joined
+	}
+	return text + "\n" + joined
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	"whatsignal/pkg/media"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testMB = 1024 * 1024
+
+// fakeCompressor returns a fixed file in place of running a real tool
+type fakeCompressor struct {
+	output   string
+	err      error
+	maxBytes int64
+}
+
+func (f *fakeCompressor) Compress(ctx context.Context, path string, maxBytes int64) (string, error) {
+	f.maxBytes = maxBytes
+	return f.output, f.err
+}
+
+func oversizeTestMessage(attachment string) *signaltypes.SignalMessage {
+	return &signaltypes.SignalMessage{
+		MessageID:   "sig_big_1",
+		Sender:      "group.123",
+		Message:     "look",
+		Timestamp:   time.Now().UnixMilli(),
+		Attachments: []string{attachment},
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{
+			ID: "wa_msg_1",
+		},
+	}
+}
+
+func expectOversizeMapping(bridge *bridge, ctx context.Context) {
+	mapping := &models.MessageMapping{
+		WhatsAppChatID: "group123@g.us",
+		WhatsAppMsgID:  "wa_msg_1",
+		SignalMsgID:    "sig_orig",
+		ForwardedAt:    time.Now(),
+	}
+	bridge.db.(*mockDatabaseService).On("GetMessageMapping", ctx, "wa_msg_1").Return(mapping, nil).Once()
+	bridge.db.(*mockDatabaseService).On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Return(nil).Once()
+}
+
+func TestOversizeNote(t *testing.T) {
+	assert.Equal(t, "[attachment too large, 1 MB]", oversizeNote(1))
+	assert.Equal(t, "[attachment too large, 23 MB]", oversizeNote(23*testMB))
+	assert.Equal(t, "[attachment too large, 24 MB]", oversizeNote(23*testMB+1))
+}
+
+func TestWithAttachmentNotes(t *testing.T) {
+	assert.Equal(t, "hi", withAttachmentNotes("hi", nil))
+	assert.Equal(t, "[a]", withAttachmentNotes("  ", []string{"[a]"}))
+	assert.Equal(t, "hi\n[a]\n[b]", withAttachmentNotes("hi", []string{"[a]", "[b]"}))
+}
+
+func TestHandleSignalMessage_OversizeAttachmentAddsNote(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	expectOversizeMapping(bridge, ctx)
+
+	bridge.media.(*mockMediaHandler).On("ProcessMedia", "/tmp/big.jpg").
+		Return("", &media.OversizeError{MediaType: "image", Size: 23*testMB + 1, Limit: 5 * testMB}).Once()
+
+	waClient := bridge.waClient.(*mockWhatsAppClient)
+	var sentText string
+	waClient.sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		sentText = text
+		return &types.SendMessageResponse{MessageID: "wa_msg_reply", Status: "sent"}, nil
+	}
+
+	err := bridge.HandleSignalMessage(ctx, oversizeTestMessage("/tmp/big.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "look\n[attachment too large, 24 MB]", sentText)
+	bridge.media.(*mockMediaHandler).AssertExpectations(t)
+}
+
+func TestHandleSignalMessage_OversizeAttachmentCompressed(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	expectOversizeMapping(bridge, ctx)
+
+	compressed := filepath.Join(t.TempDir(), "small.jpg")
+	require.NoError(t, os.WriteFile(compressed, []byte("small"), 0600))
+	compressor := &fakeCompressor{output: compressed}
+	bridge.compressor = compressor
+
+	mediaHandler := bridge.media.(*mockMediaHandler)
+	mediaHandler.On("ProcessMedia", "/tmp/big.jpg").
+		Return("", &media.OversizeError{MediaType: "image", Size: 23 * testMB, Limit: 5 * testMB}).Once()
+	mediaHandler.On("ProcessMedia", compressed).Return("/cache/small.jpg", nil).Once()
+
+	waClient := bridge.waClient.(*mockWhatsAppClient)
+	waClient.On("SendImageWithSession", ctx, "group123@g.us", "/cache/small.jpg", "look", "wa_msg_1", "default").
+		Return(&types.SendMessageResponse{MessageID: "wa_msg_reply", Status: "sent"}, nil).Once()
+
+	err := bridge.HandleSignalMessage(ctx, oversizeTestMessage("/tmp/big.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5*testMB), compressor.maxBytes)
+	assert.NoFileExists(t, compressed)
+	mediaHandler.AssertExpectations(t)
+	waClient.AssertExpectations(t)
+}
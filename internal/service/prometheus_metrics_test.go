@@ -0,0 +1,57 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"whatsignal/internal/metrics"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeSample returns the value of the sample named series in the Prometheus exposition of the
+// global registry, or 0 when it is absent
+func scrapeSample(t *testing.T, series string) float64 {
+	t.Helper()
+	var out strings.Builder
+	require.NoError(t, metrics.GetAllMetrics().WritePrometheus(&out))
+
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), " ")
+		if ok && name == series {
+			v, err := strconv.ParseFloat(value, 64)
+			require.NoError(t, err)
+			return v
+		}
+	}
+	return 0
+}
+
+func TestHandleWhatsAppMessage_MovesPrometheusCounters(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	bridge.db.(*mockDatabaseService).On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	bridge.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-1", Timestamp: time.Now().UnixMilli()}
+
+	const relayed = `whatsignal_message_processing_success{direction="whatsapp_to_signal",has_media="false",session="default"}`
+	const attempted = `whatsignal_message_processing_total{direction="whatsapp_to_signal",has_media="false",session="default"}`
+	relayedBefore := scrapeSample(t, relayed)
+	attemptedBefore := scrapeSample(t, attempted)
+
+	for _, id := range []string{"prom-msg-1", "prom-msg-2"} {
+		err := bridge.HandleWhatsAppMessageWithSession(context.Background(), "default", "15550001111@c.us", id, "15550001111@c.us", "", "hi", "")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, relayedBefore+2, scrapeSample(t, relayed))
+	assert.Equal(t, attemptedBefore+2, scrapeSample(t, attempted))
+}
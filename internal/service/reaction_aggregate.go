@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// reactionTally holds the current reactions to one bridged WhatsApp message
+type reactionTally struct {
+	sessionName string
+	mapping     *models.MessageMapping
+	fromMe      bool
+	// reactors maps each reactor's WhatsApp ID to their emoji; WhatsApp allows one reaction per
+	// person, so a new one replaces the old and a removal deletes it
+	reactors map[string]string
+	// firstSeen orders emojis with equal counts by when they first appeared
+	firstSeen map[string]int
+	seen      int
+	updatedAt time.Time
+	// announced is set once an update for the message was sent to Signal
+	announced bool
+	// ctx and timer are set while an update is waiting for the window to pass
+	ctx   context.Context
+	timer *time.Timer
+}
+
+// reactionAggregator relays WhatsApp reactions as a running tally per message, such as
+// "Reactions: 👍 x3  ❤️ x1". The first reaction to a message starts a window; reactions to the
+// same message within it are folded into the one update sent when it passes. Tallies outlive
+// the window so later updates show the totals, and at most maxTargets messages are tracked.
+type reactionAggregator struct {
+	mu         sync.Mutex
+	window     time.Duration
+	maxTargets int
+	tallies    map[string]*reactionTally
+	send       func(ctx context.Context, tally *reactionTally, counts []reactionCount) error
+	logger     *logrus.Logger
+	clock      Clock
+}
+
+// reactionCount is the number of people who reacted with one emoji
+type reactionCount struct {
+	emoji string
+	count int
+}
+
+func newReactionAggregator(window time.Duration, maxTargets int, send func(context.Context, *reactionTally, []reactionCount) error, logger *logrus.Logger, clock Clock) *reactionAggregator {
+	return &reactionAggregator{
+		window:     window,
+		maxTargets: maxTargets,
+		tallies:    make(map[string]*reactionTally),
+		send:       send,
+		logger:     logger,
+		clock:      clock,
+	}
+}
+
+// add records a reaction to the message in mapping and schedules an update for it
+func (a *reactionAggregator) add(ctx context.Context, sessionName string, reaction WhatsAppReaction, mapping *models.MessageMapping) {
+	key := sessionName + "|" + mapping.WhatsAppMsgID
+
+	a.mu.Lock()
+	var evicted []*reactionTally
+	tally := a.tallies[key]
+	if tally == nil {
+		evicted = a.evictLocked()
+		tally = &reactionTally{
+			sessionName: sessionName,
+			mapping:     mapping,
+			fromMe:      reaction.FromMe,
+			reactors:    make(map[string]string),
+			firstSeen:   make(map[string]int),
+		}
+		a.tallies[key] = tally
+	}
+
+	if reaction.Emoji == "" {
+		delete(tally.reactors, reaction.ReactorID)
+	} else {
+		tally.reactors[reaction.ReactorID] = reaction.Emoji
+		if _, ok := tally.firstSeen[reaction.Emoji]; !ok {
+			tally.firstSeen[reaction.Emoji] = tally.seen
+			tally.seen++
+		}
+	}
+	tally.updatedAt = a.clock.Now()
+
+	if tally.timer == nil {
+		// The webhook request finishes before the timer fires
+		tally.ctx = context.WithoutCancel(ctx)
+		tally.timer = time.AfterFunc(a.window, func() { a.flush(key, tally) })
+	}
+	a.mu.Unlock()
+
+	for _, old := range evicted {
+		a.announce(old.ctx, old, reactionCounts(old))
+	}
+}
+
+// evictLocked makes room for one more message by dropping the least recently updated tallies.
+// It returns those with a pending update, which the caller sends once the lock is released.
+func (a *reactionAggregator) evictLocked() []*reactionTally {
+	var pending []*reactionTally
+	for a.maxTargets > 0 && len(a.tallies) >= a.maxTargets {
+		var oldestKey string
+		var oldest *reactionTally
+		for k, tally := range a.tallies {
+			if oldest == nil || tally.updatedAt.Before(oldest.updatedAt) {
+				oldestKey, oldest = k, tally
+			}
+		}
+		delete(a.tallies, oldestKey)
+		if oldest.timer != nil {
+			oldest.timer.Stop()
+			oldest.timer = nil
+			pending = append(pending, oldest)
+		}
+	}
+	return pending
+}
+
+// flush sends the update for a tally whose window has passed
+func (a *reactionAggregator) flush(key string, tally *reactionTally) {
+	a.mu.Lock()
+	if a.tallies[key] != tally || tally.timer == nil {
+		// Evicted and sent already
+		a.mu.Unlock()
+		return
+	}
+	tally.timer = nil
+	ctx := tally.ctx
+	counts := reactionCounts(tally)
+	a.mu.Unlock()
+
+	a.announce(ctx, tally, counts)
+}
+
+// announce sends an update, skipping one for a message whose reactions were all removed before
+// any were announced. Errors can only be logged: the webhooks that delivered the reactions have
+// already been answered.
+func (a *reactionAggregator) announce(ctx context.Context, tally *reactionTally, counts []reactionCount) {
+	a.mu.Lock()
+	if len(counts) == 0 && !tally.announced {
+		a.mu.Unlock()
+		return
+	}
+	tally.announced = true
+	a.mu.Unlock()
+
+	if err := a.send(ctx, tally, counts); err != nil {
+		a.logger.WithError(err).WithFields(logrus.Fields{
+			LogFieldSession:   tally.sessionName,
+			LogFieldMessageID: SanitizeWhatsAppMessageID(tally.mapping.WhatsAppMsgID),
+		}).Error("Failed to relay aggregated WhatsApp reactions")
+	}
+}
+
+// reactionCounts counts the reactors per emoji, most popular first. Callers hold the lock or
+// own the tally.
+func reactionCounts(tally *reactionTally) []reactionCount {
+	byEmoji := make(map[string]int)
+	for _, emoji := range tally.reactors {
+		byEmoji[emoji]++
+	}
+	counts := make([]reactionCount, 0, len(byEmoji))
+	for emoji, count := range byEmoji {
+		counts = append(counts, reactionCount{emoji: emoji, count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return tally.firstSeen[counts[i].emoji] < tally.firstSeen[counts[j].emoji]
+	})
+	return counts
+}
+
+// formatReactionTally renders an aggregated update; an empty tally means every reaction was removed
+func formatReactionTally(counts []reactionCount, snippet string) string {
+	text := "Reactions removed"
+	if len(counts) > 0 {
+		parts := make([]string, len(counts))
+		for i, c := range counts {
+			parts[i] = fmt.Sprintf("%s x%d", c.emoji, c.count)
+		}
+		text = "Reactions: " + strings.Join(parts, "  ")
+	}
+	if snippet == "" {
+		return text
+	}
+	return fmt.Sprintf("%s on: \"%s\"", text, snippet)
+}
+
+// sendReactionTally relays an aggregated update to the session's Signal destination, quoting
+// the reacted message where it can
+func (b *bridge) sendReactionTally(ctx context.Context, tally *reactionTally, counts []reactionCount) error {
+	dest, err := b.channelManager.GetSignalDestination(tally.sessionName)
+	if err != nil {
+		return fmt.Errorf("failed to get Signal destination for session %s: %w", tally.sessionName, err)
+	}
+
+	var opts []signaltypes.SendOption
+	if quoteOpt := b.mappingQuoteOption(tally.sessionName, dest, tally.mapping, tally.fromMe, ""); quoteOpt != nil {
+		opts = append(opts, quoteOpt)
+	}
+
+	text := formatReactionTally(counts, b.reactionSnippet(tally.mapping))
+	if _, err := b.sigClient.SendMessage(ctx, dest, text, []string{}, opts...); err != nil {
+		return fmt.Errorf("failed to send reactions to Signal: %w", err)
+	}
+	return nil
+}
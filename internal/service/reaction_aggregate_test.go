@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sentTally struct {
+	msgID  string
+	counts []reactionCount
+}
+
+// newTestReactionAggregator returns an aggregator that reports each update on the channel
+func newTestReactionAggregator(window time.Duration, maxTargets int) (*reactionAggregator, chan sentTally) {
+	sent := make(chan sentTally, 10)
+	aggregator := newReactionAggregator(window, maxTargets, func(ctx context.Context, tally *reactionTally, counts []reactionCount) error {
+		sent <- sentTally{msgID: tally.mapping.WhatsAppMsgID, counts: counts}
+		return nil
+	}, logrus.New(), RealClock)
+	return aggregator, sent
+}
+
+func waitForTally(t *testing.T, sent chan sentTally) sentTally {
+	t.Helper()
+	select {
+	case tally := <-sent:
+		return tally
+	case <-time.After(time.Second):
+		t.Fatal("no reaction update was sent")
+		return sentTally{}
+	}
+}
+
+func TestReactionAggregator_FoldsReactionsInWindow(t *testing.T) {
+	aggregator, sent := newTestReactionAggregator(30*time.Millisecond, 10)
+	mapping := &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_photo"}
+
+	ctx := context.Background()
+	aggregator.add(ctx, "default", WhatsAppReaction{ReactorID: "alice", Emoji: "❤️"}, mapping)
+	aggregator.add(ctx, "default", WhatsAppReaction{ReactorID: "bob", Emoji: "👍"}, mapping)
+	aggregator.add(ctx, "default", WhatsAppReaction{ReactorID: "carol", Emoji: "👍"}, mapping)
+	aggregator.add(ctx, "default", WhatsAppReaction{ReactorID: "dave", Emoji: "👍"}, mapping)
+
+	tally := waitForTally(t, sent)
+	assert.Equal(t, "wa_photo", tally.msgID)
+	assert.Equal(t, []reactionCount{{emoji: "👍", count: 3}, {emoji: "❤️", count: 1}}, tally.counts)
+	assert.Equal(t, "Reactions: 👍 x3  ❤️ x1", formatReactionTally(tally.counts, ""))
+
+	select {
+	case extra := <-sent:
+		t.Fatalf("expected one update, got another: %+v", extra)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestReactionAggregator_ChangesAndRemovals(t *testing.T) {
+	aggregator, sent := newTestReactionAggregator(20*time.Millisecond, 10)
+	mapping := &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_text"}
+
+	ctx := context.Background()
+	aggregator.add(ctx, "default", WhatsAppReaction{ReactorID: "alice", Emoji: "👍"}, mapping)
+	aggregator.add(ctx, "default", WhatsAppReaction{ReactorID: "bob", Emoji: "👍"}, mapping)
+	aggregator.add(ctx, "default", WhatsAppReaction{ReactorID: "alice", Emoji: "😂"}, mapping)
+	tally := waitForTally(t, sent)
+	assert.Equal(t, []reactionCount{{emoji: "👍", count: 1}, {emoji: "😂", count: 1}}, tally.counts, "a changed reaction replaces the old one")
+
+	// A later window reports the running totals
+	aggregator.add(ctx, "default", WhatsAppReaction{ReactorID: "bob"}, mapping)
+	tally = waitForTally(t, sent)
+	assert.Equal(t, []reactionCount{{emoji: "😂", count: 1}}, tally.counts)
+
+	aggregator.add(ctx, "default", WhatsAppReaction{ReactorID: "alice"}, mapping)
+	tally = waitForTally(t, sent)
+	assert.Empty(t, tally.counts, "removing the last reaction of an announced message is reported")
+}
+
+func TestReactionAggregator_RemovedBeforeAnnouncedIsSilent(t *testing.T) {
+	aggregator, sent := newTestReactionAggregator(20*time.Millisecond, 10)
+	mapping := &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_text"}
+
+	aggregator.add(context.Background(), "default", WhatsAppReaction{ReactorID: "alice", Emoji: "👍"}, mapping)
+	aggregator.add(context.Background(), "default", WhatsAppReaction{ReactorID: "alice"}, mapping)
+
+	select {
+	case tally := <-sent:
+		t.Fatalf("expected no update, got %+v", tally)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestReactionAggregator_EvictionSendsPendingUpdate(t *testing.T) {
+	aggregator, sent := newTestReactionAggregator(time.Hour, 1)
+	first := &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_first"}
+	second := &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_second"}
+
+	aggregator.add(context.Background(), "default", WhatsAppReaction{ReactorID: "alice", Emoji: "👍"}, first)
+	aggregator.add(context.Background(), "default", WhatsAppReaction{ReactorID: "alice", Emoji: "🎉"}, second)
+
+	tally := waitForTally(t, sent)
+	assert.Equal(t, "wa_first", tally.msgID, "the evicted message's update is sent straight away")
+	assert.Equal(t, []reactionCount{{emoji: "👍", count: 1}}, tally.counts)
+
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	assert.Len(t, aggregator.tallies, 1)
+	aggregator.tallies["default|wa_second"].timer.Stop()
+}
+
+func TestFormatReactionTally(t *testing.T) {
+	assert.Equal(t, `Reactions: 🔥 x2 on: "[image]"`, formatReactionTally([]reactionCount{{emoji: "🔥", count: 2}}, "[image]"))
+	assert.Equal(t, "Reactions removed", formatReactionTally(nil, ""))
+}
+
+func TestHandleWhatsAppReaction_Aggregated(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	bridge.signalConfig.IntermediaryPhoneNumber = "+1999999999"
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-tally", Timestamp: time.Now().UnixMilli()}
+
+	sends := make(chan struct{}, 10)
+	bridge.reactionTallies = newReactionAggregator(30*time.Millisecond, 10, func(ctx context.Context, tally *reactionTally, counts []reactionCount) error {
+		err := bridge.sendReactionTally(ctx, tally, counts)
+		sends <- struct{}{}
+		return err
+	}, bridge.logger, bridge.clock)
+
+	mapping := &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_photo", SignalMsgID: "1700000000123", MediaType: "image", SessionName: "default"}
+	for _, reaction := range []WhatsAppReaction{
+		{ReactorID: "+15550001111@c.us", Emoji: "👍"},
+		{ReactorID: "+15550002222@c.us", Emoji: "👍"},
+		{ReactorID: "+15550003333@c.us", Emoji: "👍"},
+		{ReactorID: "+15550004444@c.us", Emoji: "❤️"},
+	} {
+		require.NoError(t, bridge.HandleWhatsAppReaction(context.Background(), "default", reaction, mapping))
+	}
+
+	select {
+	case <-sends:
+	case <-time.After(time.Second):
+		t.Fatal("no aggregated reaction was sent")
+	}
+	assert.Equal(t, "+1234567890", sigClient.lastRecipient)
+	assert.Equal(t, `Reactions: 👍 x3  ❤️ x1 on: "[image]"`, sigClient.lastMessage)
+	assert.Equal(t, int64(1700000000123), sigClient.lastSendOptions.QuoteTimestamp)
+
+	select {
+	case <-sends:
+		t.Fatal("the reactions were expected as one message")
+	case <-time.After(60 * time.Millisecond):
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	signaltypes "whatsignal/pkg/signal/types"
+)
+
+// recentReactionCache remembers which Signal reactions were recently relayed, so a reaction
+// that signal-cli delivers twice is only sent to WhatsApp once. Reactions do not create
+// message mappings, so the general message dedup does not catch them.
+type recentReactionCache struct {
+	mu         sync.Mutex
+	window     time.Duration
+	maxEntries int
+	entries    map[string]time.Time
+	clock      Clock
+}
+
+func newRecentReactionCache(window time.Duration, maxEntries int, clock Clock) *recentReactionCache {
+	return &recentReactionCache{
+		window:     window,
+		maxEntries: maxEntries,
+		entries:    make(map[string]time.Time),
+		clock:      clock,
+	}
+}
+
+// claim reports whether the reaction should be relayed, recording it if so. A reaction claimed
+// within the window is a duplicate. Checking and recording together keeps parallel poll workers
+// from both relaying the same reaction.
+func (c *recentReactionCache) claim(key string) bool {
+	if c == nil {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	if claimedAt, ok := c.entries[key]; ok && now.Sub(claimedAt) < c.window {
+		return false
+	}
+
+	for k, claimedAt := range c.entries {
+		if now.Sub(claimedAt) >= c.window {
+			delete(c.entries, k)
+		}
+	}
+
+	for c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, claimedAt := range c.entries {
+			if oldestKey == "" || claimedAt.Before(oldest) {
+				oldestKey, oldest = k, claimedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+
+	c.entries[key] = now
+	return true
+}
+
+// release forgets a claimed reaction whose relay failed, so a redelivery can retry it
+func (c *recentReactionCache) release(key string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// recentReactionKey identifies a reaction by session, target, emoji, sender and whether it is a removal
+func recentReactionKey(sessionName, sender string, reaction *signaltypes.SignalReaction) string {
+	return sessionName + "|" + strconv.FormatInt(reaction.TargetTimestamp, 10) + "|" + reaction.Emoji + "|" + sender + "|" + strconv.FormatBool(reaction.IsRemove)
+}
+
+// claimReaction claims a Signal reaction in the recent reaction cache and, with
+// signal.persistReactionDedup, also checks that it was not relayed before a restart. A
+// database error is logged and the cache decides alone.
+func (b *bridge) claimReaction(ctx context.Context, key string) bool {
+	if !b.recentReactions.claim(key) {
+		return false
+	}
+	if !b.persistReactions {
+		return true
+	}
+
+	seen, err := b.db.WasSeen(ctx, key)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to check stored reaction keys")
+		return true
+	}
+	return !seen
+}
+
+// rememberReaction stores a relayed reaction with signal.persistReactionDedup, for as long as
+// the dedup window
+func (b *bridge) rememberReaction(ctx context.Context, key string) {
+	if !b.persistReactions {
+		return
+	}
+	if err := b.db.RecordSeen(ctx, key, b.recentReactions.window); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to store reaction key")
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	"whatsignal/internal/testutil"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentReactionCache(t *testing.T) {
+	clock := testutil.NewFakeClock(time.Now())
+	cache := newRecentReactionCache(time.Minute, 10, clock)
+
+	assert.True(t, cache.claim("a"))
+	assert.False(t, cache.claim("a"), "same reaction within the window is a duplicate")
+	assert.True(t, cache.claim("b"))
+
+	clock.Advance(time.Minute)
+	assert.True(t, cache.claim("a"), "entries expire after the window")
+}
+
+func TestRecentReactionCacheRelease(t *testing.T) {
+	cache := newRecentReactionCache(time.Minute, 10, RealClock)
+
+	require.True(t, cache.claim("a"))
+	cache.release("a")
+	assert.True(t, cache.claim("a"), "a released reaction can be relayed again")
+}
+
+func TestRecentReactionCacheIsBounded(t *testing.T) {
+	clock := testutil.NewFakeClock(time.Now())
+	cache := newRecentReactionCache(time.Hour, 2, clock)
+
+	cache.claim("a")
+	clock.Advance(time.Second)
+	cache.claim("b")
+	clock.Advance(time.Second)
+	cache.claim("c")
+
+	assert.Len(t, cache.entries, 2)
+	assert.True(t, cache.claim("a"), "oldest entry is evicted")
+}
+
+func TestRecentReactionCacheNilIsDisabled(t *testing.T) {
+	var cache *recentReactionCache
+	assert.True(t, cache.claim("a"))
+	assert.True(t, cache.claim("a"))
+	cache.release("a")
+}
+
+func TestRecentReactionKey(t *testing.T) {
+	reaction := &signaltypes.SignalReaction{Emoji: "👍", TargetTimestamp: 1234567890000}
+	key := recentReactionKey("default", "+1234567890", reaction)
+
+	removal := *reaction
+	removal.IsRemove = true
+	otherEmoji := *reaction
+	otherEmoji.Emoji = "❤️"
+	otherTarget := *reaction
+	otherTarget.TargetTimestamp++
+
+	assert.Equal(t, key, recentReactionKey("default", "+1234567890", &signaltypes.SignalReaction{Emoji: "👍", TargetTimestamp: 1234567890000}))
+	assert.NotEqual(t, key, recentReactionKey("default", "+1234567890", &removal))
+	assert.NotEqual(t, key, recentReactionKey("default", "+1234567890", &otherEmoji))
+	assert.NotEqual(t, key, recentReactionKey("default", "+1234567890", &otherTarget))
+	assert.NotEqual(t, key, recentReactionKey("default", "+1987654321", reaction))
+	assert.NotEqual(t, key, recentReactionKey("other", "+1234567890", reaction))
+}
+
+func newReactionDedupTestBridge(t *testing.T) (*bridge, *mockWhatsAppClient, context.Context) {
+	t.Helper()
+	b, _, cleanup := setupTestBridge(t)
+	t.Cleanup(cleanup)
+	b.recentReactions = newRecentReactionCache(time.Minute, 10, RealClock)
+
+	ctx := context.Background()
+	b.db.(*mockDatabaseService).On("GetMessageMapping", ctx, "1234567890000").Return(&models.MessageMapping{
+		WhatsAppChatID: "chat123@c.us",
+		WhatsAppMsgID:  "wa_msg456",
+		SignalMsgID:    "1234567890000",
+	}, nil)
+	return b, b.waClient.(*mockWhatsAppClient), ctx
+}
+
+func newDedupTestReaction(emoji string, isRemove bool) *signaltypes.SignalMessage {
+	return &signaltypes.SignalMessage{
+		MessageID: "msg123",
+		Sender:    "+1234567890",
+		Timestamp: time.Now().UnixMilli(),
+		Reaction: &signaltypes.SignalReaction{
+			Emoji:           emoji,
+			TargetTimestamp: 1234567890000,
+			IsRemove:        isRemove,
+		},
+	}
+}
+
+func TestHandleSignalReaction_SkipsDuplicate(t *testing.T) {
+	b, waClient, ctx := newReactionDedupTestBridge(t)
+	waClient.On("SendReactionWithSession", ctx, "chat123@c.us", "wa_msg456", "👍", "default").
+		Return(&types.SendMessageResponse{MessageID: "reaction"}, nil).Once()
+
+	require.NoError(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", false), "default"))
+	require.NoError(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", false), "default"))
+
+	waClient.AssertNumberOfCalls(t, "SendReactionWithSession", 1)
+}
+
+func TestHandleSignalReaction_RemovalIsNotADuplicate(t *testing.T) {
+	b, waClient, ctx := newReactionDedupTestBridge(t)
+	waClient.On("SendReactionWithSession", ctx, "chat123@c.us", "wa_msg456", "👍", "default").
+		Return(&types.SendMessageResponse{MessageID: "reaction"}, nil).Once()
+	waClient.On("SendReactionWithSession", ctx, "chat123@c.us", "wa_msg456", "", "default").
+		Return(&types.SendMessageResponse{MessageID: "removal"}, nil).Once()
+
+	require.NoError(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", false), "default"))
+	require.NoError(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", true), "default"))
+
+	waClient.AssertExpectations(t)
+}
+
+func TestHandleSignalReaction_RetriesAfterFailedSend(t *testing.T) {
+	b, waClient, ctx := newReactionDedupTestBridge(t)
+	waClient.On("SendReactionWithSession", ctx, "chat123@c.us", "wa_msg456", "👍", "default").
+		Return(nil, assert.AnError).Once()
+	waClient.On("SendReactionWithSession", ctx, "chat123@c.us", "wa_msg456", "👍", "default").
+		Return(&types.SendMessageResponse{MessageID: "reaction"}, nil).Once()
+
+	require.Error(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", false), "default"))
+	require.NoError(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", false), "default"))
+
+	waClient.AssertNumberOfCalls(t, "SendReactionWithSession", 2)
+}
+
+func TestHandleSignalReaction_DedupDisabled(t *testing.T) {
+	b, waClient, ctx := newReactionDedupTestBridge(t)
+	b.recentReactions = nil
+	waClient.On("SendReactionWithSession", ctx, mock.Anything, mock.Anything, "👍", "default").
+		Return(&types.SendMessageResponse{MessageID: "reaction"}, nil)
+
+	require.NoError(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", false), "default"))
+	require.NoError(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", false), "default"))
+
+	waClient.AssertNumberOfCalls(t, "SendReactionWithSession", 2)
+}
+
+func TestHandleSignalReaction_PersistedDedupSurvivesRestart(t *testing.T) {
+	b, waClient, ctx := newReactionDedupTestBridge(t)
+	b.persistReactions = true
+	waClient.On("SendReactionWithSession", ctx, "chat123@c.us", "wa_msg456", "👍", "default").
+		Return(&types.SendMessageResponse{MessageID: "reaction"}, nil).Once()
+
+	require.NoError(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", false), "default"))
+
+	// A restart empties the in-memory cache; the stored key still catches the redelivery
+	b.recentReactions = newRecentReactionCache(time.Minute, 10, RealClock)
+	require.NoError(t, b.handleSignalReactionWithSession(ctx, newDedupTestReaction("👍", false), "default"))
+
+	waClient.AssertNumberOfCalls(t, "SendReactionWithSession", 1)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"whatsignal/internal/metrics"
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+)
+
+// heldReceipt is a Signal receipt waiting for the send it confirms to be recorded
+type heldReceipt struct {
+	msg    *signaltypes.SignalMessage
+	status string
+	heldAt time.Time
+}
+
+// receiptCorrelator matches Signal receipts to the messages relayed to Signal by their send
+// timestamp. signal-cli can report a receipt before the relay has stored the message's Signal
+// timestamp, so a receipt matching no mapping is held for ttl and replayed once the send is
+// recorded. At most maxEntries receipts are held; the oldest is dropped first.
+type receiptCorrelator struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	held       map[int64]heldReceipt
+	clock      Clock
+}
+
+func newReceiptCorrelator(ttl time.Duration, maxEntries int, clock Clock) *receiptCorrelator {
+	return &receiptCorrelator{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		held:       make(map[int64]heldReceipt),
+		clock:      clock,
+	}
+}
+
+// hold keeps msg, a receipt with the given status, until take is called for its target
+// timestamp. Of several receipts for one message, such as delivery then read, the most
+// advanced is kept.
+func (c *receiptCorrelator) hold(msg *signaltypes.SignalMessage, status string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	for target, receipt := range c.held {
+		if now.Sub(receipt.heldAt) > c.ttl {
+			delete(c.held, target)
+		}
+	}
+
+	target := msg.Receipt.TargetTimestamp
+	if existing, ok := c.held[target]; ok {
+		if !shouldUpdateDeliveryStatus(models.DeliveryStatus(existing.status), status) {
+			return
+		}
+	} else if c.maxEntries > 0 && len(c.held) >= c.maxEntries {
+		var oldestTarget int64
+		var oldest time.Time
+		for t, receipt := range c.held {
+			if oldest.IsZero() || receipt.heldAt.Before(oldest) {
+				oldestTarget, oldest = t, receipt.heldAt
+			}
+		}
+		delete(c.held, oldestTarget)
+	}
+	c.held[target] = heldReceipt{msg: msg, status: status, heldAt: now}
+}
+
+// take returns and forgets the receipt held for the message sent at timestamp, if any
+func (c *receiptCorrelator) take(timestamp int64) (*signaltypes.SignalMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	receipt, ok := c.held[timestamp]
+	if !ok {
+		return nil, false
+	}
+	delete(c.held, timestamp)
+	if c.clock.Now().Sub(receipt.heldAt) > c.ttl {
+		return nil, false
+	}
+	return receipt.msg, true
+}
+
+// relayedStatus returns the delivery status a message just accepted by signal-cli is recorded
+// with: sent when delivery receipts are awaited, delivered otherwise
+func (b *bridge) relayedStatus() models.DeliveryStatus {
+	if b.deliveryReceipts != nil {
+		return models.DeliveryStatusSent
+	}
+	return models.DeliveryStatusDelivered
+}
+
+// applyHeldReceipt applies a receipt that arrived before the message sent at timestamp was
+// recorded. Failures are logged, as the message itself was relayed.
+func (b *bridge) applyHeldReceipt(ctx context.Context, timestamp int64) {
+	if b.deliveryReceipts == nil {
+		return
+	}
+	msg, ok := b.deliveryReceipts.take(timestamp)
+	if !ok {
+		return
+	}
+	if err := b.HandleSignalReceipt(ctx, msg); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to apply Signal receipt received before the send was recorded")
+	}
+}
+
+func recordReceiptResult(result string) {
+	metrics.IncrementCounter("signal_delivery_receipts_total", map[string]string{
+		"result": result,
+	}, "Signal receipts by whether they matched a relayed message")
+}
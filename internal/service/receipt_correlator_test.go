@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/models"
+	"whatsignal/internal/testutil"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const receiptTestTimestamp int64 = 1774363000001
+
+func testReceipt(delivery, read bool) *signaltypes.SignalMessage {
+	return &signaltypes.SignalMessage{
+		MessageID: "1774363197904",
+		Sender:    "+1234567890",
+		Timestamp: 1774363197904,
+		Receipt: &signaltypes.SignalReceipt{
+			When:            1774363197904,
+			TargetTimestamp: receiptTestTimestamp,
+			IsDelivery:      delivery,
+			IsRead:          read,
+		},
+	}
+}
+
+// setupReceiptBridge returns a test bridge awaiting delivery receipts whose Signal sends are
+// accepted at receiptTestTimestamp
+func setupReceiptBridge(t *testing.T) (*bridge, *mockDatabaseService) {
+	bridge, _, cleanup := setupTestBridge(t)
+	t.Cleanup(cleanup)
+	bridge.deliveryReceipts = newReceiptCorrelator(time.Duration(constants.EarlyReceiptTTLSec)*time.Second, constants.MaxEarlyReceipts, RealClock)
+	bridge.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{
+		MessageID: "1774363000001",
+		Timestamp: receiptTestTimestamp,
+	}
+	return bridge, bridge.db.(*mockDatabaseService)
+}
+
+func TestDeliveryReceipts_ReceiptAdvancesSentToDelivered(t *testing.T) {
+	bridge, db := setupReceiptBridge(t)
+	ctx := context.Background()
+
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "123456789@c.us", "wa_msg_123", "123456789@c.us", "", "hi", "")
+	require.NoError(t, err)
+	db.AssertCalled(t, "UpdateSignalIDByWhatsAppID", ctx, "wa_msg_123", "1774363000001", mock.AnythingOfType("time.Time"), string(models.DeliveryStatusSent))
+
+	db.On("GetMessageMappingBySignalID", ctx, "1774363000001").Return(&models.MessageMapping{
+		WhatsAppChatID: "123456789@c.us",
+		WhatsAppMsgID:  "wa_msg_123",
+		SignalMsgID:    "1774363000001",
+		SessionName:    "default",
+		DeliveryStatus: models.DeliveryStatusSent,
+	}, nil).Once()
+	db.On("UpdateDeliveryStatus", ctx, "wa_msg_123", string(models.DeliveryStatusDelivered)).Return(nil).Once()
+
+	require.NoError(t, bridge.HandleSignalReceipt(ctx, testReceipt(true, false)))
+	db.AssertExpectations(t)
+}
+
+func TestDeliveryReceipts_ReceiptBeforeSendIsRecorded(t *testing.T) {
+	bridge, db := setupReceiptBridge(t)
+	ctx := context.Background()
+
+	// signal-cli reports the receipt before the relay stores the Signal timestamp
+	db.On("GetMessageMappingBySignalID", ctx, "1774363000001").Return(nil, nil).Once()
+	db.On("GetMessageMapping", ctx, "1774363000001").Return(nil, nil).Once()
+	require.NoError(t, bridge.HandleSignalReceipt(ctx, testReceipt(true, false)))
+
+	db.On("GetMessageMappingBySignalID", ctx, "1774363000001").Return(&models.MessageMapping{
+		WhatsAppChatID: "123456789@c.us",
+		WhatsAppMsgID:  "wa_msg_123",
+		SignalMsgID:    "1774363000001",
+		SessionName:    "default",
+		DeliveryStatus: models.DeliveryStatusSent,
+	}, nil).Once()
+	db.On("UpdateDeliveryStatus", mock.Anything, "wa_msg_123", string(models.DeliveryStatusDelivered)).Return(nil).Once()
+
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "123456789@c.us", "wa_msg_123", "123456789@c.us", "", "hi", "")
+	require.NoError(t, err)
+	db.AssertExpectations(t)
+
+	_, held := bridge.deliveryReceipts.take(receiptTestTimestamp)
+	assert.False(t, held, "the held receipt is applied once")
+}
+
+func TestDeliveryReceipts_DisabledRecordsDelivered(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	db := bridge.db.(*mockDatabaseService)
+	bridge.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "1774363000001", Timestamp: receiptTestTimestamp}
+
+	ctx := context.Background()
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "123456789@c.us", "wa_msg_123", "123456789@c.us", "", "hi", "")
+	require.NoError(t, err)
+	db.AssertCalled(t, "UpdateSignalIDByWhatsAppID", ctx, "wa_msg_123", "1774363000001", mock.AnythingOfType("time.Time"), string(models.DeliveryStatusDelivered))
+}
+
+func TestReceiptCorrelator(t *testing.T) {
+	t.Run("keeps the most advanced receipt", func(t *testing.T) {
+		c := newReceiptCorrelator(time.Minute, 10, RealClock)
+		read := testReceipt(false, true)
+		c.hold(read, string(models.DeliveryStatusRead))
+		c.hold(testReceipt(true, false), string(models.DeliveryStatusDelivered))
+
+		msg, ok := c.take(receiptTestTimestamp)
+		require.True(t, ok)
+		assert.Same(t, read, msg)
+	})
+
+	t.Run("expired receipts are dropped", func(t *testing.T) {
+		clock := testutil.NewFakeClock(time.Now())
+		c := newReceiptCorrelator(time.Minute, 10, clock)
+		c.hold(testReceipt(true, false), string(models.DeliveryStatusDelivered))
+		clock.Advance(time.Minute + time.Second)
+
+		_, ok := c.take(receiptTestTimestamp)
+		assert.False(t, ok)
+	})
+
+	t.Run("oldest receipt is evicted when full", func(t *testing.T) {
+		c := newReceiptCorrelator(time.Minute, 1, RealClock)
+		c.hold(testReceipt(true, false), string(models.DeliveryStatusDelivered))
+		newer := testReceipt(true, false)
+		newer.Receipt.TargetTimestamp = receiptTestTimestamp + 1
+		c.hold(newer, string(models.DeliveryStatusDelivered))
+
+		_, ok := c.take(receiptTestTimestamp)
+		assert.False(t, ok)
+		_, ok = c.take(receiptTestTimestamp + 1)
+		assert.True(t, ok)
+	})
+}
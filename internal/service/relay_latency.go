@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"whatsignal/internal/metrics"
+)
+
+// ingressContextKey carries the time an inbound message reached the bridge
+const ingressContextKey ContextKey = "ingress_time"
+
+// WithIngressTime returns a context carrying the time the message being handled reached the
+// bridge: when its webhook was received or its Signal poll returned
+func WithIngressTime(ctx context.Context, receivedAt time.Time) context.Context {
+	return context.WithValue(ctx, ingressContextKey, receivedAt)
+}
+
+// ingressTimeFromContext returns the time set by WithIngressTime, or the zero time
+func ingressTimeFromContext(ctx context.Context) time.Time {
+	receivedAt, _ := ctx.Value(ingressContextKey).(time.Time)
+	return receivedAt
+}
+
+// recordRelayLatency observes the time from the message's ingress to its delivery on the other
+// platform in the relay_latency_seconds histogram. Messages handled without an ingress time are
+// not observed.
+func recordRelayLatency(ctx context.Context, direction string) {
+	receivedAt := ingressTimeFromContext(ctx)
+	if receivedAt.IsZero() {
+		return
+	}
+	metrics.ObserveHistogram("relay_latency_seconds", time.Since(receivedAt).Seconds(), metrics.DefaultLatencyBuckets, map[string]string{
+		"direction": direction,
+	}, "Time from a message reaching the bridge to its delivery on the other platform")
+}
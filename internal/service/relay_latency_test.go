@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/metrics"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const relayLatencyKey = "relay_latency_seconds_direction:whatsapp_to_signal"
+
+func relayLatencyObservations() (int64, float64) {
+	histogram, ok := metrics.GetAllMetrics().Histograms[relayLatencyKey]
+	if !ok {
+		return 0, 0
+	}
+	return histogram.Count, histogram.Sum
+}
+
+func TestHandleWhatsAppMessage_RecordsRelayLatency(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	bridge.db.(*mockDatabaseService).On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	bridge.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-1", Timestamp: time.Now().UnixMilli()}
+
+	countBefore, sumBefore := relayLatencyObservations()
+
+	ctx := WithIngressTime(context.Background(), time.Now().Add(-2*time.Second))
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "15550001111@c.us", "msg-1", "15550001111@c.us", "", "hi", "")
+	require.NoError(t, err)
+
+	count, sum := relayLatencyObservations()
+	assert.Equal(t, countBefore+1, count)
+	assert.GreaterOrEqual(t, sum-sumBefore, 2.0, "latency is measured from ingress")
+
+	// Without an ingress time there is nothing to measure from
+	err = bridge.HandleWhatsAppMessageWithSession(context.Background(), "default", "15550001111@c.us", "msg-2", "15550001111@c.us", "", "again", "")
+	require.NoError(t, err)
+	count, _ = relayLatencyObservations()
+	assert.Equal(t, countBefore+1, count)
+}
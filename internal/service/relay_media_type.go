@@ -0,0 +1,25 @@
+package service
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Media type labels of the send metrics besides the router's image, video, voice and document
+const (
+	relayMediaTypeText    = "text"
+	relayMediaTypeSticker = "sticker"
+)
+
+// relayMediaType names the kind of message being sent for the send metrics' media_type label:
+// text without attachments, sticker for a WebP image, the format both platforms use for
+// stickers, and otherwise the media router's type of the first attachment
+func (b *bridge) relayMediaType(attachments []string) string {
+	if len(attachments) == 0 {
+		return relayMediaTypeText
+	}
+	if strings.EqualFold(filepath.Ext(attachments[0]), ".webp") {
+		return relayMediaTypeSticker
+	}
+	return b.mediaRouter.GetMediaType(attachments[0])
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"whatsignal/internal/metrics"
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func counterValue(name string) float64 {
+	if counter, ok := metrics.GetAllMetrics().Counters[name]; ok {
+		return counter.Value
+	}
+	return 0
+}
+
+func TestSendMessageToWhatsApp_CountsByMediaType(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	waClient := b.waClient.(*mockWhatsAppClient)
+	sent := &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}
+	waClient.sendTextResp = sent
+	waClient.sendImageResp = sent
+	waClient.sendVideoResp = sent
+	waClient.sendDocumentResp = sent
+	voiceErr := errors.New("voice upload rejected")
+	waClient.sendVoiceErr = voiceErr
+	failures := fmt.Sprintf("whatsapp_send_failures_media_type:voice_retryable:%t_session:default", isRetryableWhatsAppError(voiceErr))
+
+	tests := []struct {
+		name        string
+		attachments []string
+		mediaType   string
+		status      string
+	}{
+		{name: "text", mediaType: "text", status: "success"},
+		{name: "image", attachments: []string{"/tmp/photo.jpg"}, mediaType: "image", status: "success"},
+		{name: "video", attachments: []string{"/tmp/clip.mp4"}, mediaType: "video", status: "success"},
+		{name: "document", attachments: []string{"/tmp/report.pdf"}, mediaType: "document", status: "success"},
+		{name: "sticker", attachments: []string{"/tmp/sticker.webp"}, mediaType: "sticker", status: "success"},
+		{name: "voice", attachments: []string{"/tmp/note.ogg"}, mediaType: "voice", status: "failure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := "whatsapp_send_total_media_type:" + tt.mediaType + "_session:default_status:" + tt.status
+			before, failuresBefore := counterValue(total), counterValue(failures)
+
+			_, err := b.sendMessageToWhatsApp(ctx, "15550001111@c.us", "hello", nil, tt.attachments, "", "default")
+			if tt.status == "failure" {
+				require.Error(t, err)
+				assert.Equal(t, failuresBefore+1, counterValue(failures))
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, before+1, counterValue(total))
+		})
+	}
+}
+
+func TestRelayWhatsAppMessage_CountsByMediaType(t *testing.T) {
+	b, tmpDir, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	b.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig_sent", Timestamp: time.Now().UnixMilli()}
+	b.db.(*mockDatabaseService).On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	b.db.(*mockDatabaseService).On("UpdateSignalIDByWhatsAppID", ctx, mock.Anything, "sig_sent", mock.AnythingOfType("time.Time"), string(models.DeliveryStatusDelivered)).Return(nil)
+
+	photo := filepath.Join(tmpDir, "photo.jpg")
+	sticker := filepath.Join(tmpDir, "sticker.webp")
+	require.NoError(t, os.WriteFile(photo, []byte("photo"), 0644))
+	require.NoError(t, os.WriteFile(sticker, []byte("sticker"), 0644))
+	b.media.(*mockMediaHandler).On("ProcessMedia", photo).Return(photo, nil)
+	b.media.(*mockMediaHandler).On("ProcessMedia", sticker).Return(sticker, nil)
+
+	tests := []struct {
+		msgID     string
+		mediaPath string
+		mediaType string
+	}{
+		{msgID: "wa_text", mediaType: "text"},
+		{msgID: "wa_photo", mediaPath: photo, mediaType: "image"},
+		{msgID: "wa_sticker", mediaPath: sticker, mediaType: "sticker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mediaType, func(t *testing.T) {
+			total := "signal_send_total_media_type:" + tt.mediaType + "_session:default_status:success"
+			before := counterValue(total)
+
+			require.NoError(t, b.HandleWhatsAppMessageWithSession(ctx, "default", "15550001111@c.us", tt.msgID, "15550001111@c.us", "Alice", "hi", tt.mediaPath))
+			assert.Equal(t, before+1, counterValue(total))
+		})
+	}
+}
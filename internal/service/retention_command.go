@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"whatsignal/internal/validation"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chatCommandRetention reports or changes how many days of message history cleanup keeps
+const chatCommandRetention = "/retention"
+
+// RetentionSetting holds the number of days cleanup keeps message records. It starts at the
+// configured retentionDays and can be changed at runtime with /retention; a change lasts until
+// restart.
+type RetentionSetting struct {
+	days atomic.Int64
+}
+
+// NewRetentionSetting returns a RetentionSetting of days
+func NewRetentionSetting(days int) *RetentionSetting {
+	r := &RetentionSetting{}
+	r.Set(days)
+	return r
+}
+
+// Days returns the current retention in days
+func (r *RetentionSetting) Days() int {
+	return int(r.days.Load())
+}
+
+// Set changes the retention to days
+func (r *RetentionSetting) Set(days int) {
+	r.days.Store(int64(days))
+}
+
+// parseRetentionCommand reports whether a Signal message is a /retention command and returns
+// its argument, empty for a query
+func parseRetentionCommand(msg *signaltypes.SignalMessage) (string, bool) {
+	if len(msg.Attachments) > 0 {
+		return "", false
+	}
+	fields := strings.Fields(msg.Message)
+	if len(fields) == 0 || len(fields) > 2 || !strings.EqualFold(fields[0], chatCommandRetention) {
+		return "", false
+	}
+	if len(fields) == 1 {
+		return "", true
+	}
+	return fields[1], true
+}
+
+// handleRetentionCommand replies to /retention with the current retention, or sets it from
+// /retention <days> until restart. Only the channel's own Signal number may use it; the command
+// is never relayed either way. It reports whether msg was a retention command.
+func (b *bridge) handleRetentionCommand(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string) bool {
+	argument, ok := parseRetentionCommand(msg)
+	if !ok {
+		return false
+	}
+
+	reply := fmt.Sprintf("Message history is kept for %d days.", b.retention.Days())
+	if argument != "" {
+		days, err := strconv.Atoi(argument)
+		if err == nil {
+			err = validation.ValidateRetentionDays(days)
+		}
+		if err != nil {
+			reply = fmt.Sprintf("Usage: %s <days>, from 1 to 3650", chatCommandRetention)
+		} else {
+			previous := b.retention.Days()
+			b.retention.Set(days)
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
+				LogFieldSession: sessionName,
+				"old":           previous,
+				"new":           days,
+			}).Info("Retention days changed from Signal command")
+			reply = fmt.Sprintf("Message history is now kept for %d days, until restart.", days)
+		}
+	}
+
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, reply); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to send retention reply")
+	}
+	return true
+}
+
+// Retention returns the retention setting changed by /retention, for the cleanup scheduler
+func (b *bridge) Retention() *RetentionSetting {
+	return b.retention
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetentionCommand(t *testing.T) {
+	argument, ok := parseRetentionCommand(&signaltypes.SignalMessage{Message: " /Retention\n"})
+	assert.True(t, ok)
+	assert.Empty(t, argument)
+
+	argument, ok = parseRetentionCommand(&signaltypes.SignalMessage{Message: "/retention 14"})
+	assert.True(t, ok)
+	assert.Equal(t, "14", argument)
+
+	_, ok = parseRetentionCommand(&signaltypes.SignalMessage{Message: "/retention is too short"})
+	assert.False(t, ok)
+	_, ok = parseRetentionCommand(&signaltypes.SignalMessage{Message: "/retention", Attachments: []string{"a.jpg"}})
+	assert.False(t, ok)
+}
+
+// sendRetentionCommand handles text from sender as a Signal message and fails the test if
+// anything is relayed to WhatsApp
+func sendRetentionCommand(t *testing.T, b *bridge, sender, text string) {
+	t.Helper()
+	b.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		t.Errorf("retention command relayed to WhatsApp: %q", text)
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+	err := b.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{MessageID: "cmd1", Sender: sender, Message: text})
+	require.NoError(t, err)
+}
+
+func TestRetentionCommand_ReportsAndSetsRetention(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	b.retention.Set(30)
+
+	sigClient := b.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig_sent", Timestamp: time.Now().UnixMilli()}
+
+	sendRetentionCommand(t, b, "+1234567890", "/retention")
+	assert.Equal(t, "Message history is kept for 30 days.", sigClient.lastMessage)
+
+	sendRetentionCommand(t, b, "+1234567890", "/retention 14")
+	assert.Equal(t, 14, b.Retention().Days())
+	assert.Contains(t, sigClient.lastMessage, "now kept for 14 days")
+
+	sendRetentionCommand(t, b, "+1234567890", "/retention")
+	assert.Equal(t, "Message history is kept for 14 days.", sigClient.lastMessage)
+
+	// The scheduler sharing the setting cleans up with the new value
+	cleaner := &mockBridge{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	scheduler := NewScheduler(cleaner, 30, 24, logger)
+	scheduler.SetRetention(b.Retention())
+	ctx := context.Background()
+	cleaner.On("CleanupOldRecords", ctx, 14).Return(nil).Once()
+	scheduler.runCleanup(ctx)
+	cleaner.AssertExpectations(t)
+}
+
+func TestRetentionCommand_RejectsInvalidDays(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	b.retention.Set(30)
+
+	sigClient := b.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig_sent", Timestamp: time.Now().UnixMilli()}
+
+	for _, text := range []string{"/retention 0", "/retention 5000", "/retention two"} {
+		sendRetentionCommand(t, b, "+1234567890", text)
+		assert.Contains(t, sigClient.lastMessage, "Usage: /retention <days>", text)
+	}
+	assert.Equal(t, 30, b.Retention().Days())
+}
+
+func TestRetentionCommand_IgnoresOtherSenders(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	b.retention.Set(30)
+
+	sigClient := b.sigClient.(*mockSignalClient)
+	sendRetentionCommand(t, b, "+1999999999", "/retention 1")
+
+	assert.Equal(t, 30, b.Retention().Days())
+	assert.Empty(t, sigClient.lastMessage)
+}
@@ -10,15 +10,40 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ContactCleaner removes cached contacts older than a retention period
+type ContactCleaner interface {
+	CleanupOldContacts(ctx context.Context, retentionDays int) error
+}
+
+// GroupCleaner removes cached groups older than a retention period
+type GroupCleaner interface {
+	CleanupOldGroups(ctx context.Context, retentionDays int) error
+}
+
+// CacheBudgetEnforcer deletes least recently used cached media while the cache is over its
+// size budget
+type CacheBudgetEnforcer interface {
+	EnforceCacheBudget() (int64, error)
+}
+
 type Scheduler struct {
-	cleaner       RecordCleaner
-	retentionDays int
-	intervalHours int
-	logger        *logrus.Logger
-	stopCh        chan struct{}
-	stopMu        sync.Mutex
-	stopOnce      sync.Once
-	stopWg        sync.WaitGroup
+	cleaner              RecordCleaner
+	retention            *RetentionSetting
+	contactCleaner       ContactCleaner
+	contactRetentionDays int
+	groupCleaner         GroupCleaner
+	groupRetentionDays   int
+	orphanPurger         OrphanMediaPurger
+	orphanGracePeriod    time.Duration
+	cacheBudget          CacheBudgetEnforcer
+	intervalHours        int
+	clock                Clock
+	nextRun              time.Time
+	logger               *logrus.Logger
+	stopCh               chan struct{}
+	stopMu               sync.Mutex
+	stopOnce             sync.Once
+	stopWg               sync.WaitGroup
 }
 
 // NewScheduler creates a new cleanup scheduler.
@@ -29,25 +54,69 @@ func NewScheduler(cleaner RecordCleaner, retentionDays, intervalHours int, logge
 	}
 	return &Scheduler{
 		cleaner:       cleaner,
-		retentionDays: retentionDays,
+		retention:     NewRetentionSetting(retentionDays),
 		intervalHours: intervalHours,
+		clock:         RealClock,
 		logger:        logger,
 		stopCh:        make(chan struct{}),
 	}
 }
 
+// SetRetention makes cleanup runs read the retention from r instead of the retentionDays given
+// to NewScheduler, so changes made with /retention apply from the next run. It must be called
+// before Start.
+func (s *Scheduler) SetRetention(r *RetentionSetting) {
+	s.retention = r
+}
+
+// SetContactCleanup makes each cleanup run also remove cached contacts older than
+// retentionDays. A retentionDays of 0 or less keeps cached contacts indefinitely.
+// It must be called before Start.
+func (s *Scheduler) SetContactCleanup(cleaner ContactCleaner, retentionDays int) {
+	s.contactCleaner = cleaner
+	s.contactRetentionDays = retentionDays
+}
+
+// SetGroupCleanup makes each cleanup run also remove cached groups older than
+// retentionDays. A retentionDays of 0 or less keeps cached groups indefinitely.
+// It must be called before Start.
+func (s *Scheduler) SetGroupCleanup(cleaner GroupCleaner, retentionDays int) {
+	s.groupCleaner = cleaner
+	s.groupRetentionDays = retentionDays
+}
+
+// SetOrphanMediaPurge makes each cleanup run also delete cached media that no message mapping
+// refers to and that was last used more than gracePeriod ago. A gracePeriod of 0 or less keeps
+// orphaned media until its retention expires. It must be called before Start.
+func (s *Scheduler) SetOrphanMediaPurge(purger OrphanMediaPurger, gracePeriod time.Duration) {
+	s.orphanPurger = purger
+	s.orphanGracePeriod = gracePeriod
+}
+
+// SetCacheBudget makes each cleanup run also evict the least recently used cached media while
+// the cache is larger than media.maxCacheSizeMB. It must be called before Start.
+func (s *Scheduler) SetCacheBudget(enforcer CacheBudgetEnforcer) {
+	s.cacheBudget = enforcer
+}
+
+// SetClock makes the scheduler decide when a cleanup run is due with c instead of the system
+// clock. It must be called before Start.
+func (s *Scheduler) SetClock(c Clock) {
+	s.clock = c
+}
+
 func (s *Scheduler) Start(ctx context.Context) {
 	s.stopMu.Lock()
 	s.stopWg.Add(1)
 	s.stopMu.Unlock()
 	defer s.stopWg.Done()
 
-	ticker := time.NewTicker(time.Duration(s.intervalHours) * time.Hour)
-	defer ticker.Stop()
-
 	s.logger.Info("Starting cleanup scheduler")
 
-	s.runCleanup(ctx)
+	s.runIfDue(ctx)
+
+	ticker := time.NewTicker(s.interval())
+	defer ticker.Stop()
 
 	for {
 		select {
@@ -58,11 +127,34 @@ func (s *Scheduler) Start(ctx context.Context) {
 			s.logger.Info("Scheduler stop signal received, stopping")
 			return
 		case <-ticker.C:
-			s.runCleanup(ctx)
+			s.runIfDue(ctx)
 		}
 	}
 }
 
+func (s *Scheduler) interval() time.Duration {
+	return time.Duration(s.intervalHours) * time.Hour
+}
+
+// runIfDue runs a cleanup when the clock has reached the next scheduled run and schedules the
+// one after it. The first call always runs. It reports whether a cleanup ran.
+func (s *Scheduler) runIfDue(ctx context.Context) bool {
+	now := s.clock.Now()
+	if !s.nextRun.IsZero() && now.Before(s.nextRun) {
+		return false
+	}
+	if s.nextRun.IsZero() {
+		s.nextRun = now
+	}
+	// Advance from the schedule rather than from now, so a tick received late does not push
+	// every later run back
+	for !s.nextRun.After(now) {
+		s.nextRun = s.nextRun.Add(s.interval())
+	}
+	s.runCleanup(ctx)
+	return true
+}
+
 func (s *Scheduler) Stop() {
 	s.stopMu.Lock()
 	s.stopOnce.Do(func() {
@@ -73,11 +165,50 @@ func (s *Scheduler) Stop() {
 }
 
 func (s *Scheduler) runCleanup(ctx context.Context) {
-	s.logger.WithField("retentionDays", s.retentionDays).Info("Running scheduled cleanup")
+	retentionDays := s.retention.Days()
+	startedAt := s.clock.Now()
+	s.logger.WithField("retentionDays", retentionDays).Info("Running scheduled cleanup")
+	defer func() {
+		s.logger.WithField("duration", s.clock.Now().Sub(startedAt)).Debug("Scheduled cleanup finished")
+	}()
 
-	if err := s.cleaner.CleanupOldRecords(ctx, s.retentionDays); err != nil {
+	if err := s.cleaner.CleanupOldRecords(ctx, retentionDays); err != nil {
 		s.logger.WithError(err).Error("Failed to cleanup old records")
 	} else {
 		s.logger.Info("Successfully completed cleanup")
 	}
+
+	// After the record cleanup, so media of the mappings it just deleted counts as orphaned
+	if s.orphanPurger != nil && s.orphanGracePeriod > 0 {
+		if removed, err := s.orphanPurger.PurgeOrphanedMedia(ctx, s.orphanGracePeriod); err != nil {
+			s.logger.WithError(err).Error("Failed to purge orphaned media")
+		} else {
+			s.logger.WithField("removed", removed).Debug("Purged orphaned media")
+		}
+	}
+
+	// After the orphan purge, so only media still in use is evicted to meet the budget
+	if s.cacheBudget != nil {
+		if freed, err := s.cacheBudget.EnforceCacheBudget(); err != nil {
+			s.logger.WithError(err).Error("Failed to enforce the media cache budget")
+		} else if freed > 0 {
+			s.logger.WithField("freedBytes", freed).Info("Evicted cached media over the cache budget")
+		}
+	}
+
+	if s.contactCleaner != nil && s.contactRetentionDays > 0 {
+		if err := s.contactCleaner.CleanupOldContacts(ctx, s.contactRetentionDays); err != nil {
+			s.logger.WithError(err).Error("Failed to cleanup old contacts")
+		} else {
+			s.logger.WithField("retentionDays", s.contactRetentionDays).Debug("Cleaned up cached contacts")
+		}
+	}
+
+	if s.groupCleaner != nil && s.groupRetentionDays > 0 {
+		if err := s.groupCleaner.CleanupOldGroups(ctx, s.groupRetentionDays); err != nil {
+			s.logger.WithError(err).Error("Failed to cleanup old groups")
+		} else {
+			s.logger.WithField("retentionDays", s.groupRetentionDays).Debug("Cleaned up cached groups")
+		}
+	}
 }
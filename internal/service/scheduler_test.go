@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"whatsignal/internal/models"
+	"whatsignal/internal/testutil"
+
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +32,122 @@ func TestScheduler_RunCleanup(t *testing.T) {
 	mockBridge.AssertExpectations(t)
 }
 
+// mockCacheCleaner records contact and group cache cleanups
+type mockCacheCleaner struct {
+	mock.Mock
+}
+
+func (m *mockCacheCleaner) CleanupOldContacts(ctx context.Context, retentionDays int) error {
+	args := m.Called(ctx, retentionDays)
+	return args.Error(0)
+}
+
+func (m *mockCacheCleaner) CleanupOldGroups(ctx context.Context, retentionDays int) error {
+	args := m.Called(ctx, retentionDays)
+	return args.Error(0)
+}
+
+func TestScheduler_RunCleanupCaches(t *testing.T) {
+	mockBridge := &mockBridge{}
+	cacheCleaner := &mockCacheCleaner{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	scheduler := NewScheduler(mockBridge, 30, 24, logger)
+	scheduler.SetContactCleanup(cacheCleaner, 90)
+	scheduler.SetGroupCleanup(cacheCleaner, 7)
+
+	ctx := context.Background()
+
+	mockBridge.On("CleanupOldRecords", ctx, 30).Return(nil).Once()
+	cacheCleaner.On("CleanupOldContacts", ctx, 90).Return(assert.AnError).Once()
+	cacheCleaner.On("CleanupOldGroups", ctx, 7).Return(nil).Once()
+
+	scheduler.runCleanup(ctx)
+
+	mockBridge.AssertExpectations(t)
+	cacheCleaner.AssertExpectations(t)
+}
+
+func TestScheduler_CacheCleanupDisabledByDefault(t *testing.T) {
+	mockBridge := &mockBridge{}
+	cacheCleaner := &mockCacheCleaner{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	scheduler := NewScheduler(mockBridge, 30, 24, logger)
+	scheduler.SetContactCleanup(cacheCleaner, 0)
+	scheduler.SetGroupCleanup(cacheCleaner, 0)
+
+	ctx := context.Background()
+
+	mockBridge.On("CleanupOldRecords", ctx, 30).Return(nil).Once()
+
+	scheduler.runCleanup(ctx)
+
+	mockBridge.AssertExpectations(t)
+	cacheCleaner.AssertNotCalled(t, "CleanupOldContacts", mock.Anything, mock.Anything)
+	cacheCleaner.AssertNotCalled(t, "CleanupOldGroups", mock.Anything, mock.Anything)
+}
+
+func TestScheduler_RunCleanupPurgesOrphanedMedia(t *testing.T) {
+	mockBridge := &mockBridge{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	scheduler := NewScheduler(mockBridge, 30, 24, logger)
+	scheduler.SetOrphanMediaPurge(mockBridge, 6*time.Hour)
+
+	ctx := context.Background()
+
+	cleanup := mockBridge.On("CleanupOldRecords", ctx, 30).Return(nil).Once()
+	mockBridge.On("PurgeOrphanedMedia", ctx, 6*time.Hour).Return(3, nil).Once().NotBefore(cleanup)
+
+	scheduler.runCleanup(ctx)
+
+	mockBridge.AssertExpectations(t)
+}
+
+func TestScheduler_RunCleanupEnforcesCacheBudget(t *testing.T) {
+	mockBridge := &mockBridge{}
+	mediaHandler := &mockMediaHandler{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	scheduler := NewScheduler(mockBridge, 30, 24, logger)
+	scheduler.SetOrphanMediaPurge(mockBridge, 6*time.Hour)
+	scheduler.SetCacheBudget(mediaHandler)
+
+	ctx := context.Background()
+
+	cleanup := mockBridge.On("CleanupOldRecords", ctx, 30).Return(nil).Once()
+	purge := mockBridge.On("PurgeOrphanedMedia", ctx, 6*time.Hour).Return(0, nil).Once().NotBefore(cleanup)
+	mediaHandler.On("EnforceCacheBudget").Return(int64(2048), nil).Once().NotBefore(purge)
+
+	scheduler.runCleanup(ctx)
+
+	mockBridge.AssertExpectations(t)
+	mediaHandler.AssertExpectations(t)
+}
+
+func TestScheduler_OrphanMediaPurgeDisabled(t *testing.T) {
+	mockBridge := &mockBridge{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	scheduler := NewScheduler(mockBridge, 30, 24, logger)
+	scheduler.SetOrphanMediaPurge(mockBridge, 0)
+
+	ctx := context.Background()
+
+	mockBridge.On("CleanupOldRecords", ctx, 30).Return(nil).Once()
+
+	scheduler.runCleanup(ctx)
+
+	mockBridge.AssertExpectations(t)
+	mockBridge.AssertNotCalled(t, "PurgeOrphanedMedia", mock.Anything, mock.Anything)
+}
+
 func TestScheduler_RunCleanupError(t *testing.T) {
 	mockBridge := &mockBridge{}
 	logger := logrus.New()
@@ -163,3 +284,74 @@ func TestScheduler_StopIsIdempotent(t *testing.T) {
 		t.Fatal("Scheduler did not stop within timeout")
 	}
 }
+
+func TestScheduler_RetentionCleanupAtControlledTime(t *testing.T) {
+	attachmentsDir := t.TempDir()
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	channelManager, err := NewChannelManager([]models.Channel{{WhatsAppSessionName: "default", SignalDestinationPhoneNumber: "+1234567890"}})
+	require.NoError(t, err)
+
+	db := &mockDatabaseService{}
+	mediaHandler := &mockMediaHandler{}
+	db.On("CleanupOldRecords", mock.Anything, 7).Return(nil)
+	mediaHandler.On("CleanupOldFiles", int64(7*24*60*60)).Return(nil)
+	b := NewBridge(&mockWAClient{}, &mockSignalClient{}, db, mediaHandler, models.RetryConfig{}, models.MediaConfig{}, channelManager, nil, nil, attachmentsDir, logger).(*bridge)
+
+	clock := testutil.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	b.clock = clock
+	scheduler := NewScheduler(b, 7, 24, logger)
+	scheduler.SetClock(clock)
+
+	writeAttachment := func(name string, modTime time.Time) string {
+		path := filepath.Join(attachmentsDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+		return path
+	}
+	expired := writeAttachment("expired.jpg", time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC))
+	expiresSoon := writeAttachment("expires-soon.jpg", time.Date(2026, 2, 23, 12, 0, 0, 0, time.UTC))
+	recent := writeAttachment("recent.jpg", time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC))
+
+	ctx := context.Background()
+	scheduler.runCleanup(ctx)
+	assert.NoFileExists(t, expired)
+	assert.FileExists(t, expiresSoon, "six days old is within the retention")
+	assert.FileExists(t, recent)
+
+	// Two days later the second attachment has passed the retention too
+	clock.Advance(48 * time.Hour)
+	scheduler.runCleanup(ctx)
+	assert.NoFileExists(t, expiresSoon)
+	assert.FileExists(t, recent)
+}
+
+func TestScheduler_RunIfDueFollowsClock(t *testing.T) {
+	mockBridge := &mockBridge{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	scheduler := NewScheduler(mockBridge, 30, 24, logger)
+	clock := testutil.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	scheduler.SetClock(clock)
+
+	ctx := context.Background()
+	mockBridge.On("CleanupOldRecords", ctx, 30).Return(nil)
+
+	assert.True(t, scheduler.runIfDue(ctx), "the first run is due at once")
+	assert.False(t, scheduler.runIfDue(ctx))
+
+	clock.Advance(23 * time.Hour)
+	assert.False(t, scheduler.runIfDue(ctx), "the interval has not passed yet")
+
+	clock.Advance(time.Hour)
+	assert.True(t, scheduler.runIfDue(ctx))
+
+	// A late tick does not move the schedule: the next run is still due at 12:00
+	clock.Advance(25 * time.Hour)
+	assert.True(t, scheduler.runIfDue(ctx))
+	clock.Advance(23 * time.Hour)
+	assert.True(t, scheduler.runIfDue(ctx))
+
+	mockBridge.AssertNumberOfCalls(t, "CleanupOldRecords", 4)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -11,6 +12,11 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// SessionNotifier sends a system notice about a WhatsApp session to Signal
+type SessionNotifier interface {
+	SendSystemNotification(ctx context.Context, sessionName, message string) error
+}
+
 // SessionMonitor monitors WhatsApp session health and restarts it when needed
 type SessionMonitor struct {
 	waClient               types.WAClient
@@ -25,6 +31,27 @@ type SessionMonitor struct {
 	stopCh                 chan struct{}
 	monitorWg              sync.WaitGroup
 	unhealthyStatusSet     map[string]struct{} // Pre-computed set for O(1) lookup
+
+	// Down notifications; only touched by the monitor loop
+	notifier          SessionNotifier
+	notifyAfter       time.Duration
+	repeatInterval    time.Duration
+	maxRepeatInterval time.Duration
+	downSince         time.Time     // Zero while the session is up
+	downNotified      bool          // Whether the first down notice was sent for this outage
+	nextNotifyAt      time.Time     // When the next down notice or reminder is due
+	currentRepeat     time.Duration // Reminder interval, doubled after each reminder
+	now               func() time.Time
+
+	// Restart backoff; only touched by the monitor loop
+	restartBackoffBase time.Duration // Zero restarts on every unhealthy check
+	restartBackoffMax  time.Duration
+	restartResetAfter  time.Duration
+	restartBackoff     time.Duration // Wait after the next restart attempt, doubled after each attempt
+	nextRestartAt      time.Time     // Restarts are skipped until then
+	workingSince       time.Time     // Zero while the session is not WORKING
+
+	numbers *SessionNumbers // Optional; adds the session's redacted number to health logs
 }
 
 // NewSessionMonitor creates a new session monitor
@@ -60,7 +87,46 @@ func NewSessionMonitorWithStartupTimeout(waClient types.WAClient, logger *logrus
 		sessionName:            waClient.GetSessionName(),
 		stopCh:                 make(chan struct{}),
 		unhealthyStatusSet:     unhealthyStatusSet,
+		now:                    time.Now,
+	}
+}
+
+// SetDownNotifier makes the monitor notify Signal when the session goes down. The first notice
+// is sent once the session has been down for notifyAfter (0 notifies on the first failed check),
+// reminders follow after repeat, doubling each time up to constants.MaxSessionDownRepeatSec, and a
+// single recovery notice is sent when the session is working again. It must be called before Start.
+func (sm *SessionMonitor) SetDownNotifier(notifier SessionNotifier, notifyAfter, repeat time.Duration) {
+	if repeat <= 0 {
+		repeat = time.Duration(constants.DefaultSessionDownRepeatSec) * time.Second
+	}
+	sm.notifier = notifier
+	sm.notifyAfter = notifyAfter
+	sm.repeatInterval = repeat
+	sm.maxRepeatInterval = time.Duration(constants.MaxSessionDownRepeatSec) * time.Second
+	if sm.maxRepeatInterval < repeat {
+		sm.maxRepeatInterval = repeat
+	}
+}
+
+// SetRestartBackoff makes the monitor space out restarts of a session that keeps failing: after
+// a restart attempt the next one waits base, doubling after each further attempt up to max. Once
+// the session has stayed WORKING for resetAfter the wait starts over at base. Without it an
+// unhealthy session is restarted on every check. It must be called before Start.
+func (sm *SessionMonitor) SetRestartBackoff(base, max, resetAfter time.Duration) {
+	if max < base {
+		max = base
 	}
+	sm.restartBackoffBase = base
+	sm.restartBackoffMax = max
+	sm.restartResetAfter = resetAfter
+	sm.restartBackoff = base
+}
+
+// SetSessionNumbers makes the monitor include the session's redacted phone number in its logs
+// and look the number up when the session is working but its number is not known yet. It must
+// be called before Start.
+func (sm *SessionMonitor) SetSessionNumbers(numbers *SessionNumbers) {
+	sm.numbers = numbers
 }
 
 // Start begins monitoring the session
@@ -158,18 +224,30 @@ func (sm *SessionMonitor) checkAndRecoverSession(ctx context.Context) {
 	// Get current session status
 	status, err := sm.getSessionStatusFromAPI(checkCtx)
 	if err != nil {
-		sm.logger.WithError(err).Error("Failed to get session status")
+		sm.logEntry().WithError(err).Error("Failed to get session status")
 		return
 	}
 
-	sm.logger.WithField("status", status).Debug("Session status check")
+	sm.logEntry().WithField("status", status).Debug("Session status check")
 
 	// Update state tracking and check if session is stuck in STARTING
 	stuckInStarting, startingDuration := sm.updateAndCheckStartingTimeout(sm.sessionName, status)
 
+	switch {
+	case status == "WORKING":
+		sm.refreshNumber(checkCtx)
+		sm.notifySessionUp(ctx)
+		sm.trackRestartRecovery()
+	case stuckInStarting || sm.isSessionUnhealthy(status):
+		sm.notifySessionDown(ctx, status)
+	}
+	if status != "WORKING" {
+		sm.workingSince = time.Time{}
+	}
+
 	// Check if session is stuck in STARTING status (check this first)
 	if stuckInStarting {
-		sm.logger.WithFields(logrus.Fields{
+		sm.logEntry().WithFields(logrus.Fields{
 			"status":   status,
 			"duration": startingDuration.Seconds(),
 			"timeout":  sm.startupTimeout.Seconds(),
@@ -181,21 +259,136 @@ func (sm *SessionMonitor) checkAndRecoverSession(ctx context.Context) {
 
 	// Check if session is in a bad state
 	if sm.isSessionUnhealthy(status) {
-		sm.logger.WithField("status", status).Warn("Session is in unhealthy state, attempting restart")
+		sm.logEntry().WithField("status", status).Warn("Session is in unhealthy state, attempting restart")
 		sm.handleSessionRestart(ctx, sm.sessionName, "unhealthy state")
 	}
 }
 
 // handleSessionRestart encapsulates the restart logic to avoid duplication
 func (sm *SessionMonitor) handleSessionRestart(ctx context.Context, sessionName, reason string) {
+	if now := sm.now(); now.Before(sm.nextRestartAt) {
+		sm.logEntry().WithFields(logrus.Fields{
+			"reason":   reason,
+			"retry_in": sm.nextRestartAt.Sub(now).Round(time.Second).String(),
+		}).Info("Delaying session restart, backing off after recent attempts")
+		return
+	}
+	defer sm.scheduleNextRestart()
+
 	if err := sm.restartSession(ctx); err != nil {
-		sm.logger.WithError(err).WithField("reason", reason).Error("Failed to restart session")
+		sm.logEntry().WithError(err).WithField("reason", reason).Error("Failed to restart session")
 	} else {
-		sm.logger.WithField("reason", reason).Info("Session restart initiated successfully")
+		sm.logEntry().WithField("reason", reason).Info("Session restart initiated successfully")
 		sm.resetSessionTracking(sessionName)
 	}
 }
 
+// scheduleNextRestart sets when the next restart may be attempted and doubles the wait after it
+func (sm *SessionMonitor) scheduleNextRestart() {
+	if sm.restartBackoffBase <= 0 {
+		return
+	}
+	sm.nextRestartAt = sm.now().Add(sm.restartBackoff)
+	sm.restartBackoff = min(sm.restartBackoff*2, sm.restartBackoffMax)
+}
+
+// trackRestartRecovery resets the restart backoff once the session has been WORKING for the
+// configured cooldown
+func (sm *SessionMonitor) trackRestartRecovery() {
+	if sm.restartBackoffBase <= 0 {
+		return
+	}
+	now := sm.now()
+	if sm.workingSince.IsZero() {
+		sm.workingSince = now
+	}
+	if now.Sub(sm.workingSince) < sm.restartResetAfter || (sm.nextRestartAt.IsZero() && sm.restartBackoff == sm.restartBackoffBase) {
+		return
+	}
+	sm.nextRestartAt = time.Time{}
+	sm.restartBackoff = sm.restartBackoffBase
+	sm.logEntry().Info("Session stable, restart backoff reset")
+}
+
+// logEntry returns a log entry for the monitored session, with its redacted number when known
+func (sm *SessionMonitor) logEntry() *logrus.Entry {
+	entry := sm.logger.WithField("session", sm.sessionName)
+	if phone := sm.numbers.Redacted(sm.sessionName); phone != "" {
+		entry = entry.WithField("phone", phone)
+	}
+	return entry
+}
+
+// refreshNumber looks up the session's number once it is working, if it is not known yet
+func (sm *SessionMonitor) refreshNumber(ctx context.Context) {
+	if sm.numbers == nil || sm.numbers.Get(sm.sessionName) != "" {
+		return
+	}
+	if err := sm.numbers.RefreshSession(ctx, sm.sessionName); err != nil {
+		sm.logEntry().WithError(err).Debug("Failed to look up session phone number")
+	}
+}
+
+// notifySessionDown tracks an outage and sends the first down notice and later reminders when due
+func (sm *SessionMonitor) notifySessionDown(ctx context.Context, status string) {
+	if sm.notifier == nil {
+		return
+	}
+
+	now := sm.now()
+	if sm.downSince.IsZero() {
+		sm.downSince = now
+		sm.downNotified = false
+		sm.nextNotifyAt = now.Add(sm.notifyAfter)
+		sm.currentRepeat = sm.repeatInterval
+	}
+	if now.Before(sm.nextNotifyAt) {
+		return
+	}
+
+	var message string
+	if !sm.downNotified {
+		message = fmt.Sprintf("WhatsApp session %s is down (status %s). Messages from WhatsApp are not being relayed.", sm.sessionName, status)
+	} else {
+		message = fmt.Sprintf("WhatsApp session %s is still down after %s (status %s).", sm.sessionName, now.Sub(sm.downSince).Round(time.Second), status)
+	}
+
+	// A failed send is retried on the next check
+	if err := sm.notifier.SendSystemNotification(ctx, sm.sessionName, message); err != nil {
+		sm.logger.WithError(err).Warn("Failed to send session down notification")
+		return
+	}
+
+	if sm.downNotified {
+		sm.currentRepeat *= 2
+		if sm.currentRepeat > sm.maxRepeatInterval {
+			sm.currentRepeat = sm.maxRepeatInterval
+		}
+	}
+	sm.downNotified = true
+	sm.nextNotifyAt = now.Add(sm.currentRepeat)
+}
+
+// notifySessionUp ends a tracked outage, sending a recovery notice if the outage was reported
+func (sm *SessionMonitor) notifySessionUp(ctx context.Context) {
+	if sm.notifier == nil || sm.downSince.IsZero() {
+		return
+	}
+
+	downFor := sm.now().Sub(sm.downSince).Round(time.Second)
+	notified := sm.downNotified
+	sm.downSince = time.Time{}
+	sm.downNotified = false
+	if !notified {
+		return
+	}
+
+	message := fmt.Sprintf("WhatsApp session %s has recovered after %s.", sm.sessionName, downFor)
+	if err := sm.notifier.SendSystemNotification(ctx, sm.sessionName, message); err != nil {
+		sm.logger.WithError(err).Warn("Failed to send session recovery notification")
+	}
+}
+
 func (sm *SessionMonitor) getSessionStatusFromAPI(ctx context.Context) (string, error) {
 	// Use the same method as WaitForSessionReady to get the actual WAHA status
 	session, err := sm.waClient.GetSessionStatus(ctx)
@@ -630,3 +630,184 @@ func TestSessionMonitor_ResetSessionTracking(t *testing.T) {
 	assert.False(t, timestampExists, "Timestamp should be cleared after reset")
 	assert.False(t, statusExists, "Status should be cleared after reset")
 }
+
+// recordingNotifier records session notices and can fail the next sends
+type recordingNotifier struct {
+	sent     []string
+	sentAt   []time.Duration
+	failures int
+	clock    func() time.Duration
+}
+
+func (n *recordingNotifier) SendSystemNotification(ctx context.Context, sessionName, message string) error {
+	if n.failures > 0 {
+		n.failures--
+		return assert.AnError
+	}
+	n.sent = append(n.sent, message)
+	n.sentAt = append(n.sentAt, n.clock())
+	return nil
+}
+
+// newDownNotifyTestMonitor returns a monitor on a fake clock and a function that runs one
+// health check reporting status after advancing the clock by step
+func newDownNotifyTestMonitor(t *testing.T, notifyAfter, repeat time.Duration) (*SessionMonitor, *recordingNotifier, func(status string, step time.Duration)) {
+	t.Helper()
+	client := &mockWhatsAppClient{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	monitor := NewSessionMonitor(client, logger, 30*time.Second)
+
+	start := time.Now()
+	now := start
+	monitor.now = func() time.Time { return now }
+	notifier := &recordingNotifier{clock: func() time.Duration { return now.Sub(start) }}
+	monitor.SetDownNotifier(notifier, notifyAfter, repeat)
+
+	// Restarts keep failing, so the session stays down until the test reports WORKING
+	client.On("RestartSession", mock.Anything).Return(assert.AnError)
+	check := func(status string, step time.Duration) {
+		now = now.Add(step)
+		client.On("GetSessionStatus", mock.Anything).Return(&types.Session{Name: "test-session", Status: types.SessionStatus(status)}, nil).Once()
+		monitor.checkAndRecoverSession(context.Background())
+	}
+	return monitor, notifier, check
+}
+
+func TestSessionMonitor_DownNotificationCadence(t *testing.T) {
+	_, notifier, check := newDownNotifyTestMonitor(t, 0, 15*time.Minute)
+
+	// Two hours of downtime, checked every five minutes
+	check("STOPPED", 0)
+	for i := 0; i < 24; i++ {
+		check("STOPPED", 5*time.Minute)
+	}
+	check("WORKING", 5*time.Minute)
+	check("WORKING", 5*time.Minute)
+
+	require.Len(t, notifier.sent, 5)
+	// Immediate notice, then reminders after 15, 30 and 60 minutes, then recovery
+	assert.Equal(t, []time.Duration{0, 15 * time.Minute, 45 * time.Minute, 105 * time.Minute, 125 * time.Minute}, notifier.sentAt)
+	assert.Contains(t, notifier.sent[0], "test-session is down (status STOPPED)")
+	assert.Contains(t, notifier.sent[1], "still down after 15m0s")
+	assert.Contains(t, notifier.sent[3], "still down after 1h45m0s")
+	assert.Equal(t, "WhatsApp session test-session has recovered after 2h5m0s.", notifier.sent[4])
+}
+
+func TestSessionMonitor_DownNotificationThreshold(t *testing.T) {
+	_, notifier, check := newDownNotifyTestMonitor(t, 10*time.Minute, 15*time.Minute)
+
+	// A short outage below the threshold sends nothing, including no recovery notice
+	check("STOPPED", 0)
+	check("STOPPED", 5*time.Minute)
+	check("WORKING", 2*time.Minute)
+	assert.Empty(t, notifier.sent)
+
+	// A longer one is reported once it crosses the threshold
+	check("FAILED", 5*time.Minute)
+	check("FAILED", 5*time.Minute)
+	check("FAILED", 5*time.Minute)
+	check("WORKING", 5*time.Minute)
+	require.Len(t, notifier.sent, 2)
+	assert.Equal(t, []time.Duration{22 * time.Minute, 27 * time.Minute}, notifier.sentAt)
+	assert.Contains(t, notifier.sent[0], "is down (status FAILED)")
+	assert.Contains(t, notifier.sent[1], "has recovered after 15m0s")
+}
+
+func TestSessionMonitor_DownNotificationRetriesFailedSend(t *testing.T) {
+	_, notifier, check := newDownNotifyTestMonitor(t, 0, 15*time.Minute)
+	notifier.failures = 1
+
+	check("STOPPED", 0)
+	assert.Empty(t, notifier.sent)
+	check("STOPPED", 5*time.Minute)
+	require.Len(t, notifier.sent, 1)
+	assert.Contains(t, notifier.sent[0], "is down")
+}
+
+func TestSessionMonitor_NoDownNotifierSendsNothing(t *testing.T) {
+	client := &mockWhatsAppClient{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	monitor := NewSessionMonitor(client, logger, 30*time.Second)
+
+	client.On("GetSessionStatus", mock.Anything).Return(&types.Session{Name: "test-session", Status: "STOPPED"}, nil).Once()
+	client.On("RestartSession", mock.Anything).Return(assert.AnError).Once()
+	monitor.checkAndRecoverSession(context.Background())
+
+	assert.True(t, monitor.downSince.IsZero())
+	client.AssertExpectations(t)
+}
+
+// newRestartBackoffTestMonitor returns a monitor whose restarts always fail, and a check function
+// that advances the fake clock by step, reports status and returns whether a restart was attempted
+func newRestartBackoffTestMonitor(t *testing.T, base, max, resetAfter time.Duration) func(status string, step time.Duration) bool {
+	t.Helper()
+	client := &mockWhatsAppClient{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	monitor := NewSessionMonitor(client, logger, 30*time.Second)
+
+	now := time.Now()
+	monitor.now = func() time.Time { return now }
+	monitor.SetRestartBackoff(base, max, resetAfter)
+
+	restarts := 0
+	client.On("RestartSession", mock.Anything).Run(func(mock.Arguments) { restarts++ }).Return(assert.AnError)
+	return func(status string, step time.Duration) bool {
+		now = now.Add(step)
+		before := restarts
+		client.On("GetSessionStatus", mock.Anything).Return(&types.Session{Name: "test-session", Status: types.SessionStatus(status)}, nil).Once()
+		monitor.checkAndRecoverSession(context.Background())
+		return restarts > before
+	}
+}
+
+func TestSessionMonitor_RestartBackoffSpacesOutRepeatedFailures(t *testing.T) {
+	check := newRestartBackoffTestMonitor(t, time.Minute, 4*time.Minute, 10*time.Minute)
+
+	// Checked every 30 seconds for 15 minutes while every restart fails
+	var restartedAt []time.Duration
+	for elapsed := time.Duration(0); elapsed <= 15*time.Minute; elapsed += 30 * time.Second {
+		step := 30 * time.Second
+		if elapsed == 0 {
+			step = 0
+		}
+		if check("FAILED", step) {
+			restartedAt = append(restartedAt, elapsed)
+		}
+	}
+
+	// Waits of 1, 2 and 4 minutes, then capped at 4
+	assert.Equal(t, []time.Duration{0, time.Minute, 3 * time.Minute, 7 * time.Minute, 11 * time.Minute, 15 * time.Minute}, restartedAt)
+}
+
+func TestSessionMonitor_RestartBackoffResetsAfterStableRecovery(t *testing.T) {
+	check := newRestartBackoffTestMonitor(t, time.Minute, 8*time.Minute, 5*time.Minute)
+
+	require.True(t, check("FAILED", 0))
+	require.True(t, check("FAILED", time.Minute))
+	require.True(t, check("FAILED", 2*time.Minute))
+	// The next restart would now wait 4 minutes
+
+	// A brief recovery does not reset the backoff
+	check("WORKING", time.Minute)
+	check("WORKING", time.Minute)
+	assert.False(t, check("FAILED", time.Minute), "still backing off after a short recovery")
+	require.True(t, check("FAILED", time.Minute))
+
+	// Staying healthy for the cooldown does
+	check("WORKING", time.Minute)
+	check("WORKING", 5*time.Minute)
+	assert.True(t, check("FAILED", time.Minute), "restarts immediately after a stable recovery")
+	assert.False(t, check("FAILED", 30*time.Second))
+	assert.True(t, check("FAILED", 30*time.Second), "backoff starts over at the base wait")
+}
+
+func TestSessionMonitor_NoRestartBackoffRestartsEveryCheck(t *testing.T) {
+	check := newRestartBackoffTestMonitor(t, 0, 0, 0)
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, check("FAILED", 30*time.Second))
+	}
+}
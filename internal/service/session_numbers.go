@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"whatsignal/internal/privacy"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SessionNumbers caches the phone number each WhatsApp session is logged in with, as
+// reported by WAHA, so logs and status reports can say which account a session belongs to.
+type SessionNumbers struct {
+	waClient types.WAClient
+	logger   *logrus.Logger
+
+	mu      sync.RWMutex
+	numbers map[string]string
+}
+
+// NewSessionNumbers creates an empty cache that looks numbers up through waClient
+func NewSessionNumbers(waClient types.WAClient, logger *logrus.Logger) *SessionNumbers {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &SessionNumbers{
+		waClient: waClient,
+		logger:   logger,
+		numbers:  make(map[string]string),
+	}
+}
+
+// Refresh fetches the number of each session. A session whose lookup fails keeps the number
+// cached before; a session that is not logged in is dropped from the cache.
+func (n *SessionNumbers) Refresh(ctx context.Context, sessions []string) error {
+	var errs []error
+	for _, session := range sessions {
+		if err := n.RefreshSession(ctx, session); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RefreshSession fetches the number of a single session
+func (n *SessionNumbers) RefreshSession(ctx context.Context, session string) error {
+	me, err := n.waClient.GetMe(ctx, session)
+	if err != nil {
+		return fmt.Errorf("failed to get account of session %s: %w", session, err)
+	}
+
+	number := ""
+	if me != nil {
+		number = me.PhoneNumber()
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if number == "" {
+		delete(n.numbers, session)
+		return nil
+	}
+	if n.numbers[session] != number {
+		n.logger.WithFields(logrus.Fields{
+			"session": session,
+			"phone":   privacy.MaskPhoneNumber(number),
+		}).Info("WhatsApp session account")
+	}
+	n.numbers[session] = number
+	return nil
+}
+
+// Get returns the cached number of session, or "" if it is not known
+func (n *SessionNumbers) Get(session string) string {
+	if n == nil {
+		return ""
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.numbers[session]
+}
+
+// Redacted returns the cached number of session with all but the last four digits masked,
+// or "" if it is not known
+func (n *SessionNumbers) Redacted(session string) string {
+	return privacy.MaskPhoneNumber(n.Get(session))
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionNumbers_Refresh(t *testing.T) {
+	client := &mockWhatsAppClient{}
+	client.On("GetMe", mock.Anything, "personal").Return(&types.Me{ID: "15551234567@c.us"}, nil).Once()
+	client.On("GetMe", mock.Anything, "business").Return(nil, nil).Once()
+
+	numbers := NewSessionNumbers(client, logrus.New())
+	require.NoError(t, numbers.Refresh(context.Background(), []string{"personal", "business"}))
+
+	assert.Equal(t, "+15551234567", numbers.Get("personal"))
+	assert.Equal(t, "+*******4567", numbers.Redacted("personal"))
+	assert.Equal(t, "", numbers.Get("business"))
+	assert.Equal(t, "", numbers.Redacted("business"))
+
+	// A failed lookup keeps the number known before
+	client.On("GetMe", mock.Anything, "personal").Return(nil, errors.New("timeout")).Once()
+	err := numbers.Refresh(context.Background(), []string{"personal"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "session personal")
+	assert.Equal(t, "+15551234567", numbers.Get("personal"))
+
+	client.AssertExpectations(t)
+}
+
+func TestSessionNumbers_Nil(t *testing.T) {
+	var numbers *SessionNumbers
+	assert.Equal(t, "", numbers.Get("personal"))
+	assert.Equal(t, "", numbers.Redacted("personal"))
+}
+
+func TestSessionMonitor_LogsRedactedNumber(t *testing.T) {
+	client := &mockWhatsAppClient{}
+	client.On("GetSessionStatus", mock.Anything).Return(&types.Session{Name: "test-session", Status: "WORKING"}, nil)
+	client.On("GetMe", mock.Anything, "test-session").Return(&types.Me{ID: "15551234567@c.us"}, nil).Once()
+
+	logger, hook := logtest.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	monitor := NewSessionMonitor(client, logger, time.Minute)
+	monitor.SetSessionNumbers(NewSessionNumbers(client, logger))
+
+	// The first working check looks the number up, later checks reuse it
+	monitor.checkAndRecoverSession(context.Background())
+	hook.Reset()
+	monitor.checkAndRecoverSession(context.Background())
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "test-session", entry.Data["session"])
+	assert.Equal(t, "+*******4567", entry.Data["phone"])
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/errors"
+	"whatsignal/internal/metrics"
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// signalContactSuffix marks cached contacts that come from Signal profiles, keeping them apart
+// from WhatsApp contacts of the same number
+const signalContactSuffix = "@signal"
+
+// SignalProfileClient looks up Signal profiles for ContactService
+type SignalProfileClient interface {
+	GetContactProfile(ctx context.Context, number string) (*signaltypes.ContactProfile, error)
+}
+
+// SetSignalClient lets the service cache the profile names of Signal senders
+func (cs *ContactService) SetSignalClient(client SignalProfileClient) {
+	cs.sigProfiles = client
+}
+
+// SyncSignalContact caches the Signal profile name of number as the contact <number>@signal.
+// Numbers without a profile name are left uncached; without a Signal client it does nothing.
+func (cs *ContactService) SyncSignalContact(ctx context.Context, number string) error {
+	if cs.sigProfiles == nil || cs.db == nil {
+		return nil
+	}
+
+	profile, err := cs.sigProfiles.GetContactProfile(ctx, number)
+	if err != nil {
+		return fmt.Errorf("failed to fetch Signal profile: %w", err)
+	}
+	if profile == nil || profile.DisplayName() == "" {
+		cs.logger.WithContext(logrus.Fields{
+			"phone_number": SanitizePhoneNumber(number),
+		}).Debug("Signal sender has no profile name")
+		return nil
+	}
+
+	contact := &models.Contact{
+		ContactID:   number + signalContactSuffix,
+		PhoneNumber: number,
+		PushName:    profile.DisplayName(),
+	}
+	if err := cs.db.SaveContact(ctx, contact); err != nil {
+		cs.logger.LogWarn(
+			errors.Wrap(err, errors.ErrCodeDatabaseQuery, "failed to save Signal contact to cache"),
+			"Contact cache save failed",
+			logrus.Fields{"phone_number": SanitizePhoneNumber(number)},
+		)
+		return fmt.Errorf("failed to save Signal contact: %w", err)
+	}
+	metrics.IncrementCounter("signal_contact_syncs_total", nil, "Signal profile names cached as contacts")
+	return nil
+}
+
+// signalContactSyncer caches the profile names of Signal senders in the background while their
+// messages are relayed. Each number is synced at most once per interval.
+type signalContactSyncer struct {
+	mu       sync.Mutex
+	contacts ContactServiceInterface
+	interval time.Duration
+	synced   map[string]time.Time
+	wg       sync.WaitGroup
+	logger   *logrus.Logger
+}
+
+func newSignalContactSyncer(contacts ContactServiceInterface, interval time.Duration, logger *logrus.Logger) *signalContactSyncer {
+	return &signalContactSyncer{
+		contacts: contacts,
+		interval: interval,
+		synced:   make(map[string]time.Time),
+		logger:   logger,
+	}
+}
+
+// schedule starts a background sync of sender unless it was synced within the interval, or is
+// not a phone number, and reports whether it did
+func (s *signalContactSyncer) schedule(ctx context.Context, sender string) bool {
+	if !strings.HasPrefix(sender, "+") {
+		return false
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	if last, ok := s.synced[sender]; ok && now.Sub(last) < s.interval {
+		s.mu.Unlock()
+		return false
+	}
+	s.synced[sender] = now
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		// The relay that scheduled the sync may finish first
+		syncCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Duration(constants.ContactRefreshTimeoutSec)*time.Second)
+		defer cancel()
+
+		if err := s.contacts.SyncSignalContact(syncCtx, sender); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("phone", SanitizePhoneNumber(sender)).Debug("Background Signal contact sync failed")
+		}
+	}()
+	return true
+}
+
+// wait blocks until running syncs finish
+func (s *signalContactSyncer) wait() {
+	s.wg.Wait()
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactService_SyncSignalContact(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("caches the profile name", func(t *testing.T) {
+		db := &memoryContactDB{contacts: map[string]models.Contact{}}
+		sigClient := &mockSignalClient{}
+		sigClient.On("GetContactProfile", ctx, "+15550000001").Return(&signaltypes.ContactProfile{
+			Number:     "+15550000001",
+			GivenName:  "Alice",
+			FamilyName: "Smith",
+		}, nil).Once()
+		contacts := NewContactService(db, &mockWAClient{})
+		contacts.SetSignalClient(sigClient)
+
+		require.NoError(t, contacts.SyncSignalContact(ctx, "+15550000001"))
+
+		cached, ok := db.contacts["+15550000001"]
+		require.True(t, ok)
+		assert.Equal(t, "+15550000001@signal", cached.ContactID)
+		assert.Equal(t, "Alice Smith", cached.GetDisplayName())
+		sigClient.AssertExpectations(t)
+	})
+
+	t.Run("leaves numbers without a profile uncached", func(t *testing.T) {
+		db := &memoryContactDB{contacts: map[string]models.Contact{}}
+		sigClient := &mockSignalClient{}
+		sigClient.On("GetContactProfile", ctx, "+15550000002").Return(nil, nil).Once()
+		sigClient.On("GetContactProfile", ctx, "+15550000003").Return(&signaltypes.ContactProfile{About: "busy"}, nil).Once()
+		contacts := NewContactService(db, &mockWAClient{})
+		contacts.SetSignalClient(sigClient)
+
+		require.NoError(t, contacts.SyncSignalContact(ctx, "+15550000002"))
+		require.NoError(t, contacts.SyncSignalContact(ctx, "+15550000003"))
+
+		assert.Empty(t, db.contacts)
+		sigClient.AssertExpectations(t)
+	})
+
+	t.Run("returns fetch errors", func(t *testing.T) {
+		sigClient := &mockSignalClient{}
+		sigClient.On("GetContactProfile", ctx, "+15550000004").Return(nil, errors.New("get profile failed with status: 500")).Once()
+		contacts := NewContactService(&memoryContactDB{contacts: map[string]models.Contact{}}, &mockWAClient{})
+		contacts.SetSignalClient(sigClient)
+
+		err := contacts.SyncSignalContact(ctx, "+15550000004")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch Signal profile")
+	})
+
+	t.Run("does nothing without a Signal client", func(t *testing.T) {
+		db := &memoryContactDB{contacts: map[string]models.Contact{}}
+		require.NoError(t, NewContactService(db, &mockWAClient{}).SyncSignalContact(ctx, "+15550000001"))
+		assert.Empty(t, db.contacts)
+	})
+}
+
+func TestHandleSignalMessage_SyncsSenderProfile(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	contactDB := &cachingContactDB{memoryContactDB: memoryContactDB{contacts: map[string]models.Contact{}}}
+	sigProfiles := &mockSignalClient{}
+	sigProfiles.On("GetContactProfile", mock.Anything, "+15550000001").Return(&signaltypes.ContactProfile{Name: "Alice"}, nil).Once()
+	contacts := NewContactServiceWithConfigAndLogger(contactDB, &mockWAClient{}, 24, b.logger)
+	contacts.SetSignalClient(sigProfiles)
+	b.contactService = contacts
+	b.signalContacts = newSignalContactSyncer(contacts, time.Hour, b.logger)
+
+	// A message from an unlinked Signal group is skipped, but its sender is still synced
+	ctx := context.Background()
+	for _, id := range []string{"1700000000301", "1700000000302"} {
+		require.NoError(t, b.HandleSignalMessage(ctx, &signaltypes.SignalMessage{
+			MessageID: id,
+			Sender:    "+15550000001",
+			Message:   "hello",
+			GroupID:   testSignalGroupID,
+		}))
+		b.signalContacts.wait()
+	}
+
+	cached, err := contactDB.GetContactByPhone(ctx, "+15550000001")
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, "+15550000001@signal", cached.ContactID)
+	assert.Equal(t, "Alice", cached.GetDisplayName())
+	sigProfiles.AssertExpectations(t)
+}
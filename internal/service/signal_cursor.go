@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// signalCursor holds the newest processed Signal envelope timestamp (milliseconds) for the
+// intermediary account, mirrored in the database so a restart can skip redelivered envelopes.
+type signalCursor struct {
+	mu     sync.Mutex
+	loaded bool
+	value  int64
+}
+
+func (s *messageService) signalCursorEnabled() bool {
+	return s.signalConfig.PersistCursor && s.signalConfig.IntermediaryPhoneNumber != ""
+}
+
+// loadSignalCursor returns the cursor, reading it from the database the first time.
+// Load failures are logged and retried on the next call.
+func (s *messageService) loadSignalCursor(ctx context.Context) int64 {
+	s.cursor.mu.Lock()
+	defer s.cursor.mu.Unlock()
+
+	if s.cursor.loaded {
+		return s.cursor.value
+	}
+
+	stored, err := s.db.GetSignalCursor(ctx, s.signalConfig.IntermediaryPhoneNumber)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to load Signal cursor, processing all received envelopes")
+		return s.cursor.value
+	}
+
+	s.cursor.value = max(s.cursor.value, stored)
+	s.cursor.loaded = true
+	if s.cursor.value > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"cursor":    s.cursor.value,
+			"cursor_at": time.UnixMilli(s.cursor.value).UTC().Format(time.RFC3339),
+		}).Info("Resuming Signal processing from stored cursor")
+	}
+	return s.cursor.value
+}
+
+// isBeforeSignalCursor reports whether an envelope was already processed before a restart.
+// Envelopes newer than the cursor are new. Older ones are only skipped when a message mapping
+// records them, whatever their age: the cursor is shared by every sender and envelope
+// timestamps come from the sender's clock, so a phone with a slow clock or one delivering
+// queued messages can be behind it with messages never seen, which signal-cli will not return
+// again.
+func (s *messageService) isBeforeSignalCursor(ctx context.Context, msg *signaltypes.SignalMessage) bool {
+	if !s.signalCursorEnabled() || msg.Timestamp <= 0 {
+		return false
+	}
+
+	cursor := s.loadSignalCursor(ctx)
+	if cursor == 0 || msg.Timestamp > cursor {
+		return false
+	}
+
+	mapping, err := s.db.GetMessageMappingBySignalID(ctx, msg.MessageID)
+	return err == nil && mapping != nil
+}
+
+// advanceSignalCursor moves the cursor forward to timestamp and persists it
+func (s *messageService) advanceSignalCursor(ctx context.Context, timestamp int64) {
+	if !s.signalCursorEnabled() || timestamp <= 0 {
+		return
+	}
+
+	s.cursor.mu.Lock()
+	defer s.cursor.mu.Unlock()
+
+	if timestamp <= s.cursor.value {
+		return
+	}
+	if err := s.db.SetSignalCursor(ctx, s.signalConfig.IntermediaryPhoneNumber, timestamp); err != nil {
+		s.logger.WithError(err).Warn("Failed to persist Signal cursor")
+		return
+	}
+	s.cursor.value = timestamp
+}
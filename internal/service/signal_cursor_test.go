@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const cursorTestAccount = "+1111111111"
+
+func newCursorTestService(t *testing.T) (*messageService, *mockBridge, *mockDB, *mockSignalClient) {
+	bridge := new(mockBridge)
+	db := new(mockDB)
+	signalClient := &mockSignalClient{}
+	channelManager, err := NewChannelManager([]models.Channel{
+		{
+			WhatsAppSessionName:          "default",
+			SignalDestinationPhoneNumber: "+1234567890",
+		},
+		{
+			WhatsAppSessionName:          "second",
+			SignalDestinationPhoneNumber: "+1234567891",
+		},
+	})
+	require.NoError(t, err)
+
+	signalConfig := models.SignalConfig{
+		IntermediaryPhoneNumber: cursorTestAccount,
+		PollIntervalSec:         5,
+		PollTimeoutSec:          10,
+		PollWorkers:             1,
+		PersistCursor:           true,
+	}
+	svc := NewMessageService(bridge, db, new(mockMediaCache), signalClient, signalConfig, channelManager).(*messageService)
+
+	db.On("SavePendingMessages", mock.Anything, mock.Anything).Return(nil).Maybe()
+	db.On("DeletePendingMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return svc, bridge, db, signalClient
+}
+
+func TestSignalCursorAdvances(t *testing.T) {
+	svc, bridge, db, signalClient := newCursorTestService(t)
+	ctx := context.Background()
+
+	db.On("GetSignalCursor", ctx, cursorTestAccount).Return(int64(0), nil).Once()
+	db.On("SetSignalCursor", ctx, cursorTestAccount, mock.AnythingOfType("int64")).Return(nil)
+	bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.Anything, "+1234567890").Return(nil)
+
+	signalClient.On("ReceiveMessages", ctx, 10).Return([]signaltypes.SignalMessage{
+		{MessageID: "1000", Sender: "+1234567890", Message: "first", Timestamp: 1000},
+		{MessageID: "2000", Sender: "+1234567890", Message: "second", Timestamp: 2000},
+	}, nil).Once()
+
+	require.NoError(t, svc.PollSignalMessages(ctx))
+
+	db.AssertCalled(t, "SetSignalCursor", ctx, cursorTestAccount, int64(2000))
+	assert.Equal(t, int64(2000), svc.loadSignalCursor(ctx))
+	bridge.AssertNumberOfCalls(t, "HandleSignalMessageWithDestination", 2)
+
+	// A failed message does not advance the cursor
+	failing := signaltypes.SignalMessage{MessageID: "3000", Sender: "+1234567890", Message: "third", Timestamp: 3000}
+	bridge.ExpectedCalls = nil
+	bridge.On("HandleSignalMessageWithDestination", ctx, mock.Anything, "+1234567890").Return(assert.AnError)
+	require.Error(t, svc.DispatchSingleSignalMessage(ctx, failing))
+	assert.Equal(t, int64(2000), svc.loadSignalCursor(ctx))
+	db.AssertNotCalled(t, "SetSignalCursor", ctx, cursorTestAccount, int64(3000))
+}
+
+func TestSignalCursorResumesAfterRestart(t *testing.T) {
+	svc, bridge, db, signalClient := newCursorTestService(t)
+	ctx := context.Background()
+
+	stored := time.Now().UnixMilli()
+	db.On("GetSignalCursor", ctx, cursorTestAccount).Return(stored, nil).Once()
+	db.On("SetSignalCursor", ctx, cursorTestAccount, mock.AnythingOfType("int64")).Return(nil)
+
+	// Startup loads the stored cursor
+	db.On("GetPendingMessages", ctx, mock.Anything).Return([]models.PendingSignalMessage{}, nil)
+	require.NoError(t, svc.ProcessPendingMessages(ctx))
+	assert.Equal(t, stored, svc.loadSignalCursor(ctx))
+
+	old := signaltypes.SignalMessage{MessageID: "old", Sender: "+1234567890", Message: "old", Timestamp: stored - 60_000}
+	recentProcessed := signaltypes.SignalMessage{MessageID: "recent-done", Sender: "+1234567890", Message: "done", Timestamp: stored - 5_000}
+	recentMissed := signaltypes.SignalMessage{MessageID: "recent-missed", Sender: "+1234567890", Message: "missed", Timestamp: stored - 4_000}
+	newer := signaltypes.SignalMessage{MessageID: "new", Sender: "+1234567890", Message: "new", Timestamp: stored + 1_000}
+
+	db.On("GetMessageMappingBySignalID", ctx, "old").Return(&models.MessageMapping{SignalMsgID: "old"}, nil)
+	db.On("GetMessageMappingBySignalID", ctx, "recent-done").Return(&models.MessageMapping{SignalMsgID: "recent-done"}, nil)
+	db.On("GetMessageMappingBySignalID", ctx, "recent-missed").Return(nil, nil)
+
+	var relayed []string
+	bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.Anything, "+1234567890").Run(func(args mock.Arguments) {
+		relayed = append(relayed, args.Get(1).(*signaltypes.SignalMessage).MessageID)
+	}).Return(nil)
+
+	signalClient.On("ReceiveMessages", ctx, 10).Return([]signaltypes.SignalMessage{old, recentProcessed, recentMissed, newer}, nil).Once()
+	require.NoError(t, svc.PollSignalMessages(ctx))
+
+	assert.ElementsMatch(t, []string{"recent-missed", "new"}, relayed)
+	assert.Equal(t, newer.Timestamp, svc.loadSignalCursor(ctx))
+	db.AssertNumberOfCalls(t, "GetSignalCursor", 1)
+}
+
+func TestSignalCursorRelaysLaggingSender(t *testing.T) {
+	svc, bridge, db, signalClient := newCursorTestService(t)
+	ctx := context.Background()
+
+	db.On("GetSignalCursor", ctx, cursorTestAccount).Return(int64(0), nil).Once()
+	db.On("SetSignalCursor", ctx, cursorTestAccount, mock.AnythingOfType("int64")).Return(nil)
+
+	// One channel's phone moves the cursor forward; the other's, whose clock runs two minutes
+	// slow, sends a message afterwards that is timestamped well behind it
+	now := time.Now().UnixMilli()
+	ahead := signaltypes.SignalMessage{MessageID: "ahead", Sender: "+1234567890", Message: "first", Timestamp: now}
+	lagging := signaltypes.SignalMessage{MessageID: "lagging", Sender: "+1234567891", Message: "second", Timestamp: now - 120_000}
+	db.On("GetMessageMappingBySignalID", ctx, "lagging").Return(nil, nil)
+
+	var relayed []string
+	bridge.On("HandleSignalMessageWithDestination", polledMessageCtx(), mock.Anything, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		relayed = append(relayed, args.Get(1).(*signaltypes.SignalMessage).MessageID)
+	}).Return(nil)
+
+	signalClient.On("ReceiveMessages", ctx, 10).Return([]signaltypes.SignalMessage{ahead}, nil).Once()
+	require.NoError(t, svc.PollSignalMessages(ctx))
+	signalClient.On("ReceiveMessages", ctx, 10).Return([]signaltypes.SignalMessage{lagging}, nil).Once()
+	require.NoError(t, svc.PollSignalMessages(ctx))
+
+	assert.Equal(t, []string{"ahead", "lagging"}, relayed, "a message behind the cursor that was never relayed is not dropped")
+	assert.Equal(t, now, svc.loadSignalCursor(ctx))
+}
+
+func TestSignalCursorDisabled(t *testing.T) {
+	svc, bridge, db, _ := newCursorTestService(t)
+	svc.signalConfig.PersistCursor = false
+	ctx := context.Background()
+
+	bridge.On("HandleSignalMessageWithDestination", ctx, mock.Anything, "+1234567890").Return(nil)
+	require.NoError(t, svc.DispatchSingleSignalMessage(ctx, signaltypes.SignalMessage{MessageID: "1", Sender: "+1234567890", Message: "hi", Timestamp: 1}))
+
+	db.AssertNotCalled(t, "GetSignalCursor", mock.Anything, mock.Anything)
+	db.AssertNotCalled(t, "SetSignalCursor", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"whatsignal/internal/metrics"
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// handleSignalEdit applies a Signal edit to the WhatsApp message the edited message was bridged
+// to. The existing mapping is kept, so replies, receipts and deletions of the message keep
+// resolving to the same WhatsApp message; edits of messages that were never bridged are dropped.
+func (b *bridge) handleSignalEdit(ctx context.Context, msg *signaltypes.SignalMessage, destination string) error {
+	startTime := time.Now()
+
+	sessionName, err := b.channelManager.GetWhatsAppSession(destination)
+	if err != nil {
+		return fmt.Errorf("failed to determine WhatsApp session for Signal destination %s: %w", destination, err)
+	}
+
+	metrics.IncrementCounter("message_processing_total", map[string]string{
+		"direction":    "signal_to_whatsapp",
+		"session":      sessionName,
+		"message_type": "edit",
+		"has_media":    "false",
+	}, "Total message processing attempts")
+
+	targetID := msg.Edit.TargetMessageID
+	if targetID == "" {
+		targetID = fmt.Sprintf("%d", msg.Edit.TargetTimestamp)
+	}
+
+	mapping, err := b.db.GetMessageMappingBySignalID(ctx, targetID)
+	if err != nil {
+		metrics.IncrementCounter("message_processing_failures", map[string]string{
+			"direction":    "signal_to_whatsapp",
+			"session":      sessionName,
+			"message_type": "edit",
+			"stage":        "resolve_mapping",
+		}, "Message processing failures by stage")
+		return fmt.Errorf("failed to get message mapping for edit: %w", err)
+	}
+	if mapping == nil || mapping.WhatsAppMsgID == "" {
+		b.logger.WithContext(ctx).WithField("targetMessageID", SanitizeMessageID(targetID)).Debug("Skipping edit — no mapping found for target message")
+		return nil
+	}
+
+	text, _ := whatsAppMentions(mapping.WhatsAppChatID, msg.Message, msg.Mentions)
+	text = b.transforms.Apply(models.TransformSignalToWhatsApp, text)
+	if strings.TrimSpace(text) == "" {
+		// Media-only revisions have no text WhatsApp could show
+		return nil
+	}
+
+	if mapping.SessionName != "" {
+		sessionName = mapping.SessionName
+	}
+	if err := b.waClient.EditMessageWithSession(ctx, mapping.WhatsAppChatID, mapping.WhatsAppMsgID, text, sessionName); err != nil {
+		metrics.IncrementCounter("message_processing_failures", map[string]string{
+			"direction":    "signal_to_whatsapp",
+			"session":      sessionName,
+			"message_type": "edit",
+			"stage":        "send_whatsapp",
+		}, "Message processing failures by stage")
+		return fmt.Errorf("failed to edit message in WhatsApp: %w", err)
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession:   sessionName,
+		"whatsappChatID":  SanitizePhoneNumber(mapping.WhatsAppChatID),
+		"whatsappMsgID":   SanitizeWhatsAppMessageID(mapping.WhatsAppMsgID),
+		"targetMessageID": SanitizeMessageID(targetID),
+	}).Info("Successfully edited message in WhatsApp")
+
+	metrics.IncrementCounter("message_processing_success", map[string]string{
+		"direction":    "signal_to_whatsapp",
+		"session":      sessionName,
+		"message_type": "edit",
+		"has_media":    "false",
+	}, "Successful message processing operations")
+	metrics.RecordTimer("message_processing_duration", time.Since(startTime), map[string]string{
+		"direction":    "signal_to_whatsapp",
+		"session":      sessionName,
+		"message_type": "edit",
+	}, "Message processing duration")
+
+	return nil
+}
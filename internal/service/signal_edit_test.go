@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func signalEdit(text string, target int64) *signaltypes.SignalMessage {
+	return &signaltypes.SignalMessage{
+		MessageID: "1700000000009",
+		Sender:    "+1234567890",
+		Message:   text,
+		Timestamp: 1700000000009,
+		Edit:      &signaltypes.SignalEdit{TargetMessageID: "1700000000004", TargetTimestamp: target},
+	}
+}
+
+func TestHandleSignalEdit_EditsBridgedMessage(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mockDB := b.db.(*mockDatabaseService)
+	waClient := b.waClient.(*mockWhatsAppClient)
+	mockDB.On("GetMessageMappingBySignalID", ctx, "1700000000004").Return(&models.MessageMapping{
+		WhatsAppChatID: "15550001111@c.us",
+		WhatsAppMsgID:  "wa_original",
+		SignalMsgID:    "1700000000004",
+		SessionName:    "default",
+	}, nil).Once()
+	waClient.On("EditMessageWithSession", ctx, "15550001111@c.us", "wa_original", "see you at 9", "default").Return(nil).Once()
+
+	require.NoError(t, b.HandleSignalMessage(ctx, signalEdit("see you at 9", 1700000000004)))
+
+	waClient.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+	mockDB.AssertNotCalled(t, "SaveMessageMapping", mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "GetLatestMessageMappingBySession", mock.Anything, mock.Anything)
+	waClient.AssertNotCalled(t, "SendTextWithSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleSignalEdit_SkipsUnbridgedMessage(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	b.db.(*mockDatabaseService).On("GetMessageMappingBySignalID", ctx, "1700000000004").Return(nil, nil).Once()
+
+	require.NoError(t, b.HandleSignalMessage(ctx, signalEdit("see you at 9", 1700000000004)))
+
+	b.waClient.(*mockWhatsAppClient).AssertNotCalled(t, "EditMessageWithSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	b.waClient.(*mockWhatsAppClient).AssertNotCalled(t, "SendTextWithSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleSignalEdit_ReturnsWhatsAppError(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	b.db.(*mockDatabaseService).On("GetMessageMappingBySignalID", ctx, "1700000000004").Return(&models.MessageMapping{
+		WhatsAppChatID: "15550001111@c.us",
+		WhatsAppMsgID:  "wa_original",
+		SessionName:    "default",
+	}, nil).Once()
+	b.waClient.(*mockWhatsAppClient).On("EditMessageWithSession", ctx, "15550001111@c.us", "wa_original", "see you at 9", "default").
+		Return(errors.New("edit failed with status 400")).Once()
+
+	err := b.HandleSignalMessage(ctx, signalEdit("see you at 9", 1700000000004))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to edit message in WhatsApp")
+}
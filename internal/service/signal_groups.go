@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"whatsignal/internal/metrics"
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chatCommandLinkGroup routes the Signal group it is sent in to a WhatsApp group
+const chatCommandLinkGroup = "/linkgroup"
+
+// parseLinkGroupCommand reports whether a Signal message is a /linkgroup command and returns
+// its argument, the WhatsApp group ID
+func parseLinkGroupCommand(msg *signaltypes.SignalMessage) (string, bool) {
+	if len(msg.Attachments) > 0 {
+		return "", false
+	}
+	fields := strings.Fields(msg.Message)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], chatCommandLinkGroup) {
+		return "", false
+	}
+	return strings.Join(fields[1:], ""), true
+}
+
+// handleLinkGroupCommand maps the Signal group a /linkgroup command was sent in to the WhatsApp
+// group it names and confirms it on Signal. Only the channel's own Signal number may link
+// groups; the command is never relayed either way. It reports whether msg was a link command.
+func (b *bridge) handleLinkGroupCommand(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string) bool {
+	whatsAppGroupID, ok := parseLinkGroupCommand(msg)
+	if !ok {
+		return false
+	}
+
+	reply := fmt.Sprintf("Usage: %s 120363028123456789@g.us (send it in the Signal group to link)", chatCommandLinkGroup)
+	if strings.HasSuffix(whatsAppGroupID, "@g.us") {
+		err := b.db.SaveGroupMapping(ctx, &models.GroupMapping{
+			SessionName:     sessionName,
+			SignalGroupID:   msg.GroupID,
+			WhatsAppGroupID: whatsAppGroupID,
+		})
+		if err != nil {
+			b.logger.WithContext(ctx).WithError(err).WithField(LogFieldSession, sessionName).Warn("Failed to save group mapping")
+			reply = "Could not link this group."
+		} else {
+			b.logger.WithContext(ctx).WithFields(logrus.Fields{
+				LogFieldSession: sessionName,
+				LogFieldChatID:  SanitizePhoneNumber(whatsAppGroupID),
+			}).Info("Linked Signal group to WhatsApp group")
+			reply = fmt.Sprintf("Signal group linked to %s.", b.chatDisplayName(ctx, sessionName, whatsAppGroupID))
+		}
+	}
+
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, reply); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to send linkgroup reply")
+	}
+	return true
+}
+
+// resolveSignalGroupMapping returns the WhatsApp group msg's Signal group is mapped to, as a
+// mapping whose WhatsAppMsgID is the quoted message when msg replies to a message bridged from
+// that group. It returns nil when the Signal group is not mapped; the message is then skipped.
+func (b *bridge) resolveSignalGroupMapping(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string) (*models.MessageMapping, error) {
+	groupMapping, err := b.db.GetGroupMapping(ctx, sessionName, msg.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group mapping: %w", err)
+	}
+	if groupMapping == nil {
+		b.logger.WithContext(ctx).WithFields(logrus.Fields{
+			LogFieldSession: sessionName,
+			"signal_msg_id": SanitizeMessageID(msg.MessageID),
+		}).Warnf("Signal group is not mapped to a WhatsApp group, skipping message; send %s <WhatsApp group ID> in the group to map it", chatCommandLinkGroup)
+		metrics.IncrementCounter("signal_group_messages_unmapped_total", map[string]string{
+			"session": sessionName,
+		}, "Signal group messages skipped because the group is not mapped to a WhatsApp group")
+		return nil, nil
+	}
+
+	mapping := &models.MessageMapping{WhatsAppChatID: groupMapping.WhatsAppGroupID, SessionName: sessionName}
+	if msg.QuotedMessage != nil {
+		quoted, err := b.db.GetMessageMapping(ctx, msg.QuotedMessage.ID)
+		if err != nil {
+			b.logger.WithContext(ctx).WithError(err).Warn("Failed to look up quoted message in Signal group, sending without reply")
+		} else if quoted != nil && quoted.WhatsAppChatID == groupMapping.WhatsAppGroupID {
+			mapping.WhatsAppMsgID = quoted.WhatsAppMsgID
+		}
+	}
+	return mapping, nil
+}
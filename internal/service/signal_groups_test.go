@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testSignalGroupID = "c2lnbmFsLWdyb3VwLWlk"
+
+func TestHandleSignalGroupMessage_RelaysMappedGroup(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mockDB := b.db.(*mockDatabaseService)
+	require.NoError(t, mockDB.SaveGroupMapping(ctx, &models.GroupMapping{
+		SessionName:     "default",
+		SignalGroupID:   testSignalGroupID,
+		WhatsAppGroupID: "120363028123456789@g.us",
+	}))
+	mockDB.On("SaveMessageMapping", ctx, mock.MatchedBy(func(mapping *models.MessageMapping) bool {
+		return mapping.WhatsAppChatID == "120363028123456789@g.us" && mapping.WhatsAppMsgID == "wa_group_1" && mapping.SessionName == "default"
+	})).Return(nil).Once()
+	b.waClient.(*mockWhatsAppClient).On("SendTextWithSession", ctx, "120363028123456789@g.us", "Running 10 minutes late", "", "default").
+		Return(&types.SendMessageResponse{MessageID: "wa_group_1", Status: "sent"}, nil).Once()
+
+	err := b.HandleSignalMessage(ctx, &signaltypes.SignalMessage{
+		MessageID: "1700000000001",
+		Sender:    "+15550000001",
+		Message:   "Running 10 minutes late",
+		Timestamp: time.Now().UnixMilli(),
+		GroupID:   testSignalGroupID,
+	})
+	require.NoError(t, err)
+
+	b.waClient.(*mockWhatsAppClient).AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+	mockDB.AssertNotCalled(t, "GetLatestGroupMessageMappingBySession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleSignalGroupMessage_SkipsUnmappedGroup(t *testing.T) {
+	b, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	err := b.HandleSignalMessage(ctx, &signaltypes.SignalMessage{
+		MessageID: "1700000000002",
+		Sender:    "+15550000001",
+		Message:   "Anyone there?",
+		Timestamp: time.Now().UnixMilli(),
+		GroupID:   testSignalGroupID,
+	})
+	require.NoError(t, err, "messages from unmapped groups are skipped, not failed")
+
+	b.waClient.(*mockWhatsAppClient).AssertNotCalled(t, "SendTextWithSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	b.db.(*mockDatabaseService).AssertNotCalled(t, "SaveMessageMapping", mock.Anything, mock.Anything)
+	b.db.(*mockDatabaseService).AssertNotCalled(t, "GetLatestGroupMessageMappingBySession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLinkGroupCommand(t *testing.T) {
+	t.Run("links the group", func(t *testing.T) {
+		bridge, mockDB, _, sigClient, sends := setupWhoisBridge(t)
+
+		err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{
+			MessageID: "cmd1",
+			Sender:    "+1234567890",
+			Message:   "/linkgroup 120363028123456789@g.us",
+			GroupID:   testSignalGroupID,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, *sends)
+		assert.Equal(t, "Signal group linked to 120363028123456789@g.us.", sigClient.lastMessage)
+		mapping, err := mockDB.GetGroupMapping(context.Background(), "default", testSignalGroupID)
+		require.NoError(t, err)
+		require.NotNil(t, mapping)
+		assert.Equal(t, "120363028123456789@g.us", mapping.WhatsAppGroupID)
+	})
+
+	t.Run("rejects an argument that is not a group", func(t *testing.T) {
+		bridge, mockDB, _, sigClient, _ := setupWhoisBridge(t)
+
+		err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{
+			MessageID: "cmd2",
+			Sender:    "+1234567890",
+			Message:   "/linkgroup 15551234567@c.us",
+			GroupID:   testSignalGroupID,
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, sigClient.lastMessage, "Usage: /linkgroup")
+		mapping, err := mockDB.GetGroupMapping(context.Background(), "default", testSignalGroupID)
+		require.NoError(t, err)
+		assert.Nil(t, mapping)
+	})
+
+	t.Run("ignores other group members", func(t *testing.T) {
+		bridge, mockDB, _, sigClient, _ := setupWhoisBridge(t)
+
+		err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{
+			MessageID: "cmd3",
+			Sender:    "+15550000001",
+			Message:   "/linkgroup 120363028123456789@g.us",
+			GroupID:   testSignalGroupID,
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, sigClient.lastMessage)
+		mapping, err := mockDB.GetGroupMapping(context.Background(), "default", testSignalGroupID)
+		require.NoError(t, err)
+		assert.Nil(t, mapping)
+	})
+}
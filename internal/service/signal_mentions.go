@@ -0,0 +1,77 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	signaltypes "whatsignal/pkg/signal/types"
+)
+
+// whatsAppMentions rewrites the Signal mentions in text for the WhatsApp chat chatID. In group
+// chats a mention of a Signal phone number becomes "@<number>" and the returned IDs list the
+// matching @c.us participants for WhatsApp to notify. Mentions that cannot be mapped (UUID-only
+// mentions, or any mention outside a group) are written out literally as "@Name".
+func whatsAppMentions(chatID, text string, mentions []signaltypes.SignalMention) (string, []string) {
+	if len(mentions) == 0 {
+		return text, nil
+	}
+
+	isGroup := strings.HasSuffix(chatID, "@g.us")
+
+	// Signal ranges count UTF-16 code units; replace from the end so earlier ranges stay valid
+	sorted := make([]signaltypes.SignalMention, len(mentions))
+	copy(sorted, mentions)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start > sorted[j].Start })
+
+	units := utf16.Encode([]rune(text))
+	var ids []string
+	end := len(units)
+	for _, mention := range sorted {
+		if mention.Start < 0 || mention.Length < 0 || mention.Start+mention.Length > end {
+			continue // overlapping or out of range
+		}
+
+		replacement := mentionLiteral(mention)
+		if number := mentionNumber(mention); isGroup && number != "" {
+			replacement = "@" + number
+			ids = append(ids, number+"@c.us")
+		}
+
+		tail := append(utf16.Encode([]rune(replacement)), units[mention.Start+mention.Length:]...)
+		units = append(units[:mention.Start:mention.Start], tail...)
+		end = mention.Start
+	}
+
+	// Report mentions in reading order
+	for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+	return string(utf16.Decode(units)), ids
+}
+
+// mentionNumber returns the digits of a mention's phone number, or "" when it has none
+func mentionNumber(mention signaltypes.SignalMention) string {
+	number := strings.TrimPrefix(strings.TrimSpace(mention.Number), "+")
+	if len(number) < 6 || len(number) > 15 {
+		return ""
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return number
+}
+
+// mentionLiteral is the text written in place of a mention that cannot become a WhatsApp mention
+func mentionLiteral(mention signaltypes.SignalMention) string {
+	switch {
+	case mention.Name != "" && mention.Name != mention.UUID:
+		return "@" + mention.Name
+	case mention.Number != "":
+		return "@" + mention.Number
+	default:
+		return "@someone"
+	}
+}
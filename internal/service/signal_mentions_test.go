@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhatsAppMentions(t *testing.T) {
+	tests := []struct {
+		name         string
+		chatID       string
+		text         string
+		mentions     []signaltypes.SignalMention
+		expectedText string
+		expectedIDs  []string
+	}{
+		{
+			name:         "no mentions",
+			chatID:       "family@g.us",
+			text:         "hello",
+			expectedText: "hello",
+		},
+		{
+			name:         "number mention in a group",
+			chatID:       "family@g.us",
+			text:         "hi \uFFFC!",
+			mentions:     []signaltypes.SignalMention{{Start: 3, Length: 1, Number: "+15550001111", Name: "Alice"}},
+			expectedText: "hi @15550001111!",
+			expectedIDs:  []string{"15550001111@c.us"},
+		},
+		{
+			name:   "several mentions keep reading order",
+			chatID: "family@g.us",
+			text:   "\uFFFC and \uFFFC",
+			mentions: []signaltypes.SignalMention{
+				{Start: 6, Length: 1, Number: "+15550002222"},
+				{Start: 0, Length: 1, Number: "+15550001111"},
+			},
+			expectedText: "@15550001111 and @15550002222",
+			expectedIDs:  []string{"15550001111@c.us", "15550002222@c.us"},
+		},
+		{
+			name:         "offsets count UTF-16 code units",
+			chatID:       "family@g.us",
+			text:         "😀 \uFFFC",
+			mentions:     []signaltypes.SignalMention{{Start: 3, Length: 1, Number: "+15550001111"}},
+			expectedText: "😀 @15550001111",
+			expectedIDs:  []string{"15550001111@c.us"},
+		},
+		{
+			name:         "UUID-only mention falls back to the name",
+			chatID:       "family@g.us",
+			text:         "hi \uFFFC",
+			mentions:     []signaltypes.SignalMention{{Start: 3, Length: 1, UUID: "0b6a1c3e-uuid", Name: "Bob"}},
+			expectedText: "hi @Bob",
+		},
+		{
+			name:         "mention outside a group stays literal",
+			chatID:       "15550009999@c.us",
+			text:         "hi \uFFFC",
+			mentions:     []signaltypes.SignalMention{{Start: 3, Length: 1, Number: "+15550001111", Name: "Alice"}},
+			expectedText: "hi @Alice",
+		},
+		{
+			name:         "out of range mention is ignored",
+			chatID:       "family@g.us",
+			text:         "hi",
+			mentions:     []signaltypes.SignalMention{{Start: 5, Length: 1, Number: "+15550001111"}},
+			expectedText: "hi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, ids := whatsAppMentions(tt.chatID, tt.text, tt.mentions)
+			assert.Equal(t, tt.expectedText, text)
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestHandleSignalGroupMessage_RelaysMentions(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	mapping := &models.MessageMapping{
+		WhatsAppChatID: "group123@g.us",
+		WhatsAppMsgID:  "wa_msg_1",
+		SignalMsgID:    "sig_orig",
+		ForwardedAt:    time.Now(),
+	}
+	bridge.db.(*mockDatabaseService).On("GetMessageMapping", ctx, "wa_msg_1").Return(mapping, nil).Once()
+	bridge.db.(*mockDatabaseService).On("SaveMessageMapping", ctx, mock.AnythingOfType("*models.MessageMapping")).Return(nil).Once()
+
+	waClient := bridge.waClient.(*mockWhatsAppClient)
+	waClient.On("SendMentionsWithSession", ctx, "group123@g.us", "@15550001111 can you bring chairs?", []string{"15550001111@c.us"}, "wa_msg_1", "default").
+		Return(&types.SendMessageResponse{MessageID: "wa_msg_reply", Status: "sent"}, nil).Once()
+
+	msg := &signaltypes.SignalMessage{
+		MessageID: "sig_reply_1",
+		Sender:    "group.123",
+		Message:   "\uFFFC can you bring chairs?",
+		Timestamp: time.Now().UnixMilli(),
+		Mentions:  []signaltypes.SignalMention{{Start: 0, Length: 1, Number: "+15550001111", Name: "Alice"}},
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{
+			ID: "wa_msg_1",
+		},
+	}
+
+	err := bridge.HandleSignalMessage(ctx, msg)
+	require.NoError(t, err)
+	waClient.AssertExpectations(t)
+}
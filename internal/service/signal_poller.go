@@ -34,6 +34,7 @@ import (
 	"whatsignal/internal/models"
 	"whatsignal/internal/privacy"
 	"whatsignal/internal/retry"
+	"whatsignal/internal/tracing"
 	"whatsignal/pkg/signal"
 	signaltypes "whatsignal/pkg/signal/types"
 
@@ -68,6 +69,7 @@ type SignalPoller struct {
 	lastSuccessTime     time.Time
 	wsReceiver          *signal.WSReceiver // non-nil when using WebSocket mode
 	useWebSocket        bool
+	queue               *WorkQueue // nil leaves polling unbounded
 }
 
 // NewSignalPoller creates a new Signal polling service.
@@ -87,6 +89,23 @@ func NewSignalPoller(signalClient signal.Client, messageService MessageService,
 	}
 }
 
+// SetWorkQueue makes each poll, or each WebSocket message, take a slot in queue, so polling
+// pauses while the queue is full. It must be called before Start.
+func (sp *SignalPoller) SetWorkQueue(queue *WorkQueue) {
+	sp.queue = queue
+}
+
+// acquireQueueSlot waits for a free work queue slot and reports whether it got one before ctx
+// was done. Callers that get one must release it.
+func (sp *SignalPoller) acquireQueueSlot(ctx context.Context) bool {
+	if sp.queue.TryAcquire() {
+		return true
+	}
+	sp.logger.WithFields(sp.logFields()).WithField("queue_depth", sp.queue.Depth()).Debug("Work queue full, pausing Signal polling")
+	metrics.IncrementCounter("signal_poll_paused_total", nil, "Signal polls delayed because the work queue was full")
+	return sp.queue.Acquire(ctx) == nil
+}
+
 // validateConfig checks if the poller configuration is valid.
 // It returns an error if any configuration values are invalid or out of range.
 func (sp *SignalPoller) validateConfig() error {
@@ -287,6 +306,11 @@ func (sp *SignalPoller) pollWithRetry() {
 	// This prevents "context deadline exceeded" errors when retries take longer than expected
 	ctx := sp.ctx
 
+	if !sp.acquireQueueSlot(ctx) {
+		return
+	}
+	defer sp.queue.Release()
+
 	startTime := time.Now()
 
 	// Check verbose logging once at start
@@ -481,9 +505,12 @@ func (sp *SignalPoller) wsReadLoop(conn *websocket.Conn) {
 			return
 		}
 
+		receivedAt := time.Now()
 		converted := sigClient.ConvertRestMessages(sp.ctx, []signaltypes.RestMessage{*msg})
 		for _, m := range converted {
-			sp.logger.WithFields(logrus.Fields{
+			// Each received message gets its own request ID for log and HTTP correlation
+			msgCtx := WithIngressTime(tracing.WithRequestID(sp.ctx, tracing.GenerateRequestID()), receivedAt)
+			sp.logger.WithContext(msgCtx).WithFields(logrus.Fields{
 				"messageID":  m.MessageID,
 				"sender":     SanitizePhoneNumber(m.Sender),
 				"hasQuote":   m.QuotedMessage != nil,
@@ -491,9 +518,13 @@ func (sp *SignalPoller) wsReadLoop(conn *websocket.Conn) {
 				"hasText":    m.Message != "",
 			}).Info("WebSocket message converted")
 
-			if err := sp.messageService.DispatchSingleSignalMessage(sp.ctx, m); err != nil {
-				sp.logger.WithError(err).WithField("messageID", m.MessageID).Error("Failed to dispatch WebSocket message")
+			if !sp.acquireQueueSlot(sp.ctx) {
+				return
+			}
+			if err := sp.messageService.DispatchSingleSignalMessage(msgCtx, m); err != nil {
+				sp.logger.WithContext(msgCtx).WithError(err).WithField("messageID", m.MessageID).Error("Failed to dispatch WebSocket message")
 			}
+			sp.queue.Release()
 		}
 	}
 }
@@ -126,6 +126,31 @@ func (m *mockMessageService) SendSignalNotification(ctx context.Context, session
 	return args.Error(0)
 }
 
+func (m *mockMessageService) SendSystemNotification(ctx context.Context, sessionName, message string) error {
+	args := m.Called(ctx, sessionName, message)
+	return args.Error(0)
+}
+
+func (m *mockMessageService) HandleWhatsAppReaction(ctx context.Context, sessionName string, reaction WhatsAppReaction, mapping *models.MessageMapping) error {
+	args := m.Called(ctx, sessionName, reaction, mapping)
+	return args.Error(0)
+}
+
+func (m *mockMessageService) HandleWhatsAppPin(ctx context.Context, sessionName string, pin models.WhatsAppPin) error {
+	args := m.Called(ctx, sessionName, pin)
+	return args.Error(0)
+}
+
+func (m *mockMessageService) HandleWhatsAppLiveLocation(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation) error {
+	args := m.Called(ctx, sessionName, loc)
+	return args.Error(0)
+}
+
+func (m *mockMessageService) HandleWhatsAppGroupParticipants(ctx context.Context, sessionName string, change models.WhatsAppGroupParticipants) error {
+	args := m.Called(ctx, sessionName, change)
+	return args.Error(0)
+}
+
 func (m *mockMessageService) GetMessageMappingByWhatsAppID(ctx context.Context, whatsappID string) (*models.MessageMapping, error) {
 	args := m.Called(ctx, whatsappID)
 	if args.Get(0) == nil {
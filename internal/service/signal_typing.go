@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"whatsignal/internal/metrics"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// signalTypingRelay mirrors the Signal user's typing indicator into the WhatsApp chat they are
+// replying to. Signal repeats STARTED while the user keeps typing but may never send STOPPED,
+// so each indicator is cleared after hold unless it is renewed.
+type signalTypingRelay struct {
+	mu       sync.Mutex
+	waClient types.WAClient
+	logger   *logrus.Logger
+	hold     time.Duration
+	active   map[string]*time.Timer
+}
+
+func newSignalTypingRelay(waClient types.WAClient, hold time.Duration, logger *logrus.Logger) *signalTypingRelay {
+	return &signalTypingRelay{
+		waClient: waClient,
+		logger:   logger,
+		hold:     hold,
+		active:   make(map[string]*time.Timer),
+	}
+}
+
+// start shows the typing indicator in chatID until stop is called or hold passes without
+// another start. The indicator is optional in WAHA, so failures are only logged.
+func (r *signalTypingRelay) start(ctx context.Context, chatID, sessionName string) {
+	if err := r.waClient.StartTyping(ctx, chatID, sessionName); err != nil {
+		r.logger.WithContext(ctx).WithError(err).Debug("optional: startTyping failed")
+		return
+	}
+
+	key := sessionName + "|" + chatID
+	// The poll that delivered the typing event finishes before the timer fires
+	stopCtx := context.WithoutCancel(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if timer := r.active[key]; timer != nil {
+		timer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(r.hold, func() {
+		r.mu.Lock()
+		if r.active[key] != timer {
+			r.mu.Unlock()
+			return
+		}
+		delete(r.active, key)
+		r.mu.Unlock()
+		r.clear(stopCtx, chatID, sessionName)
+	})
+	r.active[key] = timer
+}
+
+// stop clears the typing indicator in chatID
+func (r *signalTypingRelay) stop(ctx context.Context, chatID, sessionName string) {
+	key := sessionName + "|" + chatID
+
+	r.mu.Lock()
+	timer := r.active[key]
+	delete(r.active, key)
+	r.mu.Unlock()
+
+	if timer != nil {
+		timer.Stop()
+	}
+	r.clear(ctx, chatID, sessionName)
+}
+
+func (r *signalTypingRelay) clear(ctx context.Context, chatID, sessionName string) {
+	if err := r.waClient.StopTyping(ctx, chatID, sessionName); err != nil {
+		r.logger.WithContext(ctx).WithError(err).Debug("optional: stopTyping failed")
+	}
+}
+
+// HandleSignalTyping relays a Signal typing event to the WhatsApp chat the next message would be
+// sent to: the linked WhatsApp group for a Signal group, otherwise the chat unquoted replies go
+// to. Events that cannot be placed are dropped; they are never worth failing a poll for.
+func (b *bridge) HandleSignalTyping(ctx context.Context, msg *signaltypes.SignalMessage, destination string) error {
+	if b.signalTyping == nil || msg == nil || msg.Typing == nil {
+		return nil
+	}
+
+	sessionName, err := b.channelManager.GetWhatsAppSession(destination)
+	if err != nil {
+		return fmt.Errorf("failed to determine WhatsApp session for Signal destination %s: %w", destination, err)
+	}
+
+	var chatID string
+	if msg.GroupID != "" {
+		groupMapping, err := b.db.GetGroupMapping(ctx, sessionName, msg.GroupID)
+		if err != nil {
+			return fmt.Errorf("failed to get group mapping: %w", err)
+		}
+		if groupMapping != nil {
+			chatID = groupMapping.WhatsAppGroupID
+		}
+	} else if msg.Sender == destination {
+		mapping, err := b.db.GetLatestMessageMappingBySession(ctx, sessionName)
+		if err != nil {
+			return fmt.Errorf("failed to get latest message mapping: %w", err)
+		}
+		if mapping != nil {
+			chatID = mapping.WhatsAppChatID
+		}
+	}
+	if chatID == "" {
+		return nil
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession: sessionName,
+		LogFieldChatID:  SanitizePhoneNumber(chatID),
+		"action":        msg.Typing.Action,
+	}).Debug("Relaying Signal typing indicator to WhatsApp")
+	metrics.IncrementCounter("signal_typing_relayed_total", map[string]string{
+		"session": sessionName,
+	}, "Signal typing indicators relayed to WhatsApp")
+
+	if msg.Typing.IsStarted() {
+		b.signalTyping.start(ctx, chatID, sessionName)
+	} else {
+		b.signalTyping.stop(ctx, chatID, sessionName)
+	}
+	return nil
+}
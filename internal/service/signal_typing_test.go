@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func typingEvent(sender, groupID, action string) *signaltypes.SignalMessage {
+	return &signaltypes.SignalMessage{
+		MessageID: "1700000000100",
+		Sender:    sender,
+		GroupID:   groupID,
+		Typing:    &signaltypes.TypingInfo{Action: action, GroupID: groupID},
+	}
+}
+
+func TestHandleSignalTyping(t *testing.T) {
+	t.Run("starts typing in the chat unquoted replies go to", func(t *testing.T) {
+		b, _, cleanup := setupTestBridge(t)
+		defer cleanup()
+		waClient := b.waClient.(*mockWhatsAppClient)
+		b.signalTyping = newSignalTypingRelay(waClient, time.Hour, logrus.New())
+
+		ctx := context.Background()
+		b.db.(*mockDatabaseService).On("GetLatestMessageMappingBySession", ctx, "default").
+			Return(&models.MessageMapping{WhatsAppChatID: "15550001111@c.us", SessionName: "default"}, nil).Once()
+		waClient.On("StartTyping", ctx, "15550001111@c.us", "default").Return(nil).Once()
+		waClient.On("StopTyping", mock.Anything, "15550001111@c.us", "default").Return(nil).Once()
+
+		require.NoError(t, b.HandleSignalTyping(ctx, typingEvent("+1234567890", "", signaltypes.TypingActionStarted), "+1234567890"))
+		waClient.AssertCalled(t, "StartTyping", ctx, "15550001111@c.us", "default")
+		waClient.AssertNotCalled(t, "StopTyping", mock.Anything, mock.Anything, mock.Anything)
+
+		b.db.(*mockDatabaseService).On("GetLatestMessageMappingBySession", ctx, "default").
+			Return(&models.MessageMapping{WhatsAppChatID: "15550001111@c.us", SessionName: "default"}, nil).Once()
+		require.NoError(t, b.HandleSignalTyping(ctx, typingEvent("+1234567890", "", signaltypes.TypingActionStopped), "+1234567890"))
+		waClient.AssertExpectations(t)
+		assert.Empty(t, b.signalTyping.active)
+	})
+
+	t.Run("starts typing in the linked WhatsApp group", func(t *testing.T) {
+		b, _, cleanup := setupTestBridge(t)
+		defer cleanup()
+		waClient := b.waClient.(*mockWhatsAppClient)
+		b.signalTyping = newSignalTypingRelay(waClient, time.Hour, logrus.New())
+
+		ctx := context.Background()
+		require.NoError(t, b.db.(*mockDatabaseService).SaveGroupMapping(ctx, &models.GroupMapping{
+			SessionName:     "default",
+			SignalGroupID:   testSignalGroupID,
+			WhatsAppGroupID: "120363028123456789@g.us",
+		}))
+		waClient.On("StartTyping", ctx, "120363028123456789@g.us", "default").Return(nil).Once()
+
+		require.NoError(t, b.HandleSignalTyping(ctx, typingEvent("+15550000001", testSignalGroupID, signaltypes.TypingActionStarted), "+1234567890"))
+		waClient.AssertExpectations(t)
+	})
+
+	t.Run("clears the indicator when Signal does not repeat it", func(t *testing.T) {
+		b, _, cleanup := setupTestBridge(t)
+		defer cleanup()
+		waClient := b.waClient.(*mockWhatsAppClient)
+		b.signalTyping = newSignalTypingRelay(waClient, 10*time.Millisecond, logrus.New())
+
+		ctx := context.Background()
+		b.db.(*mockDatabaseService).On("GetLatestMessageMappingBySession", ctx, "default").
+			Return(&models.MessageMapping{WhatsAppChatID: "15550001111@c.us", SessionName: "default"}, nil).Once()
+		stopped := make(chan struct{})
+		waClient.On("StartTyping", ctx, "15550001111@c.us", "default").Return(nil).Once()
+		waClient.On("StopTyping", mock.Anything, "15550001111@c.us", "default").Run(func(mock.Arguments) {
+			close(stopped)
+		}).Return(nil).Once()
+
+		require.NoError(t, b.HandleSignalTyping(ctx, typingEvent("+1234567890", "", signaltypes.TypingActionStarted), "+1234567890"))
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("typing indicator was not cleared")
+		}
+	})
+
+	t.Run("ignores typing from other numbers and when disabled", func(t *testing.T) {
+		b, _, cleanup := setupTestBridge(t)
+		defer cleanup()
+		waClient := b.waClient.(*mockWhatsAppClient)
+
+		ctx := context.Background()
+		require.NoError(t, b.HandleSignalTyping(ctx, typingEvent("+1234567890", "", signaltypes.TypingActionStarted), "+1234567890"))
+
+		b.signalTyping = newSignalTypingRelay(waClient, time.Hour, logrus.New())
+		require.NoError(t, b.HandleSignalTyping(ctx, typingEvent("+15550000001", "", signaltypes.TypingActionStarted), "+1234567890"))
+
+		waClient.AssertNotCalled(t, "StartTyping", mock.Anything, mock.Anything, mock.Anything)
+		b.db.(*mockDatabaseService).AssertNotCalled(t, "GetLatestMessageMappingBySession", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"whatsignal/internal/metrics"
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// isStaleWhatsAppMessage reports whether the message being relayed was sent longer ago than
+// server.maxRelayAgeMin, as happens when WAHA delivers a backlog after downtime. A message
+// without a timestamp is never stale.
+func (b *bridge) isStaleWhatsAppMessage(ctx context.Context) bool {
+	if b.maxRelayAge <= 0 {
+		return false
+	}
+	sentAt := whatsAppSentAtFromContext(ctx)
+	return !sentAt.IsZero() && b.clock.Now().Sub(sentAt) > b.maxRelayAge
+}
+
+// saveUnrelayedMapping stores the mapping for a WhatsApp message that is not relayed to Signal,
+// so replies and reactions to it still resolve. The Signal ID is msgID behind reason, which
+// marks why the message was held back.
+func (b *bridge) saveUnrelayedMapping(ctx context.Context, sessionName, chatID, msgID, reason string) error {
+	return b.db.SaveMessageMapping(ctx, &models.MessageMapping{
+		WhatsAppChatID:  chatID,
+		WhatsAppMsgID:   msgID,
+		SignalMsgID:     reason + ":" + msgID,
+		SignalTimestamp: b.clock.Now(),
+		ForwardedAt:     b.clock.Now(),
+		DeliveryStatus:  models.DeliveryStatusReceived,
+		SessionName:     sessionName,
+	})
+}
+
+// dropStaleWhatsAppMessage stores the mapping for a stale message instead of relaying it and
+// logs how many stale messages have been dropped so far
+func (b *bridge) dropStaleWhatsAppMessage(ctx context.Context, sessionName, chatID, msgID string) error {
+	if err := b.saveUnrelayedMapping(ctx, sessionName, chatID, msgID, "stale"); err != nil {
+		return fmt.Errorf("failed to save message mapping for stale message: %w", err)
+	}
+	dropped := b.staleDropped.Add(1)
+	metrics.IncrementCounter("stale_messages_dropped_total", map[string]string{
+		"session": sessionName,
+	}, "WhatsApp messages older than server.maxRelayAgeMin that were not relayed")
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession:   sessionName,
+		LogFieldMessageID: SanitizeWhatsAppMessageID(msgID),
+		"age":             b.clock.Now().Sub(whatsAppSentAtFromContext(ctx)).Round(time.Second).String(),
+		"dropped_total":   dropped,
+	}).Info("WhatsApp message is older than the maximum relay age, stored without relaying")
+	return nil
+}
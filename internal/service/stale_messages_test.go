@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	"whatsignal/internal/testutil"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWhatsAppMessage_MaxRelayAge(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxRelayAge time.Duration
+		sentAt      time.Time
+		relayed     bool
+	}{
+		{name: "old message is dropped", maxRelayAge: time.Hour, sentAt: time.Now().Add(-2 * time.Hour), relayed: false},
+		{name: "fresh message is relayed", maxRelayAge: time.Hour, sentAt: time.Now().Add(-time.Minute), relayed: true},
+		{name: "message without timestamp is relayed", maxRelayAge: time.Hour, relayed: true},
+		{name: "no limit relays old messages", sentAt: time.Now().Add(-48 * time.Hour), relayed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			bridge.maxRelayAge = tt.maxRelayAge
+			mockDB := bridge.db.(*mockDatabaseService)
+			mockDB.On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+			sigClient := bridge.sigClient.(*mockSignalClient)
+			sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-aged", Timestamp: time.Now().UnixMilli()}
+
+			ctx := WithWhatsAppSentAt(context.Background(), tt.sentAt)
+			err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123@c.us", "msg-aged", "sender123", "", "hi", "")
+			require.NoError(t, err)
+
+			if tt.relayed {
+				assert.Equal(t, "sender123: hi", sigClient.lastMessage)
+				assert.Zero(t, bridge.staleDropped.Load())
+				return
+			}
+			assert.Empty(t, sigClient.lastMessage, "the message is not relayed")
+			assert.Equal(t, int64(1), bridge.staleDropped.Load())
+			mockDB.AssertCalled(t, "SaveMessageMapping", mock.Anything, mock.MatchedBy(func(m *models.MessageMapping) bool {
+				return m.WhatsAppMsgID == "msg-aged" && m.SignalMsgID == "stale:msg-aged" && m.SessionName == "default"
+			}))
+		})
+	}
+}
+
+func TestIsStaleWhatsAppMessage_FollowsClock(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+	clock := testutil.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	bridge.clock = clock
+	bridge.maxRelayAge = time.Hour
+
+	ctx := WithWhatsAppSentAt(context.Background(), clock.Now().Add(-30*time.Minute))
+	assert.False(t, bridge.isStaleWhatsAppMessage(ctx))
+
+	clock.Advance(31 * time.Minute)
+	assert.True(t, bridge.isStaleWhatsAppMessage(ctx), "the message is older than the maximum relay age by now")
+}
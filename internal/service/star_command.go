@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Signal commands that star or unstar the quoted WhatsApp message
+const (
+	chatCommandStar   = "/star"
+	chatCommandUnstar = "/unstar"
+)
+
+// parseStarCommand reports whether a Signal message is a /star or /unstar command and, if so,
+// whether it stars the message
+func parseStarCommand(msg *signaltypes.SignalMessage) (star bool, ok bool) {
+	if len(msg.Attachments) > 0 {
+		return false, false
+	}
+	switch strings.ToLower(strings.TrimSpace(msg.Message)) {
+	case chatCommandStar:
+		return true, true
+	case chatCommandUnstar:
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// handleStarCommand stars or unstars the WhatsApp message quoted by a /star or /unstar command
+// and confirms the result on Signal. Only the channel's own Signal number may star messages. It
+// reports whether msg was a star command, in which case it must not be relayed.
+func (b *bridge) handleStarCommand(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string) bool {
+	star, ok := parseStarCommand(msg)
+	if !ok {
+		return false
+	}
+
+	command := chatCommandUnstar
+	if star {
+		command = chatCommandStar
+	}
+
+	notice := b.starQuotedMessage(ctx, msg, sessionName, star, command)
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, notice); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to confirm star command on Signal")
+	}
+	return true
+}
+
+// starQuotedMessage applies a star command to the quoted message and returns the notice to
+// send back to Signal
+func (b *bridge) starQuotedMessage(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string, star bool, command string) string {
+	if msg.QuotedMessage == nil {
+		return "Quote the message you want to " + strings.TrimPrefix(command, "/") + " and send " + command + " again."
+	}
+
+	mapping, err := b.db.GetMessageMapping(ctx, msg.QuotedMessage.ID)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithField(LogFieldSession, sessionName).Warn("Failed to look up quoted message for star command")
+		return "Could not look up the quoted message."
+	}
+	if mapping == nil || mapping.WhatsAppMsgID == "" || mapping.SessionName != sessionName {
+		return "The quoted message is not a WhatsApp message this channel knows about."
+	}
+
+	if err := b.waClient.StarMessageWithSession(ctx, mapping.WhatsAppChatID, mapping.WhatsAppMsgID, star, sessionName); err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+			LogFieldSession:   sessionName,
+			LogFieldMessageID: SanitizeWhatsAppMessageID(mapping.WhatsAppMsgID),
+		}).Warn("Failed to star WhatsApp message")
+		return "Could not update the star on WhatsApp: " + err.Error()
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession:   sessionName,
+		LogFieldChatID:    SanitizePhoneNumber(mapping.WhatsAppChatID),
+		LogFieldMessageID: SanitizeWhatsAppMessageID(mapping.WhatsAppMsgID),
+		"star":            star,
+	}).Info("Updated WhatsApp message star from Signal command")
+
+	chatName := b.chatDisplayName(ctx, sessionName, mapping.WhatsAppChatID)
+	if star {
+		return "Starred the message in " + chatName + "."
+	}
+	return "Unstarred the message in " + chatName + "."
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStarCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      signaltypes.SignalMessage
+		wantStar bool
+		wantOK   bool
+	}{
+		{"star", signaltypes.SignalMessage{Message: "/star"}, true, true},
+		{"unstar", signaltypes.SignalMessage{Message: " /UNSTAR\n"}, false, true},
+		{"regular message", signaltypes.SignalMessage{Message: "a star is born"}, false, false},
+		{"command inside text", signaltypes.SignalMessage{Message: "/star this"}, false, false},
+		{"with attachment", signaltypes.SignalMessage{Message: "/star", Attachments: []string{"a.jpg"}}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			star, ok := parseStarCommand(&tt.msg)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantStar, star)
+		})
+	}
+}
+
+// setupStarTestBridge returns a bridge that knows the Signal message sig_quoted as WhatsApp
+// message wa_quoted and fails the test if anything is relayed to WhatsApp
+func setupStarTestBridge(t *testing.T) (*bridge, func()) {
+	bridge, _, cleanup := setupTestBridge(t)
+
+	bridge.db.(*mockDatabaseService).On("GetMessageMapping", mock.Anything, "sig_quoted").Return(&models.MessageMapping{
+		WhatsAppChatID: "111@c.us",
+		WhatsAppMsgID:  "wa_quoted",
+		SignalMsgID:    "sig_quoted",
+		SessionName:    "default",
+	}, nil)
+	bridge.sigClient.(*mockSignalClient).sendMessageResponse = &signaltypes.SendMessageResponse{
+		MessageID: "sig_sent",
+		Timestamp: time.Now().UnixMilli(),
+	}
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		t.Errorf("star command relayed to WhatsApp: %q", text)
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+
+	return bridge, cleanup
+}
+
+func starCommand(text string) *signaltypes.SignalMessage {
+	return &signaltypes.SignalMessage{
+		MessageID: "cmd1",
+		Sender:    "+1234567890",
+		Message:   text,
+		QuotedMessage: &struct {
+			ID        string `json:"id"`
+			Author    string `json:"author"`
+			Text      string `json:"text"`
+			Timestamp int64  `json:"timestamp"`
+		}{ID: "sig_quoted"},
+	}
+}
+
+func TestStarCommandStarsQuotedMessage(t *testing.T) {
+	tests := []struct {
+		command string
+		star    bool
+		confirm string
+	}{
+		{"/star", true, "Starred the message"},
+		{"/unstar", false, "Unstarred the message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			bridge, cleanup := setupStarTestBridge(t)
+			defer cleanup()
+
+			waClient := bridge.waClient.(*mockWhatsAppClient)
+			waClient.On("StarMessageWithSession", mock.Anything, "111@c.us", "wa_quoted", tt.star, "default").Return(nil).Once()
+
+			require.NoError(t, bridge.HandleSignalMessage(context.Background(), starCommand(tt.command)))
+
+			waClient.AssertExpectations(t)
+			assert.Contains(t, bridge.sigClient.(*mockSignalClient).lastMessage, tt.confirm)
+		})
+	}
+}
+
+func TestStarCommandReportsFailures(t *testing.T) {
+	t.Run("no quote", func(t *testing.T) {
+		bridge, cleanup := setupStarTestBridge(t)
+		defer cleanup()
+
+		msg := starCommand("/star")
+		msg.QuotedMessage = nil
+		require.NoError(t, bridge.HandleSignalMessage(context.Background(), msg))
+
+		assert.Contains(t, bridge.sigClient.(*mockSignalClient).lastMessage, "Quote the message you want to star")
+		bridge.waClient.(*mockWhatsAppClient).AssertNotCalled(t, "StarMessageWithSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown quote", func(t *testing.T) {
+		bridge, cleanup := setupStarTestBridge(t)
+		defer cleanup()
+
+		bridge.db.(*mockDatabaseService).On("GetMessageMapping", mock.Anything, "sig_unknown").Return(nil, nil)
+		msg := starCommand("/star")
+		msg.QuotedMessage.ID = "sig_unknown"
+		require.NoError(t, bridge.HandleSignalMessage(context.Background(), msg))
+
+		assert.Contains(t, bridge.sigClient.(*mockSignalClient).lastMessage, "not a WhatsApp message")
+	})
+
+	t.Run("WAHA error", func(t *testing.T) {
+		bridge, cleanup := setupStarTestBridge(t)
+		defer cleanup()
+
+		bridge.waClient.(*mockWhatsAppClient).On("StarMessageWithSession", mock.Anything, "111@c.us", "wa_quoted", true, "default").Return(errors.New("request failed with status 404"))
+		require.NoError(t, bridge.HandleSignalMessage(context.Background(), starCommand("/star")))
+
+		assert.Contains(t, bridge.sigClient.(*mockSignalClient).lastMessage, "Could not update the star on WhatsApp")
+	})
+}
+
+func TestStarCommandIgnoresOtherSenders(t *testing.T) {
+	bridge, cleanup := setupStarTestBridge(t)
+	defer cleanup()
+
+	msg := starCommand("/star")
+	msg.Sender = "+15550000001"
+	require.NoError(t, bridge.HandleSignalMessage(context.Background(), msg))
+
+	bridge.waClient.(*mockWhatsAppClient).AssertNotCalled(t, "StarMessageWithSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, bridge.sigClient.(*mockSignalClient).lastMessage)
+}
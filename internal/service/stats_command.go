@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+)
+
+// chatCommandStats replies with message mapping statistics for the channel
+const chatCommandStats = "/stats"
+
+// statsStatusOrder lists delivery statuses in the order they are reported
+var statsStatusOrder = []models.DeliveryStatus{
+	models.DeliveryStatusPending,
+	models.DeliveryStatusReceived,
+	models.DeliveryStatusSent,
+	models.DeliveryStatusDelivered,
+	models.DeliveryStatusRead,
+	models.DeliveryStatusFailed,
+}
+
+// isStatsCommand reports whether a Signal message is a /stats command
+func isStatsCommand(msg *signaltypes.SignalMessage) bool {
+	return len(msg.Attachments) == 0 && strings.EqualFold(strings.TrimSpace(msg.Message), chatCommandStats)
+}
+
+// handleStatsCommand replies to a /stats command with the session's mapping statistics. Only
+// the channel's own Signal number may query them; the command is never relayed either way.
+// It reports whether msg was a stats command.
+func (b *bridge) handleStatsCommand(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string) bool {
+	if !isStatsCommand(msg) {
+		return false
+	}
+
+	reply := "Could not load message statistics."
+	stats, err := b.db.GetMappingStats(ctx, sessionName)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithField(LogFieldSession, sessionName).Warn("Failed to load mapping statistics")
+	} else {
+		reply = formatMappingStats(sessionName, stats)
+	}
+
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, reply); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to send stats reply")
+	}
+	return true
+}
+
+// formatMappingStats renders mapping statistics as a Signal reply
+func formatMappingStats(sessionName string, stats models.MappingStats) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Stats for %s\n", sessionName)
+	fmt.Fprintf(&sb, "Total mappings: %d\n", stats.Total)
+	fmt.Fprintf(&sb, "Last 24h: %d\n", stats.Last24h)
+	fmt.Fprintf(&sb, "Media: %d", stats.Media)
+
+	var statuses []string
+	seen := make(map[models.DeliveryStatus]bool, len(statsStatusOrder))
+	for _, status := range statsStatusOrder {
+		seen[status] = true
+		if count := stats.ByStatus[status]; count > 0 {
+			statuses = append(statuses, fmt.Sprintf("%s %d", status, count))
+		}
+	}
+	var other []string
+	for status, count := range stats.ByStatus {
+		if !seen[status] && count > 0 {
+			other = append(other, fmt.Sprintf("%s %d", status, count))
+		}
+	}
+	sort.Strings(other)
+	statuses = append(statuses, other...)
+
+	if len(statuses) > 0 {
+		fmt.Fprintf(&sb, "\nBy status: %s", strings.Join(statuses, ", "))
+	}
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsStatsCommand(t *testing.T) {
+	assert.True(t, isStatsCommand(&signaltypes.SignalMessage{Message: " /STATS\n"}))
+	assert.False(t, isStatsCommand(&signaltypes.SignalMessage{Message: "/stats please"}))
+	assert.False(t, isStatsCommand(&signaltypes.SignalMessage{Message: "/stats", Attachments: []string{"a.jpg"}}))
+}
+
+func TestFormatMappingStats(t *testing.T) {
+	reply := formatMappingStats("default", models.MappingStats{
+		Total:   12,
+		Last24h: 3,
+		Media:   2,
+		ByStatus: map[models.DeliveryStatus]int{
+			models.DeliveryStatusRead:    5,
+			models.DeliveryStatusSent:    6,
+			models.DeliveryStatus("odd"): 1,
+		},
+	})
+
+	assert.Equal(t, "Stats for default\nTotal mappings: 12\nLast 24h: 3\nMedia: 2\nBy status: sent 6, read 5, odd 1", reply)
+}
+
+func TestStatsCommandRepliesWithoutRelaying(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	mockDB := bridge.db.(*mockDatabaseService)
+	mockDB.On("GetMappingStats", mock.Anything, "default").Return(models.MappingStats{
+		Total:    4,
+		Last24h:  1,
+		Media:    1,
+		ByStatus: map[models.DeliveryStatus]int{models.DeliveryStatusDelivered: 4},
+	}, nil)
+
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig_sent", Timestamp: time.Now().UnixMilli()}
+
+	whatsAppSends := 0
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		whatsAppSends++
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+
+	err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/stats"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, whatsAppSends)
+	assert.Contains(t, sigClient.lastMessage, "Total mappings: 4")
+	assert.Contains(t, sigClient.lastMessage, "By status: delivered 4")
+	mockDB.AssertExpectations(t)
+}
+
+func TestStatsCommandIgnoresOtherSenders(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	whatsAppSends := 0
+	bridge.waClient.(*mockWhatsAppClient).sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		whatsAppSends++
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+
+	err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1999999999", Message: "/stats"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, whatsAppSends, "the command is not relayed")
+	assert.Empty(t, sigClient.lastMessage, "no stats are sent to an unauthorized number")
+	bridge.db.(*mockDatabaseService).AssertNotCalled(t, "GetMappingStats", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"whatsignal/internal/constants"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// typingSimulator shows the WhatsApp typing indicator before a relayed text is sent, for a time
+// proportional to its length, so bridged replies do not arrive instantly
+type typingSimulator struct {
+	waClient types.WAClient
+	logger   *logrus.Logger
+	perChar  time.Duration
+	maxDelay time.Duration
+	testMode bool // WHATSIGNAL_TEST_MODE skips the delay but still toggles the indicator
+}
+
+func newTypingSimulator(waClient types.WAClient, maxSec int, logger *logrus.Logger) *typingSimulator {
+	if maxSec <= 0 {
+		maxSec = constants.MaxTypingDurationSec
+	}
+	return &typingSimulator{
+		waClient: waClient,
+		logger:   logger,
+		perChar:  time.Duration(constants.TypingDurationPerCharMs) * time.Millisecond,
+		maxDelay: time.Duration(maxSec) * time.Second,
+		testMode: os.Getenv("WHATSIGNAL_TEST_MODE") == "true",
+	}
+}
+
+// delay returns how long typing text is simulated for
+func (t *typingSimulator) delay(text string) time.Duration {
+	if t.testMode {
+		return 0
+	}
+	return min(time.Duration(utf8.RuneCountInString(text))*t.perChar, t.maxDelay)
+}
+
+// start shows the typing indicator in chatID and waits for the simulated typing time, or until
+// ctx is done. The returned func clears the indicator and must be called once the text is sent.
+// The indicator is optional in WAHA, so failures are only logged.
+func (t *typingSimulator) start(ctx context.Context, chatID, sessionName, text string) func() {
+	if err := t.waClient.StartTyping(ctx, chatID, sessionName); err != nil {
+		t.logger.WithContext(ctx).WithError(err).Debug("optional: startTyping failed")
+		return func() {}
+	}
+
+	if delay := t.delay(text); delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	return func() {
+		if err := t.waClient.StopTyping(ctx, chatID, sessionName); err != nil {
+			t.logger.WithContext(ctx).WithError(err).Debug("optional: stopTyping failed")
+		}
+	}
+}
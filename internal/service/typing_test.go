@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendMessageToWhatsApp_SimulatesTyping(t *testing.T) {
+	t.Setenv("WHATSIGNAL_TEST_MODE", "true")
+
+	tests := []struct {
+		name           string
+		simulateTyping bool
+		attachments    []string
+		expectedCalls  []string
+	}{
+		{
+			name:           "enabled",
+			simulateTyping: true,
+			expectedCalls:  []string{"StartTyping", "SendText", "StopTyping"},
+		},
+		{
+			name:          "disabled",
+			expectedCalls: []string{"SendText"},
+		},
+		{
+			name:           "enabled for media",
+			simulateTyping: true,
+			attachments:    []string{"/tmp/photo.jpg"},
+			expectedCalls:  []string{"SendImage"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			var calls []string
+			waClient := &mockWhatsAppClient{
+				sendTextFunc: func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+					calls = append(calls, "SendText")
+					return &types.SendMessageResponse{MessageID: "wa-typed", Status: "sent"}, nil
+				},
+			}
+			waClient.On("StartTyping", mock.Anything, "123456789@c.us", "default").Run(func(mock.Arguments) {
+				calls = append(calls, "StartTyping")
+			}).Return(nil).Maybe()
+			waClient.On("StopTyping", mock.Anything, "123456789@c.us", "default").Run(func(mock.Arguments) {
+				calls = append(calls, "StopTyping")
+			}).Return(nil).Maybe()
+			waClient.On("SendImageWithSession", mock.Anything, "123456789@c.us", "/tmp/photo.jpg", "hello there", "", "default").Run(func(mock.Arguments) {
+				calls = append(calls, "SendImage")
+			}).Return(&types.SendMessageResponse{MessageID: "wa-image", Status: "sent"}, nil).Maybe()
+			bridge.waClient = waClient
+			if tt.simulateTyping {
+				bridge.typing = newTypingSimulator(waClient, 0, bridge.logger)
+			}
+
+			_, err := bridge.sendMessageToWhatsApp(context.Background(), "123456789@c.us", "hello there", nil, tt.attachments, "", "default")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func TestTypingSimulator_Delay(t *testing.T) {
+	t.Setenv("WHATSIGNAL_TEST_MODE", "")
+
+	typing := newTypingSimulator(&mockWhatsAppClient{}, 0, logrus.New())
+	assert.Equal(t, 250*time.Millisecond, typing.delay("hello"))
+	assert.Equal(t, 250*time.Millisecond, typing.delay("héllo"))
+	assert.Equal(t, 3*time.Second, typing.delay(strings.Repeat("a", 1000)))
+
+	capped := newTypingSimulator(&mockWhatsAppClient{}, 10, logrus.New())
+	assert.Equal(t, 10*time.Second, capped.delay(strings.Repeat("a", 1000)))
+
+	t.Setenv("WHATSIGNAL_TEST_MODE", "true")
+	assert.Equal(t, time.Duration(0), newTypingSimulator(&mockWhatsAppClient{}, 0, logrus.New()).delay("hello"))
+}
+
+func TestTypingSimulator_WaitsBeforeSending(t *testing.T) {
+	t.Setenv("WHATSIGNAL_TEST_MODE", "")
+
+	waClient := &mockWhatsAppClient{}
+	waClient.On("StartTyping", mock.Anything, "123456789@c.us", "default").Return(nil).Once()
+	waClient.On("StopTyping", mock.Anything, "123456789@c.us", "default").Return(nil).Once()
+
+	typing := newTypingSimulator(waClient, 0, logrus.New())
+	typing.perChar = 10 * time.Millisecond
+
+	started := time.Now()
+	stop := typing.start(context.Background(), "123456789@c.us", "default", "hello")
+	assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+	stop()
+
+	waClient.AssertExpectations(t)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"whatsignal/pkg/signal"
+)
+
+// whatsAppExpiryContextKey carries the disappearing-messages timer of an incoming WhatsApp message
+const whatsAppExpiryContextKey ContextKey = "whatsapp_expiry"
+
+// WithWhatsAppExpiry returns a context marking the WhatsApp message being handled as a
+// disappearing message that expires after expiry
+func WithWhatsAppExpiry(ctx context.Context, expiry time.Duration) context.Context {
+	if expiry <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, whatsAppExpiryContextKey, expiry)
+}
+
+// whatsAppExpiryFromContext returns the timer set by WithWhatsAppExpiry, or 0 for a permanent message
+func whatsAppExpiryFromContext(ctx context.Context) time.Duration {
+	expiry, _ := ctx.Value(whatsAppExpiryContextKey).(time.Duration)
+	return expiry
+}
+
+// disappearingLabel returns the note appended to a relayed disappearing message. Whole days
+// from two days up are shown in days, shorter timers in hours rounded up.
+func disappearingLabel(expiry time.Duration) string {
+	const day = 24 * time.Hour
+	if expiry >= 2*day && expiry%day == 0 {
+		return fmt.Sprintf("⏳ disappears in %dd", expiry/day)
+	}
+	hours := (expiry + time.Hour - 1) / time.Hour
+	return fmt.Sprintf("⏳ disappears in %dh", hours)
+}
+
+// signalTimers tracks the disappearing-messages timer WhatSignal set on each Signal
+// conversation. Signal timers cover a whole conversation, and one conversation carries every
+// chat of a channel, so the timer is switched per relayed message and back off afterwards.
+type signalTimers struct {
+	mu      sync.Mutex
+	current map[string]time.Duration
+}
+
+func newSignalTimers() *signalTimers {
+	return &signalTimers{current: make(map[string]time.Duration)}
+}
+
+// apply sets the timer of the conversation with destination to expiry, unless it already is.
+// It locks the timers until the returned unlock is called, so the caller can send the message
+// before another relay changes the timer. unlock must be called even when apply fails.
+func (t *signalTimers) apply(ctx context.Context, client signal.Client, destination string, expiry time.Duration) (func(), error) {
+	t.mu.Lock()
+	if t.current[destination] == expiry {
+		return t.mu.Unlock, nil
+	}
+	if err := client.SetExpiration(ctx, destination, expiry); err != nil {
+		return t.mu.Unlock, fmt.Errorf("failed to set Signal expiration timer: %w", err)
+	}
+	t.current[destination] = expiry
+	return t.mu.Unlock, nil
+}
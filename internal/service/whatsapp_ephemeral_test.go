@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisappearingLabel(t *testing.T) {
+	assert.Equal(t, "⏳ disappears in 24h", disappearingLabel(24*time.Hour))
+	assert.Equal(t, "⏳ disappears in 7d", disappearingLabel(7*24*time.Hour))
+	assert.Equal(t, "⏳ disappears in 90d", disappearingLabel(90*24*time.Hour))
+	assert.Equal(t, "⏳ disappears in 2h", disappearingLabel(90*time.Minute))
+}
+
+func TestHandleWhatsAppMessage_DisappearingLabel(t *testing.T) {
+	tests := []struct {
+		name            string
+		mode            string
+		expiry          time.Duration
+		expectedMessage string
+	}{
+		{
+			name:            "label mode with 24 hour timer",
+			mode:            models.DisappearingLabel,
+			expiry:          24 * time.Hour,
+			expectedMessage: "sender123: see you\n⏳ disappears in 24h",
+		},
+		{
+			name:            "label mode with 7 day timer",
+			mode:            models.DisappearingLabel,
+			expiry:          7 * 24 * time.Hour,
+			expectedMessage: "sender123: see you\n⏳ disappears in 7d",
+		},
+		{
+			name:            "label mode with permanent message",
+			mode:            models.DisappearingLabel,
+			expectedMessage: "sender123: see you",
+		},
+		{
+			name:            "disabled",
+			expiry:          24 * time.Hour,
+			expectedMessage: "sender123: see you",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			bridge.disappearing = tt.mode
+			sigClient := bridge.sigClient.(*mockSignalClient)
+			sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-ephemeral", Timestamp: time.Now().UnixMilli()}
+
+			ctx := WithWhatsAppExpiry(context.Background(), tt.expiry)
+			err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123@c.us", "msg-ephemeral", "sender123", "", "see you", "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMessage, sigClient.lastMessage)
+			sigClient.AssertNotCalled(t, "SetExpiration", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandleWhatsAppMessage_DisappearingTimer(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	bridge.disappearing = models.DisappearingTimer
+	bridge.signalTimers = newSignalTimers()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-timer", Timestamp: time.Now().UnixMilli()}
+	sigClient.On("SetExpiration", mock.Anything, "+1234567890", 24*time.Hour).Return(nil).Once()
+	sigClient.On("SetExpiration", mock.Anything, "+1234567890", time.Duration(0)).Return(nil).Once()
+
+	ephemeral := WithWhatsAppExpiry(context.Background(), 24*time.Hour)
+	// Two disappearing messages set the timer once, the permanent one turns it off again
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ephemeral, "default", "chat123@c.us", "msg-timer-1", "sender123", "", "one", ""))
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ephemeral, "default", "chat123@c.us", "msg-timer-2", "sender123", "", "two", ""))
+	assert.Equal(t, "sender123: two", sigClient.lastMessage)
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(context.Background(), "default", "chat123@c.us", "msg-timer-3", "sender123", "", "three", ""))
+
+	sigClient.AssertExpectations(t)
+}
+
+func TestHandleWhatsAppMessage_DisappearingTimerFailureLabels(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	bridge.disappearing = models.DisappearingTimer
+	bridge.signalTimers = newSignalTimers()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-timer", Timestamp: time.Now().UnixMilli()}
+	sigClient.On("SetExpiration", mock.Anything, "+1234567890", 24*time.Hour).Return(errors.New("unsupported")).Once()
+
+	ctx := WithWhatsAppExpiry(context.Background(), 24*time.Hour)
+	require.NoError(t, bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123@c.us", "msg-timer-fail", "sender123", "", "one", ""))
+	assert.Equal(t, "sender123: one\n⏳ disappears in 24h", sigClient.lastMessage)
+}
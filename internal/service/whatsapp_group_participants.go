@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HandleWhatsAppGroupParticipants relays members joining or leaving a WhatsApp group to Signal
+// as "Alice was added to Family" or "Bob left Family", for channels with
+// relayGroupParticipants set. Members are named through the contact service and the group by
+// its cached subject.
+func (b *bridge) HandleWhatsAppGroupParticipants(ctx context.Context, sessionName string, change models.WhatsAppGroupParticipants) error {
+	if !b.channelManager.RelaysGroupParticipants(sessionName) || len(change.Participants) == 0 {
+		b.logger.WithContext(ctx).WithField(LogFieldSession, sessionName).Debug("Group participant changes are not relayed for this channel")
+		return nil
+	}
+
+	groupName := change.GroupID
+	if b.groupService != nil {
+		groupName = b.groupService.GetGroupName(ctx, change.GroupID, sessionName)
+	}
+
+	names := make([]string, 0, len(change.Participants))
+	for _, participant := range change.Participants {
+		names = append(names, b.contactDisplayName(ctx, participant, ""))
+	}
+
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, formatGroupParticipants(change.Action, names, groupName)); err != nil {
+		return fmt.Errorf("failed to send group participant change to Signal: %w", err)
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession: sessionName,
+		LogFieldChatID:  SanitizePhoneNumber(change.GroupID),
+		"action":        change.Action,
+		"participants":  len(change.Participants),
+	}).Debug("Relayed WhatsApp group participant change to Signal")
+
+	return nil
+}
+
+// formatGroupParticipants renders a participant change notice, listing names as "Alice",
+// "Alice and Bob" or "Alice, Bob and Carol"
+func formatGroupParticipants(action string, names []string, groupName string) string {
+	list := names[len(names)-1]
+	if len(names) > 1 {
+		list = strings.Join(names[:len(names)-1], ", ") + " and " + list
+	}
+
+	if action == models.GroupParticipantsLeave {
+		return fmt.Sprintf("%s left %s", list, groupName)
+	}
+	verb := "was"
+	if len(names) > 1 {
+		verb = "were"
+	}
+	return fmt.Sprintf("%s %s added to %s", list, verb, groupName)
+}
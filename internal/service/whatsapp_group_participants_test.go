@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupGroupParticipantsBridge returns a bridge whose channel relays group participant changes
+// when relay is set, with "Family" as the subject of family@g.us
+func setupGroupParticipantsBridge(t *testing.T, relay bool) (*bridge, *mockSignalClient, func()) {
+	bridge, _, cleanup := setupTestBridge(t)
+
+	channelManager, err := NewChannelManager([]models.Channel{
+		{WhatsAppSessionName: "default", SignalDestinationPhoneNumber: "+1234567890", RelayGroupParticipants: relay},
+	})
+	require.NoError(t, err)
+	bridge.channelManager = channelManager
+
+	groups := &mockGroupService{}
+	groups.On("GetGroupName", mock.Anything, "family@g.us", "default").Return("Family").Maybe()
+	bridge.groupService = groups
+
+	contacts := &mockContactService{}
+	contacts.On("GetContactDisplayName", mock.Anything, "15550001111").Return("Alice").Maybe()
+	contacts.On("GetContactDisplayName", mock.Anything, "15550002222").Return("Bob").Maybe()
+	contacts.On("GetContactDisplayName", mock.Anything, "15550003333").Return("15550003333").Maybe()
+	bridge.contactService = contacts
+
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-notice", Timestamp: time.Now().UnixMilli()}
+	return bridge, sigClient, cleanup
+}
+
+func TestHandleWhatsAppGroupParticipants(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   models.WhatsAppGroupParticipants
+		expected string
+	}{
+		{
+			name:     "member added",
+			change:   models.WhatsAppGroupParticipants{GroupID: "family@g.us", Action: models.GroupParticipantsJoin, Participants: []string{"15550001111@c.us"}},
+			expected: "Alice was added to Family",
+		},
+		{
+			name:     "several members added",
+			change:   models.WhatsAppGroupParticipants{GroupID: "family@g.us", Action: models.GroupParticipantsJoin, Participants: []string{"15550001111@c.us", "15550002222@c.us", "15550003333@c.us"}},
+			expected: "Alice, Bob and 15550003333 were added to Family",
+		},
+		{
+			name:     "member left",
+			change:   models.WhatsAppGroupParticipants{GroupID: "family@g.us", Action: models.GroupParticipantsLeave, Participants: []string{"15550002222@c.us"}},
+			expected: "Bob left Family",
+		},
+		{
+			name:     "members left",
+			change:   models.WhatsAppGroupParticipants{GroupID: "family@g.us", Action: models.GroupParticipantsLeave, Participants: []string{"15550001111@c.us", "15550002222@c.us"}},
+			expected: "Alice and Bob left Family",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, sigClient, cleanup := setupGroupParticipantsBridge(t, true)
+			defer cleanup()
+
+			err := bridge.HandleWhatsAppGroupParticipants(context.Background(), "default", tt.change)
+			require.NoError(t, err)
+			assert.Equal(t, "+1234567890", sigClient.lastRecipient)
+			assert.Equal(t, tt.expected, sigClient.lastMessage)
+		})
+	}
+}
+
+func TestHandleWhatsAppGroupParticipants_NotRelayedUnlessEnabled(t *testing.T) {
+	bridge, sigClient, cleanup := setupGroupParticipantsBridge(t, false)
+	defer cleanup()
+
+	err := bridge.HandleWhatsAppGroupParticipants(context.Background(), "default", models.WhatsAppGroupParticipants{
+		GroupID: "family@g.us", Action: models.GroupParticipantsJoin, Participants: []string{"15550001111@c.us"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, sigClient.lastMessage)
+}
+
+func TestHandleWhatsAppGroupParticipants_SendFailure(t *testing.T) {
+	bridge, sigClient, cleanup := setupGroupParticipantsBridge(t, true)
+	defer cleanup()
+	sigClient.sendMessageErr = errors.New("signal-cli down")
+
+	err := bridge.HandleWhatsAppGroupParticipants(context.Background(), "default", models.WhatsAppGroupParticipants{
+		GroupID: "family@g.us", Action: models.GroupParticipantsLeave, Participants: []string{"15550002222@c.us"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "signal-cli down")
+}
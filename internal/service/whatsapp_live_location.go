@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// liveLocationShare holds one location being shared live in a WhatsApp chat
+type liveLocationShare struct {
+	sessionName string
+	latest      models.WhatsAppLiveLocation
+	updatedAt   time.Time
+	lastSent    time.Time
+	// pending is set while latest has not been sent yet
+	pending bool
+	ctx     context.Context
+	// flushTimer is set while a pending update waits for the interval to pass; idleTimer ends
+	// the share when no update arrives for the idle time
+	flushTimer *time.Timer
+	idleTimer  *time.Timer
+}
+
+// liveLocationRelay relays WhatsApp live locations as at most one Signal message per share and
+// interval. The first update of a share is sent at once; later ones within the interval are
+// coalesced so only the latest coordinates are sent when it passes. A share without updates for
+// the idle time has ended: its pending update is sent followed by a note that it ended. At most
+// maxShares shares are followed; the least recently updated one is dropped to make room.
+type liveLocationRelay struct {
+	mu        sync.Mutex
+	interval  time.Duration
+	idle      time.Duration
+	maxShares int
+	shares    map[string]*liveLocationShare
+	send      func(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation, ended bool) error
+	logger    *logrus.Logger
+	clock     Clock
+}
+
+func newLiveLocationRelay(interval, idle time.Duration, maxShares int, send func(context.Context, string, models.WhatsAppLiveLocation, bool) error, logger *logrus.Logger, clock Clock) *liveLocationRelay {
+	return &liveLocationRelay{
+		interval:  interval,
+		idle:      idle,
+		maxShares: maxShares,
+		shares:    make(map[string]*liveLocationShare),
+		send:      send,
+		logger:    logger,
+		clock:     clock,
+	}
+}
+
+// update records a live location update and sends it now or once the share's interval passes
+func (r *liveLocationRelay) update(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation) {
+	key := sessionName + "|" + loc.ID
+	now := r.clock.Now()
+
+	r.mu.Lock()
+	share := r.shares[key]
+	if share == nil {
+		r.evictLocked()
+		share = &liveLocationShare{sessionName: sessionName}
+		r.shares[key] = share
+	}
+	share.latest = loc
+	share.updatedAt = now
+	// The webhook request finishes before the timers fire
+	share.ctx = context.WithoutCancel(ctx)
+
+	if share.idleTimer != nil {
+		share.idleTimer.Stop()
+	}
+	share.idleTimer = time.AfterFunc(r.idle, func() { r.end(key, share) })
+
+	sendNow := false
+	switch {
+	case share.flushTimer != nil:
+		share.pending = true
+	case share.lastSent.IsZero() || now.Sub(share.lastSent) >= r.interval:
+		share.lastSent = now
+		share.pending = false
+		sendNow = true
+	default:
+		share.pending = true
+		share.flushTimer = time.AfterFunc(share.lastSent.Add(r.interval).Sub(now), func() { r.flush(key, share) })
+	}
+	sendCtx := share.ctx
+	r.mu.Unlock()
+
+	if sendNow {
+		r.deliver(sendCtx, sessionName, loc, false)
+	}
+}
+
+// evictLocked makes room for one more share by dropping the least recently updated ones
+func (r *liveLocationRelay) evictLocked() {
+	for r.maxShares > 0 && len(r.shares) >= r.maxShares {
+		var oldestKey string
+		var oldest *liveLocationShare
+		for k, share := range r.shares {
+			if oldest == nil || share.updatedAt.Before(oldest.updatedAt) {
+				oldestKey, oldest = k, share
+			}
+		}
+		delete(r.shares, oldestKey)
+		stopLiveLocationTimers(oldest)
+	}
+}
+
+// flush sends the latest update of a share whose interval has passed
+func (r *liveLocationRelay) flush(key string, share *liveLocationShare) {
+	r.mu.Lock()
+	if r.shares[key] != share || share.flushTimer == nil {
+		// Ended or evicted already
+		r.mu.Unlock()
+		return
+	}
+	share.flushTimer = nil
+	if !share.pending {
+		r.mu.Unlock()
+		return
+	}
+	share.pending = false
+	share.lastSent = r.clock.Now()
+	ctx, loc := share.ctx, share.latest
+	r.mu.Unlock()
+
+	r.deliver(ctx, share.sessionName, loc, false)
+}
+
+// end sends a share's pending update and a note that it ended, once no update arrived for the
+// idle time
+func (r *liveLocationRelay) end(key string, share *liveLocationShare) {
+	r.mu.Lock()
+	if r.shares[key] != share || r.clock.Now().Sub(share.updatedAt) < r.idle {
+		// Evicted, or a newer update restarted the idle timer
+		r.mu.Unlock()
+		return
+	}
+	delete(r.shares, key)
+	stopLiveLocationTimers(share)
+	pending := share.pending
+	ctx, loc := share.ctx, share.latest
+	r.mu.Unlock()
+
+	if pending {
+		r.deliver(ctx, share.sessionName, loc, false)
+	}
+	r.deliver(ctx, share.sessionName, loc, true)
+}
+
+func stopLiveLocationTimers(share *liveLocationShare) {
+	if share.flushTimer != nil {
+		share.flushTimer.Stop()
+		share.flushTimer = nil
+	}
+	if share.idleTimer != nil {
+		share.idleTimer.Stop()
+		share.idleTimer = nil
+	}
+}
+
+// deliver sends one message. Errors can only be logged: the webhooks that delivered the updates
+// have already been answered.
+func (r *liveLocationRelay) deliver(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation, ended bool) {
+	if err := r.send(ctx, sessionName, loc, ended); err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			LogFieldSession: sessionName,
+			LogFieldChatID:  SanitizePhoneNumber(loc.ChatID),
+		}).Error("Failed to relay WhatsApp live location")
+	}
+}
+
+// HandleWhatsAppLiveLocation relays an update of a live location shared in a WhatsApp chat
+// when whatsapp.liveLocationIntervalSec is set, coalescing updates within the interval
+func (b *bridge) HandleWhatsAppLiveLocation(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation) error {
+	if b.liveLocations == nil {
+		return nil
+	}
+	b.liveLocations.update(ctx, sessionName, loc)
+	return nil
+}
+
+// sendLiveLocation relays a live location update, or the note that it ended, to the session's
+// Signal destination
+func (b *bridge) sendLiveLocation(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation, ended bool) error {
+	dest, err := b.channelManager.GetSignalDestination(sessionName)
+	if err != nil {
+		return fmt.Errorf("failed to get Signal destination for session %s: %w", sessionName, err)
+	}
+
+	text := formatLiveLocation(b.contactDisplayName(ctx, loc.Sender, loc.SenderName), loc, ended)
+	if _, err := b.sigClient.SendMessage(ctx, dest, text, []string{}); err != nil {
+		return fmt.Errorf("failed to send live location to Signal: %w", err)
+	}
+	return nil
+}
+
+// formatLiveLocation renders a live location update with a map link, or the note that it ended
+func formatLiveLocation(sender string, loc models.WhatsAppLiveLocation, ended bool) string {
+	if ended {
+		return fmt.Sprintf("📍 Live location from %s ended", sender)
+	}
+	coords := strconv.FormatFloat(loc.Latitude, 'f', 6, 64) + "," + strconv.FormatFloat(loc.Longitude, 'f', 6, 64)
+	text := fmt.Sprintf("📍 Live location from %s: %s\nhttps://maps.google.com/?q=%s", sender, coords, coords)
+	if loc.Caption != "" {
+		text += "\n" + loc.Caption
+	}
+	return text
+}
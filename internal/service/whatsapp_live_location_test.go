@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWhatsAppLiveLocation_Coalesced(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	bridge.contactService = nil
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-live", Timestamp: time.Now().UnixMilli()}
+	sent := make(chan string, 10)
+	// The flush and idle timers send from their own goroutines
+	var sendMu sync.Mutex
+	bridge.liveLocations = newLiveLocationRelay(40*time.Millisecond, 120*time.Millisecond, 10, func(ctx context.Context, sessionName string, loc models.WhatsAppLiveLocation, ended bool) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		err := bridge.sendLiveLocation(ctx, sessionName, loc, ended)
+		sent <- sigClient.lastMessage
+		return err
+	}, bridge.logger, bridge.clock)
+
+	next := func() string {
+		t.Helper()
+		select {
+		case text := <-sent:
+			return text
+		case <-time.After(time.Second):
+			t.Fatal("no live location message was sent")
+		}
+		return ""
+	}
+
+	loc := models.WhatsAppLiveLocation{ID: "live-1", ChatID: "family@g.us", Sender: "+15550001111@c.us", SenderName: "Alice"}
+	for i, lat := range []float64{52.5, 52.51, 52.52} {
+		loc.Latitude, loc.Longitude = lat, 13.4
+		require.NoError(t, bridge.HandleWhatsAppLiveLocation(context.Background(), "default", loc))
+		if i == 0 {
+			assert.Equal(t, "📍 Live location from Alice: 52.500000,13.400000\nhttps://maps.google.com/?q=52.500000,13.400000", next(), "the first update is sent at once")
+		}
+	}
+
+	assert.Equal(t, "📍 Live location from Alice: 52.520000,13.400000\nhttps://maps.google.com/?q=52.520000,13.400000", next(), "later updates are coalesced into the latest")
+	assert.Equal(t, "📍 Live location from Alice ended", next())
+	sendMu.Lock()
+	assert.Equal(t, "+1234567890", sigClient.lastRecipient)
+	sendMu.Unlock()
+
+	select {
+	case text := <-sent:
+		t.Fatalf("unexpected message after the share ended: %q", text)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHandleWhatsAppLiveLocation_Disabled(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	loc := models.WhatsAppLiveLocation{ID: "live-1", ChatID: "+15550001111@c.us", Sender: "+15550001111@c.us"}
+	require.NoError(t, bridge.HandleWhatsAppLiveLocation(context.Background(), "default", loc))
+	assert.Empty(t, bridge.sigClient.(*mockSignalClient).lastMessage)
+}
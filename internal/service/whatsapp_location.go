@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"whatsignal/internal/models"
+)
+
+// whatsAppLocationContextKey carries the location pin of an incoming WhatsApp message
+const whatsAppLocationContextKey ContextKey = "whatsapp_location"
+
+// mappingMediaTypeLocation is the media type of the message mapping of a relayed location pin
+const mappingMediaTypeLocation = "location"
+
+// WithWhatsAppLocation returns a context marking the WhatsApp message being handled as a
+// location pin
+func WithWhatsAppLocation(ctx context.Context, loc models.WhatsAppLocation) context.Context {
+	return context.WithValue(ctx, whatsAppLocationContextKey, loc)
+}
+
+// whatsAppLocationFromContext returns the location set by WithWhatsAppLocation, if any
+func whatsAppLocationFromContext(ctx context.Context) (models.WhatsAppLocation, bool) {
+	loc, ok := ctx.Value(whatsAppLocationContextKey).(models.WhatsAppLocation)
+	return loc, ok
+}
+
+// formatWhatsAppLocation renders a location pin for Signal as "📍 <title>" and a map link on
+// the next line. The title is the place's name, else its address, else "Location".
+func formatWhatsAppLocation(loc models.WhatsAppLocation) string {
+	title := loc.Name
+	if title == "" {
+		title = loc.Address
+	}
+	if title == "" {
+		title = "Location"
+	}
+	coords := strconv.FormatFloat(loc.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(loc.Longitude, 'f', -1, 64)
+	return fmt.Sprintf("📍 %s\nhttps://maps.google.com/?q=%s", title, coords)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatWhatsAppLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		loc      models.WhatsAppLocation
+		expected string
+	}{
+		{
+			name:     "named place",
+			loc:      models.WhatsAppLocation{Latitude: 52.370216, Longitude: 4.895168, Name: "Dam Square", Address: "1012 JS Amsterdam"},
+			expected: "📍 Dam Square\nhttps://maps.google.com/?q=52.370216,4.895168",
+		},
+		{
+			name:     "address only",
+			loc:      models.WhatsAppLocation{Latitude: 40.6892, Longitude: -74.0445, Address: "New York, NY 10004"},
+			expected: "📍 New York, NY 10004\nhttps://maps.google.com/?q=40.6892,-74.0445",
+		},
+		{
+			name:     "dropped pin",
+			loc:      models.WhatsAppLocation{Latitude: -33.8568, Longitude: 151.2153},
+			expected: "📍 Location\nhttps://maps.google.com/?q=-33.8568,151.2153",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatWhatsAppLocation(tt.loc))
+		})
+	}
+}
+
+func TestHandleWhatsAppMessage_Location(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	mockDB := bridge.db.(*mockDatabaseService)
+	mockDB.On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-1", Timestamp: time.Now().UnixMilli()}
+
+	ctx := WithWhatsAppLocation(context.Background(), models.WhatsAppLocation{
+		Latitude:  52.370216,
+		Longitude: 4.895168,
+		Name:      "Dam Square",
+	})
+	// WEBJS sends the map thumbnail as the body
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "15550001111@c.us", "msg-1", "15550001111@c.us", "", "/9j/4AAQSkZJRg==", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "15550001111: 📍 Dam Square\nhttps://maps.google.com/?q=52.370216,4.895168", sigClient.lastMessage)
+	mockDB.AssertCalled(t, "SaveMessageMapping", mock.Anything, mock.MatchedBy(func(m *models.MessageMapping) bool {
+		return m.WhatsAppMsgID == "msg-1" && m.MediaType == "location"
+	}))
+}
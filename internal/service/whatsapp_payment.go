@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"whatsignal/internal/models"
+)
+
+// whatsAppPaymentContextKey carries the WhatsApp Pay details of an incoming WhatsApp message
+const whatsAppPaymentContextKey ContextKey = "whatsapp_payment"
+
+// paymentInformationalNote ends every payment summary: the bridge only reports payments, which
+// can only be accepted, declined or paid in WhatsApp
+const paymentInformationalNote = "ℹ️ For information only; handle the payment in WhatsApp"
+
+// WithWhatsAppPayment returns a context marking the WhatsApp message being handled as a
+// WhatsApp Pay payment or payment request
+func WithWhatsAppPayment(ctx context.Context, payment models.WhatsAppPayment) context.Context {
+	return context.WithValue(ctx, whatsAppPaymentContextKey, payment)
+}
+
+// whatsAppPaymentFromContext returns the payment set by WithWhatsAppPayment, if any
+func whatsAppPaymentFromContext(ctx context.Context) (models.WhatsAppPayment, bool) {
+	payment, ok := ctx.Value(whatsAppPaymentContextKey).(models.WhatsAppPayment)
+	return payment, ok
+}
+
+// formatWhatsAppPayment renders a payment for Signal, e.g. "💳 Payment: 45.99 USD — completed"
+// followed by its note and paymentInformationalNote on the lines after. Missing fields are left
+// out; without a note the message body, if any, is shown instead.
+func formatWhatsAppPayment(payment models.WhatsAppPayment, body string) string {
+	var summary strings.Builder
+	summary.WriteString("💳 Payment")
+	if payment.Amount1000 > 0 {
+		summary.WriteString(": " + formatAmount1000(payment.Amount1000))
+		if payment.CurrencyCode != "" {
+			summary.WriteString(" " + payment.CurrencyCode)
+		}
+	}
+	if payment.Status != "" {
+		summary.WriteString(" — " + payment.Status)
+	}
+
+	note := payment.Note
+	if note == "" {
+		note = strings.TrimSpace(body)
+	}
+	if note != "" {
+		summary.WriteString("\n" + note)
+	}
+	summary.WriteString("\n" + paymentInformationalNote)
+	return summary.String()
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatWhatsAppPayment(t *testing.T) {
+	tests := []struct {
+		name     string
+		payment  models.WhatsAppPayment
+		body     string
+		expected string
+	}{
+		{
+			name:     "all fields",
+			payment:  models.WhatsAppPayment{Amount1000: 45990, CurrencyCode: "USD", Status: "completed", Note: "Dinner"},
+			expected: "💳 Payment: 45.99 USD — completed\nDinner\n" + paymentInformationalNote,
+		},
+		{
+			name:     "no status",
+			payment:  models.WhatsAppPayment{Amount1000: 1200000, CurrencyCode: "BRL"},
+			expected: "💳 Payment: 1200.00 BRL\n" + paymentInformationalNote,
+		},
+		{
+			name:     "amount without currency",
+			payment:  models.WhatsAppPayment{Amount1000: 5000, Status: "requested"},
+			expected: "💳 Payment: 5.00 — requested\n" + paymentInformationalNote,
+		},
+		{
+			name:     "no amount",
+			payment:  models.WhatsAppPayment{CurrencyCode: "INR", Status: "declined"},
+			expected: "💳 Payment — declined\n" + paymentInformationalNote,
+		},
+		{
+			name:     "body stands in for the note",
+			payment:  models.WhatsAppPayment{Status: "sent"},
+			body:     " Rent ",
+			expected: "💳 Payment — sent\nRent\n" + paymentInformationalNote,
+		},
+		{
+			name:     "no fields",
+			expected: "💳 Payment\n" + paymentInformationalNote,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatWhatsAppPayment(tt.payment, tt.body))
+		})
+	}
+}
+
+func TestHandleWhatsAppMessage_PaymentSummary(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	bridge.db.(*mockDatabaseService).On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-1", Timestamp: time.Now().UnixMilli()}
+
+	ctx := WithWhatsAppPayment(context.Background(), models.WhatsAppPayment{
+		Amount1000:   250000,
+		CurrencyCode: "INR",
+		Status:       "requested",
+		Note:         "Concert tickets",
+	})
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "15550001111@c.us", "msg-1", "15550001111@c.us", "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "15550001111: 💳 Payment: 250.00 INR — requested\nConcert tickets\n"+paymentInformationalNote, sigClient.lastMessage)
+}
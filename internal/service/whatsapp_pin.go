@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HandleWhatsAppPin relays a message pinned in a WhatsApp chat to Signal. When the pinned
+// message was bridged, the notice quotes its Signal message and names its media type.
+func (b *bridge) HandleWhatsAppPin(ctx context.Context, sessionName string, pin models.WhatsAppPin) error {
+	dest, err := b.channelManager.GetSignalDestination(sessionName)
+	if err != nil {
+		return fmt.Errorf("failed to get Signal destination for session %s: %w", sessionName, err)
+	}
+
+	mapping := b.pinnedMessageMapping(ctx, sessionName, pin)
+	var snippet string
+	var opts []signaltypes.SendOption
+	if mapping != nil {
+		snippet = b.reactionSnippet(mapping)
+		if quoteOpt := b.mappingQuoteOption(sessionName, dest, mapping, pin.FromMe, ""); quoteOpt != nil {
+			opts = append(opts, quoteOpt)
+		}
+	}
+
+	if _, err := b.sigClient.SendMessage(ctx, dest, formatWhatsAppPin(snippet), []string{}, opts...); err != nil {
+		return fmt.Errorf("failed to send pin notice to Signal: %w", err)
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession:   sessionName,
+		LogFieldChatID:    SanitizePhoneNumber(pin.ChatID),
+		LogFieldMessageID: SanitizeWhatsAppMessageID(pin.MessageID),
+		"bridged":         mapping != nil,
+	}).Debug("Relayed WhatsApp pin to Signal")
+
+	return nil
+}
+
+// pinnedMessageMapping finds the mapping of a pinned message, or nil when it was never bridged.
+// Mappings are keyed by WAHA's message ID, which the pin only carries in protocol form, so the
+// WAHA form is tried first.
+func (b *bridge) pinnedMessageMapping(ctx context.Context, sessionName string, pin models.WhatsAppPin) *models.MessageMapping {
+	for _, id := range []string{pin.WAHAMessageID(), pin.MessageID} {
+		mapping, err := b.db.GetMessageMappingByWhatsAppID(ctx, id)
+		if err != nil {
+			b.logger.WithContext(ctx).WithError(err).WithField(LogFieldSession, sessionName).Debug("Failed to look up pinned WhatsApp message")
+			return nil
+		}
+		if mapping != nil {
+			return mapping
+		}
+	}
+	return nil
+}
+
+// formatWhatsAppPin renders a pin notice, with the pinned message's snippet when there is one
+func formatWhatsAppPin(snippet string) string {
+	if snippet == "" {
+		return "📌 A message was pinned"
+	}
+	return fmt.Sprintf("📌 A message was pinned: \"%s\"", snippet)
+}
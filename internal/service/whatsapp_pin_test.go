@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWhatsAppPin_KnownMapping(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	bridge.signalConfig.IntermediaryPhoneNumber = "+1999999999"
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-pin", Timestamp: time.Now().UnixMilli()}
+	pin := models.WhatsAppPin{ChatID: "family@g.us", MessageID: "ABC", Participant: "+15550001111@c.us"}
+	bridge.db.(*mockDatabaseService).On("GetMessageMappingByWhatsAppID", ctx, "false_family@g.us_ABC_+15550001111@c.us").Return(&models.MessageMapping{
+		WhatsAppChatID: "family@g.us",
+		WhatsAppMsgID:  "false_family@g.us_ABC_+15550001111@c.us",
+		SignalMsgID:    "1700000000123",
+		MediaType:      "image",
+		SessionName:    "default",
+	}, nil)
+
+	require.NoError(t, bridge.HandleWhatsAppPin(ctx, "default", pin))
+
+	assert.Equal(t, "+1234567890", sigClient.lastRecipient)
+	assert.Equal(t, `📌 A message was pinned: "[image]"`, sigClient.lastMessage)
+	assert.Equal(t, int64(1700000000123), sigClient.lastSendOptions.QuoteTimestamp)
+	assert.Equal(t, "+1999999999", sigClient.lastSendOptions.QuoteAuthor)
+}
+
+func TestHandleWhatsAppPin_UnknownMapping(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-pin", Timestamp: time.Now().UnixMilli()}
+	db := bridge.db.(*mockDatabaseService)
+	db.On("GetMessageMappingByWhatsAppID", ctx, mock.Anything).Return(nil, nil)
+
+	require.NoError(t, bridge.HandleWhatsAppPin(ctx, "default", models.WhatsAppPin{ChatID: "+15550001111@c.us", MessageID: "ABC"}))
+
+	assert.Equal(t, "📌 A message was pinned", sigClient.lastMessage)
+	assert.Zero(t, sigClient.lastSendOptions.QuoteTimestamp)
+	db.AssertCalled(t, "GetMessageMappingByWhatsAppID", ctx, "false_+15550001111@c.us_ABC")
+	db.AssertCalled(t, "GetMessageMappingByWhatsAppID", ctx, "ABC")
+}
+
+func TestHandleWhatsAppPin_SendFailure(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	bridge.sigClient.(*mockSignalClient).sendMessageErr = assert.AnError
+	bridge.db.(*mockDatabaseService).On("GetMessageMappingByWhatsAppID", ctx, mock.Anything).Return(nil, nil)
+
+	err := bridge.HandleWhatsAppPin(ctx, "default", models.WhatsAppPin{ChatID: "+15550001111@c.us", MessageID: "ABC"})
+	assert.ErrorIs(t, err, assert.AnError)
+}
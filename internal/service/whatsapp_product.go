@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"whatsignal/internal/models"
+)
+
+// whatsAppProductContextKey carries the catalog product an incoming WhatsApp message shares
+const whatsAppProductContextKey ContextKey = "whatsapp_product"
+
+// WithWhatsAppProduct returns a context marking the WhatsApp message being handled as sharing
+// product from a business catalog
+func WithWhatsAppProduct(ctx context.Context, product models.WhatsAppProduct) context.Context {
+	return context.WithValue(ctx, whatsAppProductContextKey, product)
+}
+
+// whatsAppProductFromContext returns the product set by WithWhatsAppProduct, if any
+func whatsAppProductFromContext(ctx context.Context) (models.WhatsAppProduct, bool) {
+	product, ok := ctx.Value(whatsAppProductContextKey).(models.WhatsAppProduct)
+	return product, ok
+}
+
+// formatWhatsAppProduct renders a shared product for Signal, e.g.
+// "🛍️ Product: Teapot — USD 45.99" followed by its description on the next line. Missing
+// fields are left out; without a description the message body, if any, is shown instead.
+func formatWhatsAppProduct(product models.WhatsAppProduct, body string) string {
+	var summary strings.Builder
+	summary.WriteString("🛍️ Product")
+	if product.Title != "" {
+		summary.WriteString(": " + product.Title)
+	}
+	if price := formatProductPrice(product); price != "" {
+		summary.WriteString(" — " + price)
+	}
+
+	description := product.Description
+	if description == "" {
+		description = strings.TrimSpace(body)
+		if description == product.Title {
+			description = ""
+		}
+	}
+	if description != "" {
+		summary.WriteString("\n" + description)
+	}
+	return summary.String()
+}
+
+// formatProductPrice returns the product's price with its currency, such as "EUR 12500.00",
+// or "" when the catalog lists none
+func formatProductPrice(product models.WhatsAppProduct) string {
+	if product.PriceAmount1000 <= 0 {
+		return ""
+	}
+	price := formatAmount1000(product.PriceAmount1000)
+	if product.CurrencyCode == "" {
+		return price
+	}
+	return product.CurrencyCode + " " + price
+}
+
+// formatAmount1000 renders an amount given in thousandths of the currency unit with two decimals
+func formatAmount1000(amount1000 int64) string {
+	amount := amount1000 / 10 // in hundredths
+	return fmt.Sprintf("%d.%02d", amount/100, amount%100)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatWhatsAppProduct(t *testing.T) {
+	tests := []struct {
+		name     string
+		product  models.WhatsAppProduct
+		body     string
+		expected string
+	}{
+		{
+			name:     "all fields",
+			product:  models.WhatsAppProduct{Title: "Espresso cup", Description: "Porcelain, 90 ml", PriceAmount1000: 12500, CurrencyCode: "EUR"},
+			expected: "🛍️ Product: Espresso cup — EUR 12.50\nPorcelain, 90 ml",
+		},
+		{
+			name:     "no price",
+			product:  models.WhatsAppProduct{Title: "Teapot", Description: "Cast iron"},
+			expected: "🛍️ Product: Teapot\nCast iron",
+		},
+		{
+			name:     "price without currency",
+			product:  models.WhatsAppProduct{Title: "Teapot", PriceAmount1000: 45990},
+			expected: "🛍️ Product: Teapot — 45.99",
+		},
+		{
+			name:     "body stands in for the description",
+			product:  models.WhatsAppProduct{Title: "Teapot"},
+			body:     " Back in stock ",
+			expected: "🛍️ Product: Teapot\nBack in stock",
+		},
+		{
+			name:     "body repeating the title",
+			product:  models.WhatsAppProduct{Title: "Teapot"},
+			body:     "Teapot",
+			expected: "🛍️ Product: Teapot",
+		},
+		{
+			name:     "no fields",
+			expected: "🛍️ Product",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatWhatsAppProduct(tt.product, tt.body))
+		})
+	}
+}
+
+func TestHandleWhatsAppMessage_ProductSummary(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	bridge.db.(*mockDatabaseService).On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-1", Timestamp: time.Now().UnixMilli()}
+
+	ctx := WithWhatsAppProduct(context.Background(), models.WhatsAppProduct{
+		Title:           "Teapot",
+		Description:     "Cast iron, 1 l",
+		PriceAmount1000: 45990,
+		CurrencyCode:    "USD",
+	})
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "15550001111@c.us", "msg-1", "15550001111@c.us", "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "15550001111: 🛍️ Product: Teapot — USD 45.99\nCast iron, 1 l", sigClient.lastMessage)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// whatsAppQuoteContextKey carries the WhatsApp message an incoming WhatsApp message replies to
+const whatsAppQuoteContextKey ContextKey = "whatsapp_quote"
+
+// WhatsAppQuote identifies the WhatsApp message that an incoming WhatsApp message replies to
+type WhatsAppQuote struct {
+	MessageID string
+	Body      string
+	// FromMe is set when the quoted message was sent by the bridged WhatsApp account, which
+	// means it was relayed from Signal and its Signal author is the channel's own number
+	FromMe bool
+}
+
+// WithWhatsAppQuote returns a context marking the WhatsApp message being handled as a reply to quote
+func WithWhatsAppQuote(ctx context.Context, quote WhatsAppQuote) context.Context {
+	if quote.MessageID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, whatsAppQuoteContextKey, quote)
+}
+
+// whatsAppQuoteFromContext returns the quote set by WithWhatsAppQuote, if any
+func whatsAppQuoteFromContext(ctx context.Context) (WhatsAppQuote, bool) {
+	quote, ok := ctx.Value(whatsAppQuoteContextKey).(WhatsAppQuote)
+	return quote, ok
+}
+
+// signalQuoteOption returns a send option that relays a WhatsApp reply as a native Signal
+// reply to the Signal message its quoted WhatsApp message was bridged as. It returns nil
+// when the message is not a reply or the quoted message was never bridged.
+func (b *bridge) signalQuoteOption(ctx context.Context, sessionName, destination string) signaltypes.SendOption {
+	quote, ok := whatsAppQuoteFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	mapping, err := b.db.GetMessageMappingByWhatsAppID(ctx, quote.MessageID)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).WithField(LogFieldSession, sessionName).Debug("Failed to look up quoted WhatsApp message, relaying without a Signal quote")
+		return nil
+	}
+	return b.mappingQuoteOption(sessionName, destination, mapping, quote.FromMe, strings.TrimSpace(quote.Body))
+}
+
+// mappingQuoteOption returns a send option quoting the Signal message a mapping was bridged as,
+// or nil when the mapping belongs to another session or has no Signal message yet. fromMe
+// marks messages sent by the bridged WhatsApp account, whose Signal author is destination.
+func (b *bridge) mappingQuoteOption(sessionName, destination string, mapping *models.MessageMapping, fromMe bool, text string) signaltypes.SendOption {
+	timestamp, author, ok := b.mappingSignalTarget(sessionName, destination, mapping, fromMe)
+	if !ok {
+		return nil
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		LogFieldSession:   sessionName,
+		LogFieldMessageID: SanitizeWhatsAppMessageID(mapping.WhatsAppMsgID),
+		"signal_msg_id":   SanitizeMessageID(mapping.SignalMsgID),
+	}).Debug("Quoting bridged message on Signal")
+
+	return signaltypes.WithQuote(timestamp, author, text)
+}
+
+// mappingSignalTarget returns the timestamp and author of the Signal message a mapping was
+// bridged as, the pair Signal identifies a message by, or false when the mapping belongs to
+// another session or has no Signal message yet
+func (b *bridge) mappingSignalTarget(sessionName, destination string, mapping *models.MessageMapping, fromMe bool) (int64, string, bool) {
+	if mapping == nil || mapping.SessionName != sessionName {
+		return 0, "", false
+	}
+
+	// Placeholder IDs ("pending:", "paused:") have no Signal message to point at
+	timestamp, err := strconv.ParseInt(mapping.SignalMsgID, 10, 64)
+	if err != nil || timestamp <= 0 {
+		return 0, "", false
+	}
+
+	author := b.signalConfig.IntermediaryPhoneNumber
+	if fromMe {
+		author = destination
+	}
+	if author == "" {
+		return 0, "", false
+	}
+	return timestamp, author, true
+}
+
+// withUnbridgedQuote prefixes the content of a WhatsApp reply with the quoted text when the
+// reply cannot be relayed as a native Signal reply, because the quoted message was never
+// bridged. WAHA includes the quoted text in the reply, so its context survives.
+func withUnbridgedQuote(ctx context.Context, quoteOpt signaltypes.SendOption, content string) string {
+	quote, ok := whatsAppQuoteFromContext(ctx)
+	if !ok || quoteOpt != nil {
+		return content
+	}
+	return withQuoteContext(quote.Body, content)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithWhatsAppQuoteIgnoresEmptyID(t *testing.T) {
+	ctx := WithWhatsAppQuote(context.Background(), WhatsAppQuote{Body: "hi"})
+	_, ok := whatsAppQuoteFromContext(ctx)
+	assert.False(t, ok)
+}
+
+func TestHandleWhatsAppReply_UsesNativeSignalQuote(t *testing.T) {
+	tests := []struct {
+		name           string
+		quote          WhatsAppQuote
+		mapping        *models.MessageMapping
+		expectedTS     int64
+		expectedAuthor string
+	}{
+		{
+			name:           "reply to a relayed WhatsApp message",
+			quote:          WhatsAppQuote{MessageID: "wa_original", Body: "dinner at 8?"},
+			mapping:        &models.MessageMapping{WhatsAppMsgID: "wa_original", SignalMsgID: "1700000000123", SessionName: "default"},
+			expectedTS:     1700000000123,
+			expectedAuthor: "+1999999999",
+		},
+		{
+			name:           "reply to a message sent from Signal",
+			quote:          WhatsAppQuote{MessageID: "wa_original", Body: "on my way", FromMe: true},
+			mapping:        &models.MessageMapping{WhatsAppMsgID: "wa_original", SignalMsgID: "1700000000456", SessionName: "default"},
+			expectedTS:     1700000000456,
+			expectedAuthor: "+1234567890",
+		},
+		{
+			name:    "quoted message was never bridged",
+			quote:   WhatsAppQuote{MessageID: "wa_unknown"},
+			mapping: nil,
+		},
+		{
+			name:    "quoted message still pending",
+			quote:   WhatsAppQuote{MessageID: "wa_original"},
+			mapping: &models.MessageMapping{WhatsAppMsgID: "wa_original", SignalMsgID: "pending:wa_original", SessionName: "default"},
+		},
+		{
+			name:    "quoted message from another session",
+			quote:   WhatsAppQuote{MessageID: "wa_original"},
+			mapping: &models.MessageMapping{WhatsAppMsgID: "wa_original", SignalMsgID: "1700000000123", SessionName: "business"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			bridge.signalConfig.IntermediaryPhoneNumber = "+1999999999"
+			sigClient := bridge.sigClient.(*mockSignalClient)
+			sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{
+				MessageID: "sig-reply",
+				Timestamp: time.Now().UnixMilli(),
+			}
+			bridge.db.(*mockDatabaseService).On("GetMessageMappingByWhatsAppID", mock.Anything, tt.quote.MessageID).Return(tt.mapping, nil)
+
+			ctx := WithWhatsAppQuote(context.Background(), tt.quote)
+			err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123", "wa_reply", "sender123", "", "sounds good", "")
+			require.NoError(t, err)
+
+			assert.Equal(t, "sender123: sounds good", sigClient.lastMessage, "the reply text is not prefixed with the quote")
+			assert.Equal(t, tt.expectedTS, sigClient.lastSendOptions.QuoteTimestamp)
+			assert.Equal(t, tt.expectedAuthor, sigClient.lastSendOptions.QuoteAuthor)
+			if tt.expectedTS != 0 {
+				assert.Equal(t, tt.quote.Body, sigClient.lastSendOptions.QuoteMessage)
+			}
+		})
+	}
+}
+
+func TestHandleWhatsAppReply_UnbridgedQuoteIsPrefixed(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{
+		MessageID: "sig-reply",
+		Timestamp: time.Now().UnixMilli(),
+	}
+	bridge.db.(*mockDatabaseService).On("GetMessageMappingByWhatsAppID", mock.Anything, "wa_old").Return(nil, nil)
+
+	ctx := WithWhatsAppQuote(context.Background(), WhatsAppQuote{MessageID: "wa_old", Body: "dinner at 8?\nor 9"})
+	err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123", "wa_reply", "sender123", "", "sounds good", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "sender123: > dinner at 8?\n> or 9\n\nsounds good", sigClient.lastMessage)
+	assert.Zero(t, sigClient.lastSendOptions.QuoteTimestamp, "an unbridged message cannot be quoted natively")
+}
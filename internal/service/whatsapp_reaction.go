@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WhatsAppReaction describes a reaction to a WhatsApp message that was bridged to Signal
+type WhatsAppReaction struct {
+	// ReactorID is the WhatsApp ID of the person who reacted
+	ReactorID string
+	// ReactorName is the display name WhatsApp sent with the reaction, if any
+	ReactorName string
+	// Emoji is the reaction; empty when the reaction was removed
+	Emoji string
+	// FromMe is set when the reacted message was sent by the bridged WhatsApp account
+	FromMe bool
+}
+
+// HandleWhatsAppReaction relays a reaction to a bridged WhatsApp message to Signal. In group
+// chats the notice names the reactor; in one-on-one chats the reactor is the chat itself and
+// is omitted. The notice quotes the reacted Signal message where it can. With
+// whatsapp.nativeReactions set, reactions are instead mirrored as a Signal reaction on the
+// bridged message, and with whatsapp.reactionAggregateWindowSec set they are folded into a
+// tally that is relayed once the window passes.
+func (b *bridge) HandleWhatsAppReaction(ctx context.Context, sessionName string, reaction WhatsAppReaction, mapping *models.MessageMapping) error {
+	if mapping == nil {
+		return fmt.Errorf("no mapping for reacted message")
+	}
+
+	dest, err := b.channelManager.GetSignalDestination(sessionName)
+	if err != nil {
+		return fmt.Errorf("failed to get Signal destination for session %s: %w", sessionName, err)
+	}
+
+	if b.nativeReactions != nil {
+		if handled, err := b.relayNativeReaction(ctx, sessionName, dest, reaction, mapping); handled || err != nil {
+			return err
+		}
+	}
+
+	if b.reactionTallies != nil {
+		b.reactionTallies.add(ctx, sessionName, reaction, mapping)
+		return nil
+	}
+
+	reactor := ""
+	if strings.HasSuffix(mapping.WhatsAppChatID, "@g.us") {
+		reactor = b.reactorDisplayName(ctx, reaction)
+	}
+	text := formatWhatsAppReaction(reactor, reaction.Emoji, b.reactionSnippet(mapping))
+
+	var opts []signaltypes.SendOption
+	if quoteOpt := b.mappingQuoteOption(sessionName, dest, mapping, reaction.FromMe, ""); quoteOpt != nil {
+		opts = append(opts, quoteOpt)
+	}
+
+	if _, err := b.sigClient.SendMessage(ctx, dest, text, []string{}, opts...); err != nil {
+		return fmt.Errorf("failed to send reaction to Signal: %w", err)
+	}
+
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession:   sessionName,
+		LogFieldChatID:    SanitizePhoneNumber(mapping.WhatsAppChatID),
+		LogFieldMessageID: SanitizeWhatsAppMessageID(mapping.WhatsAppMsgID),
+		"emoji":           reaction.Emoji,
+	}).Debug("Relayed WhatsApp reaction to Signal")
+
+	return nil
+}
+
+// reactorDisplayName resolves the name of a group member who reacted, preferring the contact
+// service, then the name sent with the reaction, then the bare number
+func (b *bridge) reactorDisplayName(ctx context.Context, reaction WhatsAppReaction) string {
+	return b.contactDisplayName(ctx, reaction.ReactorID, reaction.ReactorName)
+}
+
+// contactDisplayName resolves the name of the WhatsApp user with the given ID, preferring the
+// contact service, then fallbackName, then the bare number
+func (b *bridge) contactDisplayName(ctx context.Context, id, fallbackName string) string {
+	phone := strings.TrimSuffix(strings.TrimSuffix(id, "@c.us"), "@lid")
+	if b.contactsEnabled() && phone != "" {
+		// The contact service echoes the number back when it knows no name for it
+		if name := b.contactService.GetContactDisplayName(ctx, phone); name != "" && name != phone {
+			return name
+		}
+	}
+	if fallbackName != "" {
+		return fallbackName
+	}
+	if phone != "" {
+		return phone
+	}
+	return "Someone"
+}
+
+// reactionSnippet describes the reacted message from its mapping. Mappings do not store message
+// text, so only media messages get a snippet; text messages rely on the Signal quote instead.
+func (b *bridge) reactionSnippet(mapping *models.MessageMapping) string {
+	mediaType := mapping.MediaType
+	if mediaType == "" && mapping.MediaPath != nil && *mapping.MediaPath != "" {
+		mediaType = b.mediaRouter.GetMediaType(*mapping.MediaPath)
+	}
+	if mediaType == "" {
+		return ""
+	}
+	return "[" + mediaType + "]"
+}
+
+// formatWhatsAppReaction renders a reaction notice. An empty reactor omits the attribution and
+// an empty emoji means the reaction was removed.
+func formatWhatsAppReaction(reactor, emoji, snippet string) string {
+	var text string
+	switch {
+	case reactor != "" && emoji != "":
+		text = fmt.Sprintf("%s reacted %s", reactor, emoji)
+	case reactor != "":
+		text = fmt.Sprintf("%s removed their reaction", reactor)
+	case emoji != "":
+		text = fmt.Sprintf("Reacted %s", emoji)
+	default:
+		text = "Removed reaction"
+	}
+
+	if snippet == "" {
+		return text
+	}
+	if emoji == "" {
+		return fmt.Sprintf("%s from: \"%s\"", text, snippet)
+	}
+	return fmt.Sprintf("%s to: \"%s\"", text, snippet)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWhatsAppReaction(t *testing.T) {
+	tests := []struct {
+		name            string
+		reaction        WhatsAppReaction
+		mapping         *models.MessageMapping
+		contactName     string
+		expectedMessage string
+		expectedQuoteTS int64
+	}{
+		{
+			name:            "group reaction names the reactor from the contact service",
+			reaction:        WhatsAppReaction{ReactorID: "+15550001111@c.us", ReactorName: "ali", Emoji: "👍"},
+			mapping:         &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_photo", SignalMsgID: "1700000000123", MediaType: "image", SessionName: "default"},
+			contactName:     "Alice",
+			expectedMessage: `Alice reacted 👍 to: "[image]"`,
+			expectedQuoteTS: 1700000000123,
+		},
+		{
+			name:            "group reaction falls back to the webhook name for unknown contacts",
+			reaction:        WhatsAppReaction{ReactorID: "+15550001111@c.us", ReactorName: "ali", Emoji: "🔥"},
+			mapping:         &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_text", SignalMsgID: "1700000000456", SessionName: "default"},
+			contactName:     "+15550001111",
+			expectedMessage: "ali reacted 🔥",
+			expectedQuoteTS: 1700000000456,
+		},
+		{
+			name:            "group reaction removed",
+			reaction:        WhatsAppReaction{ReactorID: "+15550001111@c.us", Emoji: ""},
+			mapping:         &models.MessageMapping{WhatsAppChatID: "family@g.us", WhatsAppMsgID: "wa_photo", SignalMsgID: "1700000000123", MediaType: "image", SessionName: "default"},
+			contactName:     "Alice",
+			expectedMessage: `Alice removed their reaction from: "[image]"`,
+			expectedQuoteTS: 1700000000123,
+		},
+		{
+			name:            "one-on-one reaction omits the reactor",
+			reaction:        WhatsAppReaction{ReactorID: "+15550001111@c.us", ReactorName: "ali", Emoji: "👍"},
+			mapping:         &models.MessageMapping{WhatsAppChatID: "+15550001111@c.us", WhatsAppMsgID: "wa_photo", SignalMsgID: "1700000000123", MediaType: "image", SessionName: "default"},
+			expectedMessage: `Reacted 👍 to: "[image]"`,
+			expectedQuoteTS: 1700000000123,
+		},
+		{
+			name:            "one-on-one reaction to a message not yet on Signal",
+			reaction:        WhatsAppReaction{ReactorID: "+15550001111@c.us", Emoji: "❤️"},
+			mapping:         &models.MessageMapping{WhatsAppChatID: "+15550001111@c.us", WhatsAppMsgID: "wa_text", SignalMsgID: "pending:wa_text", SessionName: "default"},
+			expectedMessage: "Reacted ❤️",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			bridge.signalConfig.IntermediaryPhoneNumber = "+1999999999"
+			sigClient := bridge.sigClient.(*mockSignalClient)
+			sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{
+				MessageID: "sig-reaction",
+				Timestamp: time.Now().UnixMilli(),
+			}
+			contacts := &mockContactService{}
+			contacts.On("GetContactDisplayName", mock.Anything, "+15550001111").Return(tt.contactName).Maybe()
+			bridge.contactService = contacts
+
+			err := bridge.HandleWhatsAppReaction(context.Background(), "default", tt.reaction, tt.mapping)
+			require.NoError(t, err)
+
+			assert.Equal(t, "+1234567890", sigClient.lastRecipient)
+			assert.Equal(t, tt.expectedMessage, sigClient.lastMessage)
+			assert.Equal(t, tt.expectedQuoteTS, sigClient.lastSendOptions.QuoteTimestamp)
+			if tt.expectedQuoteTS != 0 {
+				assert.Equal(t, "+1999999999", sigClient.lastSendOptions.QuoteAuthor)
+			}
+		})
+	}
+}
+
+func TestHandleWhatsAppReaction_RequiresMapping(t *testing.T) {
+	bridge, _, cleanup := setupTestBridge(t)
+	defer cleanup()
+
+	err := bridge.HandleWhatsAppReaction(context.Background(), "default", WhatsAppReaction{Emoji: "👍"}, nil)
+	require.Error(t, err)
+}
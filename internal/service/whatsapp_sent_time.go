@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// whatsAppSentAtContextKey carries the time an incoming WhatsApp message was sent
+const whatsAppSentAtContextKey ContextKey = "whatsapp_sent_at"
+
+// WithWhatsAppSentAt returns a context carrying the time the WhatsApp message being handled was
+// sent, as reported by WAHA. A zero time leaves ctx unchanged.
+func WithWhatsAppSentAt(ctx context.Context, sentAt time.Time) context.Context {
+	if sentAt.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, whatsAppSentAtContextKey, sentAt)
+}
+
+// whatsAppSentAtFromContext returns the time set by WithWhatsAppSentAt, or the zero time
+func whatsAppSentAtFromContext(ctx context.Context) time.Time {
+	sentAt, _ := ctx.Value(whatsAppSentAtContextKey).(time.Time)
+	return sentAt
+}
+
+// loadDisplayLocation returns the time zone named by server.displayTimezone. An empty name is
+// UTC; an unknown one is logged and also falls back to UTC, so a typo never stops relaying.
+func loadDisplayLocation(name string, logger *logrus.Logger) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		logger.WithError(err).WithField("timezone", name).Warn("Invalid display timezone, showing times in UTC")
+		return time.UTC
+	}
+	return loc
+}
+
+// formatSentTime renders the prefix for a message sent at sentAt as seen at now in loc: the time
+// alone for a message from the same day, with the date for an older one
+func formatSentTime(sentAt, now time.Time, loc *time.Location) string {
+	sentAt, now = sentAt.In(loc), now.In(loc)
+	if sentAt.Year() == now.Year() && sentAt.YearDay() == now.YearDay() {
+		return "[" + sentAt.Format("15:04") + "] "
+	}
+	return "[" + sentAt.Format("Jan 2 15:04") + "] "
+}
+
+// sentTimePrefix returns the send time prefix for the message being relayed, or "" when
+// whatsapp.showSentTime is off or WAHA did not report a timestamp
+func (b *bridge) sentTimePrefix(ctx context.Context) string {
+	if b.displayLocation == nil {
+		return ""
+	}
+	sentAt := whatsAppSentAtFromContext(ctx)
+	if sentAt.IsZero() {
+		return ""
+	}
+	return formatSentTime(sentAt, b.clock.Now(), b.displayLocation)
+}
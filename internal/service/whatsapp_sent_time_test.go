@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSentTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	sentAt := time.Date(2026, 10, 16, 7, 14, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		now      time.Time
+		loc      *time.Location
+		expected string
+	}{
+		{name: "same day in UTC", now: sentAt.Add(time.Hour), loc: time.UTC, expected: "[07:14] "},
+		{name: "same day in configured zone", now: sentAt.Add(time.Hour), loc: berlin, expected: "[09:14] "},
+		{name: "older message shows the date", now: sentAt.Add(24 * time.Hour), loc: berlin, expected: "[Oct 16 09:14] "},
+		{name: "day boundary follows the zone", now: time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC), loc: berlin, expected: "[Oct 16 09:14] "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatSentTime(sentAt, tt.now, tt.loc))
+		})
+	}
+}
+
+func TestLoadDisplayLocation(t *testing.T) {
+	logger, hook := logtest.NewNullLogger()
+
+	assert.Equal(t, time.UTC, loadDisplayLocation("", logger))
+	assert.Empty(t, hook.AllEntries())
+
+	assert.Equal(t, "America/New_York", loadDisplayLocation("America/New_York", logger).String())
+	assert.Empty(t, hook.AllEntries())
+
+	assert.Equal(t, time.UTC, loadDisplayLocation("Mars/Olympus_Mons", logger))
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Equal(t, "Mars/Olympus_Mons", hook.LastEntry().Data["timezone"])
+}
+
+func TestHandleWhatsAppMessage_SentTimePrefix(t *testing.T) {
+	sentAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name            string
+		location        *time.Location
+		sentAt          time.Time
+		expectedMessage string
+	}{
+		{
+			name:            "enabled",
+			location:        time.UTC,
+			sentAt:          sentAt,
+			expectedMessage: formatSentTime(sentAt, time.Now(), time.UTC) + "sender123: hi",
+		},
+		{
+			name:            "enabled without timestamp",
+			location:        time.UTC,
+			expectedMessage: "sender123: hi",
+		},
+		{
+			name:            "disabled",
+			sentAt:          sentAt,
+			expectedMessage: "sender123: hi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			bridge.displayLocation = tt.location
+			sigClient := bridge.sigClient.(*mockSignalClient)
+			sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-timed", Timestamp: time.Now().UnixMilli()}
+
+			ctx := WithWhatsAppSentAt(context.Background(), tt.sentAt)
+			err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123@c.us", "msg-timed", "sender123", "", "hi", "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMessage, sigClient.lastMessage)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"whatsignal/internal/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+// viewOnceNotice prefixes view-once media relayed to Signal, where it does not disappear
+const viewOnceNotice = "⚠️ View-once media"
+
+// whatsAppViewOnceContextKey marks an incoming WhatsApp message as view-once media
+const whatsAppViewOnceContextKey ContextKey = "whatsapp_view_once"
+
+// WithWhatsAppViewOnce returns a context marking the WhatsApp message being handled as view-once
+// media
+func WithWhatsAppViewOnce(ctx context.Context) context.Context {
+	return context.WithValue(ctx, whatsAppViewOnceContextKey, true)
+}
+
+// isWhatsAppViewOnce reports whether WithWhatsAppViewOnce marked the message
+func isWhatsAppViewOnce(ctx context.Context) bool {
+	viewOnce, _ := ctx.Value(whatsAppViewOnceContextKey).(bool)
+	return viewOnce
+}
+
+// markViewOnce puts viewOnceNotice before the caption of view-once media
+func markViewOnce(caption string) string {
+	if caption == "" {
+		return viewOnceNotice
+	}
+	return viewOnceNotice + "\n" + caption
+}
+
+// dropViewOnceMessage stores the mapping for view-once media blocked by whatsapp.blockViewOnce
+// without relaying it, so replies and reactions to it still resolve
+func (b *bridge) dropViewOnceMessage(ctx context.Context, sessionName, chatID, msgID string) error {
+	if err := b.saveUnrelayedMapping(ctx, sessionName, chatID, msgID, "view_once"); err != nil {
+		return fmt.Errorf("failed to save message mapping for view-once message: %w", err)
+	}
+	metrics.IncrementCounter("view_once_blocked_total", map[string]string{
+		"session": sessionName,
+	}, "View-once WhatsApp media not relayed because of whatsapp.blockViewOnce")
+	b.logger.WithContext(ctx).WithFields(logrus.Fields{
+		LogFieldSession:   sessionName,
+		LogFieldMessageID: SanitizeWhatsAppMessageID(msgID),
+	}).Info("WhatsApp message is view-once media, stored without relaying")
+	return nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWhatsAppMessage_ViewOnce(t *testing.T) {
+	tests := []struct {
+		name     string
+		block    bool
+		viewOnce bool
+		caption  string
+		expected string
+	}{
+		{name: "caption is prefixed", viewOnce: true, caption: "for your eyes only", expected: "sender123: ⚠️ View-once media\nfor your eyes only"},
+		{name: "notice without a caption", viewOnce: true, expected: "sender123: ⚠️ View-once media"},
+		{name: "blocked", block: true, viewOnce: true, caption: "for your eyes only"},
+		{name: "blocking leaves other messages alone", block: true, caption: "hi", expected: "sender123: hi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge, _, cleanup := setupTestBridge(t)
+			defer cleanup()
+
+			bridge.blockViewOnce = tt.block
+			mockDB := bridge.db.(*mockDatabaseService)
+			mockDB.On("SaveMessageMapping", mock.Anything, mock.AnythingOfType("*models.MessageMapping")).Return(nil)
+			sigClient := bridge.sigClient.(*mockSignalClient)
+			sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig-1", Timestamp: time.Now().UnixMilli()}
+
+			ctx := context.Background()
+			if tt.viewOnce {
+				ctx = WithWhatsAppViewOnce(ctx)
+			}
+			err := bridge.HandleWhatsAppMessageWithSession(ctx, "default", "chat123@c.us", "msg-once", "sender123", "", tt.caption, "")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, sigClient.lastMessage)
+			if tt.expected == "" {
+				mockDB.AssertCalled(t, "SaveMessageMapping", mock.Anything, mock.MatchedBy(func(m *models.MessageMapping) bool {
+					return m.WhatsAppMsgID == "msg-once" && m.SignalMsgID == "view_once:msg-once"
+				}))
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/models"
+	"whatsignal/internal/validation"
+	signaltypes "whatsignal/pkg/signal/types"
+)
+
+// chatCommandWhois replies with what the bridge knows about a WhatsApp number
+const chatCommandWhois = "/whois"
+
+// parseWhoisCommand reports whether a Signal message is a /whois command and returns its
+// argument with spaces, dashes and parentheses removed
+func parseWhoisCommand(msg *signaltypes.SignalMessage) (string, bool) {
+	if len(msg.Attachments) > 0 {
+		return "", false
+	}
+	fields := strings.Fields(msg.Message)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], chatCommandWhois) {
+		return "", false
+	}
+	number := strings.NewReplacer("-", "", "(", "", ")", "").Replace(strings.Join(fields[1:], ""))
+	return number, true
+}
+
+// handleWhoisCommand replies to a /whois command with the contact details, group memberships and
+// last bridged message of a WhatsApp number. Only the channel's own Signal number may use it;
+// the command is never relayed either way. It reports whether msg was a whois command.
+func (b *bridge) handleWhoisCommand(ctx context.Context, msg *signaltypes.SignalMessage, sessionName string) bool {
+	number, ok := parseWhoisCommand(msg)
+	if !ok {
+		return false
+	}
+
+	reply := fmt.Sprintf("Usage: %s +15551234567", chatCommandWhois)
+	if validation.ValidateE164PhoneNumber(number) == nil {
+		reply = b.whoisReply(ctx, strings.TrimPrefix(number, "+"))
+	}
+
+	if err := b.SendSignalNotificationForSession(ctx, sessionName, reply); err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to send whois reply")
+	}
+	return true
+}
+
+// whoisReply looks phone up in the contact cache, falling back to WAHA, and renders the result
+func (b *bridge) whoisReply(ctx context.Context, phone string) string {
+	contactID := phone + "@c.us"
+	source := "cache"
+
+	contact, err := b.db.GetContactByPhone(ctx, phone)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to look up cached contact for whois")
+	}
+	if contact == nil {
+		source = "WhatsApp"
+		waContact, err := b.waClient.GetContact(ctx, contactID)
+		if err != nil {
+			b.logger.WithContext(ctx).WithError(err).Warn("Failed to look up contact on WhatsApp for whois")
+		}
+		if waContact == nil {
+			return fmt.Sprintf("No WhatsApp contact found for +%s.", phone)
+		}
+		contact = &models.Contact{}
+		contact.FromWAContact(waContact)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Whois +%s (from %s)\n", phone, source)
+	fmt.Fprintf(&sb, "Name: %s\n", whoisValue(contact.Name))
+	fmt.Fprintf(&sb, "Push name: %s\n", whoisValue(contact.PushName))
+	fmt.Fprintf(&sb, "Groups: %s\n", b.whoisGroups(ctx, contactID))
+	fmt.Fprintf(&sb, "Last bridged: %s", b.whoisLastBridged(ctx, contactID))
+	return sb.String()
+}
+
+// whoisGroups lists the WhatsApp groups contactID is a member of
+func (b *bridge) whoisGroups(ctx context.Context, contactID string) string {
+	batchSize := constants.DefaultContactSyncBatchSize
+	var names []string
+	for offset := 0; ; offset += batchSize {
+		groups, err := b.waClient.GetAllGroups(ctx, batchSize, offset)
+		if err != nil {
+			b.logger.WithContext(ctx).WithError(err).Warn("Failed to list groups for whois")
+			return "unavailable"
+		}
+		for _, group := range groups {
+			for _, participant := range group.Participants {
+				if participant.ID == contactID {
+					names = append(names, whoisValue(group.Subject))
+					break
+				}
+			}
+		}
+		if len(groups) < batchSize {
+			break
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// whoisLastBridged returns when a message of the direct chat with contactID was last bridged
+func (b *bridge) whoisLastBridged(ctx context.Context, contactID string) string {
+	mapping, err := b.db.GetLatestMessageMappingByWhatsAppChatID(ctx, contactID)
+	if err != nil {
+		b.logger.WithContext(ctx).WithError(err).Warn("Failed to look up last bridged message for whois")
+		return "unavailable"
+	}
+	if mapping == nil || mapping.ForwardedAt.IsZero() {
+		return "never"
+	}
+	return mapping.ForwardedAt.UTC().Format(time.RFC3339)
+}
+
+func whoisValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWhoisCommand(t *testing.T) {
+	number, ok := parseWhoisCommand(&signaltypes.SignalMessage{Message: " /WHOIS +1 (555) 123-4567 "})
+	assert.True(t, ok)
+	assert.Equal(t, "+15551234567", number)
+
+	number, ok = parseWhoisCommand(&signaltypes.SignalMessage{Message: "/whois"})
+	assert.True(t, ok)
+	assert.Equal(t, "", number)
+
+	_, ok = parseWhoisCommand(&signaltypes.SignalMessage{Message: "/whoisthis"})
+	assert.False(t, ok)
+	_, ok = parseWhoisCommand(&signaltypes.SignalMessage{Message: "/whois +15551234567", Attachments: []string{"a.jpg"}})
+	assert.False(t, ok)
+}
+
+// setupWhoisBridge returns a test bridge whose WhatsApp sends are counted in sends
+func setupWhoisBridge(t *testing.T) (*bridge, *mockDatabaseService, *mockWhatsAppClient, *mockSignalClient, *int) {
+	t.Helper()
+	bridge, _, cleanup := setupTestBridge(t)
+	t.Cleanup(cleanup)
+
+	sigClient := bridge.sigClient.(*mockSignalClient)
+	sigClient.sendMessageResponse = &signaltypes.SendMessageResponse{MessageID: "sig_sent", Timestamp: time.Now().UnixMilli()}
+
+	sends := 0
+	waClient := bridge.waClient.(*mockWhatsAppClient)
+	waClient.sendTextFunc = func(ctx context.Context, chatID, text string) (*types.SendMessageResponse, error) {
+		sends++
+		return &types.SendMessageResponse{MessageID: "wa_sent", Status: "sent"}, nil
+	}
+	return bridge, bridge.db.(*mockDatabaseService), waClient, sigClient, &sends
+}
+
+func TestWhoisCommand(t *testing.T) {
+	groups := []types.Group{
+		{Subject: "Family", Participants: []types.GroupParticipant{{ID: "15551234567@c.us"}, {ID: "15550000000@c.us"}}},
+		{Subject: "Work", Participants: []types.GroupParticipant{{ID: "15550000000@c.us"}}},
+	}
+	lastBridged := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+
+	t.Run("cached contact", func(t *testing.T) {
+		bridge, mockDB, waClient, sigClient, sends := setupWhoisBridge(t)
+		mockDB.On("GetContactByPhone", mock.Anything, "15551234567").Return(&models.Contact{
+			ContactID: "15551234567@c.us",
+			Name:      "Alice",
+			PushName:  "Ali",
+		}, nil).Once()
+		mockDB.On("GetLatestMessageMappingByWhatsAppChatID", mock.Anything, "15551234567@c.us").Return(&models.MessageMapping{ForwardedAt: lastBridged}, nil).Once()
+		waClient.On("GetAllGroups", mock.Anything, 100, 0).Return(groups, nil).Once()
+
+		err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{MessageID: "cmd1", Sender: "+1234567890", Message: "/whois +1 555 123 4567"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, *sends)
+		assert.Equal(t, "Whois +15551234567 (from cache)\nName: Alice\nPush name: Ali\nGroups: Family\nLast bridged: 2026-10-01T09:30:00Z", sigClient.lastMessage)
+		waClient.AssertNotCalled(t, "GetContact", mock.Anything, mock.Anything)
+		mockDB.AssertExpectations(t)
+		waClient.AssertExpectations(t)
+	})
+
+	t.Run("live lookup fallback", func(t *testing.T) {
+		bridge, mockDB, waClient, sigClient, sends := setupWhoisBridge(t)
+		mockDB.On("GetContactByPhone", mock.Anything, "15551234567").Return(nil, nil).Once()
+		mockDB.On("GetLatestMessageMappingByWhatsAppChatID", mock.Anything, "15551234567@c.us").Return(nil, nil).Once()
+		waClient.On("GetContact", mock.Anything, "15551234567@c.us").Return(&types.Contact{
+			ID:       "15551234567@c.us",
+			Number:   "15551234567",
+			PushName: "Ali",
+		}, nil).Once()
+		waClient.On("GetAllGroups", mock.Anything, 100, 0).Return(nil, errors.New("WAHA down")).Once()
+
+		err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{MessageID: "cmd2", Sender: "+1234567890", Message: "/whois +15551234567"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, *sends)
+		assert.Equal(t, "Whois +15551234567 (from WhatsApp)\nName: -\nPush name: Ali\nGroups: unavailable\nLast bridged: never", sigClient.lastMessage)
+		mockDB.AssertExpectations(t)
+		waClient.AssertExpectations(t)
+	})
+
+	t.Run("unknown number", func(t *testing.T) {
+		bridge, mockDB, waClient, sigClient, sends := setupWhoisBridge(t)
+		mockDB.On("GetContactByPhone", mock.Anything, "15559999999").Return(nil, nil).Once()
+		waClient.On("GetContact", mock.Anything, "15559999999@c.us").Return(nil, errors.New("not found")).Once()
+
+		err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{MessageID: "cmd3", Sender: "+1234567890", Message: "/whois +15559999999"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, *sends)
+		assert.Equal(t, "No WhatsApp contact found for +15559999999.", sigClient.lastMessage)
+		waClient.AssertNotCalled(t, "GetAllGroups", mock.Anything, mock.Anything, mock.Anything)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		bridge, mockDB, _, sigClient, sends := setupWhoisBridge(t)
+
+		err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{MessageID: "cmd4", Sender: "+1234567890", Message: "/whois alice"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, *sends)
+		assert.Equal(t, "Usage: /whois +15551234567", sigClient.lastMessage)
+		mockDB.AssertNotCalled(t, "GetContactByPhone", mock.Anything, mock.Anything)
+	})
+
+	t.Run("other sender", func(t *testing.T) {
+		bridge, mockDB, _, sigClient, sends := setupWhoisBridge(t)
+
+		err := bridge.HandleSignalMessage(context.Background(), &signaltypes.SignalMessage{MessageID: "cmd5", Sender: "+1999999999", Message: "/whois +15551234567"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, *sends)
+		assert.Empty(t, sigClient.lastMessage)
+		mockDB.AssertNotCalled(t, "GetContactByPhone", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+
+	"whatsignal/internal/metrics"
+)
+
+// WorkQueue bounds how much inbound work, WhatsApp webhooks and Signal polls, is processed at
+// once, so a burst cannot grow memory without limit. Each unit of work takes a slot for as long
+// as it runs: the webhook handler sheds work when no slot is free and the Signal poller waits
+// for one. A nil WorkQueue is unbounded.
+type WorkQueue struct {
+	slots chan struct{}
+}
+
+// NewWorkQueue returns a queue of depth slots, or nil (unbounded) when depth is not positive
+func NewWorkQueue(depth int) *WorkQueue {
+	if depth <= 0 {
+		return nil
+	}
+	metrics.SetGauge("work_queue_capacity", float64(depth), nil, "Inbound work processed at once before new work is shed or paused")
+	q := &WorkQueue{slots: make(chan struct{}, depth)}
+	q.recordDepth()
+	return q
+}
+
+// TryAcquire takes a slot without waiting and reports whether one was free
+func (q *WorkQueue) TryAcquire() bool {
+	if q == nil {
+		return true
+	}
+	select {
+	case q.slots <- struct{}{}:
+		q.recordDepth()
+		return true
+	default:
+		return false
+	}
+}
+
+// Acquire waits for a free slot until ctx is done
+func (q *WorkQueue) Acquire(ctx context.Context) error {
+	if q == nil {
+		return nil
+	}
+	select {
+	case q.slots <- struct{}{}:
+		q.recordDepth()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by TryAcquire or Acquire
+func (q *WorkQueue) Release() {
+	if q == nil {
+		return
+	}
+	<-q.slots
+	q.recordDepth()
+}
+
+// Depth returns the number of slots in use
+func (q *WorkQueue) Depth() int {
+	if q == nil {
+		return 0
+	}
+	return len(q.slots)
+}
+
+// Capacity returns the number of slots, or 0 for an unbounded queue
+func (q *WorkQueue) Capacity() int {
+	if q == nil {
+		return 0
+	}
+	return cap(q.slots)
+}
+
+func (q *WorkQueue) recordDepth() {
+	metrics.SetGauge("work_queue_depth", float64(len(q.slots)), nil, "Inbound work being processed")
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkQueue_BoundsDepth(t *testing.T) {
+	queue := NewWorkQueue(2)
+	require.NotNil(t, queue)
+	assert.Equal(t, 2, queue.Capacity())
+
+	assert.True(t, queue.TryAcquire())
+	assert.True(t, queue.TryAcquire())
+	assert.False(t, queue.TryAcquire(), "a full queue has no free slot")
+	assert.Equal(t, 2, queue.Depth())
+
+	queue.Release()
+	assert.Equal(t, 1, queue.Depth())
+	assert.True(t, queue.TryAcquire(), "a released slot can be taken again")
+}
+
+func TestWorkQueue_AcquireWaitsForSlot(t *testing.T) {
+	queue := NewWorkQueue(1)
+	require.True(t, queue.TryAcquire())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, queue.Acquire(ctx), context.DeadlineExceeded)
+
+	acquired := make(chan error, 1)
+	go func() { acquired <- queue.Acquire(context.Background()) }()
+	select {
+	case <-acquired:
+		t.Fatal("Acquire returned while the queue was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	queue.Release()
+	select {
+	case err := <-acquired:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Acquire did not return after a slot was released")
+	}
+}
+
+func TestWorkQueue_NilIsUnbounded(t *testing.T) {
+	queue := NewWorkQueue(0)
+	assert.Nil(t, queue)
+	for i := 0; i < 100; i++ {
+		assert.True(t, queue.TryAcquire())
+	}
+	assert.NoError(t, queue.Acquire(context.Background()))
+	queue.Release()
+	assert.Equal(t, 0, queue.Depth())
+	assert.Equal(t, 0, queue.Capacity())
+}
+
+func TestSignalPoller_PausesWhileWorkQueueFull(t *testing.T) {
+	msgSvc := &mockMessageService{}
+	polled := make(chan struct{}, 1)
+	msgSvc.On("PollSignalMessages", mock.Anything).Run(func(mock.Arguments) { polled <- struct{}{} }).Return(nil)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	poller := NewSignalPoller(&mockSignalClient{}, msgSvc, models.SignalConfig{PollIntervalSec: 1, PollingEnabled: true},
+		models.RetryConfig{InitialBackoffMs: 10, MaxBackoffMs: 50, MaxAttempts: 1}, logger)
+	queue := NewWorkQueue(1)
+	poller.SetWorkQueue(queue)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	poller.ctx = ctx
+
+	require.True(t, queue.TryAcquire())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		poller.pollWithRetry()
+	}()
+
+	select {
+	case <-polled:
+		t.Fatal("polled while the work queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	queue.Release()
+	select {
+	case <-polled:
+	case <-time.After(time.Second):
+		t.Fatal("polling did not resume after the queue drained")
+	}
+	<-done
+	assert.Equal(t, 0, queue.Depth(), "the poll releases its slot")
+}
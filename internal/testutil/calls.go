@@ -0,0 +1,53 @@
+// Package testutil provides in-memory fakes of the WhatsApp (WAHA) and Signal clients for
+// tests that drive the bridge end to end.
+//
+// FakeWAHA and FakeSignal implement types.WAClient and signal.Client without any HTTP. They
+// record every call, return deterministic message IDs and timestamps, and can be told to fail
+// the next call to a method. This lets a test relay a WhatsApp message to Signal, reply to it
+// from Signal, and check where the reply lands in WhatsApp.
+package testutil
+
+import "sync"
+
+// callLog counts calls per method and hands out queued failures. The fakes embed it and hold
+// its lock while recording.
+type callLog struct {
+	mu       sync.Mutex
+	calls    map[string]int
+	failures map[string][]error
+}
+
+// record counts a call to method and returns the next queued failure for it, if any. The
+// caller must hold mu.
+func (c *callLog) record(method string) error {
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+	}
+	c.calls[method]++
+
+	queued := c.failures[method]
+	if len(queued) == 0 {
+		return nil
+	}
+	c.failures[method] = queued[1:]
+	return queued[0]
+}
+
+// FailNext makes the next call to method return err. Calling it several times queues several
+// failures, returned in order.
+func (c *callLog) FailNext(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failures == nil {
+		c.failures = make(map[string][]error)
+	}
+	c.failures[method] = append(c.failures[method], err)
+}
+
+// Calls returns how many times method has been called, including calls that failed
+func (c *callLog) Calls(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
@@ -0,0 +1,282 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"whatsignal/pkg/signal"
+	signaltypes "whatsignal/pkg/signal/types"
+)
+
+// fakeSignalStartTimestamp is the clock FakeSignal's timestamps count up from, in milliseconds
+const fakeSignalStartTimestamp int64 = 1700000000000
+
+// SignalSend records a message sent through FakeSignal
+type SignalSend struct {
+	Recipient   string
+	Message     string
+	Attachments []string
+	// Request is the request the send options built, with quote and text mode fields set
+	Request signaltypes.SendMessageRequest
+	// Timestamp and MessageID are what FakeSignal returned; like signal-cli, the ID is the
+	// timestamp in milliseconds
+	Timestamp int64
+	MessageID string
+}
+
+// SignalReaction records a reaction sent or removed through FakeSignal
+type SignalReaction struct {
+	Recipient       string
+	Emoji           string
+	TargetAuthor    string
+	TargetTimestamp int64
+	Remove          bool
+}
+
+// FakeSignal is an in-memory signal.Client. Sends are recorded and get increasing timestamps,
+// messages queued with Deliver are returned by the next ReceiveMessages, and attachments are
+// served from what the test adds.
+type FakeSignal struct {
+	callLog
+
+	number        string
+	mode          string
+	lastTimestamp int64
+	sends         []SignalSend
+	incoming      []signaltypes.SignalMessage
+	attachments   map[string][]byte
+	expirations   map[string]time.Duration
+	reactions     []SignalReaction
+}
+
+var _ signal.Client = (*FakeSignal)(nil)
+
+// NewFakeSignal returns a FakeSignal for the bridge's intermediary number
+func NewFakeSignal(number string) *FakeSignal {
+	return &FakeSignal{
+		number:        number,
+		mode:          "native",
+		lastTimestamp: fakeSignalStartTimestamp,
+		attachments:   make(map[string][]byte),
+		expirations:   make(map[string]time.Duration),
+	}
+}
+
+// SetMode sets what DetectedMode reports
+func (f *FakeSignal) SetMode(mode string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mode = mode
+}
+
+// SetAttachment makes an attachment available to DownloadAttachment and ListAttachments
+func (f *FakeSignal) SetAttachment(id string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attachments[id] = data
+}
+
+// Deliver queues messages for the next ReceiveMessages call
+func (f *FakeSignal) Deliver(msgs ...signaltypes.SignalMessage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.incoming = append(f.incoming, msgs...)
+}
+
+// Reply builds the message a Signal user sends when replying to sent. The reply comes from
+// the recipient of sent and quotes it, the way signal-cli reports a native reply.
+func (f *FakeSignal) Reply(sent SignalSend, text string) signaltypes.SignalMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	timestamp := f.nextTimestampLocked()
+	msg := signaltypes.SignalMessage{
+		Timestamp: timestamp,
+		Sender:    sent.Recipient,
+		MessageID: strconv.FormatInt(timestamp, 10),
+		Message:   text,
+	}
+	msg.QuotedMessage = &struct {
+		ID        string `json:"id"`
+		Author    string `json:"author"`
+		Text      string `json:"text"`
+		Timestamp int64  `json:"timestamp"`
+	}{
+		ID:        sent.MessageID,
+		Author:    f.number,
+		Text:      sent.Message,
+		Timestamp: sent.Timestamp,
+	}
+	return msg
+}
+
+// Sends returns the messages sent so far, oldest first
+func (f *FakeSignal) Sends() []SignalSend {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]SignalSend(nil), f.sends...)
+}
+
+// LastSend returns the most recent send, if there was one
+func (f *FakeSignal) LastSend() (SignalSend, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.sends) == 0 {
+		return SignalSend{}, false
+	}
+	return f.sends[len(f.sends)-1], true
+}
+
+// SendMessage implements signal.Client
+func (f *FakeSignal) SendMessage(ctx context.Context, recipient, message string, attachments []string, opts ...signaltypes.SendOption) (*signaltypes.SendMessageResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("SendMessage"); err != nil {
+		return nil, err
+	}
+
+	req := signaltypes.SendMessageRequest{
+		Message:    message,
+		Number:     f.number,
+		Recipients: []string{recipient},
+	}
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	timestamp := f.nextTimestampLocked()
+	sent := SignalSend{
+		Recipient:   recipient,
+		Message:     message,
+		Attachments: append([]string(nil), attachments...),
+		Request:     req,
+		Timestamp:   timestamp,
+		MessageID:   strconv.FormatInt(timestamp, 10),
+	}
+	f.sends = append(f.sends, sent)
+	return &signaltypes.SendMessageResponse{Timestamp: timestamp, MessageID: sent.MessageID}, nil
+}
+
+// ReceiveMessages implements signal.Client, returning and clearing the delivered messages
+func (f *FakeSignal) ReceiveMessages(ctx context.Context, timeoutSeconds int) ([]signaltypes.SignalMessage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("ReceiveMessages"); err != nil {
+		return nil, err
+	}
+	msgs := f.incoming
+	f.incoming = nil
+	return msgs, nil
+}
+
+// InitializeDevice implements signal.Client
+func (f *FakeSignal) InitializeDevice(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.record("InitializeDevice")
+}
+
+// DownloadAttachment implements signal.Client
+func (f *FakeSignal) DownloadAttachment(ctx context.Context, attachmentID string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("DownloadAttachment"); err != nil {
+		return nil, err
+	}
+	data, ok := f.attachments[attachmentID]
+	if !ok {
+		return nil, fmt.Errorf("attachment %s not found", attachmentID)
+	}
+	return data, nil
+}
+
+// ListAttachments implements signal.Client
+func (f *FakeSignal) ListAttachments(ctx context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("ListAttachments"); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(f.attachments))
+	for id := range f.attachments {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// SetExpiration implements signal.Client, recording the timer set for recipient
+func (f *FakeSignal) SetExpiration(ctx context.Context, recipient string, expiration time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("SetExpiration"); err != nil {
+		return err
+	}
+	f.expirations[recipient] = expiration
+	return nil
+}
+
+// SendReaction implements signal.Client, recording the reaction
+func (f *FakeSignal) SendReaction(ctx context.Context, recipient, emoji, targetAuthor string, targetTimestamp int64, remove bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("SendReaction"); err != nil {
+		return err
+	}
+	f.reactions = append(f.reactions, SignalReaction{
+		Recipient:       recipient,
+		Emoji:           emoji,
+		TargetAuthor:    targetAuthor,
+		TargetTimestamp: targetTimestamp,
+		Remove:          remove,
+	})
+	return nil
+}
+
+// GetContactProfile implements signal.Client; the fake knows no profiles
+func (f *FakeSignal) GetContactProfile(ctx context.Context, number string) (*signaltypes.ContactProfile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("GetContactProfile"); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// Reactions returns the reactions sent so far, oldest first
+func (f *FakeSignal) Reactions() []SignalReaction {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]SignalReaction(nil), f.reactions...)
+}
+
+// Expiration returns the disappearing-messages timer last set for recipient
+func (f *FakeSignal) Expiration(recipient string) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.expirations[recipient]
+}
+
+// DetectedMode implements signal.Client
+func (f *FakeSignal) DetectedMode() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mode
+}
+
+// nextTimestampLocked returns a timestamp later than any handed out before; the caller must
+// hold mu
+func (f *FakeSignal) nextTimestampLocked() int64 {
+	f.lastTimestamp += 1000
+	return f.lastTimestamp
+}
@@ -0,0 +1,445 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"whatsignal/pkg/whatsapp/types"
+)
+
+// WhatsAppSend records a message or reaction sent through FakeWAHA
+type WhatsAppSend struct {
+	// Method is the client method used, such as "SendTextWithSession"
+	Method  string
+	Session string
+	ChatID  string
+	// Text is the message text, the media caption or the reaction emoji
+	Text      string
+	MediaPath string
+	Mentions  []string
+	// ReplyTo is the quoted message ID, or the reacted message ID for reactions
+	ReplyTo string
+	// MessageID is the ID FakeWAHA returned for the send
+	MessageID string
+}
+
+// FakeWAHA is an in-memory types.WAClient. Sends are recorded and get sequential message IDs;
+// sessions report running unless set otherwise. Contacts, groups, chat labels and chat history
+// are served from what the test adds.
+type FakeWAHA struct {
+	callLog
+
+	sessionName  string
+	nextID       int
+	sends        []WhatsAppSend
+	deleted      []string
+	sessions     map[string]types.SessionStatus
+	contacts     map[string]types.Contact
+	groups       map[string]types.Group
+	labels       map[string][]types.Label
+	history      map[string][]types.ChatMessage
+	accounts     map[string]types.Me
+	capabilities types.Capabilities
+}
+
+var _ types.WAClient = (*FakeWAHA)(nil)
+
+// NewFakeWAHA returns a FakeWAHA whose default session is sessionName
+func NewFakeWAHA(sessionName string) *FakeWAHA {
+	return &FakeWAHA{
+		sessionName: sessionName,
+		sessions:    make(map[string]types.SessionStatus),
+		contacts:    make(map[string]types.Contact),
+		groups:      make(map[string]types.Group),
+		labels:      make(map[string][]types.Label),
+		history:     make(map[string][]types.ChatMessage),
+		accounts:    make(map[string]types.Me),
+	}
+}
+
+// SetSessionStatus sets the status reported for a session
+func (f *FakeWAHA) SetSessionStatus(sessionName string, status types.SessionStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions[sessionName] = status
+}
+
+// SetAccount sets the account GetMe reports for a session; sessions without one are not logged in
+func (f *FakeWAHA) SetAccount(sessionName string, me types.Me) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.accounts[sessionName] = me
+}
+
+// AddContact makes a contact available to GetContact and GetAllContacts
+func (f *FakeWAHA) AddContact(contact types.Contact) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.contacts[contact.ID] = contact
+}
+
+// AddGroup makes a group available to GetGroup and GetAllGroups
+func (f *FakeWAHA) AddGroup(group types.Group) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.groups[string(group.ID)] = group
+}
+
+// SetChatLabels sets the labels returned for a chat
+func (f *FakeWAHA) SetChatLabels(chatID string, labels []types.Label) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.labels[chatID] = labels
+}
+
+// SetChatMessages sets the history returned for a chat, newest first
+func (f *FakeWAHA) SetChatMessages(chatID string, messages []types.ChatMessage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.history[chatID] = messages
+}
+
+// SetCapabilities sets what GetCapabilities reports
+func (f *FakeWAHA) SetCapabilities(capabilities types.Capabilities) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.capabilities = capabilities
+}
+
+// Sends returns the messages and reactions sent so far, oldest first
+func (f *FakeWAHA) Sends() []WhatsAppSend {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]WhatsAppSend(nil), f.sends...)
+}
+
+// LastSend returns the most recent send, if there was one
+func (f *FakeWAHA) LastSend() (WhatsAppSend, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.sends) == 0 {
+		return WhatsAppSend{}, false
+	}
+	return f.sends[len(f.sends)-1], true
+}
+
+// Deleted returns the IDs of deleted messages, oldest first
+func (f *FakeWAHA) Deleted() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.deleted...)
+}
+
+// send records a send unless a failure is queued for its method
+func (f *FakeWAHA) send(rec WhatsAppSend) (*types.SendMessageResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(rec.Method); err != nil {
+		return nil, err
+	}
+	if rec.Session == "" {
+		rec.Session = f.sessionName
+	}
+	f.nextID++
+	rec.MessageID = fmt.Sprintf("fake_wa_%d", f.nextID)
+	f.sends = append(f.sends, rec)
+	return &types.SendMessageResponse{MessageID: rec.MessageID, Status: "sent"}, nil
+}
+
+// SendTextWithSession implements types.WAClient
+func (f *FakeWAHA) SendTextWithSession(ctx context.Context, chatID, message, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	return f.send(WhatsAppSend{Method: "SendTextWithSession", Session: sessionName, ChatID: chatID, Text: message, ReplyTo: replyTo})
+}
+
+// SendMentionsWithSession implements types.WAClient
+func (f *FakeWAHA) SendMentionsWithSession(ctx context.Context, chatID, message string, mentions []string, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	return f.send(WhatsAppSend{Method: "SendMentionsWithSession", Session: sessionName, ChatID: chatID, Text: message, Mentions: mentions, ReplyTo: replyTo})
+}
+
+// SendImageWithSession implements types.WAClient
+func (f *FakeWAHA) SendImageWithSession(ctx context.Context, chatID, imagePath, caption, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	return f.send(WhatsAppSend{Method: "SendImageWithSession", Session: sessionName, ChatID: chatID, Text: caption, MediaPath: imagePath, ReplyTo: replyTo})
+}
+
+// SendVideoWithSession implements types.WAClient
+func (f *FakeWAHA) SendVideoWithSession(ctx context.Context, chatID, videoPath, caption, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	return f.send(WhatsAppSend{Method: "SendVideoWithSession", Session: sessionName, ChatID: chatID, Text: caption, MediaPath: videoPath, ReplyTo: replyTo})
+}
+
+// SendDocumentWithSession implements types.WAClient
+func (f *FakeWAHA) SendDocumentWithSession(ctx context.Context, chatID, docPath, caption, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	return f.send(WhatsAppSend{Method: "SendDocumentWithSession", Session: sessionName, ChatID: chatID, Text: caption, MediaPath: docPath, ReplyTo: replyTo})
+}
+
+// SendVoiceWithSession implements types.WAClient
+func (f *FakeWAHA) SendVoiceWithSession(ctx context.Context, chatID, voicePath, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	return f.send(WhatsAppSend{Method: "SendVoiceWithSession", Session: sessionName, ChatID: chatID, MediaPath: voicePath, ReplyTo: replyTo})
+}
+
+// SendReactionWithSession implements types.WAClient
+func (f *FakeWAHA) SendReactionWithSession(ctx context.Context, chatID, messageID, reaction, sessionName string) (*types.SendMessageResponse, error) {
+	return f.send(WhatsAppSend{Method: "SendReactionWithSession", Session: sessionName, ChatID: chatID, Text: reaction, ReplyTo: messageID})
+}
+
+// DeleteMessage implements types.WAClient
+func (f *FakeWAHA) DeleteMessage(ctx context.Context, chatID, messageID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("DeleteMessage"); err != nil {
+		return err
+	}
+	f.deleted = append(f.deleted, messageID)
+	return nil
+}
+
+// EditMessageWithSession implements types.WAClient
+func (f *FakeWAHA) EditMessageWithSession(ctx context.Context, chatID, messageID, newText, sessionName string) error {
+	return f.simple("EditMessageWithSession")
+}
+
+// StarMessageWithSession implements types.WAClient
+func (f *FakeWAHA) StarMessageWithSession(ctx context.Context, chatID, messageID string, star bool, sessionName string) error {
+	return f.simple("StarMessageWithSession")
+}
+
+// CreateSession implements types.WAClient
+func (f *FakeWAHA) CreateSession(ctx context.Context, config *types.SessionConfig) error {
+	return f.simple("CreateSession")
+}
+
+// StartSession implements types.WAClient
+func (f *FakeWAHA) StartSession(ctx context.Context) error {
+	return f.setStatus("StartSession", f.sessionName, types.SessionStatusRunning)
+}
+
+// StopSession implements types.WAClient
+func (f *FakeWAHA) StopSession(ctx context.Context) error {
+	return f.setStatus("StopSession", f.sessionName, types.SessionStatusStopped)
+}
+
+// RestartSession implements types.WAClient
+func (f *FakeWAHA) RestartSession(ctx context.Context) error {
+	return f.setStatus("RestartSession", f.sessionName, types.SessionStatusRunning)
+}
+
+// RestartSessionByName implements types.WAClient
+func (f *FakeWAHA) RestartSessionByName(ctx context.Context, sessionName string) error {
+	return f.setStatus("RestartSessionByName", sessionName, types.SessionStatusRunning)
+}
+
+// GetSessionStatus implements types.WAClient
+func (f *FakeWAHA) GetSessionStatus(ctx context.Context) (*types.Session, error) {
+	return f.sessionStatus("GetSessionStatus", f.sessionName)
+}
+
+// GetSessionStatusByName implements types.WAClient
+func (f *FakeWAHA) GetSessionStatusByName(ctx context.Context, sessionName string) (*types.Session, error) {
+	return f.sessionStatus("GetSessionStatusByName", sessionName)
+}
+
+// GetMe implements types.WAClient
+func (f *FakeWAHA) GetMe(ctx context.Context, sessionName string) (*types.Me, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("GetMe"); err != nil {
+		return nil, err
+	}
+	me, ok := f.accounts[sessionName]
+	if !ok {
+		return nil, nil
+	}
+	return &me, nil
+}
+
+// WaitForSessionReady implements types.WAClient
+func (f *FakeWAHA) WaitForSessionReady(ctx context.Context, maxWaitTime time.Duration) error {
+	return f.waitReady("WaitForSessionReady", f.sessionName)
+}
+
+// WaitForSessionReadyByName implements types.WAClient
+func (f *FakeWAHA) WaitForSessionReadyByName(ctx context.Context, sessionName string, maxWaitTime time.Duration) error {
+	return f.waitReady("WaitForSessionReadyByName", sessionName)
+}
+
+// GetSessionName implements types.WAClient
+func (f *FakeWAHA) GetSessionName() string {
+	return f.sessionName
+}
+
+// GetContact implements types.WAClient. Unknown contacts return nil, like a 404 from WAHA.
+func (f *FakeWAHA) GetContact(ctx context.Context, contactID string) (*types.Contact, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetContact"); err != nil {
+		return nil, err
+	}
+	contact, ok := f.contacts[contactID]
+	if !ok {
+		return nil, nil
+	}
+	return &contact, nil
+}
+
+// GetAllContacts implements types.WAClient, listing contacts by ID
+func (f *FakeWAHA) GetAllContacts(ctx context.Context, limit, offset int) ([]types.Contact, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetAllContacts"); err != nil {
+		return nil, err
+	}
+	contacts := make([]types.Contact, 0, len(f.contacts))
+	for _, contact := range f.contacts {
+		contacts = append(contacts, contact)
+	}
+	sort.Slice(contacts, func(i, j int) bool { return contacts[i].ID < contacts[j].ID })
+	return page(contacts, limit, offset), nil
+}
+
+// GetGroup implements types.WAClient. Unknown groups return nil, like a 404 from WAHA.
+func (f *FakeWAHA) GetGroup(ctx context.Context, groupID string) (*types.Group, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetGroup"); err != nil {
+		return nil, err
+	}
+	group, ok := f.groups[groupID]
+	if !ok {
+		return nil, nil
+	}
+	return &group, nil
+}
+
+// GetGroupWithSession implements types.WAClient. The fake keeps one set of groups for all
+// sessions.
+func (f *FakeWAHA) GetGroupWithSession(ctx context.Context, groupID, sessionName string) (*types.Group, error) {
+	return f.GetGroup(ctx, groupID)
+}
+
+// GetAllGroups implements types.WAClient, listing groups by ID
+func (f *FakeWAHA) GetAllGroups(ctx context.Context, limit, offset int) ([]types.Group, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetAllGroups"); err != nil {
+		return nil, err
+	}
+	groups := make([]types.Group, 0, len(f.groups))
+	for _, group := range f.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return page(groups, limit, offset), nil
+}
+
+// GetChatLabels implements types.WAClient
+func (f *FakeWAHA) GetChatLabels(ctx context.Context, chatID string) ([]types.Label, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetChatLabels"); err != nil {
+		return nil, err
+	}
+	return f.labels[chatID], nil
+}
+
+// GetChatMessages implements types.WAClient
+func (f *FakeWAHA) GetChatMessages(ctx context.Context, chatID string, limit int, sessionName string) ([]types.ChatMessage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetChatMessages"); err != nil {
+		return nil, err
+	}
+	messages := f.history[chatID]
+	if limit > 0 && len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+// AckMessage implements types.WAClient
+func (f *FakeWAHA) AckMessage(ctx context.Context, chatID, sessionName string) error {
+	return f.simple("AckMessage")
+}
+
+// StartTyping implements types.WAClient
+func (f *FakeWAHA) StartTyping(ctx context.Context, chatID, sessionName string) error {
+	return f.simple("StartTyping")
+}
+
+// StopTyping implements types.WAClient
+func (f *FakeWAHA) StopTyping(ctx context.Context, chatID, sessionName string) error {
+	return f.simple("StopTyping")
+}
+
+// HealthCheck implements types.WAClient
+func (f *FakeWAHA) HealthCheck(ctx context.Context) error {
+	return f.simple("HealthCheck")
+}
+
+// GetCapabilities implements types.WAClient
+func (f *FakeWAHA) GetCapabilities(ctx context.Context) types.Capabilities {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_ = f.record("GetCapabilities")
+	return f.capabilities
+}
+
+// simple records a call that has no effect beyond its queued failure
+func (f *FakeWAHA) simple(method string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.record(method)
+}
+
+func (f *FakeWAHA) setStatus(method, sessionName string, status types.SessionStatus) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return err
+	}
+	f.sessions[sessionName] = status
+	return nil
+}
+
+func (f *FakeWAHA) sessionStatus(method, sessionName string) (*types.Session, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return nil, err
+	}
+	return &types.Session{Name: sessionName, Status: f.statusLocked(sessionName)}, nil
+}
+
+func (f *FakeWAHA) waitReady(method, sessionName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return err
+	}
+	if status := f.statusLocked(sessionName); status != types.SessionStatusRunning {
+		return fmt.Errorf("session %s is %s", sessionName, status)
+	}
+	return nil
+}
+
+// statusLocked returns a session's status, running unless set otherwise; the caller must hold mu
+func (f *FakeWAHA) statusLocked(sessionName string) types.SessionStatus {
+	if status, ok := f.sessions[sessionName]; ok {
+		return status
+	}
+	return types.SessionStatusRunning
+}
+
+// page applies limit and offset the way WAHA's list endpoints do; a limit of 0 means no limit
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,110 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	signaltypes "whatsignal/pkg/signal/types"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeWAHA_RecordsSendsAndQueuedFailures(t *testing.T) {
+	ctx := context.Background()
+	waha := NewFakeWAHA("personal")
+	errFirst, errSecond := errors.New("first"), errors.New("second")
+	waha.FailNext("SendTextWithSession", errFirst)
+	waha.FailNext("SendTextWithSession", errSecond)
+
+	_, err := waha.SendTextWithSession(ctx, "chat@c.us", "one", "", "")
+	assert.Equal(t, errFirst, err)
+	_, err = waha.SendTextWithSession(ctx, "chat@c.us", "two", "", "")
+	assert.Equal(t, errSecond, err)
+
+	resp, err := waha.SendTextWithSession(ctx, "chat@c.us", "three", "wamid.1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "fake_wa_1", resp.MessageID)
+	assert.Equal(t, 3, waha.Calls("SendTextWithSession"))
+	assert.Equal(t, []WhatsAppSend{{
+		Method:    "SendTextWithSession",
+		Session:   "personal",
+		ChatID:    "chat@c.us",
+		Text:      "three",
+		ReplyTo:   "wamid.1",
+		MessageID: "fake_wa_1",
+	}}, waha.Sends())
+}
+
+func TestFakeWAHA_Sessions(t *testing.T) {
+	ctx := context.Background()
+	waha := NewFakeWAHA("personal")
+
+	session, err := waha.GetSessionStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, types.SessionStatusRunning, session.Status)
+
+	waha.SetSessionStatus("personal", types.SessionStatusStopped)
+	assert.Error(t, waha.WaitForSessionReady(ctx, 0))
+	require.NoError(t, waha.RestartSessionByName(ctx, "personal"))
+	assert.NoError(t, waha.WaitForSessionReady(ctx, 0))
+}
+
+func TestFakeWAHA_ListsPageByID(t *testing.T) {
+	ctx := context.Background()
+	waha := NewFakeWAHA("personal")
+	for _, id := range []string{"c@c.us", "a@c.us", "b@c.us"} {
+		waha.AddContact(types.Contact{ID: id})
+	}
+
+	contacts, err := waha.GetAllContacts(ctx, 2, 1)
+	require.NoError(t, err)
+	require.Len(t, contacts, 2)
+	assert.Equal(t, "b@c.us", contacts[0].ID)
+	assert.Equal(t, "c@c.us", contacts[1].ID)
+
+	contact, err := waha.GetContact(ctx, "missing@c.us")
+	require.NoError(t, err)
+	assert.Nil(t, contact)
+}
+
+func TestFakeSignal_SendAndReply(t *testing.T) {
+	ctx := context.Background()
+	sig := NewFakeSignal("+1234567890")
+
+	resp, err := sig.SendMessage(ctx, "+1111111111", "hello", nil, signaltypes.WithQuote(42, "+1111111111", "earlier"))
+	require.NoError(t, err)
+	sent, ok := sig.LastSend()
+	require.True(t, ok)
+	assert.Equal(t, resp.MessageID, sent.MessageID)
+	assert.Equal(t, int64(42), sent.Request.QuoteTimestamp)
+
+	reply := sig.Reply(sent, "hi back")
+	assert.Equal(t, "+1111111111", reply.Sender)
+	assert.Greater(t, reply.Timestamp, sent.Timestamp)
+	require.NotNil(t, reply.QuotedMessage)
+	assert.Equal(t, sent.MessageID, reply.QuotedMessage.ID)
+
+	sig.Deliver(reply)
+	msgs, err := sig.ReceiveMessages(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	msgs, err = sig.ReceiveMessages(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	clock.Advance(90 * time.Minute)
+	assert.Equal(t, start.Add(90*time.Minute), clock.Now())
+
+	clock.Set(start)
+	assert.Equal(t, start, clock.Now())
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader is the HTTP header that carries a request ID to WAHA and signal-cli
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDLogField is the log field holding the request ID; it matches service.LogFieldRequestID
+const requestIDLogField = "request_id"
+
+// EnsureRequestID returns ctx unchanged if it already carries a request ID, and otherwise a
+// context with a newly generated one
+func EnsureRequestID(ctx context.Context) context.Context {
+	if GetRequestID(ctx) != "" {
+		return ctx
+	}
+	return WithRequestID(ctx, GenerateRequestID())
+}
+
+// requestIDTransport sets the request ID header on outgoing requests whose context carries one
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+// NewRequestIDTransport wraps base so that every request made with a context carrying a
+// request ID sends it in the X-Request-ID header. A nil base uses http.DefaultTransport.
+func NewRequestIDTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &requestIDTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestID := GetRequestID(req.Context())
+	if requestID == "" || req.Header.Get(RequestIDHeader) != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	clone := req.Clone(req.Context())
+	clone.Header.Set(RequestIDHeader, requestID)
+	return t.base.RoundTrip(clone)
+}
+
+// requestIDHook adds the request ID to log entries created with a context that carries one
+type requestIDHook struct{}
+
+// NewRequestIDHook returns a logrus hook that adds a request_id field to every entry logged
+// through logger.WithContext(ctx) when ctx carries a request ID
+func NewRequestIDHook() logrus.Hook {
+	return requestIDHook{}
+}
+
+// Levels implements logrus.Hook
+func (requestIDHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (requestIDHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	if _, ok := entry.Data[requestIDLogField]; ok {
+		return nil
+	}
+	if requestID := GetRequestID(entry.Context); requestID != "" {
+		entry.Data[requestIDLogField] = requestID
+	}
+	return nil
+}
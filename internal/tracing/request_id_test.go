@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestEnsureRequestID(t *testing.T) {
+	ctx := EnsureRequestID(context.Background())
+	requestID := GetRequestID(ctx)
+	if requestID == "" {
+		t.Fatal("Expected a generated request ID")
+	}
+
+	if got := GetRequestID(EnsureRequestID(ctx)); got != requestID {
+		t.Fatalf("Expected existing request ID %s to be kept, got %s", requestID, got)
+	}
+}
+
+func TestRequestIDTransport(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(RequestIDHeader))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRequestIDTransport(nil)}
+
+	ctx := WithRequestID(context.Background(), "req_abc")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if req.Header.Get(RequestIDHeader) != "" {
+		t.Fatal("Expected the caller's request to be left unmodified")
+	}
+
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if len(received) != 2 || received[0] != "req_abc" || received[1] != "" {
+		t.Fatalf("Expected headers [req_abc, \"\"], got %q", received)
+	}
+}
+
+func TestRequestIDHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(NewRequestIDHook())
+
+	ctx := WithRequestID(context.Background(), "req_abc")
+	logger.WithContext(ctx).Info("with context")
+	logger.Info("without context")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d", len(lines))
+	}
+
+	var withCtx, withoutCtx map[string]interface{}
+	if err := json.Unmarshal(lines[0], &withCtx); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(lines[1], &withoutCtx); err != nil {
+		t.Fatal(err)
+	}
+	if withCtx["request_id"] != "req_abc" {
+		t.Fatalf("Expected request_id req_abc, got %v", withCtx["request_id"])
+	}
+	if _, ok := withoutCtx["request_id"]; ok {
+		t.Fatal("Expected no request_id without a context")
+	}
+}
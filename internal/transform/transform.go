@@ -0,0 +1,89 @@
+// Package transform rewrites the text of relayed messages with the rules in the transforms
+// section of the configuration
+package transform
+
+import (
+	"fmt"
+	"regexp"
+
+	"whatsignal/internal/models"
+)
+
+// MaxRules caps the number of transform rules
+const MaxRules = 50
+
+type rule struct {
+	kind        string
+	direction   string
+	pattern     *regexp.Regexp
+	replacement string
+	text        string
+}
+
+// Pipeline applies compiled transform rules in their configured order. A nil Pipeline leaves
+// text unchanged.
+type Pipeline struct {
+	rules []rule
+}
+
+// Compile validates rules and compiles their patterns. It returns nil when there are no rules.
+func Compile(rules []models.TransformRule) (*Pipeline, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	if len(rules) > MaxRules {
+		return nil, fmt.Errorf("too many transform rules: %d (maximum %d)", len(rules), MaxRules)
+	}
+
+	p := &Pipeline{rules: make([]rule, 0, len(rules))}
+	for i, r := range rules {
+		switch r.Direction {
+		case "", models.TransformWhatsAppToSignal, models.TransformSignalToWhatsApp:
+		default:
+			return nil, fmt.Errorf("transform rule %d: invalid direction %q", i+1, r.Direction)
+		}
+
+		compiled := rule{kind: r.Type, direction: r.Direction, replacement: r.Replacement, text: r.Text}
+		switch r.Type {
+		case models.TransformReplace:
+			if r.Pattern == "" {
+				return nil, fmt.Errorf("transform rule %d: replace requires a pattern", i+1)
+			}
+			pattern, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("transform rule %d: invalid pattern: %w", i+1, err)
+			}
+			compiled.pattern = pattern
+		case models.TransformPrefix, models.TransformSuffix:
+			if r.Text == "" {
+				return nil, fmt.Errorf("transform rule %d: %s requires text", i+1, r.Type)
+			}
+		default:
+			return nil, fmt.Errorf("transform rule %d: invalid type %q: must be \"replace\", \"prefix\" or \"suffix\"", i+1, r.Type)
+		}
+		p.rules = append(p.rules, compiled)
+	}
+	return p, nil
+}
+
+// Apply runs the rules for direction over text. Empty text, such as media without a caption,
+// is left empty.
+func (p *Pipeline) Apply(direction, text string) string {
+	if p == nil || text == "" {
+		return text
+	}
+	for _, r := range p.rules {
+		if r.direction != "" && r.direction != direction {
+			continue
+		}
+		switch r.kind {
+		case models.TransformReplace:
+			text = r.pattern.ReplaceAllString(text, r.replacement)
+		case models.TransformPrefix:
+			text = r.text + text
+		case models.TransformSuffix:
+			text += r.text
+		}
+	}
+	return text
+}
@@ -0,0 +1,82 @@
+package transform
+
+import (
+	"testing"
+
+	"whatsignal/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline_RedactionThenSignature(t *testing.T) {
+	p, err := Compile([]models.TransformRule{
+		{Type: models.TransformReplace, Pattern: `(?i)\b(password|secret)\b`, Replacement: "[redacted]"},
+		{Type: models.TransformSuffix, Text: "\n-- sent via bridge", Direction: models.TransformSignalToWhatsApp},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "the [redacted] is [redacted]\n-- sent via bridge",
+		p.Apply(models.TransformSignalToWhatsApp, "the Password is secret"))
+	assert.Equal(t, "the [redacted] is [redacted]",
+		p.Apply(models.TransformWhatsAppToSignal, "the Password is secret"), "the signature only applies to Signal to WhatsApp")
+}
+
+func TestPipeline_RulesApplyInOrder(t *testing.T) {
+	p, err := Compile([]models.TransformRule{
+		{Type: models.TransformSuffix, Text: " secret"},
+		{Type: models.TransformReplace, Pattern: "secret", Replacement: "***"},
+		{Type: models.TransformPrefix, Text: "> "},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "> hello ***", p.Apply(models.TransformWhatsAppToSignal, "hello"), "later rules see the output of earlier ones")
+}
+
+func TestPipeline_ReplacementGroups(t *testing.T) {
+	p, err := Compile([]models.TransformRule{
+		{Type: models.TransformReplace, Pattern: `\+?(\d{3})\d{4,}(\d{2})`, Replacement: "$1…$2"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "call 555…89", p.Apply(models.TransformWhatsAppToSignal, "call +5551234589"))
+}
+
+func TestPipeline_EmptyTextIsUnchanged(t *testing.T) {
+	p, err := Compile([]models.TransformRule{{Type: models.TransformPrefix, Text: "[bridge] "}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "", p.Apply(models.TransformWhatsAppToSignal, ""))
+}
+
+func TestPipeline_NilIsNoop(t *testing.T) {
+	p, err := Compile(nil)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+	assert.Equal(t, "hello", p.Apply(models.TransformWhatsAppToSignal, "hello"))
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     models.TransformRule
+		contains string
+	}{
+		{"invalid regex", models.TransformRule{Type: models.TransformReplace, Pattern: "(unclosed"}, "transform rule 1: invalid pattern"},
+		{"replace without pattern", models.TransformRule{Type: models.TransformReplace}, "replace requires a pattern"},
+		{"prefix without text", models.TransformRule{Type: models.TransformPrefix}, "prefix requires text"},
+		{"unknown type", models.TransformRule{Type: "uppercase"}, `invalid type "uppercase"`},
+		{"unknown direction", models.TransformRule{Type: models.TransformSuffix, Text: "x", Direction: "both"}, `invalid direction "both"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile([]models.TransformRule{tt.rule})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.contains)
+		})
+	}
+
+	_, err := Compile(make([]models.TransformRule, MaxRules+1))
+	assert.ErrorContains(t, err, "too many transform rules")
+}
@@ -200,6 +200,34 @@ func ValidateStringLength(value, fieldName string, minLength, maxLength int) err
 	return nil
 }
 
+// ValidateHTTPHeader validates a configured HTTP header: the name must be an RFC 7230 token
+// and the value must not contain control characters, so it cannot split the request.
+func ValidateHTTPHeader(name, value string) error {
+	if name == "" {
+		return errors.New(errors.ErrCodeInvalidInput, "header name cannot be empty")
+	}
+	for _, r := range name {
+		if !isHeaderTokenRune(r) {
+			return errors.New(errors.ErrCodeInvalidInput,
+				fmt.Sprintf("invalid character %q in header name %q", r, name))
+		}
+	}
+	for _, r := range value {
+		if r != '\t' && unicode.IsControl(r) {
+			return errors.New(errors.ErrCodeInvalidInput,
+				fmt.Sprintf("header %q value contains control characters", name))
+		}
+	}
+	return nil
+}
+
+func isHeaderTokenRune(r rune) bool {
+	if r > unicode.MaxASCII {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+}
+
 // ValidateNumericRange validates numeric values against bounds
 func ValidateNumericRange(value int, fieldName string, min, max int) error {
 	if value < min {
@@ -505,6 +505,38 @@ func TestValidateStringLength(t *testing.T) {
 	}
 }
 
+func TestValidateHTTPHeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		value       string
+		expectError bool
+	}{
+		{name: "simple header", header: "X-Proxy-Token", value: "secret", expectError: false},
+		{name: "empty value", header: "X-Empty", value: "", expectError: false},
+		{name: "value with tab and spaces", header: "Accept", value: "image/*;\tq=0.8, */*", expectError: false},
+		{name: "token punctuation", header: "X_Custom.Header~1", value: "v", expectError: false},
+		{name: "empty name", header: "", value: "v", expectError: true},
+		{name: "name with space", header: "X Token", value: "v", expectError: true},
+		{name: "name with colon", header: "X-Token:", value: "v", expectError: true},
+		{name: "non-ASCII name", header: "X-Tökén", value: "v", expectError: true},
+		{name: "value with CRLF", header: "X-Token", value: "v\r\nHost: evil", expectError: true},
+		{name: "value with NUL", header: "X-Token", value: "v\x00", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHTTPHeader(tt.header, tt.value)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, string(errors.ErrCodeInvalidInput), string(errors.GetCode(err)))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidateNumericRange(t *testing.T) {
 	tests := []struct {
 		name        string
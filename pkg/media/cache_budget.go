@@ -0,0 +1,117 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"whatsignal/internal/metrics"
+)
+
+// cachedFile is a file of the media cache considered for eviction
+type cachedFile struct {
+	path     string
+	size     int64
+	lastUsed time.Time
+}
+
+// EnforceCacheBudget deletes the least recently used cached files until the cache is within
+// media.maxCacheSizeMB. A file counts as used when it was written or, with media.persistIndex,
+// last reused from the cache. Files being written, partial downloads and the cache index are
+// neither counted nor removed. It returns the number of bytes freed; without a budget it does
+// nothing.
+func (h *handler) EnforceCacheBudget() (int64, error) {
+	if h.config.MaxCacheSizeMB <= 0 {
+		return 0, nil
+	}
+	budget := int64(h.config.MaxCacheSizeMB) * 1024 * 1024
+
+	var lastAccess map[string]time.Time
+	if h.index != nil {
+		lastAccess = h.index.lastAccessByPath()
+	}
+	files, err := listCachedFiles(h.cacheDir, lastAccess, true)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+	if total <= budget {
+		return 0, nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].lastUsed.Before(files[j].lastUsed) })
+
+	var freed int64
+	var removed []string
+	for _, file := range files {
+		if total <= budget {
+			break
+		}
+		if removeErr := os.Remove(file.path); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			err = fmt.Errorf("failed to remove file over the cache budget: %w", removeErr)
+			break
+		}
+		total -= file.size
+		freed += file.size
+		removed = append(removed, file.path)
+	}
+
+	if len(removed) > 0 {
+		metrics.AddToCounter("media_cache_budget_evictions_total", float64(len(removed)), nil, "Cached media files deleted to keep the cache within media.maxCacheSizeMB")
+		if h.index != nil {
+			if forgetErr := h.index.forget(removed); forgetErr != nil && err == nil {
+				err = forgetErr
+			}
+		}
+	}
+	return freed, err
+}
+
+// listCachedFiles returns the cached media files in dir with their size and last use. In the
+// cache directory itself it also lists the session subdirectories.
+func listCachedFiles(dir string, lastAccess map[string]time.Time, withSessionDirs bool) ([]cachedFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var files []cachedFile
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if withSessionDirs {
+				sessionFiles, err := listCachedFiles(path, lastAccess, false)
+				if err != nil {
+					return nil, err
+				}
+				files = append(files, sessionFiles...)
+			}
+			continue
+		}
+		if isCacheWorkFile(entry.Name()) || strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get file info: %w", err)
+		}
+		used := info.ModTime()
+		if accessed, ok := lastAccess[path]; ok && accessed.After(used) {
+			used = accessed
+		}
+		files = append(files, cachedFile{path: path, size: info.Size(), lastUsed: used})
+	}
+	return files, nil
+}
@@ -0,0 +1,108 @@
+package media
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const budgetTestFileSize = 400 * 1024
+
+// writeBudgetFiles writes files of budgetTestFileSize bytes last modified hoursOld hours ago
+func writeBudgetFiles(t *testing.T, dir string, files map[string]int) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0750))
+	for name, hoursOld := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, make([]byte, budgetTestFileSize), 0644))
+		modTime := time.Now().Add(-time.Duration(hoursOld) * time.Hour)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+}
+
+func TestEnforceCacheBudget_RemovesLeastRecentlyUsedFirst(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	h := newSessionDirsTestHandler(t, cacheDir, false)
+	h.config.MaxCacheSizeMB = 1
+
+	// 2 MB in six files; the budget keeps the two most recently used
+	writeBudgetFiles(t, cacheDir, map[string]int{"oldest.jpg": 60, "newest.jpg": 1})
+	writeBudgetFiles(t, filepath.Join(cacheDir, "personal"), map[string]int{"old.jpg": 50, "recent.jpg": 2})
+	writeBudgetFiles(t, filepath.Join(cacheDir, "work"), map[string]int{"older.jpg": 40, "middle.jpg": 30})
+
+	freed, err := h.EnforceCacheBudget()
+	require.NoError(t, err)
+	assert.Equal(t, int64(4*budgetTestFileSize), freed)
+
+	for _, path := range []string{"oldest.jpg", "personal/old.jpg", "work/older.jpg", "work/middle.jpg"} {
+		assert.NoFileExists(t, filepath.Join(cacheDir, path))
+	}
+	for _, path := range []string{"newest.jpg", "personal/recent.jpg"} {
+		assert.FileExists(t, filepath.Join(cacheDir, path))
+	}
+
+	// Within budget now, nothing more to free
+	freed, err = h.EnforceCacheBudget()
+	require.NoError(t, err)
+	assert.Zero(t, freed)
+}
+
+func TestEnforceCacheBudget_KeepsFilesBeingWritten(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	h := newSessionDirsTestHandler(t, cacheDir, false)
+	h.config.MaxCacheSizeMB = 1
+
+	writeBudgetFiles(t, cacheDir, map[string]int{
+		"photo.jpg.tmp":    90,
+		"download_123.jpg": 90,
+		"partial_abc.part": 90,
+		"old.jpg":          80,
+		"kept.jpg":         1,
+		"another.jpg":      2,
+	})
+
+	freed, err := h.EnforceCacheBudget()
+	require.NoError(t, err)
+	assert.Equal(t, int64(budgetTestFileSize), freed)
+
+	assert.NoFileExists(t, filepath.Join(cacheDir, "old.jpg"))
+	for _, name := range []string{"photo.jpg.tmp", "download_123.jpg", "partial_abc.part", "kept.jpg", "another.jpg"} {
+		assert.FileExists(t, filepath.Join(cacheDir, name))
+	}
+}
+
+func TestEnforceCacheBudget_IndexLastAccess(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	h := newSessionDirsTestHandler(t, cacheDir, true)
+	h.config.MaxCacheSizeMB = 1
+
+	writeBudgetFiles(t, cacheDir, map[string]int{"reused.jpg": 70, "middle.jpg": 20, "newest.jpg": 1})
+	// Reused from the cache just now, so it outlives the files written after it
+	require.NoError(t, h.index.record("reused", filepath.Join(cacheDir, "reused.jpg"), budgetTestFileSize))
+
+	freed, err := h.EnforceCacheBudget()
+	require.NoError(t, err)
+	assert.Equal(t, int64(budgetTestFileSize), freed)
+
+	assert.NoFileExists(t, filepath.Join(cacheDir, "middle.jpg"))
+	assert.FileExists(t, filepath.Join(cacheDir, "reused.jpg"))
+	assert.FileExists(t, filepath.Join(cacheDir, "newest.jpg"))
+}
+
+func TestEnforceCacheBudget_Unlimited(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	h := newSessionDirsTestHandler(t, cacheDir, false)
+
+	writeBudgetFiles(t, cacheDir, map[string]int{"a.jpg": 90, "b.jpg": 80, "c.jpg": 70})
+
+	freed, err := h.EnforceCacheBudget()
+	require.NoError(t, err)
+	assert.Zero(t, freed)
+	for _, name := range []string{"a.jpg", "b.jpg", "c.jpg"} {
+		assert.FileExists(t, filepath.Join(cacheDir, name))
+	}
+}
@@ -0,0 +1,225 @@
+package media
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsignal/internal/constants"
+)
+
+// cacheIndexFileName is the index kept in the cache directory when media.persistIndex is on
+const cacheIndexFileName = "index.json"
+
+// cacheIndexEntry describes one cached media file
+type cacheIndexEntry struct {
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	LastAccess time.Time `json:"lastAccess"`
+}
+
+// cacheIndex maps content hashes to cached files so dedup lookups and cleanup do not have to
+// scan the cache directory. Entries whose file has gone missing are dropped when they are next
+// looked up or cleaned, so a stale index heals itself.
+type cacheIndex struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]cacheIndexEntry
+	now     func() time.Time
+}
+
+// loadCacheIndex reads the index from cacheDir. A missing or unreadable index is rebuilt from
+// a single scan of the directory.
+func loadCacheIndex(cacheDir string) (*cacheIndex, error) {
+	idx := &cacheIndex{
+		path:    filepath.Join(cacheDir, cacheIndexFileName),
+		entries: make(map[string]cacheIndexEntry),
+		now:     time.Now,
+	}
+
+	data, err := os.ReadFile(idx.path) // #nosec G304 - Path built from the configured cache directory
+	if err == nil && json.Unmarshal(data, &idx.entries) == nil {
+		return idx, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read media cache index: %w", err)
+	}
+
+	if err := idx.rebuild(cacheDir); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// rebuild replaces the entries with the files currently in cacheDir and its session
+// directories, using their modification time as the last access
+func (idx *cacheIndex) rebuild(cacheDir string) error {
+	dirEntries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.entries = make(map[string]cacheIndexEntry)
+	idx.addDirLocked(cacheDir, dirEntries)
+	for _, entry := range dirEntries {
+		if !entry.IsDir() {
+			continue
+		}
+		sessionDir := filepath.Join(cacheDir, entry.Name())
+		if sessionEntries, err := os.ReadDir(sessionDir); err == nil {
+			idx.addDirLocked(sessionDir, sessionEntries)
+		}
+	}
+	return idx.saveLocked()
+}
+
+// addDirLocked adds the files of dir to the entries; the caller must hold idx.mu
+func (idx *cacheIndex) addDirLocked(dir string, dirEntries []os.DirEntry) {
+	cacheDir := filepath.Dir(idx.path)
+	for _, entry := range dirEntries {
+		if entry.IsDir() || isCacheIndexFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		hash := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		idx.entries[indexKey(cacheDir, hash, path)] = cacheIndexEntry{
+			Path:       path,
+			Size:       info.Size(),
+			LastAccess: info.ModTime(),
+		}
+	}
+}
+
+// lookup reports whether the media keyed by hash is cached at path, refreshing its last access. An entry whose
+// file no longer exists is removed.
+func (idx *cacheIndex) lookup(hash, path string) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	entry, ok := idx.entries[hash]
+	if !ok || entry.Path != path {
+		return false
+	}
+	if _, err := os.Stat(path); err != nil {
+		delete(idx.entries, hash)
+		_ = idx.saveLocked()
+		return false
+	}
+
+	entry.LastAccess = idx.now()
+	idx.entries[hash] = entry
+	_ = idx.saveLocked()
+	return true
+}
+
+// record adds or refreshes the entry for a file just written to the cache
+func (idx *cacheIndex) record(hash, path string, size int64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.entries[hash] = cacheIndexEntry{Path: path, Size: size, LastAccess: idx.now()}
+	return idx.saveLocked()
+}
+
+// removeOlderThan deletes cached files not accessed within the age maxAge returns for their
+// path and drops their entries, along with entries whose file is already gone
+func (idx *cacheIndex) removeOlderThan(maxAge func(path string) time.Duration) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	now := idx.now()
+	for hash, entry := range idx.entries {
+		if now.Sub(entry.LastAccess) <= maxAge(entry.Path) {
+			if _, err := os.Stat(entry.Path); errors.Is(err, os.ErrNotExist) {
+				delete(idx.entries, hash)
+			}
+			continue
+		}
+		if err := os.Remove(entry.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			_ = idx.saveLocked()
+			return fmt.Errorf("failed to remove old file: %w", err)
+		}
+		delete(idx.entries, hash)
+	}
+	return idx.saveLocked()
+}
+
+// lastAccessByPath returns the last access of each indexed file by its path
+func (idx *cacheIndex) lastAccessByPath() map[string]time.Time {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	byPath := make(map[string]time.Time, len(idx.entries))
+	for _, entry := range idx.entries {
+		byPath[entry.Path] = entry.LastAccess
+	}
+	return byPath
+}
+
+// forget drops the entries of files deleted outside the index
+func (idx *cacheIndex) forget(paths []string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	gone := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		gone[path] = true
+	}
+	for key, entry := range idx.entries {
+		if gone[entry.Path] {
+			delete(idx.entries, key)
+		}
+	}
+	return idx.saveLocked()
+}
+
+// stats returns the number and total size of indexed files and the oldest last access
+func (idx *cacheIndex) stats() (int, int64, time.Time) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	var size int64
+	var oldest time.Time
+	for _, entry := range idx.entries {
+		size += entry.Size
+		if oldest.IsZero() || entry.LastAccess.Before(oldest) {
+			oldest = entry.LastAccess
+		}
+	}
+	return len(idx.entries), size, oldest
+}
+
+// saveLocked writes the index atomically; the caller must hold idx.mu
+func (idx *cacheIndex) saveLocked() error {
+	data, err := json.Marshal(idx.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode media cache index: %w", err)
+	}
+
+	tmpPath := idx.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, constants.DefaultFilePermissions); err != nil {
+		return fmt.Errorf("failed to write media cache index: %w", err)
+	}
+	if err := os.Rename(tmpPath, idx.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace media cache index: %w", err)
+	}
+	return nil
+}
+
+// isCacheIndexFile reports whether name is the index or its temporary file
+func isCacheIndexFile(name string) bool {
+	return name == cacheIndexFileName || name == cacheIndexFileName+".tmp"
+}
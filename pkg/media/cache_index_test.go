@@ -0,0 +1,133 @@
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIndexedTestHandler(t *testing.T, cacheDir string) *handler {
+	t.Helper()
+	config := getTestMediaConfig()
+	config.PersistIndex = true
+	h, err := NewHandler(cacheDir, config)
+	require.NoError(t, err)
+	return h.(*handler)
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCacheIndex_PersistsAcrossHandlers(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	content := []byte("indexed content")
+	sourcePath := filepath.Join(tmpDir, "source.jpg")
+	require.NoError(t, os.WriteFile(sourcePath, content, 0644))
+
+	first := newIndexedTestHandler(t, cacheDir)
+	cachedPath, err := first.ProcessMedia(context.Background(), sourcePath)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(cacheDir, cacheIndexFileName))
+
+	// A new handler knows the cached file from the index alone
+	second := newIndexedTestHandler(t, cacheDir)
+	hash := contentHash(content)
+	require.Contains(t, second.index.entries, hash)
+	assert.Equal(t, cachedPath, second.index.entries[hash].Path)
+	assert.Equal(t, int64(len(content)), second.index.entries[hash].Size)
+
+	again, err := second.ProcessMedia(context.Background(), sourcePath)
+	require.NoError(t, err)
+	assert.Equal(t, cachedPath, again)
+}
+
+func TestCacheIndex_RecoversFromDeletedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	content := []byte("content that goes missing")
+	sourcePath := filepath.Join(tmpDir, "source.jpg")
+	require.NoError(t, os.WriteFile(sourcePath, content, 0644))
+
+	first := newIndexedTestHandler(t, cacheDir)
+	cachedPath, err := first.ProcessMedia(context.Background(), sourcePath)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(cachedPath))
+
+	// The stale entry is dropped and the file cached again
+	second := newIndexedTestHandler(t, cacheDir)
+	recached, err := second.ProcessMedia(context.Background(), sourcePath)
+	require.NoError(t, err)
+	assert.Equal(t, cachedPath, recached)
+	data, err := os.ReadFile(recached)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	// Cleanup also drops entries whose file is gone
+	other := []byte("another file")
+	otherSource := filepath.Join(tmpDir, "other.jpg")
+	require.NoError(t, os.WriteFile(otherSource, other, 0644))
+	otherPath, err := second.ProcessMedia(context.Background(), otherSource)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(otherPath))
+	require.NoError(t, second.CleanupOldFiles(3600))
+	assert.NotContains(t, second.index.entries, contentHash(other))
+	assert.Contains(t, second.index.entries, contentHash(content))
+}
+
+func TestCacheIndex_RebuildsFromDirectory(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cacheDir, 0750))
+	existing := filepath.Join(cacheDir, "abc123.jpg")
+	require.NoError(t, os.WriteFile(existing, []byte("cached before the index"), 0644))
+
+	// No index yet: it is built from the directory
+	h := newIndexedTestHandler(t, cacheDir)
+	require.Contains(t, h.index.entries, "abc123")
+	assert.Equal(t, existing, h.index.entries["abc123"].Path)
+
+	// A corrupt index is rebuilt as well
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, cacheIndexFileName), []byte("{not json"), 0600))
+	h = newIndexedTestHandler(t, cacheDir)
+	assert.Len(t, h.index.entries, 1)
+	assert.Contains(t, h.index.entries, "abc123")
+}
+
+func TestCacheIndex_CleanupUsesLastAccess(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	require.NoError(t, os.MkdirAll(cacheDir, 0750))
+
+	// Both files were written long ago
+	oldTime := time.Now().Add(-8 * 24 * time.Hour)
+	content := []byte("reused content")
+	reused := filepath.Join(cacheDir, contentHash(content)+".jpg")
+	unused := filepath.Join(cacheDir, contentHash([]byte("unused"))+".jpg")
+	for _, path := range []string{reused, unused} {
+		require.NoError(t, os.WriteFile(path, content, 0644))
+		require.NoError(t, os.Chtimes(path, oldTime, oldTime))
+	}
+
+	h := newIndexedTestHandler(t, cacheDir)
+
+	// Relaying the same content again counts as an access
+	sourcePath := filepath.Join(tmpDir, "source.jpg")
+	require.NoError(t, os.WriteFile(sourcePath, content, 0644))
+	cachedPath, err := h.ProcessMedia(context.Background(), sourcePath)
+	require.NoError(t, err)
+	require.Equal(t, reused, cachedPath)
+
+	require.NoError(t, h.CleanupOldFiles(7*24*60*60))
+	assert.FileExists(t, reused)
+	assert.NoFileExists(t, unused)
+	assert.FileExists(t, filepath.Join(cacheDir, cacheIndexFileName))
+}
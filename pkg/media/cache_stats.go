@@ -0,0 +1,69 @@
+package media
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheStats summarises the media cache
+type CacheStats struct {
+	Files         int
+	SizeBytes     int64
+	OldestFileAge time.Duration // Zero when the cache is empty
+	Hits          int64         // ProcessMedia calls served from the cache since startup
+	Misses        int64         // ProcessMedia calls that stored a new file since startup
+	HitRate       float64       // Hits / (Hits + Misses), or 0 before the first lookup
+}
+
+// CacheStats reports the size of the media cache and how often ProcessMedia found media already
+// cached. With media.persistIndex the index is used and file age is measured from the last
+// access, as cleanup does; otherwise the cache directory and its session directories are
+// scanned and age is measured from the modification time.
+func (h *handler) CacheStats() (CacheStats, error) {
+	var stats CacheStats
+	var oldest time.Time
+	if h.index != nil {
+		stats.Files, stats.SizeBytes, oldest = h.index.stats()
+	} else {
+		entries, err := os.ReadDir(h.cacheDir)
+		if err != nil {
+			return CacheStats{}, fmt.Errorf("failed to read cache directory: %w", err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			if sessionEntries, err := os.ReadDir(filepath.Join(h.cacheDir, entry.Name())); err == nil {
+				entries = append(entries, sessionEntries...)
+			}
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || isCacheIndexFile(name) || strings.HasPrefix(name, partialDownloadPrefix) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			stats.Files++
+			stats.SizeBytes += info.Size()
+			if oldest.IsZero() || info.ModTime().Before(oldest) {
+				oldest = info.ModTime()
+			}
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestFileAge = max(time.Since(oldest), 0)
+	}
+
+	stats.Hits = h.cacheHits.Load()
+	stats.Misses = h.cacheMisses.Load()
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return stats, nil
+}
@@ -0,0 +1,87 @@
+package media
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedCache writes files of the given sizes to cacheDir, the first one modified an hour ago
+func seedCache(t *testing.T, cacheDir string, sizes ...int) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(cacheDir, 0755))
+	for i, size := range sizes {
+		path := filepath.Join(cacheDir, string(rune('a'+i))+".jpg")
+		require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+		if i == 0 {
+			old := time.Now().Add(-time.Hour)
+			require.NoError(t, os.Chtimes(path, old, old))
+		}
+	}
+}
+
+func TestCacheStats_ScansCacheDirectory(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	seedCache(t, cacheDir, 100, 200, 300)
+	h, err := NewHandler(cacheDir, getTestMediaConfig())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, partialDownloadPrefix+"abc"+partialDownloadSuffix), make([]byte, 50), 0600))
+
+	stats, err := h.CacheStats()
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Files, "partial downloads are not counted")
+	assert.Equal(t, int64(600), stats.SizeBytes)
+	assert.InDelta(t, time.Hour.Seconds(), stats.OldestFileAge.Seconds(), 60)
+	assert.Zero(t, stats.HitRate, "no lookups yet")
+}
+
+func TestCacheStats_UsesIndex(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	seedCache(t, cacheDir, 100, 200)
+	h := newIndexedTestHandler(t, cacheDir)
+
+	// A file the index does not know about is not counted
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "unindexed.jpg"), make([]byte, 1000), 0644))
+
+	stats, err := h.CacheStats()
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Files)
+	assert.Equal(t, int64(300), stats.SizeBytes)
+	assert.InDelta(t, time.Hour.Seconds(), stats.OldestFileAge.Seconds(), 60)
+}
+
+func TestCacheStats_EmptyCache(t *testing.T) {
+	h, _, cleanup := setupTestHandler(t)
+	defer cleanup()
+
+	stats, err := h.CacheStats()
+	require.NoError(t, err)
+	assert.Equal(t, CacheStats{}, stats)
+}
+
+func TestCacheStats_CountsHitsAndMisses(t *testing.T) {
+	h, tmpDir, cleanup := setupTestHandler(t)
+	defer cleanup()
+
+	first := filepath.Join(tmpDir, "first.jpg")
+	second := filepath.Join(tmpDir, "second.jpg")
+	require.NoError(t, os.WriteFile(first, []byte("first image"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("second image"), 0644))
+
+	for _, path := range []string{first, first, second, first} {
+		_, err := h.ProcessMedia(context.Background(), path)
+		require.NoError(t, err)
+	}
+
+	stats, err := h.CacheStats()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.Equal(t, 0.5, stats.HitRate)
+	assert.Equal(t, 2, stats.Files)
+}
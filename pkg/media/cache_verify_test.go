@@ -0,0 +1,108 @@
+package media
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessMedia_VerifyCacheReplacesCorruptedFile(t *testing.T) {
+	content := []byte("complete image content")
+
+	tests := []struct {
+		name         string
+		persistIndex bool
+	}{
+		{name: "directory scan"},
+		{name: "cache index", persistIndex: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetches atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fetches.Add(1)
+				w.Header().Set("Content-Type", "image/jpeg")
+				_, _ = w.Write(content)
+			}))
+			defer server.Close()
+
+			config := getTestMediaConfig()
+			config.PersistIndex = tt.persistIndex
+			config.VerifyCache = true
+			hi, err := NewHandler(filepath.Join(t.TempDir(), "cache"), config)
+			require.NoError(t, err)
+			h := hi.(*handler)
+			h.wahaBaseURL = server.URL
+
+			cachedPath, err := h.ProcessMedia(context.Background(), server.URL+"/image.jpg")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(filepath.Base(cachedPath), contentHash(content)+"."), cachedPath)
+
+			// Simulate an interrupted write leaving a truncated file behind
+			require.NoError(t, os.WriteFile(cachedPath, content[:5], 0644))
+
+			again, err := h.ProcessMedia(context.Background(), server.URL+"/image.jpg")
+			require.NoError(t, err)
+			assert.Equal(t, cachedPath, again)
+			assert.Equal(t, int32(2), fetches.Load())
+
+			data, err := os.ReadFile(again)
+			require.NoError(t, err)
+			assert.Equal(t, content, data)
+		})
+	}
+}
+
+func TestProcessMedia_VerifyCacheLocalFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := filepath.Join(tmpDir, "photo.jpg")
+	content := []byte("local image content")
+	require.NoError(t, os.WriteFile(source, content, 0644))
+
+	config := getTestMediaConfig()
+	config.VerifyCache = true
+	h, err := NewHandler(filepath.Join(tmpDir, "cache"), config)
+	require.NoError(t, err)
+
+	cachedPath, err := h.ProcessMedia(context.Background(), source)
+	require.NoError(t, err)
+
+	// Replace the cached copy with a corrupted file rather than writing through a hard link
+	require.NoError(t, os.Remove(cachedPath))
+	require.NoError(t, os.WriteFile(cachedPath, []byte("garbage"), 0644))
+
+	again, err := h.ProcessMedia(context.Background(), source)
+	require.NoError(t, err)
+	data, err := os.ReadFile(again)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+}
+
+func TestProcessMedia_WithoutVerifyCacheServesCachedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := filepath.Join(tmpDir, "photo.jpg")
+	require.NoError(t, os.WriteFile(source, []byte("local image content"), 0644))
+
+	h, err := NewHandler(filepath.Join(tmpDir, "cache"), getTestMediaConfig())
+	require.NoError(t, err)
+
+	cachedPath, err := h.ProcessMedia(context.Background(), source)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(cachedPath))
+	require.NoError(t, os.WriteFile(cachedPath, []byte("garbage"), 0644))
+
+	again, err := h.ProcessMedia(context.Background(), source)
+	require.NoError(t, err)
+	data, err := os.ReadFile(again)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("garbage"), data)
+}
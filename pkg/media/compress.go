@@ -0,0 +1,81 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsignal/internal/constants"
+)
+
+// Compressor shrinks a media file so that it fits within maxBytes
+type Compressor interface {
+	// Compress writes a smaller copy of path and returns its location. The caller removes
+	// the copy when done with it.
+	Compress(ctx context.Context, path string, maxBytes int64) (string, error)
+}
+
+// commandCompressor runs an external tool, such as ffmpeg or ImageMagick, to shrink media
+type commandCompressor struct {
+	command []string
+	timeout time.Duration
+}
+
+// NewCommandCompressor returns a Compressor that runs command for each file. The arguments may
+// use {input} for the file to shrink, {output} for where to write the result, and {maxBytes}
+// for the size it must fit in. The output keeps the input's extension.
+func NewCommandCompressor(command []string) Compressor {
+	return &commandCompressor{
+		command: command,
+		timeout: time.Duration(constants.DefaultMediaCompressTimeoutSec) * time.Second,
+	}
+}
+
+func (c *commandCompressor) Compress(ctx context.Context, path string, maxBytes int64) (string, error) {
+	if len(c.command) == 0 {
+		return "", fmt.Errorf("no compress command configured")
+	}
+
+	output, err := os.CreateTemp("", "whatsignal-compress-*"+filepath.Ext(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to create compress output file: %w", err)
+	}
+	outputPath := output.Name()
+	_ = output.Close()
+
+	replacer := strings.NewReplacer(
+		"{input}", path,
+		"{output}", outputPath,
+		"{maxBytes}", strconv.FormatInt(maxBytes, 10),
+	)
+	args := make([]string, len(c.command))
+	for i, arg := range c.command {
+		args[i] = replacer.Replace(arg)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// #nosec G204 - Command comes from trusted application config
+	if out, err := exec.CommandContext(runCtx, args[0], args[1:]...).CombinedOutput(); err != nil {
+		_ = os.Remove(outputPath)
+		return "", fmt.Errorf("compress command failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		_ = os.Remove(outputPath)
+		return "", fmt.Errorf("failed to read compressed file: %w", err)
+	}
+	if info.Size() == 0 || info.Size() > maxBytes {
+		_ = os.Remove(outputPath)
+		return "", fmt.Errorf("compressed file is %d bytes, limit is %d", info.Size(), maxBytes)
+	}
+
+	return outputPath, nil
+}
@@ -0,0 +1,42 @@
+package media
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandCompressor(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "big.jpg")
+	require.NoError(t, os.WriteFile(input, []byte("0123456789"), 0600))
+
+	t.Run("writes output within limit", func(t *testing.T) {
+		c := NewCommandCompressor([]string{"sh", "-c", "head -c 4 \"$0\" > \"$1\"", "{input}", "{output}"})
+		output, err := c.Compress(context.Background(), input, 5)
+		require.NoError(t, err)
+		defer func() { _ = os.Remove(output) }()
+
+		assert.Equal(t, ".jpg", filepath.Ext(output))
+		data, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, "0123", string(data))
+	})
+
+	t.Run("rejects output over limit", func(t *testing.T) {
+		c := NewCommandCompressor([]string{"sh", "-c", "cp \"$0\" \"$1\"", "{input}", "{output}"})
+		_, err := c.Compress(context.Background(), input, 5)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "limit is 5")
+	})
+
+	t.Run("reports command failure", func(t *testing.T) {
+		c := NewCommandCompressor([]string{"sh", "-c", "echo nope >&2; exit 1"})
+		_, err := c.Compress(context.Background(), input, 5)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nope")
+	})
+}
@@ -0,0 +1,136 @@
+package media
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/media"
+	"whatsignal/internal/models"
+	"whatsignal/internal/security"
+)
+
+// defaultExtensionDetectionOrder is used when media.extensionDetectionOrder is empty
+var defaultExtensionDetectionOrder = []string{models.MediaExtensionFromContentType, models.MediaExtensionFromURL}
+
+// genericContentTypes say nothing about a file's type; CDNs send them for anything
+var genericContentTypes = map[string]bool{
+	"application/octet-stream": true,
+	"binary/octet-stream":      true,
+	"application/binary":       true,
+	"application/unknown":      true,
+}
+
+// extensionDetectionOrder returns the sources tried for the type of a download: the configured
+// order, or the default, with the content signature appended when it is not listed
+func (h *handler) extensionDetectionOrder() []string {
+	order := h.config.ExtensionDetectionOrder
+	if len(order) == 0 {
+		order = defaultExtensionDetectionOrder
+	}
+	for _, source := range order {
+		if source == models.MediaExtensionFromSignature {
+			return order
+		}
+	}
+	return append(append([]string(nil), order...), models.MediaExtensionFromSignature)
+}
+
+// getFileExtensionFromResponse returns the extension, with its dot, for a file downloaded from
+// mediaURL. The start of the body is peeked for the content signature without consuming it.
+func (h *handler) getFileExtensionFromResponse(resp *http.Response, mediaURL string) string {
+	return h.detectExtension(resp.Header.Get("Content-Type"), mediaURL, peekBody(resp))
+}
+
+// detectExtension tries each source of the detection order until one names a type, and returns
+// its extension with the dot, or ".bin" when none does. head is the start of the file, or nil
+// when it is not available.
+func (h *handler) detectExtension(contentType, mediaURL string, head []byte) string {
+	for _, source := range h.extensionDetectionOrder() {
+		var ext string
+		switch source {
+		case models.MediaExtensionFromContentType:
+			ext = extensionFromContentType(contentType)
+		case models.MediaExtensionFromURL:
+			ext = extensionFromURL(mediaURL)
+		case models.MediaExtensionFromSignature:
+			if len(head) > 0 {
+				ext = media.ExtensionFromContent(head)
+			}
+		}
+		if ext != "" {
+			return "." + strings.TrimPrefix(ext, ".")
+		}
+	}
+
+	// Default extension for unknown types
+	return ".bin"
+}
+
+// extensionFromContentType returns an extension registered for a Content-Type, or "" for a
+// missing, generic or unknown one
+func extensionFromContentType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && genericContentTypes[mediaType] {
+		return ""
+	}
+	if exts, err := mime.ExtensionsByType(contentType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// extensionFromURL returns the extension of a URL's path, ignoring its query
+func extensionFromURL(mediaURL string) string {
+	if u, err := url.Parse(mediaURL); err == nil && u.Path != "" {
+		return filepath.Ext(u.Path)
+	}
+	return filepath.Ext(mediaURL)
+}
+
+// peekBody returns up to MimeDetectionBufferSize bytes from the start of a response body and
+// puts them back, or nil when there is no body
+func peekBody(resp *http.Response) []byte {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+	br := bufio.NewReaderSize(resp.Body, constants.MimeDetectionBufferSize)
+	head, _ := br.Peek(constants.MimeDetectionBufferSize)
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, resp.Body}
+	return head
+}
+
+// detectFileTypeFromContent returns the extension, without its dot, for the content of a local
+// file, or "" when its type is not recognised
+func (h *handler) detectFileTypeFromContent(path string) (string, error) {
+	// Validate file path to prevent directory traversal
+	if err := security.ValidateFilePath(path); err != nil {
+		return "", fmt.Errorf("invalid file path for content detection: %w", err)
+	}
+
+	file, err := os.Open(path) // #nosec G304 - Path validated by security.ValidateFilePath above
+	if err != nil {
+		return "", fmt.Errorf("failed to open file for content detection: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	// Read first 512 bytes for content type detection
+	buffer := make([]byte, constants.MimeDetectionBufferSize)
+	n, err := file.Read(buffer)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read file content: %w", err)
+	}
+	return media.ExtensionFromContent(buffer[:n]), nil
+}
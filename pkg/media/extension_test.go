@@ -0,0 +1,103 @@
+package media
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"whatsignal/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jpegHead is the start of a JPEG file, enough for its signature
+var jpegHead = []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00}
+
+func TestDetectExtension(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       []string
+		contentType string
+		url         string
+		head        []byte
+		expected    string
+	}{
+		{name: "content type first by default", contentType: "image/png", url: "http://example.com/a.pdf", head: jpegHead, expected: ".png"},
+		{name: "url when the content type is unknown", contentType: "", url: "http://example.com/a.pdf?token=x", head: jpegHead, expected: ".pdf"},
+		{name: "generic content type is skipped", contentType: "application/octet-stream", url: "http://example.com/a.pdf", expected: ".pdf"},
+		{name: "signature as last resort", contentType: "application/octet-stream; charset=binary", url: "http://example.com/download", head: jpegHead, expected: ".jpg"},
+		{name: "bin when nothing is known", url: "http://example.com/download", head: []byte("plain text"), expected: ".bin"},
+		{
+			name:        "configured order",
+			order:       []string{models.MediaExtensionFromURL, models.MediaExtensionFromContentType},
+			contentType: "image/png",
+			url:         "http://example.com/a.pdf",
+			expected:    ".pdf",
+		},
+		{
+			name:        "signature first",
+			order:       []string{models.MediaExtensionFromSignature, models.MediaExtensionFromContentType},
+			contentType: "text/plain",
+			url:         "http://example.com/a.txt",
+			head:        jpegHead,
+			expected:    ".jpg",
+		},
+		{
+			name:     "signature appended to a configured order",
+			order:    []string{models.MediaExtensionFromContentType},
+			url:      "http://example.com/a.pdf",
+			head:     jpegHead,
+			expected: ".jpg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := getTestMediaConfig()
+			config.ExtensionDetectionOrder = tt.order
+			h := &handler{config: config}
+			assert.Equal(t, tt.expected, h.detectExtension(tt.contentType, tt.url, tt.head))
+		})
+	}
+}
+
+func TestProcessMediaFromURL_WrongContentType(t *testing.T) {
+	content := append(append([]byte{}, jpegHead...), []byte(strings.Repeat("x", 1024))...)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A CDN labelling every file as text
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	newHandler := func(t *testing.T, order []string) Handler {
+		t.Helper()
+		config := getTestMediaConfig()
+		config.ExtensionDetectionOrder = order
+		h, err := NewHandler(filepath.Join(t.TempDir(), "cache"), config)
+		require.NoError(t, err)
+		h.(*handler).wahaBaseURL = server.URL
+		return h
+	}
+
+	t.Run("content type first names it after the header", func(t *testing.T) {
+		cachedPath, err := newHandler(t, nil).ProcessMedia(context.Background(), server.URL+"/download")
+		require.NoError(t, err)
+		// The extension registered for text/plain varies with the system's MIME table
+		assert.NotEqual(t, ".jpg", filepath.Ext(cachedPath))
+	})
+
+	t.Run("signature first wins", func(t *testing.T) {
+		cachedPath, err := newHandler(t, []string{models.MediaExtensionFromSignature, models.MediaExtensionFromContentType, models.MediaExtensionFromURL}).ProcessMedia(context.Background(), server.URL+"/download")
+		require.NoError(t, err)
+		assert.Equal(t, ".jpg", filepath.Ext(cachedPath))
+		cached, err := os.ReadFile(cachedPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, cached, "peeking at the signature keeps the whole body")
+	})
+}
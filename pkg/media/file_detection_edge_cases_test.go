@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"whatsignal/internal/media"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -166,8 +168,7 @@ func TestDetectByFileSignature_EdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			h := &handler{}
-			format := h.detectByFileSignature(tt.content)
+			format := media.ExtensionFromSignature(tt.content)
 			assert.Equal(t, tt.expectedFormat, format, "format mismatch for %s", tt.name)
 		})
 	}
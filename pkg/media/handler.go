@@ -5,23 +5,41 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"io"
-	"mime"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 	"whatsignal/internal/constants"
 	"whatsignal/internal/media"
+	"whatsignal/internal/metrics"
 	"whatsignal/internal/models"
 	"whatsignal/internal/security"
 )
 
 type Handler interface {
-	ProcessMedia(path string) (string, error)
+	ProcessMedia(ctx context.Context, path string) (string, error)
+	ProcessMediaForSession(ctx context.Context, path, sessionName string) (string, error)
+	ProcessMediaWithConfig(ctx context.Context, path, sessionName string, config models.MediaConfig) (string, error)
 	CleanupOldFiles(maxAge int64) error
+	RemoveUnreferencedFiles(referenced []string, gracePeriod time.Duration) (int, error)
+	EnforceCacheBudget() (int64, error)
+	CacheStats() (CacheStats, error)
+}
+
+// OversizeError is returned by ProcessMedia for media larger than the limit for its type
+type OversizeError struct {
+	MediaType string
+	Size      int64
+	Limit     int64
+}
+
+func (e *OversizeError) Error() string {
+	return fmt.Sprintf("%s too large: %d > %d bytes", e.MediaType, e.Size, e.Limit)
 }
 
 type handler struct {
@@ -32,6 +50,22 @@ type handler struct {
 	wahaBaseURL  string // For URL rewriting
 	wahaAPIKey   string // For WAHA authentication
 	signalRPCURL string // For Signal-CLI service validation
+
+	// downloadSlots bounds concurrent URL downloads; inFlight counts the ones running
+	downloadSlots chan struct{}
+	inFlight      atomic.Int64
+
+	// index is the persisted cache index; nil unless media.persistIndex is set
+	index *cacheIndex
+
+	// cacheHits and cacheMisses count isCached lookups for CacheStats
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
+
+	// partials holds the partial download files in use, so two downloads of one URL never
+	// write the same file
+	partialsMu sync.Mutex
+	partials   map[string]bool
 }
 
 func NewHandler(cacheDir string, config models.MediaConfig) (Handler, error) {
@@ -53,13 +87,27 @@ func NewHandlerWithServices(cacheDir string, config models.MediaConfig, wahaBase
 		downloadTimeout = constants.DefaultMediaDownloadTimeoutSec
 	}
 
+	maxDownloads := config.MaxConcurrentDownloads
+	if maxDownloads <= 0 {
+		maxDownloads = constants.DefaultMaxConcurrentDownloads
+	}
+
 	h := &handler{
-		cacheDir:     cacheDir,
-		config:       config,
-		mediaRouter:  media.NewRouter(config),
-		wahaBaseURL:  wahaBaseURL,
-		wahaAPIKey:   wahaAPIKey,
-		signalRPCURL: signalRPCURL,
+		cacheDir:      cacheDir,
+		config:        config,
+		mediaRouter:   media.NewRouter(config),
+		wahaBaseURL:   wahaBaseURL,
+		wahaAPIKey:    wahaAPIKey,
+		signalRPCURL:  signalRPCURL,
+		downloadSlots: make(chan struct{}, maxDownloads),
+	}
+
+	if config.PersistIndex {
+		index, err := loadCacheIndex(cacheDir)
+		if err != nil {
+			return nil, err
+		}
+		h.index = index
 	}
 
 	h.httpClient = &http.Client{
@@ -75,20 +123,26 @@ func NewHandlerWithServices(cacheDir string, config models.MediaConfig, wahaBase
 	return h, nil
 }
 
-func (h *handler) ProcessMedia(pathOrURL string) (string, error) {
+func (h *handler) ProcessMedia(ctx context.Context, pathOrURL string) (string, error) {
+	return h.processMedia(ctx, pathOrURL, h.cacheDir, h.mediaRouter)
+}
+
+// processMedia stores the media at pathOrURL in dir, the cache directory or a session's
+// subdirectory of it. router decides the media's type and size limit.
+func (h *handler) processMedia(ctx context.Context, pathOrURL, dir string, router media.Router) (string, error) {
 	// Check if input is a URL
 	if isURL(pathOrURL) {
-		return h.processMediaFromURL(pathOrURL)
+		return h.processMediaFromURL(ctx, pathOrURL, dir, router)
 	}
 	if u, err := url.Parse(pathOrURL); err == nil && u.Scheme != "" {
 		return "", fmt.Errorf("unsupported media URL scheme: %s", u.Scheme)
 	}
 
 	// Process as local file path
-	return h.processMediaFromFile(pathOrURL)
+	return h.processMediaFromFile(pathOrURL, dir, router)
 }
 
-func (h *handler) processMediaFromURL(mediaURL string) (string, error) {
+func (h *handler) processMediaFromURL(ctx context.Context, mediaURL, dir string, router media.Router) (string, error) {
 	// Rewrite localhost URLs to use the correct WAHA host
 	rewrittenURL := h.rewriteMediaURL(mediaURL)
 
@@ -96,8 +150,15 @@ func (h *handler) processMediaFromURL(mediaURL string) (string, error) {
 		return "", err
 	}
 
+	// Wait for a download slot; waiting ends with ctx and is bounded by the download timeout
+	release, err := h.acquireDownloadSlot(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to download media from URL: %w", err)
+	}
+	defer release()
+
 	// Download the file from URL
-	tempPath, ext, err := h.downloadFromURL(rewrittenURL)
+	tempPath, ext, hash, err := h.downloadFromURL(ctx, rewrittenURL, router)
 	if err != nil {
 		return "", fmt.Errorf("failed to download media from URL: %w", err)
 	}
@@ -110,15 +171,15 @@ func (h *handler) processMediaFromURL(mediaURL string) (string, error) {
 	}
 
 	// Validate media type and size
-	if err := h.validateMedia(ext, info.Size()); err != nil {
+	if err := validateMedia(router, ext, info.Size()); err != nil {
 		return "", err
 	}
 
 	// Process the downloaded file
-	return h.processDownloadedFile(tempPath, ext)
+	return h.processDownloadedFile(tempPath, ext, hash, dir)
 }
 
-func (h *handler) processMediaFromFile(path string) (string, error) {
+func (h *handler) processMediaFromFile(path, dir string, router media.Router) (string, error) {
 	// Validate file path to prevent directory traversal
 	if err := security.ValidateFilePath(path); err != nil {
 		return "", fmt.Errorf("invalid media path: %w", err)
@@ -140,10 +201,18 @@ func (h *handler) processMediaFromFile(path string) (string, error) {
 	}
 
 	// Check if file type is allowed and validate size
-	if err := h.validateMedia(ext, info.Size()); err != nil {
+	if err := validateMedia(router, ext, info.Size()); err != nil {
 		return "", err
 	}
 
+	if oriented, ok := h.autoOrient(path, ext); ok {
+		defer func() { _ = os.Remove(oriented) }()
+		path = oriented
+		if info, err = os.Stat(path); err != nil {
+			return "", fmt.Errorf("failed to get oriented image info: %w", err)
+		}
+	}
+
 	file, err := os.Open(path) // #nosec G304 - Path validated by security.ValidateFilePath above
 	if err != nil {
 		return "", fmt.Errorf("failed to open file: %w", err)
@@ -156,9 +225,9 @@ func (h *handler) processMediaFromFile(path string) (string, error) {
 	}
 
 	hashStr := fmt.Sprintf("%x", hash.Sum(nil))
-	cachedPath := filepath.Join(h.cacheDir, hashStr+"."+ext)
+	cachedPath := filepath.Join(dir, hashStr+"."+ext)
 
-	if _, err := os.Stat(cachedPath); err == nil {
+	if h.isCached(hashStr, cachedPath) {
 		return cachedPath, nil
 	}
 
@@ -168,38 +237,63 @@ func (h *handler) processMediaFromFile(path string) (string, error) {
 		}
 	}
 
+	h.recordCached(hashStr, cachedPath, info.Size())
 	return cachedPath, nil
 }
 
-func (h *handler) validateMedia(ext string, size int64) error {
+func validateMedia(router media.Router, ext string, size int64) error {
 	// Create a fake path with the extension to use MediaRouter
 	fakePath := "file." + ext
-	mediaType := h.mediaRouter.GetMediaType(fakePath)
+	mediaType := router.GetMediaType(fakePath)
 
-	maxSizeBytes := h.mediaRouter.GetMaxSizeForMediaType(mediaType)
+	maxSizeBytes := router.GetMaxSizeForMediaType(mediaType)
 	if size > maxSizeBytes {
-		return fmt.Errorf("%s too large: %d > %d bytes", mediaType, size, maxSizeBytes)
+		return &OversizeError{MediaType: mediaType, Size: size, Limit: maxSizeBytes}
 	}
 
 	return nil
 }
 
 func (h *handler) CleanupOldFiles(maxAge int64) error {
-	entries, err := os.ReadDir(h.cacheDir)
+	// With an index, age is measured from the last access rather than the file's modification time
+	defaultAge := time.Duration(maxAge) * time.Second
+	if h.index != nil {
+		// Partial downloads are not in the index
+		h.removeStalePartialDownloads(defaultAge)
+		return h.index.removeOlderThan(func(path string) time.Duration {
+			return h.retentionFor(path, defaultAge)
+		})
+	}
+
+	return h.cleanupDir(h.cacheDir, defaultAge, true)
+}
+
+// cleanupDir removes the files in dir older than their retention. In the cache directory itself
+// it also cleans the session subdirectories of media.sessionDirs.
+func (h *handler) cleanupDir(dir string, defaultAge time.Duration, withSessionDirs bool) error {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return fmt.Errorf("failed to read cache directory: %w", err)
 	}
 
 	now := time.Now()
 	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if withSessionDirs {
+				if err := h.cleanupDir(path, defaultAge, false); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
 		info, err := entry.Info()
 		if err != nil {
 			return fmt.Errorf("failed to get file info: %w", err)
 		}
 
-		age := now.Sub(info.ModTime())
-		if age.Seconds() > float64(maxAge) {
-			path := filepath.Join(h.cacheDir, info.Name())
+		if now.Sub(info.ModTime()) > h.retentionFor(path, defaultAge) {
 			if err := os.Remove(path); err != nil {
 				return fmt.Errorf("failed to remove old file: %w", err)
 			}
@@ -209,68 +303,144 @@ func (h *handler) CleanupOldFiles(maxAge int64) error {
 	return nil
 }
 
-func (h *handler) downloadFromURL(mediaURL string) (string, string, error) {
-	// Use the same timeout as configured for the HTTP client
+// acquireDownloadSlot blocks until fewer than the configured number of downloads are running,
+// or until ctx is done or the download timeout passes. The returned func releases the slot.
+// A handler without a semaphore (e.g. one built outside NewHandler) is unbounded.
+func (h *handler) acquireDownloadSlot(ctx context.Context) (func(), error) {
+	if h.downloadSlots == nil {
+		metrics.SetGauge("media_downloads_in_flight", float64(h.inFlight.Add(1)), nil, "Media URL downloads currently running")
+		return func() {
+			metrics.SetGauge("media_downloads_in_flight", float64(h.inFlight.Add(-1)), nil, "Media URL downloads currently running")
+		}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.downloadTimeout())
+	defer cancel()
+
+	select {
+	case h.downloadSlots <- struct{}{}:
+	case <-ctx.Done():
+		metrics.IncrementCounter("media_downloads_queue_timeouts_total", nil, "Media downloads abandoned while waiting for a download slot")
+		return nil, fmt.Errorf("timed out waiting for a download slot: %w", ctx.Err())
+	}
+
+	metrics.SetGauge("media_downloads_in_flight", float64(h.inFlight.Add(1)), nil, "Media URL downloads currently running")
+	return func() {
+		metrics.SetGauge("media_downloads_in_flight", float64(h.inFlight.Add(-1)), nil, "Media URL downloads currently running")
+		<-h.downloadSlots
+	}, nil
+}
+
+// inFlightDownloads returns the number of media URL downloads currently running
+func (h *handler) inFlightDownloads() int {
+	return int(h.inFlight.Load())
+}
+
+// downloadTimeout returns the configured media download timeout or the default
+func (h *handler) downloadTimeout() time.Duration {
 	downloadTimeout := h.config.DownloadTimeout
 	if downloadTimeout <= 0 {
 		downloadTimeout = constants.DefaultMediaDownloadTimeoutSec
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(downloadTimeout)*time.Second)
+	return time.Duration(downloadTimeout) * time.Second
+}
+
+// downloadFromURL downloads mediaURL to a temporary file in the cache directory and returns its
+// path, extension and SHA-256, enforcing router's size limit. The hash is empty for resumable
+// downloads, which are not read in one piece.
+func (h *handler) downloadFromURL(ctx context.Context, mediaURL string, router media.Router) (string, string, string, error) {
+	// Use the same timeout as configured for the HTTP client
+	ctx, cancel := context.WithTimeout(ctx, h.downloadTimeout())
 	defer cancel()
 
 	// Safety: validate again at download time
 	if err := h.validateDownloadURL(mediaURL); err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 	resolvedIP, err := h.resolveDownloadIP(ctx, mediaURL)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
+	client := h.httpClientForPinnedDownload(mediaURL, resolvedIP)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", mediaURL, nil)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to create request: %w", err)
+	if h.config.DownloadResumeAttempts > 0 {
+		if release, ok := h.claimPartialDownload(mediaURL); ok {
+			defer release()
+			tempPath, ext, err := h.downloadResumable(ctx, client, mediaURL, router)
+			return tempPath, ext, "", err
+		}
 	}
 
-	// Add WAHA API key authentication if available
-	if h.wahaAPIKey != "" {
-		req.Header.Set("X-Api-Key", h.wahaAPIKey)
+	req, err := h.newDownloadRequest(ctx, mediaURL)
+	if err != nil {
+		return "", "", "", err
 	}
 
-	resp, err := h.httpClientForPinnedDownload(mediaURL, resolvedIP).Do(req) // #nosec G704 - URL validated by validateDownloadURL above
+	resp, err := client.Do(req) // #nosec G704 - URL validated by validateDownloadURL above
 	if err != nil {
-		return "", "", fmt.Errorf("failed to download file: %w", err)
+		return "", "", "", fmt.Errorf("failed to download file: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("download failed with status: %d", resp.StatusCode)
+		return "", "", "", fmt.Errorf("download failed with status: %d", resp.StatusCode)
 	}
 
 	// Determine file extension from Content-Type or URL
 	ext := h.getFileExtensionFromResponse(resp, mediaURL)
 
+	mediaType := router.GetMediaType("file." + strings.TrimPrefix(ext, "."))
+	maxSizeBytes := router.GetMaxSizeForMediaType(mediaType)
+	// A declared length over the limit is rejected before any of the body is read
+	if resp.ContentLength > maxSizeBytes {
+		return "", "", "", &OversizeError{MediaType: mediaType, Size: resp.ContentLength, Limit: maxSizeBytes}
+	}
+
 	// Create temporary file
-	tempFile, err := os.CreateTemp(h.cacheDir, "download_*"+ext)
+	tempFile, err := os.CreateTemp(h.cacheDir, downloadTempPrefix+"*"+ext)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to create temp file: %w", err)
+		return "", "", "", fmt.Errorf("failed to create temp file: %w", err)
 	}
 	defer func() { _ = tempFile.Close() }()
 
-	// Copy response body to temp file
-	mediaType := h.mediaRouter.GetMediaType("file." + strings.TrimPrefix(ext, "."))
-	maxSizeBytes := h.mediaRouter.GetMaxSizeForMediaType(mediaType)
-	written, err := io.Copy(tempFile, io.LimitReader(resp.Body, maxSizeBytes+1))
+	// Stream the body to the temp file, hashing it on the way so it is not read again. The
+	// limit stops the copy one byte past the largest allowed size, so an oversized body is
+	// never held in memory or written out in full.
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tempFile, hash), io.LimitReader(resp.Body, maxSizeBytes+1))
 	if err != nil {
 		_ = os.Remove(tempFile.Name()) // #nosec G703 - Best effort cleanup after copy failure; path from os.CreateTemp
-		return "", "", fmt.Errorf("failed to save downloaded file: %w", err)
+		return "", "", "", fmt.Errorf("failed to save downloaded file: %w", err)
 	}
 	if written > maxSizeBytes {
 		_ = os.Remove(tempFile.Name()) // #nosec G703 - Best effort cleanup after oversized download; path from os.CreateTemp
-		return "", "", fmt.Errorf("%s too large: %d > %d bytes", mediaType, written, maxSizeBytes)
+		return "", "", "", &OversizeError{MediaType: mediaType, Size: written, Limit: maxSizeBytes}
 	}
 
-	return tempFile.Name(), strings.TrimPrefix(ext, "."), nil
+	return tempFile.Name(), strings.TrimPrefix(ext, "."), fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// newDownloadRequest builds the GET request for a media URL with the configured headers and
+// WAHA authentication
+func (h *handler) newDownloadRequest(ctx context.Context, mediaURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", mediaURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Configured headers and User-Agent, for proxies or CDNs in front of WAHA media
+	for name, value := range h.config.DownloadHeaders {
+		req.Header.Set(name, value)
+	}
+	if h.config.DownloadUserAgent != "" {
+		req.Header.Set("User-Agent", h.config.DownloadUserAgent)
+	}
+
+	// Add WAHA API key authentication if available
+	if h.wahaAPIKey != "" {
+		req.Header.Set("X-Api-Key", h.wahaAPIKey)
+	}
+	return req, nil
 }
 
 func (h *handler) resolveDownloadIP(ctx context.Context, mediaURL string) (net.IP, error) {
@@ -328,28 +498,38 @@ func (h *handler) httpClientForPinnedDownload(mediaURL string, resolvedIP net.IP
 	return &pinnedClient
 }
 
-func (h *handler) processDownloadedFile(tempPath, ext string) (string, error) {
+// processDownloadedFile stores a downloaded file in dir under its content hash. hashStr is the
+// SHA-256 computed while downloading, or empty to hash the file here.
+func (h *handler) processDownloadedFile(tempPath, ext, hashStr, dir string) (string, error) {
 	// Validate file path to prevent directory traversal
 	if err := security.ValidateFilePath(tempPath); err != nil {
 		return "", fmt.Errorf("invalid temp file path: %w", err)
 	}
 
-	file, err := os.Open(tempPath) // #nosec G304 - Path validated by security.ValidateFilePath above
-	if err != nil {
-		return "", fmt.Errorf("failed to open downloaded file: %w", err)
+	if oriented, ok := h.autoOrient(tempPath, ext); ok {
+		defer func() { _ = os.Remove(oriented) }()
+		tempPath = oriented
+		hashStr = "" // Re-encoding changed the content
 	}
-	defer func() { _ = file.Close() }()
 
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", fmt.Errorf("failed to calculate hash: %w", err)
+	if hashStr == "" {
+		file, err := os.Open(tempPath) // #nosec G304 - Path validated by security.ValidateFilePath above
+		if err != nil {
+			return "", fmt.Errorf("failed to open downloaded file: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+
+		hash := sha256.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return "", fmt.Errorf("failed to calculate hash: %w", err)
+		}
+		hashStr = fmt.Sprintf("%x", hash.Sum(nil))
 	}
 
-	hashStr := fmt.Sprintf("%x", hash.Sum(nil))
-	cachedPath := filepath.Join(h.cacheDir, hashStr+"."+ext)
+	cachedPath := filepath.Join(dir, hashStr+"."+ext)
 
 	// Check if file already exists in cache
-	if _, err := os.Stat(cachedPath); err == nil {
+	if h.isCached(hashStr, cachedPath) {
 		return cachedPath, nil
 	}
 
@@ -358,9 +538,63 @@ func (h *handler) processDownloadedFile(tempPath, ext string) (string, error) {
 		return "", fmt.Errorf("failed to copy file to cache: %w", err)
 	}
 
+	if info, err := os.Stat(cachedPath); err == nil {
+		h.recordCached(hashStr, cachedPath, info.Size())
+	}
 	return cachedPath, nil
 }
 
+// isCached reports whether the media with the given content hash is already at cachedPath,
+// consulting the cache index when there is one. With media.verifyCache a cached file whose
+// content no longer matches its hash, e.g. after an interrupted write, is removed and reported
+// as not cached, so the caller stores a fresh copy from the source.
+func (h *handler) isCached(hash, cachedPath string) bool {
+	var cached bool
+	if h.index != nil {
+		cached = h.index.lookup(indexKey(h.cacheDir, hash, cachedPath), cachedPath)
+	} else {
+		_, err := os.Stat(cachedPath)
+		cached = err == nil
+	}
+	if cached && h.config.VerifyCache && !cachedFileIntact(hash, cachedPath) {
+		metrics.IncrementCounter("media_cache_corrupt_total", nil, "Cached media files replaced because their content did not match their hash")
+		_ = os.Remove(cachedPath)
+		cached = false
+	}
+
+	if cached {
+		h.cacheHits.Add(1)
+		metrics.IncrementCounter("media_cache_hits_total", nil, "Processed media found already in the cache")
+	} else {
+		h.cacheMisses.Add(1)
+		metrics.IncrementCounter("media_cache_misses_total", nil, "Processed media stored in the cache as a new file")
+	}
+	return cached
+}
+
+// cachedFileIntact reports whether the SHA-256 of the file at path is hash
+func cachedFileIntact(hash, path string) bool {
+	file, err := os.Open(path) // #nosec G304 - Path built from the cache directory and a content hash
+	if err != nil {
+		return false
+	}
+	defer func() { _ = file.Close() }()
+
+	sum := sha256.New()
+	if _, err := io.Copy(sum, file); err != nil {
+		return false
+	}
+	return fmt.Sprintf("%x", sum.Sum(nil)) == hash
+}
+
+// recordCached adds a newly cached file to the cache index, if there is one. A failure to
+// persist the index is not fatal: the entry stays in memory and the file is still served.
+func (h *handler) recordCached(hash, cachedPath string, size int64) {
+	if h.index != nil {
+		_ = h.index.record(indexKey(h.cacheDir, hash, cachedPath), cachedPath, size)
+	}
+}
+
 func (h *handler) rewriteMediaURL(mediaURL string) string {
 	// If no WAHA base URL is configured, return original URL
 	if h.wahaBaseURL == "" {
@@ -426,141 +660,6 @@ func isDockerInternalIP(ip net.IP) bool {
 	return dockerNet.Contains(ip)
 }
 
-func (h *handler) getFileExtensionFromResponse(resp *http.Response, mediaURL string) string {
-	// Try to get extension from Content-Type header
-	contentType := resp.Header.Get("Content-Type")
-	if contentType != "" {
-		if exts, err := mime.ExtensionsByType(contentType); err == nil && len(exts) > 0 {
-			return exts[0]
-		}
-	}
-
-	// Fallback to URL path extension
-	if ext := filepath.Ext(mediaURL); ext != "" {
-		return ext
-	}
-
-	// Default extension for unknown types
-	return ".bin"
-}
-
-func (h *handler) detectFileTypeFromContent(path string) (string, error) {
-	// Validate file path to prevent directory traversal
-	if err := security.ValidateFilePath(path); err != nil {
-		return "", fmt.Errorf("invalid file path for content detection: %w", err)
-	}
-
-	file, err := os.Open(path) // #nosec G304 - Path validated by security.ValidateFilePath above
-	if err != nil {
-		return "", fmt.Errorf("failed to open file for content detection: %w", err)
-	}
-	defer func() { _ = file.Close() }()
-
-	// Read first 512 bytes for content type detection
-	buffer := make([]byte, constants.MimeDetectionBufferSize)
-	n, err := file.Read(buffer)
-	if err != nil && err != io.EOF {
-		return "", fmt.Errorf("failed to read file content: %w", err)
-	}
-
-	// Check for specific file signatures first (more reliable than http.DetectContentType for audio)
-	if ext := h.detectByFileSignature(buffer[:n]); ext != "" {
-		return ext, nil
-	}
-
-	// Detect MIME type from content
-	contentType := http.DetectContentType(buffer[:n])
-
-	// Try direct mapping first
-	if ext, ok := constants.ContentTypeToExtension[contentType]; ok {
-		return ext, nil
-	}
-
-	// Fallback to partial matching for complex content types
-	switch {
-	case strings.HasPrefix(contentType, "audio/"):
-		// Check for partial matches in audio types
-		for contentTypeKey, ext := range constants.ContentTypeToExtension {
-			if strings.HasPrefix(contentTypeKey, "audio/") && strings.Contains(contentType, strings.TrimPrefix(contentTypeKey, "audio/")) {
-				return ext, nil
-			}
-		}
-		return constants.DefaultAudioExtension, nil
-	case strings.HasPrefix(contentType, "image/"):
-		// Check for partial matches in image types
-		for contentTypeKey, ext := range constants.ContentTypeToExtension {
-			if strings.HasPrefix(contentTypeKey, "image/") && strings.Contains(contentType, strings.TrimPrefix(contentTypeKey, "image/")) {
-				return ext, nil
-			}
-		}
-		return constants.DefaultImageExtension, nil
-	case strings.HasPrefix(contentType, "video/"):
-		// Check for partial matches in video types
-		for contentTypeKey, ext := range constants.ContentTypeToExtension {
-			if strings.HasPrefix(contentTypeKey, "video/") && strings.Contains(contentType, strings.TrimPrefix(contentTypeKey, "video/")) {
-				return ext, nil
-			}
-
-		}
-		return constants.DefaultVideoExtension, nil
-	default:
-		// For other types, return empty string to use document default
-		return "", nil
-	}
-}
-
-func (h *handler) detectByFileSignature(data []byte) string {
-	if len(data) < 3 {
-		return ""
-	}
-
-	// Check for known file signatures from constants
-	for signature, ext := range constants.FileSignatures {
-		sigBytes := []byte(signature)
-		if len(data) >= len(sigBytes) {
-			if string(data[0:len(sigBytes)]) == signature {
-				// Special case for WebP: also check for WEBP marker
-				if signature == "RIFF" && len(data) >= 12 && string(data[8:12]) == "WEBP" {
-					return ext
-				} else if signature != "RIFF" {
-					return ext
-				}
-			}
-		}
-	}
-
-	// Special binary signatures that can't be easily stored as strings
-
-	// Check for MP3 frame sync (binary pattern)
-	if len(data) >= 2 && data[0] == 0xFF && (data[1]&0xE0) == 0xE0 {
-		return "mp3"
-	}
-
-	// Check for AAC file signature (ADTS header)
-	if len(data) >= 2 && data[0] == 0xFF && (data[1]&0xF0) == 0xF0 {
-		return "aac"
-	}
-
-	// Check for M4A/MP4 signature (ftyp box)
-	if len(data) >= 8 && string(data[4:8]) == "ftyp" {
-		// Check for M4A-specific brand codes
-		if len(data) >= 12 {
-			brand := string(data[8:12])
-			if brand == "M4A " || brand == "mp41" || brand == "mp42" {
-				return "m4a"
-			}
-		}
-		return "mp4" // Default to mp4 for other ftyp variants
-	}
-
-	// Check for JPEG signatures (binary pattern)
-	if len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF {
-		return "jpg"
-	}
-
-	return ""
-}
-
 func copyFile(src, dst string) error {
 	// Validate source path to prevent directory traversal
 	if err := security.ValidateFilePath(src); err != nil {
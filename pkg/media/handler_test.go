@@ -1,6 +1,7 @@
 package media
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -9,7 +10,10 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 	"whatsignal/internal/constants"
@@ -106,7 +110,7 @@ func TestProcessMedia(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test processing
-	cachePath, err := handler.ProcessMedia(sourcePath)
+	cachePath, err := handler.ProcessMedia(context.Background(), sourcePath)
 	require.NoError(t, err)
 	assert.Contains(t, cachePath, expectedHash)
 
@@ -116,12 +120,12 @@ func TestProcessMedia(t *testing.T) {
 	assert.Equal(t, content, cachedContent)
 
 	// Test processing same file again (should return same path)
-	cachePath2, err := handler.ProcessMedia(sourcePath)
+	cachePath2, err := handler.ProcessMedia(context.Background(), sourcePath)
 	require.NoError(t, err)
 	assert.Equal(t, cachePath, cachePath2)
 
 	// Test processing non-existent file
-	_, err = handler.ProcessMedia("/nonexistent/file.jpg")
+	_, err = handler.ProcessMedia(context.Background(), "/nonexistent/file.jpg")
 	assert.Error(t, err)
 }
 
@@ -181,7 +185,7 @@ func TestProcessMediaWithInvalidPath(t *testing.T) {
 	defer cleanup()
 
 	// Test with non-existent file
-	cachedPath, err := handler.ProcessMedia("/nonexistent/path")
+	cachedPath, err := handler.ProcessMedia(context.Background(), "/nonexistent/path")
 	assert.Error(t, err)
 	assert.Empty(t, cachedPath)
 }
@@ -194,7 +198,7 @@ func TestProcessMediaWithUnsupportedType(t *testing.T) {
 	sourcePath := createTestFile(t, tmpDir, "test.xyz", 1024)
 
 	// Should process unknown file types as documents (default behavior)
-	cachedPath, err := handler.ProcessMedia(sourcePath)
+	cachedPath, err := handler.ProcessMedia(context.Background(), sourcePath)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, cachedPath)
 	assert.FileExists(t, cachedPath)
@@ -257,7 +261,7 @@ func TestProcessMediaSizeLimits(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			sourcePath := createTestFile(t, tmpDir, tt.filename, tt.size)
-			cachedPath, err := handler.ProcessMedia(sourcePath)
+			cachedPath, err := handler.ProcessMedia(context.Background(), sourcePath)
 			if tt.wantError {
 				assert.Error(t, err)
 				assert.Empty(t, cachedPath)
@@ -357,7 +361,7 @@ func TestProcessMediaCopyFallback(t *testing.T) {
 	require.NoError(t, err)
 
 	// Process the file
-	cachedPath, err := handler.ProcessMedia(sourcePath)
+	cachedPath, err := handler.ProcessMedia(context.Background(), sourcePath)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, cachedPath)
 
@@ -482,7 +486,7 @@ func TestProcessMediaFromURL(t *testing.T) {
 	h.wahaBaseURL = server.URL
 
 	// Test successful URL processing
-	cachedPath, err := handlerInterface.ProcessMedia(server.URL + "/image.jpg")
+	cachedPath, err := handlerInterface.ProcessMedia(context.Background(), server.URL+"/image.jpg")
 	require.NoError(t, err)
 	assert.NotEmpty(t, cachedPath)
 
@@ -492,11 +496,45 @@ func TestProcessMediaFromURL(t *testing.T) {
 	assert.Equal(t, testContent, cachedContent)
 
 	// Test processing same URL again (should return same cached path)
-	cachedPath2, err := handlerInterface.ProcessMedia(server.URL + "/image.jpg")
+	cachedPath2, err := handlerInterface.ProcessMedia(context.Background(), server.URL+"/image.jpg")
 	require.NoError(t, err)
 	assert.Equal(t, cachedPath, cachedPath2)
 }
 
+func TestProcessMediaFromURL_DownloadHeaders(t *testing.T) {
+	handlerInterface, _, cleanup := setupTestHandler(t)
+	defer cleanup()
+	h := handlerInterface.(*handler)
+
+	testContent := []byte("proxied image content")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Proxy-Token") != "letmein" || r.Header.Get("User-Agent") != "whatsignal-test/1.0" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(testContent); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+	h.wahaBaseURL = server.URL
+
+	_, err := handlerInterface.ProcessMedia(context.Background(), server.URL+"/proxied.jpg")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "download failed with status: 403")
+
+	h.config.DownloadHeaders = map[string]string{"X-Proxy-Token": "letmein"}
+	h.config.DownloadUserAgent = "whatsignal-test/1.0"
+
+	cachedPath, err := handlerInterface.ProcessMedia(context.Background(), server.URL+"/proxied.jpg")
+	require.NoError(t, err)
+	cachedContent, err := os.ReadFile(cachedPath)
+	require.NoError(t, err)
+	assert.Equal(t, testContent, cachedContent)
+}
+
 func TestProcessMediaFromURLErrors(t *testing.T) {
 	handlerInterface, _, cleanup := setupTestHandler(t)
 	defer cleanup()
@@ -548,7 +586,7 @@ func TestProcessMediaFromURLErrors(t *testing.T) {
 			defer server.Close()
 			h.wahaBaseURL = server.URL
 
-			_, err := handlerInterface.ProcessMedia(server.URL + "/file")
+			_, err := handlerInterface.ProcessMedia(context.Background(), server.URL+"/file")
 			require.Error(t, err)
 			assert.Contains(t, err.Error(), tt.expectedError)
 		})
@@ -575,11 +613,127 @@ func TestProcessMediaFromURLWithLargeFile(t *testing.T) {
 	defer server.Close()
 	h.wahaBaseURL = server.URL
 
-	_, err := handlerInterface.ProcessMedia(server.URL + "/large.jpg")
+	_, err := handlerInterface.ProcessMedia(context.Background(), server.URL+"/large.jpg")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "image too large")
 }
 
+func TestProcessMediaFromURL_LimitsConcurrentDownloads(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := getTestMediaConfig()
+	config.MaxConcurrentDownloads = 2
+	handlerInterface, err := NewHandler(filepath.Join(tmpDir, "cache"), config)
+	require.NoError(t, err)
+	h := handlerInterface.(*handler)
+
+	var active, maxActive atomic.Int32
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			peak := maxActive.Load()
+			if n <= peak || maxActive.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		<-unblock
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("image " + r.URL.Path))
+	}))
+	defer server.Close()
+	h.wahaBaseURL = server.URL
+
+	const downloads = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, downloads)
+	for i := 0; i < downloads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := handlerInterface.ProcessMedia(context.Background(), fmt.Sprintf("%s/image%d.jpg", server.URL, i))
+			errs <- err
+		}(i)
+	}
+
+	require.Eventually(t, func() bool { return h.inFlightDownloads() == 2 }, 2*time.Second, 10*time.Millisecond)
+	// The remaining downloads queue instead of reaching the server
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(2), active.Load())
+	assert.Equal(t, 2, h.inFlightDownloads())
+
+	close(unblock)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), maxActive.Load())
+	assert.Equal(t, 0, h.inFlightDownloads())
+}
+
+func TestAcquireDownloadSlot_RespectsContextCancellation(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := getTestMediaConfig()
+	config.MaxConcurrentDownloads = 1
+	handlerInterface, err := NewHandler(filepath.Join(tmpDir, "cache"), config)
+	require.NoError(t, err)
+	h := handlerInterface.(*handler)
+
+	release, err := h.acquireDownloadSlot(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = h.acquireDownloadSlot(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	release()
+	release2, err := h.acquireDownloadSlot(context.Background())
+	require.NoError(t, err)
+	release2()
+	assert.Equal(t, 0, h.inFlightDownloads())
+}
+
+func TestProcessMediaFromURL_CancelReleasesQueuedDownload(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := getTestMediaConfig()
+	config.MaxConcurrentDownloads = 1
+	handlerInterface, err := NewHandler(filepath.Join(tmpDir, "cache"), config)
+	require.NoError(t, err)
+	h := handlerInterface.(*handler)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a download waiting for a slot must not reach the server")
+	}))
+	defer server.Close()
+	h.wahaBaseURL = server.URL
+
+	// Another download holds the only slot
+	release, err := h.acquireDownloadSlot(context.Background())
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := handlerInterface.ProcessMedia(ctx, server.URL+"/queued.jpg")
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("cancelling the context did not release the queued download")
+	}
+	assert.Equal(t, 1, h.inFlightDownloads())
+}
+
 func TestGetFileExtensionFromResponse(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -676,9 +830,13 @@ func TestProcessMediaFromURLTimeout(t *testing.T) {
 	defer server.Close()
 	handler.wahaBaseURL = server.URL
 
-	_, err = handler.ProcessMedia(server.URL + "/slow.jpg")
+	start := time.Now()
+	_, err = handler.ProcessMedia(context.Background(), server.URL+"/slow.jpg")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to download media from URL")
+	assert.Contains(t, err.Error(), "Client.Timeout exceeded")
+	assert.NotContains(t, err.Error(), "download slot")
+	assert.Less(t, time.Since(start), 5*time.Second)
 }
 
 func TestProcessMediaFromURLInvalidURL(t *testing.T) {
@@ -688,7 +846,7 @@ func TestProcessMediaFromURLInvalidURL(t *testing.T) {
 	h.wahaBaseURL = "http://invalid-domain-that-does-not-exist.com"
 
 	// Test with invalid URL
-	_, err := handlerInterface.ProcessMedia("http://invalid-domain-that-does-not-exist.com/image.jpg")
+	_, err := handlerInterface.ProcessMedia(context.Background(), "http://invalid-domain-that-does-not-exist.com/image.jpg")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to download media from URL")
 }
@@ -697,7 +855,7 @@ func TestProcessMediaRejectsSchemeWithoutHost(t *testing.T) {
 	handlerInterface, _, cleanup := setupTestHandler(t)
 	defer cleanup()
 
-	_, err := handlerInterface.ProcessMedia("file:///etc/passwd")
+	_, err := handlerInterface.ProcessMedia(context.Background(), "file:///etc/passwd")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported media URL scheme")
 }
@@ -713,7 +871,7 @@ func TestProcessMediaMixedTypes(t *testing.T) {
 	err := os.WriteFile(localPath, localContent, 0644)
 	require.NoError(t, err)
 
-	cachedLocalPath, err := handlerInterface.ProcessMedia(localPath)
+	cachedLocalPath, err := handlerInterface.ProcessMedia(context.Background(), localPath)
 	require.NoError(t, err)
 	assert.NotEmpty(t, cachedLocalPath)
 
@@ -729,7 +887,7 @@ func TestProcessMediaMixedTypes(t *testing.T) {
 	defer server.Close()
 	h.wahaBaseURL = server.URL
 
-	cachedURLPath, err := handlerInterface.ProcessMedia(server.URL + "/remote.jpg")
+	cachedURLPath, err := handlerInterface.ProcessMedia(context.Background(), server.URL+"/remote.jpg")
 	require.NoError(t, err)
 	assert.NotEmpty(t, cachedURLPath)
 
@@ -814,7 +972,7 @@ func TestDownloadFromURLContentTypes(t *testing.T) {
 			}
 
 			if isAllowed {
-				cachedPath, err := handler.ProcessMedia(url)
+				cachedPath, err := handler.ProcessMedia(context.Background(), url)
 				if err != nil {
 					t.Logf("Error processing %s (expected %s): %v", url, tt.expectExt, err)
 					// Skip this test case if there's an unexpected error
@@ -1029,7 +1187,7 @@ func TestProcessMediaFromURLWithAPIKey(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test URL processing
-	cachedPath, err := handler.ProcessMedia(server.URL + "/image.jpg")
+	cachedPath, err := handler.ProcessMedia(context.Background(), server.URL+"/image.jpg")
 	require.NoError(t, err)
 	assert.NotEmpty(t, cachedPath)
 
@@ -1068,7 +1226,7 @@ func TestProcessMediaFromURLWithoutAPIKey(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test URL processing
-	cachedPath, err := handler.ProcessMedia(server.URL + "/image.jpg")
+	cachedPath, err := handler.ProcessMedia(context.Background(), server.URL+"/image.jpg")
 	require.NoError(t, err)
 	assert.NotEmpty(t, cachedPath)
 
@@ -1144,7 +1302,7 @@ func TestProcessMediaFromFileEdgeCases(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			filePath := tt.setupFile()
 
-			result, err := h.processMediaFromFile(filePath)
+			result, err := h.processMediaFromFile(filePath, h.cacheDir, h.mediaRouter)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -1220,7 +1378,7 @@ func TestProcessDownloadedFileEdgeCases(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tempPath := tt.setupFile()
 
-			result, err := h.processDownloadedFile(tempPath, tt.ext)
+			result, err := h.processDownloadedFile(tempPath, tt.ext, "", h.cacheDir)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -1318,7 +1476,7 @@ func TestDownloadFromURLEdgeCases(t *testing.T) {
 				testURL = server.URL + "/test.jpg"
 			}
 
-			tempPath, ext, err := h.downloadFromURL(testURL)
+			tempPath, ext, _, err := h.downloadFromURL(context.Background(), testURL, h.mediaRouter)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -1359,7 +1517,7 @@ func TestDownloadFromURLRejectsOversizedResponseAndRemovesTempFile(t *testing.T)
 	defer server.Close()
 	h.wahaBaseURL = server.URL
 
-	tempPath, ext, err := h.downloadFromURL(server.URL + "/oversized.jpg")
+	tempPath, ext, _, err := h.downloadFromURL(context.Background(), server.URL+"/oversized.jpg", h.mediaRouter)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "too large")
 	assert.Empty(t, tempPath)
@@ -1370,6 +1528,111 @@ func TestDownloadFromURLRejectsOversizedResponseAndRemovesTempFile(t *testing.T)
 	assert.Empty(t, matches)
 }
 
+// zeroReader is an endless body that costs no memory to serve
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestDownloadFromURLStreamsOversizedBodyWithBoundedMemory(t *testing.T) {
+	config := getTestMediaConfig()
+	config.MaxSizeMB.Image = 16
+
+	handlerInterface, err := NewHandlerWithWAHA(filepath.Join(t.TempDir(), "cache"), config, "http://127.0.0.1", "test-api-key")
+	require.NoError(t, err)
+	h := handlerInterface.(*handler)
+
+	// 128 MB without a Content-Length, so the limit can only be enforced while reading
+	const bodySize = 128 * constants.BytesPerMegabyte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.CopyN(w, zeroReader{}, bodySize)
+	}))
+	defer server.Close()
+	h.wahaBaseURL = server.URL
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	tempPath, _, _, err := h.downloadFromURL(context.Background(), server.URL+"/huge.jpg", h.mediaRouter)
+
+	runtime.ReadMemStats(&after)
+
+	var oversize *OversizeError
+	require.ErrorAs(t, err, &oversize)
+	assert.Contains(t, err.Error(), "image too large")
+	assert.Equal(t, int64(config.MaxSizeMB.Image)*constants.BytesPerMegabyte, oversize.Limit)
+	assert.Empty(t, tempPath)
+
+	// Buffering the body, even only up to the limit, would allocate at least 16 MB
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(8*constants.BytesPerMegabyte), "download allocated memory in proportion to the body")
+
+	matches, globErr := filepath.Glob(filepath.Join(h.cacheDir, "download_*"))
+	require.NoError(t, globErr)
+	assert.Empty(t, matches, "aborted download left its temp file")
+}
+
+func TestDownloadFromURLRejectsDeclaredOversizeBeforeReading(t *testing.T) {
+	config := getTestMediaConfig()
+	config.MaxSizeMB.Image = 1
+
+	handlerInterface, err := NewHandlerWithWAHA(filepath.Join(t.TempDir(), "cache"), config, "http://127.0.0.1", "test-api-key")
+	require.NoError(t, err)
+	h := handlerInterface.(*handler)
+
+	declared := 200 * constants.BytesPerMegabyte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("Content-Length", fmt.Sprint(declared))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("start of a huge image"))
+	}))
+	defer server.Close()
+	h.wahaBaseURL = server.URL
+
+	tempPath, _, _, err := h.downloadFromURL(context.Background(), server.URL+"/huge.jpg", h.mediaRouter)
+	var oversize *OversizeError
+	require.ErrorAs(t, err, &oversize)
+	assert.Equal(t, int64(declared), oversize.Size)
+	assert.Empty(t, tempPath)
+
+	matches, globErr := filepath.Glob(filepath.Join(h.cacheDir, "download_*"))
+	require.NoError(t, globErr)
+	assert.Empty(t, matches)
+}
+
+func TestDownloadFromURLHashesWhileStreaming(t *testing.T) {
+	handlerInterface, _, cleanup := setupTestHandler(t)
+	defer cleanup()
+	h := handlerInterface.(*handler)
+
+	content := []byte(strings.Repeat("streamed image data ", 10000))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+	h.wahaBaseURL = server.URL
+
+	tempPath, ext, hash, err := h.downloadFromURL(context.Background(), server.URL+"/photo.jpg", h.mediaRouter)
+	require.NoError(t, err)
+	defer func() { _ = os.Remove(tempPath) }()
+
+	sum := sha256.Sum256(content)
+	assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+
+	cachedPath, err := h.processDownloadedFile(tempPath, ext, hash, h.cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(h.cacheDir, hash+"."+ext), cachedPath)
+	cached, err := os.ReadFile(cachedPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, cached)
+}
+
 func TestRewriteMediaURLEdgeCases(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1524,7 +1787,7 @@ func TestProcessMediaWithoutExtension(t *testing.T) {
 	require.NoError(t, err)
 
 	// Should process the file successfully, detecting it as audio
-	cachedPath, err := handler.ProcessMedia(sourcePath)
+	cachedPath, err := handler.ProcessMedia(context.Background(), sourcePath)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, cachedPath)
 	assert.FileExists(t, cachedPath)
@@ -1589,7 +1852,7 @@ func TestDetectFileTypeFromContent(t *testing.T) {
 			defer func() { _ = os.Remove(testPath) }()
 
 			// Test through ProcessMedia which will use detectFileTypeFromContent
-			cachedPath, err := handler.ProcessMedia(testPath)
+			cachedPath, err := handler.ProcessMedia(context.Background(), testPath)
 			assert.NoError(t, err)
 			assert.NotEmpty(t, cachedPath)
 			assert.FileExists(t, cachedPath)
@@ -1667,7 +1930,7 @@ func TestProcessMediaFromURL_RedirectSSRFBlocked(t *testing.T) {
 	handlerInterface, err := NewHandlerWithWAHA(cacheDir, getTestMediaConfig(), wahaServer.URL, "test-api-key")
 	require.NoError(t, err)
 
-	_, err = handlerInterface.ProcessMedia(wahaServer.URL + "/api/files/default/image.jpg")
+	_, err = handlerInterface.ProcessMedia(context.Background(), wahaServer.URL+"/api/files/default/image.jpg")
 	require.Error(t, err, "Should fail because redirect target is not in allowlist")
 	assert.Contains(t, err.Error(), "redirect blocked")
 }
@@ -0,0 +1,186 @@
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"os"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/metrics"
+)
+
+// exifOrientationTag is the TIFF tag holding how a JPEG's pixels must be turned to display upright
+const exifOrientationTag = 0x0112
+
+// errNoOrientation is returned by readJPEGOrientation for a JPEG without an orientation tag
+var errNoOrientation = errors.New("no EXIF orientation")
+
+// autoOrient writes an upright copy of the JPEG at path to the cache directory when
+// media.autoOrientImages is on and its EXIF orientation says it is turned or mirrored. The copy
+// is re-encoded without EXIF, so it is not turned a second time. It returns the copy's path and
+// true, or false when the image is used as it is: other formats, JPEGs without an orientation
+// or already upright, and files that cannot be decoded pass through unchanged. The caller
+// removes the copy.
+func (h *handler) autoOrient(path, ext string) (string, bool) {
+	if !h.config.AutoOrientImages || (ext != "jpg" && ext != "jpeg") {
+		return "", false
+	}
+
+	orientation, err := readJPEGOrientation(path)
+	if err != nil || orientation <= 1 || orientation > 8 {
+		return "", false
+	}
+
+	oriented, err := writeOrientedJPEG(path, h.cacheDir, orientation)
+	if err != nil {
+		return "", false
+	}
+	metrics.IncrementCounter("media_images_reoriented_total", nil, "JPEG images re-encoded upright from their EXIF orientation")
+	return oriented, true
+}
+
+// writeOrientedJPEG decodes the JPEG at path, turns it upright for orientation and writes it to
+// a new file in dir
+func writeOrientedJPEG(path, dir string, orientation int) (string, error) {
+	file, err := os.Open(path) // #nosec G304 - Path validated by the caller
+	if err != nil {
+		return "", fmt.Errorf("failed to open image: %w", err)
+	}
+	img, err := jpeg.Decode(file)
+	_ = file.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	out, err := os.CreateTemp(dir, "orient_*.jpg")
+	if err != nil {
+		return "", fmt.Errorf("failed to create oriented image: %w", err)
+	}
+	err = jpeg.Encode(out, applyOrientation(img, orientation), &jpeg.Options{Quality: constants.AutoOrientJPEGQuality})
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(out.Name())
+		return "", fmt.Errorf("failed to encode oriented image: %w", err)
+	}
+	return out.Name(), nil
+}
+
+// applyOrientation returns img as it is meant to be displayed for an EXIF orientation of 2-8:
+// mirrored, turned by 180°, or turned by 90° either way with or without mirroring
+func applyOrientation(img image.Image, orientation int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// source maps a pixel of the upright image to the pixel of img it comes from
+	var source func(x, y int) (int, int)
+	dw, dh := w, h
+	switch orientation {
+	case 2:
+		source = func(x, y int) (int, int) { return w - 1 - x, y }
+	case 3:
+		source = func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }
+	case 4:
+		source = func(x, y int) (int, int) { return x, h - 1 - y }
+	case 5:
+		source = func(x, y int) (int, int) { return y, x }
+	case 6:
+		source = func(x, y int) (int, int) { return y, h - 1 - x }
+	case 7:
+		source = func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }
+	case 8:
+		source = func(x, y int) (int, int) { return w - 1 - y, x }
+	default:
+		return img
+	}
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	upright := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := source(x, y)
+			upright.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return upright
+}
+
+// readJPEGOrientation returns the EXIF orientation of the JPEG at path, reading its header
+// segments up to the image data
+func readJPEGOrientation(path string) (int, error) {
+	file, err := os.Open(path) // #nosec G304 - Path validated by the caller
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+	r := bufio.NewReader(file)
+
+	var soi [2]byte
+	if _, err := io.ReadFull(r, soi[:]); err != nil || soi != [2]byte{0xFF, 0xD8} {
+		return 0, errors.New("not a JPEG")
+	}
+
+	for {
+		var marker [4]byte
+		if _, err := io.ReadFull(r, marker[:]); err != nil {
+			return 0, err
+		}
+		if marker[0] != 0xFF || marker[1] == 0xDA || marker[1] == 0xD9 {
+			// Start of scan or end of image: no EXIF before the image data
+			return 0, errNoOrientation
+		}
+		length := int(binary.BigEndian.Uint16(marker[2:]))
+		if length < 2 {
+			return 0, errors.New("invalid JPEG segment length")
+		}
+		segment := make([]byte, length-2)
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return 0, err
+		}
+		if marker[1] == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+	}
+}
+
+// exifOrientation returns the orientation tag from the first IFD of the TIFF data in an EXIF
+// segment
+func exifOrientation(tiff []byte) (int, error) {
+	if len(tiff) < 8 {
+		return 0, errNoOrientation
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, errNoOrientation
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0, errNoOrientation
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			return int(order.Uint16(tiff[entry+8:])), nil
+		}
+	}
+	return 0, errNoOrientation
+}
@@ -0,0 +1,208 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// corner names a corner of an image
+type corner int
+
+const (
+	topLeft corner = iota
+	topRight
+	bottomLeft
+	bottomRight
+)
+
+// orientedTestJPEG encodes a w x h white JPEG with a red block in redAt and a green block in
+// greenAt, and an EXIF orientation tag unless orientation is 0
+func orientedTestJPEG(t *testing.T, w, h int, redAt, greenAt corner, orientation int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	paintCorner(img, redAt, color.RGBA{R: 255, A: 255})
+	paintCorner(img, greenAt, color.RGBA{G: 255, A: 255})
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}))
+	if orientation == 0 {
+		return buf.Bytes()
+	}
+
+	// Big-endian TIFF with one IFD entry: orientation, SHORT, count 1
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	tiff = binary.BigEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = append(tiff, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01)
+	tiff = binary.BigEndian.AppendUint16(tiff, uint16(orientation))
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(app1)+2))
+	data = append(data, app1...)
+	return append(data, buf.Bytes()[2:]...)
+}
+
+// cornerBlock is the size of the coloured blocks in orientation test images
+const cornerBlock = 8
+
+func paintCorner(img *image.RGBA, at corner, c color.Color) {
+	b := img.Bounds()
+	x0, y0 := 0, 0
+	if at == topRight || at == bottomRight {
+		x0 = b.Dx() - cornerBlock
+	}
+	if at == bottomLeft || at == bottomRight {
+		y0 = b.Dy() - cornerBlock
+	}
+	for y := y0; y < y0+cornerBlock; y++ {
+		for x := x0; x < x0+cornerBlock; x++ {
+			img.Set(x, y, c)
+		}
+	}
+}
+
+// cornerColor returns the dominant colour in the middle of a corner block: "red", "green" or "white"
+func cornerColor(img image.Image, at corner) string {
+	b := img.Bounds()
+	x, y := cornerBlock/2, cornerBlock/2
+	if at == topRight || at == bottomRight {
+		x = b.Dx() - 1 - cornerBlock/2
+	}
+	if at == bottomLeft || at == bottomRight {
+		y = b.Dy() - 1 - cornerBlock/2
+	}
+	r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+	switch {
+	case r > 0xC000 && g > 0xC000 && bl > 0xC000:
+		return "white"
+	case r > 0xC000 && g < 0x4000:
+		return "red"
+	case g > 0xC000 && r < 0x4000:
+		return "green"
+	}
+	return fmt.Sprintf("rgb(%d,%d,%d)", r>>8, g>>8, bl>>8)
+}
+
+func newAutoOrientTestHandler(t *testing.T, cacheDir string) Handler {
+	t.Helper()
+	config := getTestMediaConfig()
+	config.AutoOrientImages = true
+	h, err := NewHandler(cacheDir, config)
+	require.NoError(t, err)
+	return h
+}
+
+func TestProcessMedia_AutoOrientImages(t *testing.T) {
+	// Each stored image shows, once turned as its orientation says, a 32x16 picture with red
+	// top left and green top right
+	tests := []struct {
+		orientation   int
+		width, height int
+		red, green    corner
+	}{
+		{orientation: 1, width: 32, height: 16, red: topLeft, green: topRight},
+		{orientation: 2, width: 32, height: 16, red: topRight, green: topLeft},
+		{orientation: 3, width: 32, height: 16, red: bottomRight, green: bottomLeft},
+		{orientation: 4, width: 32, height: 16, red: bottomLeft, green: bottomRight},
+		{orientation: 5, width: 16, height: 32, red: topLeft, green: bottomLeft},
+		{orientation: 6, width: 16, height: 32, red: bottomLeft, green: topLeft},
+		{orientation: 7, width: 16, height: 32, red: bottomRight, green: topRight},
+		{orientation: 8, width: 16, height: 32, red: topRight, green: bottomRight},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("orientation %d", tt.orientation), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			sourcePath := filepath.Join(tmpDir, "photo.jpg")
+			require.NoError(t, os.WriteFile(sourcePath, orientedTestJPEG(t, tt.width, tt.height, tt.red, tt.green, tt.orientation), 0644))
+
+			cachedPath, err := newAutoOrientTestHandler(t, filepath.Join(tmpDir, "cache")).ProcessMedia(context.Background(), sourcePath)
+			require.NoError(t, err)
+
+			orientation, err := readJPEGOrientation(cachedPath)
+			if tt.orientation > 1 {
+				assert.ErrorIs(t, err, errNoOrientation, "the orientation tag is stripped")
+			} else {
+				assert.Equal(t, 1, orientation)
+			}
+
+			file, err := os.Open(cachedPath)
+			require.NoError(t, err)
+			defer func() { _ = file.Close() }()
+			img, err := jpeg.Decode(file)
+			require.NoError(t, err)
+
+			assert.Equal(t, image.Pt(32, 16), img.Bounds().Size())
+			assert.Equal(t, "red", cornerColor(img, topLeft))
+			assert.Equal(t, "green", cornerColor(img, topRight))
+			assert.Equal(t, "white", cornerColor(img, bottomLeft))
+			assert.Equal(t, "white", cornerColor(img, bottomRight))
+		})
+	}
+}
+
+func TestProcessMedia_AutoOrientPassesThrough(t *testing.T) {
+	tmpDir := t.TempDir()
+	pngImage := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, pngImage))
+
+	sources := map[string][]byte{
+		"untagged.jpg": orientedTestJPEG(t, 32, 16, topLeft, topRight, 0),
+		"image.png":    pngData.Bytes(),
+	}
+	h := newAutoOrientTestHandler(t, filepath.Join(tmpDir, "cache"))
+	for name, content := range sources {
+		sourcePath := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(sourcePath, content, 0644))
+
+		cachedPath, err := h.ProcessMedia(context.Background(), sourcePath)
+		require.NoError(t, err)
+		cached, err := os.ReadFile(cachedPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, cached, "%s is cached unchanged", name)
+	}
+}
+
+func TestProcessMedia_AutoOrientDisabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := orientedTestJPEG(t, 16, 32, bottomLeft, topLeft, 6)
+	sourcePath := filepath.Join(tmpDir, "photo.jpg")
+	require.NoError(t, os.WriteFile(sourcePath, content, 0644))
+
+	h, err := NewHandler(filepath.Join(tmpDir, "cache"), getTestMediaConfig())
+	require.NoError(t, err)
+	cachedPath, err := h.ProcessMedia(context.Background(), sourcePath)
+	require.NoError(t, err)
+	cached, err := os.ReadFile(cachedPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, cached)
+}
+
+func TestExifOrientation_LittleEndian(t *testing.T) {
+	tiff := []byte("II\x2a\x00\x08\x00\x00\x00\x01\x00\x12\x01\x03\x00\x01\x00\x00\x00\x06\x00\x00\x00\x00\x00\x00\x00")
+	orientation, err := exifOrientation(tiff)
+	require.NoError(t, err)
+	assert.Equal(t, 6, orientation)
+
+	_, err = exifOrientation([]byte("not tiff"))
+	assert.ErrorIs(t, err, errNoOrientation)
+}
@@ -0,0 +1,103 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Downloads are written to download_<random><ext> in the cache directory before they are cached
+const downloadTempPrefix = "download_"
+
+// RemoveUnreferencedFiles deletes the cached files that are not in referenced and have not been
+// written or, with media.persistIndex, reused within gracePeriod. The grace period keeps media
+// that is being relayed, whose message mapping is saved only after it is sent. Downloads in
+// progress, partial downloads and the cache index are never removed. It returns the number of
+// files deleted.
+func (h *handler) RemoveUnreferencedFiles(referenced []string, gracePeriod time.Duration) (int, error) {
+	keep := make(map[string]bool, len(referenced))
+	for _, path := range referenced {
+		keep[absPath(path)] = true
+	}
+
+	var lastAccess map[string]time.Time
+	if h.index != nil {
+		lastAccess = h.index.lastAccessByPath()
+	}
+
+	removed, err := h.removeUnreferencedInDir(h.cacheDir, keep, lastAccess, time.Now().Add(-gracePeriod), true)
+	if h.index != nil && len(removed) > 0 {
+		if forgetErr := h.index.forget(removed); forgetErr != nil && err == nil {
+			err = forgetErr
+		}
+	}
+	return len(removed), err
+}
+
+// removeUnreferencedInDir deletes the unreferenced files in dir last used before cutoff and
+// returns their paths. In the cache directory itself it also sweeps the session subdirectories.
+func (h *handler) removeUnreferencedInDir(dir string, keep map[string]bool, lastAccess map[string]time.Time, cutoff time.Time, withSessionDirs bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var removed []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if withSessionDirs {
+				sessionRemoved, err := h.removeUnreferencedInDir(path, keep, lastAccess, cutoff, false)
+				removed = append(removed, sessionRemoved...)
+				if err != nil {
+					return removed, err
+				}
+			}
+			continue
+		}
+		if isCacheWorkFile(entry.Name()) || keep[absPath(path)] {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return removed, fmt.Errorf("failed to get file info: %w", err)
+		}
+		used := info.ModTime()
+		if accessed, ok := lastAccess[path]; ok && accessed.After(used) {
+			used = accessed
+		}
+		if !used.Before(cutoff) {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("failed to remove orphaned file: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// isCacheWorkFile reports whether name is a file of the cache that is not cached media: the
+// index, a download being written or a partial download kept for resuming
+func isCacheWorkFile(name string) bool {
+	return isCacheIndexFile(name) ||
+		strings.HasPrefix(name, downloadTempPrefix) ||
+		(strings.HasPrefix(name, partialDownloadPrefix) && strings.HasSuffix(name, partialDownloadSuffix))
+}
+
+// absPath returns path cleaned and made absolute, so paths stored in different forms compare
+// equal; it falls back to the cleaned path when the working directory is unknown
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
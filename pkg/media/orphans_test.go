@@ -0,0 +1,86 @@
+package media
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveUnreferencedFiles(t *testing.T) {
+	for _, persistIndex := range []bool{false, true} {
+		name := "directory scan"
+		if persistIndex {
+			name = "index"
+		}
+		t.Run(name, func(t *testing.T) {
+			cacheDir := filepath.Join(t.TempDir(), "cache")
+			h := newSessionDirsTestHandler(t, cacheDir, persistIndex)
+			sessionDir := filepath.Join(cacheDir, "personal")
+
+			writeAgedFiles(t, cacheDir, map[string]int{
+				"referenced.jpg":   3,
+				"orphan.jpg":       3,
+				"recent.jpg":       0,
+				"partial_abc.part": 3,
+				"download_123.jpg": 3,
+			})
+			writeAgedFiles(t, sessionDir, map[string]int{
+				"referenced.pdf": 3,
+				"orphan.pdf":     3,
+			})
+
+			referenced := []string{
+				filepath.Join(cacheDir, "referenced.jpg"),
+				// Paths are compared after cleaning
+				filepath.Join(cacheDir, "personal", "..", "personal", "referenced.pdf"),
+				"/signal/attachments/not-in-the-cache.jpg",
+			}
+			removed, err := h.RemoveUnreferencedFiles(referenced, 24*time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, 2, removed)
+
+			assert.NoFileExists(t, filepath.Join(cacheDir, "orphan.jpg"))
+			assert.NoFileExists(t, filepath.Join(sessionDir, "orphan.pdf"))
+			for _, kept := range []string{"referenced.jpg", "recent.jpg", "partial_abc.part", "download_123.jpg", "personal/referenced.pdf"} {
+				assert.FileExists(t, filepath.Join(cacheDir, kept))
+			}
+			if persistIndex {
+				assert.FileExists(t, filepath.Join(cacheDir, cacheIndexFileName))
+			}
+		})
+	}
+}
+
+func TestRemoveUnreferencedFiles_RecentlyReusedIsKept(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	h := newSessionDirsTestHandler(t, cacheDir, true)
+
+	sourcePath := filepath.Join(tmpDir, "photo.jpg")
+	require.NoError(t, os.WriteFile(sourcePath, []byte("photo"), 0644))
+	cachedPath, err := h.ProcessMedia(context.Background(), sourcePath)
+	require.NoError(t, err)
+	oldTime := time.Now().Add(-72 * time.Hour)
+	require.NoError(t, os.Chtimes(cachedPath, oldTime, oldTime))
+
+	// A dedup hit is about to be relayed, so its mapping does not exist yet
+	_, err = h.ProcessMedia(context.Background(), sourcePath)
+	require.NoError(t, err)
+
+	removed, err := h.RemoveUnreferencedFiles(nil, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+	assert.FileExists(t, cachedPath)
+
+	removed, err = h.RemoveUnreferencedFiles(nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoFileExists(t, cachedPath)
+	count, _, _ := h.index.stats()
+	assert.Zero(t, count, "removed files are dropped from the index")
+}
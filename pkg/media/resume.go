@@ -0,0 +1,212 @@
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/media"
+	"whatsignal/internal/metrics"
+)
+
+// Partial downloads are kept in the cache directory as partial_<sha256 of the URL>.part
+const (
+	partialDownloadPrefix = "partial_"
+	partialDownloadSuffix = ".part"
+)
+
+// partialDownloadPath returns the file a resumable download of mediaURL writes to. It is named
+// by the URL's hash so that a later delivery of the same media resumes where this one stopped.
+func (h *handler) partialDownloadPath(mediaURL string) string {
+	return filepath.Join(h.cacheDir, fmt.Sprintf("%s%x%s", partialDownloadPrefix, sha256.Sum256([]byte(mediaURL)), partialDownloadSuffix))
+}
+
+// claimPartialDownload reserves the partial file of mediaURL for one download. It reports false
+// when another download of the same URL holds it; the returned func releases it.
+func (h *handler) claimPartialDownload(mediaURL string) (func(), bool) {
+	path := h.partialDownloadPath(mediaURL)
+	h.partialsMu.Lock()
+	defer h.partialsMu.Unlock()
+	if h.partials[path] {
+		return nil, false
+	}
+	if h.partials == nil {
+		h.partials = make(map[string]bool)
+	}
+	h.partials[path] = true
+	return func() {
+		h.partialsMu.Lock()
+		delete(h.partials, path)
+		h.partialsMu.Unlock()
+	}, true
+}
+
+// downloadResumable downloads mediaURL into its partial file. When the connection drops, the
+// download is retried up to media.downloadResumeAttempts times with a Range request for the
+// missing bytes. A server that ignores the range sends the whole file again, which replaces
+// the partial file. If every attempt fails the partial file is kept for the next delivery.
+func (h *handler) downloadResumable(ctx context.Context, client *http.Client, mediaURL string, router media.Router) (string, string, error) {
+	partialPath := h.partialDownloadPath(mediaURL)
+	var ext, validator string
+
+	for attempt := 0; ; attempt++ {
+		canRetry := attempt < h.config.DownloadResumeAttempts && ctx.Err() == nil
+
+		var offset int64
+		if info, err := os.Stat(partialPath); err == nil {
+			offset = info.Size()
+		}
+
+		resp, err := h.requestRange(ctx, client, mediaURL, offset, validator)
+		if err != nil {
+			if canRetry {
+				continue
+			}
+			return "", "", fmt.Errorf("failed to download file: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusPartialContent && contentRangeStart(resp.Header.Get("Content-Range")) == offset:
+		case resp.StatusCode == http.StatusOK:
+			offset = 0 // The range was ignored; start over
+		case resp.StatusCode == http.StatusPartialContent || resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+			// The partial file does not match what the server has
+			_ = resp.Body.Close()
+			_ = os.Remove(partialPath)
+			if canRetry {
+				continue
+			}
+			return "", "", fmt.Errorf("download failed with status: %d", resp.StatusCode)
+		default:
+			_ = resp.Body.Close()
+			return "", "", fmt.Errorf("download failed with status: %d", resp.StatusCode)
+		}
+
+		switch {
+		case offset == 0:
+			ext = h.getFileExtensionFromResponse(resp, mediaURL)
+		case ext == "":
+			// The body continues the partial file, so its start is no content signature
+			ext = h.detectExtension(resp.Header.Get("Content-Type"), mediaURL, nil)
+		}
+		if validator == "" || offset == 0 {
+			validator = rangeValidator(resp)
+		}
+
+		mediaType := router.GetMediaType("file." + strings.TrimPrefix(ext, "."))
+		maxSizeBytes := router.GetMaxSizeForMediaType(mediaType)
+		size, err := writePartial(partialPath, resp.Body, offset, maxSizeBytes+1)
+		_ = resp.Body.Close()
+		if size > maxSizeBytes {
+			_ = os.Remove(partialPath)
+			return "", "", &OversizeError{MediaType: mediaType, Size: size, Limit: maxSizeBytes}
+		}
+		if err != nil {
+			var writeErr *partialWriteError
+			if !errors.As(err, &writeErr) && canRetry {
+				metrics.IncrementCounter("media_download_resumes_total", nil, "Media URL downloads resumed after the connection dropped")
+				continue
+			}
+			return "", "", fmt.Errorf("failed to save downloaded file: %w", err)
+		}
+
+		return partialPath, strings.TrimPrefix(ext, "."), nil
+	}
+}
+
+// requestRange requests mediaURL from offset onwards. validator, an ETag or Last-Modified value
+// from an earlier response, is sent as If-Range so a changed file is sent whole.
+func (h *handler) requestRange(ctx context.Context, client *http.Client, mediaURL string, offset int64, validator string) (*http.Response, error) {
+	req, err := h.newDownloadRequest(ctx, mediaURL)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if validator != "" {
+			req.Header.Set("If-Range", validator)
+		}
+	}
+	return client.Do(req) // #nosec G704 - URL validated by validateDownloadURL in downloadFromURL
+}
+
+// partialWriteError is a failure to write the partial file, as opposed to reading the response
+type partialWriteError struct {
+	err error
+}
+
+func (e *partialWriteError) Error() string { return e.err.Error() }
+func (e *partialWriteError) Unwrap() error { return e.err }
+
+// writePartial writes body to path at offset, truncating anything after it, and returns the
+// file's size. At most limit bytes are written in total.
+func writePartial(path string, body io.Reader, offset, limit int64) (int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, constants.DefaultFilePermissions) // #nosec G304 - Path built by partialDownloadPath
+	if err != nil {
+		return 0, &partialWriteError{err: err}
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := file.Truncate(offset); err != nil {
+		return 0, &partialWriteError{err: err}
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, &partialWriteError{err: err}
+	}
+
+	written, err := io.Copy(file, io.LimitReader(body, max(limit-offset, 0)))
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		err = &partialWriteError{err: err}
+	}
+	return offset + written, err
+}
+
+// contentRangeStart returns the first byte position of a "bytes start-end/size" Content-Range
+// header, or -1 when it cannot be parsed
+func contentRangeStart(contentRange string) int64 {
+	spec, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// rangeValidator returns the strong ETag or Last-Modified of a response for If-Range
+func rangeValidator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// removeStalePartialDownloads deletes partial downloads not written to within maxAge
+func (h *handler) removeStalePartialDownloads(maxAge time.Duration) {
+	paths, err := filepath.Glob(filepath.Join(h.cacheDir, partialDownloadPrefix+"*"+partialDownloadSuffix))
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && now.Sub(info.ModTime()) > maxAge {
+			_ = os.Remove(path)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyMediaServer serves content, dropping the connection after dropAfter bytes for the first
+// drops requests. With honorRange it answers Range requests with 206; otherwise it ignores them.
+type flakyMediaServer struct {
+	content    []byte
+	dropAfter  int
+	drops      int
+	honorRange bool
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (s *flakyMediaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Clone(r.Context()))
+	drop := s.drops > 0
+	s.drops--
+	s.mu.Unlock()
+
+	body := s.content
+	status := http.StatusOK
+	w.Header().Set("Content-Type", "video/mp4")
+	w.Header().Set("ETag", `"v1"`)
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" && s.honorRange {
+		var start int
+		_, err := fmt.Sscanf(rangeHeader, "bytes=%d-", &start)
+		if err != nil || start >= len(s.content) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		body = s.content[start:]
+		status = http.StatusPartialContent
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(s.content)-1, len(s.content)))
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+
+	if drop && len(body) > s.dropAfter {
+		// Writing less than Content-Length makes the server close the connection mid-body
+		_, _ = w.Write(body[:s.dropAfter])
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+func (s *flakyMediaServer) rangeHeaders() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var headers []string
+	for _, r := range s.requests {
+		headers = append(headers, r.Header.Get("Range"))
+	}
+	return headers
+}
+
+func setupResumeTest(t *testing.T, attempts int, mediaServer *flakyMediaServer) (*handler, string) {
+	t.Helper()
+	handlerInterface, _, cleanup := setupTestHandler(t)
+	t.Cleanup(cleanup)
+	h := handlerInterface.(*handler)
+	h.config.DownloadResumeAttempts = attempts
+
+	server := httptest.NewServer(mediaServer)
+	t.Cleanup(server.Close)
+	h.wahaBaseURL = server.URL
+	return h, server.URL + "/large.mp4"
+}
+
+func TestProcessMediaFromURL_ResumesDroppedDownloadWithRange(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	mediaServer := &flakyMediaServer{content: content, dropAfter: 4000, drops: 2, honorRange: true}
+	h, mediaURL := setupResumeTest(t, 3, mediaServer)
+
+	cachedPath, err := h.ProcessMedia(context.Background(), mediaURL)
+	require.NoError(t, err)
+
+	cached, err := os.ReadFile(cachedPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, cached)
+	assert.Equal(t, []string{"", "bytes=4000-", "bytes=8000-"}, mediaServer.rangeHeaders())
+	assert.Equal(t, `"v1"`, mediaServer.requests[1].Header.Get("If-Range"))
+	assert.NoFileExists(t, h.partialDownloadPath(mediaURL), "the partial file is removed once processed")
+}
+
+func TestProcessMediaFromURL_FullRedownloadWhenRangeUnsupported(t *testing.T) {
+	content := bytes.Repeat([]byte("abcdefghij"), 1000)
+	mediaServer := &flakyMediaServer{content: content, dropAfter: 4000, drops: 1}
+	h, mediaURL := setupResumeTest(t, 3, mediaServer)
+
+	cachedPath, err := h.ProcessMedia(context.Background(), mediaURL)
+	require.NoError(t, err)
+
+	cached, err := os.ReadFile(cachedPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, cached, "the whole file sent again replaces the partial one")
+	assert.Equal(t, []string{"", "bytes=4000-"}, mediaServer.rangeHeaders())
+}
+
+func TestProcessMediaFromURL_PartialKeptForNextDelivery(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	mediaServer := &flakyMediaServer{content: content, dropAfter: 3000, drops: 2, honorRange: true}
+	h, mediaURL := setupResumeTest(t, 1, mediaServer)
+
+	_, err := h.ProcessMedia(context.Background(), mediaURL)
+	require.Error(t, err)
+	info, err := os.Stat(h.partialDownloadPath(mediaURL))
+	require.NoError(t, err)
+	assert.Equal(t, int64(6000), info.Size())
+
+	cachedPath, err := h.ProcessMedia(context.Background(), mediaURL)
+	require.NoError(t, err)
+	cached, err := os.ReadFile(cachedPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, cached)
+	assert.Equal(t, []string{"", "bytes=3000-", "bytes=6000-"}, mediaServer.rangeHeaders())
+}
+
+func TestProcessMediaFromURL_StalePartialIsDiscarded(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	mediaServer := &flakyMediaServer{content: content, honorRange: true}
+	h, mediaURL := setupResumeTest(t, 2, mediaServer)
+	require.NoError(t, os.WriteFile(h.partialDownloadPath(mediaURL), bytes.Repeat([]byte("x"), 2000), 0600))
+
+	cachedPath, err := h.ProcessMedia(context.Background(), mediaURL)
+	require.NoError(t, err)
+	cached, err := os.ReadFile(cachedPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, cached)
+	assert.Equal(t, []string{"bytes=2000-", ""}, mediaServer.rangeHeaders())
+}
+
+func TestProcessMediaFromURL_DroppedDownloadFailsWithoutResume(t *testing.T) {
+	mediaServer := &flakyMediaServer{content: bytes.Repeat([]byte("0123456789"), 1000), dropAfter: 4000, drops: 1, honorRange: true}
+	h, mediaURL := setupResumeTest(t, 0, mediaServer)
+
+	_, err := h.ProcessMedia(context.Background(), mediaURL)
+	require.Error(t, err)
+	assert.Len(t, mediaServer.rangeHeaders(), 1)
+	assert.NoFileExists(t, h.partialDownloadPath(mediaURL))
+}
+
+func TestClaimPartialDownload(t *testing.T) {
+	handlerInterface, _, cleanup := setupTestHandler(t)
+	defer cleanup()
+	h := handlerInterface.(*handler)
+
+	release, ok := h.claimPartialDownload("http://waha/a.mp4")
+	require.True(t, ok)
+	_, ok = h.claimPartialDownload("http://waha/a.mp4")
+	assert.False(t, ok, "a second download of the same URL does not share the partial file")
+	_, ok = h.claimPartialDownload("http://waha/b.mp4")
+	assert.True(t, ok)
+
+	release()
+	_, ok = h.claimPartialDownload("http://waha/a.mp4")
+	assert.True(t, ok)
+}
+
+func TestContentRangeStart(t *testing.T) {
+	assert.Equal(t, int64(4000), contentRangeStart("bytes 4000-9999/10000"))
+	assert.Equal(t, int64(0), contentRangeStart("bytes 0-9/10"))
+	assert.Equal(t, int64(-1), contentRangeStart("bytes */10000"))
+	assert.Equal(t, int64(-1), contentRangeStart(""))
+}
@@ -0,0 +1,43 @@
+package media
+
+import (
+	"time"
+
+	"whatsignal/internal/constants"
+)
+
+// retentionFor returns how long the cached file at path is kept. A media type configured in
+// retentionByType overrides maxAge for its extensions; when an extension is listed under several
+// types the shortest retention wins. Extensions not listed under any type count as documents,
+// since that is how they are sent.
+func (h *handler) retentionFor(path string, maxAge time.Duration) time.Duration {
+	retention := h.config.RetentionByType
+	var days []int
+	if h.mediaRouter.IsImageAttachment(path) {
+		days = append(days, retention.Image)
+	}
+	if h.mediaRouter.IsVideoAttachment(path) {
+		days = append(days, retention.Video)
+	}
+	if h.mediaRouter.IsVoiceAttachment(path) {
+		days = append(days, retention.Voice)
+	}
+	if h.mediaRouter.IsDocumentAttachment(path) || len(days) == 0 {
+		days = append(days, retention.Document)
+	}
+
+	shortest := time.Duration(0)
+	for _, d := range days {
+		if d <= 0 {
+			continue
+		}
+		age := time.Duration(d*constants.SecondsPerDay) * time.Second
+		if shortest == 0 || age < shortest {
+			shortest = age
+		}
+	}
+	if shortest == 0 {
+		return maxAge
+	}
+	return shortest
+}
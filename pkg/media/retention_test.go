@@ -0,0 +1,90 @@
+package media
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"whatsignal/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAgedFiles writes each named file into dir with a modification time daysOld in the past
+func writeAgedFiles(t *testing.T, dir string, files map[string]int) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0750))
+	for name, daysOld := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+		modTime := time.Now().Add(-time.Duration(daysOld) * 24 * time.Hour)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+}
+
+func retentionTestConfig() models.MediaConfig {
+	config := getTestMediaConfig()
+	// gif is listed as both an image and a video
+	config.AllowedTypes.Video = append(config.AllowedTypes.Video, "gif")
+	config.RetentionByType = models.MediaRetention{Image: 30, Video: 3}
+	return config
+}
+
+func TestCleanupOldFiles_RetentionByType(t *testing.T) {
+	tests := []struct {
+		name         string
+		persistIndex bool
+	}{
+		{name: "directory scan"},
+		{name: "cache index", persistIndex: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := filepath.Join(t.TempDir(), "cache")
+			writeAgedFiles(t, cacheDir, map[string]int{
+				"old-image.jpg":   20, // image retention 30 days: kept
+				"old-video.mp4":   4,  // video retention 3 days: removed
+				"new-video.mov":   2,  // kept
+				"both.gif":        4,  // image and video: shortest (3 days) applies, removed
+				"old-voice.ogg":   10, // no override, global 7 days: removed
+				"new-voice.ogg":   5,  // kept
+				"old-notes.pdf":   8,  // no override: removed
+				"unlisted.zip":    6,  // treated as a document, global 7 days: kept
+				"ancient-img.png": 31, // past the image retention: removed
+			})
+
+			config := retentionTestConfig()
+			config.PersistIndex = tt.persistIndex
+			h, err := NewHandler(cacheDir, config)
+			require.NoError(t, err)
+
+			require.NoError(t, h.CleanupOldFiles(7*24*60*60))
+
+			for _, kept := range []string{"old-image.jpg", "new-video.mov", "new-voice.ogg", "unlisted.zip"} {
+				assert.FileExists(t, filepath.Join(cacheDir, kept))
+			}
+			for _, removed := range []string{"old-video.mp4", "both.gif", "old-voice.ogg", "old-notes.pdf", "ancient-img.png"} {
+				assert.NoFileExists(t, filepath.Join(cacheDir, removed))
+			}
+		})
+	}
+}
+
+func TestRetentionFor(t *testing.T) {
+	config := retentionTestConfig()
+	config.RetentionByType.Document = 14
+	h, err := NewHandler(t.TempDir(), config)
+	require.NoError(t, err)
+	handler := h.(*handler)
+
+	week := 7 * 24 * time.Hour
+	day := 24 * time.Hour
+	assert.Equal(t, 30*day, handler.retentionFor("a.jpg", week))
+	assert.Equal(t, 3*day, handler.retentionFor("a.gif", week))
+	assert.Equal(t, week, handler.retentionFor("a.ogg", week))
+	assert.Equal(t, 14*day, handler.retentionFor("a.pdf", week))
+	assert.Equal(t, 14*day, handler.retentionFor("a.zip", week))
+}
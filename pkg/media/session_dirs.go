@@ -0,0 +1,58 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/media"
+	"whatsignal/internal/models"
+)
+
+// unsafeSessionDirChars matches the characters of a session name not used in its directory name
+var unsafeSessionDirChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// sessionDirName returns the name of the cache subdirectory for sessionName
+func sessionDirName(sessionName string) string {
+	return unsafeSessionDirChars.ReplaceAllString(sessionName, "_")
+}
+
+// ProcessMediaForSession is ProcessMedia for media relayed by sessionName. With
+// media.sessionDirs the cached file is stored in a subdirectory of the cache named after the
+// session, so each session's media can be handled and deleted on its own; otherwise, or
+// without a session, the cache stays flat.
+func (h *handler) ProcessMediaForSession(ctx context.Context, pathOrURL, sessionName string) (string, error) {
+	return h.processMediaForSession(ctx, pathOrURL, sessionName, h.mediaRouter)
+}
+
+// ProcessMediaWithConfig is ProcessMediaForSession with the media types and size limits of
+// config, the media configuration of the session's channel, instead of the global ones
+func (h *handler) ProcessMediaWithConfig(ctx context.Context, pathOrURL, sessionName string, config models.MediaConfig) (string, error) {
+	return h.processMediaForSession(ctx, pathOrURL, sessionName, media.NewRouter(config))
+}
+
+func (h *handler) processMediaForSession(ctx context.Context, pathOrURL, sessionName string, router media.Router) (string, error) {
+	if !h.config.SessionDirs || sessionName == "" {
+		return h.processMedia(ctx, pathOrURL, h.cacheDir, router)
+	}
+
+	dir := filepath.Join(h.cacheDir, sessionDirName(sessionName))
+	if err := os.MkdirAll(dir, constants.DefaultDirectoryPermissions); err != nil {
+		return "", fmt.Errorf("failed to create session cache directory: %w", err)
+	}
+	return h.processMedia(ctx, pathOrURL, dir, router)
+}
+
+// indexKey returns the cache index key of the file with content hash at cachedPath. Files in the
+// cache directory itself are keyed by hash; files in a session directory by "<dir>/<hash>", so
+// the same media cached for two sessions has an entry for each copy.
+func indexKey(cacheDir, hash, cachedPath string) string {
+	dir := filepath.Dir(cachedPath)
+	if dir == filepath.Clean(cacheDir) {
+		return hash
+	}
+	return filepath.Base(dir) + "/" + hash
+}
@@ -0,0 +1,145 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSessionDirsTestHandler(t *testing.T, cacheDir string, persistIndex bool) *handler {
+	t.Helper()
+	config := getTestMediaConfig()
+	config.SessionDirs = true
+	config.PersistIndex = persistIndex
+	h, err := NewHandler(cacheDir, config)
+	require.NoError(t, err)
+	return h.(*handler)
+}
+
+func TestSessionDirName(t *testing.T) {
+	assert.Equal(t, "personal", sessionDirName("personal"))
+	assert.Equal(t, "my_session_", sessionDirName("my session!"))
+	assert.Equal(t, "___", sessionDirName("../"))
+}
+
+func TestProcessMediaForSession_SeparatesSessions(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	sourcePath := filepath.Join(tmpDir, "source.jpg")
+	require.NoError(t, os.WriteFile(sourcePath, []byte("shared photo"), 0644))
+
+	h := newSessionDirsTestHandler(t, cacheDir, false)
+	personal, err := h.ProcessMediaForSession(context.Background(), sourcePath, "personal")
+	require.NoError(t, err)
+	work, err := h.ProcessMediaForSession(context.Background(), sourcePath, "work")
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(cacheDir, "personal"), filepath.Dir(personal))
+	assert.Equal(t, filepath.Join(cacheDir, "work"), filepath.Dir(work))
+	assert.FileExists(t, personal)
+	assert.FileExists(t, work)
+
+	// Without a session the cache stays flat
+	flat, err := h.ProcessMediaForSession(context.Background(), sourcePath, "")
+	require.NoError(t, err)
+	assert.Equal(t, cacheDir, filepath.Dir(flat))
+
+	stats, err := h.CacheStats()
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Files)
+}
+
+func TestProcessMediaForSession_FlatByDefault(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	sourcePath := filepath.Join(tmpDir, "source.jpg")
+	require.NoError(t, os.WriteFile(sourcePath, []byte("photo"), 0644))
+
+	h, err := NewHandler(cacheDir, getTestMediaConfig())
+	require.NoError(t, err)
+	cachedPath, err := h.ProcessMediaForSession(context.Background(), sourcePath, "personal")
+	require.NoError(t, err)
+	assert.Equal(t, cacheDir, filepath.Dir(cachedPath))
+}
+
+func TestCleanupOldFiles_SessionDirs(t *testing.T) {
+	for _, persistIndex := range []bool{false, true} {
+		name := "directory scan"
+		if persistIndex {
+			name = "index"
+		}
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cacheDir := filepath.Join(tmpDir, "cache")
+			h := newSessionDirsTestHandler(t, cacheDir, persistIndex)
+
+			var old, recent []string
+			for _, session := range []string{"personal", "work"} {
+				for i, content := range []string{"old " + session, "recent " + session} {
+					// Cached files may be hard links, so each needs a source of its own
+					sourcePath := filepath.Join(tmpDir, fmt.Sprintf("%s-%d.jpg", session, i))
+					require.NoError(t, os.WriteFile(sourcePath, []byte(content), 0644))
+					cachedPath, err := h.ProcessMediaForSession(context.Background(), sourcePath, session)
+					require.NoError(t, err)
+					if content == "old "+session {
+						old = append(old, cachedPath)
+					} else {
+						recent = append(recent, cachedPath)
+					}
+				}
+			}
+
+			oldTime := time.Now().Add(-8 * 24 * time.Hour)
+			for _, path := range old {
+				require.NoError(t, os.Chtimes(path, oldTime, oldTime))
+			}
+			// A fresh handler rebuilds its index from the files' modification times
+			if persistIndex {
+				require.NoError(t, os.Remove(filepath.Join(cacheDir, cacheIndexFileName)))
+				h = newSessionDirsTestHandler(t, cacheDir, true)
+				assert.Len(t, h.index.entries, 4)
+			}
+
+			require.NoError(t, h.CleanupOldFiles(7*24*60*60))
+			for _, path := range old {
+				assert.NoFileExists(t, path)
+			}
+			for _, path := range recent {
+				assert.FileExists(t, path)
+			}
+		})
+	}
+}
+
+func TestProcessMediaWithConfig_ChannelLimits(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	sourcePath := filepath.Join(tmpDir, "report.pdf")
+	require.NoError(t, os.WriteFile(sourcePath, make([]byte, 3*1024*1024), 0644))
+
+	h := newSessionDirsTestHandler(t, cacheDir, false)
+
+	business := h.config
+	business.MaxSizeMB.Document = 200
+	cached, err := h.ProcessMediaWithConfig(context.Background(), sourcePath, "business", business)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(cacheDir, "business"), filepath.Dir(cached))
+
+	personal := h.config
+	personal.MaxSizeMB.Document = 2
+	_, err = h.ProcessMediaWithConfig(context.Background(), sourcePath, "personal", personal)
+	var oversize *OversizeError
+	require.ErrorAs(t, err, &oversize)
+	assert.Equal(t, "document", oversize.MediaType)
+	assert.Equal(t, int64(2*1024*1024), oversize.Limit)
+
+	// The global limits are untouched
+	_, err = h.ProcessMediaForSession(context.Background(), sourcePath, "personal")
+	require.NoError(t, err)
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 	"whatsignal/internal/constants"
 	"whatsignal/internal/httputil"
+	"whatsignal/internal/media"
 	"whatsignal/internal/metrics"
 	"whatsignal/internal/privacy"
 	"whatsignal/internal/security"
@@ -27,11 +28,14 @@ import (
 )
 
 type Client interface {
-	SendMessage(ctx context.Context, recipient, message string, attachments []string) (*types.SendMessageResponse, error)
+	SendMessage(ctx context.Context, recipient, message string, attachments []string, opts ...types.SendOption) (*types.SendMessageResponse, error)
 	ReceiveMessages(ctx context.Context, timeoutSeconds int) ([]types.SignalMessage, error)
 	InitializeDevice(ctx context.Context) error
 	DownloadAttachment(ctx context.Context, attachmentID string) ([]byte, error)
 	ListAttachments(ctx context.Context) ([]string, error)
+	SetExpiration(ctx context.Context, recipient string, expiration time.Duration) error
+	SendReaction(ctx context.Context, recipient, emoji, targetAuthor string, targetTimestamp int64, remove bool) error
+	GetContactProfile(ctx context.Context, number string) (*types.ContactProfile, error)
 	DetectedMode() string
 }
 
@@ -53,6 +57,7 @@ type SignalClient struct {
 	initialized        bool   // Tracks whether InitializeDevice succeeded
 	initError          string // Stores initialization error message if any
 	detectedMode       string // Mode reported by signal-cli /v1/about ("native", "json-rpc", etc.)
+	strictAccountMatch bool   // Drop envelopes whose account is not phoneNumber
 }
 
 func NewClient(baseURL, phoneNumber, deviceName, attachmentsDir string, httpClient *http.Client) Client {
@@ -107,12 +112,15 @@ func (c *SignalClient) doPollRequestWithCircuitBreaker(ctx context.Context, req
 	return c.doRequestWithCB(ctx, req, c.pollCircuitBreaker)
 }
 
-func (c *SignalClient) SendMessage(ctx context.Context, recipient, message string, attachments []string) (*types.SendMessageResponse, error) {
+func (c *SignalClient) SendMessage(ctx context.Context, recipient, message string, attachments []string, opts ...types.SendOption) (*types.SendMessageResponse, error) {
 	payload := types.SendMessageRequest{
 		Message:    message,
 		Number:     c.phoneNumber,
 		Recipients: []string{recipient},
 	}
+	for _, opt := range opts {
+		opt(&payload)
+	}
 
 	if len(attachments) > 0 {
 		payload.Base64Attachments = make([]string, len(attachments))
@@ -251,9 +259,9 @@ func (c *SignalClient) ReceiveMessages(ctx context.Context, timeoutSeconds int)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var messages []types.RestMessage
-	if err := json.Unmarshal(bodyBytes, &messages); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	messages, err := c.decodeEnvelopes(bodyBytes)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(messages) > 0 {
@@ -345,6 +353,9 @@ func (c *SignalClient) ReceiveMessages(ctx context.Context, timeoutSeconds int)
 
 	result := make([]types.SignalMessage, 0, len(messages))
 	for _, msg := range messages {
+		if !c.acceptsAccount(msg) {
+			continue
+		}
 		if msg.Envelope.DataMessage != nil {
 			sigMsg := c.convertDataMessageToSignalMessage(ctx, msg)
 			if sigMsg.QuotedMessage == nil && msg.Envelope.SyncMessage != nil && msg.Envelope.SyncMessage.SentMessage != nil {
@@ -365,6 +376,12 @@ func (c *SignalClient) ReceiveMessages(ctx context.Context, timeoutSeconds int)
 			result = append(result, c.convertReceiptMessageToSignalMessages(msg)...)
 			continue
 		}
+		if msg.Envelope.TypingMessage != nil {
+			if sigMsg := c.convertTypingMessageToSignalMessage(msg); sigMsg != nil {
+				result = append(result, *sigMsg)
+			}
+			continue
+		}
 	}
 
 	return result, nil
@@ -393,6 +410,46 @@ func quotedMessageFromRestQuote(quote *types.RestMessageQuote) *struct {
 	}
 }
 
+// decodeEnvelopes decodes a /v1/receive response. Each envelope is decoded on its own: a
+// malformed one is logged and skipped rather than failing the batch, as the receive already
+// consumed the envelopes and a failure would drop the valid ones with it.
+func (c *SignalClient) decodeEnvelopes(body []byte) ([]types.RestMessage, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	messages := make([]types.RestMessage, 0, len(raw))
+	for i, envelope := range raw {
+		var msg types.RestMessage
+		if err := json.Unmarshal(envelope, &msg); err != nil {
+			c.logger.WithError(err).WithField("index", i).Warn("Skipping malformed Signal envelope")
+			metrics.IncrementCounter("signal_malformed_envelopes_total", nil, "Signal envelopes skipped because they could not be decoded")
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func mentionsFromRest(mentions []types.RestMessageMention) []types.SignalMention {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	result := make([]types.SignalMention, 0, len(mentions))
+	for _, mention := range mentions {
+		result = append(result, types.SignalMention{
+			Start:  mention.Start,
+			Length: mention.Length,
+			Number: mention.Number,
+			UUID:   mention.UUID,
+			Name:   mention.Name,
+		})
+	}
+	return result
+}
+
 func receiptMessageFromInterface(raw interface{}) (*types.RestReceiptMessage, error) {
 	if raw == nil {
 		return nil, nil
@@ -447,6 +504,63 @@ func (c *SignalClient) convertReceiptMessageToSignalMessages(msg types.RestMessa
 	return result
 }
 
+func typingMessageFromInterface(raw interface{}) (*types.RestTypingMessage, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal typing message: %w", err)
+	}
+
+	var typing types.RestTypingMessage
+	if err := json.Unmarshal(data, &typing); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal typing message: %w", err)
+	}
+
+	return &typing, nil
+}
+
+// convertTypingMessageToSignalMessage converts a typing envelope to a SignalMessage carrying
+// only Typing. It returns nil if the envelope cannot be parsed or names no action.
+func (c *SignalClient) convertTypingMessageToSignalMessage(msg types.RestMessage) *types.SignalMessage {
+	typing, err := typingMessageFromInterface(msg.Envelope.TypingMessage)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.WithError(err).Warn("Failed to parse Signal typing message")
+		}
+		return nil
+	}
+	if typing == nil || typing.Action == "" {
+		return nil
+	}
+
+	return &types.SignalMessage{
+		Timestamp: msg.Envelope.Timestamp,
+		Sender:    msg.Envelope.Source,
+		MessageID: fmt.Sprintf("%d", msg.Envelope.Timestamp),
+		GroupID:   typing.GroupID,
+		Typing: &types.TypingInfo{
+			Action:    typing.Action,
+			Timestamp: typing.Timestamp.Int64(),
+			GroupID:   typing.GroupID,
+		},
+	}
+}
+
+// editFromTargetTimestamp returns the edit a message with the given editTargetTimestamp makes,
+// or nil for a new message
+func editFromTargetTimestamp(target int64) *types.SignalEdit {
+	if target == 0 {
+		return nil
+	}
+	return &types.SignalEdit{
+		TargetMessageID: fmt.Sprintf("%d", target),
+		TargetTimestamp: target,
+	}
+}
+
 // convertDataMessageToSignalMessage converts an incoming DataMessage to a SignalMessage
 func (c *SignalClient) convertDataMessageToSignalMessage(ctx context.Context, msg types.RestMessage) types.SignalMessage {
 	sigMsg := types.SignalMessage{
@@ -465,7 +579,13 @@ func (c *SignalClient) convertDataMessageToSignalMessage(ctx context.Context, ms
 		}
 	}
 
+	sigMsg.Edit = editFromTargetTimestamp(msg.Envelope.DataMessage.EditTargetTimestamp)
 	sigMsg.QuotedMessage = quotedMessageFromRestQuote(msg.Envelope.DataMessage.GetQuote())
+	sigMsg.Mentions = mentionsFromRest(msg.Envelope.DataMessage.Mentions)
+
+	if groupInfo := msg.Envelope.DataMessage.GroupInfo; groupInfo != nil {
+		sigMsg.GroupID = groupInfo.GroupID
+	}
 
 	if msg.Envelope.DataMessage.Reaction != nil {
 		sigMsg.Reaction = &types.SignalReaction{
@@ -523,7 +643,9 @@ func (c *SignalClient) convertSyncMessageToSignalMessage(ctx context.Context, ms
 		sigMsg.Sender = "group." + sent.GroupInfo.GroupID
 	}
 
+	sigMsg.Edit = editFromTargetTimestamp(sent.GetEditTargetTimestamp())
 	sigMsg.QuotedMessage = quotedMessageFromRestQuote(sent.GetQuote())
+	sigMsg.Mentions = mentionsFromRest(sent.GetMentions())
 
 	if sent.Reaction != nil {
 		sigMsg.Reaction = &types.SignalReaction{
@@ -806,6 +928,9 @@ func (c *SignalClient) DetectedMode() string {
 func (c *SignalClient) ConvertRestMessages(ctx context.Context, messages []types.RestMessage) []types.SignalMessage {
 	result := make([]types.SignalMessage, 0, len(messages))
 	for _, msg := range messages {
+		if !c.acceptsAccount(msg) {
+			continue
+		}
 		if msg.Envelope.DataMessage != nil {
 			sigMsg := c.convertDataMessageToSignalMessage(ctx, msg)
 			if sigMsg.QuotedMessage == nil && msg.Envelope.SyncMessage != nil && msg.Envelope.SyncMessage.SentMessage != nil {
@@ -823,11 +948,38 @@ func (c *SignalClient) ConvertRestMessages(ctx context.Context, messages []types
 		}
 		if msg.Envelope.ReceiptMessage != nil {
 			result = append(result, c.convertReceiptMessageToSignalMessages(msg)...)
+			continue
+		}
+		if msg.Envelope.TypingMessage != nil {
+			if sigMsg := c.convertTypingMessageToSignalMessage(msg); sigMsg != nil {
+				result = append(result, *sigMsg)
+			}
 		}
 	}
 	return result
 }
 
+// SetStrictAccountMatch makes the client drop received envelopes addressed to an account other
+// than its intermediary number, including envelopes that name no account
+func (c *SignalClient) SetStrictAccountMatch(strict bool) {
+	c.strictAccountMatch = strict
+}
+
+// acceptsAccount reports whether msg may be processed. Without strict account matching every
+// envelope is accepted; with it, an envelope for another account, which a misconfigured or
+// compromised signal-cli could deliver, is logged and dropped.
+func (c *SignalClient) acceptsAccount(msg types.RestMessage) bool {
+	if !c.strictAccountMatch || msg.Account == c.phoneNumber {
+		return true
+	}
+	c.logger.WithFields(logrus.Fields{
+		"account":   privacy.MaskPhoneNumber(msg.Account),
+		"timestamp": msg.Envelope.Timestamp,
+	}).Warn("Dropping Signal envelope addressed to another account")
+	metrics.IncrementCounter("signal_account_mismatch_total", nil, "Signal envelopes dropped because their account is not the intermediary number")
+	return false
+}
+
 func (c *SignalClient) DownloadAttachment(ctx context.Context, attachmentID string) ([]byte, error) {
 	endpoint := fmt.Sprintf("%s/v1/attachments/%s", c.baseURL, url.QueryEscape(attachmentID))
 
@@ -939,8 +1091,12 @@ func (c *SignalClient) encodeAttachment(filePath string) (string, string, string
 	// Base64 encode the file data
 	encodedData := base64.StdEncoding.EncodeToString(data)
 
-	// Detect content type from file extension
+	// Detect content type from file extension, else from the content, as attachments saved by
+	// signal-cli may have no extension
 	contentType := c.detectContentType(filePath)
+	if contentType == constants.DefaultMimeType {
+		contentType = detectContentTypeFromData(data)
+	}
 
 	// Extract just the filename from the path
 	filename := filepath.Base(filePath)
@@ -965,6 +1121,22 @@ func (c *SignalClient) detectContentType(filePath string) string {
 	return constants.DefaultMimeType
 }
 
+// detectContentTypeFromData returns the MIME type of a file from its leading bytes: the type of
+// a known file signature, else what http.DetectContentType sniffs, which is
+// application/octet-stream when nothing matches
+func detectContentTypeFromData(data []byte) string {
+	head := data[:min(len(data), constants.MimeDetectionBufferSize)]
+	if ext := media.ExtensionFromContent(head); ext != "" {
+		if mimeType, ok := constants.MimeTypes["."+ext]; ok {
+			return mimeType
+		}
+		if mimeType := mime.TypeByExtension("." + ext); mimeType != "" {
+			return mimeType
+		}
+	}
+	return http.DetectContentType(head)
+}
+
 func (c *SignalClient) ListAttachments(ctx context.Context) ([]string, error) {
 	endpoint := fmt.Sprintf("%s/v1/attachments", c.baseURL)
 
@@ -997,6 +1169,132 @@ func (c *SignalClient) ListAttachments(ctx context.Context) ([]string, error) {
 	return attachments, nil
 }
 
+// SetExpiration sets the disappearing-messages timer of the conversation with recipient.
+// Signal timers apply to the whole conversation, not to single messages; 0 turns it off.
+func (c *SignalClient) SetExpiration(ctx context.Context, recipient string, expiration time.Duration) error {
+	jsonData, err := json.Marshal(types.UpdateContactRequest{
+		Recipient:           recipient,
+		ExpirationInSeconds: int(expiration / time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/contacts/%s", c.baseURL, url.PathEscape(c.phoneNumber))
+	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create set expiration request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequestWithCircuitBreaker(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to set expiration: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if readErr != nil {
+			return fmt.Errorf("set expiration failed with status: %d (failed to read body: %v)", resp.StatusCode, readErr)
+		}
+		return fmt.Errorf("set expiration failed with status: %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"recipient":  maskPhone(recipient),
+		"expiration": expiration,
+	}).Debug("Signal conversation expiration updated")
+	return nil
+}
+
+// GetContactProfile returns the Signal profile of number. A number signal-cli has no profile for
+// is returned as nil without an error.
+func (c *SignalClient) GetContactProfile(ctx context.Context, number string) (*types.ContactProfile, error) {
+	if number == "" {
+		return nil, fmt.Errorf("number cannot be empty")
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/profiles/%s", c.baseURL, url.PathEscape(number))
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create get profile request: %w", err)
+	}
+
+	resp, err := c.doRequestWithCircuitBreaker(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if readErr != nil {
+			return nil, fmt.Errorf("get profile failed with status: %d (failed to read body: %v)", resp.StatusCode, readErr)
+		}
+		return nil, fmt.Errorf("get profile failed with status: %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var profile types.ContactProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, fmt.Errorf("failed to decode profile: %w", err)
+	}
+	if profile.Number == "" {
+		profile.Number = number
+	}
+	return &profile, nil
+}
+
+// SendReaction reacts with emoji to the message targetAuthor sent at targetTimestamp in the
+// conversation with recipient. With remove set the reaction is taken back instead; Signal
+// expects the emoji of the reaction being removed.
+func (c *SignalClient) SendReaction(ctx context.Context, recipient, emoji, targetAuthor string, targetTimestamp int64, remove bool) error {
+	jsonData, err := json.Marshal(types.ReactionRequest{
+		Reaction:     emoji,
+		Recipient:    recipient,
+		TargetAuthor: targetAuthor,
+		Timestamp:    targetTimestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	method := http.MethodPost
+	if remove {
+		method = http.MethodDelete
+	}
+	endpoint := fmt.Sprintf("%s/v1/reactions/%s", c.baseURL, url.PathEscape(c.phoneNumber))
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create reaction request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequestWithCircuitBreaker(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to send reaction: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if readErr != nil {
+			return fmt.Errorf("signal API error: status %d (failed to read body: %v)", resp.StatusCode, readErr)
+		}
+		return fmt.Errorf("signal API error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"recipient": maskPhone(recipient),
+		"timestamp": targetTimestamp,
+		"remove":    remove,
+	}).Debug("Signal reaction sent")
+	return nil
+}
+
 // HealthCheck performs a health check on the Signal API
 func (c *SignalClient) HealthCheck(ctx context.Context) error {
 	endpoint := fmt.Sprintf("%s/v1/about", c.baseURL)
@@ -3,6 +3,7 @@ package signal
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -190,12 +191,19 @@ func TestEncodeAttachment(t *testing.T) {
 			expectedContent: base64.StdEncoding.EncodeToString([]byte("fake ogg content")),
 		},
 		{
-			name:            "Unknown file type",
+			name:            "Unknown file type is sniffed",
 			filename:        "test.unknown",
 			content:         []byte("fake unknown content"),
-			expectedType:    "application/octet-stream",
+			expectedType:    "text/plain; charset=utf-8",
 			expectedContent: base64.StdEncoding.EncodeToString([]byte("fake unknown content")),
 		},
+		{
+			name:            "Unknown binary file type",
+			filename:        "test.unknown",
+			content:         []byte{0x00, 0x01, 0x02, 0x03},
+			expectedType:    "application/octet-stream",
+			expectedContent: base64.StdEncoding.EncodeToString([]byte{0x00, 0x01, 0x02, 0x03}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -220,6 +228,48 @@ func TestEncodeAttachment(t *testing.T) {
 	}
 }
 
+func TestEncodeAttachment_ExtensionlessContent(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      []byte
+		expectedType string
+	}{
+		{
+			name:         "OGG voice note",
+			content:      append([]byte("OggS\x00\x02\x00\x00"), []byte("OpusHead")...),
+			expectedType: "audio/ogg",
+		},
+		{
+			name:         "JPEG image",
+			content:      []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00},
+			expectedType: "image/jpeg",
+		},
+		{
+			name:         "PNG image",
+			content:      []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+			expectedType: "image/png",
+		},
+		{
+			name:         "unrecognised content",
+			content:      []byte{0x00, 0x01, 0x02, 0x03},
+			expectedType: "application/octet-stream",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// signal-cli stores received attachments under their ID, without an extension
+			filePath := filepath.Join(t.TempDir(), "Xk3jQ9vTzLr0aB7cD1eF")
+			require.NoError(t, os.WriteFile(filePath, tt.content, 0644))
+
+			_, contentType, filename, err := (&SignalClient{}).encodeAttachment(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedType, contentType)
+			assert.Equal(t, "Xk3jQ9vTzLr0aB7cD1eF", filename)
+		})
+	}
+}
+
 func TestEncodeAttachmentErrors(t *testing.T) {
 	client := &SignalClient{}
 
@@ -458,6 +508,53 @@ func TestSendMessage(t *testing.T) {
 	}
 }
 
+func TestSendMessageWithTextMode(t *testing.T) {
+	var captured types.SendMessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = types.SendMessageRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"timestamp": 1234567890}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "+0987654321", "test-device", "", nil)
+
+	_, err := client.SendMessage(context.Background(), "+1234567890", "**bold**", nil, types.WithTextMode(types.TextModeStyled))
+	require.NoError(t, err)
+	assert.Equal(t, "styled", captured.TextMode)
+	assert.Equal(t, "**bold**", captured.Message)
+
+	_, err = client.SendMessage(context.Background(), "+1234567890", "plain", nil)
+	require.NoError(t, err)
+	assert.Empty(t, captured.TextMode)
+}
+
+func TestSendMessageWithQuote(t *testing.T) {
+	var raw map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"timestamp": 1234567890}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "+0987654321", "test-device", "", nil)
+
+	_, err := client.SendMessage(context.Background(), "+1234567890", "Alice: sounds good", nil, types.WithQuote(1700000000123, "+0987654321", "Alice: dinner at 8?"))
+	require.NoError(t, err)
+	assert.Equal(t, float64(1700000000123), raw["quote_timestamp"])
+	assert.Equal(t, "+0987654321", raw["quote_author"])
+	assert.Equal(t, "Alice: dinner at 8?", raw["quote_message"])
+
+	_, err = client.SendMessage(context.Background(), "+1234567890", "plain", nil)
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "quote_timestamp")
+	assert.NotContains(t, raw, "quote_author")
+	assert.NotContains(t, raw, "quote_message")
+}
+
 func TestReceiveMessages(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -683,6 +780,93 @@ func TestReceiveMessages_PreservesQuotedMessageFromSyncEnvelope(t *testing.T) {
 	assert.Equal(t, int64(1234567889000), msg.QuotedMessage.Timestamp)
 }
 
+func TestReceiveMessages_SkipsMalformedEnvelopes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[
+			{
+				"envelope": {
+					"source": "+1234567890",
+					"timestamp": 1234567890000,
+					"dataMessage": {"timestamp": 1234567890000, "message": "first"}
+				}
+			},
+			{"envelope": {"source": "+1234567890", "timestamp": "not a number"}},
+			"not an envelope",
+			{
+				"envelope": {
+					"source": "+1234567890",
+					"timestamp": 1234567891000,
+					"dataMessage": {"timestamp": 1234567891000, "message": "second"}
+				}
+			}
+		]`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "+0987654321", "test-device", "", nil)
+
+	messages, err := client.ReceiveMessages(context.Background(), 5)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "first", messages[0].Message)
+	assert.Equal(t, "second", messages[1].Message)
+}
+
+func TestReceiveMessages_RejectsMalformedBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"envelope": {}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "+0987654321", "test-device", "", nil)
+
+	messages, err := client.ReceiveMessages(context.Background(), 5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode response")
+	assert.Nil(t, messages)
+}
+
+func TestReceiveMessages_StrictAccountMatch(t *testing.T) {
+	const body = `[
+		{"envelope": {"source": "+1234567890", "timestamp": 1, "dataMessage": {"timestamp": 1, "message": "for us"}}, "account": "+0987654321"},
+		{"envelope": {"source": "+1234567890", "timestamp": 2, "dataMessage": {"timestamp": 2, "message": "for someone else"}}, "account": "+1111111111"},
+		{"envelope": {"source": "+1234567890", "timestamp": 3, "dataMessage": {"timestamp": 3, "message": "no account"}}}
+	]`
+
+	tests := []struct {
+		name     string
+		strict   bool
+		expected []string
+	}{
+		{name: "strict drops other accounts", strict: true, expected: []string{"for us"}},
+		{name: "off by default", expected: []string{"for us", "for someone else", "no account"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "+0987654321", "test-device", "", nil).(*SignalClient)
+			client.SetStrictAccountMatch(tt.strict)
+
+			messages, err := client.ReceiveMessages(context.Background(), 5)
+			require.NoError(t, err)
+			var texts []string
+			for _, msg := range messages {
+				texts = append(texts, msg.Message)
+			}
+			assert.Equal(t, tt.expected, texts)
+		})
+	}
+}
+
 func TestReceiveMessagesPerRequestTimeout(t *testing.T) {
 	// Verify that ReceiveMessages creates a per-request context with timeout = pollTimeout + 15s
 	// This ensures the HTTP request timeout accounts for both the long-poll duration and network overhead.
@@ -1825,3 +2009,242 @@ func TestConvertRestMessages_ReceiptMessageMultipleTimestamps(t *testing.T) {
 	assert.True(t, result[0].Receipt.IsDelivery)
 	assert.True(t, result[1].Receipt.IsDelivery)
 }
+
+func TestConvertRestMessages_TypingMessage(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	sigClient := NewClientWithLogger("http://localhost", "+1234567890", "test", "", nil, logger).(*SignalClient)
+
+	var messages []types.RestMessage
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"envelope": {"source": "+15550000001", "timestamp": 1774363197904,
+			"typingMessage": {"action": "STARTED", "timestamp": 1774363197904, "groupId": "c2lnbmFsLWdyb3VwLWlk"}},
+		 "account": "+15550000002"},
+		{"envelope": {"source": "+15550000001", "timestamp": 1774363199000,
+			"typingMessage": {"action": "STOPPED", "timestamp": 1774363199000}},
+		 "account": "+15550000002"}
+	]`), &messages))
+
+	result := sigClient.ConvertRestMessages(context.Background(), messages)
+	require.Len(t, result, 2)
+
+	require.NotNil(t, result[0].Typing)
+	assert.True(t, result[0].Typing.IsStarted())
+	assert.Equal(t, "+15550000001", result[0].Sender)
+	assert.Equal(t, "c2lnbmFsLWdyb3VwLWlk", result[0].GroupID)
+	assert.Empty(t, result[0].Message)
+
+	require.NotNil(t, result[1].Typing)
+	assert.Equal(t, types.TypingActionStopped, result[1].Typing.Action)
+	assert.Equal(t, int64(1774363199000), result[1].Typing.Timestamp)
+	assert.Empty(t, result[1].GroupID)
+}
+
+func TestConvertMessages_ParsesMentions(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	sigClient := NewClientWithLogger("http://localhost", "+1234567890", "test", "", nil, logger).(*SignalClient)
+
+	t.Run("data message", func(t *testing.T) {
+		raw := `{"envelope":{"source":"+15550000001","timestamp":1700000000001,"dataMessage":{"timestamp":1700000000001,"message":"\uFFFC can you bring chairs?","mentions":[{"name":"Alice","number":"+15550001111","uuid":"a1b2c3","start":0,"length":1}]}},"account":"+1234567890"}`
+		var msg types.RestMessage
+		require.NoError(t, json.Unmarshal([]byte(raw), &msg))
+
+		sigMsg := sigClient.convertDataMessageToSignalMessage(context.Background(), msg)
+		require.Len(t, sigMsg.Mentions, 1)
+		assert.Equal(t, types.SignalMention{Start: 0, Length: 1, Number: "+15550001111", UUID: "a1b2c3", Name: "Alice"}, sigMsg.Mentions[0])
+	})
+
+	t.Run("sync message nested in dataMessage", func(t *testing.T) {
+		raw := `{"envelope":{"source":"+1234567890","timestamp":1700000000002,"syncMessage":{"sentMessage":{"timestamp":1700000000002,"message":"hi \uFFFC","groupInfo":{"groupId":"abc"},"dataMessage":{"mentions":[{"uuid":"d4e5f6","start":3,"length":1}]}}}},"account":"+1234567890"}`
+		var msg types.RestMessage
+		require.NoError(t, json.Unmarshal([]byte(raw), &msg))
+
+		sigMsg := sigClient.convertSyncMessageToSignalMessage(context.Background(), msg)
+		require.NotNil(t, sigMsg)
+		require.Len(t, sigMsg.Mentions, 1)
+		assert.Equal(t, types.SignalMention{Start: 3, Length: 1, UUID: "d4e5f6"}, sigMsg.Mentions[0])
+	})
+
+	t.Run("no mentions", func(t *testing.T) {
+		raw := `{"envelope":{"source":"+15550000001","timestamp":1700000000003,"dataMessage":{"timestamp":1700000000003,"message":"plain"}},"account":"+1234567890"}`
+		var msg types.RestMessage
+		require.NoError(t, json.Unmarshal([]byte(raw), &msg))
+
+		sigMsg := sigClient.convertDataMessageToSignalMessage(context.Background(), msg)
+		assert.Nil(t, sigMsg.Mentions)
+	})
+}
+
+func TestConvertDataMessage_ParsesGroupInfo(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	sigClient := NewClientWithLogger("http://localhost", "+1234567890", "test", "", nil, logger).(*SignalClient)
+
+	raw := `{"envelope":{"source":"+15550000001","timestamp":1700000000004,"dataMessage":{"timestamp":1700000000004,"message":"see you at 8","groupInfo":{"groupId":"c2lnbmFsLWdyb3Vw","type":"DELIVER"}}},"account":"+1234567890"}`
+	var msg types.RestMessage
+	require.NoError(t, json.Unmarshal([]byte(raw), &msg))
+
+	sigMsg := sigClient.convertDataMessageToSignalMessage(context.Background(), msg)
+	assert.Equal(t, "c2lnbmFsLWdyb3Vw", sigMsg.GroupID)
+	assert.Equal(t, "+15550000001", sigMsg.Sender, "the sender stays the group member who posted")
+
+	raw = `{"envelope":{"source":"+15550000001","timestamp":1700000000005,"dataMessage":{"timestamp":1700000000005,"message":"direct"}},"account":"+1234567890"}`
+	var direct types.RestMessage
+	require.NoError(t, json.Unmarshal([]byte(raw), &direct))
+	assert.Empty(t, sigClient.convertDataMessageToSignalMessage(context.Background(), direct).GroupID)
+}
+
+func TestConvertMessages_ParseEditTargetTimestamp(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	sigClient := NewClientWithLogger("http://localhost", "+1234567890", "test", "", nil, logger).(*SignalClient)
+
+	raw := `{"envelope":{"source":"+1234567890","timestamp":1700000000009,"dataMessage":{"timestamp":1700000000009,"message":"see you at 9","editTargetTimestamp":1700000000004}},"account":"+1234567890"}`
+	var msg types.RestMessage
+	require.NoError(t, json.Unmarshal([]byte(raw), &msg))
+
+	sigMsg := sigClient.convertDataMessageToSignalMessage(context.Background(), msg)
+	require.NotNil(t, sigMsg.Edit)
+	assert.Equal(t, "1700000000004", sigMsg.Edit.TargetMessageID)
+	assert.Equal(t, int64(1700000000004), sigMsg.Edit.TargetTimestamp)
+	assert.Equal(t, "see you at 9", sigMsg.Message)
+
+	raw = `{"envelope":{"source":"+1234567890","timestamp":1700000000010,"syncMessage":{"sentMessage":{"destination":"+15550000001","timestamp":1700000000010,"message":"fixed","editTargetTimestamp":1700000000006}}},"account":"+1234567890"}`
+	var sync types.RestMessage
+	require.NoError(t, json.Unmarshal([]byte(raw), &sync))
+	syncMsg := sigClient.convertSyncMessageToSignalMessage(context.Background(), sync)
+	require.NotNil(t, syncMsg)
+	require.NotNil(t, syncMsg.Edit)
+	assert.Equal(t, int64(1700000000006), syncMsg.Edit.TargetTimestamp)
+
+	raw = `{"envelope":{"source":"+15550000001","timestamp":1700000000011,"dataMessage":{"timestamp":1700000000011,"message":"new"}},"account":"+1234567890"}`
+	var plain types.RestMessage
+	require.NoError(t, json.Unmarshal([]byte(raw), &plain))
+	assert.Nil(t, sigClient.convertDataMessageToSignalMessage(context.Background(), plain).Edit)
+}
+
+func TestSetExpiration(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverStatus  int
+		expectedError string
+	}{
+		{name: "timer set", serverStatus: http.StatusNoContent},
+		{name: "server error", serverStatus: http.StatusBadRequest, expectedError: "set expiration failed with status: 400"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "PUT", r.Method)
+				assert.Equal(t, "/v1/contacts/+0987654321", r.URL.Path)
+
+				var req types.UpdateContactRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, "+1234567890", req.Recipient)
+				assert.Equal(t, 86400, req.ExpirationInSeconds)
+
+				w.WriteHeader(tt.serverStatus)
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "+0987654321", "test-device", "", nil)
+			err := client.SetExpiration(context.Background(), "+1234567890", 24*time.Hour)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetContactProfile(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverStatus  int
+		body          string
+		wantName      string
+		wantNil       bool
+		expectedError string
+	}{
+		{name: "profile with name", serverStatus: http.StatusOK, body: `{"given_name":"Alice","lastname":"Smith","about":"hi"}`, wantName: "Alice Smith"},
+		{name: "no profile", serverStatus: http.StatusNotFound, body: `{"error":"profile not found"}`, wantNil: true},
+		{name: "server error", serverStatus: http.StatusInternalServerError, body: `{"error":"boom"}`, expectedError: "get profile failed with status: 500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "GET", r.Method)
+				assert.Equal(t, "/v1/profiles/+1234567890", r.URL.Path)
+				w.WriteHeader(tt.serverStatus)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "+0987654321", "test-device", "", nil)
+			profile, err := client.GetContactProfile(context.Background(), "+1234567890")
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantNil {
+				assert.Nil(t, profile)
+				return
+			}
+			require.NotNil(t, profile)
+			assert.Equal(t, "+1234567890", profile.Number)
+			assert.Equal(t, tt.wantName, profile.DisplayName())
+		})
+	}
+}
+
+func TestSendReaction(t *testing.T) {
+	tests := []struct {
+		name          string
+		remove        bool
+		serverStatus  int
+		expectedVerb  string
+		expectedError string
+	}{
+		{name: "reaction added", serverStatus: http.StatusNoContent, expectedVerb: http.MethodPost},
+		{name: "reaction removed", remove: true, serverStatus: http.StatusNoContent, expectedVerb: http.MethodDelete},
+		{name: "server error", serverStatus: http.StatusBadRequest, expectedVerb: http.MethodPost, expectedError: "signal API error: status 400"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.expectedVerb, r.Method)
+				assert.Equal(t, "/v1/reactions/+0987654321", r.URL.Path)
+
+				var req types.ReactionRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, "👍", req.Reaction)
+				assert.Equal(t, "+1234567890", req.Recipient)
+				assert.Equal(t, "+0987654321", req.TargetAuthor)
+				assert.Equal(t, int64(1700000000000), req.Timestamp)
+
+				w.WriteHeader(tt.serverStatus)
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "+0987654321", "test-device", "", nil)
+			err := client.SendReaction(context.Background(), "+1234567890", "👍", "+0987654321", 1700000000000, tt.remove)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package signal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"whatsignal/pkg/signal/types"
+)
+
+// IsRegistered reports whether the intermediary number is registered with signal-cli
+func (c *SignalClient) IsRegistered(ctx context.Context) (bool, error) {
+	endpoint := fmt.Sprintf("%s/v1/accounts", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create accounts request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.doRequestWithCircuitBreaker(ctx, req)
+	if err != nil {
+		return false, fmt.Errorf("failed to list signal-cli accounts: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, registrationError("list accounts", resp)
+	}
+
+	var accounts []string
+	if err := json.NewDecoder(resp.Body).Decode(&accounts); err != nil {
+		return false, fmt.Errorf("failed to decode accounts response: %w", err)
+	}
+	for _, account := range accounts {
+		if account == c.phoneNumber {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Register asks signal-cli to register the intermediary number, sending a verification code
+// by SMS or, with useVoice, by voice call. Signal usually requires a captcha token from
+// https://signalcaptchas.org/registration/generate.html.
+func (c *SignalClient) Register(ctx context.Context, useVoice bool, captcha string) error {
+	endpoint := fmt.Sprintf("%s/v1/register/%s", c.baseURL, url.PathEscape(c.phoneNumber))
+	if err := c.postRegistration(ctx, endpoint, types.RegisterRequest{UseVoice: useVoice, Captcha: captcha}, "register"); err != nil {
+		return err
+	}
+
+	c.logger.WithField("number", maskPhone(c.phoneNumber)).Info("Signal registration requested, waiting for verification code")
+	return nil
+}
+
+// VerifyRegistration completes registration with the code Signal sent. pin is only needed
+// when the number has a registration lock PIN.
+func (c *SignalClient) VerifyRegistration(ctx context.Context, code, pin string) error {
+	endpoint := fmt.Sprintf("%s/v1/register/%s/verify/%s", c.baseURL, url.PathEscape(c.phoneNumber), url.PathEscape(code))
+	if err := c.postRegistration(ctx, endpoint, types.VerifyRequest{Pin: pin}, "verify"); err != nil {
+		return err
+	}
+
+	c.logger.WithField("number", maskPhone(c.phoneNumber)).Info("Signal number verified")
+	return nil
+}
+
+func (c *SignalClient) postRegistration(ctx context.Context, endpoint string, payload interface{}, action string) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", action, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", action, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequestWithCircuitBreaker(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s request: %w", action, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return registrationError(action, resp)
+	}
+	return nil
+}
+
+func registrationError(action string, resp *http.Response) error {
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if readErr != nil {
+		return fmt.Errorf("signal %s failed with status %d (failed to read body: %v)", action, resp.StatusCode, readErr)
+	}
+	return fmt.Errorf("signal %s failed with status %d: %s", action, resp.StatusCode, string(body))
+}
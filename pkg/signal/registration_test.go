@@ -0,0 +1,105 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"whatsignal/pkg/signal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRegistrationTestClient(t *testing.T, handler http.HandlerFunc) *SignalClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, "+15550001111", "test-device", t.TempDir(), server.Client()).(*SignalClient)
+}
+
+func TestSignalClient_IsRegistered(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected bool
+		wantErr  bool
+	}{
+		{"registered", http.StatusOK, `["+15550009999","+15550001111"]`, true, false},
+		{"not registered", http.StatusOK, `["+15550009999"]`, false, false},
+		{"no accounts", http.StatusOK, `[]`, false, false},
+		{"server error", http.StatusInternalServerError, `{"error":"boom"}`, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newRegistrationTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/v1/accounts", r.URL.Path)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			registered, err := client.IsRegistered(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, registered)
+		})
+	}
+}
+
+func TestSignalClient_Register(t *testing.T) {
+	var received types.RegisterRequest
+	client := newRegistrationTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/register/+15550001111", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	require.NoError(t, client.Register(context.Background(), true, "signalcaptcha://token"))
+	assert.True(t, received.UseVoice)
+	assert.Equal(t, "signalcaptcha://token", received.Captcha)
+}
+
+func TestSignalClient_RegisterSurfacesCaptchaError(t *testing.T) {
+	client := newRegistrationTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"Captcha required for verification"}`))
+	})
+
+	err := client.Register(context.Background(), false, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 400")
+	assert.Contains(t, err.Error(), "Captcha required")
+}
+
+func TestSignalClient_VerifyRegistration(t *testing.T) {
+	var received types.VerifyRequest
+	client := newRegistrationTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/register/+15550001111/verify/123-456", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	require.NoError(t, client.VerifyRegistration(context.Background(), "123-456", "4321"))
+	assert.Equal(t, "4321", received.Pin)
+}
+
+func TestSignalClient_VerifyRegistrationInvalidCode(t *testing.T) {
+	client := newRegistrationTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"Verify error: Invalid verification code"}`))
+	})
+
+	err := client.VerifyRegistration(context.Background(), "000-000", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid verification code")
+}
@@ -5,7 +5,7 @@ import (
 )
 
 type Client interface {
-	SendMessage(ctx context.Context, recipient, message string, attachments []string) (*SendMessageResponse, error)
+	SendMessage(ctx context.Context, recipient, message string, attachments []string, opts ...SendOption) (*SendMessageResponse, error)
 	ReceiveMessages(ctx context.Context, timeoutSeconds int) ([]SignalMessage, error)
 	InitializeDevice(ctx context.Context) error
 	HealthCheck(ctx context.Context) error
@@ -13,7 +13,7 @@ type MockClient struct {
 	mock.Mock
 }
 
-func (m *MockClient) SendMessage(ctx context.Context, recipient, message string, attachments []string) (*SendMessageResponse, error) {
+func (m *MockClient) SendMessage(ctx context.Context, recipient, message string, attachments []string, opts ...SendOption) (*SendMessageResponse, error) {
 	args := m.Called(ctx, recipient, message, attachments)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"strconv"
+	"strings"
 )
 
 // FlexibleInt64 can unmarshal both string and int64 JSON values
@@ -40,6 +41,68 @@ type SendMessageRequest struct {
 	Recipients        []string `json:"recipients"`
 	Base64Attachments []string `json:"base64_attachments,omitempty"`
 	TextMode          string   `json:"text_mode,omitempty"` // "normal" or "styled"
+	QuoteTimestamp    int64    `json:"quote_timestamp,omitempty"`
+	QuoteAuthor       string   `json:"quote_author,omitempty"`
+	QuoteMessage      string   `json:"quote_message,omitempty"`
+}
+
+// TextModeStyled asks signal-cli-rest-api to turn markdown-like markers into Signal text styles
+const TextModeStyled = "styled"
+
+// SendOption customizes a SendMessageRequest before it is sent
+type SendOption func(*SendMessageRequest)
+
+// WithTextMode sets the text_mode of an outgoing message ("normal" or "styled")
+func WithTextMode(mode string) SendOption {
+	return func(req *SendMessageRequest) {
+		req.TextMode = mode
+	}
+}
+
+// WithQuote makes the message a native Signal reply to the message sent by author at
+// timestamp (milliseconds). text is shown in the quote if the recipient no longer has the
+// original message.
+func WithQuote(timestamp int64, author, text string) SendOption {
+	return func(req *SendMessageRequest) {
+		req.QuoteTimestamp = timestamp
+		req.QuoteAuthor = author
+		req.QuoteMessage = text
+	}
+}
+
+// UpdateContactRequest is the body of PUT /v1/contacts/{number}. ExpirationInSeconds sets the
+// disappearing-messages timer of the conversation with Recipient; 0 turns it off.
+type UpdateContactRequest struct {
+	Recipient           string `json:"recipient"`
+	ExpirationInSeconds int    `json:"expiration_in_seconds"`
+}
+
+// ContactProfile is the Signal profile of a number, as returned by GET /v1/profiles/{number}.
+// Profiles are set by their owner; any of the names may be empty.
+type ContactProfile struct {
+	Number     string `json:"number,omitempty"`
+	Name       string `json:"name,omitempty"`
+	GivenName  string `json:"given_name,omitempty"`
+	FamilyName string `json:"lastname,omitempty"`
+	About      string `json:"about,omitempty"`
+}
+
+// DisplayName returns the profile name, or the given and family names joined when signal-cli
+// reports them separately; empty when the profile has no name
+func (p *ContactProfile) DisplayName() string {
+	if name := strings.TrimSpace(p.Name); name != "" {
+		return name
+	}
+	return strings.TrimSpace(strings.TrimSpace(p.GivenName) + " " + strings.TrimSpace(p.FamilyName))
+}
+
+// ReactionRequest is the body of POST /v1/reactions/{number}, which reacts to the message sent by
+// TargetAuthor at Timestamp, and of DELETE on the same path, which removes that reaction
+type ReactionRequest struct {
+	Reaction     string `json:"reaction"`
+	Recipient    string `json:"recipient"`
+	TargetAuthor string `json:"target_author"`
+	Timestamp    int64  `json:"timestamp"`
 }
 
 type SendMessageResponse struct {
@@ -65,6 +128,17 @@ type AboutResponse struct {
 	Capabilities map[string][]string `json:"capabilities"`
 }
 
+// RegisterRequest is the body of signal-cli-rest-api's POST /v1/register/{number}
+type RegisterRequest struct {
+	UseVoice bool   `json:"use_voice"`
+	Captcha  string `json:"captcha,omitempty"`
+}
+
+// VerifyRequest is the body of signal-cli-rest-api's POST /v1/register/{number}/verify/{token}
+type VerifyRequest struct {
+	Pin string `json:"pin,omitempty"`
+}
+
 // SignalMessage represents a received Signal message
 type SignalMessage struct {
 	Timestamp     int64    `json:"timestamp"`
@@ -80,9 +154,24 @@ type SignalMessage struct {
 	} `json:"quotedMessage,omitempty"`
 	Reaction    *SignalReaction `json:"reaction,omitempty"`
 	Deletion    *SignalDeletion `json:"deletion,omitempty"`
+	Edit        *SignalEdit     `json:"edit,omitempty"`
 	Receipt     *SignalReceipt  `json:"receipt,omitempty"`
+	Typing      *TypingInfo     `json:"typing,omitempty"`
+	Mentions    []SignalMention `json:"mentions,omitempty"`
 	IsSentByMe  bool            `json:"isSentByMe,omitempty"`
 	Destination string          `json:"destination,omitempty"`
+	// GroupID is the Signal group a received message was posted in; empty for direct messages
+	GroupID string `json:"groupId,omitempty"`
+}
+
+// SignalMention marks a range of a message's text that mentions a Signal user. Start and
+// Length count UTF-16 code units, as Signal does; the range usually holds a single U+FFFC.
+type SignalMention struct {
+	Start  int    `json:"start"`
+	Length int    `json:"length"`
+	Number string `json:"number,omitempty"`
+	UUID   string `json:"uuid,omitempty"`
+	Name   string `json:"name,omitempty"`
 }
 
 // SignalDeletion represents a message deletion event
@@ -91,6 +180,12 @@ type SignalDeletion struct {
 	TargetTimestamp int64  `json:"targetTimestamp"`
 }
 
+// SignalEdit marks a message as a new revision of an earlier one; Message holds the new text
+type SignalEdit struct {
+	TargetMessageID string `json:"targetMessageId"`
+	TargetTimestamp int64  `json:"targetTimestamp"`
+}
+
 // SignalReaction represents a reaction to a message
 type SignalReaction struct {
 	Emoji           string `json:"emoji"`
@@ -109,6 +204,25 @@ type SignalReceipt struct {
 	IsViewed        bool  `json:"isViewed"`
 }
 
+// Typing actions reported by signal-cli
+const (
+	TypingActionStarted = "STARTED"
+	TypingActionStopped = "STOPPED"
+)
+
+// TypingInfo reports that the sender started or stopped composing a message, in a Signal group
+// when GroupID is set
+type TypingInfo struct {
+	Action    string `json:"action"`
+	Timestamp int64  `json:"timestamp"`
+	GroupID   string `json:"groupId,omitempty"`
+}
+
+// IsStarted reports whether the sender started composing
+func (t *TypingInfo) IsStarted() bool {
+	return t.Action == TypingActionStarted
+}
+
 // RestDataMessage represents the data message content in Signal messages
 type RestDataMessage struct {
 	Timestamp    int64                   `json:"timestamp"`
@@ -121,6 +235,10 @@ type RestDataMessage struct {
 	RemoteDelete *struct {
 		Timestamp int64 `json:"timestamp"`
 	} `json:"remoteDelete,omitempty"`
+	Mentions  []RestMessageMention `json:"mentions,omitempty"`
+	GroupInfo *RestGroupInfo       `json:"groupInfo,omitempty"`
+	// EditTargetTimestamp is the timestamp of the message this one edits; 0 for new messages
+	EditTargetTimestamp int64 `json:"editTargetTimestamp,omitempty"`
 }
 
 // GetQuote returns the quote from whichever field signal-cli populated.
@@ -153,8 +271,33 @@ type RestSentMessage struct {
 	RemoteDelete      *struct {
 		Timestamp int64 `json:"timestamp"`
 	} `json:"remoteDelete,omitempty"`
-	GroupInfo   *RestGroupInfo   `json:"groupInfo,omitempty"`
-	DataMessage *RestDataMessage `json:"dataMessage,omitempty"`
+	GroupInfo           *RestGroupInfo       `json:"groupInfo,omitempty"`
+	Mentions            []RestMessageMention `json:"mentions,omitempty"`
+	EditTargetTimestamp int64                `json:"editTargetTimestamp,omitempty"`
+	DataMessage         *RestDataMessage     `json:"dataMessage,omitempty"`
+}
+
+// GetEditTargetTimestamp returns the timestamp of the message this one edits, falling back to
+// the nested DataMessage like GetQuote; 0 for new messages
+func (s *RestSentMessage) GetEditTargetTimestamp() int64 {
+	if s.EditTargetTimestamp != 0 {
+		return s.EditTargetTimestamp
+	}
+	if s.DataMessage != nil {
+		return s.DataMessage.EditTargetTimestamp
+	}
+	return 0
+}
+
+// GetMentions returns the message's mentions, falling back to the nested DataMessage like GetQuote
+func (s *RestSentMessage) GetMentions() []RestMessageMention {
+	if len(s.Mentions) > 0 {
+		return s.Mentions
+	}
+	if s.DataMessage != nil {
+		return s.DataMessage.Mentions
+	}
+	return nil
 }
 
 // GetQuote returns the quote from whichever field signal-cli populated.
@@ -223,6 +366,13 @@ type RestReceiptMessage struct {
 	Timestamps []FlexibleInt64 `json:"timestamps"`
 }
 
+// RestTypingMessage represents the signal-cli typing envelope payload.
+type RestTypingMessage struct {
+	Action    string        `json:"action"`
+	Timestamp FlexibleInt64 `json:"timestamp"`
+	GroupID   string        `json:"groupId,omitempty"`
+}
+
 type RestMessageAttachment struct {
 	ContentType string `json:"contentType"`
 	Filename    string `json:"filename"`
@@ -236,6 +386,15 @@ type RestMessageQuote struct {
 	Text   string `json:"text"`
 }
 
+// RestMessageMention is a mention as reported by signal-cli
+type RestMessageMention struct {
+	Name   string `json:"name,omitempty"`
+	Number string `json:"number,omitempty"`
+	UUID   string `json:"uuid,omitempty"`
+	Start  int    `json:"start"`
+	Length int    `json:"length"`
+}
+
 type RestMessageReaction struct {
 	Emoji           string `json:"emoji"`
 	TargetAuthor    string `json:"targetAuthor"`
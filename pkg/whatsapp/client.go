@@ -10,12 +10,12 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"whatsignal/internal/constants"
-	"whatsignal/internal/httputil"
 	"whatsignal/internal/security"
 	"whatsignal/pkg/circuitbreaker"
 	"whatsignal/pkg/whatsapp/types"
@@ -42,6 +42,8 @@ type WhatsAppClient struct {
 	sessionMgr      types.SessionManager
 	supportsVideoMu sync.RWMutex
 	supportsVideo   *bool // Cached video support status
+	capabilitiesMu  sync.RWMutex
+	capabilities    *types.Capabilities // Cached server capabilities
 	logger          *logrus.Logger
 	circuitBreaker  *circuitbreaker.CircuitBreaker
 	testMode        bool
@@ -61,7 +63,8 @@ func NewClientWithLogger(config types.ClientConfig, logger *logrus.Logger) types
 		apiKey:      config.APIKey,
 		sessionName: config.SessionName,
 		client: &http.Client{
-			Timeout: config.Timeout,
+			Timeout:   config.Timeout,
+			Transport: config.Transport,
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return http.ErrUseLastResponse
 			},
@@ -117,18 +120,16 @@ func (c *WhatsAppClient) doGetJSON(ctx context.Context, url string, target inter
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+		return statusError(fmt.Sprintf("request failed with status %d", resp.StatusCode), resp)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return nil
+	return decodeResponse(resp, target)
 }
 
-func (c *WhatsAppClient) CreateSession(ctx context.Context) error {
-	_, err := c.sessionMgr.Create(ctx, c.sessionName)
+// CreateSession creates the client's session in WAHA. config, which may be nil, sets up the
+// session's webhooks and proxy so it needs no configuration on the WAHA side.
+func (c *WhatsAppClient) CreateSession(ctx context.Context, config *types.SessionConfig) error {
+	_, err := c.sessionMgr.Create(ctx, c.sessionName, config)
 	return err
 }
 
@@ -167,13 +168,7 @@ func (c *WhatsAppClient) RestartSessionByName(ctx context.Context, sessionName s
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		var errorResp map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&errorResp); err == nil {
-			if errMsg, ok := errorResp["error"].(string); ok {
-				return fmt.Errorf("restart failed with status %d: %s", resp.StatusCode, errMsg)
-			}
-		}
-		return fmt.Errorf("restart failed with status %d", resp.StatusCode)
+		return statusError(fmt.Sprintf("restart failed with status %d", resp.StatusCode), resp)
 	}
 
 	return nil
@@ -206,7 +201,27 @@ func (c *WhatsAppClient) GetSessionStatusByName(ctx context.Context, sessionName
 		}
 	}
 
-	return nil, fmt.Errorf("session %s not found", sessionName)
+	return nil, fmt.Errorf("session %s %w", sessionName, types.ErrSessionNotFound)
+}
+
+// GetMe returns the WhatsApp account a session is logged in with. WAHA answers null for a
+// session that is not logged in, which is returned as nil.
+func (c *WhatsAppClient) GetMe(ctx context.Context, sessionName string) (*types.Me, error) {
+	if sessionName == "" {
+		sessionName = c.sessionName
+	}
+	reqURL := fmt.Sprintf("%s%s/sessions/%s%s", c.baseURL, types.APIBase, url.PathEscape(sessionName), types.EndpointSessionMe)
+	var me *types.Me
+	if err := c.doGetJSON(ctx, reqURL, &me); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session account: %w", err)
+	}
+	if me == nil || me.ID == "" {
+		return nil, nil
+	}
+	return me, nil
 }
 
 // validateSessionStatus checks if a session is ready to send messages
@@ -299,7 +314,7 @@ func (c *WhatsAppClient) WaitForSessionReadyByName(ctx context.Context, sessionN
 
 		if resp.StatusCode == http.StatusOK {
 			var sessions []map[string]interface{}
-			if err := json.NewDecoder(resp.Body).Decode(&sessions); err == nil {
+			if err := decodeResponse(resp, &sessions); err == nil {
 				if closeErr := resp.Body.Close(); closeErr != nil && c.logger != nil {
 					c.logger.WithError(closeErr).Debug("Failed to close response body")
 				}
@@ -358,7 +373,8 @@ func (c *WhatsAppClient) sendSeenWithSession(ctx context.Context, chatID, sessio
 	return err
 }
 
-func (c *WhatsAppClient) startTypingWithSession(ctx context.Context, chatID, sessionName string) error {
+// StartTyping shows the typing indicator in a chat until StopTyping is called or WAHA times it out
+func (c *WhatsAppClient) StartTyping(ctx context.Context, chatID, sessionName string) error {
 	payload := types.TypingRequest{
 		ChatID:  chatID,
 		Session: sessionName,
@@ -367,7 +383,8 @@ func (c *WhatsAppClient) startTypingWithSession(ctx context.Context, chatID, ses
 	return err
 }
 
-func (c *WhatsAppClient) stopTypingWithSession(ctx context.Context, chatID, sessionName string) error {
+// StopTyping clears the typing indicator in a chat
+func (c *WhatsAppClient) StopTyping(ctx context.Context, chatID, sessionName string) error {
 	payload := types.TypingRequest{
 		ChatID:  chatID,
 		Session: sessionName,
@@ -381,20 +398,45 @@ func (c *WhatsAppClient) SendText(ctx context.Context, chatID, text string) (*ty
 }
 
 func (c *WhatsAppClient) SendTextWithSession(ctx context.Context, chatID, text, replyTo, sessionName string) (*types.SendMessageResponse, error) {
+	return c.SendMentionsWithSession(ctx, chatID, text, nil, replyTo, sessionName)
+}
+
+// SendLocation sends a location pin at lat, lng to a chat from the client's default session.
+// title labels the pin and may be empty.
+func (c *WhatsAppClient) SendLocation(ctx context.Context, chatID string, lat, lng float64, title string) (*types.SendMessageResponse, error) {
+	if !c.testMode {
+		if err := c.validateSessionStatus(ctx, c.sessionName); err != nil {
+			return nil, err
+		}
+	}
+
+	payload := types.LocationMessageRequest{
+		ChatID:    chatID,
+		Session:   c.sessionName,
+		Latitude:  lat,
+		Longitude: lng,
+		Title:     title,
+	}
+	return c.sendRequest(ctx, types.APIBase+types.EndpointSendLocation, payload)
+}
+
+// SendMentionsWithSession sends a text that mentions the given WhatsApp IDs, so the mentioned
+// group members are notified. Each ID should appear in the text as @ followed by its number.
+func (c *WhatsAppClient) SendMentionsWithSession(ctx context.Context, chatID, text string, mentions []string, replyTo, sessionName string) (*types.SendMessageResponse, error) {
 	if !c.testMode {
 		if err := c.validateSessionStatus(ctx, sessionName); err != nil {
 			return nil, err
 		}
 	}
 
-	if err := c.startTypingWithSession(ctx, chatID, sessionName); err != nil {
+	if err := c.StartTyping(ctx, chatID, sessionName); err != nil {
 		if c.logger != nil {
 			c.logger.WithError(err).Debug("optional: startTyping failed")
 		}
 	}
 
 	defer func() {
-		if err := c.stopTypingWithSession(ctx, chatID, sessionName); err != nil {
+		if err := c.StopTyping(ctx, chatID, sessionName); err != nil {
 			if c.logger != nil {
 				c.logger.WithError(err).Debug("optional: stopTyping failed")
 			}
@@ -402,10 +444,11 @@ func (c *WhatsAppClient) SendTextWithSession(ctx context.Context, chatID, text,
 	}()
 
 	payload := types.SendMessageRequest{
-		ChatID:  chatID,
-		Text:    text,
-		Session: sessionName,
-		ReplyTo: replyTo,
+		ChatID:   chatID,
+		Text:     text,
+		Session:  sessionName,
+		ReplyTo:  replyTo,
+		Mentions: mentions,
 	}
 	return c.sendRequest(ctx, types.APIBase+types.EndpointSendText, payload)
 }
@@ -527,6 +570,34 @@ func (c *WhatsAppClient) SendReactionWithSession(ctx context.Context, chatID, me
 	return c.sendReactionRequest(ctx, endpoint, payload)
 }
 
+// StarMessage stars or unstars a message in the client's default session
+func (c *WhatsAppClient) StarMessage(ctx context.Context, chatID, messageID string, star bool) error {
+	return c.StarMessageWithSession(ctx, chatID, messageID, star, c.sessionName)
+}
+
+// StarMessageWithSession stars or unstars a message so it shows up under starred messages on the phone
+func (c *WhatsAppClient) StarMessageWithSession(ctx context.Context, chatID, messageID string, star bool, sessionName string) error {
+	if chatID == "" {
+		return fmt.Errorf("chatID cannot be empty")
+	}
+	if messageID == "" {
+		return fmt.Errorf("messageID cannot be empty")
+	}
+
+	payload := types.StarRequest{
+		Session:   sessionName,
+		ChatID:    chatID,
+		MessageID: messageID,
+		Star:      star,
+	}
+
+	endpoint := types.APIBase + types.EndpointStar
+	if _, err := c.sendReactionRequest(ctx, endpoint, payload); err != nil {
+		return fmt.Errorf("failed to star message: %w", err)
+	}
+	return nil
+}
+
 func (c *WhatsAppClient) DeleteMessage(ctx context.Context, chatID, messageID string) error {
 	// Validate parameters
 	if chatID == "" {
@@ -555,14 +626,55 @@ func (c *WhatsAppClient) DeleteMessage(ctx context.Context, chatID, messageID st
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		// Try to decode error response
-		var errorResp map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&errorResp); err == nil {
-			if errMsg, ok := errorResp["error"].(string); ok {
-				return fmt.Errorf("delete failed with status %d: %s", resp.StatusCode, errMsg)
-			}
-		}
-		return fmt.Errorf("delete failed with status %d", resp.StatusCode)
+		return statusError(fmt.Sprintf("delete failed with status %d", resp.StatusCode), resp)
+	}
+
+	return nil
+}
+
+// EditMessage replaces the text of a message sent from the client's default session
+func (c *WhatsAppClient) EditMessage(ctx context.Context, chatID, messageID, newText string) error {
+	return c.EditMessageWithSession(ctx, chatID, messageID, newText, c.sessionName)
+}
+
+// EditMessageWithSession replaces the text of a message the session sent. WhatsApp shows it as
+// edited; only the session's own text messages can be edited.
+func (c *WhatsAppClient) EditMessageWithSession(ctx context.Context, chatID, messageID, newText, sessionName string) error {
+	if chatID == "" {
+		return fmt.Errorf("chatID cannot be empty")
+	}
+	if messageID == "" {
+		return fmt.Errorf("messageID cannot be empty")
+	}
+	if strings.TrimSpace(newText) == "" {
+		return fmt.Errorf("newText cannot be empty")
+	}
+
+	jsonData, err := json.Marshal(types.EditMessageRequest{Text: newText})
+	if err != nil {
+		return fmt.Errorf("failed to marshal edit payload: %w", err)
+	}
+
+	// PUT /api/{session}/chats/{chatId}/messages/{messageId}
+	reqURL := fmt.Sprintf("%s/api/%s/chats/%s/messages/%s", c.baseURL, url.PathEscape(sessionName), url.PathEscape(chatID), url.PathEscape(messageID))
+	req, err := http.NewRequestWithContext(ctx, "PUT", reqURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create edit request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-Api-Key", c.apiKey)
+	}
+
+	resp, err := c.doRequestWithCircuitBreaker(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to send edit request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return statusError(fmt.Sprintf("edit failed with status %d", resp.StatusCode), resp)
 	}
 
 	return nil
@@ -592,31 +704,14 @@ func (c *WhatsAppClient) sendReactionRequest(ctx context.Context, endpoint strin
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		// Try to decode error response
-		var result types.SendMessageResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err == nil {
-			return &result, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, result.Error)
-		}
-		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
-	}
-
-	// Read response body
-	responseBody, err := httputil.ReadLimitedBody(resp.Body, int64(constants.DefaultWebhookMaxBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Handle empty response (which is valid for reactions)
-	if len(responseBody) == 0 {
-		return &types.SendMessageResponse{
-			Status: "sent",
-		}, nil
+		err := statusError(fmt.Sprintf("request failed with status %d", resp.StatusCode), resp)
+		return &types.SendMessageResponse{Error: err.Error()}, err
 	}
 
-	// Try to decode JSON response
-	var result types.SendMessageResponse
-	if err := json.Unmarshal(responseBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	// An empty response is valid for reactions
+	result := types.SendMessageResponse{Status: "sent"}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -644,13 +739,9 @@ func (c *WhatsAppClient) sendRequest(ctx context.Context, endpoint string, paylo
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	// Read response body for debugging
-	bodyBytes, err := httputil.ReadLimitedBody(resp.Body, int64(constants.DefaultWebhookMaxBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		err := statusError(fmt.Sprintf("request failed with status %d", resp.StatusCode), resp)
+
 		// Log detailed error information for debugging
 		if c.logger != nil {
 			// Create a sanitized version of the payload for logging (truncate base64 data)
@@ -665,26 +756,24 @@ func (c *WhatsAppClient) sendRequest(ctx context.Context, endpoint string, paylo
 				sanitizedPayload = payload
 			}
 
-			c.logger.WithFields(logrus.Fields{
+			c.logger.WithError(err).WithFields(logrus.Fields{
 				"endpoint":        endpoint,
 				"status_code":     resp.StatusCode,
-				"response_body":   string(bodyBytes),
 				"request_payload": sanitizedPayload,
 			}).Error("WAHA API request failed")
 		}
 
-		// Try to parse error response
-		var errorResult types.WAHAErrorResponse
-		if err := json.Unmarshal(bodyBytes, &errorResult); err == nil && errorResult.Error != "" {
-			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, errorResult.Error)
-		}
+		return nil, err
+	}
 
-		// Fallback to raw response body if no structured error
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	// A non-JSON body on a success status (e.g. a proxy error page) is an error, not a send
+	bodyBytes, err := readResponse(resp)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if response body is empty - this is acceptable for some WAHA responses
-	if len(bodyBytes) == 0 {
+	if bodyBytes == nil {
 		// Return a success response with empty message ID
 		// WAHA sometimes returns 201 with empty body when message is sent successfully
 		return &types.SendMessageResponse{
@@ -781,27 +870,12 @@ func (c *WhatsAppClient) GetAllContacts(ctx context.Context, limit, offset int)
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		// Read response body for better error diagnostics
-		bodyBytes, err := httputil.ReadLimitedBody(resp.Body, int64(constants.DefaultWebhookMaxBytes))
-		if err != nil {
-			return nil, fmt.Errorf("request failed with status %d (failed to read error response: %w)", resp.StatusCode, err)
-		}
-
-		// Try to decode structured error response
-		var errorResp map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &errorResp); err == nil {
-			if errMsg, ok := errorResp["error"].(string); ok {
-				return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, errMsg)
-			}
-		}
-
-		// Fallback to raw response body for debugging
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, statusError(fmt.Sprintf("request failed with status %d", resp.StatusCode), resp)
 	}
 
 	var contacts []types.Contact
-	if err := json.NewDecoder(resp.Body).Decode(&contacts); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &contacts); err != nil {
+		return nil, err
 	}
 
 	return contacts, nil
@@ -809,7 +883,13 @@ func (c *WhatsAppClient) GetAllContacts(ctx context.Context, limit, offset int)
 
 // GetGroup retrieves a specific group by group ID
 func (c *WhatsAppClient) GetGroup(ctx context.Context, groupID string) (*types.Group, error) {
-	reqURL := fmt.Sprintf("%s%s/%s%s/%s", c.baseURL, types.APIBase, url.PathEscape(c.sessionName), types.EndpointGroups, url.PathEscape(groupID))
+	return c.GetGroupWithSession(ctx, groupID, c.sessionName)
+}
+
+// GetGroupWithSession retrieves a group through sessionName. A group the session does not know
+// returns nil.
+func (c *WhatsAppClient) GetGroupWithSession(ctx context.Context, groupID, sessionName string) (*types.Group, error) {
+	reqURL := fmt.Sprintf("%s%s/%s%s/%s", c.baseURL, types.APIBase, url.PathEscape(sessionName), types.EndpointGroups, url.PathEscape(groupID))
 	var group types.Group
 	if err := c.doGetJSON(ctx, reqURL, &group); err != nil {
 		if errors.Is(err, errNotFound) {
@@ -842,32 +922,55 @@ func (c *WhatsAppClient) GetAllGroups(ctx context.Context, limit, offset int) ([
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		// Read response body for better error diagnostics
-		bodyBytes, err := httputil.ReadLimitedBody(resp.Body, int64(constants.DefaultWebhookMaxBytes))
-		if err != nil {
-			return nil, fmt.Errorf("request failed with status %d (failed to read error response: %w)", resp.StatusCode, err)
-		}
-
-		// Try to decode structured error response
-		var errorResp map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &errorResp); err == nil {
-			if errMsg, ok := errorResp["error"].(string); ok {
-				return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, errMsg)
-			}
-		}
-
-		// Fallback to raw response body for debugging
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, statusError(fmt.Sprintf("request failed with status %d", resp.StatusCode), resp)
 	}
 
 	var groups []types.Group
-	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &groups); err != nil {
+		return nil, err
 	}
 
 	return groups, nil
 }
 
+// GetChatLabels retrieves the labels of a chat. Labels only exist on WhatsApp Business accounts;
+// an unknown chat returns no labels, while other accounts typically get an error from WAHA.
+func (c *WhatsAppClient) GetChatLabels(ctx context.Context, chatID string) ([]types.Label, error) {
+	reqURL := fmt.Sprintf("%s%s/%s%s/%s", c.baseURL, types.APIBase, url.PathEscape(c.sessionName), types.EndpointLabelsChats, url.PathEscape(chatID))
+	var labels []types.Label
+	if err := c.doGetJSON(ctx, reqURL, &labels); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return labels, nil
+}
+
+// GetChatMessages returns up to limit of the most recent messages of a chat in sessionName,
+// newest first, without downloading their media. An unknown chat has no messages.
+func (c *WhatsAppClient) GetChatMessages(ctx context.Context, chatID string, limit int, sessionName string) ([]types.ChatMessage, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chatID cannot be empty")
+	}
+	if sessionName == "" {
+		sessionName = c.sessionName
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("downloadMedia", "false")
+	reqURL := fmt.Sprintf("%s%s/%s%s/%s%s?%s", c.baseURL, types.APIBase, url.PathEscape(sessionName), types.EndpointChats, url.PathEscape(chatID), types.EndpointChatMessages, query.Encode())
+	var messages []types.ChatMessage
+	if err := c.doGetJSON(ctx, reqURL, &messages); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get chat messages: %w", err)
+	}
+	return messages, nil
+}
+
 // getServerVersion retrieves the WAHA server version info
 func (c *WhatsAppClient) getServerVersion(ctx context.Context) (*types.ServerVersion, error) {
 	url := fmt.Sprintf("%s/api/server/version", c.baseURL)
@@ -878,6 +981,41 @@ func (c *WhatsAppClient) getServerVersion(ctx context.Context) (*types.ServerVer
 	return &version, nil
 }
 
+// GetCapabilities returns the features supported by the WAHA server. The server version is
+// fetched once and cached; if it cannot be fetched, unknown capabilities are cached instead.
+func (c *WhatsAppClient) GetCapabilities(ctx context.Context) types.Capabilities {
+	c.capabilitiesMu.RLock()
+	if c.capabilities != nil {
+		capabilities := *c.capabilities
+		c.capabilitiesMu.RUnlock()
+		return capabilities
+	}
+	c.capabilitiesMu.RUnlock()
+
+	version, err := c.getServerVersion(ctx)
+	if err != nil && c.logger != nil {
+		// Log error but don't fail - fall back to unknown capabilities
+		c.logger.WithError(err).Warn("Failed to detect WAHA capabilities")
+	}
+	capabilities := types.NewCapabilities(version)
+
+	if err == nil && c.logger != nil {
+		c.logger.WithFields(logrus.Fields{
+			"engine":    capabilities.Engine,
+			"tier":      capabilities.Tier,
+			"version":   capabilities.Version,
+			"reactions": capabilities.SupportsReactions(),
+			"polls":     capabilities.SupportsPolls(),
+			"buttons":   capabilities.SupportsButtons(),
+		}).Info("Detected WAHA capabilities")
+	}
+
+	c.capabilitiesMu.Lock()
+	c.capabilities = &capabilities
+	c.capabilitiesMu.Unlock()
+	return capabilities
+}
+
 // checkVideoSupport checks if the WAHA server supports video sending
 func (c *WhatsAppClient) checkVideoSupport(ctx context.Context) bool {
 	// Return cached value if already checked
@@ -889,31 +1027,14 @@ func (c *WhatsAppClient) checkVideoSupport(ctx context.Context) bool {
 	}
 	c.supportsVideoMu.RUnlock()
 
-	// Default to false
-	supportsVideo := false
-
-	// Get server version
-	version, err := c.getServerVersion(ctx)
-	if err != nil {
-		// Log error but don't fail - assume no video support
-		if c.logger != nil {
-			c.logger.WithError(err).Warn("Failed to check WAHA version for video support")
-		}
-		c.supportsVideoMu.Lock()
-		c.supportsVideo = &supportsVideo
-		c.supportsVideoMu.Unlock()
-		return supportsVideo
-	}
-
-	// Check if tier is PLUS and browser is chrome
-	if version.Tier == "PLUS" && strings.Contains(version.Browser, "google-chrome") {
-		supportsVideo = true
-		if c.logger != nil {
-			c.logger.WithFields(logrus.Fields{"tier": version.Tier, "browser": version.Browser}).Info("WAHA Plus detected - video sending enabled")
-		}
-	} else {
-		if c.logger != nil {
-			c.logger.WithFields(logrus.Fields{"tier": version.Tier, "browser": version.Browser}).Info("WAHA version does not support video - videos will be sent as documents")
+	capabilities := c.GetCapabilities(ctx)
+	supportsVideo := capabilities.SupportsVideo()
+	if c.logger != nil {
+		fields := logrus.Fields{"tier": capabilities.Tier, "browser": capabilities.Browser}
+		if supportsVideo {
+			c.logger.WithFields(fields).Info("WAHA Plus detected - video sending enabled")
+		} else {
+			c.logger.WithFields(fields).Info("WAHA version does not support video - videos will be sent as documents")
 		}
 	}
 
@@ -955,10 +1076,5 @@ func (c *WhatsAppClient) HealthCheck(ctx context.Context) error {
 		return nil
 	}
 
-	// Read the response body for error details
-	body, readErr := httputil.ReadLimitedBody(resp.Body, int64(constants.DefaultWebhookMaxBytes))
-	if readErr != nil {
-		return fmt.Errorf("WhatsApp API health check returned status %d (failed to read body: %v)", resp.StatusCode, readErr)
-	}
-	return fmt.Errorf("WhatsApp API health check returned status %d: %s", resp.StatusCode, string(body))
+	return statusError(fmt.Sprintf("WhatsApp API health check returned status %d", resp.StatusCode), resp)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -278,7 +279,7 @@ func TestClient_Session(t *testing.T) {
 	ctx := context.Background()
 
 	// Test session lifecycle
-	err := client.CreateSession(ctx)
+	err := client.CreateSession(ctx, nil)
 	require.NoError(t, err)
 
 	err = client.StartSession(ctx)
@@ -292,6 +293,58 @@ func TestClient_Session(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClient_CreateSessionSendsConfig(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, testEndpointSessions, r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(types.ClientConfig{
+		BaseURL:     server.URL,
+		APIKey:      "test-api-key",
+		SessionName: "test-session",
+		Timeout:     5 * time.Second,
+	})
+
+	err := client.CreateSession(context.Background(), &types.SessionConfig{
+		Webhooks: []types.SessionWebhook{{
+			URL:    "http://whatsignal:8082/webhook/whatsapp",
+			Events: []string{"message", "message.reaction"},
+			HMAC:   &types.WebhookHMAC{Key: "secret"},
+		}},
+		Proxy: &types.SessionProxy{Server: "proxy:3128"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-session", body["name"])
+	config := body["config"].(map[string]interface{})
+	webhooks := config["webhooks"].([]interface{})
+	require.Len(t, webhooks, 1)
+	webhook := webhooks[0].(map[string]interface{})
+	assert.Equal(t, "http://whatsignal:8082/webhook/whatsapp", webhook["url"])
+	assert.Equal(t, []interface{}{"message", "message.reaction"}, webhook["events"])
+	assert.Equal(t, map[string]interface{}{"key": "secret"}, webhook["hmac"])
+	assert.Equal(t, map[string]interface{}{"server": "proxy:3128"}, config["proxy"])
+}
+
+func TestClient_CreateSessionWithoutConfig(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(types.ClientConfig{BaseURL: server.URL, SessionName: "test-session", Timeout: 5 * time.Second})
+	require.NoError(t, client.CreateSession(context.Background(), nil))
+	assert.Equal(t, map[string]interface{}{"name": "test-session"}, body)
+}
+
 func TestClient_SendText(t *testing.T) {
 	client, server := setupTestClient(t)
 	defer server.Close()
@@ -340,6 +393,127 @@ func TestClient_SendText_EmptyResponse(t *testing.T) {
 	assert.Equal(t, "", resp.MessageID) // Empty message ID is acceptable
 }
 
+func TestClient_SendMentions(t *testing.T) {
+	var received types.SendMessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != testAPIBase+testEndpointSendText {
+			w.WriteHeader(http.StatusOK) // typing indicators
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"msg123","status":"sent"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(types.ClientConfig{
+		BaseURL:     server.URL,
+		APIKey:      "test-api-key",
+		SessionName: "test-session",
+		Timeout:     5 * time.Second,
+	})
+
+	_, err := client.SendMentionsWithSession(context.Background(), "group@g.us", "@15550001111 hi", []string{"15550001111@c.us"}, "", "test-session")
+	require.NoError(t, err)
+	assert.Equal(t, "@15550001111 hi", received.Text)
+	assert.Equal(t, []string{"15550001111@c.us"}, received.Mentions)
+
+	received = types.SendMessageRequest{}
+	_, err = client.SendTextWithSession(context.Background(), "group@g.us", "plain", "", "test-session")
+	require.NoError(t, err)
+	assert.Nil(t, received.Mentions)
+}
+
+func TestClient_SendLocation(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantMessageID string
+		wantWarning   bool
+		wantErr       bool
+	}{
+		{
+			name:          "message ID",
+			status:        http.StatusCreated,
+			body:          `{"id":{"fromMe":true,"remote":"123456@c.us","id":"loc1","_serialized":"true_123456@c.us_loc1"}}`,
+			wantMessageID: "true_123456@c.us_loc1",
+		},
+		{
+			name:          "message ID in engine data",
+			status:        http.StatusOK,
+			body:          `{"_data":{"id":{"_serialized":"true_123456@c.us_loc2"}}}`,
+			wantMessageID: "true_123456@c.us_loc2",
+		},
+		{
+			name:   "empty body",
+			status: http.StatusCreated,
+		},
+		{
+			name:        "invalid JSON",
+			status:      http.StatusCreated,
+			body:        `{"id":`,
+			wantWarning: true,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusInternalServerError,
+			body:    `{"error":"session not ready"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received types.LocationMessageRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/api/sendLocation", r.URL.Path)
+				assert.Equal(t, "test-api-key", r.Header.Get("X-Api-Key"))
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+				if tt.body != "" {
+					w.Header().Set("Content-Type", "application/json")
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(types.ClientConfig{
+				BaseURL:     server.URL,
+				APIKey:      "test-api-key",
+				SessionName: "test-session",
+				Timeout:     5 * time.Second,
+			}).(*WhatsAppClient)
+
+			resp, err := client.SendLocation(context.Background(), "123456@c.us", 52.370216, 4.895168, "Dam Square")
+
+			assert.Equal(t, types.LocationMessageRequest{
+				ChatID:    "123456@c.us",
+				Session:   "test-session",
+				Latitude:  52.370216,
+				Longitude: 4.895168,
+				Title:     "Dam Square",
+			}, received)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, resp)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "sent", resp.Status)
+			assert.Equal(t, tt.wantMessageID, resp.MessageID)
+			if tt.wantWarning {
+				assert.Contains(t, resp.Error, "could not parse response")
+			} else {
+				assert.Empty(t, resp.Error)
+			}
+		})
+	}
+}
+
 func TestClient_SendImage(t *testing.T) {
 	// Create a temporary file
 	tmpFile, err := os.CreateTemp("", "test-image-*.jpg")
@@ -844,6 +1018,113 @@ func TestSendReaction(t *testing.T) {
 	assert.Equal(t, "reaction124", resp.MessageID)
 }
 
+func TestStarMessage(t *testing.T) {
+	var received []types.StarRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/star", r.URL.Path)
+		assert.Equal(t, "test-api-key", r.Header.Get("X-Api-Key"))
+
+		var payload types.StarRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received = append(received, payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(types.ClientConfig{
+		BaseURL:     server.URL,
+		APIKey:      "test-api-key",
+		SessionName: "default",
+		Timeout:     10 * time.Second,
+	}).(*WhatsAppClient)
+
+	require.NoError(t, client.StarMessage(context.Background(), "chat123@c.us", "msg456", true))
+	require.NoError(t, client.StarMessageWithSession(context.Background(), "chat123@c.us", "msg456", false, "business"))
+
+	assert.Equal(t, []types.StarRequest{
+		{Session: "default", ChatID: "chat123@c.us", MessageID: "msg456", Star: true},
+		{Session: "business", ChatID: "chat123@c.us", MessageID: "msg456", Star: false},
+	}, received)
+}
+
+func TestStarMessage_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "Message not found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(types.ClientConfig{
+		BaseURL:     server.URL,
+		SessionName: "default",
+		Timeout:     10 * time.Second,
+	})
+
+	err := client.StarMessageWithSession(context.Background(), "chat123@c.us", "missing", true, "default")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+
+	assert.Error(t, client.StarMessageWithSession(context.Background(), "", "msg456", true, "default"))
+	assert.Error(t, client.StarMessageWithSession(context.Background(), "chat123@c.us", "", true, "default"))
+}
+
+func TestEditMessage(t *testing.T) {
+	type editCall struct {
+		path    string
+		payload types.EditMessageRequest
+	}
+	var received []editCall
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "test-api-key", r.Header.Get("X-Api-Key"))
+
+		var payload types.EditMessageRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received = append(received, editCall{path: r.URL.Path, payload: payload})
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(types.ClientConfig{
+		BaseURL:     server.URL,
+		APIKey:      "test-api-key",
+		SessionName: "default",
+		Timeout:     10 * time.Second,
+	}).(*WhatsAppClient)
+
+	require.NoError(t, client.EditMessage(context.Background(), "chat123@c.us", "msg456", "fixed typo"))
+	require.NoError(t, client.EditMessageWithSession(context.Background(), "chat123@c.us", "msg789", "second edit", "business"))
+
+	assert.Equal(t, []editCall{
+		{path: "/api/default/chats/chat123@c.us/messages/msg456", payload: types.EditMessageRequest{Text: "fixed typo"}},
+		{path: "/api/business/chats/chat123@c.us/messages/msg789", payload: types.EditMessageRequest{Text: "second edit"}},
+	}, received)
+}
+
+func TestEditMessage_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "Message not found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(types.ClientConfig{
+		BaseURL:     server.URL,
+		SessionName: "default",
+		Timeout:     10 * time.Second,
+	})
+
+	err := client.EditMessageWithSession(context.Background(), "chat123@c.us", "missing", "text", "default")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "edit failed with status 404")
+
+	assert.Error(t, client.EditMessageWithSession(context.Background(), "", "msg456", "text", "default"))
+	assert.Error(t, client.EditMessageWithSession(context.Background(), "chat123@c.us", "", "text", "default"))
+	assert.Error(t, client.EditMessageWithSession(context.Background(), "chat123@c.us", "msg456", "  ", "default"))
+}
+
 func TestDeleteMessage(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -1540,6 +1821,28 @@ func TestClient_GetGroup(t *testing.T) {
 	assert.False(t, group.Participants[1].IsAdmin)
 }
 
+func TestClient_GetGroupWithSession(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"group123@g.us","subject":"Suppliers"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(types.ClientConfig{
+		BaseURL:     server.URL,
+		SessionName: "default",
+		APIKey:      "test-key",
+	})
+
+	group, err := client.GetGroupWithSession(context.Background(), "group123@g.us", "business")
+	require.NoError(t, err)
+	require.NotNil(t, group)
+	assert.Equal(t, "/api/business/groups/group123@g.us", gotPath, "the group is fetched through the given session")
+	assert.Equal(t, "Suppliers", group.Subject)
+}
+
 func TestClient_GetAllGroups(t *testing.T) {
 	client, server := setupTestClient(t)
 	defer server.Close()
@@ -1556,6 +1859,146 @@ func TestClient_GetAllGroups(t *testing.T) {
 	assert.Equal(t, "Group 2", groups[1].Subject)
 }
 
+func TestClient_GetChatLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantLabels []types.Label
+		wantErr    bool
+	}{
+		{
+			name:   "chat with labels",
+			status: http.StatusOK,
+			body:   `[{"id":"1","name":"Lead","color":0,"colorHex":"#ff9485"},{"id":"5","name":"VIP","color":4,"colorHex":"#64c4ff"}]`,
+			wantLabels: []types.Label{
+				{ID: "1", Name: "Lead", Color: 0, ColorHex: "#ff9485"},
+				{ID: "5", Name: "VIP", Color: 4, ColorHex: "#64c4ff"},
+			},
+		},
+		{
+			name:       "chat without labels",
+			status:     http.StatusOK,
+			body:       `[]`,
+			wantLabels: []types.Label{},
+		},
+		{
+			name:   "unknown chat",
+			status: http.StatusNotFound,
+			body:   `{"error":"not found"}`,
+		},
+		{
+			name:    "not a business account",
+			status:  http.StatusBadRequest,
+			body:    `{"error":"labels are only available for WhatsApp Business"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.EscapedPath()
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "test-key", r.Header.Get("X-Api-Key"))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(types.ClientConfig{
+				BaseURL:     server.URL,
+				SessionName: "business",
+				APIKey:      "test-key",
+			})
+
+			labels, err := client.GetChatLabels(context.Background(), "123456789@c.us")
+			assert.Equal(t, "/api/business/labels/chats/123456789@c.us", gotPath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, labels)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLabels, labels)
+		})
+	}
+}
+
+func TestClient_GetChatMessages(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantMessages []types.ChatMessage
+		wantErr      bool
+	}{
+		{
+			name:   "chat with history",
+			status: http.StatusOK,
+			body:   `[{"id":"true_123@c.us_B","timestamp":1700000060,"from":"123@c.us","fromMe":true,"body":"See you","hasMedia":false},{"id":"false_123@c.us_A","timestamp":1700000000,"from":"123@c.us","fromMe":false,"body":"","hasMedia":true}]`,
+			wantMessages: []types.ChatMessage{
+				{ID: "true_123@c.us_B", Timestamp: 1700000060, From: "123@c.us", FromMe: true, Body: "See you"},
+				{ID: "false_123@c.us_A", Timestamp: 1700000000, From: "123@c.us", HasMedia: true},
+			},
+		},
+		{
+			name:   "unknown chat",
+			status: http.StatusNotFound,
+			body:   `{"error":"not found"}`,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusInternalServerError,
+			body:    `{"error":"boom"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotQuery url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.EscapedPath()
+				gotQuery = r.URL.Query()
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "test-key", r.Header.Get("X-Api-Key"))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(types.ClientConfig{
+				BaseURL:     server.URL,
+				SessionName: "default",
+				APIKey:      "test-key",
+			})
+
+			messages, err := client.GetChatMessages(context.Background(), "123@c.us", 50, "personal")
+			assert.Equal(t, "/api/personal/chats/123@c.us/messages", gotPath)
+			assert.Equal(t, "50", gotQuery.Get("limit"))
+			assert.Equal(t, "false", gotQuery.Get("downloadMedia"))
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, messages)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMessages, messages)
+		})
+	}
+
+	t.Run("empty chat ID", func(t *testing.T) {
+		client := NewClient(types.ClientConfig{BaseURL: "http://localhost", SessionName: "default"})
+		_, err := client.GetChatMessages(context.Background(), "", 50, "default")
+		assert.Error(t, err)
+	})
+}
+
 func TestClient_RedirectBlocked(t *testing.T) {
 	redirectTarget := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("redirect target should never be reached")
@@ -1727,3 +2170,32 @@ func TestSendReaction_Legacy(t *testing.T) {
 	assert.Equal(t, "msg123", receivedPayload.MessageID)
 	assert.Equal(t, "👍", receivedPayload.Reaction)
 }
+
+func TestClient_GetCapabilitiesIsCached(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/server/version" {
+			callCount++
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(types.ServerVersion{Version: "2024.2.3", Engine: "NOWEB", Tier: "CORE"})
+		}
+	}))
+	defer server.Close()
+
+	client := &WhatsAppClient{
+		baseURL:        server.URL,
+		client:         &http.Client{Timeout: 5 * time.Second},
+		circuitBreaker: circuitbreaker.New("test", 5, time.Second),
+	}
+
+	ctx := context.Background()
+	caps := client.GetCapabilities(ctx)
+	assert.Equal(t, types.EngineNOWEB, caps.Engine)
+	assert.True(t, caps.SupportsPolls())
+	assert.False(t, caps.SupportsButtons())
+
+	// Video detection reuses the cached capabilities
+	assert.False(t, client.checkVideoSupport(ctx))
+	assert.Equal(t, caps, client.GetCapabilities(ctx))
+	assert.Equal(t, 1, callCount)
+}
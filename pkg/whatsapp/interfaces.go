@@ -8,7 +8,7 @@ import (
 
 // SessionManager handles WhatsApp session lifecycle
 type SessionManager interface {
-	Create(ctx context.Context, name string) (*types.Session, error)
+	Create(ctx context.Context, name string, config *types.SessionConfig) (*types.Session, error)
 	Get(ctx context.Context, name string) (*types.Session, error)
 	Start(ctx context.Context, name string) error
 	Stop(ctx context.Context, name string) error
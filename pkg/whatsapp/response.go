@@ -0,0 +1,117 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"whatsignal/internal/constants"
+	"whatsignal/internal/httputil"
+	"whatsignal/pkg/whatsapp/types"
+)
+
+// UnexpectedResponseError is returned when WAHA answers with something other than JSON, typically
+// an HTML error page from a proxy in front of it. Snippet holds the start of the body for diagnosis.
+type UnexpectedResponseError struct {
+	StatusCode  int
+	ContentType string
+	Snippet     string
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = "unknown content type"
+	}
+	return fmt.Sprintf("unexpected non-JSON response (status %d, %s): %s", e.StatusCode, contentType, e.Snippet)
+}
+
+// readResponse reads a WAHA response body. Empty bodies are returned as nil, since WAHA answers
+// some requests with no content; a body that is not JSON yields an *UnexpectedResponseError.
+func readResponse(resp *http.Response) ([]byte, error) {
+	body, err := httputil.ReadLimitedBody(resp.Body, int64(constants.DefaultWebhookMaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	if !isJSONResponse(resp.Header.Get("Content-Type"), body) {
+		return nil, newUnexpectedResponseError(resp, body)
+	}
+	return body, nil
+}
+
+// decodeResponse reads a successful WAHA response into target. An empty body leaves target untouched.
+func decodeResponse(resp *http.Response, target interface{}) error {
+	body, err := readResponse(resp)
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// statusError builds the error for a WAHA request that failed with a non-success status. prefix
+// describes the failure, e.g. "delete failed with status 500"; WAHA's own error message is appended
+// when the body carries one, otherwise the start of the body.
+func statusError(prefix string, resp *http.Response) error {
+	body, err := readResponse(resp)
+	var unexpected *UnexpectedResponseError
+	switch {
+	case errors.As(err, &unexpected):
+		return fmt.Errorf("%s: %w", prefix, err)
+	case err != nil:
+		return fmt.Errorf("%s (failed to read error response: %w)", prefix, err)
+	case body == nil:
+		return errors.New(prefix)
+	}
+
+	var errorResp types.WAHAErrorResponse
+	if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Error != "" {
+		return fmt.Errorf("%s: %s", prefix, errorResp.Error)
+	}
+	return fmt.Errorf("%s: %s", prefix, responseSnippet(body))
+}
+
+// isJSONResponse reports whether a body can be treated as JSON. A JSON content type is trusted;
+// without one, which is common for plain WAHA deployments, the body must look like a JSON
+// object or array.
+func isJSONResponse(contentType string, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return true
+	}
+	if mediaType == "text/html" || strings.HasSuffix(mediaType, "xml") {
+		return false
+	}
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+func newUnexpectedResponseError(resp *http.Response, body []byte) *UnexpectedResponseError {
+	return &UnexpectedResponseError{
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Snippet:     responseSnippet(body),
+	}
+}
+
+// responseSnippet collapses whitespace in body and truncates it to ResponseSnippetLength characters
+func responseSnippet(body []byte) string {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	runes := []rune(snippet)
+	if len(runes) > constants.ResponseSnippetLength {
+		return string(runes[:constants.ResponseSnippetLength]) + "...[truncated]"
+	}
+	return snippet
+}
@@ -0,0 +1,134 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"whatsignal/internal/constants"
+	"whatsignal/pkg/whatsapp/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const proxyErrorPage = `<!DOCTYPE html>
+<html>
+<head><title>502 Bad Gateway</title></head>
+<body>
+<center><h1>502 Bad Gateway</h1></center>
+<hr><center>nginx</center>
+</body>
+</html>`
+
+func newHTMLErrorClient(t *testing.T, status int, page string) *WhatsAppClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(page))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(types.ClientConfig{
+		BaseURL:     server.URL,
+		SessionName: "test-session",
+		Timeout:     5 * time.Second,
+	}).(*WhatsAppClient)
+}
+
+func TestHTMLErrorPage_ReturnsUnexpectedResponseError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		call   func(c *WhatsAppClient) error
+		prefix string
+	}{
+		{
+			name:   "ack",
+			status: http.StatusBadGateway,
+			call: func(c *WhatsAppClient) error {
+				return c.AckMessage(context.Background(), "123@c.us", "test-session")
+			},
+			prefix: "request failed with status 502",
+		},
+		{
+			name:   "delete",
+			status: http.StatusBadGateway,
+			call: func(c *WhatsAppClient) error {
+				return c.DeleteMessage(context.Background(), "123@c.us", "msg123")
+			},
+			prefix: "delete failed with status 502",
+		},
+		{
+			name:   "ack with success status",
+			status: http.StatusOK,
+			call: func(c *WhatsAppClient) error {
+				return c.AckMessage(context.Background(), "123@c.us", "test-session")
+			},
+		},
+		{
+			name:   "get contact",
+			status: http.StatusOK,
+			call: func(c *WhatsAppClient) error {
+				_, err := c.GetContact(context.Background(), "123@c.us")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newHTMLErrorClient(t, tt.status, proxyErrorPage)
+
+			err := tt.call(client)
+			require.Error(t, err)
+
+			var respErr *UnexpectedResponseError
+			require.True(t, errors.As(err, &respErr), "expected UnexpectedResponseError, got %v", err)
+			assert.Equal(t, tt.status, respErr.StatusCode)
+			assert.Equal(t, "text/html; charset=utf-8", respErr.ContentType)
+			assert.Contains(t, respErr.Snippet, "<title>502 Bad Gateway</title>")
+			assert.NotContains(t, respErr.Snippet, "\n")
+			if tt.prefix != "" {
+				assert.True(t, strings.HasPrefix(err.Error(), tt.prefix), err.Error())
+			}
+		})
+	}
+}
+
+func TestUnexpectedResponseError_TruncatesSnippet(t *testing.T) {
+	page := "<html><body>" + strings.Repeat("x", 5000) + "</body></html>"
+	client := newHTMLErrorClient(t, http.StatusServiceUnavailable, page)
+
+	err := client.DeleteMessage(context.Background(), "123@c.us", "msg123")
+
+	var respErr *UnexpectedResponseError
+	require.True(t, errors.As(err, &respErr))
+	assert.Equal(t, constants.ResponseSnippetLength+len("...[truncated]"), len(respErr.Snippet))
+	assert.True(t, strings.HasSuffix(respErr.Snippet, "...[truncated]"))
+	assert.Less(t, len(err.Error()), 400)
+}
+
+func TestIsJSONResponse(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		want        bool
+	}{
+		{"application/json", `{"ok":true}`, true},
+		{"application/problem+json", `{"title":"bad"}`, true},
+		{"text/plain; charset=utf-8", `[{"id":"1"}]`, true},
+		{"", `{"ok":true}`, true},
+		{"text/html", `{"ok":true}`, false},
+		{"", "<html></html>", false},
+		{"text/plain", "Bad Gateway", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, isJSONResponse(tt.contentType, []byte(tt.body)), "%q %q", tt.contentType, tt.body)
+	}
+}
@@ -1,6 +1,7 @@
 package whatsapp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -34,7 +35,9 @@ func (sm *sessionManager) doRequest(req *http.Request) (*http.Response, error) {
 	return sm.client.Do(req) // #nosec G704 - URL from trusted application config
 }
 
-func (sm *sessionManager) Create(ctx context.Context, name string) (*types.Session, error) {
+// Create creates a session in WAHA. config, when not nil, sets up the session's webhooks and
+// proxy as part of the same request.
+func (sm *sessionManager) Create(ctx context.Context, name string, config *types.SessionConfig) (*types.Session, error) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
@@ -49,10 +52,16 @@ func (sm *sessionManager) Create(ctx context.Context, name string) (*types.Sessi
 		UpdatedAt: time.Now(),
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/sessions", sm.baseURL), nil)
+	body, err := json.Marshal(types.CreateSessionRequest{Name: name, Config: config})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session config: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/sessions", sm.baseURL), bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
 	if sm.apiKey != "" {
 		req.Header.Set("X-Api-Key", sm.apiKey)
@@ -64,7 +73,7 @@ func (sm *sessionManager) Create(ctx context.Context, name string) (*types.Sessi
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, fmt.Errorf("failed to create session, status: %d", resp.StatusCode)
 	}
 
@@ -101,7 +110,7 @@ func (sm *sessionManager) Get(ctx context.Context, name string) (*types.Session,
 	}
 
 	var serverSession types.Session
-	if err := json.NewDecoder(resp.Body).Decode(&serverSession); err != nil {
+	if err := decodeResponse(resp, &serverSession); err != nil {
 		return nil, fmt.Errorf("failed to decode session response: %w", err)
 	}
 
@@ -73,13 +73,13 @@ func TestSessionManager_Create(t *testing.T) {
 	ctx := context.Background()
 
 	// Test successful creation
-	session, err := sm.Create(ctx, "test")
+	session, err := sm.Create(ctx, "test", nil)
 	require.NoError(t, err)
 	assert.Equal(t, "test", session.Name)
 	assert.Equal(t, types.SessionStatusInitialized, session.Status)
 
 	// Test duplicate creation
-	_, err = sm.Create(ctx, "test")
+	_, err = sm.Create(ctx, "test", nil)
 	assert.Error(t, err)
 }
 
@@ -90,7 +90,7 @@ func TestSessionManager_Get(t *testing.T) {
 	ctx := context.Background()
 
 	// Create a session first
-	_, err := sm.Create(ctx, "test")
+	_, err := sm.Create(ctx, "test", nil)
 	require.NoError(t, err)
 
 	// Test successful get
@@ -111,7 +111,7 @@ func TestSessionManager_Start(t *testing.T) {
 	ctx := context.Background()
 
 	// Create a session first
-	_, err := sm.Create(ctx, "test")
+	_, err := sm.Create(ctx, "test", nil)
 	require.NoError(t, err)
 
 	// Test successful start
@@ -134,7 +134,7 @@ func TestSessionManager_Stop(t *testing.T) {
 	ctx := context.Background()
 
 	// Create and start a session first
-	_, err := sm.Create(ctx, "test")
+	_, err := sm.Create(ctx, "test", nil)
 	require.NoError(t, err)
 	err = sm.Start(ctx, "test")
 	require.NoError(t, err)
@@ -159,7 +159,7 @@ func TestSessionManager_Delete(t *testing.T) {
 	ctx := context.Background()
 
 	// Create a session first
-	_, err := sm.Create(ctx, "test")
+	_, err := sm.Create(ctx, "test", nil)
 	require.NoError(t, err)
 
 	// Test successful delete
@@ -191,7 +191,7 @@ func TestSessionManager_ApiKeyHeader(t *testing.T) {
 	sm := NewSessionManager(server.URL, apiKey, 5*time.Second)
 	ctx := context.Background()
 
-	_, err := sm.Create(ctx, "test")
+	_, err := sm.Create(ctx, "test", nil)
 	require.NoError(t, err)
 	assert.Equal(t, apiKey, receivedApiKey)
 }
@@ -0,0 +1,91 @@
+package types
+
+import "strings"
+
+// WAHA engines as reported by /api/server/version
+const (
+	EngineWEBJS = "WEBJS"
+	EngineNOWEB = "NOWEB"
+	EngineGOWS  = "GOWS"
+	EngineVENOM = "VENOM"
+
+	TierCore = "CORE"
+	TierPlus = "PLUS"
+)
+
+// Capabilities describes which optional features the connected WAHA server supports,
+// derived from its engine, tier and browser. The zero value describes a server that
+// could not be identified: only features WhatSignal has always used are assumed.
+type Capabilities struct {
+	Version string
+	Engine  string
+	Tier    string
+	Browser string
+}
+
+// NewCapabilities derives capabilities from WAHA server version info
+func NewCapabilities(version *ServerVersion) Capabilities {
+	if version == nil {
+		return Capabilities{}
+	}
+	return Capabilities{
+		Version: version.Version,
+		Engine:  strings.ToUpper(strings.TrimSpace(version.Engine)),
+		Tier:    strings.ToUpper(strings.TrimSpace(version.Tier)),
+		Browser: version.Browser,
+	}
+}
+
+// Known reports whether the server engine was identified
+func (c Capabilities) Known() bool {
+	return c.Engine != ""
+}
+
+// IsPlus reports whether the server runs the WAHA Plus tier
+func (c Capabilities) IsPlus() bool {
+	return c.Tier == TierPlus
+}
+
+// SupportsVideo reports whether videos can be sent as playable videos.
+// Video conversion requires WAHA Plus running with Google Chrome.
+func (c Capabilities) SupportsVideo() bool {
+	return c.IsPlus() && strings.Contains(c.Browser, "google-chrome")
+}
+
+// SupportsReactions reports whether /api/reaction is available. Reactions are assumed
+// supported when the engine is unknown, matching behavior before detection existed.
+func (c Capabilities) SupportsReactions() bool {
+	switch c.Engine {
+	case "", EngineWEBJS, EngineNOWEB, EngineGOWS:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsPolls reports whether /api/sendPoll is available
+func (c Capabilities) SupportsPolls() bool {
+	switch c.Engine {
+	case EngineWEBJS, EngineNOWEB, EngineGOWS:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsButtons reports whether /api/sendButtons is available, which only
+// the WEBJS engine offers on the Plus tier
+func (c Capabilities) SupportsButtons() bool {
+	return c.Engine == EngineWEBJS && c.IsPlus()
+}
+
+// String describes the server for log and user-facing messages
+func (c Capabilities) String() string {
+	if !c.Known() {
+		return "unknown WAHA engine"
+	}
+	if c.Tier == "" {
+		return "WAHA " + c.Engine
+	}
+	return "WAHA " + c.Engine + " (" + c.Tier + ")"
+}
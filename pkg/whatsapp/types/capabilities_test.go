@@ -0,0 +1,77 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCapabilities(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   *ServerVersion
+		known     bool
+		video     bool
+		reactions bool
+		polls     bool
+		buttons   bool
+	}{
+		{
+			name:      "WEBJS plus with chrome",
+			version:   &ServerVersion{Version: "2024.2.3", Engine: "WEBJS", Tier: "PLUS", Browser: "/usr/bin/google-chrome-stable"},
+			known:     true,
+			video:     true,
+			reactions: true,
+			polls:     true,
+			buttons:   true,
+		},
+		{
+			name:      "WEBJS core",
+			version:   &ServerVersion{Version: "2024.2.3", Engine: "WEBJS", Tier: "CORE"},
+			known:     true,
+			reactions: true,
+			polls:     true,
+		},
+		{
+			name:      "NOWEB plus without chrome",
+			version:   &ServerVersion{Version: "2024.2.3", Engine: "NOWEB", Tier: "PLUS", Browser: "/usr/bin/firefox"},
+			known:     true,
+			reactions: true,
+			polls:     true,
+		},
+		{
+			name:      "GOWS core with lowercase fields",
+			version:   &ServerVersion{Version: "2025.1.1", Engine: "gows", Tier: "core"},
+			known:     true,
+			reactions: true,
+			polls:     true,
+		},
+		{
+			name:    "VENOM",
+			version: &ServerVersion{Version: "2023.10.1", Engine: "VENOM", Tier: "CORE"},
+			known:   true,
+		},
+		{
+			name:      "unknown server",
+			version:   nil,
+			reactions: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps := NewCapabilities(tt.version)
+			assert.Equal(t, tt.known, caps.Known())
+			assert.Equal(t, tt.video, caps.SupportsVideo(), "video")
+			assert.Equal(t, tt.reactions, caps.SupportsReactions(), "reactions")
+			assert.Equal(t, tt.polls, caps.SupportsPolls(), "polls")
+			assert.Equal(t, tt.buttons, caps.SupportsButtons(), "buttons")
+		})
+	}
+}
+
+func TestCapabilities_String(t *testing.T) {
+	assert.Equal(t, "unknown WAHA engine", Capabilities{}.String())
+	assert.Equal(t, "WAHA VENOM", Capabilities{Engine: EngineVENOM}.String())
+	assert.Equal(t, "WAHA NOWEB (PLUS)", Capabilities{Engine: EngineNOWEB, Tier: TierPlus}.String())
+}
@@ -10,16 +10,18 @@ const (
 )
 
 const (
-	APIBase             = "/api"
-	EndpointSendText    = "/sendText"
-	EndpointSendSeen    = "/sendSeen"
-	EndpointStartTyping = "/startTyping"
-	EndpointStopTyping  = "/stopTyping"
-	EndpointSendImage   = "/sendImage"
-	EndpointSendFile    = "/sendFile"
-	EndpointSendVoice   = "/sendVoice"
-	EndpointSendVideo   = "/sendVideo"
-	EndpointReaction    = "/reaction"
+	APIBase              = "/api"
+	EndpointSendText     = "/sendText"
+	EndpointSendSeen     = "/sendSeen"
+	EndpointStartTyping  = "/startTyping"
+	EndpointStopTyping   = "/stopTyping"
+	EndpointSendImage    = "/sendImage"
+	EndpointSendFile     = "/sendFile"
+	EndpointSendVoice    = "/sendVoice"
+	EndpointSendVideo    = "/sendVideo"
+	EndpointSendLocation = "/sendLocation"
+	EndpointReaction     = "/reaction"
+	EndpointStar         = "/star"
 
 	// Contact endpoints
 	EndpointContactsAll = "/contacts/all"
@@ -28,4 +30,14 @@ const (
 	// Group endpoints
 	EndpointGroups    = "/groups"
 	EndpointGroupsAll = "/groups"
+
+	// Chat endpoints, under /api/{session}/chats/{chatId}
+	EndpointChats        = "/chats"
+	EndpointChatMessages = "/messages"
+
+	// Label endpoints (WhatsApp Business)
+	EndpointLabelsChats = "/labels/chats"
+
+	// Session endpoints, under /api/sessions/{session}
+	EndpointSessionMe = "/me"
 )
@@ -95,6 +95,11 @@ func TestAPIEndpointConstants(t *testing.T) {
 			endpoint: EndpointSendVideo,
 			expected: "/sendVideo",
 		},
+		{
+			name:     "send location endpoint",
+			endpoint: EndpointSendLocation,
+			expected: "/sendLocation",
+		},
 	}
 
 	for _, tt := range tests {
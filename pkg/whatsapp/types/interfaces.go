@@ -7,13 +7,18 @@ import (
 
 type WAClient interface {
 	SendTextWithSession(ctx context.Context, chatID, message, replyTo, sessionName string) (*SendMessageResponse, error)
+	SendMentionsWithSession(ctx context.Context, chatID, message string, mentions []string, replyTo, sessionName string) (*SendMessageResponse, error)
 	SendImageWithSession(ctx context.Context, chatID, imagePath, caption, replyTo, sessionName string) (*SendMessageResponse, error)
 	SendVideoWithSession(ctx context.Context, chatID, videoPath, caption, replyTo, sessionName string) (*SendMessageResponse, error)
 	SendDocumentWithSession(ctx context.Context, chatID, docPath, caption, replyTo, sessionName string) (*SendMessageResponse, error)
 	SendVoiceWithSession(ctx context.Context, chatID, voicePath, replyTo, sessionName string) (*SendMessageResponse, error)
 	SendReactionWithSession(ctx context.Context, chatID, messageID, reaction, sessionName string) (*SendMessageResponse, error)
 	DeleteMessage(ctx context.Context, chatID, messageID string) error
-	CreateSession(ctx context.Context) error
+	// EditMessageWithSession replaces the text of a message the session sent
+	EditMessageWithSession(ctx context.Context, chatID, messageID, newText, sessionName string) error
+	StarMessageWithSession(ctx context.Context, chatID, messageID string, star bool, sessionName string) error
+	// CreateSession creates the client's session in WAHA with config, which may be nil
+	CreateSession(ctx context.Context, config *SessionConfig) error
 	StartSession(ctx context.Context) error
 	StopSession(ctx context.Context) error
 	RestartSession(ctx context.Context) error
@@ -23,6 +28,8 @@ type WAClient interface {
 	WaitForSessionReady(ctx context.Context, maxWaitTime time.Duration) error
 	WaitForSessionReadyByName(ctx context.Context, sessionName string, maxWaitTime time.Duration) error
 	GetSessionName() string
+	// GetMe returns the account a session is logged in with, or nil when it is not logged in
+	GetMe(ctx context.Context, sessionName string) (*Me, error)
 
 	// Contact methods
 	GetContact(ctx context.Context, contactID string) (*Contact, error)
@@ -30,17 +37,31 @@ type WAClient interface {
 
 	// Group methods
 	GetGroup(ctx context.Context, groupID string) (*Group, error)
+	// GetGroupWithSession is GetGroup through sessionName instead of the client's session
+	GetGroupWithSession(ctx context.Context, groupID, sessionName string) (*Group, error)
 	GetAllGroups(ctx context.Context, limit, offset int) ([]Group, error)
 
+	// GetChatLabels returns the WhatsApp Business labels of a chat
+	GetChatLabels(ctx context.Context, chatID string) ([]Label, error)
+	// GetChatMessages returns up to limit of the most recent messages of a chat, newest first
+	GetChatMessages(ctx context.Context, chatID string, limit int, sessionName string) ([]ChatMessage, error)
+
 	// Message acknowledgment
 	AckMessage(ctx context.Context, chatID, sessionName string) error
 
+	// Typing indicator
+	StartTyping(ctx context.Context, chatID, sessionName string) error
+	StopTyping(ctx context.Context, chatID, sessionName string) error
+
 	// Health check
 	HealthCheck(ctx context.Context) error
+
+	// GetCapabilities returns the optional features supported by the WAHA server
+	GetCapabilities(ctx context.Context) Capabilities
 }
 
 type SessionManager interface {
-	Create(ctx context.Context, name string) (*Session, error)
+	Create(ctx context.Context, name string, config *SessionConfig) (*Session, error)
 	Get(ctx context.Context, name string) (*Session, error)
 	Start(ctx context.Context, name string) error
 	Stop(ctx context.Context, name string) error
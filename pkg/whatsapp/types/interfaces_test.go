@@ -22,6 +22,14 @@ func (m *MockWAClient) SendTextWithSession(ctx context.Context, chatID, message,
 	return args.Get(0).(*SendMessageResponse), args.Error(1)
 }
 
+func (m *MockWAClient) SendMentionsWithSession(ctx context.Context, chatID, message string, mentions []string, replyTo, sessionName string) (*SendMessageResponse, error) {
+	args := m.Called(ctx, chatID, message, mentions, replyTo, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*SendMessageResponse), args.Error(1)
+}
+
 func (m *MockWAClient) SendImageWithSession(ctx context.Context, chatID, imagePath, caption, replyTo, sessionName string) (*SendMessageResponse, error) {
 	args := m.Called(ctx, chatID, imagePath, caption, replyTo, sessionName)
 	if args.Get(0) == nil {
@@ -62,8 +70,8 @@ func (m *MockWAClient) SendReactionWithSession(ctx context.Context, chatID, mess
 	return args.Get(0).(*SendMessageResponse), args.Error(1)
 }
 
-func (m *MockWAClient) CreateSession(ctx context.Context) error {
-	args := m.Called(ctx)
+func (m *MockWAClient) CreateSession(ctx context.Context, config *SessionConfig) error {
+	args := m.Called(ctx, config)
 	return args.Error(0)
 }
 
@@ -117,6 +125,14 @@ func (m *MockWAClient) GetGroup(ctx context.Context, groupID string) (*Group, er
 	return args.Get(0).(*Group), args.Error(1)
 }
 
+func (m *MockWAClient) GetGroupWithSession(ctx context.Context, groupID, sessionName string) (*Group, error) {
+	args := m.Called(ctx, groupID, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Group), args.Error(1)
+}
+
 func (m *MockWAClient) GetAllGroups(ctx context.Context, limit, offset int) ([]Group, error) {
 	args := m.Called(ctx, limit, offset)
 	if args.Get(0) == nil {
@@ -125,6 +141,30 @@ func (m *MockWAClient) GetAllGroups(ctx context.Context, limit, offset int) ([]G
 	return args.Get(0).([]Group), args.Error(1)
 }
 
+func (m *MockWAClient) GetChatLabels(ctx context.Context, chatID string) ([]Label, error) {
+	args := m.Called(ctx, chatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Label), args.Error(1)
+}
+
+func (m *MockWAClient) GetChatMessages(ctx context.Context, chatID string, limit int, sessionName string) ([]ChatMessage, error) {
+	args := m.Called(ctx, chatID, limit, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ChatMessage), args.Error(1)
+}
+
+func (m *MockWAClient) GetMe(ctx context.Context, sessionName string) (*Me, error) {
+	args := m.Called(ctx, sessionName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Me), args.Error(1)
+}
+
 func (m *MockWAClient) RestartSession(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -150,6 +190,16 @@ func (m *MockWAClient) DeleteMessage(ctx context.Context, chatID, messageID stri
 	return args.Error(0)
 }
 
+func (m *MockWAClient) EditMessageWithSession(ctx context.Context, chatID, messageID, newText, sessionName string) error {
+	args := m.Called(ctx, chatID, messageID, newText, sessionName)
+	return args.Error(0)
+}
+
+func (m *MockWAClient) StarMessageWithSession(ctx context.Context, chatID, messageID string, star bool, sessionName string) error {
+	args := m.Called(ctx, chatID, messageID, star, sessionName)
+	return args.Error(0)
+}
+
 func (m *MockWAClient) GetSessionName() string {
 	return "test-session"
 }
@@ -159,18 +209,33 @@ func (m *MockWAClient) HealthCheck(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockWAClient) GetCapabilities(ctx context.Context) Capabilities {
+	args := m.Called(ctx)
+	return args.Get(0).(Capabilities)
+}
+
 func (m *MockWAClient) AckMessage(ctx context.Context, chatID, sessionName string) error {
 	args := m.Called(ctx, chatID, sessionName)
 	return args.Error(0)
 }
 
+func (m *MockWAClient) StartTyping(ctx context.Context, chatID, sessionName string) error {
+	args := m.Called(ctx, chatID, sessionName)
+	return args.Error(0)
+}
+
+func (m *MockWAClient) StopTyping(ctx context.Context, chatID, sessionName string) error {
+	args := m.Called(ctx, chatID, sessionName)
+	return args.Error(0)
+}
+
 // MockSessionManager is a mock implementation of the SessionManager interface
 type MockSessionManager struct {
 	mock.Mock
 }
 
-func (m *MockSessionManager) Create(ctx context.Context, name string) (*Session, error) {
-	args := m.Called(ctx, name)
+func (m *MockSessionManager) Create(ctx context.Context, name string, config *SessionConfig) (*Session, error) {
+	args := m.Called(ctx, name, config)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -325,8 +390,8 @@ func TestMockWAClientSessionOperations(t *testing.T) {
 	ctx := context.Background()
 
 	// Test CreateSession
-	mockClient.On("CreateSession", ctx).Return(nil)
-	err := mockClient.CreateSession(ctx)
+	mockClient.On("CreateSession", ctx, (*SessionConfig)(nil)).Return(nil)
+	err := mockClient.CreateSession(ctx, nil)
 	assert.NoError(t, err)
 
 	// Test StartSession
@@ -367,8 +432,8 @@ func TestMockSessionManagerOperations(t *testing.T) {
 	}
 
 	// Test Create
-	mockManager.On("Create", ctx, "test-session").Return(expectedSession, nil)
-	session, err := mockManager.Create(ctx, "test-session")
+	mockManager.On("Create", ctx, "test-session", (*SessionConfig)(nil)).Return(expectedSession, nil)
+	session, err := mockManager.Create(ctx, "test-session", nil)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSession, session)
 
@@ -412,8 +477,8 @@ func TestWAClientErrorHandling(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, response)
 
-	mockClient.On("CreateSession", ctx).Return(assert.AnError)
-	err = mockClient.CreateSession(ctx)
+	mockClient.On("CreateSession", ctx, (*SessionConfig)(nil)).Return(assert.AnError)
+	err = mockClient.CreateSession(ctx, nil)
 	assert.Error(t, err)
 
 	mockClient.On("HealthCheck", ctx).Return(assert.AnError)
@@ -428,8 +493,8 @@ func TestSessionManagerErrorHandling(t *testing.T) {
 	ctx := context.Background()
 
 	// Test error responses
-	mockManager.On("Create", ctx, "test-session").Return(nil, assert.AnError)
-	session, err := mockManager.Create(ctx, "test-session")
+	mockManager.On("Create", ctx, "test-session", (*SessionConfig)(nil)).Return(nil, assert.AnError)
+	session, err := mockManager.Create(ctx, "test-session", nil)
 	assert.Error(t, err)
 	assert.Nil(t, session)
 
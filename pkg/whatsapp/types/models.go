@@ -2,7 +2,9 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 )
@@ -18,6 +20,9 @@ const (
 	SessionStatusError       SessionStatus = "error"
 )
 
+// ErrSessionNotFound is returned when WAHA has no session with the requested name
+var ErrSessionNotFound = errors.New("not found")
+
 // Session represents a WhatsApp session
 type Session struct {
 	Name      string        `json:"name"`
@@ -27,6 +32,52 @@ type Session struct {
 	Error     string        `json:"error,omitempty"`
 }
 
+// CreateSessionRequest is the body of POST /api/sessions
+type CreateSessionRequest struct {
+	Name   string         `json:"name"`
+	Config *SessionConfig `json:"config,omitempty"`
+}
+
+// SessionConfig is the configuration WAHA applies to a session it creates
+type SessionConfig struct {
+	Proxy    *SessionProxy    `json:"proxy,omitempty"`
+	Webhooks []SessionWebhook `json:"webhooks,omitempty"`
+}
+
+// SessionProxy routes a session's WhatsApp connection through a proxy
+type SessionProxy struct {
+	Server   string `json:"server"` // host:port
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// SessionWebhook is a URL WAHA posts the listed events of a session to
+type SessionWebhook struct {
+	URL    string       `json:"url"`
+	Events []string     `json:"events"`
+	HMAC   *WebhookHMAC `json:"hmac,omitempty"`
+}
+
+// WebhookHMAC is the key WAHA signs webhook bodies with, sent as the X-Webhook-Hmac header
+type WebhookHMAC struct {
+	Key string `json:"key"`
+}
+
+// Me is the WhatsApp account a session is logged in with
+type Me struct {
+	ID       string `json:"id"` // e.g. "15551234567@c.us"
+	PushName string `json:"pushName"`
+}
+
+// PhoneNumber returns the account's number in E.164 form, or "" if the ID is not a phone number
+func (m *Me) PhoneNumber() string {
+	number, _, _ := strings.Cut(m.ID, "@")
+	if number == "" || strings.Trim(number, "0123456789") != "" {
+		return ""
+	}
+	return "+" + number
+}
+
 // WebhookEvent represents a webhook event from WAHA
 type WebhookEvent struct {
 	Event   string          `json:"event"`
@@ -40,6 +91,19 @@ type ReactionRequest struct {
 	Reaction  string `json:"reaction"`
 }
 
+// EditMessageRequest represents the request to replace the text of a sent message
+type EditMessageRequest struct {
+	Text string `json:"text"`
+}
+
+// StarRequest represents the request to star or unstar a message
+type StarRequest struct {
+	Session   string `json:"session"`
+	ChatID    string `json:"chatId"`
+	MessageID string `json:"messageId"`
+	Star      bool   `json:"star"`
+}
+
 // SeenRequest represents the request to mark messages as seen
 type SeenRequest struct {
 	ChatID  string `json:"chatId"`
@@ -58,6 +122,17 @@ type SendMessageRequest struct {
 	Text    string `json:"text"`
 	Session string `json:"session"`
 	ReplyTo string `json:"reply_to,omitempty"`
+	// Mentions lists the WhatsApp IDs (e.g. 123@c.us) that the text mentions as @123
+	Mentions []string `json:"mentions,omitempty"`
+}
+
+// LocationMessageRequest represents the request to send a location pin
+type LocationMessageRequest struct {
+	ChatID    string  `json:"chatId"`
+	Session   string  `json:"session"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Title     string  `json:"title,omitempty"`
 }
 
 // FileData represents file information for media messages
@@ -159,6 +234,8 @@ type ClientConfig struct {
 	SessionName string        `json:"session_name" validate:"required"`
 	Timeout     time.Duration `json:"timeout" validate:"required"`
 	RetryCount  int           `json:"retry_count" validate:"min=1,max=10"`
+	// Transport overrides the HTTP transport used for WAHA API calls; nil uses the default
+	Transport http.RoundTripper `json:"-"`
 }
 
 // ServerVersion represents WAHA server version info from /api/server/version
@@ -234,6 +311,25 @@ func (g *Group) GetDisplayName() string {
 	return g.ID.String()
 }
 
+// Label is a WhatsApp Business chat label from the WAHA API
+type Label struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Color    int    `json:"color"`
+	ColorHex string `json:"colorHex"`
+}
+
+// ChatMessage is a message from a chat's history in the WAHA API
+type ChatMessage struct {
+	ID          string `json:"id"`
+	Timestamp   int64  `json:"timestamp"` // Unix seconds
+	From        string `json:"from"`
+	FromMe      bool   `json:"fromMe"`
+	Participant string `json:"participant,omitempty"` // Sender in group chats
+	Body        string `json:"body"`
+	HasMedia    bool   `json:"hasMedia"`
+}
+
 // IsGroupMessage returns true if the message is from a group chat
 func (m *MessagePayload) IsGroupMessage() bool {
 	return strings.HasSuffix(m.ChatID, "@g.us")
@@ -603,3 +603,10 @@ func TestGroupParticipant_Marshal(t *testing.T) {
 	assert.Equal(t, participant.Role, unmarshaled.Role)
 	assert.Equal(t, participant.IsAdmin, unmarshaled.IsAdmin)
 }
+
+func TestMe_PhoneNumber(t *testing.T) {
+	assert.Equal(t, "+15551234567", (&Me{ID: "15551234567@c.us"}).PhoneNumber())
+	assert.Equal(t, "+15551234567", (&Me{ID: "15551234567"}).PhoneNumber())
+	assert.Equal(t, "", (&Me{ID: "120363028123456789:12@lid"}).PhoneNumber())
+	assert.Equal(t, "", (&Me{}).PhoneNumber())
+}
@@ -256,3 +256,39 @@ func TestWhatsAppClient_OptionalEndpoints(t *testing.T) {
 	assert.True(t, endpointsCalled["/api/startTyping"], "Should attempt startTyping endpoint")
 	assert.True(t, endpointsCalled["/api/stopTyping"], "Should attempt stopTyping endpoint")
 }
+
+func TestWhatsAppClient_GetMe(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		expectedID string
+	}{
+		{name: "logged in", status: http.StatusOK, body: `{"id":"15551234567@c.us","pushName":"Bridge"}`, expectedID: "15551234567@c.us"},
+		{name: "not logged in", status: http.StatusOK, body: `null`},
+		{name: "unknown session", status: http.StatusNotFound, body: `{"error":"not found"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/sessions/personal/me", r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(types.ClientConfig{BaseURL: server.URL, SessionName: "default"})
+			me, err := client.GetMe(context.Background(), "personal")
+			require.NoError(t, err)
+			if tt.expectedID == "" {
+				assert.Nil(t, me)
+				return
+			}
+			require.NotNil(t, me)
+			assert.Equal(t, tt.expectedID, me.ID)
+			assert.Equal(t, "+15551234567", me.PhoneNumber())
+		})
+	}
+}
@@ -0,0 +1,20 @@
+-- Add chat_settings table for per-chat bridge settings (e.g. paused relaying)
+-- Version: 1.0
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS chat_settings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_name TEXT NOT NULL,
+    chat_id TEXT NOT NULL,
+    paused BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(session_name, chat_id)
+);
+
+CREATE TRIGGER IF NOT EXISTS chat_settings_updated_at
+AFTER UPDATE ON chat_settings
+BEGIN
+    UPDATE chat_settings SET updated_at = CURRENT_TIMESTAMP
+    WHERE id = NEW.id;
+END;
@@ -0,0 +1,9 @@
+-- Add signal_cursor table recording the newest processed Signal envelope per account
+-- Version: 1.0
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS signal_cursor (
+    account TEXT PRIMARY KEY,
+    last_timestamp INTEGER NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
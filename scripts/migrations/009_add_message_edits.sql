@@ -0,0 +1,14 @@
+-- Add message_edits table recording each edit of a bridged WhatsApp message
+-- Version: 1.0
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS message_edits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    whatsapp_msg_id_hash TEXT NOT NULL,
+    session_name TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    edited_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_edits_msg_id ON message_edits(whatsapp_msg_id_hash, id);
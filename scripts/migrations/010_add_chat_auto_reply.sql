@@ -0,0 +1,6 @@
+-- Add per-chat auto-reply text and the time it was last sent to chat_settings
+-- Version: 1.0
+-- Created: 2026-10-16
+
+ALTER TABLE chat_settings ADD COLUMN auto_reply TEXT NOT NULL DEFAULT '';
+ALTER TABLE chat_settings ADD COLUMN auto_reply_sent_at INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,10 @@
+-- Add seen_keys table remembering recently relayed events across restarts
+-- Version: 1.0
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS seen_keys (
+    key_hash TEXT PRIMARY KEY,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_seen_keys_expires_at ON seen_keys(expires_at);
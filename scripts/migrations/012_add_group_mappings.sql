@@ -0,0 +1,21 @@
+-- Add group_mappings table routing Signal groups to WhatsApp groups
+-- Version: 1.0
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS group_mappings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_name TEXT NOT NULL,
+    signal_group_id TEXT NOT NULL,
+    signal_group_id_hash TEXT NOT NULL,
+    whatsapp_group_id TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(session_name, signal_group_id_hash)
+);
+
+CREATE TRIGGER IF NOT EXISTS group_mappings_updated_at
+AFTER UPDATE ON group_mappings
+BEGIN
+    UPDATE group_mappings SET updated_at = CURRENT_TIMESTAMP
+    WHERE id = NEW.id;
+END;